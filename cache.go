@@ -0,0 +1,324 @@
+//
+// FilePath    : go-utils\cache.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 内存缓存, 按字节数限制容量的 LRU 缓存和带过期清理的 TTL 缓存, 替代无限增长的 sync.Map
+//
+
+package utils
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lruEntry LRU 缓存条目
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+	size  int64 // 条目大小
+}
+
+// LRU 按字节数限制容量的最近最少使用缓存, 并发安全.
+// 写入后总大小超过 maxBytes 时淘汰最久未访问的条目.
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	maxBytes int64                      // 最大容量
+	bytes    int64                      // 当前总大小
+	sizeOf   func(key K, value V) int64 // 计算条目大小
+	ll       *list.List                 // 访问顺序, 头部为最近访问
+	items    map[K]*list.Element        // 键 -> 链表元素
+	onEvict  func(key K, value V)       // 条目被淘汰时的回调
+}
+
+// NewLRU 创建 LRU 缓存
+//   - maxBytes: 最大容量, 小于 1 时使用 1
+//   - sizeOf: 计算条目大小(字节), 为 nil 时每个条目大小为 1, 此时 maxBytes 即最大条目数
+func NewLRU[K comparable, V any](maxBytes int64, sizeOf func(key K, value V) int64) *LRU[K, V] {
+	if sizeOf == nil {
+		sizeOf = func(K, V) int64 { return 1 }
+	}
+
+	return &LRU[K, V]{
+		maxBytes: max(maxBytes, 1),
+		sizeOf:   sizeOf,
+		ll:       list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+// SetOnEvict 设置条目因容量不足被淘汰时的回调, 回调在释放锁后执行; Delete 和 Clear 不触发回调
+func (c *LRU[K, V]) SetOnEvict(fn func(key K, value V)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onEvict = fn
+}
+
+// Get 获取缓存, 命中时将条目标记为最近访问
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	c.ll.MoveToFront(elem)
+
+	return elem.Value.(*lruEntry[K, V]).value, true
+}
+
+// Set 设置缓存, 已存在时覆盖; 条目大小超过 maxBytes 时不缓存并返回 false
+func (c *LRU[K, V]) Set(key K, value V) bool {
+	size := c.sizeOf(key, value)
+
+	c.mu.Lock()
+
+	if size > c.maxBytes {
+		// 新值放不下时删除旧值, 避免读到过期的数据
+		if elem, ok := c.items[key]; ok {
+			c.remove(elem)
+		}
+
+		c.mu.Unlock()
+
+		return false
+	}
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*lruEntry[K, V])
+		c.bytes += size - entry.size
+		entry.value, entry.size = value, size
+		c.ll.MoveToFront(elem)
+	} else {
+		c.items[key] = c.ll.PushFront(&lruEntry[K, V]{key: key, value: value, size: size})
+		c.bytes += size
+	}
+
+	// 淘汰最久未访问的条目
+	var evicted []*lruEntry[K, V]
+
+	for c.bytes > c.maxBytes {
+		entry := c.remove(c.ll.Back())
+		evicted = append(evicted, entry)
+	}
+
+	onEvict := c.onEvict
+	c.mu.Unlock()
+
+	if onEvict != nil {
+		for _, entry := range evicted {
+			onEvict(entry.key, entry.value)
+		}
+	}
+
+	return true
+}
+
+// remove 删除条目, 调用方需要持有锁
+func (c *LRU[K, V]) remove(elem *list.Element) *lruEntry[K, V] {
+	entry := c.ll.Remove(elem).(*lruEntry[K, V])
+	delete(c.items, entry.key)
+	c.bytes -= entry.size
+
+	return entry
+}
+
+// Delete 删除缓存, 返回是否存在
+func (c *LRU[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if ok {
+		c.remove(elem)
+	}
+
+	return ok
+}
+
+// DeleteFunc 删除 fn 返回 true 的所有条目, 返回删除的数量; fn 在持有锁时调用, 不能调用缓存的方法
+func (c *LRU[K, V]) DeleteFunc(fn func(key K, value V) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+
+	for _, elem := range c.items {
+		if entry := elem.Value.(*lruEntry[K, V]); fn(entry.key, entry.value) {
+			c.remove(elem)
+			n++
+		}
+	}
+
+	return n
+}
+
+// Len 获取条目数
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ll.Len()
+}
+
+// Bytes 获取当前总大小
+func (c *LRU[K, V]) Bytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.bytes
+}
+
+// Clear 清空缓存
+func (c *LRU[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ll.Init()
+	clear(c.items)
+	c.bytes = 0
+}
+
+// ttlEntry TTL 缓存条目
+type ttlEntry[V any] struct {
+	value    V
+	expireAt time.Time // 过期时间, 零值表示永不过期
+}
+
+// expired 判断条目在 now 时是否已过期
+func (e ttlEntry[V]) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
+}
+
+// TTLCache 带过期时间的缓存, 并发安全.
+// 读取时忽略已过期的条目, 后台清理协程定期删除过期条目, 不再使用时需要调用 Close 停止清理协程.
+type TTLCache[K comparable, V any] struct {
+	mu    sync.RWMutex
+	ttl   time.Duration // 默认过期时间
+	items map[K]ttlEntry[V]
+	stop  chan struct{} // 停止清理协程
+	once  sync.Once
+	now   func() time.Time
+}
+
+// NewTTLCache 创建 TTL 缓存
+//   - ttl: 默认过期时间, 小于等于 0 表示永不过期
+//   - cleanupInterval: 清理过期条目的间隔, 小于等于 0 时不启动清理协程, 过期条目只在覆盖或删除时释放
+func NewTTLCache[K comparable, V any](ttl, cleanupInterval time.Duration) *TTLCache[K, V] {
+	c := &TTLCache[K, V]{
+		ttl:   ttl,
+		items: make(map[K]ttlEntry[V]),
+		stop:  make(chan struct{}),
+		now:   time.Now,
+	}
+
+	if cleanupInterval > 0 {
+		go c.janitor(cleanupInterval)
+	}
+
+	return c
+}
+
+// janitor 定期删除过期条目, 直到 Close
+func (c *TTLCache[K, V]) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.DeleteExpired()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// Get 获取缓存, 不存在或已过期时返回 false
+func (c *TTLCache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	entry, ok := c.items[key]
+	c.mu.RUnlock()
+
+	if !ok || entry.expired(c.now()) {
+		var zero V
+		return zero, false
+	}
+
+	return entry.value, true
+}
+
+// Set 使用默认过期时间设置缓存
+func (c *TTLCache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL 使用指定过期时间设置缓存
+//   - ttl: 过期时间, 小于等于 0 表示永不过期
+func (c *TTLCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	entry := ttlEntry[V]{value: value}
+	if ttl > 0 {
+		entry.expireAt = c.now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[key] = entry
+}
+
+// Delete 删除缓存
+func (c *TTLCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, key)
+}
+
+// DeleteExpired 删除所有过期条目, 返回删除的条目数
+func (c *TTLCache[K, V]) DeleteExpired() int {
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+
+	for key, entry := range c.items {
+		if entry.expired(now) {
+			delete(c.items, key)
+			n++
+		}
+	}
+
+	return n
+}
+
+// Len 获取条目数, 包含已过期但尚未清理的条目
+func (c *TTLCache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.items)
+}
+
+// Clear 清空缓存
+func (c *TTLCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.items)
+}
+
+// Close 停止清理协程, 可重复调用; 关闭后缓存仍可读写
+func (c *TTLCache[K, V]) Close() {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+}
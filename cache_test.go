@@ -0,0 +1,166 @@
+//
+// FilePath    : go-utils\cache_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 内存缓存测试
+//
+
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	c := NewLRU[string, string](10, func(k, v string) int64 { return int64(len(k) + len(v)) })
+
+	var evicted []string
+
+	c.SetOnEvict(func(k, _ string) { evicted = append(evicted, k) })
+
+	c.Set("a", "1111") // 5
+	c.Set("b", "2222") // 5
+
+	// 访问 a, b 成为最久未访问的条目
+	if v, ok := c.Get("a"); !ok || v != "1111" {
+		t.Fatalf("Get(a) = %q, %v", v, ok)
+	}
+
+	c.Set("c", "33") // 3, 淘汰 b
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Get(b) should be evicted")
+	}
+
+	if len(evicted) != 1 || evicted[0] != "b" {
+		t.Errorf("evicted = %v, want [b]", evicted)
+	}
+
+	if c.Len() != 2 || c.Bytes() != 8 {
+		t.Errorf("Len() = %d, Bytes() = %d, want 2, 8", c.Len(), c.Bytes())
+	}
+
+	// 覆盖时更新大小
+	c.Set("c", "3")
+
+	if c.Bytes() != 7 {
+		t.Errorf("Bytes() after overwrite = %d, want 7", c.Bytes())
+	}
+
+	// 超过容量的条目不缓存, 并删除旧值
+	if c.Set("a", "0123456789") {
+		t.Errorf("Set() with oversized entry should return false")
+	}
+
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Get(a) should be removed after oversized Set")
+	}
+
+	if !c.Delete("c") || c.Delete("c") {
+		t.Errorf("Delete(c) should return true only once")
+	}
+
+	c.Set("d", "4")
+	c.Clear()
+
+	if c.Len() != 0 || c.Bytes() != 0 {
+		t.Errorf("Len() = %d, Bytes() = %d after Clear", c.Len(), c.Bytes())
+	}
+}
+
+func TestLRUEntryCount(t *testing.T) {
+	c := NewLRU[int, int](2, nil)
+
+	for i := range 5 {
+		c.Set(i, i)
+	}
+
+	if c.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", c.Len())
+	}
+
+	if _, ok := c.Get(4); !ok {
+		t.Errorf("Get(4) should hit")
+	}
+}
+
+func TestLRUDeleteFunc(t *testing.T) {
+	c := NewLRU[string, int](10, nil)
+
+	for i, key := range []string{"user:1", "user:2", "order:1"} {
+		c.Set(key, i)
+	}
+
+	if n := c.DeleteFunc(func(key string, _ int) bool { return strings.HasPrefix(key, "user:") }); n != 2 {
+		t.Fatalf("DeleteFunc() = %d, want 2", n)
+	}
+
+	if _, ok := c.Get("order:1"); !ok || c.Len() != 1 {
+		t.Errorf("Len() = %d, order:1 should remain", c.Len())
+	}
+}
+
+func TestTTLCache(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	c := NewTTLCache[string, int](time.Minute, 0)
+	defer c.Close()
+
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	c.SetWithTTL("b", 2, time.Hour)
+	c.SetWithTTL("c", 3, 0)
+
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %d, %v", v, ok)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Get(a) should be expired")
+	}
+
+	if n := c.DeleteExpired(); n != 1 {
+		t.Errorf("DeleteExpired() = %d, want 1", n)
+	}
+
+	now = now.Add(24 * time.Hour)
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Get(b) should be expired")
+	}
+
+	if v, ok := c.Get("c"); !ok || v != 3 {
+		t.Errorf("Get(c) = %d, %v; never expires", v, ok)
+	}
+
+	c.Delete("c")
+	c.Clear()
+
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d after Clear", c.Len())
+	}
+}
+
+func TestTTLCacheJanitor(t *testing.T) {
+	c := NewTTLCache[string, int](time.Millisecond, 5*time.Millisecond)
+	defer c.Close()
+
+	c.Set("a", 1)
+
+	deadline := time.Now().Add(time.Second)
+	for c.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if c.Len() != 0 {
+		t.Fatalf("janitor did not delete expired entries")
+	}
+
+	c.Close()
+}
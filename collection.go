@@ -0,0 +1,142 @@
+//
+// FilePath    : go-utils\collection.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 泛型切片和 map 工具, 分块、去重、差集、交集、分组、映射、过滤、归约
+//
+
+package utils
+
+// Chunk 将切片按 size 分块, 最后一块可能不足 size; 分块共享原切片的底层数组, size 小于 1 时返回 nil
+//   - s: 切片
+//   - size: 每块的元素数量
+func Chunk[T any](s []T, size int) [][]T {
+	if size < 1 || len(s) == 0 {
+		return nil
+	}
+
+	chunks := make([][]T, 0, (len(s)+size-1)/size)
+
+	for size < len(s) {
+		chunks = append(chunks, s[:size:size])
+		s = s[size:]
+	}
+
+	return append(chunks, s)
+}
+
+// Unique 去除重复元素, 保留每个元素第一次出现的位置, 返回新切片
+func Unique[T comparable](s []T) []T {
+	seen := make(map[T]struct{}, len(s))
+	result := make([]T, 0, len(s))
+
+	for _, item := range s {
+		if _, exists := seen[item]; !exists {
+			seen[item] = struct{}{}
+
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// DifferenceOf 返回 a 中所有不在 b 中的元素, 保持 a 中的顺序; 字符串切片且 b 很大时可以使用并行的 Difference
+func DifferenceOf[T comparable](a, b []T) []T {
+	set := toSet(b)
+
+	return Filter(a, func(item T) bool {
+		_, exists := set[item]
+		return !exists
+	})
+}
+
+// Intersect 返回 a 中同时在 b 中的元素, 保持 a 中的顺序, 不去重
+func Intersect[T comparable](a, b []T) []T {
+	set := toSet(b)
+
+	return Filter(a, func(item T) bool {
+		_, exists := set[item]
+		return exists
+	})
+}
+
+// toSet 将切片转换为集合
+func toSet[T comparable](s []T) map[T]struct{} {
+	set := make(map[T]struct{}, len(s))
+	for _, item := range s {
+		set[item] = struct{}{}
+	}
+
+	return set
+}
+
+// GroupBy 按 key 函数的结果分组, 组内保持原有顺序
+//   - s: 切片
+//   - key: 获取元素的分组 key
+func GroupBy[T any, K comparable](s []T, key func(item T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, item := range s {
+		k := key(item)
+		groups[k] = append(groups[k], item)
+	}
+
+	return groups
+}
+
+// Map 对每个元素执行 fn, 返回结果切片
+func Map[T, R any](s []T, fn func(item T) R) []R {
+	result := make([]R, len(s))
+	for i, item := range s {
+		result[i] = fn(item)
+	}
+
+	return result
+}
+
+// Filter 返回 keep 为 true 的元素, 返回新切片
+func Filter[T any](s []T, keep func(item T) bool) []T {
+	var result []T
+
+	for _, item := range s {
+		if keep(item) {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// Reduce 从 initial 开始依次使用 fn 合并每个元素, 返回最终结果
+//   - s: 切片
+//   - initial: 初始值
+//   - fn: 合并函数, acc 为当前结果
+func Reduce[T, R any](s []T, initial R, fn func(acc R, item T) R) R {
+	acc := initial
+	for _, item := range s {
+		acc = fn(acc, item)
+	}
+
+	return acc
+}
+
+// Keys 返回 map 的所有 key, 顺序不固定
+func Keys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// Values 返回 map 的所有 value, 顺序不固定
+func Values[K comparable, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+
+	return values
+}
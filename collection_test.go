@@ -0,0 +1,155 @@
+//
+// FilePath    : go-utils\collection_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 泛型切片和 map 工具测试
+//
+
+package utils
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		name string
+		s    []int
+		size int
+		want [][]int
+	}{
+		{"整除", []int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{"有余数", []int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{"size 大于长度", []int{1, 2}, 5, [][]int{{1, 2}}},
+		{"空切片", nil, 2, nil},
+		{"size 非法", []int{1}, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Chunk(tt.s, tt.size); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Chunk() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 追加到分块不能覆盖下一块
+	chunks := Chunk([]int{1, 2, 3, 4}, 2)
+	_ = append(chunks[0], 99)
+
+	if chunks[1][0] != 3 {
+		t.Errorf("append to chunk overwrote next chunk: %v", chunks)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := []int{1, 2, 2, 3, 4, 5}
+	b := []int{2, 4, 6}
+
+	if got, want := Unique(a), []int{1, 2, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("Unique() = %v, want %v", got, want)
+	}
+
+	if got, want := DifferenceOf(a, b), []int{1, 3, 5}; !slices.Equal(got, want) {
+		t.Errorf("DifferenceOf() = %v, want %v", got, want)
+	}
+
+	if got, want := Intersect(a, b), []int{2, 2, 4}; !slices.Equal(got, want) {
+		t.Errorf("Intersect() = %v, want %v", got, want)
+	}
+}
+
+func TestGroupByMapFilterReduce(t *testing.T) {
+	words := []string{"go", "rust", "java", "c", "zig"}
+
+	groups := GroupBy(words, func(w string) int { return len(w) })
+	want := map[int][]string{2: {"go"}, 4: {"rust", "java"}, 1: {"c"}, 3: {"zig"}}
+
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("GroupBy() = %v, want %v", groups, want)
+	}
+
+	lengths := Map(words, func(w string) int { return len(w) })
+	if !slices.Equal(lengths, []int{2, 4, 4, 1, 3}) {
+		t.Errorf("Map() = %v", lengths)
+	}
+
+	long := Filter(words, func(w string) bool { return len(w) > 2 })
+	if !slices.Equal(long, []string{"rust", "java", "zig"}) {
+		t.Errorf("Filter() = %v", long)
+	}
+
+	total := Reduce(lengths, 0, func(acc, n int) int { return acc + n })
+	if total != 14 {
+		t.Errorf("Reduce() = %d, want 14", total)
+	}
+
+	keys := Keys(groups)
+	slices.Sort(keys)
+
+	if !slices.Equal(keys, []int{1, 2, 3, 4}) {
+		t.Errorf("Keys() = %v", keys)
+	}
+
+	if got := len(Values(groups)); got != 4 {
+		t.Errorf("len(Values()) = %d, want 4", got)
+	}
+}
+
+// benchInts 生成 n 个有重复的整数
+func benchInts(n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i % (n / 2)
+	}
+
+	return s
+}
+
+func BenchmarkUnique(b *testing.B) {
+	s := benchInts(10000)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_ = Unique(s)
+	}
+}
+
+func BenchmarkDifferenceOf(b *testing.B) {
+	a := benchInts(10000)
+	other := benchInts(5000)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_ = DifferenceOf(a, other)
+	}
+}
+
+func BenchmarkGroupBy(b *testing.B) {
+	s := make([]string, 10000)
+	for i := range s {
+		s[i] = strconv.Itoa(i)
+	}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_ = GroupBy(s, func(v string) int { return len(v) })
+	}
+}
+
+func BenchmarkChunk(b *testing.B) {
+	s := benchInts(10000)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_ = Chunk(s, 100)
+	}
+}
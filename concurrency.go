@@ -0,0 +1,291 @@
+//
+// FilePath    : go-utils\concurrency.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 并发工具, 有界协程池和并行 Map/ForEach, 支持上下文取消、panic 恢复、首个错误或聚合错误
+//
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrPoolClosed 协程池已关闭, 不能再提交任务
+var ErrPoolClosed = errors.New("pool is closed")
+
+// PanicError 任务 panic 时返回的错误, 包含 panic 的值和堆栈
+type PanicError struct {
+	Value any    // panic 的值
+	Stack []byte // panic 时的堆栈
+}
+
+// Error 实现 error 接口
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap panic 的值为 error 时返回该 error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+
+	return err
+}
+
+// safeCall 执行 fn, panic 时转换为 *PanicError
+func safeCall(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return fn()
+}
+
+// parallelConfig 并发任务配置
+type parallelConfig struct {
+	aggregate bool // 是否聚合所有错误, 默认遇到首个错误后取消其他任务
+	queueSize int  // 协程池任务队列长度
+}
+
+// ParallelOption 定义并发任务的可选配置函数类型
+type ParallelOption func(*parallelConfig)
+
+// WithAggregateErrors 设置执行全部任务并使用 errors.Join 聚合所有错误, 默认遇到首个错误后取消上下文并返回该错误
+func WithAggregateErrors() ParallelOption {
+	return func(c *parallelConfig) {
+		c.aggregate = true
+	}
+}
+
+// WithQueueSize 设置协程池任务队列长度, 队列满时 Submit 阻塞, 默认与工作协程数相同
+func WithQueueSize(size int) ParallelOption {
+	return func(c *parallelConfig) {
+		c.queueSize = size
+	}
+}
+
+// errCollector 收集任务错误, 首个错误模式下出错时取消上下文
+type errCollector struct {
+	mu        sync.Mutex
+	aggregate bool
+	cancel    context.CancelFunc
+	errs      []error
+}
+
+// add 记录错误
+func (e *errCollector) add(err error) {
+	if err == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.aggregate && len(e.errs) > 0 {
+		return
+	}
+
+	e.errs = append(e.errs, err)
+
+	if !e.aggregate {
+		e.cancel()
+	}
+}
+
+// err 返回首个错误或聚合错误
+func (e *errCollector) err() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.aggregate && len(e.errs) > 0 {
+		return e.errs[0]
+	}
+
+	return errors.Join(e.errs...)
+}
+
+// Pool 有界协程池, 固定数量的工作协程执行提交的任务, 任务 panic 时转换为 *PanicError.
+// 默认遇到首个错误后取消传给任务的上下文, 未开始的任务不再执行; Wait 等待全部任务结束并返回错误.
+type Pool struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	tasks  chan func(ctx context.Context) error
+	wg     sync.WaitGroup
+	errs   *errCollector
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewPool 创建协程池
+//   - ctx: 上下文, 取消后未开始的任务不再执行
+//   - workers: 工作协程数, 小于 1 时使用 1
+//   - opts: 可选配置
+func NewPool(ctx context.Context, workers int, opts ...ParallelOption) *Pool {
+	workers = max(workers, 1)
+
+	cfg := &parallelConfig{queueSize: workers}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	p := &Pool{
+		ctx:    ctx,
+		cancel: cancel,
+		tasks:  make(chan func(ctx context.Context) error, max(cfg.queueSize, 0)),
+		errs:   &errCollector{aggregate: cfg.aggregate, cancel: cancel},
+	}
+
+	p.wg.Add(workers)
+
+	for range workers {
+		go p.work()
+	}
+
+	return p
+}
+
+// work 工作协程, 依次执行任务直到队列关闭
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for task := range p.tasks {
+		// 上下文取消后只消费队列, 不再执行任务
+		if p.ctx.Err() != nil {
+			continue
+		}
+
+		p.errs.add(safeCall(func() error { return task(p.ctx) }))
+	}
+}
+
+// Submit 提交任务, 队列满时阻塞; 协程池已关闭时返回 ErrPoolClosed, 上下文已取消时返回上下文的错误
+//   - task: 任务, ctx 为协程池的上下文
+func (p *Pool) Submit(task func(ctx context.Context) error) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	}
+}
+
+// Wait 关闭协程池并等待全部任务结束, 返回首个错误或聚合错误; 没有任务出错但上下文被取消时返回上下文的错误
+func (p *Pool) Wait() error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+
+	err := p.errs.err()
+	if err == nil {
+		err = p.ctx.Err()
+	}
+
+	p.cancel()
+
+	return err
+}
+
+// ParallelMap 并发对 items 的每个元素执行 fn, 结果与 items 顺序一致.
+// 默认遇到首个错误后取消上下文并返回该错误, 使用 WithAggregateErrors 时执行全部元素并聚合错误; 出错元素的结果为零值.
+//   - ctx: 上下文, 取消后未开始的元素不再执行
+//   - items: 输入元素
+//   - fn: 处理函数, panic 时转换为 *PanicError
+//   - concurrency: 最大并发数, 小于 1 时使用 1
+//   - opts: 可选配置
+func ParallelMap[T, R any](ctx context.Context, items []T, fn func(ctx context.Context, item T) (R, error), concurrency int, opts ...ParallelOption) ([]R, error) {
+	results := make([]R, len(items))
+
+	err := parallelDo(ctx, len(items), concurrency, opts, func(ctx context.Context, i int) error {
+		r, err := fn(ctx, items[i])
+		if err != nil {
+			return err
+		}
+
+		results[i] = r
+
+		return nil
+	})
+
+	return results, err
+}
+
+// ParallelForEach 并发对 items 的每个元素执行 fn, 错误处理与 ParallelMap 相同
+//   - ctx: 上下文, 取消后未开始的元素不再执行
+//   - items: 输入元素
+//   - fn: 处理函数, panic 时转换为 *PanicError
+//   - concurrency: 最大并发数, 小于 1 时使用 1
+//   - opts: 可选配置
+func ParallelForEach[T any](ctx context.Context, items []T, fn func(ctx context.Context, item T) error, concurrency int, opts ...ParallelOption) error {
+	return parallelDo(ctx, len(items), concurrency, opts, func(ctx context.Context, i int) error {
+		return fn(ctx, items[i])
+	})
+}
+
+// parallelDo 使用最多 concurrency 个协程对下标 [0, n) 执行 fn, 错误包装元素下标
+func parallelDo(parent context.Context, n, concurrency int, opts []ParallelOption, fn func(ctx context.Context, i int) error) error {
+	cfg := &parallelConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	errs := &errCollector{aggregate: cfg.aggregate, cancel: cancel}
+
+	var (
+		next atomic.Int64
+		wg   sync.WaitGroup
+	)
+
+	workers := min(max(concurrency, 1), n)
+	wg.Add(workers)
+
+	for range workers {
+		go func() {
+			defer wg.Done()
+
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= n || ctx.Err() != nil {
+					return
+				}
+
+				if err := safeCall(func() error { return fn(ctx, i) }); err != nil {
+					errs.add(fmt.Errorf("item %d: %w", i, err))
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := errs.err(); err != nil {
+		return err
+	}
+
+	return parent.Err()
+}
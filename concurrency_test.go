@@ -0,0 +1,145 @@
+//
+// FilePath    : go-utils\concurrency_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 并发工具测试
+//
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelMap(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8}
+
+	var running, peak atomic.Int32
+
+	got, err := ParallelMap(context.Background(), items, func(_ context.Context, v int) (int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+
+		return v * v, nil
+	}, 3)
+	if err != nil {
+		t.Fatalf("ParallelMap() error = %v", err)
+	}
+
+	if want := []int{1, 4, 9, 16, 25, 36, 49, 64}; !slices.Equal(got, want) {
+		t.Errorf("ParallelMap() = %v, want %v", got, want)
+	}
+
+	if peak.Load() > 3 {
+		t.Errorf("peak concurrency = %d, want <= 3", peak.Load())
+	}
+}
+
+func TestParallelForEach_Errors(t *testing.T) {
+	errBoom := errors.New("boom")
+	items := make([]int, 100)
+
+	// 首个错误后取消, 未开始的元素不再执行
+	var calls atomic.Int32
+
+	err := ParallelForEach(context.Background(), items, func(ctx context.Context, _ int) error {
+		if calls.Add(1) == 1 {
+			return errBoom
+		}
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	}, 2)
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("ParallelForEach() error = %v, want boom", err)
+	}
+
+	if calls.Load() >= int32(len(items)) {
+		t.Errorf("calls = %d, want fewer than %d", calls.Load(), len(items))
+	}
+
+	// 聚合模式执行全部元素, panic 转换为 PanicError
+	calls.Store(0)
+
+	err = ParallelForEach(context.Background(), []int{0, 1, 2, 3}, func(_ context.Context, v int) error {
+		calls.Add(1)
+
+		switch v {
+		case 1:
+			return errBoom
+		case 2:
+			panic("oops")
+		}
+
+		return nil
+	}, 2, WithAggregateErrors())
+
+	var pe *PanicError
+	if !errors.Is(err, errBoom) || !errors.As(err, &pe) || pe.Value != "oops" {
+		t.Fatalf("ParallelForEach() aggregate error = %v", err)
+	}
+
+	if calls.Load() != 4 {
+		t.Errorf("calls = %d, want 4", calls.Load())
+	}
+}
+
+func TestPool(t *testing.T) {
+	p := NewPool(context.Background(), 2, WithAggregateErrors())
+
+	var sum atomic.Int64
+
+	for i := 1; i <= 10; i++ {
+		if err := p.Submit(func(context.Context) error {
+			sum.Add(int64(i))
+
+			if i == 5 {
+				panic(errors.New("five"))
+			}
+
+			return nil
+		}); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+
+	var pe *PanicError
+	if err := p.Wait(); !errors.As(err, &pe) {
+		t.Fatalf("Wait() error = %v, want PanicError", err)
+	}
+
+	if sum.Load() != 55 {
+		t.Errorf("sum = %d, want 55", sum.Load())
+	}
+
+	if err := p.Submit(func(context.Context) error { return nil }); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit() after Wait error = %v, want ErrPoolClosed", err)
+	}
+}
+
+func TestPool_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := NewPool(ctx, 1)
+
+	cancel()
+
+	if err := p.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() error = %v, want context.Canceled", err)
+	}
+}
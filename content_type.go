@@ -0,0 +1,298 @@
+//
+// FilePath    : go-utils\content_type.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 内容类型嗅探及 MIME 与扩展名的双向映射, 补充 http.DetectContentType 无法识别的 heic、avif、office 文档等格式
+//
+
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+// contentSniffLen 内容类型嗅探读取的字节数, 大于 http.DetectContentType 的 512 字节, 用于识别 zip 中的 office 文档
+const contentSniffLen = 4096
+
+// 常用 MIME 类型
+const (
+	MIMEOctetStream = "application/octet-stream"
+	MIMEDocx        = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MIMEXlsx        = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	MIMEPptx        = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+)
+
+// mimeExtensions MIME 类型与扩展名的对应关系, 第一个扩展名为首选扩展名.
+// 不依赖系统的 mime.types, 保证不同环境下结果一致.
+var mimeExtensions = []struct {
+	mimeType   string
+	extensions []string
+}{
+	{"image/jpeg", []string{".jpg", ".jpeg"}},
+	{"image/png", []string{".png"}},
+	{"image/gif", []string{".gif"}},
+	{"image/webp", []string{".webp"}},
+	{"image/bmp", []string{".bmp"}},
+	{"image/svg+xml", []string{".svg"}},
+	{"image/x-icon", []string{".ico"}},
+	{"image/heic", []string{".heic"}},
+	{"image/heif", []string{".heif"}},
+	{"image/avif", []string{".avif"}},
+	{"image/tiff", []string{".tiff", ".tif"}},
+	{"audio/mpeg", []string{".mp3"}},
+	{"audio/wave", []string{".wav"}},
+	{"audio/ogg", []string{".ogg"}},
+	{"video/mp4", []string{".mp4"}},
+	{"video/webm", []string{".webm"}},
+	{"video/quicktime", []string{".mov"}},
+	{"application/pdf", []string{".pdf"}},
+	{"application/zip", []string{".zip"}},
+	{"application/x-gzip", []string{".gz"}},
+	{"application/x-7z-compressed", []string{".7z"}},
+	{"application/x-rar-compressed", []string{".rar"}},
+	{"application/json", []string{".json"}},
+	{"application/xml", []string{".xml"}},
+	{"application/msword", []string{".doc"}},
+	{"application/vnd.ms-excel", []string{".xls"}},
+	{"application/vnd.ms-powerpoint", []string{".ppt"}},
+	{MIMEDocx, []string{".docx"}},
+	{MIMEXlsx, []string{".xlsx"}},
+	{MIMEPptx, []string{".pptx"}},
+	{"application/vnd.oasis.opendocument.text", []string{".odt"}},
+	{"application/vnd.oasis.opendocument.spreadsheet", []string{".ods"}},
+	{"application/vnd.oasis.opendocument.presentation", []string{".odp"}},
+	{"application/epub+zip", []string{".epub"}},
+	{"application/wasm", []string{".wasm"}},
+	{"text/plain", []string{".txt"}},
+	{"text/html", []string{".html", ".htm"}},
+	{"text/css", []string{".css"}},
+	{"text/csv", []string{".csv"}},
+	{"text/markdown", []string{".md"}},
+	{"text/javascript", []string{".js", ".mjs"}},
+	{"font/woff", []string{".woff"}},
+	{"font/woff2", []string{".woff2"}},
+	{"font/ttf", []string{".ttf"}},
+	{"font/otf", []string{".otf"}},
+}
+
+// MIME 与扩展名的索引
+var (
+	extensionByMIME = make(map[string]string)
+	mimeByExtension = make(map[string]string)
+)
+
+func init() {
+	for _, m := range mimeExtensions {
+		extensionByMIME[m.mimeType] = m.extensions[0]
+
+		for _, ext := range m.extensions {
+			mimeByExtension[ext] = m.mimeType
+		}
+	}
+}
+
+// ExtensionByMIME 获取 MIME 类型的首选扩展名(含 "."), 忽略参数, 未知类型返回空字符串
+//   - mimeType: MIME 类型, 例如 "image/jpeg" 或 "text/plain; charset=utf-8"
+func ExtensionByMIME(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return ""
+	}
+
+	if ext, ok := extensionByMIME[mediaType]; ok {
+		return ext
+	}
+
+	// 兜底使用系统的 mime.types
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+
+	return ""
+}
+
+// MIMEByExtension 根据扩展名获取 MIME 类型, 不区分大小写, 未知扩展名返回 application/octet-stream
+//   - ext: 扩展名, 可以不带 ".", 例如 ".png" 或 "png"
+func MIMEByExtension(ext string) string {
+	ext = strings.ToLower(ext)
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+
+	if mimeType, ok := mimeByExtension[ext]; ok {
+		return mimeType
+	}
+
+	if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+		if mediaType, _, err := mime.ParseMediaType(mimeType); err == nil {
+			return mediaType
+		}
+	}
+
+	return MIMEOctetStream
+}
+
+// ContentTypeByFileName 根据文件名获取下载响应的 Content-Type, 文本类型附加 charset=utf-8
+//   - fileName: 文件名
+func ContentTypeByFileName(fileName string) string {
+	mimeType := MIMEByExtension(filepath.Ext(fileName))
+	if strings.HasPrefix(mimeType, "text/") || mimeType == "application/json" || mimeType == "image/svg+xml" {
+		return mimeType + "; charset=utf-8"
+	}
+
+	return mimeType
+}
+
+// DetectContentType 从 r 读取文件头嗅探 MIME 类型, 返回不含参数的类型, 例如 "image/heic".
+// 会消耗 r 的前 4096 个字节, 需要继续读取内容时使用 DetectContentTypeReader.
+func DetectContentType(r io.Reader) (string, error) {
+	head := make([]byte, contentSniffLen)
+
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+
+	return DetectMIME(head[:n]), nil
+}
+
+// DetectContentTypeReader 嗅探 r 的 MIME 类型, 并返回包含完整内容的 io.Reader
+func DetectContentTypeReader(r io.Reader) (string, io.Reader, error) {
+	head := make([]byte, contentSniffLen)
+
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", nil, err
+	}
+
+	head = head[:n]
+
+	return DetectMIME(head), io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+// detectExtended 识别 http.DetectContentType 不支持的格式, 无法识别时返回空字符串
+func detectExtended(head []byte) string {
+	switch {
+	case len(head) >= 12 && string(head[4:8]) == "ftyp":
+		return detectISOBMFF(head)
+	case bytes.HasPrefix(head, []byte("7z\xBC\xAF\x27\x1C")):
+		return "application/x-7z-compressed"
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		return detectZip(head)
+	}
+
+	return ""
+}
+
+// detectISOBMFF 根据 ftyp 的品牌识别 heic、heif、avif, 视频格式交给 http.DetectContentType
+func detectISOBMFF(head []byte) string {
+	boxSize := int(binary.BigEndian.Uint32(head[:4]))
+	if boxSize < 16 || boxSize > len(head) {
+		boxSize = min(len(head), 64)
+	}
+
+	// 主品牌和兼容品牌, 跳过 minor_version
+	brands := [][]byte{head[8:12]}
+	for i := 16; i+4 <= boxSize; i += 4 {
+		brands = append(brands, head[i:i+4])
+	}
+
+	for _, brand := range brands {
+		switch string(brand) {
+		case "avif", "avis":
+			return "image/avif"
+		case "heic", "heix", "heim", "heis", "hevc", "hevx":
+			return "image/heic"
+		}
+	}
+
+	for _, brand := range brands {
+		if string(brand) == "mif1" || string(brand) == "msf1" {
+			return "image/heif"
+		}
+	}
+
+	return ""
+}
+
+// zip 本地文件头相关常量
+const (
+	zipLocalHeaderLen      = 30
+	zipFlagDataDescriptor  = 0x08
+	zipMethodStore         = 0
+	zipMaxScannedFileNames = 16
+)
+
+// detectZip 遍历文件头中的 zip 本地文件记录, 识别 office 文档、OpenDocument 和 epub, 其他返回 application/zip
+func detectZip(head []byte) string {
+	for offset, i := 0, 0; i < zipMaxScannedFileNames && offset+zipLocalHeaderLen <= len(head); i++ {
+		h := head[offset:]
+		if !bytes.HasPrefix(h, []byte("PK\x03\x04")) {
+			break
+		}
+
+		flags := binary.LittleEndian.Uint16(h[6:8])
+		method := binary.LittleEndian.Uint16(h[8:10])
+		compressedSize := int(binary.LittleEndian.Uint32(h[18:22]))
+		nameLen := int(binary.LittleEndian.Uint16(h[26:28]))
+		extraLen := int(binary.LittleEndian.Uint16(h[28:30]))
+
+		if zipLocalHeaderLen+nameLen > len(h) {
+			break
+		}
+
+		name := string(h[zipLocalHeaderLen : zipLocalHeaderLen+nameLen])
+		data := h[min(zipLocalHeaderLen+nameLen+extraLen, len(h)):]
+
+		switch {
+		case strings.HasPrefix(name, "word/"):
+			return MIMEDocx
+		case strings.HasPrefix(name, "xl/"):
+			return MIMEXlsx
+		case strings.HasPrefix(name, "ppt/"):
+			return MIMEPptx
+		case name == "mimetype" && method == zipMethodStore:
+			// OpenDocument 和 epub 的第一个文件为未压缩的 mimetype, 使用数据描述符时内容截止到下一个签名
+			end := min(compressedSize, len(data))
+			if flags&zipFlagDataDescriptor != 0 {
+				if end = bytes.Index(data, []byte("PK")); end < 0 {
+					end = 0
+				}
+			}
+
+			if mimeType := string(data[:end]); strings.HasPrefix(mimeType, "application/") {
+				return mimeType
+			}
+		}
+
+		// 使用数据描述符时本地文件头中没有压缩后的大小, 无法定位下一个文件
+		if flags&zipFlagDataDescriptor != 0 {
+			break
+		}
+
+		offset += zipLocalHeaderLen + nameLen + extraLen + compressedSize
+	}
+
+	// 文件名不在嗅探范围内时按特征字符串兜底, office 文档的目录名通常出现在前几个文件中
+	if !bytes.Contains(head, []byte("[Content_Types].xml")) {
+		return "application/zip"
+	}
+
+	switch {
+	case bytes.Contains(head, []byte("word/")):
+		return MIMEDocx
+	case bytes.Contains(head, []byte("xl/")):
+		return MIMEXlsx
+	case bytes.Contains(head, []byte("ppt/")):
+		return MIMEPptx
+	}
+
+	return "application/zip"
+}
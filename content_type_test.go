@@ -0,0 +1,143 @@
+//
+// FilePath    : go-utils\content_type_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 单元测试 - 内容类型嗅探及 MIME 与扩展名映射
+//
+
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+)
+
+// buildZip 按顺序生成包含指定文件的 zip, mimetype 文件不压缩
+func buildZip(t *testing.T, names ...string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	for _, name := range names {
+		var (
+			w   io.Writer
+			err error
+		)
+
+		content := "<xml/>"
+
+		if name == "mimetype" {
+			content = "application/vnd.oasis.opendocument.text"
+			w, err = zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		} else {
+			w, err = zw.Create(name)
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestDetectContentType(t *testing.T) {
+	ftyp := func(major string, compatible ...string) []byte {
+		box := []byte{0, 0, 0, byte(16 + 4*len(compatible))}
+		box = append(box, "ftyp"+major+"\x00\x00\x00\x00"...)
+
+		for _, c := range compatible {
+			box = append(box, c...)
+		}
+
+		return append(box, bytes.Repeat([]byte{0}, 16)...)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"heic", ftyp("heic", "mif1", "heic"), "image/heic"},
+		{"heif", ftyp("mif1", "mif1"), "image/heif"},
+		{"avif", ftyp("avif", "mif1", "avif"), "image/avif"},
+		{"mp4", ftyp("isom", "isom", "mp41"), "video/mp4"},
+		{"webp", []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), "image/webp"},
+		{"7z", []byte("7z\xBC\xAF\x27\x1C\x00\x04"), "application/x-7z-compressed"},
+		{"docx", buildZip(t, "[Content_Types].xml", "_rels/.rels", "word/document.xml"), MIMEDocx},
+		{"xlsx", buildZip(t, "[Content_Types].xml", "xl/workbook.xml"), MIMEXlsx},
+		{"pptx", buildZip(t, "[Content_Types].xml", "ppt/presentation.xml"), MIMEPptx},
+		{"odt", buildZip(t, "mimetype", "content.xml"), "application/vnd.oasis.opendocument.text"},
+		{"zip", buildZip(t, "a.txt", "xl/b.txt"), "application/zip"},
+		{"text", []byte("hello world"), "text/plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectContentType(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("DetectContentType() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("DetectContentType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectContentTypeReader(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), contentSniffLen*2)
+
+	mimeType, r, err := DetectContentTypeReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := io.ReadAll(r)
+	if mimeType != "text/plain" || !bytes.Equal(got, data) {
+		t.Errorf("DetectContentTypeReader() = %q, len %d", mimeType, len(got))
+	}
+}
+
+func TestMIMEExtension(t *testing.T) {
+	if got := ExtensionByMIME("image/jpeg"); got != ".jpg" {
+		t.Errorf("ExtensionByMIME(image/jpeg) = %q", got)
+	}
+
+	if got := ExtensionByMIME("text/plain; charset=utf-8"); got != ".txt" {
+		t.Errorf("ExtensionByMIME(text/plain) = %q", got)
+	}
+
+	if got := ExtensionByMIME("application/x-unknown-type"); got != "" {
+		t.Errorf("ExtensionByMIME(unknown) = %q", got)
+	}
+
+	for ext, want := range map[string]string{
+		".JPEG": "image/jpeg",
+		"docx":  MIMEDocx,
+		".heic": "image/heic",
+		".zzz":  MIMEOctetStream,
+	} {
+		if got := MIMEByExtension(ext); got != want {
+			t.Errorf("MIMEByExtension(%q) = %q, want %q", ext, got, want)
+		}
+	}
+
+	if got := ContentTypeByFileName("report.csv"); got != "text/csv; charset=utf-8" {
+		t.Errorf("ContentTypeByFileName(report.csv) = %q", got)
+	}
+}
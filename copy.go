@@ -12,12 +12,41 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"time"
 )
 
-// DeepCopy 将 data 递归地深拷贝任意类型的值,深拷贝到一个新的变量中
+// Copier 自定义深拷贝, DeepCopy 遇到实现了该接口的值时调用 DeepCopy 方法, 不再反射遍历;
+// 返回值的类型必须与接收者相同, 适用于有未导出字段或反射拷贝较慢的类型.
+// DeepCopy 方法中不能对接收者本身调用 utils.DeepCopy, 否则会无限递归.
+type Copier interface {
+	DeepCopy() any
+}
+
+var (
+	copierType   = reflect.TypeFor[Copier]()
+	timeType     = reflect.TypeFor[time.Time]()
+	nullTimeType = reflect.TypeFor[sql.NullTime]()
+)
+
+// DeepCopy 将 data 递归地深拷贝任意类型的值,深拷贝到一个新的变量中.
+// 基本类型的切片和 map、time.Time 以及实现了 Copier 的类型直接拷贝, 其他类型使用反射递归拷贝.
 func DeepCopy[T any](data T) (T, error) {
+	// 常见类型直接拷贝, 不使用反射
+	if v, ok := fastCopy(any(data)); ok {
+		if result, ok := v.(T); ok {
+			return result, nil
+		}
+	}
+
 	src := reflect.ValueOf(data)
+	if !src.IsValid() {
+		// data 为 nil 接口, 没有需要拷贝的内容
+		return data, nil
+	}
+
 	copyValue := reflect.New(src.Type()).Elem()
 
 	err := copyRecursive(src, copyValue)
@@ -35,6 +64,66 @@ func DeepCopy[T any](data T) (T, error) {
 	return result, nil
 }
 
+// fastCopy 拷贝常见类型, 不支持的类型返回 false
+func fastCopy(data any) (any, bool) {
+	switch v := data.(type) {
+	case Copier:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return v, true
+		}
+
+		return v.DeepCopy(), true
+	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, time.Time:
+		return v, true
+	case []byte:
+		return slices.Clone(v), true
+	case []string:
+		return slices.Clone(v), true
+	case []int:
+		return slices.Clone(v), true
+	case []int64:
+		return slices.Clone(v), true
+	case []uint64:
+		return slices.Clone(v), true
+	case []float64:
+		return slices.Clone(v), true
+	case map[string]string:
+		return maps.Clone(v), true
+	case map[string]int:
+		return maps.Clone(v), true
+	case map[string]int64:
+		return maps.Clone(v), true
+	case *time.Time:
+		if v == nil {
+			return v, true
+		}
+
+		t := *v
+
+		return &t, true
+	}
+
+	return nil, false
+}
+
+// isPlainType 判断类型是否不包含指针、切片、map 等引用, 可以直接赋值拷贝
+func isPlainType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Struct:
+		// time.Time 的 loc 指针指向只读的时区信息, 共享是安全的
+		return t == timeType || t == nullTimeType
+	case reflect.Array:
+		return isPlainType(t.Elem())
+	default:
+		return false
+	}
+}
+
 // copyRecursive 使用反射递归地拷贝值
 func copyRecursive(src, cpy reflect.Value) error {
 	// 检查 src 是否有效
@@ -42,6 +131,17 @@ func copyRecursive(src, cpy reflect.Value) error {
 		return errors.New("copyRecursive invalid value")
 	}
 
+	// 不含引用的类型直接赋值
+	if isPlainType(src.Type()) {
+		cpy.Set(src)
+		return nil
+	}
+
+	// 实现了 Copier 的类型使用自定义拷贝
+	if src.Type().Implements(copierType) && src.CanInterface() && (src.Kind() != reflect.Pointer || !src.IsNil()) {
+		return copyCopier(src, cpy)
+	}
+
 	// 根据 src 的类型进行不同的处理
 	switch src.Kind() {
 	case reflect.Pointer:
@@ -59,12 +159,27 @@ func copyRecursive(src, cpy reflect.Value) error {
 	case reflect.Array:
 		// 处理数组类型
 		return copyArray(src, cpy)
+	case reflect.Interface:
+		// 处理接口类型, 例如 map[string]any 的值
+		return copyInterface(src, cpy)
 	default:
 		// 处理基本类型和其他类型，直接复制值
 		return copyDefault(src, cpy)
 	}
 }
 
+// copyCopier 调用 Copier 的 DeepCopy 方法拷贝
+func copyCopier(src, cpy reflect.Value) error {
+	result := reflect.ValueOf(src.Interface().(Copier).DeepCopy())
+	if !result.IsValid() || !result.Type().AssignableTo(cpy.Type()) {
+		return fmt.Errorf("%s.DeepCopy returned %v, want %s", src.Type(), result.Type(), cpy.Type())
+	}
+
+	cpy.Set(result)
+
+	return nil
+}
+
 // copyPointer 处理指针类型的深拷贝
 func copyPointer(src, cpy reflect.Value) error {
 	if src.IsNil() {
@@ -84,15 +199,10 @@ func copyPointer(src, cpy reflect.Value) error {
 
 // copyStruct 处理结构体类型的深拷贝
 func copyStruct(src, cpy reflect.Value) error {
-	if src.Type() == reflect.TypeFor[sql.NullTime]() {
-		// 特殊处理 sql.NullTime 类型
-		cpy.Set(src)
-		return nil
-	}
-
 	// 遍历结构体的每个字段并递归复制
 	for i := 0; i < src.NumField(); i++ {
-		if src.Field(i).CanSet() {
+		// 按目标字段判断是否可设置, 值类型的 src 不可寻址, 其字段的 CanSet 始终为 false
+		if cpy.Field(i).CanSet() {
 			err := copyRecursive(src.Field(i), cpy.Field(i))
 			if err != nil {
 				return err
@@ -114,6 +224,12 @@ func copySlice(src, cpy reflect.Value) error {
 	// 创建一个新的切片并递归复制其元素
 	cpy.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Cap()))
 
+	// 元素不含引用时整体复制
+	if isPlainType(src.Type().Elem()) {
+		reflect.Copy(cpy, src)
+		return nil
+	}
+
 	for i := 0; i < src.Len(); i++ {
 		err := copyRecursive(src.Index(i), cpy.Index(i))
 		if err != nil {
@@ -135,6 +251,16 @@ func copyMap(src, cpy reflect.Value) error {
 	// 创建一个新的 map 并递归复制其键值对
 	cpy.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
 
+	// 值不含引用时直接写入, 不需要递归
+	if isPlainType(src.Type().Elem()) {
+		iter := src.MapRange()
+		for iter.Next() {
+			cpy.SetMapIndex(iter.Key(), iter.Value())
+		}
+
+		return nil
+	}
+
 	for _, key := range src.MapKeys() {
 		newValue := reflect.New(src.MapIndex(key).Type()).Elem()
 
@@ -162,6 +288,30 @@ func copyArray(src, cpy reflect.Value) error {
 	return nil
 }
 
+// copyInterface 处理接口类型的深拷贝, 动态值为切片或 map 时(例如 JSON 解码得到的 map[string]any)递归拷贝,
+// 其他动态值直接赋值, 避免丢失结构体中的未导出字段
+func copyInterface(src, cpy reflect.Value) error {
+	if src.IsNil() {
+		cpy.Set(reflect.Zero(cpy.Type()))
+		return nil
+	}
+
+	elem := src.Elem()
+	if kind := elem.Kind(); kind != reflect.Slice && kind != reflect.Map {
+		cpy.Set(src)
+		return nil
+	}
+	newValue := reflect.New(elem.Type()).Elem()
+
+	if err := copyRecursive(elem, newValue); err != nil {
+		return err
+	}
+
+	cpy.Set(newValue)
+
+	return nil
+}
+
 // copyDefault 处理基本类型和其他类型，直接复制值
 func copyDefault(src, cpy reflect.Value) error {
 	cpy.Set(src)
@@ -12,6 +12,7 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"slices"
 	"testing"
 	"time"
 )
@@ -83,3 +84,145 @@ func TestDeepCopy(t *testing.T) {
 		t.Errorf("期望原始街道为 '怡心湖'，实际值 %v", original.Address.Details.Street)
 	}
 }
+
+// copierValue 实现 Copier 的测试类型, 含未导出字段
+type copierValue struct {
+	id    int
+	items []string
+}
+
+func (v *copierValue) DeepCopy() any {
+	return &copierValue{id: v.id, items: slices.Clone(v.items)}
+}
+
+func TestDeepCopy_FastPaths(t *testing.T) {
+	now := time.Now()
+
+	// time.Time 含未导出字段, 需要整体拷贝
+	gotTime, err := DeepCopy(struct{ At time.Time }{At: now})
+	if err != nil || !gotTime.At.Equal(now) {
+		t.Errorf("DeepCopy(time) = %v, %v, want %v", gotTime.At, err, now)
+	}
+
+	ints := []int{1, 2, 3}
+	gotInts, _ := DeepCopy(ints)
+	gotInts[0] = 100
+
+	if ints[0] != 1 {
+		t.Errorf("DeepCopy([]int) shares backing array")
+	}
+
+	// map[string]any 中的嵌套 map 需要深拷贝
+	data := map[string]any{"user": map[string]any{"phone": "13800000000"}, "tags": []string{"a"}}
+	gotMap, _ := DeepCopy(data)
+	gotMap["user"].(map[string]any)["phone"] = "***"
+
+	if data["user"].(map[string]any)["phone"] != "13800000000" {
+		t.Errorf("DeepCopy(map[string]any) shares nested map")
+	}
+
+	// Copier
+	orig := &copierValue{id: 7, items: []string{"x"}}
+	gotCopier, err := DeepCopy(struct{ V *copierValue }{V: orig})
+	if err != nil || gotCopier.V == orig || gotCopier.V.id != 7 {
+		t.Fatalf("DeepCopy(Copier) = %+v, %v", gotCopier.V, err)
+	}
+
+	gotCopier.V.items[0] = "y"
+	if orig.items[0] != "x" {
+		t.Errorf("Copier copy shares slice")
+	}
+
+	// nil 接口
+	var empty any
+	if got, err := DeepCopy(empty); got != nil || err != nil {
+		t.Errorf("DeepCopy(nil) = %v, %v", got, err)
+	}
+}
+
+// legacyDeepCopy 改造前的实现: 不区分类型, 全部反射递归, 作为性能对比基准
+func legacyDeepCopy(src, cpy reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+
+		cpy.Set(reflect.New(src.Elem().Type()))
+		legacyDeepCopy(src.Elem(), cpy.Elem())
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			if src.Field(i).CanSet() {
+				legacyDeepCopy(src.Field(i), cpy.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+
+		cpy.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Cap()))
+
+		for i := 0; i < src.Len(); i++ {
+			legacyDeepCopy(src.Index(i), cpy.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+
+		cpy.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+
+		for _, key := range src.MapKeys() {
+			v := reflect.New(src.MapIndex(key).Type()).Elem()
+			legacyDeepCopy(src.MapIndex(key), v)
+			cpy.SetMapIndex(key, v)
+		}
+	default:
+		cpy.Set(src)
+	}
+}
+
+// BenchmarkDeepCopy 常见响应数据的深拷贝: 改造前的反射实现与带快速路径的 DeepCopy
+func BenchmarkDeepCopy(b *testing.B) {
+	type row struct {
+		ID        int64
+		Name      string
+		Tags      []string
+		Scores    []float64
+		CreatedAt time.Time
+	}
+
+	rows := make([]row, 100)
+	for i := range rows {
+		rows[i] = row{ID: int64(i), Name: "name", Tags: []string{"a", "b", "c"}, Scores: make([]float64, 20), CreatedAt: time.Now()}
+	}
+
+	cases := []struct {
+		name string
+		data any
+	}{
+		{"ints", make([]int, 1000)},
+		{"map", map[string]string{"a": "1", "b": "2", "c": "3"}},
+		{"rows", rows},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name+"/legacy", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				src := reflect.ValueOf(tc.data)
+				legacyDeepCopy(src, reflect.New(src.Type()).Elem())
+			}
+		})
+
+		b.Run(tc.name+"/DeepCopy", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				_, _ = DeepCopy(tc.data)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+//
+// FilePath    : billing-center\cron\catchup.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2025 by jiaopengzi, All Rights Reserved.
+// Description : 错过执行的补偿策略
+//
+
+package cron
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// CatchUpPolicy 进程停止期间错过执行的补偿策略
+type CatchUpPolicy int
+
+const (
+	CatchUpSkip     CatchUpPolicy = iota // 跳过错过的执行(默认)
+	CatchUpRunOnce                       // 启动时补执行一次
+	CatchUpBackfill                      // 启动时按错过的次数补执行, 最多 BackfillLimit 次
+)
+
+// defaultBackfillLimit 未设置 BackfillLimit 时的默认最大补执行次数
+const defaultBackfillLimit = 10
+
+// specParser 与 cron.WithSeconds 一致的表达式解析器
+var specParser = cron.NewParser(
+	cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// missedRuns 计算 last 之后到 now 之前错过的调度时间, 最多计算 limit 次
+//   - spec: cron 表达式
+//   - last: 上次执行时间
+//   - now: 当前时间
+//   - limit: 最多计算的次数
+func missedRuns(spec string, last, now time.Time, limit int) ([]time.Time, error) {
+	schedule, err := specParser.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("解析 cron 表达式 %s 失败: %w", spec, err)
+	}
+
+	var ticks []time.Time
+
+	for next := schedule.Next(last); !next.IsZero() && next.Before(now) && len(ticks) < limit; next = schedule.Next(next) {
+		ticks = append(ticks, next)
+	}
+
+	return ticks, nil
+}
+
+// catchUp 根据任务的补偿策略补执行错过的任务, 需要设置 HistoryStore
+func (tm *TaskManager) catchUp() {
+	if tm.history == nil {
+		return
+	}
+
+	tm.taskMutex.Lock()
+
+	tasks := make([]*Task, 0, len(tm.tasks))
+	for _, task := range tm.tasks {
+		// 一次性任务没有周期, 不需要补偿
+		if task.Spec != "" && task.CatchUp != CatchUpSkip {
+			tasks = append(tasks, task)
+		}
+	}
+
+	tm.taskMutex.Unlock()
+
+	for _, task := range tasks {
+		tm.catchUpWG.Add(1)
+
+		go func() {
+			defer tm.catchUpWG.Done()
+
+			tm.catchUpTask(task)
+		}()
+	}
+}
+
+// catchUpTask 补执行单个任务
+func (tm *TaskManager) catchUpTask(task *Task) {
+	fields := []zap.Field{zap.String("任务名", string(task.Name))}
+
+	last, err := tm.history.LastRun(tm.ctx, task.Name)
+	if err != nil {
+		zap.L().Error("获取任务执行记录失败", append(fields, zap.Error(err))...)
+		return
+	}
+
+	// 从未执行过, 不需要补偿
+	if last == nil || last.StartTime.IsZero() {
+		return
+	}
+
+	limit := 1
+	if task.CatchUp == CatchUpBackfill {
+		limit = task.BackfillLimit
+		if limit <= 0 {
+			limit = defaultBackfillLimit
+		}
+	}
+
+	ticks, err := missedRuns(task.Spec, last.StartTime, time.Now(), limit)
+	if err != nil {
+		zap.L().Error("计算错过的执行次数失败", append(fields, zap.Error(err))...)
+		return
+	}
+
+	if len(ticks) == 0 {
+		return
+	}
+
+	zap.L().Info("补执行错过的任务", append(fields, zap.Time("上次执行", last.StartTime), zap.Int("次数", len(ticks)))...)
+
+	for _, tick := range ticks {
+		// 任务管理器已停止
+		if tm.ctx.Err() != nil {
+			return
+		}
+
+		// Singleton 任务加锁并记录错过的调度时间, 避免多个实例重复补执行; 失败时已记录日志
+		if task.Singleton {
+			_, _ = tm.executeSingleton(task, tick)
+		} else {
+			_ = tm.execute(task, tick)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+//
+// FilePath    : billing-center\cron\catchup_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2025 by jiaopengzi, All Rights Reserved.
+// Description : 错过执行的补偿策略测试
+//
+
+package cron
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMissedRuns(t *testing.T) {
+	last := time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)
+	hourly := func(h int) time.Time { return last.Add(time.Duration(h) * time.Hour) }
+
+	tests := []struct {
+		name    string
+		spec    string
+		now     time.Time
+		limit   int
+		want    []time.Time
+		wantErr bool
+	}{
+		{"没有错过", "0 0 * * * *", hourly(1), 10, nil, false},
+		{"错过多次", "0 0 * * * *", hourly(3).Add(time.Minute), 10, []time.Time{hourly(1), hourly(2), hourly(3)}, false},
+		{"不超过 limit", "0 0 * * * *", hourly(5), 2, []time.Time{hourly(1), hourly(2)}, false},
+		{"表达式错误", "bad", hourly(5), 10, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := missedRuns(tt.spec, last, tt.now, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("missedRuns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("missedRuns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTaskManager_CatchUp 根据上次执行时间和补偿策略补执行错过的调度, 调度时间通过 RunInfo 传递
+func TestTaskManager_CatchUp(t *testing.T) {
+	// 每分钟执行一次, 上次执行在 5 分钟前, 错过 4 到 5 次
+	last := time.Now().Add(-5 * time.Minute)
+
+	tests := []struct {
+		name      string
+		policy    CatchUpPolicy
+		limit     int
+		last      *time.Time
+		wantCalls []int // 可接受的补执行次数
+	}{
+		{"跳过", CatchUpSkip, 0, &last, []int{0}},
+		{"补执行一次", CatchUpRunOnce, 0, &last, []int{1}},
+		{"按次数补执行", CatchUpBackfill, 0, &last, []int{4, 5}},
+		{"补执行不超过 BackfillLimit", CatchUpBackfill, 2, &last, []int{2}},
+		{"从未执行过", CatchUpBackfill, 0, nil, []int{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryHistoryStore()
+			if tt.last != nil {
+				_ = store.RecordRun(context.Background(), &RunRecord{Name: "catch_up", StartTime: *tt.last})
+			}
+
+			tm := NewTaskManager()
+			tm.SetHistoryStore(store)
+
+			var (
+				mu    sync.Mutex
+				ticks []time.Time
+			)
+
+			err := tm.AddTask(&Task{
+				Name:          "catch_up",
+				Spec:          "0 * * * * *",
+				CatchUp:       tt.policy,
+				BackfillLimit: tt.limit,
+				ActionCtx: func(ctx context.Context) error {
+					info, _ := RunInfoFromContext(ctx)
+
+					mu.Lock()
+					ticks = append(ticks, info.ScheduledAt)
+					mu.Unlock()
+
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("AddTask() error = %v", err)
+			}
+
+			tm.catchUp()
+			tm.catchUpWG.Wait()
+
+			if !slices.Contains(tt.wantCalls, len(ticks)) {
+				t.Fatalf("补执行次数 = %d, want %v", len(ticks), tt.wantCalls)
+			}
+
+			// 补执行按调度时间顺序进行, 且都在上次执行之后
+			for i, tick := range ticks {
+				if !tick.After(last) || (i > 0 && !tick.After(ticks[i-1])) {
+					t.Errorf("调度时间 = %v, 上次执行 %v", ticks, last)
+					break
+				}
+			}
+
+			// 补执行也会记录执行记录
+			if len(ticks) > 0 {
+				if record, _ := store.LastRun(context.Background(), "catch_up"); !record.StartTime.After(last) {
+					t.Errorf("补执行后执行记录 = %+v", record)
+				}
+			}
+		})
+	}
+}
@@ -9,16 +9,23 @@
 package cron
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/jiaopengzi/go-utils/redis/cache"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 )
 
 type Name string
 
+// errTaskExists 同名任务已存在
+var errTaskExists = errors.New("同名任务已存在")
+
 // Task 单独的任务结构体
 type Task struct {
 	ID         cron.EntryID // 任务ID(由cron生成)
@@ -26,33 +33,97 @@ type Task struct {
 	StartTime  time.Time    // 开始时间
 	ExpireTime time.Time    // 过期时间
 	Spec       string       // 定时任务表达式(为空表示仅执行一次)
-	Action     func() error // 执行函数
+	Action     func() error // 执行函数, 不支持取消, 建议使用 ActionCtx
+
+	// ActionCtx 支持上下文的执行函数, 优先于 Action 使用.
+	// 任务管理器停止或执行超时时 ctx 会被取消, 任务应及时返回.
+	// ctx 中携带本次执行的 RunInfo 和链路追踪ID, 可通过 RunInfoFromContext、Logger 获取.
+	ActionCtx func(ctx context.Context) error
+	Timeout   time.Duration // 单次执行超时时间, 为 0 表示不限制
+
+	CatchUp       CatchUpPolicy // 进程停止期间错过执行的补偿策略, 需要任务管理器设置 HistoryStore
+	BackfillLimit int           // CatchUpBackfill 策略下最多补执行的次数, 为 0 时默认 10 次
+
+	Singleton bool          // 多实例部署时每次调度只有一个实例执行, 上一次执行未结束时跳过本次调度, 需要任务管理器设置 Locker
+	LockTTL   time.Duration // 单实例执行锁的有效期, 执行期间自动续期, 为 0 时默认 30 秒
+
+	Retry *RetryPolicy // 执行失败时的重试策略, 为 nil 表示不重试, 等待下一次调度
+}
+
+// run 执行任务, 优先使用 ActionCtx, 任务 panic 时恢复并转换为错误, 避免影响调度器
+func (t *Task) run(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("任务 %s panic: %v", t.Name, r)
+			Logger(ctx).Error("任务 panic", zap.Any("panic", r), zap.StackSkip("stack", 2))
+		}
+	}()
+
+	if t.ActionCtx != nil {
+		return t.ActionCtx(ctx)
+	}
+
+	return t.Action()
 }
 
 // TaskManager 管理任务的添加、删除和更新
 type TaskManager struct {
 	cron      *cron.Cron
 	tasks     map[string]*Task
-	taskMutex sync.Mutex // 互斥锁，保护任务列表的并发访问
+	taskMutex sync.Mutex         // 互斥锁，保护任务列表的并发访问
+	ctx       context.Context    // 任务执行的根上下文, 停止时取消
+	cancel    context.CancelFunc // 取消根上下文
+	history   HistoryStore       // 执行记录存储, 为 nil 时不记录
+	locker    *cache.Client      // 分布式锁, 用于 Singleton 任务
+	catchUpWG sync.WaitGroup     // 等待补执行的任务完成
+
+	jobStore     DelayedJobStore           // 延迟任务存储, 为 nil 时不持久化
+	handlers     map[string]DelayedHandler // 延迟任务处理函数
+	handlerMutex sync.RWMutex              // 保护处理函数的并发访问
 }
 
 // NewTaskManager 创建一个新的任务管理器
 func NewTaskManager() *TaskManager {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &TaskManager{
 		// 如果不需要秒级别的任务可去掉 WithSeconds
-		cron:  cron.New(cron.WithSeconds()),
-		tasks: make(map[string]*Task),
+		cron:     cron.New(cron.WithSeconds()),
+		tasks:    make(map[string]*Task),
+		ctx:      ctx,
+		cancel:   cancel,
+		handlers: make(map[string]DelayedHandler),
 	}
 }
 
+// SetHistoryStore 设置执行记录存储, 设置后每次执行都会记录, 并在 Start 时根据任务的 CatchUp 策略补执行
+func (tm *TaskManager) SetHistoryStore(store HistoryStore) {
+	tm.history = store
+}
+
 // AddTask 添加任务
 func (tm *TaskManager) AddTask(task *Task) error {
 	tm.taskMutex.Lock()
 	defer tm.taskMutex.Unlock()
 
+	return tm.addTask(task)
+}
+
+// addTask 添加任务, 调用方需持有 taskMutex
+func (tm *TaskManager) addTask(task *Task) error {
+	// 检查执行函数
+	if task.Action == nil && task.ActionCtx == nil {
+		return fmt.Errorf("任务 %s 未设置执行函数", task.Name)
+	}
+
+	// Singleton 任务没有分布式锁时会在每个实例上执行, 直接拒绝
+	if task.Singleton && tm.locker == nil {
+		return fmt.Errorf("添加任务 %s 失败: %w", task.Name, errSingletonNoLocker)
+	}
+
 	// 检查任务名称是否已存在
 	if _, exists := tm.tasks[string(task.Name)]; exists {
-		return fmt.Errorf("任务 %s 已存在, 无法添加", task.Name)
+		return fmt.Errorf("添加任务 %s 失败: %w", task.Name, errTaskExists)
 	}
 
 	// 如果过期时间已过，不执行
@@ -73,24 +144,27 @@ func (tm *TaskManager) AddTask(task *Task) error {
 	}
 }
 
-// addOneTimeTask 添加一次性任务
+// addOneTimeTask 添加一次性任务, 在 StartTime 执行一次
 func (tm *TaskManager) addOneTimeTask(task *Task) error {
-	// 根据 StartTime 生成一个仅执行一次的 cron 表达式
-	singleSpec := buildOneTimeSpec(task.StartTime)
-	return tm.createTaskExecutor(task, singleSpec, true)
+	return tm.createTaskExecutor(task, &onceSchedule{at: task.StartTime}, true)
 }
 
 // addRecurringTask 添加周期性任务
 func (tm *TaskManager) addRecurringTask(task *Task) error {
-	return tm.createTaskExecutor(task, task.Spec, false)
+	schedule, err := specParser.Parse(task.Spec)
+	if err != nil {
+		return fmt.Errorf("添加任务 %s 失败: %v", task.Name, err)
+	}
+
+	return tm.createTaskExecutor(task, schedule, false)
 }
 
 // createTaskExecutor 工厂函数，用于生成任务执行和添加逻辑
 //   - task: 任务对象
-//   - spec: cron 表达式
+//   - schedule: 调度计划
 //   - isOneTime: 是否为一次性任务
-func (tm *TaskManager) createTaskExecutor(task *Task, spec string, isOneTime bool) error {
-	id, err := tm.cron.AddFunc(spec, func() {
+func (tm *TaskManager) createTaskExecutor(task *Task, schedule cron.Schedule, isOneTime bool) error {
+	id := tm.cron.Schedule(schedule, cron.FuncJob(func() {
 		// 检查是否过期
 		if !task.ExpireTime.IsZero() && time.Now().After(task.ExpireTime) {
 			if err := tm.RemoveTask(string(task.Name)); err != nil {
@@ -103,15 +177,10 @@ func (tm *TaskManager) createTaskExecutor(task *Task, spec string, isOneTime boo
 			return
 		}
 
-		// 执行任务
-		if err := task.Action(); err != nil {
-			msg := fmt.Sprintf("任务 %s 执行失败，错误信息: %v", task.Name, err)
-			zap.L().Error(msg)
-
-			return
-		}
+		// 执行任务, 失败时已记录日志
+		_ = tm.executeScheduled(task)
 
-		// 如果是一次性任务，执行完成后移除
+		// 如果是一次性任务，执行完成后移除, 失败也不会再次执行
 		if isOneTime {
 			if err := tm.RemoveTask(string(task.Name)); err != nil {
 				zap.L().Error("移除一次性任务失败", zap.String("任务名", string(task.Name)), zap.Error(err))
@@ -120,11 +189,7 @@ func (tm *TaskManager) createTaskExecutor(task *Task, spec string, isOneTime boo
 
 			zap.L().Info("一次性任务已执行完毕，停止执行", zap.String("任务名", string(task.Name)))
 		}
-	})
-
-	if err != nil {
-		return fmt.Errorf("添加任务 %s 失败: %v", task.Name, err)
-	}
+	}))
 
 	task.ID = id
 	tm.tasks[string(task.Name)] = task
@@ -132,24 +197,104 @@ func (tm *TaskManager) createTaskExecutor(task *Task, spec string, isOneTime boo
 	return nil
 }
 
-// buildOneTimeSpec 根据给定时间生成一个仅执行一次的 cron 表达式
-// 注意需要 futureTime >= 当前时间，否则生成的表达式无效
-func buildOneTimeSpec(futureTime time.Time) string {
-	now := time.Now()
+// executeScheduled 执行由 cron 调度触发的任务, Singleton 任务需要先获取分布式锁
+func (tm *TaskManager) executeScheduled(task *Task) error {
+	// cron 按秒调度, 截断到秒作为本次调度时间
+	tick := time.Now().Truncate(time.Second)
+
+	if !task.Singleton {
+		return tm.execute(task, tick)
+	}
+
+	_, err := tm.executeSingleton(task, tick)
+
+	return err
+}
+
+// execute 使用任务管理器的根上下文执行任务, 设置了 Timeout 时附加超时
+//   - task: 任务
+//   - scheduledAt: 调度时间
+func (tm *TaskManager) execute(task *Task, scheduledAt time.Time) error {
+	return tm.executeWithContext(tm.ctx, task, scheduledAt)
+}
+
+// executeWithContext 使用 ctx 执行任务, 设置了 Retry 时失败后按策略重试, 所有尝试结束后记录一次执行记录.
+// 每次调度生成一个执行ID, 与调度时间、执行次数一起通过 ctx 传递给任务, 失败时记录日志.
+//   - ctx: 上下文
+//   - task: 任务
+//   - scheduledAt: 调度时间
+func (tm *TaskManager) executeWithContext(ctx context.Context, task *Task, scheduledAt time.Time) error {
+	info := newRunInfo(task, scheduledAt)
+	record := &RunRecord{Name: task.Name, RunID: info.RunID, StartTime: time.Now()}
+
+	zap.L().Debug("任务开始执行", info.Fields()...)
+
+	err := tm.executeWithRetry(ctx, task, info)
+
+	tm.recordRun(record, err)
+
+	if err != nil {
+		zap.L().Error("任务执行失败", append(info.Fields(), zap.Duration("耗时", record.EndTime.Sub(record.StartTime)), zap.Error(err))...)
+	} else {
+		zap.L().Debug("任务执行完成", append(info.Fields(), zap.Duration("耗时", time.Since(record.StartTime)))...)
+	}
+
+	return err
+}
+
+// executeOnce 使用 ctx 执行一次任务, 设置了 Timeout 时附加超时
+func (tm *TaskManager) executeOnce(ctx context.Context, task *Task) error {
+	if task.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, task.Timeout)
+		defer cancel()
+	}
+
+	err := task.run(ctx)
 
-	// 如果 futureTime 在当前时间之前, 则将其设置为当前时间 + 1 秒
-	if futureTime.Before(now) {
-		futureTime = now.Add(1 * time.Second)
+	// 区分超时和停止导致的取消, 便于排查
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("任务执行超时(%s): %w", task.Timeout, err)
 	}
 
-	// 构造一个 "秒 分 时 日 月  周" 格式的表达式
-	return fmt.Sprintf("%d %d %d %d %d *",
-		futureTime.Second(),
-		futureTime.Minute(),
-		futureTime.Hour(),
-		futureTime.Day(),
-		futureTime.Month(),
-	)
+	return err
+}
+
+// recordRun 保存执行记录, 保存失败只记录日志
+func (tm *TaskManager) recordRun(record *RunRecord, err error) {
+	record.EndTime = time.Now()
+
+	if tm.history == nil {
+		return
+	}
+
+	if err != nil {
+		record.Err = err.Error()
+	}
+
+	// 使用独立的上下文, 避免停止时无法保存最后一次执行记录
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if errR := tm.history.RecordRun(ctx, record); errR != nil {
+		zap.L().Error("保存任务执行记录失败", zap.String("任务名", string(record.Name)), zap.Error(errR))
+	}
+}
+
+// onceSchedule 仅执行一次的调度计划, 实现 cron.Schedule 接口
+type onceSchedule struct {
+	at    time.Time   // 执行时间
+	fired atomic.Bool // 是否已调度
+}
+
+// Next 实现 cron.Schedule 接口 Next 方法, 首次返回执行时间(已过去时立即执行), 之后返回零值表示不再执行
+func (s *onceSchedule) Next(time.Time) time.Time {
+	if s.fired.Swap(true) {
+		return time.Time{}
+	}
+
+	return s.at
 }
 
 // RemoveTask 移除任务
@@ -187,26 +332,71 @@ func (tm *TaskManager) UpdateTask(task *Task) error {
 	tm.cron.Remove(existingTask.ID)
 	delete(tm.tasks, string(existingTask.Name))
 
-	// 添加新任务
-	return tm.AddTask(task)
+	// 添加新任务, 已持有锁, 不能调用 AddTask
+	return tm.addTask(task)
 }
 
-// Start 启动任务管理器
+// Start 启动任务管理器, 设置了 HistoryStore 时根据任务的 CatchUp 策略异步补执行错过的任务,
+// 设置了 DelayedJobStore 时重新加载未执行的延迟任务.
+// 停止后可以再次启动, 停止时已清理所有任务, 需要重新添加; 不能与 Stop 并发调用.
 func (tm *TaskManager) Start() {
+	// 停止时已取消根上下文, 重新启动时重新创建, 避免任务使用已取消的上下文执行
+	if tm.ctx.Err() != nil {
+		tm.ctx, tm.cancel = context.WithCancel(context.Background())
+	}
+
+	tm.reloadDelayedJobs()
+	tm.catchUp()
 	tm.cron.Start()
 }
 
-// Stop 停止任务管理器，同时清理所有已注册任务
+// defaultStopTimeout Stop 等待正在执行的任务返回的默认超时时间
+const defaultStopTimeout = 30 * time.Second
+
+// Stop 兼容旧版本的停止接口, 等价于 StopContext 并最多等待 30 秒, 超时只记录日志
 func (tm *TaskManager) Stop() {
-	tm.cron.Stop()
+	ctx, cancel := context.WithTimeout(context.Background(), defaultStopTimeout)
+	defer cancel()
+
+	_ = tm.StopContext(ctx)
+}
+
+// StopContext 停止任务管理器，同时清理所有已注册任务.
+// 不再调度新的执行, 并取消正在执行任务的上下文, 等待其返回直到 ctx 结束;
+// ctx 结束时仍有任务未返回则返回 ctx 的错误.
+func (tm *TaskManager) StopContext(ctx context.Context) error {
+	// 停止调度, 返回的上下文在所有正在执行的任务返回后结束
+	runningCtx := tm.cron.Stop()
+
+	// 通知正在执行的任务退出
+	tm.cancel()
 
 	tm.taskMutex.Lock()
-	defer tm.taskMutex.Unlock()
 
 	for name, task := range tm.tasks {
 		tm.cron.Remove(task.ID)
 		delete(tm.tasks, name)
 	}
 
-	zap.L().Info("所有任务已停止")
+	tm.taskMutex.Unlock()
+
+	// 等待补执行的任务和正在执行的任务都返回
+	done := make(chan struct{})
+
+	go func() {
+		<-runningCtx.Done()
+		tm.catchUpWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		zap.L().Info("所有任务已停止")
+
+		return nil
+	case <-ctx.Done():
+		zap.L().Warn("等待任务停止超时, 仍有任务在执行", zap.Error(ctx.Err()))
+
+		return fmt.Errorf("等待任务停止超时: %w", ctx.Err())
+	}
 }
@@ -0,0 +1,222 @@
+//
+// FilePath    : billing-center\cron\core_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2025 by jiaopengzi, All Rights Reserved.
+// Description : 任务管理器测试
+//
+
+package cron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitFor 等待 cond 成立, 超时失败
+func waitFor(t *testing.T, msg string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待超时: %s", msg)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// stopManager 测试结束时停止任务管理器
+func stopManager(t *testing.T, tm *TaskManager) {
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_ = tm.StopContext(ctx)
+	})
+}
+
+// TestTaskManager_StopContext 停止时取消正在执行任务的上下文并等待其返回, 任务不返回时等待超时
+func TestTaskManager_StopContext(t *testing.T) {
+	tm := NewTaskManager()
+
+	started := make(chan struct{})
+	cancelled := make(chan error, 1)
+
+	err := tm.RunAt("graceful", time.Now(), func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("RunAt() error = %v", err)
+	}
+
+	tm.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err = tm.StopContext(ctx); err != nil {
+		t.Fatalf("StopContext() error = %v", err)
+	}
+
+	if err = <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Errorf("任务上下文错误 = %v, want context.Canceled", err)
+	}
+
+	if len(tm.tasks) != 0 {
+		t.Errorf("停止后任务数 = %d, want 0", len(tm.tasks))
+	}
+}
+
+// TestTaskManager_StopContextTimeout 任务忽略上下文取消时 StopContext 在 ctx 结束后返回错误
+func TestTaskManager_StopContextTimeout(t *testing.T) {
+	tm := NewTaskManager()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	defer close(release)
+
+	err := tm.AddTask(&Task{Name: "stuck", Action: func() error {
+		close(started)
+		<-release
+
+		return nil
+	}})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	tm.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err = tm.StopContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("StopContext() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+// TestTaskManager_Timeout 单次执行超过 Timeout 时取消任务上下文并返回超时错误
+func TestTaskManager_Timeout(t *testing.T) {
+	tm := NewTaskManager()
+	task := &Task{Name: "timeout", Timeout: 20 * time.Millisecond, ActionCtx: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+
+	if err := tm.executeOnce(context.Background(), task); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("executeOnce() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+// TestTask_RunPanic 任务 panic 时转换为错误, 不影响调度器
+func TestTask_RunPanic(t *testing.T) {
+	task := &Task{Name: "panic", Action: func() error { panic("boom") }}
+
+	if err := task.run(context.Background()); err == nil {
+		t.Fatal("run() error = nil, want panic error")
+	}
+}
+
+// TestTaskManager_AddTask 添加任务时校验执行函数、名称和过期时间
+func TestTaskManager_AddTask(t *testing.T) {
+	tm := NewTaskManager()
+	action := func() error { return nil }
+
+	if err := tm.AddTask(&Task{Name: "recurring", Spec: "0 0 * * * *", Action: action}); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		task *Task
+	}{
+		{"未设置执行函数", &Task{Name: "no_action", Spec: "0 0 * * * *"}},
+		{"名称重复", &Task{Name: "recurring", Spec: "0 0 * * * *", Action: action}},
+		{"已经过期", &Task{Name: "expired", ExpireTime: time.Now().Add(-time.Minute), Action: action}},
+		{"表达式错误", &Task{Name: "bad_spec", Spec: "not a spec", Action: action}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tm.AddTask(tt.task); err == nil {
+				t.Error("AddTask() error = nil")
+			}
+		})
+	}
+}
+
+// TestInit_OneShot Init 添加 Spec 为空的任务, 在 StartTime 执行一次后移除
+func TestInit_OneShot(t *testing.T) {
+	done := make(chan struct{})
+
+	Tasks = []*Task{{Name: "init_once", ActionCtx: func(context.Context) error {
+		close(done)
+		return nil
+	}}}
+
+	t.Cleanup(func() {
+		Stop()
+
+		Tasks, manager = nil, nil
+	})
+
+	if err := Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("一次性任务未执行")
+	}
+
+	waitFor(t, "一次性任务执行后移除", func() bool {
+		manager.taskMutex.Lock()
+		defer manager.taskMutex.Unlock()
+
+		return len(manager.tasks) == 0
+	})
+}
+
+// TestTaskManager_Restart 停止后重新启动, 新添加的任务使用未取消的上下文执行
+func TestTaskManager_Restart(t *testing.T) {
+	tm := NewTaskManager()
+	tm.Start()
+
+	if err := tm.StopContext(context.Background()); err != nil {
+		t.Fatalf("StopContext() error = %v", err)
+	}
+
+	errs := make(chan error, 1)
+
+	err := tm.RunAt("restart", time.Now(), func(ctx context.Context) error {
+		errs <- ctx.Err()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RunAt() error = %v", err)
+	}
+
+	tm.Start()
+	stopManager(t, tm)
+
+	select {
+	case err = <-errs:
+		if err != nil {
+			t.Errorf("重新启动后任务上下文错误 = %v, want nil", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("重新启动后任务未执行")
+	}
+}
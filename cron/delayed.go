@@ -0,0 +1,296 @@
+//
+// FilePath    : billing-center\cron\delayed.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2025 by jiaopengzi, All Rights Reserved.
+// Description : 一次性延迟任务, 支持持久化后在重启时重新加载
+//
+
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jiaopengzi/go-utils/redis/cache"
+	"go.uber.org/zap"
+)
+
+// RunAt 添加在 at 执行一次的任务, 任务仅保存在内存中, 重启后丢失; 需要持久化时使用 ScheduleJob
+//   - name: 任务名称(唯一标识)
+//   - at: 执行时间, 已过去时立即执行
+//   - action: 执行函数
+func (tm *TaskManager) RunAt(name Name, at time.Time, action func(ctx context.Context) error) error {
+	return tm.AddTask(&Task{
+		Name:      name,
+		StartTime: at,
+		ActionCtx: action,
+	})
+}
+
+// RunAfter 添加在 d 之后执行一次的任务, 任务仅保存在内存中, 重启后丢失; 需要持久化时使用 ScheduleJob
+//   - name: 任务名称(唯一标识)
+//   - d: 延迟时间
+//   - action: 执行函数
+func (tm *TaskManager) RunAfter(name Name, d time.Duration, action func(ctx context.Context) error) error {
+	return tm.RunAt(name, time.Now().Add(d), action)
+}
+
+// DelayedJob 可持久化的一次性延迟任务, 执行函数不能持久化, 通过 Handler 名称关联 RegisterDelayedHandler 注册的处理函数
+type DelayedJob struct {
+	Name    Name      `json:"name"`    // 任务名称(唯一标识)
+	Handler string    `json:"handler"` // 处理函数名称
+	Payload string    `json:"payload"` // 处理函数参数, 一般为 json 字符串
+	RunAt   time.Time `json:"run_at"`  // 执行时间
+}
+
+// DelayedHandler 延迟任务处理函数
+type DelayedHandler func(ctx context.Context, payload string) error
+
+// DelayedJobStore 延迟任务存储, 用于进程重启后重新加载未执行的任务
+type DelayedJobStore interface {
+	// SaveJob 添加任务时调用, 保存任务
+	SaveJob(ctx context.Context, job *DelayedJob) error
+
+	// DeleteJob 任务执行完成(无论成功失败)或取消时调用, 删除任务
+	DeleteJob(ctx context.Context, name Name) error
+
+	// PendingJobs 启动时调用, 获取所有未执行的任务
+	PendingJobs(ctx context.Context) ([]*DelayedJob, error)
+}
+
+// SetDelayedJobStore 设置延迟任务存储, 设置后 ScheduleJob 添加的任务会被保存, 并在 Start 时重新加载
+func (tm *TaskManager) SetDelayedJobStore(store DelayedJobStore) {
+	tm.jobStore = store
+}
+
+// RegisterDelayedHandler 注册延迟任务处理函数, 需要在 Start 之前注册, 否则重新加载的任务找不到处理函数
+//   - handler: 处理函数名称
+//   - fn: 处理函数
+func (tm *TaskManager) RegisterDelayedHandler(handler string, fn DelayedHandler) {
+	tm.handlerMutex.Lock()
+	defer tm.handlerMutex.Unlock()
+
+	tm.handlers[handler] = fn
+}
+
+// delayedHandler 获取延迟任务处理函数
+func (tm *TaskManager) delayedHandler(handler string) (DelayedHandler, bool) {
+	tm.handlerMutex.RLock()
+	defer tm.handlerMutex.RUnlock()
+
+	fn, ok := tm.handlers[handler]
+
+	return fn, ok
+}
+
+// ScheduleJob 添加可持久化的延迟任务, 设置了 DelayedJobStore 时先保存再调度.
+// 保存和调度期间持有任务锁, 避免同名任务并发添加时覆盖或删除已保存的任务.
+func (tm *TaskManager) ScheduleJob(ctx context.Context, job *DelayedJob) error {
+	if _, ok := tm.delayedHandler(job.Handler); !ok {
+		return fmt.Errorf("延迟任务 %s 的处理函数 %s 未注册", job.Name, job.Handler)
+	}
+
+	tm.taskMutex.Lock()
+	defer tm.taskMutex.Unlock()
+
+	// 先检查任务是否已存在, 避免覆盖已保存的同名任务
+	if _, exists := tm.tasks[string(job.Name)]; exists {
+		return fmt.Errorf("添加延迟任务 %s 失败: %w", job.Name, errTaskExists)
+	}
+
+	if tm.jobStore != nil {
+		if err := tm.jobStore.SaveJob(ctx, job); err != nil {
+			return fmt.Errorf("保存延迟任务 %s 失败: %w", job.Name, err)
+		}
+	}
+
+	if err := tm.addDelayedJob(job); err != nil {
+		// 已存在的同名任务是其他调用方保存的, 不能删除
+		if !errors.Is(err, errTaskExists) {
+			tm.deleteJob(job.Name)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// CancelJob 取消未执行的延迟任务, 同时从 DelayedJobStore 中删除
+func (tm *TaskManager) CancelJob(ctx context.Context, name Name) error {
+	if err := tm.RemoveTask(string(name)); err != nil {
+		return err
+	}
+
+	if tm.jobStore == nil {
+		return nil
+	}
+
+	return tm.jobStore.DeleteJob(ctx, name)
+}
+
+// addDelayedJob 将延迟任务添加为一次性任务, 执行后从 DelayedJobStore 中删除; 调用方需持有 taskMutex
+func (tm *TaskManager) addDelayedJob(job *DelayedJob) error {
+	fn, _ := tm.delayedHandler(job.Handler)
+
+	return tm.addTask(&Task{
+		Name:      job.Name,
+		StartTime: job.RunAt,
+		ActionCtx: func(ctx context.Context) error {
+			defer tm.deleteJob(job.Name)
+
+			return fn(ctx, job.Payload)
+		},
+	})
+}
+
+// deleteJob 从 DelayedJobStore 中删除任务, 删除失败只记录日志
+func (tm *TaskManager) deleteJob(name Name) {
+	if tm.jobStore == nil {
+		return
+	}
+
+	// 使用独立的上下文, 避免停止时无法删除
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := tm.jobStore.DeleteJob(ctx, name); err != nil {
+		zap.L().Error("删除延迟任务失败", zap.String("任务名", string(name)), zap.Error(err))
+	}
+}
+
+// reloadDelayedJobs 重新加载 DelayedJobStore 中未执行的任务, 已过期的任务立即执行
+func (tm *TaskManager) reloadDelayedJobs() {
+	if tm.jobStore == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(tm.ctx, 10*time.Second)
+	defer cancel()
+
+	jobs, err := tm.jobStore.PendingJobs(ctx)
+	if err != nil {
+		zap.L().Error("加载延迟任务失败", zap.Error(err))
+		return
+	}
+
+	for _, job := range jobs {
+		if _, ok := tm.delayedHandler(job.Handler); !ok {
+			zap.L().Error("延迟任务的处理函数未注册, 跳过", zap.String("任务名", string(job.Name)), zap.String("处理函数", job.Handler))
+			continue
+		}
+
+		tm.taskMutex.Lock()
+		err := tm.addDelayedJob(job)
+		tm.taskMutex.Unlock()
+
+		if err != nil {
+			zap.L().Error("重新加载延迟任务失败", zap.String("任务名", string(job.Name)), zap.Error(err))
+			continue
+		}
+
+		zap.L().Info("已重新加载延迟任务", zap.String("任务名", string(job.Name)), zap.Time("执行时间", job.RunAt))
+	}
+}
+
+// MemoryDelayedJobStore 基于内存的延迟任务存储, 进程重启后丢失, 一般用于测试
+type MemoryDelayedJobStore struct {
+	mu   sync.RWMutex
+	jobs map[Name]DelayedJob
+}
+
+// NewMemoryDelayedJobStore 创建基于内存的延迟任务存储
+func NewMemoryDelayedJobStore() *MemoryDelayedJobStore {
+	return &MemoryDelayedJobStore{jobs: make(map[Name]DelayedJob)}
+}
+
+// SaveJob 实现 DelayedJobStore 接口 SaveJob 方法
+func (s *MemoryDelayedJobStore) SaveJob(_ context.Context, job *DelayedJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.Name] = *job
+
+	return nil
+}
+
+// DeleteJob 实现 DelayedJobStore 接口 DeleteJob 方法
+func (s *MemoryDelayedJobStore) DeleteJob(_ context.Context, name Name) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, name)
+
+	return nil
+}
+
+// PendingJobs 实现 DelayedJobStore 接口 PendingJobs 方法
+func (s *MemoryDelayedJobStore) PendingJobs(_ context.Context) ([]*DelayedJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jobs := make([]*DelayedJob, 0, len(s.jobs))
+
+	for _, job := range s.jobs {
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, nil
+}
+
+// delayedPurpose 延迟任务缓存键的用途
+const delayedPurpose cache.Purpose = "cron_delayed"
+
+// CacheDelayedJobStore 基于缓存(redis)的延迟任务存储, 所有任务保存在同一个 hash 中
+type CacheDelayedJobStore struct {
+	cacher cache.Cacher
+	key    string
+}
+
+// NewCacheDelayedJobStore 创建基于缓存的延迟任务存储
+//   - cacher: 缓存客户端
+//   - group: 分组名称, 不同服务使用不同分组, 避免互相加载对方的任务
+func NewCacheDelayedJobStore(cacher cache.Cacher, group string) *CacheDelayedJobStore {
+	return &CacheDelayedJobStore{cacher: cacher, key: cache.GenerateKey(delayedPurpose, group)}
+}
+
+// SaveJob 实现 DelayedJobStore 接口 SaveJob 方法
+func (s *CacheDelayedJobStore) SaveJob(ctx context.Context, job *DelayedJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return s.cacher.HSet(ctx, s.key, string(job.Name), string(data))
+}
+
+// DeleteJob 实现 DelayedJobStore 接口 DeleteJob 方法
+func (s *CacheDelayedJobStore) DeleteJob(ctx context.Context, name Name) error {
+	return s.cacher.HDel(ctx, s.key, string(name))
+}
+
+// PendingJobs 实现 DelayedJobStore 接口 PendingJobs 方法
+func (s *CacheDelayedJobStore) PendingJobs(ctx context.Context) ([]*DelayedJob, error) {
+	values, err := s.cacher.HGetAll(ctx, s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*DelayedJob, 0, len(values))
+
+	for name, value := range values {
+		var job DelayedJob
+		if err = json.Unmarshal([]byte(value), &job); err != nil {
+			return nil, fmt.Errorf("解析延迟任务 %s 失败: %w", name, err)
+		}
+
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, nil
+}
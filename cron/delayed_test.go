@@ -0,0 +1,232 @@
+//
+// FilePath    : billing-center\cron\delayed_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2025 by jiaopengzi, All Rights Reserved.
+// Description : 一次性延迟任务测试
+//
+
+package cron
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowJobStore 保存任务时等待一段时间, 放大并发添加同名任务的竞争窗口
+type slowJobStore struct {
+	*MemoryDelayedJobStore
+}
+
+// SaveJob 实现 DelayedJobStore 接口 SaveJob 方法
+func (s slowJobStore) SaveJob(ctx context.Context, job *DelayedJob) error {
+	time.Sleep(10 * time.Millisecond)
+
+	return s.MemoryDelayedJobStore.SaveJob(ctx, job)
+}
+
+// pendingJobs 获取存储中未执行的任务, 按名称索引
+func pendingJobs(t *testing.T, store DelayedJobStore) map[Name]*DelayedJob {
+	t.Helper()
+
+	jobs, err := store.PendingJobs(context.Background())
+	if err != nil {
+		t.Fatalf("PendingJobs() error = %v", err)
+	}
+
+	m := make(map[Name]*DelayedJob, len(jobs))
+	for _, job := range jobs {
+		m[job.Name] = job
+	}
+
+	return m
+}
+
+// newDelayedManager 创建使用 store 的任务管理器, 注册处理函数 handler
+func newDelayedManager(t *testing.T, store DelayedJobStore, handler DelayedHandler) *TaskManager {
+	t.Helper()
+
+	tm := NewTaskManager()
+	tm.SetDelayedJobStore(store)
+	tm.RegisterDelayedHandler("handler", handler)
+	stopManager(t, tm)
+
+	return tm
+}
+
+// TestScheduleJob_Concurrent 并发添加同名任务只有一个成功, 失败的调用不删除已保存的任务
+func TestScheduleJob_Concurrent(t *testing.T) {
+	store := slowJobStore{NewMemoryDelayedJobStore()}
+	tm := newDelayedManager(t, store, func(context.Context, string) error { return nil })
+
+	const callers = 4
+
+	var (
+		wg      sync.WaitGroup
+		winners atomic.Int32
+		payload atomic.Value
+	)
+
+	for i := range callers {
+		wg.Go(func() {
+			job := &DelayedJob{Name: "order_timeout", Handler: "handler", Payload: strconv.Itoa(i), RunAt: time.Now().Add(time.Hour)}
+
+			err := tm.ScheduleJob(context.Background(), job)
+			if err == nil {
+				winners.Add(1)
+				payload.Store(job.Payload)
+
+				return
+			}
+
+			if !errors.Is(err, errTaskExists) {
+				t.Errorf("ScheduleJob() error = %v, want errTaskExists", err)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if got := winners.Load(); got != 1 {
+		t.Fatalf("成功添加次数 = %d, want 1", got)
+	}
+
+	job, ok := pendingJobs(t, store)["order_timeout"]
+	if !ok {
+		t.Fatal("已保存的任务被删除")
+	}
+
+	if job.Payload != payload.Load() {
+		t.Errorf("保存的 Payload = %q, want %q", job.Payload, payload.Load())
+	}
+}
+
+// TestScheduleJob_Run 任务到期后调用处理函数并从存储中删除
+func TestScheduleJob_Run(t *testing.T) {
+	store := NewMemoryDelayedJobStore()
+	payloads := make(chan string, 1)
+
+	tm := newDelayedManager(t, store, func(_ context.Context, payload string) error {
+		payloads <- payload
+		return nil
+	})
+	tm.Start()
+
+	err := tm.ScheduleJob(context.Background(), &DelayedJob{Name: "run", Handler: "handler", Payload: `{"id":1}`, RunAt: time.Now()})
+	if err != nil {
+		t.Fatalf("ScheduleJob() error = %v", err)
+	}
+
+	select {
+	case got := <-payloads:
+		if got != `{"id":1}` {
+			t.Errorf("payload = %q", got)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("延迟任务未执行")
+	}
+
+	waitFor(t, "执行后从存储中删除", func() bool { return len(pendingJobs(t, store)) == 0 })
+}
+
+// TestScheduleJob_Reload 重启后 Start 重新加载存储中的任务, 已过期的立即执行, 处理函数未注册的保留在存储中
+func TestScheduleJob_Reload(t *testing.T) {
+	store := NewMemoryDelayedJobStore()
+	ctx := context.Background()
+
+	// 第一个进程添加任务后停止
+	first := newDelayedManager(t, store, func(context.Context, string) error { return nil })
+	if err := first.ScheduleJob(ctx, &DelayedJob{Name: "reload", Handler: "handler", RunAt: time.Now().Add(50 * time.Millisecond)}); err != nil {
+		t.Fatalf("ScheduleJob() error = %v", err)
+	}
+
+	if err := first.StopContext(ctx); err != nil {
+		t.Fatalf("StopContext() error = %v", err)
+	}
+
+	_ = store.SaveJob(ctx, &DelayedJob{Name: "orphan", Handler: "missing", RunAt: time.Now()})
+
+	// 重启后的进程重新加载
+	executed := make(chan struct{})
+	second := newDelayedManager(t, store, func(context.Context, string) error {
+		close(executed)
+		return nil
+	})
+	second.Start()
+
+	select {
+	case <-executed:
+	case <-time.After(3 * time.Second):
+		t.Fatal("重新加载的任务未执行")
+	}
+
+	waitFor(t, "执行后从存储中删除", func() bool {
+		_, ok := pendingJobs(t, store)["reload"]
+		return !ok
+	})
+
+	if _, ok := pendingJobs(t, store)["orphan"]; !ok {
+		t.Error("处理函数未注册的任务被删除")
+	}
+}
+
+// TestScheduleJob_Cancel 取消任务后不再执行并从存储中删除, 未注册处理函数的任务无法添加
+func TestScheduleJob_Cancel(t *testing.T) {
+	store := NewMemoryDelayedJobStore()
+	ctx := context.Background()
+	tm := newDelayedManager(t, store, func(context.Context, string) error { return nil })
+
+	if err := tm.ScheduleJob(ctx, &DelayedJob{Name: "missing", Handler: "missing", RunAt: time.Now()}); err == nil {
+		t.Error("未注册处理函数 ScheduleJob() error = nil")
+	}
+
+	if err := tm.ScheduleJob(ctx, &DelayedJob{Name: "cancel", Handler: "handler", RunAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("ScheduleJob() error = %v", err)
+	}
+
+	if err := tm.CancelJob(ctx, "cancel"); err != nil {
+		t.Fatalf("CancelJob() error = %v", err)
+	}
+
+	if jobs := pendingJobs(t, store); len(jobs) != 0 {
+		t.Errorf("取消后存储中的任务 = %v", jobs)
+	}
+
+	if err := tm.CancelJob(ctx, "cancel"); err == nil {
+		t.Error("重复取消 CancelJob() error = nil")
+	}
+}
+
+// TestRunAfter 延迟执行一次后移除任务, 名称可以再次使用
+func TestRunAfter(t *testing.T) {
+	tm := NewTaskManager()
+	stopManager(t, tm)
+	tm.Start()
+
+	var calls atomic.Int32
+
+	action := func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}
+
+	if err := tm.RunAfter("after", 10*time.Millisecond, action); err != nil {
+		t.Fatalf("RunAfter() error = %v", err)
+	}
+
+	waitFor(t, "一次性任务执行后移除", func() bool {
+		tm.taskMutex.Lock()
+		defer tm.taskMutex.Unlock()
+
+		return calls.Load() == 1 && len(tm.tasks) == 0
+	})
+
+	if err := tm.RunAfter("after", 0, action); err != nil {
+		t.Errorf("执行后再次 RunAfter() error = %v", err)
+	}
+}
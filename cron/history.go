@@ -0,0 +1,108 @@
+//
+// FilePath    : billing-center\cron\history.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2025 by jiaopengzi, All Rights Reserved.
+// Description : 定时任务执行记录
+//
+
+package cron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jiaopengzi/go-utils/redis/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// RunRecord 单次执行记录
+type RunRecord struct {
+	Name      Name      `json:"name"`       // 任务名称
+	RunID     string    `json:"run_id"`     // 执行ID
+	StartTime time.Time `json:"start_time"` // 开始时间
+	EndTime   time.Time `json:"end_time"`   // 结束时间
+	Err       string    `json:"err"`        // 错误信息, 成功时为空
+}
+
+// HistoryStore 执行记录存储, 用于进程重启后获取任务上次执行时间
+type HistoryStore interface {
+	// LastRun 获取任务最近一次执行记录, 没有记录时返回 nil, nil
+	LastRun(ctx context.Context, name Name) (*RunRecord, error)
+
+	// RecordRun 保存执行记录
+	RecordRun(ctx context.Context, record *RunRecord) error
+}
+
+// MemoryHistoryStore 基于内存的执行记录存储, 进程重启后丢失, 一般用于测试
+type MemoryHistoryStore struct {
+	mu      sync.RWMutex
+	records map[Name]RunRecord
+}
+
+// NewMemoryHistoryStore 创建基于内存的执行记录存储
+func NewMemoryHistoryStore() *MemoryHistoryStore {
+	return &MemoryHistoryStore{records: make(map[Name]RunRecord)}
+}
+
+// LastRun 实现 HistoryStore 接口 LastRun 方法
+func (s *MemoryHistoryStore) LastRun(_ context.Context, name Name) (*RunRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.records[name]
+	if !ok {
+		return nil, nil
+	}
+
+	return &record, nil
+}
+
+// RecordRun 实现 HistoryStore 接口 RecordRun 方法
+func (s *MemoryHistoryStore) RecordRun(_ context.Context, record *RunRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[record.Name] = *record
+
+	return nil
+}
+
+// historyPurpose 执行记录缓存键的用途
+const historyPurpose cache.Purpose = "cron_history"
+
+// CacheHistoryStore 基于缓存(redis)的执行记录存储
+type CacheHistoryStore struct {
+	cacher cache.Cacher
+	ttl    time.Duration // 记录有效期, 为 0 表示永久
+}
+
+// NewCacheHistoryStore 创建基于缓存的执行记录存储
+//   - cacher: 缓存客户端
+//   - ttl: 记录有效期, 为 0 表示永久
+func NewCacheHistoryStore(cacher cache.Cacher, ttl time.Duration) *CacheHistoryStore {
+	return &CacheHistoryStore{cacher: cacher, ttl: ttl}
+}
+
+// LastRun 实现 HistoryStore 接口 LastRun 方法
+func (s *CacheHistoryStore) LastRun(ctx context.Context, name Name) (*RunRecord, error) {
+	var record RunRecord
+
+	err := s.cacher.GetStringWithStruct(ctx, cache.GenerateKey(historyPurpose, string(name)), &record)
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// RecordRun 实现 HistoryStore 接口 RecordRun 方法
+func (s *CacheHistoryStore) RecordRun(ctx context.Context, record *RunRecord) error {
+	return s.cacher.SetStringWithStruct(ctx, cache.GenerateKey(historyPurpose, string(record.Name)), record, s.ttl)
+}
@@ -10,11 +10,46 @@
 package cron
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/jiaopengzi/go-utils/redis/cache"
 )
 
 // 定时任务变量
-var Tasks []*Task // 存储所有的定时任务
+var (
+	Tasks   []*Task       // 存储所有的定时任务
+	manager *TaskManager  // Init 创建的任务管理器
+	history HistoryStore  // Init 创建的任务管理器使用的执行记录存储
+	locker  *cache.Client // Init 创建的任务管理器使用的分布式锁
+
+	jobStore DelayedJobStore                   // Init 创建的任务管理器使用的延迟任务存储
+	handlers = make(map[string]DelayedHandler) // Init 创建的任务管理器使用的延迟任务处理函数
+)
+
+// SetLocker 设置 Init 创建的任务管理器使用的分布式锁, 需要在 Init 之前调用, 存在 Singleton 任务时必须设置
+func SetLocker(l *cache.Client) {
+	locker = l
+}
+
+// SetHistoryStore 设置 Init 创建的任务管理器使用的执行记录存储, 需要在 Init 之前调用
+func SetHistoryStore(store HistoryStore) {
+	history = store
+}
+
+// SetDelayedJobStore 设置 Init 创建的任务管理器使用的延迟任务存储, 需要在 Init 之前调用
+func SetDelayedJobStore(store DelayedJobStore) {
+	jobStore = store
+}
+
+// RegisterDelayedHandler 注册延迟任务处理函数, 需要在 Init 之前调用
+//   - handler: 处理函数名称
+//   - fn: 处理函数
+func RegisterDelayedHandler(handler string, fn DelayedHandler) {
+	handlers[handler] = fn
+}
 
 // TaskRegistrar 定义任务注册函数类型
 type TaskRegistrar func() error
@@ -46,14 +81,18 @@ func Init() error {
 	}
 
 	// 创建任务管理器
-	manager := NewTaskManager()
+	manager = NewTaskManager()
+	manager.SetHistoryStore(history)
 
-	for _, task := range Tasks {
-		// 定时任务的cron表达式配置不能为空
-		if task.Spec == "" {
-			return fmt.Errorf("定时任务的cron表达式配置不能为空，任务名称：%s", string(task.Name))
-		}
+	manager.SetLocker(locker)
+	manager.SetDelayedJobStore(jobStore)
 
+	for handler, fn := range handlers {
+		manager.RegisterDelayedHandler(handler, fn)
+	}
+
+	// Spec 为空的任务在 StartTime 执行一次
+	for _, task := range Tasks {
 		err := manager.AddTask(task)
 		if err != nil {
 			return fmt.Errorf("添加任务 %s 失败: %w", string(task.Name), err)
@@ -65,3 +104,60 @@ func Init() error {
 
 	return nil
 }
+
+// Stop 停止 Init 创建的任务管理器, 参见 TaskManager.Stop
+func Stop() {
+	if manager == nil {
+		return
+	}
+
+	manager.Stop()
+}
+
+// StopContext 停止 Init 创建的任务管理器, 取消正在执行的任务并等待其返回直到 ctx 结束, 参见 TaskManager.StopContext
+func StopContext(ctx context.Context) error {
+	if manager == nil {
+		return nil
+	}
+
+	return manager.StopContext(ctx)
+}
+
+// errNotInit 任务管理器未初始化
+var errNotInit = errors.New("定时任务未初始化, 请先调用 Init")
+
+// RunAt 使用 Init 创建的任务管理器添加在 at 执行一次的任务, 参见 TaskManager.RunAt
+func RunAt(name Name, at time.Time, action func(ctx context.Context) error) error {
+	if manager == nil {
+		return errNotInit
+	}
+
+	return manager.RunAt(name, at, action)
+}
+
+// RunAfter 使用 Init 创建的任务管理器添加在 d 之后执行一次的任务, 参见 TaskManager.RunAfter
+func RunAfter(name Name, d time.Duration, action func(ctx context.Context) error) error {
+	if manager == nil {
+		return errNotInit
+	}
+
+	return manager.RunAfter(name, d, action)
+}
+
+// ScheduleJob 使用 Init 创建的任务管理器添加可持久化的延迟任务, 参见 TaskManager.ScheduleJob
+func ScheduleJob(ctx context.Context, job *DelayedJob) error {
+	if manager == nil {
+		return errNotInit
+	}
+
+	return manager.ScheduleJob(ctx, job)
+}
+
+// CancelJob 使用 Init 创建的任务管理器取消延迟任务, 参见 TaskManager.CancelJob
+func CancelJob(ctx context.Context, name Name) error {
+	if manager == nil {
+		return errNotInit
+	}
+
+	return manager.CancelJob(ctx, name)
+}
@@ -0,0 +1,79 @@
+//
+// FilePath    : billing-center\cron\retry.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2025 by jiaopengzi, All Rights Reserved.
+// Description : 任务执行失败的重试策略
+//
+
+package cron
+
+import (
+	"context"
+	"time"
+
+	"github.com/jiaopengzi/go-utils"
+	"go.uber.org/zap"
+)
+
+// defaultRetryBackoff 默认首次重试间隔
+const defaultRetryBackoff = time.Second
+
+// RetryPolicy 任务执行失败时的重试策略, 重试在本次调度内进行, 不等待下一次调度
+type RetryPolicy struct {
+	MaxAttempts int                  // 最大执行次数(含首次), 小于等于 1 表示不重试
+	Backoff     time.Duration        // 首次重试间隔, 为 0 时默认 1 秒
+	MaxBackoff  time.Duration        // 最大重试间隔, 为 0 表示不限制
+	Multiplier  float64              // 每次重试间隔的倍数, 小于 1 时默认 2
+	RetryIf     func(err error) bool // 判断错误是否需要重试, 为 nil 时所有错误都重试
+}
+
+// retryOptions 转换为 utils.Retry 的配置, 是否重试由 shouldRetry 判断, 不重试的错误以 utils.Permanent 返回
+func (p *RetryPolicy) retryOptions() []utils.RetryOption {
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	return []utils.RetryOption{
+		utils.WithMaxAttempts(p.MaxAttempts),
+		utils.WithBackoff(utils.ExponentialBackoff(backoff, p.MaxBackoff, p.Multiplier)),
+		utils.RetryIf(func(error) bool { return true }),
+	}
+}
+
+// shouldRetry 判断第 attempt 次执行失败后是否需要重试
+func (p *RetryPolicy) shouldRetry(attempt int, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+
+	return p.RetryIf == nil || p.RetryIf(err)
+}
+
+// executeWithRetry 执行任务, 设置了 Retry 时失败后按策略重试, ctx 取消时停止重试, 返回的错误包含最后一次的错误
+//   - ctx: 上下文
+//   - task: 任务
+//   - info: 本次调度的执行信息, 每次重试递增 Attempt
+func (tm *TaskManager) executeWithRetry(ctx context.Context, task *Task, info RunInfo) error {
+	if task.Retry == nil {
+		return tm.executeOnce(withRunInfo(ctx, info), task)
+	}
+
+	return utils.Retry(ctx, func(ctx context.Context) error {
+		err := tm.executeOnce(withRunInfo(ctx, info), task)
+		if err != nil && !task.Retry.shouldRetry(info.Attempt, err) {
+			return utils.Permanent(err)
+		}
+
+		if err == nil && info.Attempt > 1 {
+			zap.L().Info("任务重试成功", info.Fields()...)
+		}
+
+		return err
+	}, append(task.Retry.retryOptions(), utils.WithOnRetry(func(_ int, err error, wait time.Duration) {
+		zap.L().Warn("任务执行失败, 等待重试", append(info.Fields(), zap.Duration("等待时间", wait), zap.Error(err))...)
+
+		info.Attempt++
+	}))...)
+}
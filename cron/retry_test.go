@@ -0,0 +1,97 @@
+//
+// FilePath    : billing-center\cron\retry_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2025 by jiaopengzi, All Rights Reserved.
+// Description : 任务执行失败的重试策略测试
+//
+
+package cron
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTaskManager_ExecuteWithRetry(t *testing.T) {
+	errTemporary := errors.New("temporary")
+	errFatal := errors.New("fatal")
+	errPanic := errors.New("panic") // 执行时 panic
+
+	tests := []struct {
+		name         string
+		retry        *RetryPolicy
+		errs         []error // 每次执行返回的错误, 超出时返回 nil
+		wantErr      error
+		wantAttempts []int
+	}{
+		{"不重试", nil, []error{errTemporary}, errTemporary, []int{1}},
+		{"重试后成功", &RetryPolicy{MaxAttempts: 3}, []error{errTemporary, errTemporary}, nil, []int{1, 2, 3}},
+		{"达到最大次数", &RetryPolicy{MaxAttempts: 2}, []error{errTemporary, errTemporary, errTemporary}, errTemporary, []int{1, 2}},
+		{"RetryIf 不重试", &RetryPolicy{MaxAttempts: 3, RetryIf: func(err error) bool { return !errors.Is(err, errFatal) }}, []error{errTemporary, errFatal}, errFatal, []int{1, 2}},
+		{"panic 也重试", &RetryPolicy{MaxAttempts: 2}, []error{errPanic}, nil, []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.retry != nil {
+				tt.retry.Backoff = time.Millisecond
+			}
+
+			var attempts []int
+
+			task := &Task{Name: "retry", Retry: tt.retry, ActionCtx: func(ctx context.Context) error {
+				info, _ := RunInfoFromContext(ctx)
+				attempts = append(attempts, info.Attempt)
+
+				var err error
+				if i := len(attempts) - 1; i < len(tt.errs) {
+					err = tt.errs[i]
+				}
+
+				if err == errPanic {
+					panic("boom")
+				}
+
+				return err
+			}}
+
+			err := NewTaskManager().execute(task, time.Now())
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("execute() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !slices.Equal(attempts, tt.wantAttempts) {
+				t.Errorf("attempts = %v, want %v", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+// TestTaskManager_RetryStop 任务管理器停止时不再重试
+func TestTaskManager_RetryStop(t *testing.T) {
+	tm := NewTaskManager()
+
+	calls := 0
+	task := &Task{Name: "retry_stop", Retry: &RetryPolicy{MaxAttempts: 5, Backoff: time.Hour}, Action: func() error {
+		calls++
+		return errors.New("temporary")
+	}}
+
+	time.AfterFunc(20*time.Millisecond, tm.cancel)
+
+	done := make(chan error, 1)
+	go func() { done <- tm.execute(task, time.Now()) }()
+
+	select {
+	case err := <-done:
+		if err == nil || calls != 1 {
+			t.Errorf("execute() error = %v, calls = %d, want error after 1 call", err, calls)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("停止后仍在等待重试")
+	}
+}
@@ -0,0 +1,83 @@
+//
+// FilePath    : billing-center\cron\run.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2025 by jiaopengzi, All Rights Reserved.
+// Description : 单次执行的上下文信息, 包含执行ID、调度时间和执行次数, 用于日志和跨系统追踪
+//
+
+package cron
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jiaopengzi/go-utils"
+	"go.uber.org/zap"
+)
+
+// RunInfo 单次执行的信息, 通过 ActionCtx 的 ctx 传递给任务
+type RunInfo struct {
+	RunID       string    // 执行ID, 同一次调度的重试共用
+	Name        Name      // 任务名称
+	ScheduledAt time.Time // 调度时间, 补执行时为错过的调度时间
+	Attempt     int       // 第几次执行, 从 1 开始, 重试时递增
+}
+
+// runInfoKey RunInfo 的上下文键
+type runInfoKey struct{}
+
+// withRunInfo 返回携带执行信息的上下文, 上下文中没有链路追踪ID时使用执行ID
+func withRunInfo(ctx context.Context, info RunInfo) context.Context {
+	ctx = context.WithValue(ctx, runInfoKey{}, info)
+
+	if utils.TraceIDFromContext(ctx) == "" {
+		ctx = utils.ContextWithTraceID(ctx, info.RunID)
+	}
+
+	return ctx
+}
+
+// RunInfoFromContext 获取任务执行的信息, 不在任务执行中时返回 false
+func RunInfoFromContext(ctx context.Context) (RunInfo, bool) {
+	if ctx == nil {
+		return RunInfo{}, false
+	}
+
+	info, ok := ctx.Value(runInfoKey{}).(RunInfo)
+
+	return info, ok
+}
+
+// Fields 执行信息的日志字段
+func (i RunInfo) Fields() []zap.Field {
+	return []zap.Field{
+		zap.String("任务名", string(i.Name)),
+		zap.String("执行ID", i.RunID),
+		zap.Time("调度时间", i.ScheduledAt),
+		zap.Int("执行次数", i.Attempt),
+	}
+}
+
+// Logger 获取附加了执行信息的日志记录器, 不在任务执行中时返回 zap.L()
+func Logger(ctx context.Context) *zap.Logger {
+	info, ok := RunInfoFromContext(ctx)
+	if !ok {
+		return zap.L()
+	}
+
+	return zap.L().With(info.Fields()...)
+}
+
+// newRunInfo 创建一次调度的执行信息
+//   - task: 任务
+//   - scheduledAt: 调度时间
+func newRunInfo(task *Task, scheduledAt time.Time) RunInfo {
+	return RunInfo{
+		RunID:       uuid.NewString(),
+		Name:        task.Name,
+		ScheduledAt: scheduledAt,
+		Attempt:     1,
+	}
+}
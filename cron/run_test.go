@@ -0,0 +1,79 @@
+//
+// FilePath    : billing-center\cron\run_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2025 by jiaopengzi, All Rights Reserved.
+// Description : 单次执行的上下文信息测试
+//
+
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jiaopengzi/go-utils"
+	"go.uber.org/zap"
+)
+
+// TestRunInfo 任务通过 ctx 获取执行信息, 没有链路追踪ID时使用执行ID, 执行记录使用相同的执行ID
+func TestRunInfo(t *testing.T) {
+	store := NewMemoryHistoryStore()
+	tm := NewTaskManager()
+	tm.SetHistoryStore(store)
+
+	scheduledAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+
+	var (
+		info    RunInfo
+		ok      bool
+		traceID string
+	)
+
+	task := &Task{Name: "run_info", ActionCtx: func(ctx context.Context) error {
+		info, ok = RunInfoFromContext(ctx)
+		traceID = utils.TraceIDFromContext(ctx)
+
+		return nil
+	}}
+
+	if err := tm.execute(task, scheduledAt); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	if !ok || info.RunID == "" || info.Name != "run_info" || !info.ScheduledAt.Equal(scheduledAt) || info.Attempt != 1 {
+		t.Fatalf("RunInfoFromContext() = %+v, %v", info, ok)
+	}
+
+	if traceID != info.RunID {
+		t.Errorf("trace ID = %q, want run ID %q", traceID, info.RunID)
+	}
+
+	if record, _ := store.LastRun(context.Background(), "run_info"); record == nil || record.RunID != info.RunID {
+		t.Errorf("LastRun() = %+v, want run ID %q", record, info.RunID)
+	}
+
+	// 每次调度生成新的执行ID
+	first := info.RunID
+	if _ = tm.execute(task, scheduledAt); info.RunID == first {
+		t.Errorf("两次调度的执行ID相同: %q", first)
+	}
+}
+
+// TestRunInfo_TraceID 上下文中已有链路追踪ID时保留
+func TestRunInfo_TraceID(t *testing.T) {
+	ctx := withRunInfo(utils.ContextWithTraceID(context.Background(), "upstream"), RunInfo{RunID: "run"})
+
+	if got := utils.TraceIDFromContext(ctx); got != "upstream" {
+		t.Errorf("trace ID = %q, want upstream", got)
+	}
+
+	if _, ok := RunInfoFromContext(context.Background()); ok {
+		t.Error("不在任务执行中 RunInfoFromContext() ok = true")
+	}
+
+	if Logger(context.Background()) != zap.L() {
+		t.Error("不在任务执行中 Logger() 不是 zap.L()")
+	}
+}
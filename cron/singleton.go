@@ -0,0 +1,89 @@
+//
+// FilePath    : billing-center\cron\singleton.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2025 by jiaopengzi, All Rights Reserved.
+// Description : 多实例部署时基于 redis 分布式锁的单实例执行
+//
+
+package cron
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jiaopengzi/go-utils/redis/cache"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// lockPurpose 单实例执行锁缓存键的用途
+const lockPurpose cache.Purpose = "cron_lock"
+
+// errSingletonNoLocker Singleton 任务未设置分布式锁
+var errSingletonNoLocker = errors.New("singleton 任务需要先调用 SetLocker 设置分布式锁")
+
+// SetLocker 设置分布式锁使用的缓存客户端, 添加 Singleton 任务前必须设置
+func (tm *TaskManager) SetLocker(locker *cache.Client) {
+	tm.locker = locker
+}
+
+// executeSingleton 获取任务的分布式锁后执行本次调度, 返回是否由当前实例执行.
+// 锁按任务加锁, 未获取到说明其他实例正在执行该任务, 本次调度直接跳过, 因此上一次执行未结束时不会重叠执行.
+// 获取锁后记录已执行的最新调度时间, 调度时间不晚于该值说明其他实例已执行, 避免实例间时钟差异导致同一次调度重复执行;
+// 执行期间自动续期, 续期失败时取消任务上下文, 执行结束后释放锁.
+//   - task: 任务
+//   - tick: 本次调度时间
+func (tm *TaskManager) executeSingleton(task *Task, tick time.Time) (bool, error) {
+	fields := []zap.Field{zap.String("任务名", string(task.Name)), zap.Time("调度时间", tick)}
+
+	l, err := tm.locker.TryLock(tm.ctx, cache.GenerateKey(lockPurpose, string(task.Name)), task.LockTTL)
+	if err != nil {
+		if errors.Is(err, cache.ErrLockNotAcquired) {
+			zap.L().Debug("其他实例正在执行任务, 跳过", fields...)
+			return false, nil
+		}
+
+		zap.L().Error("获取任务锁失败", append(fields, zap.Error(err))...)
+
+		return false, err
+	}
+
+	defer func() {
+		if errU := l.Unlock(context.WithoutCancel(tm.ctx)); errU != nil {
+			zap.L().Warn("释放任务锁失败, 等待有效期结束自动释放", append(fields, zap.Error(errU))...)
+		}
+	}()
+
+	if ok, errM := tm.markTick(task, tick); errM != nil || !ok {
+		if errM != nil {
+			zap.L().Error("记录任务调度时间失败", append(fields, zap.Error(errM))...)
+		} else {
+			zap.L().Debug("其他实例已执行本次调度, 跳过", fields...)
+		}
+
+		return false, errM
+	}
+
+	ctx, stop := l.AutoRenew(tm.ctx)
+	defer stop()
+
+	return true, tm.executeWithContext(ctx, task, tick)
+}
+
+// markTick 记录任务已执行的最新调度时间, tick 不晚于已记录的值时返回 false; 调用方需持有任务的分布式锁
+func (tm *TaskManager) markTick(task *Task, tick time.Time) (bool, error) {
+	key := cache.GenerateKey(lockPurpose, string(task.Name), "last_tick")
+
+	last, err := tm.locker.GetCounterValue(tm.ctx, key)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return false, err
+	}
+
+	if tick.Unix() <= last {
+		return false, nil
+	}
+
+	return true, tm.locker.SetCounter(tm.ctx, key, tick.Unix(), 0)
+}
@@ -0,0 +1,104 @@
+//
+// FilePath    : billing-center\cron\singleton_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2025 by jiaopengzi, All Rights Reserved.
+// Description : 多实例单实例执行测试, 使用 miniredis
+//
+
+package cron
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jiaopengzi/go-utils/redis/cache"
+)
+
+// newTestLocker 创建使用 miniredis 的分布式锁客户端
+func newTestLocker(t *testing.T) *cache.Client {
+	t.Helper()
+
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	return cache.NewClient(rdb)
+}
+
+// TestTaskManager_SingletonNoLocker 没有设置分布式锁时拒绝添加 Singleton 任务
+func TestTaskManager_SingletonNoLocker(t *testing.T) {
+	err := NewTaskManager().AddTask(&Task{Name: "singleton", Spec: "0 * * * * *", Singleton: true, Action: func() error { return nil }})
+	if !errors.Is(err, errSingletonNoLocker) {
+		t.Errorf("AddTask() error = %v, want errSingletonNoLocker", err)
+	}
+}
+
+// TestTaskManager_Singleton 多个实例的同一次调度只执行一次, 上一次执行未结束时其他实例跳过
+func TestTaskManager_Singleton(t *testing.T) {
+	locker := newTestLocker(t)
+
+	instances := make([]*TaskManager, 2)
+	for i := range instances {
+		instances[i] = NewTaskManager()
+		instances[i].SetLocker(locker)
+	}
+
+	var calls atomic.Int32
+
+	task := &Task{Name: "singleton", Spec: "0 * * * * *", Singleton: true, Action: func() error {
+		calls.Add(1)
+		return nil
+	}}
+
+	tick := time.Now().Truncate(time.Second)
+
+	for i, tm := range instances {
+		ran, err := tm.executeSingleton(task, tick)
+		if err != nil || ran != (i == 0) {
+			t.Errorf("实例 %d executeSingleton() = %v, %v, want %v", i, ran, err, i == 0)
+		}
+	}
+
+	// 下一次调度由任意实例执行一次
+	if ran, err := instances[1].executeSingleton(task, tick.Add(time.Second)); err != nil || !ran {
+		t.Errorf("下一次调度 executeSingleton() = %v, %v, want true", ran, err)
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("执行次数 = %d, want 2", got)
+	}
+
+	// 执行期间其他实例获取不到锁, 跳过本次调度
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan bool)
+
+	blocking := &Task{Name: "blocking", Singleton: true, Action: func() error {
+		close(started)
+		<-release
+
+		return nil
+	}}
+
+	go func() {
+		ran, _ := instances[0].executeSingleton(blocking, tick)
+		done <- ran
+	}()
+
+	<-started
+
+	if ran, err := instances[1].executeSingleton(blocking, tick.Add(time.Second)); err != nil || ran {
+		t.Errorf("执行期间 executeSingleton() = %v, %v, want false", ran, err)
+	}
+
+	close(release)
+
+	if !<-done {
+		t.Error("持有锁的实例未执行")
+	}
+}
@@ -174,17 +174,23 @@ func PlayKeyEncryptAES2Base64(playKey string, playKeyKey string, ivStr ...string
 
 // PlayKeyDecryptAES2String 使用 AES 解密算法用 encryptKey 解密 playKey 生成解密后的密钥 encryptPlayKey 16进制字符串
 func PlayKeyDecryptAES2String(playKeyEncrypt string) (string, error) {
-	// 获取 playKeyEncrypt 字符长度
-	l := len(playKeyEncrypt)
+	// 按字符截取, 避免非法输入中的多字节字符被截断
+	runes := []rune(playKeyEncrypt)
+
+	// 获取 playKeyEncrypt 字符长度, 至少包含 32 位的 playKeyKey 和 16 位的 iv
+	l := len(runes)
+	if l <= 32+16 {
+		return "", errors.New("无效的播放密钥长度")
+	}
 
 	// 获取 playKeyKey 从 playKeyEncryptAES2Base64 中从左至右截取 32 长度的字符串并逆序排列
-	playKeyKey := ReverseString(playKeyEncrypt[:32])
+	playKeyKey := ReverseString(string(runes[:32]))
 
 	// 获取 iv 从 playKeyEncryptAES2Base64 中从右至左截取 16 长度的字符串,并逆序排列
-	iv := ReverseString(playKeyEncrypt[l-16:])
+	iv := ReverseString(string(runes[l-16:]))
 
 	// 获取 encryptedPlayKeyBase64 从 playKeyEncrypt 中从 32 开始到 l-16 的字符串
-	encryptedPlayKeyBase64 := playKeyEncrypt[32 : l-16]
+	encryptedPlayKeyBase64 := string(runes[32 : l-16])
 
 	// 使用 AES 解密算法用 encryptKey 解密 playKey 生成解密后的密钥 encryptPlayKey 16进制字符串
 	playKey, err := DecryptAES(encryptedPlayKeyBase64, playKeyKey, iv)
@@ -195,16 +201,6 @@ func PlayKeyDecryptAES2String(playKeyEncrypt string) (string, error) {
 	return playKey, nil
 }
 
-// ReverseString 将字符串逆序排列并返回
-func ReverseString(str string) string {
-	var result string
-	for _, v := range str {
-		result = string(v) + result
-	}
-
-	return result
-}
-
 // GenerateAESKeyAndIV 生成 AES 加密算法的 32 位的 key 和 16 位的 iv
 func GenerateAESKeyAndIV() (string, string, error) {
 	// 生成 32 位的 key 和 16 位的 iv
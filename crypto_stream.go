@@ -0,0 +1,254 @@
+//
+// FilePath    : go-utils\crypto_stream.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 流式 AES-GCM 加密解密, 按固定大小分块处理, 适用于备份、导出等大文件
+//
+
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// 流式密文格式:
+//
+//	header: magic(4) | version(1) | chunkSize(4, 大端) | noncePrefix(7)
+//	chunk:  AES-GCM(明文分块), 除最后一块外明文长度均为 chunkSize, 每块附带 16 字节认证标签
+//
+// 每块的 nonce 为 noncePrefix(7) | 分块序号(4, 大端) | 是否最后一块(1), header 作为每块的附加认证数据.
+// 分块序号防止分块被重排或重复, 最后一块标记防止密文被截断, 明文长度恰好为 chunkSize 整数倍时末尾追加一个空的最后一块.
+const (
+	gcmStreamMagic            = "JGCM"
+	gcmStreamVersion          = 1
+	gcmStreamNoncePrefixSize  = 7
+	gcmStreamHeaderSize       = len(gcmStreamMagic) + 1 + 4 + gcmStreamNoncePrefixSize
+	gcmStreamDefaultChunkSize = 64 * 1024        // 默认分块大小 64KB
+	gcmStreamMaxChunkSize     = 16 * 1024 * 1024 // 分块大小上限 16MB, 避免解密时被恶意 header 分配过大内存
+)
+
+var (
+	// ErrGCMStreamHeader 流式密文 header 无效
+	ErrGCMStreamHeader = errors.New("invalid gcm stream header")
+
+	// ErrGCMStreamTruncated 流式密文被截断, 缺少最后一块
+	ErrGCMStreamTruncated = errors.New("gcm stream truncated")
+
+	// ErrGCMStreamTrailingData 流式密文最后一块之后还有多余数据
+	ErrGCMStreamTrailingData = errors.New("gcm stream has trailing data")
+)
+
+// GCMStreamOption 流式加密可选参数
+type GCMStreamOption func(*gcmStreamConfig)
+
+// gcmStreamConfig 流式加密配置
+type gcmStreamConfig struct {
+	chunkSize int
+}
+
+// WithGCMChunkSize 设置分块大小, 默认 64KB, 取值范围 [1, 16MB], 超出范围时使用默认值
+func WithGCMChunkSize(size int) GCMStreamOption {
+	return func(cfg *gcmStreamConfig) {
+		if size > 0 && size <= gcmStreamMaxChunkSize {
+			cfg.chunkSize = size
+		}
+	}
+}
+
+// GCMEncryptStream 使用 AES-GCM 流式加密 src 写入 dst, 内存占用只与分块大小有关
+//   - dst: 密文输出
+//   - src: 明文输入
+//   - key: AES 密钥, 长度为 16、24 或 32 字节
+//   - opts: 可选参数
+func GCMEncryptStream(dst io.Writer, src io.Reader, key []byte, opts ...GCMStreamOption) error {
+	cfg := &gcmStreamConfig{chunkSize: gcmStreamDefaultChunkSize}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, gcmStreamHeaderSize)
+	copy(header, gcmStreamMagic)
+	header[len(gcmStreamMagic)] = gcmStreamVersion
+	binary.BigEndian.PutUint32(header[len(gcmStreamMagic)+1:], uint32(cfg.chunkSize))
+
+	if _, err = rand.Read(header[gcmStreamHeaderSize-gcmStreamNoncePrefixSize:]); err != nil {
+		return fmt.Errorf("generate nonce prefix: %w", err)
+	}
+
+	if _, err = dst.Write(header); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+
+	stream := newGCMStream(aead, header)
+	buf := make([]byte, cfg.chunkSize, cfg.chunkSize+aead.Overhead())
+
+	for {
+		n, errR := io.ReadFull(src, buf[:cfg.chunkSize])
+
+		// 读满一块说明后面可能还有数据, 读不满则为最后一块
+		last := errors.Is(errR, io.EOF) || errors.Is(errR, io.ErrUnexpectedEOF)
+		if errR != nil && !last {
+			return fmt.Errorf("read plaintext: %w", errR)
+		}
+
+		index := stream.counter
+
+		nonce, errN := stream.nextNonce(last)
+		if errN != nil {
+			return errN
+		}
+
+		if _, err = dst.Write(aead.Seal(buf[:0], nonce, buf[:n], header)); err != nil {
+			return fmt.Errorf("write chunk %d: %w", index, err)
+		}
+
+		if last {
+			return nil
+		}
+	}
+}
+
+// GCMDecryptStream 解密 GCMEncryptStream 生成的密文 src 写入 dst, 分块大小从 header 中读取.
+// 每块认证通过后才写入 dst, 但返回错误时 dst 中可能已写入部分明文, 调用方应丢弃这部分数据.
+// 最后一块之后 src 中还有数据时返回 ErrGCMStreamTrailingData, 避免追加的数据被当作已认证的内容.
+//   - dst: 明文输出
+//   - src: 密文输入
+//   - key: AES 密钥, 与加密时相同
+func GCMDecryptStream(dst io.Writer, src io.Reader, key []byte) error {
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, gcmStreamHeaderSize)
+	if _, err = io.ReadFull(src, header); err != nil {
+		return fmt.Errorf("%w: %w", ErrGCMStreamHeader, err)
+	}
+
+	if string(header[:len(gcmStreamMagic)]) != gcmStreamMagic || header[len(gcmStreamMagic)] != gcmStreamVersion {
+		return ErrGCMStreamHeader
+	}
+
+	chunkSize := int(binary.BigEndian.Uint32(header[len(gcmStreamMagic)+1:]))
+	if chunkSize <= 0 || chunkSize > gcmStreamMaxChunkSize {
+		return fmt.Errorf("%w: chunk size %d out of range", ErrGCMStreamHeader, chunkSize)
+	}
+
+	stream := newGCMStream(aead, header)
+	buf := make([]byte, chunkSize+aead.Overhead())
+
+	for {
+		n, errR := io.ReadFull(src, buf)
+
+		switch {
+		case errors.Is(errR, io.EOF):
+			// 上一块不是最后一块, 但已没有数据
+			return ErrGCMStreamTruncated
+		case errR != nil && !errors.Is(errR, io.ErrUnexpectedEOF):
+			return fmt.Errorf("read chunk %d: %w", stream.counter, errR)
+		}
+
+		// 只有最后一块的长度小于完整分块
+		last := n < len(buf)
+
+		index := stream.counter
+
+		nonce, errN := stream.nextNonce(last)
+		if errN != nil {
+			return errN
+		}
+
+		plaintext, errO := aead.Open(buf[:0], nonce, buf[:n], header)
+		if errO != nil {
+			return fmt.Errorf("decrypt chunk %d: %w", index, errO)
+		}
+
+		if _, err = dst.Write(plaintext); err != nil {
+			return fmt.Errorf("write plaintext: %w", err)
+		}
+
+		if last {
+			return checkGCMStreamEnd(src)
+		}
+	}
+}
+
+// checkGCMStreamEnd 检查最后一块之后 src 是否已读完.
+// 部分 reader 在返回 io.EOF 后仍可能返回数据, 因此不能只依赖最后一块读不满来判断.
+func checkGCMStreamEnd(src io.Reader) error {
+	var b [1]byte
+
+	for {
+		n, err := src.Read(b[:])
+		if n > 0 {
+			return ErrGCMStreamTrailingData
+		}
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return fmt.Errorf("read after last chunk: %w", err)
+		}
+	}
+}
+
+// newGCM 使用 key 创建 AES-GCM
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create aes cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// gcmStream 生成每块的 nonce
+type gcmStream struct {
+	nonce   []byte
+	counter uint32
+	done    bool
+}
+
+// newGCMStream 使用 header 中的 nonce 前缀创建 gcmStream
+func newGCMStream(aead cipher.AEAD, header []byte) *gcmStream {
+	nonce := make([]byte, aead.NonceSize())
+	copy(nonce, header[gcmStreamHeaderSize-gcmStreamNoncePrefixSize:])
+
+	return &gcmStream{nonce: nonce}
+}
+
+// nextNonce 返回下一块的 nonce, 分块数量超出 uint32 范围时返回错误, 避免 nonce 重复
+func (s *gcmStream) nextNonce(last bool) ([]byte, error) {
+	if s.done {
+		return nil, errors.New("gcm stream chunk counter overflow")
+	}
+
+	binary.BigEndian.PutUint32(s.nonce[gcmStreamNoncePrefixSize:], s.counter)
+
+	s.nonce[len(s.nonce)-1] = 0
+	if last {
+		s.nonce[len(s.nonce)-1] = 1
+	}
+
+	if s.counter == math.MaxUint32 {
+		s.done = true
+	} else {
+		s.counter++
+	}
+
+	return s.nonce, nil
+}
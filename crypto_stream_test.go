@@ -0,0 +1,174 @@
+//
+// FilePath    : go-utils\crypto_stream_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 流式 AES-GCM 加密解密测试
+//
+
+package utils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestGCMStream_RoundTrip(t *testing.T) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+
+	tests := []struct {
+		name      string
+		size      int
+		chunkSize int
+	}{
+		{"空数据", 0, 16},
+		{"小于一块", 10, 16},
+		{"恰好整数块", 64, 16},
+		{"多块有余数", 100, 16},
+		{"默认分块", 200 * 1024, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plaintext := make([]byte, tt.size)
+			_, _ = rand.Read(plaintext)
+
+			var opts []GCMStreamOption
+			if tt.chunkSize > 0 {
+				opts = append(opts, WithGCMChunkSize(tt.chunkSize))
+			}
+
+			var encrypted bytes.Buffer
+			if err := GCMEncryptStream(&encrypted, bytes.NewReader(plaintext), key, opts...); err != nil {
+				t.Fatalf("GCMEncryptStream() error = %v", err)
+			}
+
+			var decrypted bytes.Buffer
+			if err := GCMDecryptStream(&decrypted, &encrypted, key); err != nil {
+				t.Fatalf("GCMDecryptStream() error = %v", err)
+			}
+
+			if !bytes.Equal(decrypted.Bytes(), plaintext) {
+				t.Errorf("decrypted data mismatch, got %d bytes, want %d bytes", decrypted.Len(), len(plaintext))
+			}
+		})
+	}
+}
+
+func TestGCMStream_Tampered(t *testing.T) {
+	key := make([]byte, 16)
+	_, _ = rand.Read(key)
+
+	plaintext := bytes.Repeat([]byte("0123456789"), 10)
+
+	var buf bytes.Buffer
+	if err := GCMEncryptStream(&buf, bytes.NewReader(plaintext), key, WithGCMChunkSize(16)); err != nil {
+		t.Fatalf("GCMEncryptStream() error = %v", err)
+	}
+
+	encrypted := buf.Bytes()
+	record := 16 + 16 // 分块明文 + 认证标签
+	chunk := func(i int) []byte {
+		start := gcmStreamHeaderSize + i*record
+		return encrypted[start : start+record]
+	}
+
+	// 交换第 0 块和第 1 块
+	swapped := bytes.Clone(encrypted[:gcmStreamHeaderSize])
+	swapped = append(swapped, chunk(1)...)
+	swapped = append(swapped, chunk(0)...)
+	swapped = append(swapped, encrypted[gcmStreamHeaderSize+2*record:]...)
+
+	flipped := bytes.Clone(encrypted)
+	flipped[gcmStreamHeaderSize+3] ^= 1
+
+	wrongKey := make([]byte, 16)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		key     []byte
+		wantErr error
+	}{
+		{"修改密文", flipped, key, nil},
+		{"分块重排", swapped, key, nil},
+		{"截断在分块边界", encrypted[:gcmStreamHeaderSize+2*record], key, ErrGCMStreamTruncated},
+		{"截断在分块中间", encrypted[:gcmStreamHeaderSize+2*record+5], key, nil},
+		{"错误密钥", encrypted, wrongKey, nil},
+		{"末尾追加数据", append(bytes.Clone(encrypted), "garbage"...), key, nil},
+		{"header 无效", []byte("not a gcm stream"), key, ErrGCMStreamHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := GCMDecryptStream(io.Discard, bytes.NewReader(tt.data), tt.key)
+			if err == nil {
+				t.Fatal("GCMDecryptStream() expected error, got nil")
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("GCMDecryptStream() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// eofThenReader 依次读取 parts, 每个 part 读完后返回一次 io.EOF, 模拟读到 EOF 后仍有新数据的 reader
+type eofThenReader struct {
+	parts [][]byte
+}
+
+func (r *eofThenReader) Read(p []byte) (int, error) {
+	if len(r.parts) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(p, r.parts[0])
+	if r.parts[0] = r.parts[0][n:]; len(r.parts[0]) == 0 {
+		r.parts = r.parts[1:]
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+func TestGCMStream_TrailingData(t *testing.T) {
+	key := make([]byte, 16)
+	_, _ = rand.Read(key)
+
+	var buf bytes.Buffer
+	if err := GCMEncryptStream(&buf, bytes.NewReader([]byte("backup")), key); err != nil {
+		t.Fatalf("GCMEncryptStream() error = %v", err)
+	}
+
+	src := &eofThenReader{parts: [][]byte{buf.Bytes(), []byte("garbage")}}
+
+	err := GCMDecryptStream(io.Discard, src, key)
+	if !errors.Is(err, ErrGCMStreamTrailingData) {
+		t.Errorf("GCMDecryptStream() error = %v, want %v", err, ErrGCMStreamTrailingData)
+	}
+
+	// 没有多余数据时正常结束
+	src = &eofThenReader{parts: [][]byte{buf.Bytes()}}
+	if err = GCMDecryptStream(io.Discard, src, key); err != nil {
+		t.Errorf("GCMDecryptStream() error = %v", err)
+	}
+}
+
+func BenchmarkGCMEncryptStream(b *testing.B) {
+	key := make([]byte, 32)
+	plaintext := make([]byte, 8*1024*1024)
+
+	b.SetBytes(int64(len(plaintext)))
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if err := GCMEncryptStream(io.Discard, bytes.NewReader(plaintext), key); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
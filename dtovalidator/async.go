@@ -0,0 +1,229 @@
+//
+// FilePath    : go-utils\dtovalidator\async.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 异步校验器, 查询数据库等外部状态, 例如用户名未被占用、订单未退款
+//
+
+package dtovalidator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/go-utils"
+)
+
+// asyncTagName 异步校验器使用的 struct tag, 例如 `async:"UsernameAvailable"`, 多个校验器使用逗号分隔
+const asyncTagName = "async"
+
+// AsyncLookupFunc 异步校验函数, 查询外部状态判断 value 是否合法
+//   - ctx: 上下文, 超时或请求取消时结束
+//   - value: 字段值(已解引用指针)
+//
+// 返回值为是否合法; 查询失败(数据库不可用等)时返回错误, 不视为校验失败
+type AsyncLookupFunc func(ctx context.Context, value any) (bool, error)
+
+// AsyncValidatorEntry 异步校验器明细
+type AsyncValidatorEntry struct {
+	Lookup  AsyncLookupFunc // 校验函数
+	ErrMsg  string          // 错误信息, 支持 {0} 占位字段名
+	Timeout time.Duration   // 单次校验超时时间, 为 0 时使用 SetAsyncTimeout 设置的默认值
+}
+
+// AsyncEntryMap 是一个映射, 其中键是异步校验器的名称(async tag 中的值), 值是 AsyncValidatorEntry 结构体
+var AsyncEntryMap = make(map[string]AsyncValidatorEntry)
+
+// asyncTimeout 异步校验默认超时时间
+var asyncTimeout = 3 * time.Second
+
+// asyncConcurrency 单次 ValidateAsync 最多同时执行的异步校验数量
+var asyncConcurrency = 8
+
+// RegisterAsyncValidator 添加新的异步校验器到 AsyncEntryMap 中
+//   - name: 校验器名称, 字段通过 `async:"name"` 使用
+//   - entry: 校验器明细
+func RegisterAsyncValidator(name string, entry AsyncValidatorEntry) {
+	AsyncEntryMap[name] = entry
+}
+
+// SetAsyncTimeout 设置异步校验默认超时时间
+func SetAsyncTimeout(d time.Duration) {
+	asyncTimeout = d
+}
+
+// SetAsyncConcurrency 设置单次 ValidateAsync 最多同时执行的异步校验数量, 默认 8, 小于 1 时使用 1
+func SetAsyncConcurrency(n int) {
+	asyncConcurrency = n
+}
+
+// AsyncValidationErrors 异步校验失败的字段错误
+type AsyncValidationErrors []FieldError
+
+// Error 实现 error 接口 Error 方法
+func (e AsyncValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Field+": "+fe.Msg)
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// asyncCheck 单个字段的异步校验
+type asyncCheck struct {
+	field string // 字段路径
+	name  string // 字段名, 用于错误信息
+	tag   string // 校验器名称
+	value any    // 字段值
+}
+
+// ValidateAsync 执行 obj 中 async tag 声明的异步校验, 最多 SetAsyncConcurrency 个校验并发执行.
+// 零值字段不校验(由 required 等字段校验器负责), 嵌套结构体会递归校验.
+// 校验失败时返回 AsyncValidationErrors, 校验器未注册或查询失败时返回普通错误;
+// 校验函数 panic 时转换为包含 *utils.PanicError 的错误, 不会导致进程崩溃.
+//   - ctx: 上下文
+//   - obj: 结构体或结构体指针
+func ValidateAsync(ctx context.Context, obj any) error {
+	v := indirect(reflect.ValueOf(obj))
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	checks, err := collectAsyncChecks(v, "")
+	if err != nil || len(checks) == 0 {
+		return err
+	}
+
+	// 结果与 checks 顺序一致, 按字段声明顺序返回; 查询失败时取消其余校验并返回首个错误
+	results, err := utils.ParallelMap(ctx, checks, runAsyncCheck, asyncConcurrency)
+	if err != nil {
+		return err
+	}
+
+	var failures AsyncValidationErrors
+
+	for _, fe := range results {
+		if fe != nil {
+			failures = append(failures, *fe)
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return failures
+}
+
+// runAsyncCheck 执行单个异步校验, 校验失败时返回字段错误, 查询失败或 panic 时返回错误
+func runAsyncCheck(ctx context.Context, check asyncCheck) (fe *FieldError, err error) {
+	// 校验函数由调用方提供, panic 时转换为错误, 避免在 gin Recovery 无法捕获的协程中崩溃
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("异步校验 %s 字段 %s 失败: %w", check.tag, check.field, &utils.PanicError{Value: r, Stack: debug.Stack()})
+		}
+	}()
+
+	entry := AsyncEntryMap[check.tag]
+
+	timeout := entry.Timeout
+	if timeout <= 0 {
+		timeout = asyncTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ok, err := entry.Lookup(ctx, check.value)
+	if err != nil {
+		return nil, fmt.Errorf("异步校验 %s 字段 %s 失败: %w", check.tag, check.field, err)
+	}
+
+	if ok {
+		return nil, nil
+	}
+
+	return &FieldError{
+		Field: check.field,
+		Tag:   check.tag,
+		Msg:   strings.ReplaceAll(entry.ErrMsg, "{0}", check.name),
+	}, nil
+}
+
+// collectAsyncChecks 收集结构体中需要异步校验的字段
+//   - v: 结构体值
+//   - prefix: 字段路径前缀, 例如 address.
+func collectAsyncChecks(v reflect.Value, prefix string) ([]asyncCheck, error) {
+	var checks []asyncCheck
+
+	t := v.Type()
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		field := indirect(v.Field(i))
+		if !field.IsValid() || field.IsZero() {
+			continue
+		}
+
+		name := JSONTagName(sf)
+		if name == "" {
+			name = sf.Name
+		}
+
+		// 嵌套结构体递归校验, 匿名嵌入的字段不增加路径
+		if field.Kind() == reflect.Struct && field.Type() != timeType {
+			nestedPrefix := prefix + name + "."
+			if sf.Anonymous {
+				nestedPrefix = prefix
+			}
+
+			nested, err := collectAsyncChecks(field, nestedPrefix)
+			if err != nil {
+				return nil, err
+			}
+
+			checks = append(checks, nested...)
+		}
+
+		tags := sf.Tag.Get(asyncTagName)
+		if tags == "" {
+			continue
+		}
+
+		for tag := range strings.SplitSeq(tags, ",") {
+			tag = strings.TrimSpace(tag)
+
+			entry, ok := AsyncEntryMap[tag]
+			if !ok || entry.Lookup == nil {
+				return nil, fmt.Errorf("异步校验器 %s 未注册", tag)
+			}
+
+			checks = append(checks, asyncCheck{field: prefix + name, name: name, tag: tag, value: field.Interface()})
+		}
+	}
+
+	return checks, nil
+}
+
+// BindAndValidate 绑定请求参数并校验, 先执行 gin 绑定及字段校验, 通过后执行异步校验, 使用请求的上下文.
+// 返回的错误可使用 TranslateErrors 转换为字段错误列表; 注意异步校验查询失败时返回的是普通错误, 应作为服务端错误处理.
+//   - c: gin 上下文
+//   - obj: 结构体指针
+func BindAndValidate(c *gin.Context, obj any) error {
+	if err := c.ShouldBind(obj); err != nil {
+		return err
+	}
+
+	return ValidateAsync(c.Request.Context(), obj)
+}
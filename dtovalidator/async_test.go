@@ -0,0 +1,208 @@
+//
+// FilePath    : go-utils\dtovalidator\async_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 异步校验器测试
+//
+
+package dtovalidator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/go-utils"
+)
+
+type asyncAddress struct {
+	City string `json:"city" async:"testCityServed"`
+}
+
+type asyncUserDTO struct {
+	Username string        `json:"username" binding:"required" async:"testUsernameFree"`
+	Nickname *string       `json:"nickname" async:"testUsernameFree"`
+	Address  *asyncAddress `json:"address"`
+}
+
+func TestValidateAsync(t *testing.T) {
+	taken := map[string]bool{"admin": true, "root": true}
+
+	RegisterAsyncValidator("testUsernameFree", AsyncValidatorEntry{
+		Lookup: func(_ context.Context, value any) (bool, error) {
+			return !taken[value.(string)], nil
+		},
+		ErrMsg: "{0} 已被占用",
+	})
+	RegisterAsyncValidator("testCityServed", AsyncValidatorEntry{
+		Lookup: func(ctx context.Context, value any) (bool, error) {
+			if value == "slow" {
+				<-ctx.Done()
+				return false, ctx.Err()
+			}
+
+			return value == "shanghai", nil
+		},
+		ErrMsg:  "暂不支持该城市",
+		Timeout: 10 * time.Millisecond,
+	})
+
+	defer delete(AsyncEntryMap, "testUsernameFree")
+	defer delete(AsyncEntryMap, "testCityServed")
+
+	nickname := "root"
+
+	err := ValidateAsync(context.Background(), &asyncUserDTO{
+		Username: "admin",
+		Nickname: &nickname,
+		Address:  &asyncAddress{City: "beijing"},
+	})
+
+	want := []FieldError{
+		{Field: "username", Tag: "testUsernameFree", Msg: "username 已被占用"},
+		{Field: "nickname", Tag: "testUsernameFree", Msg: "nickname 已被占用"},
+		{Field: "address.city", Tag: "testCityServed", Msg: "暂不支持该城市"},
+	}
+
+	got := TranslateErrors(err)
+	if len(got) != len(want) {
+		t.Fatalf("TranslateErrors() = %+v, want %+v", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("TranslateErrors()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// 零值字段不校验
+	if err := ValidateAsync(context.Background(), asyncUserDTO{Username: "alice"}); err != nil {
+		t.Errorf("ValidateAsync() error = %v, want nil", err)
+	}
+
+	// 查询超时返回普通错误
+	err = ValidateAsync(context.Background(), &asyncUserDTO{Username: "alice", Address: &asyncAddress{City: "slow"}})
+
+	var asyncErrs AsyncValidationErrors
+	if !errors.Is(err, context.DeadlineExceeded) || errors.As(err, &asyncErrs) {
+		t.Errorf("ValidateAsync() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	// 未注册的校验器
+	type unknownDTO struct {
+		Name string `async:"testNotRegistered"`
+	}
+
+	if err := ValidateAsync(context.Background(), &unknownDTO{Name: "x"}); err == nil {
+		t.Error("ValidateAsync() error = nil, want unregistered error")
+	}
+}
+
+func TestValidateAsync_PanicAndConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+
+	RegisterAsyncValidator("testPanic", AsyncValidatorEntry{
+		Lookup: func(context.Context, any) (bool, error) { panic("lookup boom") },
+	})
+	RegisterAsyncValidator("testCounted", AsyncValidatorEntry{
+		Lookup: func(context.Context, any) (bool, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+
+			// 记录最大并发数
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+
+			time.Sleep(5 * time.Millisecond)
+
+			return true, nil
+		},
+	})
+
+	defer delete(AsyncEntryMap, "testPanic")
+	defer delete(AsyncEntryMap, "testCounted")
+
+	type panicDTO struct {
+		Name string `async:"testPanic"`
+	}
+
+	err := ValidateAsync(context.Background(), &panicDTO{Name: "x"})
+
+	var panicErr *utils.PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "lookup boom" {
+		t.Fatalf("ValidateAsync() error = %v, want PanicError", err)
+	}
+
+	type countedDTO struct {
+		A, B, C, D, E, F string `async:"testCounted"`
+	}
+
+	SetAsyncConcurrency(2)
+	defer SetAsyncConcurrency(8)
+
+	if err = ValidateAsync(context.Background(), &countedDTO{"a", "b", "c", "d", "e", "f"}); err != nil {
+		t.Fatalf("ValidateAsync() error = %v", err)
+	}
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", got)
+	}
+}
+
+func TestBindAndValidate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	RegisterAsyncValidator("testUsernameFree", AsyncValidatorEntry{
+		Lookup: func(_ context.Context, value any) (bool, error) {
+			return value != "admin", nil
+		},
+		ErrMsg: "{0} 已被占用",
+	})
+
+	defer delete(AsyncEntryMap, "testUsernameFree")
+
+	tests := []struct {
+		name    string
+		body    string
+		wantTag string
+	}{
+		{name: "valid", body: `{"username":"alice"}`},
+		{name: "binding failed", body: `{}`, wantTag: "required"},
+		{name: "async failed", body: `{"username":"admin"}`, wantTag: "testUsernameFree"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			var dto asyncUserDTO
+
+			err := BindAndValidate(c, &dto)
+			if tt.wantTag == "" {
+				if err != nil {
+					t.Fatalf("BindAndValidate() error = %v", err)
+				}
+
+				return
+			}
+
+			got := TranslateErrors(err)
+			if len(got) != 1 || got[0].Tag != tt.wantTag {
+				t.Errorf("TranslateErrors() = %+v, want tag %s", got, tt.wantTag)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+//
+// FilePath    : go-utils\dtovalidator\format.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 格式相关校验器, URL slug、语义化版本号、密码强度
+//
+
+package dtovalidator
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// PasswordPolicy 密码强度策略
+type PasswordPolicy struct {
+	MinLength      int  // 最小长度(字符数)
+	MaxLength      int  // 最大长度(字符数), 为 0 时不限制
+	RequireUpper   bool // 是否需要大写字母
+	RequireLower   bool // 是否需要小写字母
+	RequireDigit   bool // 是否需要数字
+	RequireSpecial bool // 是否需要特殊字符(非字母数字)
+}
+
+// passwordPolicy 全局密码强度策略, 默认 8-64 位且包含大小写字母、数字和特殊字符
+var passwordPolicy = PasswordPolicy{
+	MinLength:      8,
+	MaxLength:      64,
+	RequireUpper:   true,
+	RequireLower:   true,
+	RequireDigit:   true,
+	RequireSpecial: true,
+}
+
+// SetPasswordPolicy 设置 ValidatePassword 使用的全局密码强度策略, 修改后建议同步通过 SetErrMsg 修改错误信息
+func SetPasswordPolicy(policy PasswordPolicy) {
+	passwordPolicy = policy
+}
+
+// init 初始化注册校验器
+func init() {
+	RegisterValidator("ValidateSlug", ValidatorEntry{
+		ValidatorFunc: ValidateSlug,
+		ErrMsg:        "只能包含小写字母、数字和中划线, 且不能以中划线开头或结尾.",
+	})
+
+	RegisterValidator("ValidateSemver", ValidatorEntry{
+		ValidatorFunc: ValidateSemver,
+		ErrMsg:        "请输入正确的版本号, 例如 1.2.3.",
+	})
+
+	RegisterValidator("ValidatePassword", ValidatorEntry{
+		ValidatorFunc: ValidatePassword,
+		ErrMsg:        "密码需要 8-64 位, 且包含大小写字母、数字和特殊字符.",
+	})
+}
+
+// slugRegexp URL slug, 小写字母、数字, 使用单个中划线分隔
+var slugRegexp = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// ValidateSlug 校验 URL slug, 例如 hello-world
+func ValidateSlug(fl validator.FieldLevel) bool {
+	return IsSlug(fl.Field().String())
+}
+
+// IsSlug 判断 s 是否为 URL slug, 只包含小写字母、数字和单个中划线, 长度不超过 255
+func IsSlug(s string) bool {
+	return len(s) <= 255 && slugRegexp.MatchString(s)
+}
+
+// semverRegexp 语义化版本号 2.0.0, 参见 https://semver.org
+var semverRegexp = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// ValidateSemver 校验语义化版本号, 例如 1.2.3、1.0.0-beta.1, 不允许 v 前缀
+func ValidateSemver(fl validator.FieldLevel) bool {
+	return IsSemver(fl.Field().String())
+}
+
+// IsSemver 判断 s 是否为语义化版本号
+func IsSemver(s string) bool {
+	return semverRegexp.MatchString(s)
+}
+
+// ValidatePassword 使用全局密码强度策略校验密码
+func ValidatePassword(fl validator.FieldLevel) bool {
+	return IsStrongPassword(fl.Field().String(), &passwordPolicy)
+}
+
+// IsStrongPassword 判断 s 是否满足密码强度策略, 不允许空白字符
+//   - s: 密码
+//   - policy: 密码强度策略
+func IsStrongPassword(s string, policy *PasswordPolicy) bool {
+	length := len([]rune(s))
+	if length < policy.MinLength || (policy.MaxLength > 0 && length > policy.MaxLength) {
+		return false
+	}
+
+	if strings.IndexFunc(s, unicode.IsSpace) >= 0 {
+		return false
+	}
+
+	var upper, lower, digit, special bool
+
+	for _, r := range s {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			special = true
+		}
+	}
+
+	return (!policy.RequireUpper || upper) &&
+		(!policy.RequireLower || lower) &&
+		(!policy.RequireDigit || digit) &&
+		(!policy.RequireSpecial || special)
+}
@@ -0,0 +1,98 @@
+//
+// FilePath    : go-utils\dtovalidator\format_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 格式相关校验器测试
+//
+
+package dtovalidator
+
+import "testing"
+
+func TestIsSlug(t *testing.T) {
+	cases := []struct {
+		s    string
+		want bool
+	}{
+		{"hello-world", true},
+		{"go-utils-2026", true},
+		{"hello", true},
+		{"Hello-World", false},
+		{"-hello", false},
+		{"hello-", false},
+		{"hello--world", false},
+		{"hello_world", false},
+		{"", false},
+	}
+
+	for _, c := range cases {
+		if got := IsSlug(c.s); got != c.want {
+			t.Fatalf("IsSlug(%q) = %v; want %v", c.s, got, c.want)
+		}
+	}
+}
+
+func TestIsSemver(t *testing.T) {
+	cases := []struct {
+		s    string
+		want bool
+	}{
+		{"1.2.3", true},
+		{"0.0.1", true},
+		{"1.0.0-beta.1", true},
+		{"1.0.0-rc.1+build.20260101", true},
+		{"v1.2.3", false},
+		{"1.2", false},
+		{"01.2.3", false},
+		{"1.0.0-01", false},
+	}
+
+	for _, c := range cases {
+		if got := IsSemver(c.s); got != c.want {
+			t.Fatalf("IsSemver(%q) = %v; want %v", c.s, got, c.want)
+		}
+	}
+}
+
+func TestIsStrongPassword(t *testing.T) {
+	cases := []struct {
+		s      string
+		policy PasswordPolicy
+		want   bool
+	}{
+		{"Abcdef1!", passwordPolicy, true},
+		{"abcdef1!", passwordPolicy, false},
+		{"ABCDEF1!", passwordPolicy, false},
+		{"Abcdefg!", passwordPolicy, false},
+		{"Abcdefg1", passwordPolicy, false},
+		{"Abc1!", passwordPolicy, false},
+		{"Abcd ef1!", passwordPolicy, false},
+		{"abcdef", PasswordPolicy{MinLength: 6}, true},
+		{"abcdefg", PasswordPolicy{MinLength: 6, MaxLength: 6}, false},
+		{"密码Abc123", PasswordPolicy{MinLength: 8, RequireDigit: true}, true},
+	}
+
+	for _, c := range cases {
+		if got := IsStrongPassword(c.s, &c.policy); got != c.want {
+			t.Fatalf("IsStrongPassword(%q, %+v) = %v; want %v", c.s, c.policy, got, c.want)
+		}
+	}
+}
+
+func TestSetErrMsg(t *testing.T) {
+	old := EntryMap["ValidateMobile"].ErrMsg
+	defer SetErrMsg("ValidateMobile", old)
+
+	if !SetErrMsg("ValidateMobile", "手机号格式错误") {
+		t.Fatalf("SetErrMsg returned false for registered validator")
+	}
+
+	if got := EntryMap["ValidateMobile"].ErrMsg; got != "手机号格式错误" {
+		t.Fatalf("ErrMsg = %q; want %q", got, "手机号格式错误")
+	}
+
+	if SetErrMsg("NotExists", "x") {
+		t.Fatalf("SetErrMsg returned true for unknown validator")
+	}
+}
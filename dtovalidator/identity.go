@@ -0,0 +1,147 @@
+//
+// FilePath    : go-utils\dtovalidator\identity.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 身份相关校验器, 大陆手机号、身份证号、银行卡号
+//
+
+package dtovalidator
+
+import (
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// init 初始化注册校验器
+func init() {
+	RegisterValidator("ValidateMobile", ValidatorEntry{
+		ValidatorFunc: ValidateMobile,
+		ErrMsg:        "请输入正确的手机号.",
+	})
+
+	RegisterValidator("ValidateIDCard", ValidatorEntry{
+		ValidatorFunc: ValidateIDCard,
+		ErrMsg:        "请输入正确的身份证号.",
+	})
+
+	RegisterValidator("ValidateBankCard", ValidatorEntry{
+		ValidatorFunc: ValidateBankCard,
+		ErrMsg:        "请输入正确的银行卡号.",
+	})
+}
+
+// mobileRegexp 大陆手机号, 1 开头第二位 3-9 的 11 位数字
+var mobileRegexp = regexp.MustCompile(`^1[3-9]\d{9}$`)
+
+// ValidateMobile 校验大陆手机号
+func ValidateMobile(fl validator.FieldLevel) bool {
+	return IsMobile(fl.Field().String())
+}
+
+// IsMobile 判断 s 是否为大陆手机号, 不支持 +86 等前缀
+func IsMobile(s string) bool {
+	return mobileRegexp.MatchString(s)
+}
+
+// ValidateIDCard 校验 18 位身份证号, 包括出生日期和校验位
+func ValidateIDCard(fl validator.FieldLevel) bool {
+	return IsIDCard(fl.Field().String())
+}
+
+// idCardWeights 身份证号前 17 位的加权因子
+var idCardWeights = [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+// idCardCheckCodes 身份证号校验码, 按加权和模 11 取值
+const idCardCheckCodes = "10X98765432"
+
+// IsIDCard 判断 s 是否为 18 位身份证号, 最后一位校验码 X 不区分大小写
+func IsIDCard(s string) bool {
+	if len(s) != 18 {
+		return false
+	}
+
+	sum := 0
+
+	for i := range 17 {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+
+		sum += int(s[i]-'0') * idCardWeights[i]
+	}
+
+	if !isBirthDate(s[6:14]) {
+		return false
+	}
+
+	last := s[17]
+	if last == 'x' {
+		last = 'X'
+	}
+
+	return idCardCheckCodes[sum%11] == last
+}
+
+// isBirthDate 判断 yyyymmdd 是否为合法日期, 年份 1800-2099
+func isBirthDate(s string) bool {
+	year := atoi(s[:4])
+	month := atoi(s[4:6])
+	day := atoi(s[6:])
+
+	if year < 1800 || year > 2099 || month < 1 || month > 12 || day < 1 {
+		return false
+	}
+
+	days := [12]int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+	if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+		days[1] = 29
+	}
+
+	return day <= days[month-1]
+}
+
+// atoi 将纯数字字符串转换为整数, 调用方需保证 s 只包含数字
+func atoi(s string) int {
+	n := 0
+	for i := range len(s) {
+		n = n*10 + int(s[i]-'0')
+	}
+
+	return n
+}
+
+// ValidateBankCard 校验银行卡号, 13-19 位数字且满足 Luhn 校验
+func ValidateBankCard(fl validator.FieldLevel) bool {
+	return IsBankCard(fl.Field().String())
+}
+
+// IsBankCard 判断 s 是否为银行卡号, 13-19 位数字且满足 Luhn 校验
+func IsBankCard(s string) bool {
+	if len(s) < 13 || len(s) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+
+		d := int(s[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
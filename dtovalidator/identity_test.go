@@ -0,0 +1,72 @@
+//
+// FilePath    : go-utils\dtovalidator\identity_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 身份相关校验器测试
+//
+
+package dtovalidator
+
+import "testing"
+
+func TestIsMobile(t *testing.T) {
+	cases := []struct {
+		s    string
+		want bool
+	}{
+		{"13800138000", true},
+		{"19912345678", true},
+		{"12800138000", false},
+		{"1380013800", false},
+		{"138001380001", false},
+		{"+8613800138000", false},
+		{"1380013800a", false},
+	}
+
+	for _, c := range cases {
+		if got := IsMobile(c.s); got != c.want {
+			t.Fatalf("IsMobile(%q) = %v; want %v", c.s, got, c.want)
+		}
+	}
+}
+
+func TestIsIDCard(t *testing.T) {
+	cases := []struct {
+		s    string
+		want bool
+	}{
+		{"11010519491231002X", true},
+		{"11010519491231002x", true},
+		{"110105194912310021", false}, // 校验位错误
+		{"11010519491331002X", false}, // 月份错误
+		{"11010519490230002X", false}, // 日期错误
+		{"1101051949123100", false},
+		{"11010519491231002Y", false},
+	}
+
+	for _, c := range cases {
+		if got := IsIDCard(c.s); got != c.want {
+			t.Fatalf("IsIDCard(%q) = %v; want %v", c.s, got, c.want)
+		}
+	}
+}
+
+func TestIsBankCard(t *testing.T) {
+	cases := []struct {
+		s    string
+		want bool
+	}{
+		{"4111111111111111", true},
+		{"6222021234567890120", false},
+		{"4111111111111112", false},
+		{"411111111111", false},
+		{"4111-1111-1111-1111", false},
+	}
+
+	for _, c := range cases {
+		if got := IsBankCard(c.s); got != c.want {
+			t.Fatalf("IsBankCard(%q) = %v; want %v", c.s, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+//
+// FilePath    : go-utils\dtovalidator\install.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 一次性将 EntryMap 中的校验器注册到 validator 或 gin 绑定引擎
+//
+
+package dtovalidator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// installed 记录已通过 InstallTo 注册到各个 validator 的标签, 重复安装时不视为冲突
+var (
+	installed   = make(map[*validator.Validate]map[string]struct{})
+	installedMu sync.Mutex
+)
+
+// InstallOption InstallToGin 选项
+type InstallOption func(*installConfig)
+
+// installConfig InstallToGin 配置
+type installConfig struct {
+	bindingValidator binding.StructValidator // 替换 gin 默认的绑定校验器
+}
+
+// WithBindingValidator 替换 gin 默认的绑定校验器(binding.Validator), 其 Engine 需要返回 *validator.Validate
+func WithBindingValidator(sv binding.StructValidator) InstallOption {
+	return func(cfg *installConfig) {
+		cfg.bindingValidator = sv
+	}
+}
+
+// InstallToGin 将 EntryMap 和 StructEntryMap 中的所有校验器注册到 gin 的绑定引擎, 返回所有注册失败的聚合错误
+func InstallToGin(opts ...InstallOption) error {
+	cfg := &installConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if cfg.bindingValidator != nil {
+		binding.Validator = cfg.bindingValidator
+	}
+
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("gin 绑定引擎不是 *validator.Validate: %T", binding.Validator.Engine())
+	}
+
+	return InstallTo(v)
+}
+
+// InstallTo 将 EntryMap 中的所有校验器及 StructEntryMap 中的结构体校验器注册到 v, 已初始化翻译器(Trans)时同时注册错误信息翻译.
+// 标签名与 v 中已有的校验器(内置或其他代码注册)重名时不会覆盖, 并在返回的聚合错误中报告.
+func InstallTo(v *validator.Validate) error {
+	installedMu.Lock()
+	defer installedMu.Unlock()
+
+	tags, ok := installed[v]
+	if !ok {
+		tags = make(map[string]struct{})
+		installed[v] = tags
+	}
+
+	// 按标签名排序, 保证错误信息稳定
+	names := make([]string, 0, len(EntryMap))
+	for name := range EntryMap {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var errs []error
+
+	for _, tag := range names {
+		entry := EntryMap[tag]
+
+		// 检查是否与已有的校验器重名
+		if _, self := tags[tag]; !self && tagRegistered(v, tag) {
+			errs = append(errs, fmt.Errorf("校验器 %s 与已注册的校验器重名", tag))
+			continue
+		}
+
+		if err := installEntry(v, tag, entry); err != nil {
+			errs = append(errs, fmt.Errorf("注册校验器 %s 失败: %w", tag, err))
+			continue
+		}
+
+		tags[tag] = struct{}{}
+	}
+
+	// 注册结构体级别校验器
+	structTags := make([]string, 0, len(StructEntryMap))
+	for tag := range StructEntryMap {
+		structTags = append(structTags, tag)
+	}
+
+	sort.Strings(structTags)
+
+	valid := structTags[:0]
+
+	for _, tag := range structTags {
+		if _, ok := EntryMap[tag]; ok {
+			errs = append(errs, fmt.Errorf("结构体校验器 %s 与字段校验器重名", tag))
+			continue
+		}
+
+		valid = append(valid, tag)
+	}
+
+	errs = append(errs, installStructEntries(v, valid)...)
+
+	return errors.Join(errs...)
+}
+
+// installEntry 注册单个校验器, 已初始化翻译器时同时注册错误信息翻译
+func installEntry(v *validator.Validate, tag string, entry ValidatorEntry) error {
+	if Trans != nil {
+		return registerValidatorFunc(v, tag, entry.ErrMsg, entry.ValidatorFunc)
+	}
+
+	return v.RegisterValidation(tag, validator.Func(entry.ValidatorFunc))
+}
+
+// tagRegistered 判断 v 中是否已存在标签 tag 的校验器.
+// validator 未提供查询接口, 使用未注册的标签校验时会 panic, 据此判断.
+func tagRegistered(v *validator.Validate, tag string) (registered bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg, _ := r.(string)
+			registered = !strings.HasPrefix(msg, "Undefined validation function")
+		}
+	}()
+
+	_ = v.Var("", tag)
+
+	return true
+}
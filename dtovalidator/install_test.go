@@ -0,0 +1,54 @@
+//
+// FilePath    : go-utils\dtovalidator\install_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 校验器注册测试
+//
+
+package dtovalidator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestInstallTo(t *testing.T) {
+	v := validator.New()
+
+	if err := InstallTo(v); err != nil {
+		t.Fatalf("InstallTo() error = %v", err)
+	}
+
+	// 重复安装不视为冲突
+	if err := InstallTo(v); err != nil {
+		t.Fatalf("InstallTo() again error = %v", err)
+	}
+
+	if err := v.Var(2024, "ValidateIntYear"); err != nil {
+		t.Errorf("ValidateIntYear not installed: %v", err)
+	}
+
+	// 与内置校验器重名
+	RegisterValidator("required", ValidatorEntry{ValidatorFunc: ValidateInt, ErrMsg: "x"})
+	defer delete(EntryMap, "required")
+
+	err := InstallTo(validator.New())
+	if err == nil || !strings.Contains(err.Error(), "required") {
+		t.Fatalf("InstallTo() error = %v, want duplicate required", err)
+	}
+}
+
+func TestTagRegistered(t *testing.T) {
+	v := validator.New()
+
+	if !tagRegistered(v, "max") {
+		t.Errorf("tagRegistered(max) = false, want true")
+	}
+
+	if tagRegistered(v, "NotExistsTag") {
+		t.Errorf("tagRegistered(NotExistsTag) = true, want false")
+	}
+}
@@ -33,3 +33,18 @@ var EntryMap = make(map[string]ValidatorEntry)
 func RegisterValidator(name string, entry ValidatorEntry) {
 	EntryMap[name] = entry
 }
+
+// SetErrMsg 修改已注册验证器的错误信息, 需要在 InstallTo 之前调用, 验证器不存在时返回 false
+//   - name: 验证器名称
+//   - msg: 错误信息
+func SetErrMsg(name, msg string) bool {
+	entry, ok := EntryMap[name]
+	if !ok {
+		return false
+	}
+
+	entry.ErrMsg = msg
+	EntryMap[name] = entry
+
+	return true
+}
@@ -0,0 +1,274 @@
+//
+// FilePath    : go-utils\dtovalidator\network.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 网络相关校验器, URL、域名、IP
+//
+
+package dtovalidator
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// URLPolicy URL 校验策略, 用于 webhook、回调地址等需要防止 SSRF 的场景
+type URLPolicy struct {
+	Schemes      []string // 允许的协议, 为空时允许 http 和 https
+	Hosts        []string // 允许的主机, 支持 "*.example.com" 通配子域名, 为空时不限制
+	AllowPrivate bool     // 是否允许内网地址(回环、私有、链路本地等), 默认不允许
+	ResolveHost  bool     // 是否解析域名并校验解析结果是否为内网地址, 零值不解析, 全局策略默认解析
+}
+
+// DefaultURLPolicy 返回 ValidateHTTPURL 默认使用的全局策略: 只允许 http 和 https, 不允许内网地址, 并解析域名校验解析结果,
+// 避免 127.0.0.1.nip.io 这类解析到内网地址的域名绕过校验.
+//
+// 校验时的解析结果不能防止 DNS 重绑定, 发起请求时仍需在连接阶段校验实际连接的地址.
+func DefaultURLPolicy() URLPolicy {
+	return URLPolicy{ResolveHost: true}
+}
+
+// urlPolicy 全局 URL 校验策略
+var urlPolicy = DefaultURLPolicy()
+
+// lookupIP 域名解析函数, 便于测试替换
+var lookupIP = net.LookupIP
+
+// SetURLPolicy 设置 ValidateHTTPURL 使用的全局 URL 校验策略, 建议在 DefaultURLPolicy 的基础上修改,
+// 关闭 ResolveHost 后不再校验域名的解析结果, 只能拒绝内网 IP 字面量和本地域名
+func SetURLPolicy(policy URLPolicy) {
+	urlPolicy = policy
+}
+
+// init 初始化注册校验器
+func init() {
+	RegisterValidator("ValidateHTTPURL", ValidatorEntry{
+		ValidatorFunc: ValidateHTTPURL,
+		ErrMsg:        "请输入正确的 http(s) 地址, 且不能为内网地址.",
+	})
+
+	RegisterValidator("ValidateDomain", ValidatorEntry{
+		ValidatorFunc: ValidateDomain,
+		ErrMsg:        "请输入正确的域名.",
+	})
+
+	RegisterValidator("ValidateIP", ValidatorEntry{
+		ValidatorFunc: ValidateIP,
+		ErrMsg:        "请输入正确的 IP 地址.",
+	})
+
+	RegisterValidator("ValidateIPv4", ValidatorEntry{
+		ValidatorFunc: ValidateIPv4,
+		ErrMsg:        "请输入正确的 IPv4 地址.",
+	})
+
+	RegisterValidator("ValidateIPv6", ValidatorEntry{
+		ValidatorFunc: ValidateIPv6,
+		ErrMsg:        "请输入正确的 IPv6 地址.",
+	})
+}
+
+// ValidateHTTPURL 使用全局 URL 校验策略校验 http(s) 地址
+func ValidateHTTPURL(fl validator.FieldLevel) bool {
+	return ValidateURLWithPolicy(fl, &urlPolicy)
+}
+
+// ValidateURLWithPolicy 通用的 URL 校验函数, 可用于组合自定义校验器
+//   - fl: 字段
+//   - policy: 校验策略
+func ValidateURLWithPolicy(fl validator.FieldLevel, policy *URLPolicy) bool {
+	return IsAllowedURL(fl.Field().String(), policy)
+}
+
+// IsAllowedURL 判断 rawURL 是否满足校验策略
+//   - rawURL: 需要校验的地址
+//   - policy: 校验策略
+func IsAllowedURL(rawURL string, policy *URLPolicy) bool {
+	if rawURL == "" || strings.TrimSpace(rawURL) != rawURL {
+		return false
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	// 校验协议
+	schemes := policy.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+
+	if !slices.Contains(schemes, strings.ToLower(u.Scheme)) {
+		return false
+	}
+
+	// 不允许携带用户信息, 避免 http://trusted.com@evil.com 这类混淆
+	if u.User != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return false
+	}
+
+	// 校验主机白名单
+	if len(policy.Hosts) > 0 && !hostAllowed(host, policy.Hosts) {
+		return false
+	}
+
+	if policy.AllowPrivate {
+		return true
+	}
+
+	// IP 字面量直接判断
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return !IsPrivateAddr(addr)
+	}
+
+	// 非 IP 字面量必须是合法域名, 同时排除本地域名
+	if !IsDomain(host) || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	if !policy.ResolveHost {
+		return true
+	}
+
+	// 解析域名, 所有解析结果都不能是内网地址
+	ips, err := lookupIP(host)
+	if err != nil || len(ips) == 0 {
+		return false
+	}
+
+	for _, ip := range ips {
+		addr, ok := netip.AddrFromSlice(ip)
+		if !ok || IsPrivateAddr(addr.Unmap()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hostAllowed 判断 host 是否在白名单 hosts 中, 支持 "*.example.com" 通配子域名
+func hostAllowed(host string, hosts []string) bool {
+	for _, h := range hosts {
+		h = strings.ToLower(h)
+
+		if suffix, ok := strings.CutPrefix(h, "*"); ok {
+			// *.example.com 只匹配子域名, 不匹配 example.com 本身
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+
+			continue
+		}
+
+		if host == h {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPrivateAddr 判断 addr 是否为内网地址, 包括回环、私有、链路本地、未指定、组播等地址
+func IsPrivateAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() ||
+		isSharedAddr(addr)
+}
+
+// sharedPrefix 运营商级 NAT 地址段 100.64.0.0/10 (RFC 6598), 云厂商元数据服务等也常用
+var sharedPrefix = netip.MustParsePrefix("100.64.0.0/10")
+
+// isSharedAddr 判断是否为运营商级 NAT 地址
+func isSharedAddr(addr netip.Addr) bool {
+	return sharedPrefix.Contains(addr)
+}
+
+// ValidateDomain 校验域名, 不包含协议和端口, 例如 example.com
+func ValidateDomain(fl validator.FieldLevel) bool {
+	return IsDomain(fl.Field().String())
+}
+
+// IsDomain 判断 s 是否为合法域名, 至少包含两级, 每级 1-63 个字符, 总长度不超过 253
+func IsDomain(s string) bool {
+	// 允许末尾的根域名点
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 253 {
+		return false
+	}
+
+	labels := strings.Split(s, ".")
+	if len(labels) < 2 {
+		return false
+	}
+
+	for _, label := range labels {
+		if !isDomainLabel(label) {
+			return false
+		}
+	}
+
+	// 顶级域名不能是纯数字, 避免把 IPv4 当成域名
+	tld := labels[len(labels)-1]
+
+	return strings.IndexFunc(tld, func(r rune) bool { return r < '0' || r > '9' }) >= 0
+}
+
+// isDomainLabel 判断是否为合法的域名标签, 只允许字母、数字和中划线, 且不能以中划线开头或结尾
+func isDomainLabel(label string) bool {
+	if label == "" || len(label) > 63 {
+		return false
+	}
+
+	if label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+
+	for i := range len(label) {
+		c := label[i]
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ValidateIP 校验 IPv4 或 IPv6 地址
+func ValidateIP(fl validator.FieldLevel) bool {
+	_, err := netip.ParseAddr(fl.Field().String())
+
+	return err == nil
+}
+
+// ValidateIPv4 校验 IPv4 地址
+func ValidateIPv4(fl validator.FieldLevel) bool {
+	addr, err := netip.ParseAddr(fl.Field().String())
+
+	return err == nil && addr.Is4()
+}
+
+// ValidateIPv6 校验 IPv6 地址
+func ValidateIPv6(fl validator.FieldLevel) bool {
+	addr, err := netip.ParseAddr(fl.Field().String())
+
+	return err == nil && addr.Is6()
+}
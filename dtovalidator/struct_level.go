@@ -0,0 +1,165 @@
+//
+// FilePath    : go-utils\dtovalidator\struct_level.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 结构体级别及跨字段校验器
+//
+
+package dtovalidator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// StructValidatorEntry 结构体级别校验器明细
+type StructValidatorEntry struct {
+	Types         []any                     // 需要校验的结构体类型, 传入结构体零值, 例如 OrderQuery{}
+	ValidatorFunc validator.StructLevelFunc // 校验函数, 失败时通过 sl.ReportError 报告, tag 使用注册名称
+	ErrMsg        string                    // 错误信息, 支持 {0} 占位字段名
+}
+
+// StructEntryMap 是一个映射, 其中键是结构体校验器报告错误时使用的 tag, 值是 StructValidatorEntry 结构体
+var StructEntryMap = make(map[string]StructValidatorEntry)
+
+// RegisterStructValidator 添加新的结构体级别校验器到 StructEntryMap 中, 由 InstallTo 与字段校验器一起注册
+//   - tag: 校验失败时报告的 tag, 用于匹配错误信息, 不能与字段校验器重名
+//   - entry: 校验器明细
+func RegisterStructValidator(tag string, entry StructValidatorEntry) {
+	StructEntryMap[tag] = entry
+}
+
+// RequireAnyOf 生成结构体校验函数: fields 中至少有一个字段非零值, 例如邮箱和手机号至少填写一个.
+// 校验失败时在第一个字段上报告 tag 错误.
+//   - tag: 报告错误使用的 tag
+//   - fields: 结构体字段名(Go 字段名)
+func RequireAnyOf(tag string, fields ...string) validator.StructLevelFunc {
+	return func(sl validator.StructLevel) {
+		current := sl.Current()
+
+		for _, name := range fields {
+			if field := current.FieldByName(name); field.IsValid() && !field.IsZero() {
+				return
+			}
+		}
+
+		if len(fields) > 0 {
+			reportStructError(sl, fields[0], tag, strings.Join(fields, " "))
+		}
+	}
+}
+
+// FieldBefore 生成结构体校验函数: field 的值需要小于 otherField 的值, 例如开始时间早于结束时间.
+// 支持 time.Time、整数、浮点数和字符串, 任一字段为零值时不校验(由 required 等字段校验器负责).
+// 校验失败时在 field 上报告 tag 错误.
+//   - tag: 报告错误使用的 tag
+//   - field: 结构体字段名(Go 字段名)
+//   - otherField: 比较的结构体字段名(Go 字段名)
+func FieldBefore(tag, field, otherField string) validator.StructLevelFunc {
+	return func(sl validator.StructLevel) {
+		current := sl.Current()
+		a, b := indirect(current.FieldByName(field)), indirect(current.FieldByName(otherField))
+
+		if !a.IsValid() || !b.IsValid() || a.IsZero() || b.IsZero() {
+			return
+		}
+
+		if less, ok := lessValue(a, b); ok && !less {
+			reportStructError(sl, field, tag, otherField)
+		}
+	}
+}
+
+// reportStructError 在字段 name 上报告 tag 错误, 字段名使用 json tag 名称
+func reportStructError(sl validator.StructLevel, name, tag, param string) {
+	fieldName := name
+
+	if sf, ok := sl.Current().Type().FieldByName(name); ok {
+		if jsonName := JSONTagName(sf); jsonName != "" {
+			fieldName = jsonName
+		}
+	}
+
+	sl.ReportError(sl.Current().FieldByName(name).Interface(), fieldName, name, tag, param)
+}
+
+// indirect 获取指针指向的值
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// timeType time.Time 的类型
+var timeType = reflect.TypeFor[time.Time]()
+
+// lessValue 比较 a < b, 类型不支持或不一致时返回 false
+func lessValue(a, b reflect.Value) (less, ok bool) {
+	if a.Type() == timeType && b.Type() == timeType {
+		return a.Interface().(time.Time).Before(b.Interface().(time.Time)), true
+	}
+
+	switch {
+	case a.CanInt() && b.CanInt():
+		return a.Int() < b.Int(), true
+	case a.CanUint() && b.CanUint():
+		return a.Uint() < b.Uint(), true
+	case a.CanFloat() && b.CanFloat():
+		return a.Float() < b.Float(), true
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return a.String() < b.String(), true
+	}
+
+	return false, false
+}
+
+// installStructEntries 按 tag 顺序注册结构体校验器, 已初始化翻译器时同时注册错误信息翻译.
+// validator 每个类型只能注册一个结构体校验函数, 同一类型的多个校验器会合并后依次执行.
+func installStructEntries(v *validator.Validate, tags []string) []error {
+	var errs []error
+
+	funcs := make(map[reflect.Type][]validator.StructLevelFunc)
+	types := make(map[reflect.Type]any)
+
+	for _, tag := range tags {
+		entry := StructEntryMap[tag]
+		if len(entry.Types) == 0 || entry.ValidatorFunc == nil {
+			errs = append(errs, fmt.Errorf("结构体校验器 %s 未设置类型或校验函数", tag))
+			continue
+		}
+
+		if Trans != nil {
+			if err := registerTranslation(v, tag, entry.ErrMsg); err != nil {
+				errs = append(errs, fmt.Errorf("注册结构体校验器 %s 失败: %w", tag, err))
+				continue
+			}
+		}
+
+		for _, typ := range entry.Types {
+			t := reflect.TypeOf(typ)
+			funcs[t] = append(funcs[t], entry.ValidatorFunc)
+			types[t] = typ
+		}
+	}
+
+	for t, fns := range funcs {
+		v.RegisterStructValidation(func(sl validator.StructLevel) {
+			for _, fn := range fns {
+				fn(sl)
+			}
+		}, types[t])
+	}
+
+	return errs
+}
@@ -0,0 +1,87 @@
+//
+// FilePath    : go-utils\dtovalidator\struct_level_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 结构体级别校验器测试
+//
+
+package dtovalidator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type structLevelQuery struct {
+	StartDate time.Time  `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+	Email     string     `json:"email"`
+	Phone     string     `json:"phone"`
+}
+
+func TestStructValidators(t *testing.T) {
+	RegisterStructValidator("testDateRange", StructValidatorEntry{
+		Types:         []any{structLevelQuery{}},
+		ValidatorFunc: FieldBefore("testDateRange", "StartDate", "EndDate"),
+		ErrMsg:        "{0} 需要早于结束时间",
+	})
+	RegisterStructValidator("testContact", StructValidatorEntry{
+		Types:         []any{structLevelQuery{}},
+		ValidatorFunc: RequireAnyOf("testContact", "Email", "Phone"),
+		ErrMsg:        "邮箱和手机号至少填写一个",
+	})
+
+	defer delete(StructEntryMap, "testDateRange")
+	defer delete(StructEntryMap, "testContact")
+
+	v := validator.New()
+	v.RegisterTagNameFunc(JSONTagName)
+
+	if err := InstallTo(v); err != nil {
+		t.Fatalf("InstallTo() error = %v", err)
+	}
+
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		query structLevelQuery
+		want  map[string]string // 字段路径 -> 错误信息
+	}{
+		{
+			name:  "valid",
+			query: structLevelQuery{StartDate: now, EndDate: &later, Email: "a@b.com"},
+		},
+		{
+			name:  "end before start and no contact",
+			query: structLevelQuery{StartDate: later, EndDate: &now},
+			want: map[string]string{
+				"start_date": "start_date 需要早于结束时间",
+				"email":      "邮箱和手机号至少填写一个",
+			},
+		},
+		{
+			name:  "nil end date skipped",
+			query: structLevelQuery{StartDate: later, Phone: "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TranslateErrors(v.Struct(tt.query))
+			if len(got) != len(tt.want) {
+				t.Fatalf("TranslateErrors() = %+v, want %v", got, tt.want)
+			}
+
+			for _, fe := range got {
+				if msg, ok := tt.want[fe.Field]; !ok || msg != fe.Msg {
+					t.Errorf("TranslateErrors() field %q = %q, want %q", fe.Field, fe.Msg, msg)
+				}
+			}
+		})
+	}
+}
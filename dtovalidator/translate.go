@@ -0,0 +1,102 @@
+//
+// FilePath    : go-utils\dtovalidator\translate.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 校验错误转换为结构化的字段错误
+//
+
+package dtovalidator
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError 字段校验错误, 可直接作为 400 响应的数据返回
+type FieldError struct {
+	Field string `json:"field"`           // 字段路径, 使用 json tag 名称, 例如 address.city, items[0].id
+	Tag   string `json:"tag,omitempty"`   // 校验失败的标签, 例如 required, ValidateInt
+	Param string `json:"param,omitempty"` // 标签参数, 例如 max=10 中的 10
+	Msg   string `json:"msg"`             // 错误信息
+}
+
+// TranslateErrors 将绑定或校验错误转换为字段错误列表, err 为 nil 时返回 nil.
+// 自定义校验器使用 EntryMap 或 StructEntryMap 中注册的 ErrMsg(支持 {0} 占位字段名), 内置校验器使用 Trans 翻译,
+// 异步校验错误(AsyncValidationErrors)直接返回;
+// json 解析错误转换为单个字段错误, 其他错误返回 Field 为空的单个错误.
+func TranslateErrors(err error) []FieldError {
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fieldErrs := make([]FieldError, 0, len(validationErrs))
+
+		for _, fe := range validationErrs {
+			fieldErrs = append(fieldErrs, FieldError{
+				Field: fieldPath(fe),
+				Tag:   fe.Tag(),
+				Param: fe.Param(),
+				Msg:   fieldErrorMsg(fe),
+			})
+		}
+
+		return fieldErrs
+	}
+
+	var asyncErrs AsyncValidationErrors
+	if errors.As(err, &asyncErrs) {
+		return asyncErrs
+	}
+
+	// json 类型不匹配, 例如字符串传给了整数字段
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []FieldError{{
+			Field: typeErr.Field,
+			Msg:   "参数类型错误, 需要 " + typeErr.Type.String(),
+		}}
+	}
+
+	return []FieldError{{Msg: err.Error()}}
+}
+
+// fieldPath 获取字段路径, 去掉最外层结构体名称, 例如 UserDTO.address.city 转换为 address.city
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+
+	if _, path, ok := strings.Cut(ns, "."); ok {
+		return path
+	}
+
+	return fe.Field()
+}
+
+// fieldErrorMsg 获取字段错误信息, 优先使用 EntryMap 和 StructEntryMap 中注册的 ErrMsg
+func fieldErrorMsg(fe validator.FieldError) string {
+	if entry, ok := EntryMap[fe.Tag()]; ok && entry.ErrMsg != "" {
+		return strings.ReplaceAll(entry.ErrMsg, "{0}", fe.Field())
+	}
+
+	if entry, ok := StructEntryMap[fe.Tag()]; ok && entry.ErrMsg != "" {
+		return strings.ReplaceAll(entry.ErrMsg, "{0}", fe.Field())
+	}
+
+	if Trans != nil {
+		return fe.Translate(Trans)
+	}
+
+	// 未初始化翻译器时返回简单的英文描述
+	msg := fe.Field() + " failed on the '" + fe.Tag() + "' tag"
+	if fe.Param() != "" {
+		msg += " (" + strconv.Quote(fe.Param()) + ")"
+	}
+
+	return msg
+}
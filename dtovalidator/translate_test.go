@@ -0,0 +1,81 @@
+//
+// FilePath    : go-utils\dtovalidator\translate_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 校验错误转换测试
+//
+
+package dtovalidator
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type translateAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type translateDTO struct {
+	Year    int              `json:"year" validate:"ValidateIntYear"`
+	Name    string           `json:"name" validate:"max=3"`
+	Address translateAddress `json:"address"`
+}
+
+func TestTranslateErrors(t *testing.T) {
+	v := validator.New()
+	v.RegisterTagNameFunc(JSONTagName)
+
+	if err := v.RegisterValidation("ValidateIntYear", validator.Func(ValidateIntYear)); err != nil {
+		t.Fatalf("RegisterValidation() error = %v", err)
+	}
+
+	err := v.Struct(translateDTO{Year: 99, Name: "abcd"})
+
+	got := TranslateErrors(err)
+	want := map[string]FieldError{
+		"year":         {Field: "year", Tag: "ValidateIntYear", Msg: EntryMap["ValidateIntYear"].ErrMsg},
+		"name":         {Field: "name", Tag: "max", Param: "3"},
+		"address.city": {Field: "address.city", Tag: "required"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("TranslateErrors() = %+v, want %d errors", got, len(want))
+	}
+
+	for _, fe := range got {
+		w, ok := want[fe.Field]
+		if !ok {
+			t.Fatalf("TranslateErrors() unexpected field %q", fe.Field)
+		}
+
+		if fe.Tag != w.Tag || fe.Param != w.Param || fe.Msg == "" {
+			t.Errorf("TranslateErrors() field %q = %+v, want %+v", fe.Field, fe, w)
+		}
+
+		if w.Msg != "" && fe.Msg != w.Msg {
+			t.Errorf("TranslateErrors() field %q msg = %q, want %q", fe.Field, fe.Msg, w.Msg)
+		}
+	}
+}
+
+func TestTranslateErrorsNonValidation(t *testing.T) {
+	if got := TranslateErrors(nil); got != nil {
+		t.Fatalf("TranslateErrors(nil) = %+v, want nil", got)
+	}
+
+	var dto translateDTO
+
+	err := json.Unmarshal([]byte(`{"year":"x"}`), &dto)
+	if got := TranslateErrors(err); len(got) != 1 || got[0].Field != "year" {
+		t.Errorf("TranslateErrors(json) = %+v", got)
+	}
+
+	if got := TranslateErrors(errors.New("boom")); len(got) != 1 || got[0].Field != "" || got[0].Msg != "boom" {
+		t.Errorf("TranslateErrors(other) = %+v", got)
+	}
+}
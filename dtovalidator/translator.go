@@ -36,14 +36,7 @@ func InitTrans(locale string) (err error) {
 		// 赋值给全局验证器
 		GlobalValidator = v
 		// 注册一个获取 json tag 的自定义方法
-		v.RegisterTagNameFunc(func(fld reflect.StructField) string {
-			name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0] // 以逗号分隔，忽略后面的内容
-			if name == "-" {
-				return ""
-			}
-
-			return name
-		})
+		v.RegisterTagNameFunc(JSONTagName)
 
 		zhT := zh.New() // 中文翻译器
 		enT := en.New() // 英文翻译器
@@ -72,10 +65,8 @@ func InitTrans(locale string) (err error) {
 		}
 
 		// 注册自定义验证器
-		for tag, entry := range EntryMap {
-			if err = registerValidatorFunc(v, tag, entry.ErrMsg, entry.ValidatorFunc); err != nil {
-				return err
-			}
+		if err = InstallTo(v); err != nil {
+			return err
 		}
 
 		return err
@@ -84,6 +75,16 @@ func InitTrans(locale string) (err error) {
 	return err
 }
 
+// JSONTagName 获取字段的 json tag 名称, 用于 validator.RegisterTagNameFunc, 使错误中的字段名与请求参数一致
+func JSONTagName(fld reflect.StructField) string {
+	name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0] // 以逗号分隔，忽略后面的内容
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
 // registerValidatorFunc 根据 v 验证器注册 tag 标签的自定义校验器, fn 为校验函数
 func registerValidatorFunc(v *validator.Validate, tag string, msgStr string, fn ValidatorFunc) error {
 	// 注册tag自定义校验
@@ -91,6 +92,11 @@ func registerValidatorFunc(v *validator.Validate, tag string, msgStr string, fn
 		return err
 	}
 
+	return registerTranslation(v, tag, msgStr)
+}
+
+// registerTranslation 根据 v 验证器注册 tag 标签的自定义错误信息 msgStr
+func registerTranslation(v *validator.Validate, tag string, msgStr string) error {
 	// 自定义错误内容
 	err := v.RegisterTranslation(tag, Trans, func(ut ut.Translator) error {
 		// return ut.Add(tag, "{0}"+msgStr, true) // 参考 universal-translator 的 API 文档 {0} 会自动填充字段名
@@ -33,7 +33,14 @@ const (
 	ErrInvalidSignature      = JpzError("invalid_signature.")              // 签名无效
 	ErrTimestampDiffExceeded = JpzError("timestamp_difference_exceeded.")  // 时间戳差异超出允许范围
 	ErrRequestIDNotFound     = JpzError("request_id_not_found.")           // 请求ID未找到
+	ErrUserIDNotFound        = JpzError("user_id_not_found.")              // 用户ID未找到
+	ErrPostIDNotFound        = JpzError("post_id_not_found.")              // 文章ID未找到
 	ErrDistributedLockFailed = JpzError("distributed_lock_failed.")        // 分布式锁获取失败
+	ErrUploadTooLarge        = JpzError("upload_too_large.")               // 上传文件过大
+	ErrUploadMIMENotAllowed  = JpzError("upload_mime_not_allowed.")        // 上传文件类型不允许
+	ErrUploadFieldNotFound   = JpzError("upload_field_not_found.")         // 表单中不存在上传文件字段
+	ErrUploadInvalidID       = JpzError("upload_invalid_id.")              // 上传ID不合法
+	ErrUploadChunkMissing    = JpzError("upload_chunk_missing.")           // 上传分片缺失
 )
 
 // Error 实现 error 接口 Error 方法
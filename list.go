@@ -29,21 +29,7 @@ func Difference(listA, listB []string) []string {
 
 // differenceSmall 计算两个字符串切片的差集，返回 listA 中所有不在 listB 中的元素(适用于 listB 较小的情况)。
 func differenceSmall(listA, listB []string) []string {
-	// 将listB转换为map提高查找效率
-	bMap := make(map[string]struct{}, len(listB))
-	for _, email := range listB {
-		bMap[email] = struct{}{}
-	}
-
-	var diff []string
-
-	for _, email := range listA {
-		if _, exists := bMap[email]; !exists {
-			diff = append(diff, email)
-		}
-	}
-
-	return diff
+	return DifferenceOf(listA, listB)
 }
 
 // differenceBig 并行计算两个字符串切片的差集，返回 listA 中所有不在 listB 中的元素。
@@ -90,18 +76,7 @@ func differenceBig(listA, listB []string) []string {
 
 // RemoveDuplicateElement 移除字符串切片中的重复元素,返回一个只包含唯一元素的新切片。
 func RemoveDuplicateElement(list []string) []string {
-	seen := make(map[string]struct{})      // 用于记录已见过的元素
-	result := make([]string, 0, len(list)) // 结果切片，预分配内存
-
-	for _, item := range list {
-		if _, exists := seen[item]; !exists {
-			seen[item] = struct{}{}
-
-			result = append(result, item)
-		}
-	}
-
-	return result
+	return Unique(list)
 }
 
 // ReverseSlice 传入一个切片, 然后倒序输出该切片
@@ -0,0 +1,256 @@
+//
+// FilePath    : go-utils\logger\async.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 异步日志 Core, 使用有界队列将日志写入移出请求处理路径, 队列满时丢弃并计数
+//
+
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// AsyncStats 异步日志统计
+type AsyncStats struct {
+	Queued  uint64 // 进入队列的日志条数
+	Written uint64 // 已写入的日志条数
+	Dropped uint64 // 队列满时丢弃的日志条数
+	Failed  uint64 // 写入失败的日志条数
+	Pending int    // 队列中等待写入的日志条数
+}
+
+// asyncConfig 异步日志配置
+type asyncConfig struct {
+	bufferSize int           // 队列长度(条)
+	blockLevel zapcore.Level // 不低于该级别的日志在队列满时等待, 不丢弃
+}
+
+// AsyncOption 定义异步日志的可选配置函数类型
+type AsyncOption func(*asyncConfig)
+
+// WithAsyncBufferSize 设置队列长度(条), 默认 8192
+func WithAsyncBufferSize(size int) AsyncOption {
+	return func(c *asyncConfig) {
+		c.bufferSize = size
+	}
+}
+
+// WithAsyncBlockLevel 设置队列满时等待而不丢弃的最低级别, 默认 ErrorLevel, 即错误日志不丢弃
+func WithAsyncBlockLevel(level zapcore.Level) AsyncOption {
+	return func(c *asyncConfig) {
+		c.blockLevel = level
+	}
+}
+
+// asyncItem 队列中的日志, flush 不为 nil 时为刷新标记, 写入到该位置时关闭 flush
+type asyncItem struct {
+	core   zapcore.Core
+	ent    zapcore.Entry
+	fields []zapcore.Field
+	flush  chan struct{}
+}
+
+// asyncQueue 异步日志队列, 由 AsyncCore 及其 With 派生的 Core 共享
+type asyncQueue struct {
+	cfg   asyncConfig
+	ch    chan asyncItem
+	done  chan struct{} // 写入协程退出后关闭
+	inner zapcore.Core  // 创建时的 Core, 用于关闭后 Sync
+
+	mu     sync.RWMutex // 保护 closed 与 ch 的发送和关闭
+	closed bool
+
+	queued, written, dropped, failed atomic.Uint64
+}
+
+// AsyncCore 异步写入的 zapcore.Core, 日志进入有界队列后由单独的协程按顺序写入被包装的 Core.
+// 队列满时低于 blockLevel 的日志被丢弃并计数; DPanic 及以上级别的日志会先写完队列再同步写入, 保证进程退出前落盘.
+// 注意字段在写入协程中编码, 不要在记录日志后修改 zap.Any 等字段引用的数据.
+// 应用退出前需要调用 Close 写完队列中的日志.
+type AsyncCore struct {
+	zapcore.Core
+	q *asyncQueue
+}
+
+// NewAsyncCore 创建异步写入的 Core 并启动写入协程
+//   - core: 被包装的 Core
+//   - opts: 可选配置
+func NewAsyncCore(core zapcore.Core, opts ...AsyncOption) *AsyncCore {
+	cfg := asyncConfig{bufferSize: 8192, blockLevel: zapcore.ErrorLevel}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.bufferSize <= 0 {
+		cfg.bufferSize = 8192
+	}
+
+	q := &asyncQueue{
+		cfg:   cfg,
+		ch:    make(chan asyncItem, cfg.bufferSize),
+		done:  make(chan struct{}),
+		inner: core,
+	}
+
+	go q.run()
+
+	return &AsyncCore{Core: core, q: q}
+}
+
+// run 写入协程, 按顺序写入队列中的日志, 队列关闭后退出
+func (q *asyncQueue) run() {
+	defer close(q.done)
+
+	for item := range q.ch {
+		if item.flush != nil {
+			close(item.flush)
+			continue
+		}
+
+		q.write(item.core, item.ent, item.fields)
+	}
+}
+
+// write 写入日志并计数, 写入失败时输出到标准错误, 不使用 zap 避免递归
+func (q *asyncQueue) write(core zapcore.Core, ent zapcore.Entry, fields []zapcore.Field) {
+	if err := core.Write(ent, fields); err != nil {
+		q.failed.Add(1)
+		_, _ = fmt.Fprintf(os.Stderr, "异步写入日志失败: %v, 日志消息: %s\n", err, ent.Message)
+
+		return
+	}
+
+	q.written.Add(1)
+}
+
+// enqueue 将日志放入队列, 已关闭时返回 false
+func (q *asyncQueue) enqueue(item asyncItem, block bool) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return false
+	}
+
+	if block {
+		q.ch <- item
+		q.queued.Add(1)
+
+		return true
+	}
+
+	select {
+	case q.ch <- item:
+		q.queued.Add(1)
+	default:
+		q.dropped.Add(1)
+	}
+
+	return true
+}
+
+// flush 等待队列中已有的日志写入完成, 已关闭时等待写入协程退出
+func (q *asyncQueue) flush(ctx context.Context) error {
+	marker := make(chan struct{})
+
+	q.mu.RLock()
+	closed := q.closed
+
+	if !closed {
+		q.ch <- asyncItem{flush: marker}
+	}
+	q.mu.RUnlock()
+
+	wait := marker
+	if closed {
+		wait = q.done
+	}
+
+	select {
+	case <-wait:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// With 实现 zapcore.Core 接口 With 方法, 派生的 Core 共享队列
+func (c *AsyncCore) With(fields []zapcore.Field) zapcore.Core {
+	return &AsyncCore{Core: c.Core.With(fields), q: c.q}
+}
+
+// Check 实现 zapcore.Core 接口 Check 方法, 需要将自身加入 CheckedEntry 才能异步写入
+func (c *AsyncCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口 Write 方法, 将日志放入队列, 关闭后同步写入
+func (c *AsyncCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	// DPanic 及以上级别之后进程可能退出, 写完队列后同步写入
+	if ent.Level >= zapcore.DPanicLevel {
+		_ = c.q.flush(context.Background())
+
+		if err := c.Core.Write(ent, fields); err != nil {
+			return err
+		}
+
+		return c.Core.Sync()
+	}
+
+	if c.q.enqueue(asyncItem{core: c.Core, ent: ent, fields: fields}, ent.Level >= c.q.cfg.blockLevel) {
+		return nil
+	}
+
+	return c.Core.Write(ent, fields)
+}
+
+// Sync 实现 zapcore.Core 接口 Sync 方法, 等待队列中已有的日志写入后同步被包装的 Core
+func (c *AsyncCore) Sync() error {
+	if err := c.q.flush(context.Background()); err != nil {
+		return err
+	}
+
+	return c.Core.Sync()
+}
+
+// Stats 获取异步日志统计
+func (c *AsyncCore) Stats() AsyncStats {
+	return AsyncStats{
+		Queued:  c.q.queued.Load(),
+		Written: c.q.written.Load(),
+		Dropped: c.q.dropped.Load(),
+		Failed:  c.q.failed.Load(),
+		Pending: len(c.q.ch),
+	}
+}
+
+// Close 停止接收新日志(之后的日志同步写入), 等待队列中的日志写入完成并同步被包装的 Core, 可重复调用.
+// 签名与优雅停机的关闭函数一致, 可直接注册到停机流程中, ctx 结束时返回 ctx.Err(), 剩余日志继续在后台写入.
+func (c *AsyncCore) Close(ctx context.Context) error {
+	c.q.mu.Lock()
+	if !c.q.closed {
+		c.q.closed = true
+		close(c.q.ch)
+	}
+	c.q.mu.Unlock()
+
+	select {
+	case <-c.q.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return c.q.inner.Sync()
+}
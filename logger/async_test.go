@@ -0,0 +1,123 @@
+//
+// FilePath    : go-utils\logger\async_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 异步日志 Core 测试
+//
+
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// blockingCore 写入前等待 release, 用于模拟慢速输出
+type blockingCore struct {
+	zapcore.Core
+	release chan struct{}
+}
+
+func (c *blockingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c *blockingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	<-c.release
+	return c.Core.Write(ent, fields)
+}
+
+func TestAsyncCore(t *testing.T) {
+	obsCore, logs := observer.New(zapcore.DebugLevel)
+	core := NewAsyncCore(obsCore)
+	log := zap.New(core).With(zap.String("module", "test"))
+
+	for i := range 100 {
+		log.Info("message", zap.Int("i", i))
+	}
+
+	if err := log.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	entries := logs.All()
+	if len(entries) != 100 {
+		t.Fatalf("written = %d, want 100", len(entries))
+	}
+
+	// 按顺序写入且保留 With 字段
+	if entries[99].ContextMap()["i"] != int64(99) || entries[0].ContextMap()["module"] != "test" {
+		t.Errorf("unexpected entry context: %v, %v", entries[0].ContextMap(), entries[99].ContextMap())
+	}
+
+	if err := core.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// 关闭后同步写入
+	log.Info("after close")
+
+	if logs.Len() != 101 {
+		t.Errorf("written after close = %d, want 101", logs.Len())
+	}
+
+	if stats := core.Stats(); stats.Queued != 100 || stats.Written != 100 || stats.Dropped != 0 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestAsyncCoreDrop(t *testing.T) {
+	obsCore, logs := observer.New(zapcore.DebugLevel)
+	inner := &blockingCore{Core: obsCore, release: make(chan struct{})}
+	core := NewAsyncCore(inner, WithAsyncBufferSize(2))
+	log := zap.New(core)
+
+	// 第一条被写入协程取出后阻塞, 队列中最多再放 2 条, 其余 Info 日志被丢弃
+	log.Info("info")
+
+	for core.Stats().Pending > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	for range 9 {
+		log.Info("info")
+	}
+
+	errWritten := make(chan struct{})
+
+	go func() {
+		log.Error("error") // 错误日志不丢弃, 等待队列有空位
+		close(errWritten)
+	}()
+
+	select {
+	case <-errWritten:
+		t.Fatal("Error() returned before queue had space")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(inner.release)
+	<-errWritten
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := core.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	stats := core.Stats()
+	if stats.Dropped == 0 || stats.Written+stats.Dropped != 11 || uint64(logs.Len()) != stats.Written {
+		t.Errorf("Stats() = %+v, logs = %d", stats, logs.Len())
+	}
+
+	if logs.FilterMessage("error").Len() != 1 {
+		t.Error("error entry was dropped")
+	}
+}
@@ -0,0 +1,160 @@
+//
+// FilePath    : go-utils\logger\builder.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 通过代码配置创建 logger, 支持日志文件轮转、单独的错误日志文件和运行时修改日志级别
+//
+
+package logger
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// level NewLogger 和 Init 创建的 logger 共用的日志级别, 通过 SetLevel 在运行时修改
+var level = zap.NewAtomicLevel()
+
+// SetLevel 修改 NewLogger 和 Init 创建的 logger 的日志级别, 立即生效
+//   - l: 日志级别, debug、info、warn、error、dpanic、panic、fatal
+func SetLevel(l string) error {
+	lvl, err := zapcore.ParseLevel(l)
+	if err != nil {
+		return err
+	}
+
+	level.SetLevel(lvl)
+
+	return nil
+}
+
+// GetLevel 获取当前的日志级别
+func GetLevel() string {
+	return level.String()
+}
+
+// LevelHandler 查看和修改日志级别的 http.Handler, GET 返回当前级别, PUT {"level":"debug"} 修改级别
+func LevelHandler() http.Handler {
+	return level
+}
+
+// Config NewLogger 的配置
+type Config struct {
+	Level         string // 日志级别, 默认 info
+	Dev           bool   // 开发模式: console 编码、输出到标准输出、不缓冲; 否则使用 JSON 编码
+	Console       bool   // 非开发模式下是否同时输出到标准输出
+	Filename      string // 日志文件, 为空时不写文件
+	ErrorFilename string // 单独的错误日志文件, 记录 error 及以上级别, 为空时不单独记录
+	MaxSize       int    // 单个日志文件最大大小, 单位 MB, 默认 100
+	MaxAge        int    // 日志文件保留天数, 默认 180
+	MaxBackups    int    // 日志文件保留个数, 默认 100
+	Compress      bool   // 轮转后的日志文件是否压缩
+	BufferSize    int    // 非开发模式下写文件的缓冲区大小, 单位字节, 默认 256KB
+	FlushInterval int    // 非开发模式下写文件的刷新间隔, 单位秒, 默认 5
+}
+
+// NewLogger 根据配置创建 logger, 日志级别与 SetLevel 共用, 支持 Ctx 字段; 不替换全局 logger, 需要时调用 zap.ReplaceGlobals.
+// 非开发模式下写文件有缓冲, 退出前需要调用 logger.Sync.
+//   - cfg: 配置
+func NewLogger(cfg Config) (*zap.Logger, error) {
+	if cfg.Level != "" {
+		if err := SetLevel(cfg.Level); err != nil {
+			return nil, err
+		}
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	var encoder zapcore.Encoder
+	if cfg.Dev {
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	} else {
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	}
+
+	var cores []zapcore.Core
+
+	if cfg.Dev || cfg.Console {
+		cores = append(cores, zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), level))
+	}
+
+	if cfg.Filename != "" {
+		ws, err := cfg.fileSyncer(cfg.Filename)
+		if err != nil {
+			return nil, err
+		}
+
+		cores = append(cores, zapcore.NewCore(encoder, ws, level))
+	}
+
+	if cfg.ErrorFilename != "" {
+		ws, err := cfg.fileSyncer(cfg.ErrorFilename)
+		if err != nil {
+			return nil, err
+		}
+
+		errorLevel := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+			return lvl >= zapcore.ErrorLevel && level.Enabled(lvl)
+		})
+
+		cores = append(cores, zapcore.NewCore(encoder, ws, errorLevel))
+	}
+
+	if len(cores) == 0 {
+		return nil, fmt.Errorf("没有配置日志输出: 需要开启 Dev、Console 或设置 Filename")
+	}
+
+	// 分别包装每个 core, 包装 Tee 时 Write 会绕过各 core 的级别判断
+	for i := range cores {
+		cores[i] = NewTraceCore(cores[i])
+	}
+
+	return zap.New(zapcore.NewTee(cores...), zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel)), nil
+}
+
+// fileSyncer 创建按大小轮转的日志文件写入器, 非开发模式下带缓冲
+//   - filename: 日志文件
+func (cfg *Config) fileSyncer(filename string) (zapcore.WriteSyncer, error) {
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return nil, fmt.Errorf("创建日志目录失败: %w", err)
+	}
+
+	ws := zapcore.AddSync(&lumberjack.Logger{
+		Filename:   filename,
+		MaxSize:    positiveOr(cfg.MaxSize, 100),
+		MaxAge:     positiveOr(cfg.MaxAge, 180),
+		MaxBackups: positiveOr(cfg.MaxBackups, 100),
+		Compress:   cfg.Compress,
+		LocalTime:  true,
+	})
+
+	// 开发环境直接写入, 能够实时看到日志
+	if cfg.Dev {
+		return ws, nil
+	}
+
+	return &zapcore.BufferedWriteSyncer{
+		WS:            ws,
+		Size:          positiveOr(cfg.BufferSize, 256*1024),
+		FlushInterval: time.Duration(positiveOr(cfg.FlushInterval, 5)) * time.Second,
+	}, nil
+}
+
+// positiveOr v 大于 0 时返回 v, 否则返回默认值 def
+func positiveOr(v, def int) int {
+	if v > 0 {
+		return v
+	}
+
+	return def
+}
@@ -0,0 +1,80 @@
+//
+// FilePath    : go-utils\logger\builder_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : NewLogger 单测
+//
+
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger(t *testing.T) {
+	t.Cleanup(func() { _ = SetLevel("info") })
+
+	dir := t.TempDir()
+	cfg := Config{
+		Level:         "info",
+		Filename:      filepath.Join(dir, "app.log"),
+		ErrorFilename: filepath.Join(dir, "error.log"),
+	}
+
+	l, err := NewLogger(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Debug("debug-before")
+	l.Info("info-message")
+	l.Error("error-message")
+
+	if err = SetLevel("debug"); err != nil {
+		t.Fatal(err)
+	}
+
+	l.Debug("debug-after")
+	_ = l.Sync()
+
+	app := readFile(t, cfg.Filename)
+	for _, want := range []string{"info-message", "error-message", "debug-after"} {
+		if !strings.Contains(app, want) {
+			t.Errorf("app.log 缺少 %s: %s", want, app)
+		}
+	}
+
+	if strings.Contains(app, "debug-before") {
+		t.Errorf("app.log 不应包含修改级别前的 debug 日志")
+	}
+
+	errLog := readFile(t, cfg.ErrorFilename)
+	if !strings.Contains(errLog, "error-message") || strings.Contains(errLog, "info-message") {
+		t.Errorf("error.log 应只包含 error 日志: %s", errLog)
+	}
+}
+
+func TestNewLoggerInvalid(t *testing.T) {
+	if _, err := NewLogger(Config{Level: "verbose", Dev: true}); err == nil {
+		t.Error("无效的日志级别应返回错误")
+	}
+
+	if _, err := NewLogger(Config{}); err == nil {
+		t.Error("没有输出时应返回错误")
+	}
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
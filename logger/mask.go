@@ -0,0 +1,336 @@
+//
+// FilePath    : go-utils\logger\mask.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 可配置的脱敏规则, 按字段路径或字段名正则匹配, 支持全部隐藏、保留后 4 位、哈希等策略
+//
+
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// MaskStrategy 脱敏策略, 输入原始值返回脱敏后的值
+type MaskStrategy func(value string) string
+
+// maskPlaceholder 脱敏后的占位符, 与 MaskSensitiveFields 一致
+const maskPlaceholder = "******"
+
+// 内置的脱敏策略
+var (
+	// MaskRedact 全部隐藏
+	MaskRedact MaskStrategy = func(string) string {
+		return maskPlaceholder
+	}
+
+	// MaskKeepLast4 保留后 4 位, 例如银行卡号、手机号; 不超过 4 位时全部隐藏
+	MaskKeepLast4 MaskStrategy = func(value string) string {
+		runes := []rune(value)
+		if len(runes) <= 4 {
+			return maskPlaceholder
+		}
+
+		return maskPlaceholder + string(runes[len(runes)-4:])
+	}
+
+	// MaskHash 使用 sha256 的前 16 位十六进制替换, 相同的值脱敏结果相同, 便于在日志中关联同一用户
+	MaskHash MaskStrategy = func(value string) string {
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	}
+)
+
+// MaskRule 脱敏规则, Path 和 Pattern 至少设置一个, 同时设置时满足任意一个即匹配
+type MaskRule struct {
+	Path     string         // 字段路径, 不区分大小写, 例如 user.phone; 字段名优先使用 json 标签, 切片元素不占路径层级
+	Pattern  *regexp.Regexp // 字段名正则, 匹配路径的最后一级, 例如 regexp.MustCompile(`(?i)phone|mobile`)
+	Strategy MaskStrategy   // 脱敏策略, 为 nil 时使用 MaskRedact
+}
+
+// match 判断字段是否匹配规则
+//   - path: 小写的字段路径
+//   - name: 字段名
+func (r *MaskRule) match(path, name string) bool {
+	if r.Path != "" && strings.EqualFold(r.Path, path) {
+		return true
+	}
+
+	return r.Pattern != nil && r.Pattern.MatchString(name)
+}
+
+// apply 对值执行脱敏策略
+func (r *MaskRule) apply(value string) string {
+	if r.Strategy == nil {
+		return MaskRedact(value)
+	}
+
+	return r.Strategy(value)
+}
+
+// MaskEngine 脱敏规则引擎, 规则可在运行时修改, 并发安全.
+// 按添加顺序匹配规则, 第一个匹配的规则生效; 没有规则匹配时, 字段名包含 SensitiveFields 关键字的字段全部隐藏.
+type MaskEngine struct {
+	mu    sync.RWMutex
+	rules []MaskRule
+}
+
+// NewMaskEngine 创建脱敏规则引擎
+//   - rules: 脱敏规则
+func NewMaskEngine(rules ...MaskRule) *MaskEngine {
+	return &MaskEngine{rules: rules}
+}
+
+// defaultMaskEngine 默认的脱敏规则引擎, 用于 Mask、MaskQuery 以及 res 包的响应日志
+var defaultMaskEngine = NewMaskEngine()
+
+// DefaultMaskEngine 获取默认的脱敏规则引擎
+func DefaultMaskEngine() *MaskEngine {
+	return defaultMaskEngine
+}
+
+// SetMaskRules 替换默认脱敏规则引擎的规则
+//   - rules: 脱敏规则
+func SetMaskRules(rules ...MaskRule) {
+	defaultMaskEngine.SetRules(rules...)
+}
+
+// AddMaskRule 向默认脱敏规则引擎追加规则
+//   - rules: 脱敏规则
+func AddMaskRule(rules ...MaskRule) {
+	defaultMaskEngine.AddRule(rules...)
+}
+
+// Mask 使用默认的脱敏规则引擎对 data 原地脱敏, data 需要为指针或 map
+func Mask(data any) {
+	defaultMaskEngine.Mask(data)
+}
+
+// MaskQuery 使用默认的脱敏规则引擎对 URL 查询参数脱敏, 参数名作为字段名和路径
+func MaskQuery(rawQuery string) string {
+	return defaultMaskEngine.MaskQuery(rawQuery)
+}
+
+// SetRules 替换规则
+//   - rules: 脱敏规则
+func (e *MaskEngine) SetRules(rules ...MaskRule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.rules = append([]MaskRule(nil), rules...)
+}
+
+// AddRule 追加规则
+//   - rules: 脱敏规则
+func (e *MaskEngine) AddRule(rules ...MaskRule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.rules = append(e.rules, rules...)
+}
+
+// MaskValue 对单个字符串值脱敏, 没有规则匹配且字段名不包含敏感关键字时返回原值和 false
+//   - path: 字段路径
+//   - name: 字段名
+//   - value: 原始值
+func (e *MaskEngine) MaskValue(path, name, value string) (string, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.maskValue(strings.ToLower(path), name, value)
+}
+
+// maskValue 对单个字符串值脱敏, 调用方需要持有读锁
+func (e *MaskEngine) maskValue(path, name, value string) (string, bool) {
+	for i := range e.rules {
+		if e.rules[i].match(path, name) {
+			return e.rules[i].apply(value), true
+		}
+	}
+
+	if isFieldSensitive(strings.ToLower(name), SensitiveFields) {
+		return maskPlaceholder, true
+	}
+
+	return value, false
+}
+
+// Mask 对 data 原地脱敏, 支持结构体、map、切片及其嵌套, 字段类型为 string 或 *string 时按规则脱敏, data 需要为指针或 map
+func (e *MaskEngine) Mask(data any) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	e.walk(reflect.ValueOf(data), "")
+}
+
+// MaskQuery 对 URL 查询参数脱敏, 解析失败时整体隐藏
+func (e *MaskEngine) MaskQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return maskPlaceholder
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	masked := false
+
+	for name, vs := range values {
+		for i, v := range vs {
+			if mv, ok := e.maskValue(strings.ToLower(name), name, v); ok {
+				vs[i] = mv
+				masked = true
+			}
+		}
+	}
+
+	// 没有需要脱敏的参数时保持原样, 避免重新编码改变参数顺序
+	if !masked {
+		return rawQuery
+	}
+
+	return values.Encode()
+}
+
+// walk 递归遍历并脱敏, 调用方需要持有读锁
+//   - v: 当前值
+//   - path: 小写的当前路径
+func (e *MaskEngine) walk(v reflect.Value, path string) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		e.walkStruct(v, path)
+	case reflect.Map:
+		e.walkMap(v, path)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			e.walkField(v.Index(i), path, lastSegment(path))
+		}
+	}
+}
+
+// walkStruct 遍历结构体的导出字段
+func (e *MaskEngine) walkStruct(v reflect.Value, path string) {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		fieldType := t.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		// 匿名嵌入的结构体字段与外层同级
+		if fieldType.Anonymous {
+			e.walk(v.Field(i), path)
+			continue
+		}
+
+		name := jsonFieldName(fieldType)
+		e.walkField(v.Field(i), joinPath(path, name), fieldType.Name, name)
+	}
+}
+
+// walkField 字段为字符串时按规则脱敏, 否则继续遍历
+//   - field: 字段值
+//   - path: 小写的字段路径
+//   - names: 字段名, 依次尝试匹配(结构体字段为 Go 字段名和 json 名称)
+func (e *MaskEngine) walkField(field reflect.Value, path string, names ...string) {
+	target := field
+	if target.Kind() == reflect.Pointer && !target.IsNil() && target.Elem().Kind() == reflect.String {
+		target = target.Elem()
+	}
+
+	if target.Kind() != reflect.String {
+		e.walk(field, path)
+		return
+	}
+
+	if !target.CanSet() {
+		return
+	}
+
+	for _, name := range names {
+		if masked, ok := e.maskValue(path, name, target.String()); ok {
+			target.SetString(masked)
+			return
+		}
+	}
+}
+
+// walkMap 遍历 key 为字符串的 map, map 的值不可寻址, 脱敏后使用 SetMapIndex 写回
+func (e *MaskEngine) walkMap(v reflect.Value, path string) {
+	if v.Type().Key().Kind() != reflect.String || v.IsNil() {
+		return
+	}
+
+	for _, key := range v.MapKeys() {
+		name := key.String()
+		fieldPath := joinPath(path, name)
+
+		// 复制为可寻址的值, 处理后写回
+		val := v.MapIndex(key)
+		elem := val
+		if elem.Kind() == reflect.Interface && !elem.IsNil() {
+			elem = elem.Elem()
+		}
+
+		switch elem.Kind() {
+		case reflect.String:
+			if masked, ok := e.maskValue(fieldPath, name, elem.String()); ok {
+				v.SetMapIndex(key, reflect.ValueOf(masked).Convert(val.Type()))
+			}
+		case reflect.Struct, reflect.Array:
+			cp := reflect.New(elem.Type()).Elem()
+			cp.Set(elem)
+			e.walk(cp, fieldPath)
+			v.SetMapIndex(key, cp)
+		default:
+			e.walk(elem, fieldPath)
+		}
+	}
+}
+
+// jsonFieldName 获取结构体字段的 json 名称, 没有 json 标签时为字段名
+func jsonFieldName(f reflect.StructField) string {
+	if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+
+	return f.Name
+}
+
+// joinPath 拼接小写的字段路径
+func joinPath(path, name string) string {
+	if path == "" {
+		return strings.ToLower(name)
+	}
+
+	return path + "." + strings.ToLower(name)
+}
+
+// lastSegment 获取路径的最后一级, 切片元素使用切片字段名匹配规则
+func lastSegment(path string) string {
+	if i := strings.LastIndexByte(path, '.'); i >= 0 {
+		return path[i+1:]
+	}
+
+	return path
+}
@@ -0,0 +1,114 @@
+//
+// FilePath    : go-utils\logger\mask_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 脱敏规则引擎单测
+//
+
+package logger
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type maskAddress struct {
+	Phone string `json:"phone"`
+	City  string `json:"city"`
+}
+
+type maskUser struct {
+	Name     string         `json:"name"`
+	Password string         `json:"password"`
+	IDCard   *string        `json:"id_card"`
+	Cards    []string       `json:"cards"`
+	Address  maskAddress    `json:"address"`
+	Extra    map[string]any `json:"extra"`
+}
+
+func TestMaskEngine(t *testing.T) {
+	idCard := "110101199003074514"
+	wantIDCard := MaskHash(idCard)
+	user := &maskUser{
+		Name:     "张三",
+		Password: "123456",
+		IDCard:   &idCard,
+		Cards:    []string{"6222021234567890"},
+		Address:  maskAddress{Phone: "13812345678", City: "成都"},
+		Extra:    map[string]any{"email": "a@example.com", "note": "hi", "token": "abc"},
+	}
+
+	e := NewMaskEngine(
+		MaskRule{Path: "address.phone", Strategy: MaskKeepLast4},
+		MaskRule{Path: "cards", Strategy: MaskKeepLast4},
+		MaskRule{Pattern: regexp.MustCompile(`(?i)^id_?card$`), Strategy: MaskHash},
+		MaskRule{Path: "extra.email"},
+	)
+	e.Mask(user)
+
+	if user.Name != "张三" || user.Address.City != "成都" || user.Extra["note"] != "hi" {
+		t.Errorf("不应脱敏的字段被修改: %+v", user)
+	}
+
+	if user.Password != maskPlaceholder {
+		t.Errorf("Password = %q, 敏感关键字应全部隐藏", user.Password)
+	}
+
+	if user.Address.Phone != "******5678" {
+		t.Errorf("Address.Phone = %q", user.Address.Phone)
+	}
+
+	if user.Cards[0] != "******7890" {
+		t.Errorf("Cards[0] = %q", user.Cards[0])
+	}
+
+	if !strings.HasPrefix(*user.IDCard, "sha256:") || *user.IDCard != wantIDCard {
+		t.Errorf("IDCard = %q", *user.IDCard)
+	}
+
+	if user.Extra["email"] != maskPlaceholder || user.Extra["token"] != maskPlaceholder {
+		t.Errorf("Extra = %v", user.Extra)
+	}
+}
+
+func TestMaskEngineSetRules(t *testing.T) {
+	e := NewMaskEngine(MaskRule{Path: "phone"})
+
+	v := &maskAddress{Phone: "13812345678"}
+	e.Mask(v)
+
+	if v.Phone != maskPlaceholder {
+		t.Fatalf("Phone = %q", v.Phone)
+	}
+
+	e.SetRules(MaskRule{Path: "phone", Strategy: MaskKeepLast4})
+
+	v = &maskAddress{Phone: "13812345678"}
+	e.Mask(v)
+
+	if v.Phone != "******5678" {
+		t.Fatalf("Phone = %q", v.Phone)
+	}
+}
+
+func TestMaskQuery(t *testing.T) {
+	e := NewMaskEngine(MaskRule{Path: "phone", Strategy: MaskKeepLast4})
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", ""},
+		{"page=1&size=10", "page=1&size=10"},
+		{"phone=13812345678&page=1", "page=1&phone=%2A%2A%2A%2A%2A%2A5678"},
+		{"access_token=abc", "access_token=%2A%2A%2A%2A%2A%2A"},
+	}
+
+	for _, tt := range tests {
+		if got := e.MaskQuery(tt.query); got != tt.want {
+			t.Errorf("MaskQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+//
+// FilePath    : go-utils\logger\pii.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 开发模式下扫描日志中未脱敏的个人敏感信息(PII), 用于完善 SensitiveFields 规则
+//
+
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// PIIPattern 个人敏感信息匹配规则
+type PIIPattern struct {
+	Name     string              // 规则名称, 例如 phone
+	Regexp   *regexp.Regexp      // 匹配的正则, 有分组时使用第一个分组作为匹配值
+	Validate func(s string) bool // 对匹配值的二次校验(如校验位), 为 nil 时不校验
+}
+
+// PIIFinding 扫描结果, 不包含匹配到的原始值, 避免再次泄露
+type PIIFinding struct {
+	Message string // 日志消息
+	Path    string // 字段路径, 例如 user.phone, items[0].email; 日志消息本身为 msg
+	Pattern string // 匹配的规则名称
+}
+
+// DefaultPIIPatterns 默认的个人敏感信息匹配规则: 手机号、邮箱、身份证号、银行卡号
+var DefaultPIIPatterns = []PIIPattern{
+	{
+		Name:   "phone",
+		Regexp: regexp.MustCompile(`(?:^|\D)(1[3-9]\d{9})(?:\D|$)`),
+	},
+	{
+		Name:   "email",
+		Regexp: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	},
+	{
+		Name:     "id_card",
+		Regexp:   regexp.MustCompile(`(?:^|\D)(\d{17}[\dXx])(?:\D|$)`),
+		Validate: validIDCard,
+	},
+	{
+		Name:     "bank_card",
+		Regexp:   regexp.MustCompile(`(?:^|\D)([3-6]\d{12,18})(?:\D|$)`),
+		Validate: validLuhn,
+	},
+}
+
+// PII 扫描配置
+var (
+	piiScan     bool                 // 是否开启 PII 扫描, 仅开发模式生效
+	piiPatterns = DefaultPIIPatterns // 扫描规则
+	piiReporter = defaultPIIReporter // 扫描结果上报函数
+)
+
+// SetPIIScan 设置是否开启 PII 扫描, 仅在开发模式(SetUseDevMode(true))下由 Init 生效
+//   - enable: 是否开启
+//   - patterns: 扫描规则, 为空时使用 DefaultPIIPatterns
+func SetPIIScan(enable bool, patterns ...PIIPattern) {
+	piiScan = enable
+
+	if len(patterns) > 0 {
+		piiPatterns = patterns
+	}
+}
+
+// SetPIIReporter 设置 PII 扫描结果上报函数, 默认输出到标准错误
+func SetPIIReporter(report func(finding PIIFinding)) {
+	piiReporter = report
+}
+
+// defaultPIIReporter 默认的扫描结果上报函数, 输出到标准错误, 不使用 zap 避免再次被扫描
+func defaultPIIReporter(finding PIIFinding) {
+	_, _ = fmt.Fprintf(os.Stderr, "[PII] 日志字段 %s 疑似包含未脱敏的 %s, 请检查 SensitiveFields 规则, 日志消息: %s\n",
+		finding.Path, finding.Pattern, finding.Message)
+}
+
+// ScanPII 扫描日志消息和字段中疑似未脱敏的个人敏感信息, 结果按字段路径排序
+//   - msg: 日志消息
+//   - fields: 日志字段
+//   - patterns: 扫描规则
+func ScanPII(msg string, fields []zapcore.Field, patterns []PIIPattern) []PIIFinding {
+	var findings []PIIFinding
+
+	report := func(path, value string) {
+		for _, p := range patterns {
+			if matchPII(p, value) {
+				findings = append(findings, PIIFinding{Message: msg, Path: path, Pattern: p.Name})
+			}
+		}
+	}
+
+	report("msg", msg)
+
+	// 使用 MapObjectEncoder 将字段编码为通用结构后遍历
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	for key, value := range enc.Fields {
+		walkPIIValue(key, value, report)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Path < findings[j].Path
+	})
+
+	return findings
+}
+
+// matchPII 判断 value 是否匹配规则 p
+func matchPII(p PIIPattern, value string) bool {
+	for _, m := range p.Regexp.FindAllStringSubmatch(value, -1) {
+		s := m[0]
+		if len(m) > 1 {
+			s = m[1]
+		}
+
+		if p.Validate == nil || p.Validate(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// walkPIIValue 递归遍历字段值, 对字符串调用 report
+func walkPIIValue(path string, value any, report func(path, value string)) {
+	switch v := value.(type) {
+	case string:
+		report(path, v)
+	case []byte:
+		report(path, string(v))
+	case map[string]any:
+		for key, val := range v {
+			walkPIIValue(path+"."+key, val, report)
+		}
+	case []any:
+		for i, val := range v {
+			walkPIIValue(path+"["+strconv.Itoa(i)+"]", val, report)
+		}
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		// 数值类型不扫描
+	default:
+		// zap.Any 等反射字段, 通过 json 转换为通用结构后遍历
+		data, err := json.Marshal(v)
+		if err != nil {
+			return
+		}
+
+		var generic any
+		if err = json.Unmarshal(data, &generic); err != nil {
+			return
+		}
+
+		if s, ok := generic.(string); ok {
+			report(path, s)
+			return
+		}
+
+		walkPIIValue(path, generic, report)
+	}
+}
+
+// validLuhn Luhn 校验, 用于银行卡号
+func validLuhn(s string) bool {
+	sum := 0
+	double := false
+
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// validIDCard 18 位身份证号校验位校验
+func validIDCard(s string) bool {
+	if len(s) != 18 {
+		return false
+	}
+
+	weights := [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	checkCodes := "10X98765432"
+
+	sum := 0
+
+	for i := range 17 {
+		d := int(s[i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+
+		sum += d * weights[i]
+	}
+
+	last := s[17]
+	if last == 'x' {
+		last = 'X'
+	}
+
+	return checkCodes[sum%11] == last
+}
+
+// piiScanCore 包装 zapcore.Core, 写入日志前扫描个人敏感信息
+type piiScanCore struct {
+	zapcore.Core
+	fields   []zapcore.Field // With 添加的上下文字段
+	patterns []PIIPattern
+	report   func(finding PIIFinding)
+}
+
+// NewPIIScanCore 创建扫描个人敏感信息的 Core, 写入前扫描日志消息和字段, 发现疑似未脱敏的内容时调用 report.
+// 扫描有一定开销, 仅建议在开发和测试环境使用.
+//   - core: 被包装的 Core
+//   - patterns: 扫描规则
+//   - report: 扫描结果上报函数
+func NewPIIScanCore(core zapcore.Core, patterns []PIIPattern, report func(finding PIIFinding)) zapcore.Core {
+	return &piiScanCore{Core: core, patterns: patterns, report: report}
+}
+
+// With 实现 zapcore.Core 接口 With 方法
+func (c *piiScanCore) With(fields []zapcore.Field) zapcore.Core {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(all, c.fields...)
+	all = append(all, fields...)
+
+	return &piiScanCore{Core: c.Core.With(fields), fields: all, patterns: c.patterns, report: c.report}
+}
+
+// Check 实现 zapcore.Core 接口 Check 方法, 需要将自身加入 CheckedEntry 才能在 Write 时扫描
+func (c *piiScanCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口 Write 方法
+func (c *piiScanCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(all, c.fields...)
+	all = append(all, fields...)
+
+	for _, finding := range ScanPII(ent.Message, all, c.patterns) {
+		c.report(finding)
+	}
+
+	return c.Core.Write(ent, fields)
+}
@@ -0,0 +1,104 @@
+//
+// FilePath    : go-utils\logger\pii_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : PII 扫描单测
+//
+
+package logger
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type piiUser struct {
+	Name  string   `json:"name"`
+	Email string   `json:"email"`
+	Cards []string `json:"cards"`
+}
+
+func TestScanPII(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    string
+		fields []zapcore.Field
+		want   []PIIFinding
+	}{
+		{
+			name:   "phone in field",
+			msg:    "登录",
+			fields: []zapcore.Field{zap.String("phone", "13812345678")},
+			want:   []PIIFinding{{Path: "phone", Pattern: "phone"}},
+		},
+		{
+			name:   "masked phone",
+			msg:    "登录",
+			fields: []zapcore.Field{zap.String("phone", "138****5678")},
+		},
+		{
+			name: "id card with checksum",
+			msg:  "实名认证 11010519491231002X",
+			want: []PIIFinding{{Path: "msg", Pattern: "id_card"}},
+		},
+		{
+			name: "id card with bad checksum",
+			msg:  "实名认证 110105194912310021",
+		},
+		{
+			name:   "nested reflected struct",
+			msg:    "用户",
+			fields: []zapcore.Field{zap.Any("user", piiUser{Name: "a", Email: "a@b.com", Cards: []string{"1", "4111111111111111"}})},
+			want: []PIIFinding{
+				{Path: "user.cards[1]", Pattern: "bank_card"},
+				{Path: "user.email", Pattern: "email"},
+			},
+		},
+		{
+			name:   "numeric order id not card",
+			msg:    "订单",
+			fields: []zapcore.Field{zap.Int64("order_id", 4111111111111111)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScanPII(tt.msg, tt.fields, DefaultPIIPatterns)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ScanPII() = %+v, want %+v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i].Path != tt.want[i].Path || got[i].Pattern != tt.want[i].Pattern {
+					t.Errorf("ScanPII()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPIIScanCore(t *testing.T) {
+	inner, logs := observer.New(zapcore.InfoLevel)
+
+	var findings []PIIFinding
+
+	core := NewPIIScanCore(inner, DefaultPIIPatterns, func(f PIIFinding) {
+		findings = append(findings, f)
+	})
+
+	log := zap.New(core).With(zap.String("email", "x@y.cn"))
+	log.Debug("忽略 13812345678")
+	log.Info("下单", zap.String("phone", "13812345678"))
+
+	if logs.Len() != 1 {
+		t.Fatalf("inner core got %d entries, want 1", logs.Len())
+	}
+
+	if len(findings) != 2 || findings[0].Path != "email" || findings[1].Path != "phone" {
+		t.Errorf("findings = %+v", findings)
+	}
+}
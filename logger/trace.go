@@ -0,0 +1,122 @@
+//
+// FilePath    : go-utils\logger\trace.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 从上下文中提取 OpenTelemetry 的 trace_id、span_id 写入日志, 便于日志与链路追踪关联
+//
+
+package logger
+
+import (
+	"context"
+
+	"github.com/jiaopengzi/go-utils"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 链路追踪日志字段名
+const (
+	FieldTraceID = "trace_id" // 链路追踪ID
+	FieldSpanID  = "span_id"  // 跨度ID
+)
+
+// ctxFieldKey Ctx 字段的 key, 由 TraceCore 展开为链路追踪字段
+const ctxFieldKey = "__ctx__"
+
+// TraceFields 获取上下文中的链路追踪字段.
+// 上下文中有 OpenTelemetry 的有效 span 时返回 trace_id 和 span_id; 否则有 utils.ContextWithTraceID 设置的链路追踪ID时返回 trace_id; 都没有时返回 nil.
+//   - ctx: 上下文
+func TraceFields(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return []zap.Field{
+			zap.String(FieldTraceID, sc.TraceID().String()),
+			zap.String(FieldSpanID, sc.SpanID().String()),
+		}
+	}
+
+	if traceID := utils.TraceIDFromContext(ctx); traceID != "" {
+		return []zap.Field{zap.String(FieldTraceID, traceID)}
+	}
+
+	return nil
+}
+
+// WithTrace 返回附带上下文链路追踪字段的全局 logger, 例如 logger.WithTrace(ctx).Info("下单成功")
+//   - ctx: 上下文
+func WithTrace(ctx context.Context) *zap.Logger {
+	fields := TraceFields(ctx)
+	if len(fields) == 0 {
+		return zap.L()
+	}
+
+	return zap.L().With(fields...)
+}
+
+// Ctx 将上下文作为日志字段传入, 例如 zap.L().Info("下单成功", logger.Ctx(ctx)).
+// 由 TraceCore 展开为链路追踪字段, Init 和 NewLogger 创建的 logger 已包含 TraceCore; 其他 logger 中该字段会被忽略.
+//   - ctx: 上下文
+func Ctx(ctx context.Context) zap.Field {
+	return zap.Field{Key: ctxFieldKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// traceCore 将 Ctx 字段展开为链路追踪字段的 zapcore.Core
+type traceCore struct {
+	zapcore.Core
+}
+
+// NewTraceCore 包装 core, 将日志中的 Ctx 字段展开为 trace_id、span_id.
+// 需要包装单个 core 而不是 zapcore.NewTee 的结果, Tee 中各 core 的级别判断在 Check 中完成.
+//   - core: 被包装的 core
+func NewTraceCore(core zapcore.Core) zapcore.Core {
+	return &traceCore{Core: core}
+}
+
+// With 实现 zapcore.Core 接口
+func (c *traceCore) With(fields []zapcore.Field) zapcore.Core {
+	return &traceCore{Core: c.Core.With(expandTraceFields(fields))}
+}
+
+// Check 实现 zapcore.Core 接口
+func (c *traceCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口
+func (c *traceCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, expandTraceFields(fields))
+}
+
+// expandTraceFields 将 Ctx 字段替换为链路追踪字段, 没有 Ctx 字段时返回原切片
+func expandTraceFields(fields []zapcore.Field) []zapcore.Field {
+	idx := -1
+
+	for i := range fields {
+		if fields[i].Key == ctxFieldKey && fields[i].Type == zapcore.SkipType {
+			idx = i
+			break
+		}
+	}
+
+	if idx < 0 {
+		return fields
+	}
+
+	ctx, _ := fields[idx].Interface.(context.Context)
+
+	expanded := make([]zapcore.Field, 0, len(fields)+1)
+	expanded = append(expanded, fields[:idx]...)
+	expanded = append(expanded, TraceFields(ctx)...)
+
+	return append(expanded, expandTraceFields(fields[idx+1:])...)
+}
@@ -0,0 +1,101 @@
+//
+// FilePath    : go-utils\logger\trace_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 链路追踪字段单测
+//
+
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jiaopengzi/go-utils"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// otelContext 返回携带有效 span 的上下文
+func otelContext(t *testing.T) context.Context {
+	t.Helper()
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled})
+
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+func TestTraceFields(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want map[string]string
+	}{
+		{"nil", nil, map[string]string{}},
+		{"empty", context.Background(), map[string]string{}},
+		{"utils trace id", utils.ContextWithTraceID(context.Background(), "job-1"), map[string]string{FieldTraceID: "job-1"}},
+		{"otel", otelContext(t), map[string]string{FieldTraceID: "4bf92f3577b34da6a3ce929d0e0e4736", FieldSpanID: "00f067aa0ba902b7"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]string{}
+			for _, f := range TraceFields(tt.ctx) {
+				got[f.Key] = f.String
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("TraceFields() = %v, want %v", got, tt.want)
+			}
+
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestTraceCore(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	l := zap.New(NewTraceCore(core))
+
+	l.Info("with ctx", zap.String("a", "1"), Ctx(otelContext(t)))
+	l.With(Ctx(utils.ContextWithTraceID(context.Background(), "job-1"))).Info("with")
+	l.Info("without ctx", zap.String("a", "1"))
+
+	entries := logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if fields[FieldTraceID] != "4bf92f3577b34da6a3ce929d0e0e4736" || fields[FieldSpanID] != "00f067aa0ba902b7" || fields["a"] != "1" {
+		t.Errorf("entry 0 fields = %v", fields)
+	}
+
+	if _, ok := fields[ctxFieldKey]; ok {
+		t.Errorf("Ctx 字段应被展开: %v", fields)
+	}
+
+	if got := entries[1].ContextMap()[FieldTraceID]; got != "job-1" {
+		t.Errorf("entry 1 trace_id = %v", got)
+	}
+
+	if _, ok := entries[2].ContextMap()[FieldTraceID]; ok {
+		t.Errorf("entry 2 不应有 trace_id")
+	}
+}
@@ -9,6 +9,7 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"os"
@@ -29,6 +30,8 @@ type MyZapConfig struct {
 	ZapConfig     zap.Config `json:"zapConfig" yaml:"zapConfig"`          // 继承 zap 配置
 	BufferSize    int        `json:"bufferSize"  yaml:"bufferSize"`       // 缓冲区大小 单位:字节
 	FlushInterval int        `json:"flushInterval"  yaml:"flushInterval"` // 刷新间隔 单位:秒
+
+	AsyncBufferSize int `json:"asyncBufferSize" yaml:"asyncBufferSize"` // 异步日志队列长度 单位:条, 为 0 表示同步写入
 }
 
 // lumberjackSink 重写 lumberjackSink
@@ -49,6 +52,27 @@ func SetUseDevMode(dev bool) {
 	useDevMode = dev
 }
 
+// asyncCore Init 创建的异步日志 Core, 未开启异步写入时为 nil
+var asyncCore *AsyncCore
+
+// Shutdown 写完 Init 创建的异步日志队列并同步日志, 用于优雅停机, 未开启异步写入时只同步日志
+func Shutdown(ctx context.Context) error {
+	if asyncCore != nil {
+		return asyncCore.Close(ctx)
+	}
+
+	return zap.L().Sync()
+}
+
+// GetAsyncStats 获取 Init 创建的异步日志统计, 未开启异步写入时返回 false
+func GetAsyncStats() (AsyncStats, bool) {
+	if asyncCore == nil {
+		return AsyncStats{}, false
+	}
+
+	return asyncCore.Stats(), true
+}
+
 // Init 初始化日志
 func Init(confFilePath string) error {
 	// 读取配置文件
@@ -87,16 +111,30 @@ func Init(confFilePath string) error {
 	// // 合并 Core
 	// core := zapcore.NewTee(infoCore, errorCore)
 
-	// 不分拆日志文件
-	infoLevel := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return lvl >= cfg.ZapConfig.Level.Level() // 应用配置文件中的日志级别
-	})
+	// 不分拆日志文件, 应用配置文件中的日志级别, 之后可以通过 SetLevel 修改
+	level.SetLevel(cfg.ZapConfig.Level.Level())
+
+	infoLevel := zap.LevelEnablerFunc(level.Enabled)
 
 	core, err := newCoreCustom(cfg, &infoLevel, cfg.ZapConfig.OutputPaths...)
 	if err != nil {
 		panic(err)
 	}
 
+	// 展开日志中的 Ctx 字段为链路追踪字段
+	core = NewTraceCore(core)
+
+	// 开发模式下扫描日志中未脱敏的个人敏感信息
+	if useDevMode && piiScan {
+		core = NewPIIScanCore(core, piiPatterns, piiReporter)
+	}
+
+	// 开启异步写入时, 日志写入移出请求处理路径, 退出前需要调用 Shutdown
+	if cfg.AsyncBufferSize > 0 {
+		asyncCore = NewAsyncCore(core, WithAsyncBufferSize(cfg.AsyncBufferSize))
+		core = asyncCore
+	}
+
 	// 创建 logger
 	logger := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
 
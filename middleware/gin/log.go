@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/go-utils/logger"
 	"github.com/jiaopengzi/go-utils/res"
 	"go.uber.org/zap"
 )
@@ -30,11 +31,21 @@ func ZapLogger() gin.HandlerFunc {
 		// 开始时间
 		start := time.Now()
 
+		// 开启流量统计时记录请求体实际读取的字节数
+		res.CountRequestBody(c)
+
 		// 处理请求
 		c.Next()
 
+		// 未经过响应层的请求(静态文件、中间件中断等)在这里统计流量
+		res.RecordTraffic(c)
+
 		// 格式化日志
 		fields := genZapFields(c, start)
+		fields = append(fields,
+			zap.Int64("reqSize", res.RequestSize(c)),   // 请求体字节数
+			zap.Int64("respSize", res.ResponseSize(c)), // 响应体字节数
+		)
 
 		// 根据状态码决定 zap 日志级别：>=500 -> Error, >=400 -> Warn, else Info
 		status := c.Writer.Status()
@@ -189,9 +200,9 @@ func genZapFields(c *gin.Context, start ...time.Time) []zap.Field {
 		zap.String("path", c.Request.URL.Path), // 请求路径
 	)
 
-	// 判断是否有 query 消息
+	// 判断是否有 query 消息, 按脱敏规则处理敏感参数
 	if c.Request.URL.RawQuery != "" {
-		fields = append(fields, zap.String("query", c.Request.URL.RawQuery))
+		fields = append(fields, zap.String("query", logger.MaskQuery(c.Request.URL.RawQuery)))
 	}
 
 	fields = append(fields, zap.String("user-agent", c.Request.UserAgent()))
@@ -9,19 +9,46 @@
 package mwgin
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jiaopengzi/go-utils/res"
 )
 
-// AddRequestID 添加请求 ID 中间件.
+// AddRequestID 添加请求 ID 中间件, 与 RequestID 相同.
 func AddRequestID() gin.HandlerFunc {
+	return RequestID()
+}
+
+// RequestID 请求 ID 中间件, gin 上下文中没有请求ID时生成 UUIDv7 请求ID(按时间有序, 便于日志排序和索引),
+// 通过 res.SetRequestID 保存并写入请求上下文, 同时设置 X-Request-ID 响应头和请求开始时间.
+func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 将当前请求的 RequestID 信息保存到请求的上下文 c 上
-		requestID := uuid.NewString()
-		c.Set(res.KeyRequestID, requestID)
-		c.Writer.Header().Set("X-Request-ID", requestID)
+		requestID, err := res.GetRequestID(c)
+		if err != nil {
+			requestID = newRequestID()
+		}
+
+		// 将当前请求的 RequestID 和开始时间保存到请求的上下文 c 上
+		res.SetRequestID(c, requestID)
+
+		if _, ok := c.Get(res.KeyStartTime); !ok {
+			c.Set(res.KeyStartTime, time.Now())
+		}
+
+		c.Writer.Header().Set(res.HeaderRequestID, requestID)
 
 		c.Next()
 	}
 }
+
+// newRequestID 生成 UUIDv7 请求ID, 失败时使用随机 UUID
+func newRequestID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+
+	return id.String()
+}
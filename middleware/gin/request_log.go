@@ -0,0 +1,226 @@
+//
+// FilePath    : go-utils\middleware\gin\request_log.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 请求日志中间件, 每个请求一条日志, 包含限制大小并脱敏的请求体和响应体
+//
+
+package mwgin
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/go-utils/logger"
+	"github.com/jiaopengzi/go-utils/res"
+	"go.uber.org/zap"
+)
+
+// 请求日志中请求体和响应体的默认最大记录字节数
+const defaultMaxLogBody = 4 << 10
+
+// requestLogConfig 请求日志中间件配置
+type requestLogConfig struct {
+	maxRequestBody  int                 // 请求体最大记录字节数
+	maxResponseBody int                 // 响应体最大记录字节数
+	skipPaths       map[string]struct{} // 不记录日志的路径
+}
+
+// RequestLogOption 定义请求日志中间件的可选配置函数类型
+type RequestLogOption func(*requestLogConfig)
+
+// WithMaxRequestBody 设置请求体最大记录字节数, 默认 4KB, 为 0 时不记录请求体
+//   - n: 字节数
+func WithMaxRequestBody(n int) RequestLogOption {
+	return func(c *requestLogConfig) {
+		c.maxRequestBody = n
+	}
+}
+
+// WithMaxResponseBody 设置响应体最大记录字节数, 默认 4KB, 为 0 时不记录响应体
+//   - n: 字节数
+func WithMaxResponseBody(n int) RequestLogOption {
+	return func(c *requestLogConfig) {
+		c.maxResponseBody = n
+	}
+}
+
+// WithSkipPaths 设置不记录日志的路径, 例如健康检查
+//   - paths: 请求路径
+func WithSkipPaths(paths ...string) RequestLogOption {
+	return func(c *requestLogConfig) {
+		for _, p := range paths {
+			c.skipPaths[p] = struct{}{}
+		}
+	}
+}
+
+// bodyLogWriter 记录响应体前 limit 个字节的 gin.ResponseWriter
+type bodyLogWriter struct {
+	gin.ResponseWriter
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Write 写入响应并记录前 limit 个字节
+func (w *bodyLogWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString 写入响应并记录前 limit 个字节
+func (w *bodyLogWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// capture 记录写入的内容, 超过 limit 的部分丢弃
+func (w *bodyLogWriter) capture(b []byte) {
+	if remain := w.limit - w.buf.Len(); remain < len(b) {
+		w.truncated = true
+		b = b[:max(remain, 0)]
+	}
+
+	w.buf.Write(b)
+}
+
+// RequestLogger 请求日志中间件, 每个请求结束后记录一条日志: 请求ID、方法、路径、状态码、耗时,
+// 以及限制大小并按 logger 脱敏规则处理的请求体; res.SetEnableResponseBody(true) 时同时记录响应体.
+// 只记录 JSON 和表单格式的请求体和响应体, 超过限制被截断的 JSON 无法脱敏, 只记录大小.
+// 需要在 RequestID 之后使用, 可替代 ZapLogger.
+//   - opts: 可选配置
+func RequestLogger(opts ...RequestLogOption) gin.HandlerFunc {
+	cfg := &requestLogConfig{
+		maxRequestBody:  defaultMaxLogBody,
+		maxResponseBody: defaultMaxLogBody,
+		skipPaths:       make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := cfg.skipPaths[c.Request.URL.Path]; ok {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+
+		res.CountRequestBody(c)
+
+		reqBody := captureRequestBody(c.Request, cfg.maxRequestBody)
+
+		var writer *bodyLogWriter
+		if res.ResponseBodyEnabled() && cfg.maxResponseBody > 0 {
+			writer = &bodyLogWriter{ResponseWriter: c.Writer, limit: cfg.maxResponseBody}
+			c.Writer = writer
+		}
+
+		c.Next()
+
+		res.RecordTraffic(c)
+
+		fields := genZapFields(c, start)
+		fields = append(fields,
+			zap.Int64("reqSize", res.RequestSize(c)),   // 请求体字节数
+			zap.Int64("respSize", res.ResponseSize(c)), // 响应体字节数
+		)
+
+		if reqBody != nil {
+			fields = append(fields, bodyField("reqBody", c.Request.Header.Get("Content-Type"), reqBody.buf, reqBody.truncated))
+		}
+
+		if writer != nil && writer.buf.Len() > 0 {
+			fields = append(fields, bodyField("respBody", writer.Header().Get("Content-Type"), writer.buf.Bytes(), writer.truncated))
+		}
+
+		status := c.Writer.Status()
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			zap.L().Error("[GIN]", fields...)
+		case status >= http.StatusBadRequest:
+			zap.L().Warn("[GIN]", fields...)
+		default:
+			zap.L().Info("[GIN]", fields...)
+		}
+	}
+}
+
+// capturedBody 记录的请求体
+type capturedBody struct {
+	buf       []byte
+	truncated bool
+}
+
+// captureRequestBody 预读请求体的前 limit 个字节用于记录, 并将请求体还原为完整内容; 非 JSON 和表单格式不读取
+func captureRequestBody(r *http.Request, limit int) *capturedBody {
+	if limit <= 0 || r.Body == nil || r.Body == http.NoBody || !loggableContentType(r.Header.Get("Content-Type")) {
+		return nil
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(r.Body, int64(limit)+1))
+
+	// 已读取的部分与剩余部分拼接, 处理函数仍能读取完整的请求体;
+	// 读取出错时同样还原, 已读取的字节不能丢失, 处理函数读取剩余部分时会得到同样的错误
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}
+
+	if err != nil {
+		return nil
+	}
+
+	if len(buf) > limit {
+		return &capturedBody{buf: buf[:limit], truncated: true}
+	}
+
+	return &capturedBody{buf: buf}
+}
+
+// loggableContentType 是否为可以脱敏记录的内容类型: JSON 和表单
+func loggableContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == gin.MIMEJSON || mediaType == gin.MIMEPOSTForm
+}
+
+// bodyField 生成脱敏后的请求体或响应体日志字段
+//   - key: 字段名
+//   - contentType: 内容类型
+//   - body: 记录的内容
+//   - truncated: 是否被截断
+func bodyField(key, contentType string, body []byte, truncated bool) zap.Field {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch {
+	case mediaType == gin.MIMEPOSTForm:
+		return zap.String(key, logger.MaskQuery(string(body)))
+	case mediaType != gin.MIMEJSON:
+		return zap.String(key, "<"+cmp.Or(mediaType, "unknown")+">")
+	case truncated:
+		return zap.String(key, "<truncated json>")
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return zap.String(key, "<invalid json>")
+	}
+
+	logger.Mask(&v)
+
+	return zap.Any(key, v)
+}
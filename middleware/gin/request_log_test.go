@@ -0,0 +1,267 @@
+//
+// FilePath    : go-utils\middleware\gin\request_log_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 请求日志中间件测试
+//
+
+package mwgin
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/go-utils/res"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// observeLogs 将全局日志替换为 observer, 测试结束后还原
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	t.Cleanup(zap.ReplaceGlobals(zap.New(core)))
+
+	return logs
+}
+
+// enableResponseBody 记录响应体, 测试结束后还原
+func enableResponseBody(t *testing.T) {
+	res.SetEnableResponseBody(true)
+	t.Cleanup(func() { res.SetEnableResponseBody(false) })
+}
+
+// echoEngine 创建使用请求日志中间件的引擎, /echo 读取完整的请求体并按原内容类型返回, received 记录读取到的请求体
+func echoEngine(received *string, opts ...RequestLogOption) *gin.Engine {
+	engine := gin.New()
+	engine.Use(RequestID(), RequestLogger(opts...))
+	engine.POST("/echo", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		*received = string(body)
+		c.Data(http.StatusOK, c.ContentType(), body)
+	})
+	engine.GET("/health", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	return engine
+}
+
+// post 向 target 发送 POST 请求
+func post(engine *gin.Engine, target, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	return w
+}
+
+// requestLog 获取唯一的一条请求日志的字段
+func requestLog(t *testing.T, logs *observer.ObservedLogs) map[string]any {
+	t.Helper()
+
+	entries := logs.FilterMessage("[GIN]").All()
+	if len(entries) != 1 {
+		t.Fatalf("请求日志条数 = %d, want 1", len(entries))
+	}
+
+	return entries[0].ContextMap()
+}
+
+func TestRequestLogger_Body(t *testing.T) {
+	longJSON := `{"name":"` + strings.Repeat("n", 64) + `","password":"secret"}`
+
+	tests := []struct {
+		name        string
+		opts        []RequestLogOption
+		contentType string
+		body        string
+		want        any // 请求体和响应体字段的期望值, nil 表示没有该字段
+		wantResp    any // 响应体字段的期望值, 为 nil 时与 want 相同
+	}{
+		{"JSON 脱敏", nil, gin.MIMEJSON, `{"name":"n","password":"secret"}`, map[string]any{"name": "n", "password": "******"}, nil},
+		{"JSON 带字符集", nil, gin.MIMEJSON + "; charset=utf-8", `{"token":"t"}`, map[string]any{"token": "******"}, nil},
+		{"JSON 截断", []RequestLogOption{WithMaxRequestBody(16), WithMaxResponseBody(16)}, gin.MIMEJSON, longJSON, "<truncated json>", nil},
+		{"JSON 无效", nil, gin.MIMEJSON, `{"name":`, "<invalid json>", nil},
+		{"表单脱敏", nil, gin.MIMEPOSTForm, "name=n&password=secret", "name=n&password=%2A%2A%2A%2A%2A%2A", nil},
+		{"表单截断", []RequestLogOption{WithMaxRequestBody(8), WithMaxResponseBody(8)}, gin.MIMEPOSTForm, "name=abcdefgh", "name=abc", nil},
+		{"其他类型只记录响应的内容类型", nil, gin.MIMEPlain, "password=secret", nil, "<text/plain>"},
+		{"不记录请求体和响应体", []RequestLogOption{WithMaxRequestBody(0), WithMaxResponseBody(0)}, gin.MIMEJSON, `{"name":"n"}`, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := observeLogs(t)
+			enableResponseBody(t)
+
+			var received string
+
+			w := post(echoEngine(&received, tt.opts...), "/echo", tt.contentType, tt.body)
+
+			// 记录请求体后处理函数仍能读取完整的请求体
+			if received != tt.body || w.Body.String() != tt.body {
+				t.Fatalf("处理函数读取到 %q, 响应 %q, want %q", received, w.Body.String(), tt.body)
+			}
+
+			fields := requestLog(t, logs)
+
+			for key, want := range map[string]any{"reqBody": tt.want, "respBody": cmp.Or(tt.wantResp, tt.want)} {
+				got, ok := fields[key]
+				if want == nil {
+					if ok {
+						t.Errorf("%s = %v, want none", key, got)
+					}
+
+					continue
+				}
+
+				if !ok || !equalLogValue(got, want) {
+					t.Errorf("%s = %#v, want %#v", key, got, want)
+				}
+			}
+
+			if fields["reqSize"] != int64(len(tt.body)) {
+				t.Errorf("reqSize = %v, want %d", fields["reqSize"], len(tt.body))
+			}
+
+			if strings.Contains(fmt.Sprint(fields), "secret") {
+				t.Errorf("日志包含敏感信息: %v", fields)
+			}
+		})
+	}
+}
+
+// equalLogValue 比较日志字段值, 字段值为 JSON 对象或字符串
+func equalLogValue(got, want any) bool {
+	wantMap, ok := want.(map[string]any)
+	if !ok {
+		return got == want
+	}
+
+	gotMap, ok := got.(map[string]any)
+	if !ok || len(gotMap) != len(wantMap) {
+		return false
+	}
+
+	for k, v := range wantMap {
+		if gotMap[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TestRequestLogger_ResponseBodyDisabled 未开启 res.SetEnableResponseBody 时不记录响应体
+func TestRequestLogger_ResponseBodyDisabled(t *testing.T) {
+	logs := observeLogs(t)
+
+	var received string
+
+	post(echoEngine(&received), "/echo", gin.MIMEJSON, `{"name":"n"}`)
+
+	fields := requestLog(t, logs)
+	if _, ok := fields["respBody"]; ok {
+		t.Errorf("respBody = %v, want none", fields["respBody"])
+	}
+
+	if _, ok := fields["reqBody"]; !ok {
+		t.Error("缺少 reqBody")
+	}
+}
+
+// TestRequestLogger_SkipPaths 跳过的路径不记录日志, 其他路径正常记录
+func TestRequestLogger_SkipPaths(t *testing.T) {
+	logs := observeLogs(t)
+
+	var received string
+
+	engine := echoEngine(&received, WithSkipPaths("/health"))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d", w.Code)
+	}
+
+	if n := logs.FilterMessage("[GIN]").Len(); n != 0 {
+		t.Fatalf("跳过的路径记录了 %d 条日志", n)
+	}
+
+	post(engine, "/echo", gin.MIMEJSON, `{}`)
+
+	if fields := requestLog(t, logs); fields["path"] != "/echo" {
+		t.Errorf("path = %v, want /echo", fields["path"])
+	}
+}
+
+// TestRequestLogger_Traffic 开启流量统计时, 请求日志中间件和响应函数都统计流量, 每个请求只计一次, 请求体字节数不重复计算
+func TestRequestLogger_Traffic(t *testing.T) {
+	observeLogs(t)
+
+	res.SetTrafficAccounting(true)
+	res.ResetTraffic()
+	t.Cleanup(func() {
+		res.SetTrafficAccounting(false)
+		res.ResetTraffic()
+	})
+
+	engine := gin.New()
+	engine.Use(RequestID(), RequestLogger(WithMaxRequestBody(8)))
+	engine.POST("/traffic", func(c *gin.Context) {
+		if _, err := io.Copy(io.Discard, c.Request.Body); err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		// 响应函数统计一次流量, 请求日志中间件不再重复统计
+		res.MsgResponse(&res.Response[any]{}, c)
+	})
+
+	body := `{"name":"` + strings.Repeat("n", 32) + `"}`
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/traffic", strings.NewReader(body))
+		req.Header.Set("Content-Type", gin.MIMEJSON)
+		req.ContentLength = -1 // 不使用 Content-Length, 按实际读取的字节数统计
+
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	var got *res.RouteTraffic
+
+	for _, rt := range res.TrafficSnapshot() {
+		if rt.Route == "/traffic" && rt.Method == http.MethodPost {
+			got = &rt
+		}
+	}
+
+	if got == nil {
+		t.Fatalf("TrafficSnapshot() = %+v, 缺少 /traffic", res.TrafficSnapshot())
+	}
+
+	if got.Requests != 2 || got.RequestBytes != uint64(2*len(body)) {
+		t.Errorf("traffic = %+v, want 2 requests and %d request bytes", *got, 2*len(body))
+	}
+}
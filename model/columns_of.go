@@ -0,0 +1,201 @@
+//
+// FilePath    : go-utils\model\columns_of.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 基于类型的列信息, 不需要结构体实例和字段指针, 适用于泛型代码
+//
+
+package model
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ColumnField 模型字段的列信息
+type ColumnField struct {
+	FieldName string // Go 字段名
+	Column    string // gorm 标签 column 内容
+	Type      string // gorm 标签 type 内容, 未设置时为空
+	JSONName  string // json 标签名称, 未设置时为空
+	Index     []int  // 字段在结构体中的索引路径, 可用于 reflect.Value.FieldByIndex
+}
+
+// Columns 模型 T 的列信息, 由 ColumnsOf 创建, 只读
+type Columns[T Tabler] struct {
+	Table  string        // 表名
+	Fields []ColumnField // 字段列信息, 按结构体中的顺序(嵌套结构体展开)
+
+	byFieldName map[string]int // 字段名 -> Fields 下标
+	byColumn    map[string]int // 列名 -> Fields 下标
+}
+
+// ByFieldName 根据 Go 字段名获取列信息
+func (c *Columns[T]) ByFieldName(name string) (ColumnField, bool) {
+	i, ok := c.byFieldName[name]
+	if !ok {
+		return ColumnField{}, false
+	}
+
+	return c.Fields[i], true
+}
+
+// ByColumn 根据列名获取列信息
+func (c *Columns[T]) ByColumn(column string) (ColumnField, bool) {
+	i, ok := c.byColumn[column]
+	if !ok {
+		return ColumnField{}, false
+	}
+
+	return c.Fields[i], true
+}
+
+// Column 根据 Go 字段名获取列名, 字段不存在时返回空字符串
+//   - name: Go 字段名
+//   - opts: 可选参数, WithTableName(true) 使用表名作为前缀, WithPrefix("p") 使用自定义前缀
+func (c *Columns[T]) Column(name string, opts ...Option) string {
+	f, ok := c.ByFieldName(name)
+	if !ok {
+		return ""
+	}
+
+	return c.withPrefix(f.Column, opts)
+}
+
+// Names 获取所有列名
+//   - opts: 可选参数, WithTableName(true) 使用表名作为前缀, WithPrefix("p") 使用自定义前缀
+func (c *Columns[T]) Names(opts ...Option) []string {
+	names := make([]string, 0, len(c.Fields))
+	for _, f := range c.Fields {
+		names = append(names, c.withPrefix(f.Column, opts))
+	}
+
+	return names
+}
+
+// withPrefix 根据选项为列名添加前缀
+func (c *Columns[T]) withPrefix(column string, opts []Option) string {
+	cfg := Config{}
+	for _, opt := range opts {
+		opt.Apply(&cfg)
+	}
+
+	if cfg.Prefix != "" {
+		return cfg.Prefix + "." + column
+	} else if cfg.TableName {
+		return c.Table + "." + column
+	}
+
+	return column
+}
+
+// columnsOfCache 按类型缓存列信息, reflect.Type -> *Columns[T]
+var columnsOfCache sync.Map
+
+// ColumnsOf 获取模型 T 的列信息, 只解析类型, 不需要结构体实例, 结果按类型缓存.
+// 只包含设置了 gorm 标签 column 的可导出字段, 嵌套结构体(time.Time 等除外)会展开.
+// T 可以是结构体或结构体指针, 例如 ColumnsOf[User]() 或 ColumnsOf[*User]().
+func ColumnsOf[T Tabler]() *Columns[T] {
+	typ := reflect.TypeFor[T]()
+
+	if cached, ok := columnsOfCache.Load(typ); ok {
+		return cached.(*Columns[T])
+	}
+
+	c := &Columns[T]{
+		Table:       newTabler[T]().TableName(),
+		byFieldName: make(map[string]int),
+		byColumn:    make(map[string]int),
+	}
+
+	structType := typ
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	collectColumnFields(structType, nil, &c.Fields)
+
+	for i, f := range c.Fields {
+		c.byFieldName[f.FieldName] = i
+		c.byColumn[f.Column] = i
+	}
+
+	actual, _ := columnsOfCache.LoadOrStore(typ, c)
+
+	return actual.(*Columns[T])
+}
+
+// newTabler 创建 T 的实例, T 为指针类型时创建指向零值的指针, 避免 nil 指针调用 TableName
+func newTabler[T Tabler]() T {
+	var t T
+
+	if typ := reflect.TypeFor[T](); typ.Kind() == reflect.Pointer {
+		t = reflect.New(typ.Elem()).Interface().(T)
+	}
+
+	return t
+}
+
+// columnExcludedTypes 不展开的结构体类型, 与 getExportedFieldPtrs 一致
+var columnExcludedTypes = map[reflect.Type]struct{}{
+	reflect.TypeFor[time.Time]():      {},
+	reflect.TypeFor[gorm.DeletedAt](): {},
+	reflect.TypeFor[sql.NullTime]():   {},
+}
+
+// collectColumnFields 递归收集结构体类型的列信息
+//   - typ: 结构体类型
+//   - index: 父结构体的索引路径
+//   - fields: 收集结果
+func collectColumnFields(typ reflect.Type, index []int, fields *[]ColumnField) {
+	for i := range typ.NumField() {
+		sf := typ.Field(i)
+
+		// 跳过不可导出的字段
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		// 展开嵌套结构体
+		if sf.Type.Kind() == reflect.Struct {
+			if _, excluded := columnExcludedTypes[sf.Type]; !excluded {
+				collectColumnFields(sf.Type, fieldIndex, fields)
+				continue
+			}
+		}
+
+		column := tagValue(sf.Tag.Get(gormTag), "column:", ";")
+		if column == "" {
+			continue
+		}
+
+		jsonName, _, _ := strings.Cut(sf.Tag.Get(jsonTag), ",")
+
+		*fields = append(*fields, ColumnField{
+			FieldName: sf.Name,
+			Column:    column,
+			Type:      tagValue(sf.Tag.Get(gormTag), "type:", ";"),
+			JSONName:  jsonName,
+			Index:     fieldIndex,
+		})
+	}
+}
+
+// tagValue 获取标签内容中键 key 的值, 例如 tagValue("column:id;type:bigint", "column:", ";") 返回 id
+func tagValue(content, key, separator string) string {
+	for part := range strings.SplitSeq(content, separator) {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(part), key); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+
+	return ""
+}
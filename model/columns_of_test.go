@@ -0,0 +1,51 @@
+//
+// FilePath    : go-utils\model\columns_of_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 基于类型的列信息单测
+//
+
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnsOf(t *testing.T) {
+	cols := ColumnsOf[TestModel]()
+
+	assert.Equal(t, "test_models", cols.Table)
+	assert.Equal(t, []string{"id_gorm", "created_at_gorm", "updated_at_gorm", "deleted_at_gorm", "name_gorm"}, cols.Names())
+	assert.Equal(t, "test_models.name_gorm", cols.Column("Name", WithTableName(true)))
+	assert.Equal(t, "t.id_gorm", cols.Column("ID", WithPrefix("t")))
+	assert.Empty(t, cols.Column("NotExists"))
+
+	f, ok := cols.ByFieldName("DeletedAt")
+	assert.True(t, ok)
+	assert.Equal(t, ColumnField{
+		FieldName: "DeletedAt",
+		Column:    "deleted_at_gorm",
+		Type:      "timestamp(6) with time zone",
+		JSONName:  "deleted_at_json",
+		Index:     []int{0, 1, 2},
+	}, f)
+
+	f, ok = cols.ByColumn("name_gorm")
+	assert.True(t, ok)
+	assert.Equal(t, "Name", f.FieldName)
+
+	// 与字段指针 API 结果一致
+	m := &TestModel{Name: "x"}
+	name, err := GetColumnName(m, &m.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, name, cols.Column("Name"))
+	assert.Equal(t, "x", reflect.ValueOf(m).Elem().FieldByIndex(f.Index).Interface())
+
+	// 缓存与指针类型
+	assert.Same(t, cols, ColumnsOf[TestModel]())
+	assert.Equal(t, cols.Names(), ColumnsOf[*TestModel]().Names())
+}
@@ -36,6 +36,23 @@ var CurrencySymbols = map[Currency]string{
 	CurrencyRUB: "₽",
 }
 
+// 货币代码(ISO 4217)映射
+var CurrencyCodes = map[Currency]string{
+	CurrencyCNY: "CNY",
+	CurrencyUSD: "USD",
+	CurrencyEUR: "EUR",
+	CurrencyGBP: "GBP",
+	CurrencyHKD: "HKD",
+	CurrencyTWD: "TWD",
+	CurrencySGD: "SGD",
+	CurrencyRUB: "RUB",
+}
+
+// Code 获取货币代码(ISO 4217), 未知货币返回空字符串
+func (c Currency) Code() string {
+	return CurrencyCodes[c]
+}
+
 // AmountFenToYuan 金额从分转换为元, 保留两位小数
 func (c Currency) AmountFenToYuan(amountFen int64) string {
 	amountYuan := float64(amountFen) / 100.0
@@ -0,0 +1,295 @@
+//
+// FilePath    : go-utils\model\drift.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 数据库结构漂移检测, 比较线上表结构与 gorm 模型定义, 只报告不修改
+//
+
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// DriftKind 结构漂移类别
+type DriftKind string
+
+// 结构漂移类别常量
+const (
+	DriftMissingTable  DriftKind = "missing_table"  // 表不存在
+	DriftMissingColumn DriftKind = "missing_column" // 模型中有, 数据库中没有的列
+	DriftExtraColumn   DriftKind = "extra_column"   // 数据库中有, 模型中没有的列
+	DriftTypeMismatch  DriftKind = "type_mismatch"  // 列类型不一致
+	DriftMissingIndex  DriftKind = "missing_index"  // 模型中有, 数据库中没有的索引
+	DriftExtraIndex    DriftKind = "extra_index"    // 数据库中有, 模型中没有的索引
+	DriftIndexMismatch DriftKind = "index_mismatch" // 索引的列或唯一性不一致
+)
+
+// Drift 单个结构漂移
+type Drift struct {
+	Kind     DriftKind // 漂移类别
+	Table    string    // 表名
+	Name     string    // 列名或索引名, 表不存在时为空
+	Expected string    // 模型定义
+	Actual   string    // 数据库实际
+}
+
+// String 实现 fmt.Stringer 接口
+func (d Drift) String() string {
+	s := fmt.Sprintf("[%s] %s", d.Kind, d.Table)
+	if d.Name != "" {
+		s += "." + d.Name
+	}
+
+	if d.Expected != "" || d.Actual != "" {
+		s += fmt.Sprintf(": expected=%q actual=%q", d.Expected, d.Actual)
+	}
+
+	return s
+}
+
+// DriftReport 结构漂移报告
+type DriftReport struct {
+	Drifts []Drift
+}
+
+// HasDrift 是否存在结构漂移
+func (r *DriftReport) HasDrift() bool {
+	return len(r.Drifts) > 0
+}
+
+// String 实现 fmt.Stringer 接口, 每行一个漂移
+func (r *DriftReport) String() string {
+	lines := make([]string, 0, len(r.Drifts))
+	for _, d := range r.Drifts {
+		lines = append(lines, d.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// DetectDrift 比较数据库实际表结构(通过 gorm Migrator 查询 information_schema 等)与模型定义,
+// 报告缺失的表和列、多余的列、类型不一致及索引差异, 不做任何修改, 适用于 CI 和启动检查.
+//   - db: 数据库连接
+//   - models: 模型列表, 为空时使用 GetModels() 获取已注册的模型
+func DetectDrift(db *gorm.DB, models []any) (*DriftReport, error) {
+	if len(models) == 0 {
+		models = GetModels()
+	}
+
+	report := &DriftReport{}
+	migrator := db.Migrator()
+
+	for _, m := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return nil, fmt.Errorf("解析模型 %T 失败: %w", m, err)
+		}
+
+		sch := stmt.Schema
+
+		if !migrator.HasTable(m) {
+			report.Drifts = append(report.Drifts, Drift{Kind: DriftMissingTable, Table: sch.Table})
+			continue
+		}
+
+		columnTypes, err := migrator.ColumnTypes(m)
+		if err != nil {
+			return nil, fmt.Errorf("获取表 %s 的列信息失败: %w", sch.Table, err)
+		}
+
+		report.Drifts = append(report.Drifts, diffColumns(sch, db.Dialector.DataTypeOf, columnTypes)...)
+
+		indexes, err := migrator.GetIndexes(m)
+		if err != nil {
+			return nil, fmt.Errorf("获取表 %s 的索引信息失败: %w", sch.Table, err)
+		}
+
+		report.Drifts = append(report.Drifts, diffIndexes(sch, indexes)...)
+	}
+
+	return report, nil
+}
+
+// diffColumns 比较模型字段与数据库列
+//   - sch: 模型 schema
+//   - dataTypeOf: 获取字段在当前数据库中的类型
+//   - columnTypes: 数据库列信息
+func diffColumns(sch *schema.Schema, dataTypeOf func(*schema.Field) string, columnTypes []gorm.ColumnType) []Drift {
+	var drifts []Drift
+
+	actual := make(map[string]gorm.ColumnType, len(columnTypes))
+	for _, ct := range columnTypes {
+		actual[strings.ToLower(ct.Name())] = ct
+	}
+
+	expected := make(map[string]struct{}, len(sch.Fields))
+
+	for _, field := range sch.Fields {
+		if field.DBName == "" || field.IgnoreMigration {
+			continue
+		}
+
+		expected[strings.ToLower(field.DBName)] = struct{}{}
+		want := dataTypeOf(field)
+
+		ct, ok := actual[strings.ToLower(field.DBName)]
+		if !ok {
+			drifts = append(drifts, Drift{Kind: DriftMissingColumn, Table: sch.Table, Name: field.DBName, Expected: want})
+			continue
+		}
+
+		got, ok := ct.ColumnType()
+		if !ok || got == "" {
+			got = ct.DatabaseTypeName()
+		}
+
+		if !sameColumnType(want, got) {
+			drifts = append(drifts, Drift{Kind: DriftTypeMismatch, Table: sch.Table, Name: field.DBName, Expected: want, Actual: got})
+		}
+	}
+
+	// 数据库中多余的列, 按数据库中的顺序报告
+	for _, ct := range columnTypes {
+		if _, ok := expected[strings.ToLower(ct.Name())]; !ok {
+			drifts = append(drifts, Drift{Kind: DriftExtraColumn, Table: sch.Table, Name: ct.Name(), Actual: ct.DatabaseTypeName()})
+		}
+	}
+
+	return drifts
+}
+
+// diffIndexes 比较模型索引与数据库索引, 忽略主键
+func diffIndexes(sch *schema.Schema, indexes []gorm.Index) []Drift {
+	var drifts []Drift
+
+	actual := make(map[string]gorm.Index, len(indexes))
+
+	for _, idx := range indexes {
+		if primary, ok := idx.PrimaryKey(); ok && primary {
+			continue
+		}
+
+		actual[idx.Name()] = idx
+	}
+
+	expected := make(map[string]struct{})
+
+	for _, idx := range sch.ParseIndexes() {
+		expected[idx.Name] = struct{}{}
+		want := describeIndex(idx.Class == "UNIQUE", indexColumns(idx))
+
+		got, ok := actual[idx.Name]
+		if !ok {
+			drifts = append(drifts, Drift{Kind: DriftMissingIndex, Table: sch.Table, Name: idx.Name, Expected: want})
+			continue
+		}
+
+		unique, _ := got.Unique()
+		if have := describeIndex(unique, got.Columns()); have != want {
+			drifts = append(drifts, Drift{Kind: DriftIndexMismatch, Table: sch.Table, Name: idx.Name, Expected: want, Actual: have})
+		}
+	}
+
+	// 数据库中多余的索引, 唯一约束等由 gorm 自动创建的同名索引也会在模型中声明, 不会误报
+	names := make([]string, 0, len(actual))
+	for name := range actual {
+		if _, ok := expected[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		unique, _ := actual[name].Unique()
+		drifts = append(drifts, Drift{Kind: DriftExtraIndex, Table: sch.Table, Name: name, Actual: describeIndex(unique, actual[name].Columns())})
+	}
+
+	return drifts
+}
+
+// indexColumns 获取模型索引的列名
+func indexColumns(idx *schema.Index) []string {
+	columns := make([]string, 0, len(idx.Fields))
+
+	for _, f := range idx.Fields {
+		if f.Field != nil {
+			columns = append(columns, f.DBName)
+		} else {
+			columns = append(columns, f.Expression)
+		}
+	}
+
+	return columns
+}
+
+// describeIndex 描述索引, 例如 unique(a,b)
+func describeIndex(unique bool, columns []string) string {
+	kind := "index"
+	if unique {
+		kind = "unique"
+	}
+
+	return fmt.Sprintf("%s(%s)", kind, strings.ToLower(strings.Join(columns, ",")))
+}
+
+// columnTypeAliases 不同数据库或写法下等价的列类型
+var columnTypeAliases = map[string]string{
+	"integer":                     "int",
+	"int4":                        "int",
+	"int8":                        "bigint",
+	"int2":                        "smallint",
+	"bool":                        "boolean",
+	"tinyint(1)":                  "boolean",
+	"character varying":           "varchar",
+	"character":                   "char",
+	"double precision":            "double",
+	"float8":                      "double",
+	"float4":                      "real",
+	"numeric":                     "decimal",
+	"timestamp with time zone":    "timestamptz",
+	"timestamp without time zone": "timestamp",
+}
+
+// normalizeColumnType 规范化列类型, 返回基础类型和参数, 例如 "VARCHAR(191)" 返回 "varchar", "191"
+func normalizeColumnType(t string) (string, string) {
+	t = strings.Join(strings.Fields(strings.ToLower(t)), " ")
+
+	if alias, ok := columnTypeAliases[t]; ok {
+		return alias, ""
+	}
+
+	base, params := t, ""
+
+	if i := strings.Index(t, "("); i >= 0 {
+		if j := strings.Index(t[i:], ")"); j >= 0 {
+			base = strings.TrimSpace(t[:i] + t[i+j+1:])
+			params = strings.ReplaceAll(t[i+1:i+j], " ", "")
+		}
+	}
+
+	if alias, ok := columnTypeAliases[base]; ok {
+		base = alias
+	}
+
+	return base, params
+}
+
+// sameColumnType 判断模型类型与数据库类型是否一致, 只有双方都带参数(长度、精度)时才比较参数
+func sameColumnType(expected, actual string) bool {
+	eBase, eParams := normalizeColumnType(expected)
+	aBase, aParams := normalizeColumnType(actual)
+
+	if eBase != aBase {
+		return false
+	}
+
+	return eParams == "" || aParams == "" || eParams == aParams
+}
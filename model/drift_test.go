@@ -0,0 +1,130 @@
+//
+// FilePath    : go-utils\model\drift_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 结构漂移检测测试
+//
+
+package model
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+type driftUser struct {
+	ID    uint64 `gorm:"primaryKey"`
+	Name  string `gorm:"size:64;index:idx_name"`
+	Email string `gorm:"size:128;uniqueIndex:idx_email"`
+	Age   int
+}
+
+func (driftUser) TableName() string { return "drift_user" }
+
+func driftColumn(name, columnType string) gorm.ColumnType {
+	return migrator.ColumnType{
+		NameValue:       sql.NullString{String: name, Valid: true},
+		DataTypeValue:   sql.NullString{String: columnType, Valid: true},
+		ColumnTypeValue: sql.NullString{String: columnType, Valid: true},
+	}
+}
+
+func TestDiffColumnsAndIndexes(t *testing.T) {
+	sch, err := schema.Parse(&driftUser{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("schema.Parse() error = %v", err)
+	}
+
+	dataTypeOf := func(f *schema.Field) string {
+		switch f.DBName {
+		case "id":
+			return "bigint unsigned"
+		case "age":
+			return "bigint"
+		default:
+			return "varchar(" + map[string]string{"name": "64", "email": "128"}[f.DBName] + ")"
+		}
+	}
+
+	columns := []gorm.ColumnType{
+		driftColumn("id", "BIGINT UNSIGNED"),
+		driftColumn("name", "varchar(32)"),
+		driftColumn("email", "character varying(128)"),
+		driftColumn("legacy", "text"),
+	}
+
+	got := diffColumns(sch, dataTypeOf, columns)
+	want := []Drift{
+		{Kind: DriftTypeMismatch, Table: "drift_user", Name: "name"},
+		{Kind: DriftMissingColumn, Table: "drift_user", Name: "age"},
+		{Kind: DriftExtraColumn, Table: "drift_user", Name: "legacy"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("diffColumns() = %v, want %d drifts", got, len(want))
+	}
+
+	for i := range want {
+		if got[i].Kind != want[i].Kind || got[i].Name != want[i].Name {
+			t.Errorf("diffColumns()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	indexes := []gorm.Index{
+		migrator.Index{NameValue: "PRIMARY", ColumnList: []string{"id"}, PrimaryKeyValue: sql.NullBool{Bool: true, Valid: true}},
+		migrator.Index{NameValue: "idx_email", ColumnList: []string{"email"}},
+		migrator.Index{NameValue: "idx_old", ColumnList: []string{"legacy"}},
+	}
+
+	gotIdx := diffIndexes(sch, indexes)
+	wantIdx := []Drift{
+		{Kind: DriftMissingIndex, Name: "idx_name"},
+		{Kind: DriftIndexMismatch, Name: "idx_email"},
+		{Kind: DriftExtraIndex, Name: "idx_old"},
+	}
+
+	if len(gotIdx) != len(wantIdx) {
+		t.Fatalf("diffIndexes() = %v, want %d drifts", gotIdx, len(wantIdx))
+	}
+
+	for _, w := range wantIdx {
+		found := false
+
+		for _, g := range gotIdx {
+			if g.Kind == w.Kind && g.Name == w.Name {
+				found = true
+			}
+		}
+
+		if !found {
+			t.Errorf("diffIndexes() missing %v in %v", w, gotIdx)
+		}
+	}
+}
+
+func TestSameColumnType(t *testing.T) {
+	tests := []struct {
+		expected, actual string
+		want             bool
+	}{
+		{"varchar(191)", "VARCHAR(191)", true},
+		{"varchar(191)", "varchar(255)", false},
+		{"int", "int(11)", true},
+		{"boolean", "tinyint(1)", true},
+		{"decimal(10,2)", "numeric(10, 2)", true},
+		{"bigint unsigned", "bigint", false},
+		{"timestamptz", "timestamp with time zone", true},
+	}
+
+	for _, tt := range tests {
+		if got := sameColumnType(tt.expected, tt.actual); got != tt.want {
+			t.Errorf("sameColumnType(%q, %q) = %v, want %v", tt.expected, tt.actual, got, tt.want)
+		}
+	}
+}
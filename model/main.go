@@ -9,6 +9,11 @@
 package model
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
 	"sync"
 )
 
@@ -19,21 +24,122 @@ type Tabler interface {
 	TableName() string // 表名
 }
 
+// ModelInfo 注册的模型信息
+type ModelInfo struct {
+	TableName string       // 表名
+	Type      reflect.Type // 模型结构体类型(已解引用指针)
+	Model     Tabler       // 注册的模型实例
+	Source    string       // 注册位置, 格式为 文件:行号
+}
+
+// ErrDuplicateModel 重复注册模型, 表名或模型类型已注册
+var ErrDuplicateModel = errors.New("模型重复注册")
+
 // 定义模型层相关变量
 var (
-	models []any      // 注册的模型切片
-	mu     sync.Mutex // 互斥锁 (保证并发安全)
+	models     []ModelInfo        // 注册的模型, 按注册顺序
+	modelIndex = map[string]int{} // 表名 => models 下标
+	mu         sync.RWMutex       // 读写锁 (保证并发安全)
 )
 
-// GetModels 获取所有注册的模型
+// GetModels 获取所有注册的模型, 按注册顺序
 func GetModels() []any {
-	return models
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := make([]any, len(models))
+	for i, info := range models {
+		result[i] = info.Model
+	}
+
+	return result
+}
+
+// GetModelInfos 获取所有注册的模型信息, 按注册顺序
+func GetModelInfos() []ModelInfo {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return slices.Clone(models)
+}
+
+// GetModelByTableName 根据表名获取注册的模型信息
+func GetModelByTableName(tableName string) (ModelInfo, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	i, ok := modelIndex[tableName]
+	if !ok {
+		return ModelInfo{}, false
+	}
+
+	return models[i], true
+}
+
+// ListTableNames 获取所有注册模型的表名, 按字母顺序
+func ListTableNames() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(models))
+	for _, info := range models {
+		names = append(names, info.TableName)
+	}
+
+	slices.Sort(names)
+
+	return names
 }
 
 // RegisterModel 将模型注册到模型切片中, 用于后续的初始化.
+// 表名或模型类型重复注册时 panic, 错误信息包含两次注册的位置; 需要处理错误时使用 TryRegisterModel.
 func RegisterModel(model Tabler) {
+	if err := registerModel(model, 2); err != nil {
+		panic(err)
+	}
+}
+
+// TryRegisterModel 将模型注册到模型切片中, 表名或模型类型重复注册时返回 ErrDuplicateModel
+func TryRegisterModel(model Tabler) error {
+	return registerModel(model, 2)
+}
+
+// registerModel 注册模型, skip 为 runtime.Caller 跳过的栈帧数, 用于记录注册位置
+func registerModel(model Tabler, skip int) error {
+	if model == nil {
+		return fmt.Errorf("注册的模型不能为 nil")
+	}
+
+	typ := reflect.TypeOf(model)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	source := "unknown"
+	if _, file, line, ok := runtime.Caller(skip); ok {
+		source = fmt.Sprintf("%s:%d", file, line)
+	}
+
+	info := ModelInfo{TableName: model.TableName(), Type: typ, Model: model, Source: source}
+
 	mu.Lock()         // 加锁
 	defer mu.Unlock() // 解锁
 
-	models = append(models, model) // 注册模型
+	if i, ok := modelIndex[info.TableName]; ok {
+		return fmt.Errorf("%w: 表名 '%s' 已由 %s 在 %s 注册, 重复注册位置 %s",
+			ErrDuplicateModel, info.TableName, models[i].Type, models[i].Source, source)
+	}
+
+	// 虚拟模型共用同一类型, 只按表名判断重复
+	for _, registered := range models {
+		if registered.Type == typ && typ != reflect.TypeFor[VirtualModel]() {
+			return fmt.Errorf("%w: 模型 %s 已在 %s 注册(表名 '%s'), 重复注册位置 %s",
+				ErrDuplicateModel, typ, registered.Source, registered.TableName, source)
+		}
+	}
+
+	modelIndex[info.TableName] = len(models)
+	models = append(models, info) // 注册模型
+
+	return nil
 }
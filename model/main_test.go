@@ -0,0 +1,78 @@
+//
+// FilePath    : go-utils\model\main_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 模型注册表单测
+//
+
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type registryUser struct {
+	ID uint64 `gorm:"column:id"`
+}
+
+func (registryUser) TableName() string { return "registry_users" }
+
+type registryOrder struct {
+	ID uint64 `gorm:"column:id"`
+}
+
+func (registryOrder) TableName() string { return "registry_orders" }
+
+type registryUserAlias struct {
+	ID uint64 `gorm:"column:id"`
+}
+
+func (registryUserAlias) TableName() string { return "registry_users" }
+
+// resetModels 清空注册表, 测试结束后恢复
+func resetModels(t *testing.T) {
+	mu.Lock()
+	savedModels, savedIndex := models, modelIndex
+	models, modelIndex = nil, map[string]int{}
+	mu.Unlock()
+
+	t.Cleanup(func() {
+		mu.Lock()
+		models, modelIndex = savedModels, savedIndex
+		mu.Unlock()
+	})
+}
+
+func TestModelRegistry(t *testing.T) {
+	resetModels(t)
+
+	RegisterModel(&registryUser{})
+	RegisterModel(&registryOrder{})
+
+	assert.Len(t, GetModels(), 2)
+	assert.Equal(t, []string{"registry_orders", "registry_users"}, ListTableNames())
+
+	info, ok := GetModelByTableName("registry_users")
+	assert.True(t, ok)
+	assert.Equal(t, reflect.TypeFor[registryUser](), info.Type)
+	assert.True(t, strings.Contains(info.Source, "main_test.go:"), info.Source)
+
+	_, ok = GetModelByTableName("not_exists")
+	assert.False(t, ok)
+
+	// 表名重复
+	err := TryRegisterModel(&registryUserAlias{})
+	assert.ErrorIs(t, err, ErrDuplicateModel)
+	assert.Contains(t, err.Error(), info.Source)
+
+	// 类型重复
+	assert.ErrorIs(t, TryRegisterModel(registryOrder{}), ErrDuplicateModel)
+	assert.Panics(t, func() { RegisterModel(&registryOrder{}) })
+
+	assert.Len(t, GetModelInfos(), 2)
+}
@@ -9,10 +9,15 @@
 package model
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
 )
 
 // CustomAutoMigrate 自定义迁移
@@ -30,3 +35,247 @@ func CustomAutoMigrate(db *gorm.DB) (err error) {
 
 	return nil
 }
+
+// migrateConfig 迁移配置
+type migrateConfig struct {
+	dryRun bool  // 是否只生成 DDL 不执行
+	models []any // 需要迁移的模型, 为空时使用已注册的模型
+}
+
+// MigrateOption 定义迁移的可选配置函数类型
+type MigrateOption func(*migrateConfig)
+
+// WithDryRun 设置是否只生成 DDL 不执行, 生成的 DDL 会打印到标准输出并记录在迁移报告中
+func WithDryRun(dryRun bool) MigrateOption {
+	return func(c *migrateConfig) {
+		c.dryRun = dryRun
+	}
+}
+
+// WithMigrateModels 设置需要迁移的模型, 默认使用 GetModels() 获取已注册的模型
+func WithMigrateModels(models ...any) MigrateOption {
+	return func(c *migrateConfig) {
+		c.models = models
+	}
+}
+
+// MigrationReport 迁移报告
+type MigrationReport struct {
+	DryRun  bool     // 是否为 dry-run
+	Order   []string // 迁移顺序(表名), 被外键引用的表在前
+	Changes []Drift  // 迁移前检测到的变更: 新增的表、列、索引及类型变化
+	DDL     []string // 执行的 DDL, dry-run 时为将要执行的 DDL
+}
+
+// String 实现 fmt.Stringer 接口, 便于运维审核
+func (r *MigrationReport) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "dry-run: %t\n", r.DryRun)
+	fmt.Fprintf(&sb, "order: %s\n", strings.Join(r.Order, " -> "))
+
+	sb.WriteString("changes:\n")
+
+	for _, d := range r.Changes {
+		sb.WriteString("  " + d.String() + "\n")
+	}
+
+	sb.WriteString("ddl:\n")
+
+	for _, ddl := range r.DDL {
+		sb.WriteString("  " + ddl + ";\n")
+	}
+
+	return sb.String()
+}
+
+// migrateChangeKinds AutoMigrate 会处理的漂移类别, 多余的列和索引不会被删除, 不计入变更
+var migrateChangeKinds = map[DriftKind]bool{
+	DriftMissingTable:  true,
+	DriftMissingColumn: true,
+	DriftTypeMismatch:  true,
+	DriftMissingIndex:  true,
+	DriftIndexMismatch: true,
+}
+
+// Migrate 按外键依赖顺序迁移模型, 被引用的表先迁移.
+// 迁移前使用 DetectDrift 检测新增和变化的列并记录在报告中, 迁移时记录执行的 DDL;
+// dry-run 模式下只查询数据库结构, 不执行 DDL, 可在生产环境执行前审核.
+//   - db: 数据库连接
+//   - opts: 可选参数, 例如 WithDryRun(true)
+func Migrate(db *gorm.DB, opts ...MigrateOption) (*MigrationReport, error) {
+	cfg := &migrateConfig{}
+	for _, apply := range opts {
+		apply(cfg)
+	}
+
+	models := cfg.models
+	if len(models) == 0 {
+		models = GetModels()
+	}
+
+	ordered, err := SortModelsByDependency(db, models)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MigrationReport{DryRun: cfg.dryRun}
+
+	for _, m := range ordered {
+		report.Order = append(report.Order, tableNameOf(db, m))
+	}
+
+	drift, err := DetectDrift(db, ordered)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range drift.Drifts {
+		if migrateChangeKinds[d.Kind] {
+			report.Changes = append(report.Changes, d)
+		}
+	}
+
+	// 记录 DDL, dry-run 时 gorm 只查询数据库结构, 不执行 DDL
+	tx := db.Session(&gorm.Session{
+		DryRun: cfg.dryRun,
+		Logger: &ddlLogger{Interface: db.Logger, ddl: &report.DDL},
+	})
+
+	for _, m := range ordered {
+		if err = tx.AutoMigrate(m); err != nil {
+			return report, fmt.Errorf("迁移模型 %T 失败: %w", m, err)
+		}
+	}
+
+	zap.L().Info("数据库迁移完成",
+		zap.Bool("dryRun", cfg.dryRun),
+		zap.Strings("order", report.Order),
+		zap.Int("changes", len(report.Changes)),
+		zap.Int("ddl", len(report.DDL)),
+	)
+
+	return report, nil
+}
+
+// SortModelsByDependency 按外键依赖排序模型, 被引用(belongs to)或拥有(has one/has many)关系的表在前,
+// 没有依赖关系的模型保持原有顺序, 存在循环依赖时返回错误.
+//   - db: 数据库连接, 用于解析模型
+//   - models: 模型列表
+func SortModelsByDependency(db *gorm.DB, models []any) ([]any, error) {
+	schemas := make([]*schema.Schema, len(models))
+	index := make(map[string]int, len(models)) // 表名 -> 模型下标
+
+	for i, m := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return nil, fmt.Errorf("解析模型 %T 失败: %w", m, err)
+		}
+
+		schemas[i] = stmt.Schema
+		index[stmt.Schema.Table] = i
+	}
+
+	// deps[i] 模型 i 依赖的模型下标
+	deps := make([]map[int]bool, len(models))
+	for i := range deps {
+		deps[i] = make(map[int]bool)
+	}
+
+	for i, sch := range schemas {
+		for _, rel := range sch.Relationships.Relations {
+			j, ok := index[rel.FieldSchema.Table]
+			if !ok || i == j {
+				continue
+			}
+
+			switch rel.Type {
+			case schema.BelongsTo:
+				// 外键在当前表, 依赖被引用的表
+				deps[i][j] = true
+			case schema.HasOne, schema.HasMany:
+				// 外键在关联表, 关联表依赖当前表
+				deps[j][i] = true
+			}
+		}
+	}
+
+	// 稳定的拓扑排序: 每次选择原有顺序中第一个依赖都已排好的模型
+	ordered := make([]any, 0, len(models))
+	placed := make([]bool, len(models))
+
+	for len(ordered) < len(models) {
+		next := -1
+
+		for i := range models {
+			if placed[i] {
+				continue
+			}
+
+			ready := true
+
+			for j := range deps[i] {
+				if !placed[j] {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				next = i
+				break
+			}
+		}
+
+		if next < 0 {
+			var cycle []string
+
+			for i, sch := range schemas {
+				if !placed[i] {
+					cycle = append(cycle, sch.Table)
+				}
+			}
+
+			return nil, fmt.Errorf("模型存在循环依赖: %s", strings.Join(cycle, ", "))
+		}
+
+		placed[next] = true
+		ordered = append(ordered, models[next])
+	}
+
+	return ordered, nil
+}
+
+// tableNameOf 获取模型的表名
+func tableNameOf(db *gorm.DB, m any) string {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(m); err != nil {
+		return fmt.Sprintf("%T", m)
+	}
+
+	return stmt.Schema.Table
+}
+
+// ddlLogger 记录 DDL 的 gorm 日志
+type ddlLogger struct {
+	logger.Interface
+	ddl *[]string
+}
+
+// LogMode 实现 logger.Interface 接口 LogMode 方法
+func (l *ddlLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &ddlLogger{Interface: l.Interface.LogMode(level), ddl: l.ddl}
+}
+
+// Trace 实现 logger.Interface 接口 Trace 方法, 记录 CREATE、ALTER、DROP 等 DDL 语句
+func (l *ddlLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	sql, _ := fc()
+
+	keyword, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	switch strings.ToUpper(keyword) {
+	case "CREATE", "ALTER", "DROP", "COMMENT", "RENAME":
+		*l.ddl = append(*l.ddl, sql)
+	}
+
+	l.Interface.Trace(ctx, begin, fc, err)
+}
@@ -0,0 +1,80 @@
+//
+// FilePath    : go-utils\model\migrate_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 数据库迁移单测
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type migUser struct {
+	ID        uint64       `gorm:"primaryKey"`
+	Addresses []migAddress `gorm:"foreignKey:UserID"`
+}
+
+func (migUser) TableName() string { return "mig_user" }
+
+type migAddress struct {
+	ID     uint64 `gorm:"primaryKey"`
+	UserID uint64
+}
+
+func (migAddress) TableName() string { return "mig_address" }
+
+type migOrder struct {
+	ID     uint64 `gorm:"primaryKey"`
+	UserID uint64
+	User   migUser
+}
+
+func (migOrder) TableName() string { return "mig_order" }
+
+type migLog struct {
+	ID uint64 `gorm:"primaryKey"`
+}
+
+func (migLog) TableName() string { return "mig_log" }
+
+type migA struct {
+	ID  uint64 `gorm:"primaryKey"`
+	BID uint64
+	B   *migB
+}
+
+func (migA) TableName() string { return "mig_a" }
+
+type migB struct {
+	ID  uint64 `gorm:"primaryKey"`
+	AID uint64
+	A   *migA
+}
+
+func (migB) TableName() string { return "mig_b" }
+
+func TestSortModelsByDependency(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{})
+	assert.NoError(t, err)
+
+	ordered, err := SortModelsByDependency(db, []any{&migOrder{}, &migLog{}, &migAddress{}, &migUser{}})
+	assert.NoError(t, err)
+
+	var tables []string
+	for _, m := range ordered {
+		tables = append(tables, m.(Tabler).TableName())
+	}
+
+	assert.Equal(t, []string{"mig_log", "mig_user", "mig_order", "mig_address"}, tables)
+
+	// 循环依赖
+	_, err = SortModelsByDependency(db, []any{&migA{}, &migB{}})
+	assert.ErrorContains(t, err, "mig_a, mig_b")
+}
@@ -0,0 +1,428 @@
+//
+// FilePath    : go-utils\model\openapi.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 根据模型和 DTO 的 json、校验、gorm comment 标签生成 OpenAPI 组件 schema, 保持接口文档与结构体一致
+//
+
+package model
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 生成 schema 使用的标签
+const (
+	bindingTag     = "binding"     // gin 校验标签
+	validateTag    = "validate"    // validator 校验标签
+	exampleTag     = "example"     // 示例值, 与 swag 一致
+	descriptionTag = "description" // 字段描述, 优先于 gorm 的 comment
+)
+
+// Schema OpenAPI 3.0 的 schema 对象, 只包含由结构体可以推导出的字段
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Example              any                `json:"example,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     bool               `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int64             `json:"minLength,omitempty"`
+	MaxLength            *int64             `json:"maxLength,omitempty"`
+	MinItems             *int64             `json:"minItems,omitempty"`
+	MaxItems             *int64             `json:"maxItems,omitempty"`
+}
+
+// componentRefPrefix 组件 schema 引用的前缀
+const componentRefPrefix = "#/components/schemas/"
+
+// 特殊处理的类型
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	deletedAtType     = reflect.TypeFor[gorm.DeletedAt]()
+	durationType      = reflect.TypeFor[time.Duration]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// SchemaGenerator OpenAPI 组件 schema 生成器, 结构体生成为组件并通过 $ref 引用, 支持嵌套和循环引用.
+// 字段名称取 json 标签, 描述取 description 标签或 gorm 的 comment, 示例取 example 标签,
+// binding/validate 标签中的 required、min、max、len、oneof、email 等规则转换为对应的约束.
+type SchemaGenerator struct {
+	schemas map[string]*Schema      // 组件名称 => schema
+	names   map[reflect.Type]string // 结构体类型 => 组件名称
+}
+
+// NewSchemaGenerator 创建 OpenAPI 组件 schema 生成器
+func NewSchemaGenerator() *SchemaGenerator {
+	return &SchemaGenerator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// GenerateSchemas 为所有注册的模型和 dtos 生成 OpenAPI 组件 schema, 返回组件名称 => schema
+//   - dtos: 需要生成文档的 DTO, 例如 res.Response[UserDTO]{}
+func GenerateSchemas(dtos ...any) map[string]*Schema {
+	g := NewSchemaGenerator()
+
+	for _, m := range GetModels() {
+		g.Add(m)
+	}
+
+	for _, dto := range dtos {
+		g.Add(dto)
+	}
+
+	return g.Schemas()
+}
+
+// Add 为 v 的类型生成 schema, 结构体注册为组件并返回引用, 其他类型返回内联 schema
+//   - v: 模型或 DTO 的实例, 可以是指针
+func (g *SchemaGenerator) Add(v any) *Schema {
+	if v == nil {
+		return &Schema{}
+	}
+
+	return g.schemaOf(reflect.TypeOf(v))
+}
+
+// Schemas 获取已生成的组件 schema, 组件名称 => schema
+func (g *SchemaGenerator) Schemas() map[string]*Schema {
+	return g.schemas
+}
+
+// MarshalComponents 生成 {"components":{"schemas":{...}}} 格式的 JSON, 可合并到 OpenAPI 文档中
+func (g *SchemaGenerator) MarshalComponents() ([]byte, error) {
+	return json.MarshalIndent(map[string]any{
+		"components": map[string]any{"schemas": g.schemas},
+	}, "", "  ")
+}
+
+// schemaOf 生成类型 t 的 schema
+func (g *SchemaGenerator) schemaOf(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+
+	s := g.baseSchemaOf(t)
+	if nullable && s.Ref == "" {
+		s.Nullable = true
+	}
+
+	return s
+}
+
+// baseSchemaOf 生成非指针类型 t 的 schema
+func (g *SchemaGenerator) baseSchemaOf(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case deletedAtType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: true}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "纳秒"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	// 实现了 TextMarshaler 的类型(例如 decimal、netip.Addr)序列化为字符串
+	if t.Kind() != reflect.String && (t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return &Schema{Ref: componentRefPrefix + g.component(t)}
+	default:
+		// interface、func 等无法推导的类型使用空 schema, 表示任意值
+		return &Schema{}
+	}
+}
+
+// component 注册结构体组件并返回组件名称, 先登记名称再生成属性, 以支持循环引用
+func (g *SchemaGenerator) component(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	name := componentName(t)
+	for i := 2; g.schemas[name] != nil; i++ {
+		name = fmt.Sprintf("%s%d", componentName(t), i)
+	}
+
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.names[t] = name
+	g.schemas[name] = s
+
+	g.collectProperties(t, s)
+
+	return name
+}
+
+// collectProperties 收集结构体 t 的字段到 s, 匿名嵌入且没有 json 名称的结构体字段展开到父结构体, 与 encoding/json 一致
+func (g *SchemaGenerator) collectProperties(t reflect.Type, s *Schema) {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+
+		// json:"-" 忽略字段, json:"-," 表示名称为 -
+		jsonName, opts, hasOpts := strings.Cut(sf.Tag.Get(jsonTag), ",")
+		if jsonName == "-" && !hasOpts {
+			continue
+		}
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		// 展开匿名嵌入的结构体
+		if sf.Anonymous && jsonName == "" && ft.Kind() == reflect.Struct {
+			g.collectProperties(ft, s)
+			continue
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		if jsonName == "" {
+			jsonName = sf.Name
+		}
+
+		prop := g.schemaOf(sf.Type)
+
+		// json 的 string 选项将数字和布尔值序列化为字符串
+		if hasJSONOption(opts, "string") && (prop.Type == "integer" || prop.Type == "number" || prop.Type == "boolean") {
+			prop = &Schema{Type: "string", Format: prop.Format, Nullable: prop.Nullable}
+		}
+
+		prop = withFieldInfo(prop, sf)
+
+		if applyValidation(prop, validationRules(sf)) {
+			s.Required = append(s.Required, jsonName)
+		}
+
+		s.Properties[jsonName] = prop
+	}
+}
+
+// withFieldInfo 为字段 schema 附加描述和示例, OpenAPI 3.0 中 $ref 不能有同级字段, 引用类型不附加
+func withFieldInfo(prop *Schema, sf reflect.StructField) *Schema {
+	if prop.Ref != "" {
+		return prop
+	}
+
+	prop.Description = sf.Tag.Get(descriptionTag)
+	if prop.Description == "" {
+		prop.Description = tagValue(sf.Tag.Get(gormTag), "comment:", ";")
+	}
+
+	if example, ok := sf.Tag.Lookup(exampleTag); ok {
+		prop.Example = parseExample(prop.Type, example)
+	}
+
+	return prop
+}
+
+// hasJSONOption json 标签的选项中是否包含 option
+func hasJSONOption(opts, option string) bool {
+	for opt := range strings.SplitSeq(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validationRules 获取字段的校验规则, 合并 binding 和 validate 标签, 忽略 dive 之后作用于元素的规则
+func validationRules(sf reflect.StructField) []string {
+	var rules []string
+
+	for _, tag := range []string{bindingTag, validateTag} {
+		for rule := range strings.SplitSeq(sf.Tag.Get(tag), ",") {
+			rule = strings.TrimSpace(rule)
+			if rule == "dive" {
+				break
+			}
+
+			if rule != "" {
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	return rules
+}
+
+// ruleFormats 校验规则与 OpenAPI format 的对应关系
+var ruleFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"ip":       "ip",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+	"datetime": "date-time",
+}
+
+// applyValidation 将校验规则转换为 schema 的约束, 返回字段是否必填
+func applyValidation(prop *Schema, rules []string) bool {
+	required := false
+
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			required = true
+		case "min", "gte":
+			setBound(prop, param, true, false)
+		case "max", "lte":
+			setBound(prop, param, false, false)
+		case "gt":
+			setBound(prop, param, true, true)
+		case "lt":
+			setBound(prop, param, false, true)
+		case "len":
+			setBound(prop, param, true, false)
+			setBound(prop, param, false, false)
+		case "oneof":
+			for value := range strings.FieldsSeq(param) {
+				prop.Enum = append(prop.Enum, parseExample(prop.Type, value))
+			}
+		default:
+			if format, ok := ruleFormats[name]; ok && prop.Type == "string" && prop.Format == "" {
+				prop.Format = format
+			}
+		}
+	}
+
+	return required
+}
+
+// setBound 根据 schema 类型设置长度、元素个数或数值范围约束
+//   - lower: 是否为下限
+//   - exclusive: 是否不包含边界值, 只对数值生效
+func setBound(prop *Schema, param string, lower, exclusive bool) {
+	switch prop.Type {
+	case "integer", "number":
+		v, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+
+		if lower {
+			prop.Minimum, prop.ExclusiveMinimum = &v, exclusive
+		} else {
+			prop.Maximum, prop.ExclusiveMaximum = &v, exclusive
+		}
+	case "string", "array":
+		n, err := strconv.ParseInt(param, 10, 64)
+		if err != nil {
+			return
+		}
+
+		// 长度不包含边界时转换为包含边界的整数
+		if exclusive && lower {
+			n++
+		} else if exclusive {
+			n--
+		}
+
+		switch {
+		case prop.Type == "string" && lower:
+			prop.MinLength = &n
+		case prop.Type == "string":
+			prop.MaxLength = &n
+		case lower:
+			prop.MinItems = &n
+		default:
+			prop.MaxItems = &n
+		}
+	}
+}
+
+// parseExample 按 schema 类型解析示例值, 解析失败时使用原字符串
+func parseExample(typ, value string) any {
+	switch typ {
+	case "integer":
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+
+	return value
+}
+
+// qualifiedNamePattern 类型名称中的包路径限定, 例如 github.com/x/pkg.
+var qualifiedNamePattern = regexp.MustCompile(`[\w./-]*\.`)
+
+// invalidComponentChars OpenAPI 组件名称不允许的字符
+var invalidComponentChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// componentName 生成组件名称, 泛型类型 Response[pkg.User] 转换为 Response_User, 匿名结构体为 Anonymous
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		return "Anonymous"
+	}
+
+	name = qualifiedNamePattern.ReplaceAllString(name, "")
+	name = invalidComponentChars.ReplaceAllString(name, "_")
+
+	return strings.Trim(name, "_")
+}
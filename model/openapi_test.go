@@ -0,0 +1,96 @@
+//
+// FilePath    : go-utils\model\openapi_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : OpenAPI 组件 schema 生成单测
+//
+
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type openAPIUser struct {
+	BaseModel
+	Name     string            `gorm:"column:name;comment:用户名" json:"name" binding:"required,min=2,max=20" example:"张三"`
+	Email    string            `json:"email,omitempty" validate:"omitempty,email"`
+	Age      int               `json:"age" binding:"gte=0,lt=150" example:"18"`
+	Role     string            `json:"role" binding:"oneof=admin user"`
+	Tags     []string          `json:"tags" binding:"max=5,dive,min=1"`
+	Extra    map[string]any    `json:"extra"`
+	Birthday *time.Time        `json:"birthday"`
+	Manager  *openAPIUser      `json:"manager"`
+	Groups   []openAPIGroup    `json:"groups"`
+	Secret   string            `json:"-"`
+	Attrs    map[string]string `json:"attrs" description:"扩展属性"`
+	internal string
+}
+
+type openAPIGroup struct {
+	ID uint64 `json:"id,string"`
+}
+
+type openAPIPage[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+func TestSchemaGenerator(t *testing.T) {
+	g := NewSchemaGenerator()
+
+	ref := g.Add(&openAPIPage[openAPIUser]{})
+	assert.Equal(t, "#/components/schemas/openAPIPage_openAPIUser", ref.Ref)
+
+	schemas := g.Schemas()
+	require.Contains(t, schemas, "openAPIUser")
+	require.Contains(t, schemas, "openAPIGroup")
+
+	user := schemas["openAPIUser"]
+
+	// 嵌入的 BaseModel 字段展开
+	assert.Equal(t, &Schema{Type: "string", Format: "int64", Description: "自增ID", Example: "1234567890"}, user.Properties["id"])
+	assert.Equal(t, "date-time", user.Properties["deleted_at"].Format)
+	assert.True(t, user.Properties["deleted_at"].Nullable)
+
+	name := user.Properties["name"]
+	assert.Equal(t, "用户名", name.Description)
+	assert.Equal(t, "张三", name.Example)
+	assert.Equal(t, int64(2), *name.MinLength)
+	assert.Equal(t, int64(20), *name.MaxLength)
+	assert.Equal(t, []string{"name"}, user.Required)
+
+	assert.Equal(t, "email", user.Properties["email"].Format)
+
+	age := user.Properties["age"]
+	assert.Equal(t, int64(18), age.Example)
+	assert.InDelta(t, 0, *age.Minimum, 0)
+	assert.InDelta(t, 150, *age.Maximum, 0)
+	assert.True(t, age.ExclusiveMaximum)
+
+	assert.Equal(t, []any{"admin", "user"}, user.Properties["role"].Enum)
+	assert.Equal(t, int64(5), *user.Properties["tags"].MaxItems)
+	assert.Nil(t, user.Properties["tags"].Items.MinLength)
+	assert.Equal(t, &Schema{}, user.Properties["extra"].AdditionalProperties)
+	assert.True(t, user.Properties["birthday"].Nullable)
+	assert.Equal(t, "#/components/schemas/openAPIUser", user.Properties["manager"].Ref)
+	assert.Equal(t, "#/components/schemas/openAPIGroup", user.Properties["groups"].Items.Ref)
+	assert.Equal(t, "扩展属性", user.Properties["attrs"].Description)
+	assert.NotContains(t, user.Properties, "Secret")
+	assert.NotContains(t, user.Properties, "internal")
+
+	assert.Equal(t, "string", schemas["openAPIGroup"].Properties["id"].Type)
+
+	data, err := g.MarshalComponents()
+	require.NoError(t, err)
+
+	var doc map[string]map[string]map[string]any
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Contains(t, doc["components"]["schemas"], "openAPIPage_openAPIUser")
+}
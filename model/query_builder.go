@@ -0,0 +1,185 @@
+//
+// FilePath    : go-utils\model\query_builder.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 使用字段指针构建查询条件, 字段重命名时查询条件同步变化
+//
+
+package model
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// QueryBuilder 查询条件构建器, 使用字段指针引用列, 生成 gorm 兼容的条件语句和参数.
+// 条件之间使用 AND 连接, 字段解析失败时记录第一个错误, 在 Build 时返回.
+type QueryBuilder struct {
+	model Tabler   // 表模型指针, 虚拟模型时字段为字段名
+	opts  []Option // 列名选项, 例如 WithTableName(true)
+	conds []string // 条件语句
+	args  []any    // 条件参数
+	err   error    // 第一个错误
+}
+
+// NewQueryBuilder 创建查询条件构建器
+//   - modelTar: 表模型指针, 字段指针需要指向该实例的字段
+//   - opts: 列名选项, 参见 GetColumnName
+func NewQueryBuilder(modelTar Tabler, opts ...Option) *QueryBuilder {
+	return &QueryBuilder{model: modelTar, opts: opts}
+}
+
+// add 添加条件, 将 sql 中的 {col} 替换为字段对应的列名
+func (b *QueryBuilder) add(fieldPtr any, sql string, args ...any) *QueryBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	col, err := GetColumnName(b.model, fieldPtr, b.opts...)
+	if err != nil {
+		b.err = err
+		return b
+	}
+
+	b.conds = append(b.conds, strings.ReplaceAll(sql, "{col}", col))
+	b.args = append(b.args, args...)
+
+	return b
+}
+
+// Eq 等于, col = value
+func (b *QueryBuilder) Eq(fieldPtr, value any) *QueryBuilder {
+	return b.add(fieldPtr, "{col} = ?", value)
+}
+
+// Ne 不等于, col <> value
+func (b *QueryBuilder) Ne(fieldPtr, value any) *QueryBuilder {
+	return b.add(fieldPtr, "{col} <> ?", value)
+}
+
+// Gt 大于, col > value
+func (b *QueryBuilder) Gt(fieldPtr, value any) *QueryBuilder {
+	return b.add(fieldPtr, "{col} > ?", value)
+}
+
+// Gte 大于等于, col >= value
+func (b *QueryBuilder) Gte(fieldPtr, value any) *QueryBuilder {
+	return b.add(fieldPtr, "{col} >= ?", value)
+}
+
+// Lt 小于, col < value
+func (b *QueryBuilder) Lt(fieldPtr, value any) *QueryBuilder {
+	return b.add(fieldPtr, "{col} < ?", value)
+}
+
+// Lte 小于等于, col <= value
+func (b *QueryBuilder) Lte(fieldPtr, value any) *QueryBuilder {
+	return b.add(fieldPtr, "{col} <= ?", value)
+}
+
+// In 在列表中, col IN (values), values 为切片
+func (b *QueryBuilder) In(fieldPtr, values any) *QueryBuilder {
+	return b.add(fieldPtr, "{col} IN (?)", values)
+}
+
+// NotIn 不在列表中, col NOT IN (values), values 为切片
+func (b *QueryBuilder) NotIn(fieldPtr, values any) *QueryBuilder {
+	return b.add(fieldPtr, "{col} NOT IN (?)", values)
+}
+
+// Between 在区间内(包含边界), col BETWEEN start AND end
+func (b *QueryBuilder) Between(fieldPtr, start, end any) *QueryBuilder {
+	return b.add(fieldPtr, "{col} BETWEEN ? AND ?", start, end)
+}
+
+// Like 模糊匹配, col LIKE pattern, pattern 需要自行添加 % 通配符
+func (b *QueryBuilder) Like(fieldPtr any, pattern string) *QueryBuilder {
+	return b.add(fieldPtr, "{col} LIKE ?", pattern)
+}
+
+// Contains 包含 s, col LIKE %s%, s 中的通配符会被转义
+func (b *QueryBuilder) Contains(fieldPtr any, s string) *QueryBuilder {
+	return b.Like(fieldPtr, "%"+EscapeLike(s)+"%")
+}
+
+// IsNull 为空, col IS NULL
+func (b *QueryBuilder) IsNull(fieldPtr any) *QueryBuilder {
+	return b.add(fieldPtr, "{col} IS NULL")
+}
+
+// IsNotNull 不为空, col IS NOT NULL
+func (b *QueryBuilder) IsNotNull(fieldPtr any) *QueryBuilder {
+	return b.add(fieldPtr, "{col} IS NOT NULL")
+}
+
+// Or 添加一组 OR 条件, 每个构建器的条件作为一个整体, 例如 ((a = ? AND b = ?) OR (c = ?))
+func (b *QueryBuilder) Or(builders ...*QueryBuilder) *QueryBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	var (
+		groups []string
+		args   []any
+	)
+
+	for _, other := range builders {
+		query, otherArgs, err := other.Build()
+		if err != nil {
+			b.err = err
+			return b
+		}
+
+		if query == "" {
+			continue
+		}
+
+		groups = append(groups, "("+query+")")
+		args = append(args, otherArgs...)
+	}
+
+	if len(groups) == 0 {
+		return b
+	}
+
+	b.conds = append(b.conds, "("+strings.Join(groups, " OR ")+")")
+	b.args = append(b.args, args...)
+
+	return b
+}
+
+// Build 生成条件语句和参数, 可直接用于 db.Where(query, args...); 没有条件时 query 为空字符串
+func (b *QueryBuilder) Build() (string, []any, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	return strings.Join(b.conds, " AND "), b.args, nil
+}
+
+// Scope 生成 gorm 作用域函数, 用于 db.Scopes(...), 构建失败时将错误添加到 db
+func (b *QueryBuilder) Scope() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		query, args, err := b.Build()
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+
+		if query == "" {
+			return db
+		}
+
+		return db.Where(query, args...)
+	}
+}
+
+// likeEscaper LIKE 通配符转义
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike 转义 LIKE 语句中的通配符 % 和 _, 使用 \ 作为转义字符
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,69 @@
+//
+// FilePath    : go-utils\model\query_builder_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 查询条件构建器单测
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	m := &TestModel{}
+
+	query, args, err := NewQueryBuilder(m).
+		Eq(&m.Name, "jiaopengzi").
+		In(&m.ID, []uint64{1, 2}).
+		Between(&m.CreatedAt, "2026-01-01", "2026-12-31").
+		Contains(&m.Name, "50%_off").
+		IsNull(&m.DeletedAt).
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "name_gorm = ? AND id_gorm IN (?) AND created_at_gorm BETWEEN ? AND ? AND name_gorm LIKE ? AND deleted_at_gorm IS NULL", query)
+	assert.Equal(t, []any{"jiaopengzi", []uint64{1, 2}, "2026-01-01", "2026-12-31", `%50\%\_off%`}, args)
+
+	// OR 条件和表名前缀
+	query, args, err = NewQueryBuilder(m, WithTableName(true)).
+		Gt(&m.ID, 10).
+		Or(
+			NewQueryBuilder(m).Eq(&m.Name, "a").Ne(&m.ID, 1),
+			NewQueryBuilder(m).Like(&m.Name, "b%"),
+		).
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "test_models.id_gorm > ? AND ((name_gorm = ? AND id_gorm <> ?) OR (name_gorm LIKE ?))", query)
+	assert.Equal(t, []any{10, "a", 1, "b%"}, args)
+
+	// 字段不属于模型
+	other := &TestModel{}
+	_, _, err = NewQueryBuilder(m).Eq(&other.Name, "x").Lt(&m.ID, 1).Build()
+	assert.Error(t, err)
+
+	// 没有条件
+	query, args, err = NewQueryBuilder(m).Build()
+	assert.NoError(t, err)
+	assert.Empty(t, query)
+	assert.Empty(t, args)
+}
+
+func TestQueryBuilderScope(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	assert.NoError(t, err)
+
+	m := &TestModel{}
+
+	stmt := db.Table(m.TableName()).Scopes(NewQueryBuilder(m).Eq(&m.Name, "a").Lte(&m.ID, 5).Scope()).Find(&[]TestModel{}).Statement
+	assert.Equal(t, "SELECT * FROM `test_models` WHERE (name_gorm = ? AND id_gorm <= ?) AND `test_models`.`deleted_at_gorm` IS NULL", stmt.SQL.String())
+
+	other := &TestModel{}
+	err = db.Table(m.TableName()).Scopes(NewQueryBuilder(m).Eq(&other.Name, "a").Scope()).Find(&[]TestModel{}).Error
+	assert.Error(t, err)
+}
@@ -0,0 +1,254 @@
+//
+// FilePath    : go-utils\model\repository.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 基于 Tabler 和 gorm 的通用数据访问层, 减少手写 CRUD
+//
+
+package model
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/jiaopengzi/go-utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Repository 通用数据访问层, T 为模型结构体类型(非指针), 例如 Repository[User].
+// 模型包含 gorm.DeletedAt 字段时, 查询自动使用 DeleteAtIsNull 排除已删除的数据, 删除为软删除.
+type Repository[T Tabler] struct {
+	db         *gorm.DB
+	model      *T             // 模型实例, 用于解析字段指针
+	schema     *schema.Schema // 模型 schema
+	softDelete bool           // 是否软删除
+}
+
+// NewRepository 创建通用数据访问层
+//   - db: 数据库连接
+func NewRepository[T Tabler](db *gorm.DB) (*Repository[T], error) {
+	if kind := reflect.TypeFor[T]().Kind(); kind != reflect.Struct {
+		return nil, fmt.Errorf("模型类型 %s 必须是结构体, 实际为 %s", reflect.TypeFor[T](), kind)
+	}
+
+	model := new(T)
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, fmt.Errorf("解析模型 %T 失败: %w", model, err)
+	}
+
+	if stmt.Schema.PrioritizedPrimaryField == nil {
+		return nil, fmt.Errorf("模型 %T 没有主键", model)
+	}
+
+	softDelete := false
+
+	for _, field := range stmt.Schema.Fields {
+		if field.FieldType == reflect.TypeFor[gorm.DeletedAt]() {
+			softDelete = true
+			break
+		}
+	}
+
+	return &Repository[T]{db: db, model: model, schema: stmt.Schema, softDelete: softDelete}, nil
+}
+
+// Model 获取模型实例, 仅用于获取字段指针, 例如 m := repo.Model(); repo.Column(&m.Name)
+func (r *Repository[T]) Model() *T {
+	return r.model
+}
+
+// Column 获取字段对应的列名
+//   - field: Model() 返回实例的字段指针, 或者列名/字段名字符串(会校验是否为模型的列, 可用于前端传入的参数)
+func (r *Repository[T]) Column(field any) (string, error) {
+	if name, ok := field.(string); ok {
+		f := r.schema.LookUpField(name)
+		if f == nil || f.DBName == "" {
+			return "", fmt.Errorf("模型 %s 不存在列 %s", r.schema.Name, name)
+		}
+
+		return f.DBName, nil
+	}
+
+	return GetColumnName(any(r.model).(Tabler), field)
+}
+
+// Query 获取模型的查询, 已排除软删除的数据, 可在此基础上添加查询条件
+func (r *Repository[T]) Query(ctx context.Context) *gorm.DB {
+	// 使用 DeleteAtIsNull 排除软删除的数据, 不再叠加 gorm 默认的软删除条件
+	tx := r.db.WithContext(ctx).Unscoped().Table(r.schema.Table)
+	if r.softDelete {
+		tx = tx.Where(DeleteAtIsNull(any(r.model).(Tabler)))
+	}
+
+	return tx
+}
+
+// Where 创建模型的查询条件构建器, 字段指针需要指向 Model() 返回的实例, 用于 ListQuery.Conditions 等
+func (r *Repository[T]) Where() *QueryBuilder {
+	return NewQueryBuilder(any(r.model).(Tabler))
+}
+
+// primaryKeyEq 主键等于 id 的查询条件
+func (r *Repository[T]) primaryKeyEq(id any) clause.Eq {
+	return clause.Eq{Column: clause.Column{Name: r.schema.PrioritizedPrimaryField.DBName}, Value: id}
+}
+
+// Create 创建记录, 自增主键等数据库生成的值会回写到 entity
+func (r *Repository[T]) Create(ctx context.Context, entity *T) error {
+	return r.db.WithContext(ctx).Create(entity).Error
+}
+
+// GetByID 根据主键获取记录, 记录不存在时返回 gorm.ErrRecordNotFound
+func (r *Repository[T]) GetByID(ctx context.Context, id any) (*T, error) {
+	var entity T
+
+	if err := r.Query(ctx).Where(r.primaryKeyEq(id)).Take(&entity).Error; err != nil {
+		return nil, err
+	}
+
+	return &entity, nil
+}
+
+// Update 根据 entity 的主键更新记录
+//   - entity: 需要更新的记录, 主键不能为空
+//   - fieldPtrs: entity 的字段指针, 只更新这些字段(包括零值); 为空时更新所有非零值字段
+func (r *Repository[T]) Update(ctx context.Context, entity *T, fieldPtrs ...any) error {
+	tx := r.db.WithContext(ctx).Model(entity)
+
+	if len(fieldPtrs) > 0 {
+		columns, err := GetColumnNames(any(entity).(Tabler), fieldPtrs)
+		if err != nil {
+			return err
+		}
+
+		tx = tx.Select(columns)
+	}
+
+	return tx.Updates(entity).Error
+}
+
+// UpdateFields 根据主键更新指定字段(包括零值), 不需要先查询记录, 记录不存在时返回 gorm.ErrRecordNotFound.
+// MySQL 在新值与原值相同时影响行数为 0, 因此影响行数为 0 时再按主键查询一次确认记录是否存在.
+//   - id: 主键
+//   - values: 键为 Model() 返回实例的字段指针, 或者列名/字段名字符串, 值为更新后的值
+func (r *Repository[T]) UpdateFields(ctx context.Context, id any, values map[any]any) error {
+	if len(values) == 0 {
+		return fmt.Errorf("模型 %s 更新字段不能为空", r.schema.Name)
+	}
+
+	updates := make(map[string]any, len(values))
+
+	for field, value := range values {
+		column, err := r.Column(field)
+		if err != nil {
+			return err
+		}
+
+		updates[column] = value
+	}
+
+	result := r.db.WithContext(ctx).Model(new(T)).Where(r.primaryKeyEq(id)).Updates(updates)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	return r.checkExists(ctx, id)
+}
+
+// checkExists 按主键确认记录存在(已排除软删除的数据), 不存在时返回 gorm.ErrRecordNotFound
+func (r *Repository[T]) checkExists(ctx context.Context, id any) error {
+	var count int64
+
+	if err := r.Query(ctx).Where(r.primaryKeyEq(id)).Limit(1).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Delete 根据主键删除记录, 模型包含 gorm.DeletedAt 字段时为软删除, 记录不存在时返回 gorm.ErrRecordNotFound
+func (r *Repository[T]) Delete(ctx context.Context, id any) error {
+	result := r.db.WithContext(ctx).Where(r.primaryKeyEq(id)).Delete(new(T))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// DeleteSoft 根据主键软删除记录, 模型不包含 gorm.DeletedAt 字段时返回错误, 避免误用为物理删除
+func (r *Repository[T]) DeleteSoft(ctx context.Context, id any) error {
+	if !r.softDelete {
+		return fmt.Errorf("模型 %s 不包含 gorm.DeletedAt 字段, 不支持软删除", r.schema.Name)
+	}
+
+	return r.Delete(ctx, id)
+}
+
+// Sort 排序
+type Sort struct {
+	Field any  // Model() 返回实例的字段指针, 或者列名/字段名字符串
+	Desc  bool // 是否降序
+}
+
+// ListQuery 列表查询参数
+type ListQuery struct {
+	Sorts       []Sort                    // 排序, 为空时按主键降序
+	Conditions  []*QueryBuilder           // 查询条件, 使用 Where() 创建, 多个条件使用 AND 连接
+	Scopes      []func(*gorm.DB) *gorm.DB // 查询条件, 用于 QueryBuilder 无法表达的条件
+	PageOptions []utils.PaginateOption    // 分页选项, 例如 utils.WithPageSizes
+}
+
+// List 分页查询记录, 已排除软删除的数据
+//   - page: 分页参数, 查询结果写入 page.Records 和 page.Total
+//   - query: 排序和查询条件
+func (r *Repository[T]) List(ctx context.Context, page *utils.Page[T], query ListQuery) error {
+	if page.PageBase == nil {
+		page.PageBase = &utils.PageBase{}
+	}
+
+	tx := r.Query(ctx)
+
+	for _, cond := range query.Conditions {
+		where, args, err := cond.Build()
+		if err != nil {
+			return err
+		}
+
+		if where != "" {
+			tx = tx.Where(where, args...)
+		}
+	}
+
+	tx = tx.Scopes(query.Scopes...)
+
+	sorts := query.Sorts
+	if len(sorts) == 0 {
+		sorts = []Sort{{Field: r.schema.PrioritizedPrimaryField.DBName, Desc: true}}
+	}
+
+	for _, s := range sorts {
+		column, err := r.Column(s.Field)
+		if err != nil {
+			return err
+		}
+
+		tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: s.Desc})
+	}
+
+	return page.SelectPages(r.db.WithContext(ctx), tx, query.PageOptions...)
+}
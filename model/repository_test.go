@@ -0,0 +1,211 @@
+//
+// FilePath    : go-utils\model\repository_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 通用数据访问层测试
+//
+
+package model
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jiaopengzi/go-utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type repoUser struct {
+	BaseModel
+	Name string `gorm:"column:name;type:varchar(64)"`
+	Age  int    `gorm:"column:age;type:int"`
+}
+
+func (repoUser) TableName() string { return "repo_user" }
+
+type repoLog struct {
+	ID      uint64 `gorm:"column:id;primaryKey"`
+	Content string `gorm:"column:content"`
+}
+
+func (repoLog) TableName() string { return "repo_log" }
+
+// newDryRunDB 创建只生成 SQL 不执行的数据库连接, 执行的 SQL 记录到 sqls
+func newDryRunDB(t *testing.T) (*gorm.DB, *[]string) {
+	t.Helper()
+
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+
+	var sqls []string
+
+	record := func(tx *gorm.DB) {
+		sqls = append(sqls, tx.Statement.SQL.String())
+	}
+
+	_ = db.Callback().Query().After("gorm:query").Register("test:record", record)
+	_ = db.Callback().Create().After("gorm:create").Register("test:record", record)
+	_ = db.Callback().Update().After("gorm:update").Register("test:record", record)
+	_ = db.Callback().Delete().After("gorm:delete").Register("test:record", record)
+
+	return db, &sqls
+}
+
+func TestRepository(t *testing.T) {
+	db, sqls := newDryRunDB(t)
+	ctx := context.Background()
+
+	repo, err := NewRepository[repoUser](db)
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+
+	_, _ = repo.GetByID(ctx, 1)
+
+	user := &repoUser{Name: "jiaopengzi", Age: 18}
+	user.ID = 1
+
+	_ = repo.Update(ctx, user, &user.Name, &user.Age)
+	_ = repo.Delete(ctx, 1)
+
+	want := []string{
+		"SELECT * FROM `repo_user` WHERE deleted_at IS NULL AND `id` = ? LIMIT ?",
+		"UPDATE `repo_user` SET `updated_at`=?,`name`=?,`age`=? WHERE `repo_user`.`deleted_at` IS NULL AND `id` = ?",
+		"UPDATE `repo_user` SET `deleted_at`=? WHERE `id` = ? AND `repo_user`.`deleted_at` IS NULL",
+	}
+
+	if len(*sqls) != len(want) {
+		t.Fatalf("sqls = %q; want %q", *sqls, want)
+	}
+
+	for i, sql := range *sqls {
+		if sql != want[i] {
+			t.Errorf("sql[%d] = %q; want %q", i, sql, want[i])
+		}
+	}
+}
+
+func TestRepositoryList(t *testing.T) {
+	db, sqls := newDryRunDB(t)
+
+	repo, err := NewRepository[repoUser](db)
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+
+	m := repo.Model()
+	page := &utils.Page[repoUser]{}
+
+	_ = repo.List(context.Background(), page, ListQuery{
+		Sorts: []Sort{{Field: &m.Age, Desc: true}, {Field: "Name"}},
+		Scopes: []func(*gorm.DB) *gorm.DB{func(tx *gorm.DB) *gorm.DB {
+			return tx.Where("age > ?", 10)
+		}},
+	})
+
+	if len(*sqls) == 0 || !strings.Contains((*sqls)[0], "WHERE deleted_at IS NULL AND age > ? ORDER BY `age` DESC,`name`") {
+		t.Fatalf("sqls = %q", *sqls)
+	}
+
+	// 不存在的列
+	if err = repo.List(context.Background(), page, ListQuery{Sorts: []Sort{{Field: "name; drop table"}}}); err == nil {
+		t.Fatalf("List() with unknown column should return error")
+	}
+}
+
+func TestRepositoryWithoutSoftDelete(t *testing.T) {
+	db, sqls := newDryRunDB(t)
+
+	repo, err := NewRepository[repoLog](db)
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+
+	_, _ = repo.GetByID(context.Background(), 1)
+	_ = repo.Delete(context.Background(), 1)
+
+	want := []string{
+		"SELECT * FROM `repo_log` WHERE `id` = ? LIMIT ?",
+		"DELETE FROM `repo_log` WHERE `id` = ?",
+	}
+
+	if strings.Join(*sqls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("sqls = %q; want %q", *sqls, want)
+	}
+
+	if _, err = NewRepository[*repoLog](db); err == nil {
+		t.Fatalf("NewRepository() with pointer type should return error")
+	}
+}
+
+func TestRepositoryUpdateFieldsAndConditions(t *testing.T) {
+	db, sqls := newDryRunDB(t)
+	ctx := context.Background()
+
+	repo, err := NewRepository[repoUser](db)
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+
+	m := repo.Model()
+
+	// DryRun 影响行数为 0, 按主键查询确认记录是否存在, 查询结果为 0 时返回 gorm.ErrRecordNotFound
+	if err = repo.UpdateFields(ctx, 1, map[any]any{&m.Age: 0}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("UpdateFields() error = %v, want gorm.ErrRecordNotFound", err)
+	}
+
+	_ = repo.DeleteSoft(ctx, 1)
+	_ = repo.List(ctx, &utils.Page[repoUser]{}, ListQuery{
+		Conditions: []*QueryBuilder{repo.Where().Gt(&m.Age, 10).Contains(&m.Name, "jiao")},
+	})
+
+	want := []string{
+		"UPDATE `repo_user` SET `age`=?,`updated_at`=? WHERE `id` = ? AND `repo_user`.`deleted_at` IS NULL",
+		"SELECT count(*) FROM `repo_user` WHERE deleted_at IS NULL AND `id` = ? LIMIT ?",
+		"UPDATE `repo_user` SET `deleted_at`=? WHERE `id` = ? AND `repo_user`.`deleted_at` IS NULL",
+	}
+
+	if len(*sqls) < len(want)+1 {
+		t.Fatalf("sqls = %q", *sqls)
+	}
+
+	for i := range want {
+		if (*sqls)[i] != want[i] {
+			t.Errorf("sql[%d] = %q; want %q", i, (*sqls)[i], want[i])
+		}
+	}
+
+	if !strings.Contains((*sqls)[3], "WHERE deleted_at IS NULL AND (age > ? AND name LIKE ?)") {
+		t.Errorf("list sql = %q", (*sqls)[3])
+	}
+
+	// 不存在的列和空更新
+	if err = repo.UpdateFields(ctx, 1, map[any]any{"unknown": 1}); err == nil {
+		t.Errorf("UpdateFields() with unknown column should return error")
+	}
+
+	if err = repo.UpdateFields(ctx, 1, nil); err == nil {
+		t.Errorf("UpdateFields() with empty values should return error")
+	}
+
+	// 条件构建失败
+	var other repoUser
+	if err = repo.List(ctx, &utils.Page[repoUser]{}, ListQuery{Conditions: []*QueryBuilder{repo.Where().Eq(&other.Age, 1)}}); err == nil {
+		t.Errorf("List() with invalid condition should return error")
+	}
+
+	logRepo, err := NewRepository[repoLog](db)
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+
+	if err = logRepo.DeleteSoft(ctx, 1); err == nil {
+		t.Errorf("DeleteSoft() without gorm.DeletedAt should return error")
+	}
+}
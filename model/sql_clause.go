@@ -0,0 +1,201 @@
+//
+// FilePath    : go-utils\model\sql_clause.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 生成 SELECT/INSERT/UPDATE 语句的列片段及占位符参数, 用于手写 SQL
+//
+
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/jiaopengzi/go-utils"
+)
+
+// clauseCache 缓存生成的语句片段, 键包含表名及排除的列, 排除列的组合较多, 使用 LRU 限制缓存大小, 并发安全.
+// 虚拟模型可以重新注册, 不缓存.
+var clauseCache = utils.NewLRU[string, string](columnCacheMaxBytes, func(key, clause string) int64 {
+	return int64(len(key) + len(clause))
+})
+
+// loadClause 从缓存中获取语句片段, 不存在时使用 build 生成并存入缓存
+//   - cacheable: 是否缓存
+//   - cacheKey: 缓存键
+//   - build: 生成函数
+func loadClause(cacheable bool, cacheKey string, build func() (string, error)) (string, error) {
+	if cacheable {
+		if cached, ok := clauseCache.Get(cacheKey); ok {
+			return cached, nil
+		}
+	}
+
+	clause, err := build()
+	if err != nil {
+		return "", err
+	}
+
+	if cacheable {
+		clauseCache.Set(cacheKey, clause)
+	}
+
+	return clause, nil
+}
+
+// exceptColumnsKey 获取排除字段对应的列名, 排序后拼接为缓存键的一部分
+func exceptColumnsKey(modelTar Tabler, exceptFieldPtrs []any) (string, error) {
+	if len(exceptFieldPtrs) == 0 {
+		return "", nil
+	}
+
+	columnNames, err := GetColumnNames(modelTar, exceptFieldPtrs)
+	if err != nil {
+		return "", err
+	}
+
+	slices.Sort(columnNames)
+
+	return strings.Join(columnNames, ","), nil
+}
+
+// SelectClause 生成 SELECT 语句的列片段, 例如 "id, name, created_at"
+//   - modelTar: 表模型指针
+//   - exceptFieldPtrs: 需要排除的字段指针, 虚拟模型为字段名
+func SelectClause(modelTar Tabler, exceptFieldPtrs ...any) (string, error) {
+	exceptKey, err := exceptColumnsKey(modelTar, exceptFieldPtrs)
+	if err != nil {
+		return "", err
+	}
+
+	_, virtual := asVirtualModel(modelTar)
+	cacheKey := fmt.Sprintf("select.%s.%s", modelTar.TableName(), exceptKey)
+
+	return loadClause(!virtual, cacheKey, func() (string, error) {
+		columnNames, err := GetAllColumnNamesExcept(modelTar, exceptFieldPtrs)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.Join(columnNames, ", "), nil
+	})
+}
+
+// InsertClause 生成 INSERT 语句的列及占位符片段, 并按列的顺序返回 modelTar 中对应字段的值作为参数,
+// 例如 "(id, name) VALUES (?, ?)" 和 [1, "name"], 可用于 db.Exec("INSERT INTO t "+clause, args...).
+// 虚拟模型没有字段值, 返回的参数为 nil.
+//   - modelTar: 表模型指针
+//   - exceptFieldPtrs: 需要排除的字段指针, 例如自增主键, 虚拟模型为字段名
+func InsertClause(modelTar Tabler, exceptFieldPtrs ...any) (string, []any, error) {
+	exceptKey, err := exceptColumnsKey(modelTar, exceptFieldPtrs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	_, virtual := asVirtualModel(modelTar)
+	cacheKey := fmt.Sprintf("insert.%s.%s", modelTar.TableName(), exceptKey)
+
+	clause, err := loadClause(!virtual, cacheKey, func() (string, error) {
+		columnNames, err := GetAllColumnNamesExcept(modelTar, exceptFieldPtrs)
+		if err != nil {
+			return "", err
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columnNames)), ", ")
+
+		return fmt.Sprintf("(%s) VALUES (%s)", strings.Join(columnNames, ", "), placeholders), nil
+	})
+	if err != nil || virtual {
+		return clause, nil, err
+	}
+
+	args, err := insertArgs(modelTar, exceptFieldPtrs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return clause, args, nil
+}
+
+// insertArgs 按 GetAllColumnNamesExcept 的顺序获取 modelTar 中字段的值
+func insertArgs(modelTar Tabler, exceptFieldPtrs []any) ([]any, error) {
+	fieldPtrs, err := getExportedFieldPtrs(modelTar)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptColumns, err := GetColumnNames(modelTar, exceptFieldPtrs)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]any, 0, len(fieldPtrs))
+
+	for _, fieldPtr := range fieldPtrs {
+		columnName, err := GetColumnName(modelTar, fieldPtr)
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.Contains(exceptColumns, columnName) {
+			continue
+		}
+
+		args = append(args, reflect.ValueOf(fieldPtr).Elem().Interface())
+	}
+
+	return args, nil
+}
+
+// UpdateSetClause 生成 UPDATE 语句的 SET 片段及参数, 列按列名排序, 例如 "age = ?, name = ?" 和 [18, "name"],
+// 可用于 db.Exec("UPDATE t SET "+clause+" WHERE id = ?", append(args, id)...).
+//   - modelTar: 表模型指针
+//   - values: 字段指针 => 更新的值, 虚拟模型为字段名 => 更新的值
+func UpdateSetClause(modelTar Tabler, values map[any]any) (string, []any, error) {
+	if len(values) == 0 {
+		return "", nil, fmt.Errorf("表 '%s' 的更新字段不能为空", modelTar.TableName())
+	}
+
+	type setColumn struct {
+		name  string
+		value any
+	}
+
+	columns := make([]setColumn, 0, len(values))
+
+	for fieldPtr, value := range values {
+		columnName, err := GetColumnName(modelTar, fieldPtr)
+		if err != nil {
+			return "", nil, err
+		}
+
+		columns = append(columns, setColumn{name: columnName, value: value})
+	}
+
+	slices.SortFunc(columns, func(a, b setColumn) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	columnNames := make([]string, len(columns))
+	args := make([]any, len(columns))
+
+	for i, col := range columns {
+		columnNames[i] = col.name
+		args[i] = col.value
+	}
+
+	_, virtual := asVirtualModel(modelTar)
+	cacheKey := fmt.Sprintf("update.%s.%s", modelTar.TableName(), strings.Join(columnNames, ","))
+
+	clause, err := loadClause(!virtual, cacheKey, func() (string, error) {
+		return strings.Join(columnNames, " = ?, ") + " = ?", nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return clause, args, nil
+}
@@ -0,0 +1,75 @@
+//
+// FilePath    : go-utils\model\sql_clause_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : SELECT/INSERT/UPDATE 语句片段单测
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectClause(t *testing.T) {
+	m := &TestModel{}
+
+	clause, err := SelectClause(m)
+	assert.NoError(t, err)
+	assert.Equal(t, "id_gorm, created_at_gorm, updated_at_gorm, deleted_at_gorm, name_gorm", clause)
+
+	clause, err = SelectClause(m, &m.DeletedAt, &m.CreatedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, "id_gorm, updated_at_gorm, name_gorm", clause)
+
+	// 缓存命中时结果一致
+	cached, err := SelectClause(m, &m.CreatedAt, &m.DeletedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, clause, cached)
+
+	other := &TestModel{}
+	_, err = SelectClause(m, &other.Name)
+	assert.Error(t, err)
+}
+
+func TestInsertClause(t *testing.T) {
+	m := &TestModel{Name: "jiaopengzi"}
+	m.ID = 7
+
+	clause, args, err := InsertClause(m, &m.CreatedAt, &m.UpdatedAt, &m.DeletedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, "(id_gorm, name_gorm) VALUES (?, ?)", clause)
+	assert.Equal(t, []any{uint64(7), "jiaopengzi"}, args)
+
+	// 参数使用当前实例的值
+	m2 := &TestModel{Name: "other"}
+	_, args, err = InsertClause(m2, &m2.CreatedAt, &m2.UpdatedAt, &m2.DeletedAt, &m2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"other"}, args)
+
+	v, err := NewVirtualModel("sql_clause_virtual", map[string]TableField{
+		"Name": {Name: "name"},
+		"Age":  {Name: "age"},
+	})
+	assert.NoError(t, err)
+
+	clause, args, err = InsertClause(v)
+	assert.NoError(t, err)
+	assert.Equal(t, "(age, name) VALUES (?, ?)", clause)
+	assert.Nil(t, args)
+}
+
+func TestUpdateSetClause(t *testing.T) {
+	m := &TestModel{}
+
+	clause, args, err := UpdateSetClause(m, map[any]any{&m.Name: "new", &m.ID: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, "id_gorm = ?, name_gorm = ?", clause)
+	assert.Equal(t, []any{3, "new"}, args)
+
+	_, _, err = UpdateSetClause(m, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+//
+// FilePath    : go-utils\model\tag_parser.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 可扩展的标签解析, 支持 sqlx(db)、viper(mapstructure)、bun 等标签复用字段指针 API
+//
+
+package model
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// TagParser 标签解析函数, 从标签内容中获取键 key 的值
+//   - tagValue: 标签内容, 例如 db:"user_name" 中的 user_name
+//   - key: 需要获取的键名, 为空时表示获取名称(列名), GetColumnName 使用空 key 获取列名
+type TagParser func(tagValue, key string) (string, error)
+
+// tagParsers 注册的标签解析函数, gorm 和 json 为内置标签, 不在其中
+var (
+	tagParsers = map[string]TagParser{
+		"db":           NameTagParser(","),
+		"mapstructure": NameTagParser(","),
+		"bun":          NameTagParser(","),
+	}
+	tagParsersMu sync.RWMutex
+)
+
+// RegisterTagParser 注册标签解析函数, 注册后 GetTagContent、GetColumnName(WithTag(tag)) 等可以使用该标签.
+// 已注册的标签会被覆盖, gorm 和 json 为内置标签, 注册时 panic.
+//   - tag: 标签名, 例如 db
+//   - parser: 标签解析函数
+func RegisterTagParser(tag string, parser func(tagValue, key string) (string, error)) {
+	if tag == "" || tag == gormTag || tag == jsonTag || parser == nil {
+		panic(fmt.Sprintf("model: 不能注册标签 '%s' 的解析函数", tag))
+	}
+
+	tagParsersMu.Lock()
+	defer tagParsersMu.Unlock()
+
+	tagParsers[tag] = parser
+
+	// 解析规则变化, 清除列名缓存
+	columnCache.Clear()
+}
+
+// getTagParser 获取注册的标签解析函数
+func getTagParser(tag string) (TagParser, bool) {
+	tagParsersMu.RLock()
+	defer tagParsersMu.RUnlock()
+
+	parser, ok := tagParsers[tag]
+
+	return parser, ok
+}
+
+// NameTagParser 生成第一部分为名称、使用 separator 分隔选项的标签解析函数,
+// 适用于 db:"user_name"、mapstructure:"name,omitempty"、bun:"name,pk" 等标签.
+// key 为空时返回名称, 名称为空或为 "-" 时返回错误; key 不为空时返回以 key 开头的选项去掉 key 后的内容,
+// 例如 key 为 "type:" 时从 bun:"name,type:varchar(64)" 中获取 varchar(64).
+//   - separator: 分隔符, 例如 ","
+func NameTagParser(separator string) TagParser {
+	return func(tagValue, key string) (string, error) {
+		name, options, _ := strings.Cut(tagValue, separator)
+
+		if key == "" {
+			name = strings.TrimSpace(name)
+			if name == "" || name == "-" {
+				return "", fmt.Errorf("无法找到标签 '%s' 的名称", tagValue)
+			}
+
+			return name, nil
+		}
+
+		for option := range strings.SplitSeq(options, separator) {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(option), key); ok {
+				return strings.TrimSpace(value), nil
+			}
+		}
+
+		return "", fmt.Errorf("无法找到标签 '%s' 的 '%s' 键", tagValue, key)
+	}
+}
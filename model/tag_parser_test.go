@@ -0,0 +1,68 @@
+//
+// FilePath    : go-utils\model\tag_parser_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 可扩展的标签解析单测
+//
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tagParserModel struct {
+	ID       uint64 `db:"id" mapstructure:"id" bun:"id,pk,autoincrement" toml:"ID"`
+	UserName string `db:"user_name" mapstructure:"user_name,omitempty" bun:"user_name,type:varchar(64)" toml:"UserName"`
+	Ignored  string `db:"-"`
+}
+
+func (tagParserModel) TableName() string {
+	return "tag_parser_models"
+}
+
+func TestGetTagContentRegisteredTags(t *testing.T) {
+	m := &tagParserModel{}
+
+	col, err := GetColumnName(m, &m.UserName, WithTag("db"))
+	assert.NoError(t, err)
+	assert.Equal(t, "user_name", col)
+
+	col, err = GetColumnName(m, &m.UserName, WithTag("mapstructure"), WithTableName(true))
+	assert.NoError(t, err)
+	assert.Equal(t, "tag_parser_models.user_name", col)
+
+	cols, err := GetColumnNames(m, []any{&m.ID, &m.UserName}, WithTag("bun"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "user_name"}, cols)
+
+	typ, err := GetTagContent(m, &m.UserName, "bun", "type:", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "varchar(64)", typ)
+
+	_, err = GetColumnName(m, &m.Ignored, WithTag("db"))
+	assert.Error(t, err)
+
+	// 未注册的标签
+	_, err = GetColumnName(m, &m.ID, WithTag("toml"))
+	assert.Error(t, err)
+
+	RegisterTagParser("toml", func(tagValue, _ string) (string, error) {
+		return strings.ToLower(tagValue), nil
+	})
+	defer func() {
+		tagParsersMu.Lock()
+		delete(tagParsers, "toml")
+		tagParsersMu.Unlock()
+	}()
+
+	col, err = GetColumnName(m, &m.UserName, WithTag("toml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "username", col)
+
+	assert.Panics(t, func() { RegisterTagParser(gormTag, NameTagParser(",")) })
+}
@@ -13,10 +13,10 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/jiaopengzi/go-utils"
+	"github.com/jiaopengzi/go-utils/reflectutil"
 	"gorm.io/gorm"
 )
 
@@ -35,8 +35,25 @@ const (
 	jsonTag = "json"
 )
 
-// columnCache 使用 sync.Map 作为缓存列名称的缓存, 保证并发安全.
-var columnCache sync.Map
+// columnCacheMaxBytes 列名称缓存的最大容量, 前缀等参数由调用方传入, 使用 LRU 限制缓存大小
+const columnCacheMaxBytes = 4 << 20
+
+// columnCache 缓存列名称、TableField 和软删除条件, 并发安全.
+var columnCache = utils.NewLRU[string, any](columnCacheMaxBytes, cacheEntrySize)
+
+// cacheEntrySize 估算缓存条目占用的字节数
+func cacheEntrySize(key string, value any) int64 {
+	size := len(key)
+
+	switch v := value.(type) {
+	case string:
+		size += len(v)
+	case TableField:
+		size += len(v.Name) + len(v.Type)
+	}
+
+	return int64(size)
+}
 
 // TableField 表格字段
 type TableField struct {
@@ -156,14 +173,14 @@ func isFieldInModelAndIsPtr(modelTar any, fieldPtr any) (bool, error) {
 		return true, nil
 	}
 
-	// 检查 modelTar 是否是指针
-	if !utils.IsPointer(modelTar) {
-		return false, fmt.Errorf("modelTar %T must be a pointer, with value %v", modelTar, modelTar)
+	// 检查 modelTar 是否是非 nil 指针, nil 指针在后续反射取字段时会 panic
+	if !reflectutil.IsNonNilPointer(modelTar) {
+		return false, fmt.Errorf("modelTar %T must be a non-nil pointer, with value %v", modelTar, modelTar)
 	}
 
-	// 检查 fieldPtr 是否是指针
-	if !utils.IsPointer(fieldPtr) {
-		return false, fmt.Errorf("fieldPtr %T must be a pointer, with value %v", fieldPtr, fieldPtr)
+	// 检查 fieldPtr 是否是非 nil 指针
+	if !reflectutil.IsNonNilPointer(fieldPtr) {
+		return false, fmt.Errorf("fieldPtr %T must be a non-nil pointer, with value %v", fieldPtr, fieldPtr)
 	}
 
 	// 检查 fieldPtr 是否是 modelTar 的字段
@@ -275,13 +292,14 @@ func GetFieldNameFromPtr(structPtr any, fieldPtr any) (string, error) {
 // GetTagContent 函数用于解析结构体标签内容并返回,例如解析 gorm 标签的 column 键的内容
 //   - structPtr: 需要解析的结构体指针
 //   - fieldPtr: 需要解析的字段指针
-//   - tag: 需要解析的标签名，例如 "gorm" 或 "json"
+//   - tag: 需要解析的标签名，例如 "gorm" 或 "json", 其他标签需要通过 RegisterTagParser 注册, 例如 "db"
 //   - key: 需要获取的键名，例如 "column"
-//   - separator: 分隔符，例如 ";"
+//   - separator: 分隔符，例如 ";", 只用于 gorm 和 json 标签, 注册的标签由解析函数决定
 func GetTagContent(structPtr, fieldPtr any, tag, key, separator string) (string, error) {
 	// 首先判断 tag 是否在指定的范围内
-	if tag != gormTag && tag != jsonTag {
-		return "", fmt.Errorf("不支持的标签 '%s', 只支持 %s 和 %s 的 tag", tag, gormTag, jsonTag)
+	parser, registered := getTagParser(tag)
+	if tag != gormTag && tag != jsonTag && !registered {
+		return "", fmt.Errorf("不支持的标签 '%s', 只支持 %s 和 %s 及通过 RegisterTagParser 注册的 tag", tag, gormTag, jsonTag)
 	}
 
 	// 从结构体指针中获取字段信息
@@ -293,6 +311,11 @@ func GetTagContent(structPtr, fieldPtr any, tag, key, separator string) (string,
 	// 从字段信息中获取标签内容
 	contentStr := field.Tag.Get(tag)
 
+	// 处理注册的标签
+	if registered {
+		return parser(contentStr, key)
+	}
+
 	// 处理 json 标签
 	if tag == jsonTag {
 		// 使用逗号分割标签内容
@@ -347,6 +370,13 @@ func GetColumnName(modelTar Tabler, fieldPtr any, opts ...Option) (string, error
 		return "", err
 	}
 
+	// 虚拟模型直接从字段映射中获取, 此时 fieldPtr 为字段名
+	if v, ok := asVirtualModel(modelTar); ok {
+		col, err := v.columnNameType(fieldPtr, &cfg)
+
+		return col.Name, err
+	}
+
 	// 获取模型类型和字段名称
 	fieldName, err := GetFieldNameFromPtr(modelTar, fieldPtr)
 	if err != nil {
@@ -357,7 +387,7 @@ func GetColumnName(modelTar Tabler, fieldPtr any, opts ...Option) (string, error
 	cacheKey := fmt.Sprintf("%s.%s.%s.%t.%s", modelTar.TableName(), fieldName, cfg.Prefix, cfg.TableName, cfg.Tag)
 
 	// 尝试从缓存中获取列名称
-	if cachedColumnName, ok := columnCache.Load(cacheKey); ok {
+	if cachedColumnName, ok := columnCache.Get(cacheKey); ok {
 		colName, ok := cachedColumnName.(string)
 		if ok {
 			return colName, nil
@@ -382,8 +412,12 @@ func GetColumnName(modelTar Tabler, fieldPtr any, opts ...Option) (string, error
 		if err != nil {
 			return "", err
 		}
+	// 处理通过 RegisterTagParser 注册的标签, 列名为标签的名称部分
 	default:
-		return "", fmt.Errorf("不支持的标签 '%s', 只支持 %s 和 %s 的 tag", cfg.Tag, gormTag, jsonTag)
+		ColumnName, err = GetTagContent(modelTar, fieldPtr, cfg.Tag, "", "")
+		if err != nil {
+			return "", err
+		}
 	}
 
 	if cfg.Prefix != "" {
@@ -393,7 +427,7 @@ func GetColumnName(modelTar Tabler, fieldPtr any, opts ...Option) (string, error
 	}
 
 	// 存入缓存
-	columnCache.Store(cacheKey, ColumnName)
+	columnCache.Set(cacheKey, ColumnName)
 
 	return ColumnName, nil
 }
@@ -456,6 +490,16 @@ func GetAllColumnNames(modelTar Tabler, opts ...Option) ([]string, error) {
 		return nil, err
 	}
 
+	// 虚拟模型直接从字段映射中获取
+	if v, ok := asVirtualModel(modelTar); ok {
+		tableFields, err := v.columnNameTypesExcept(nil, &cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return tableFieldNames(tableFields), nil
+	}
+
 	// 获取所有字段的指针
 	fieldPtrs, err := getExportedFieldPtrs(modelTar)
 	if err != nil {
@@ -487,6 +531,16 @@ func GetAllColumnNamesExcept(modelTar Tabler, exceptFieldPtrs []any, opts ...Opt
 		return nil, err
 	}
 
+	// 虚拟模型直接从字段映射中获取, 此时 exceptFieldPtrs 为字段名
+	if v, ok := asVirtualModel(modelTar); ok {
+		tableFields, err := v.columnNameTypesExcept(exceptFieldPtrs, &cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return tableFieldNames(tableFields), nil
+	}
+
 	// 获取所有字段的指针
 	fieldPtrs, err := getExportedFieldPtrs(modelTar)
 	if err != nil {
@@ -543,6 +597,11 @@ func GetColumnNameType(modelTar Tabler, fieldPtr any, opts ...Option) (TableFiel
 		return TableField{}, err
 	}
 
+	// 虚拟模型直接从字段映射中获取, 此时 fieldPtr 为字段名
+	if v, ok := asVirtualModel(modelTar); ok {
+		return v.columnNameType(fieldPtr, &cfg)
+	}
+
 	var tableField TableField
 
 	// 获取模型类型和字段名称
@@ -555,7 +614,7 @@ func GetColumnNameType(modelTar Tabler, fieldPtr any, opts ...Option) (TableFiel
 	cacheKey := fmt.Sprintf("%s.%s.%s.%t.type.%s", modelTar.TableName(), fieldName, cfg.Prefix, cfg.TableName, cfg.Tag)
 
 	// 尝试从缓存中获取 TableField
-	if cachedTableField, ok := columnCache.Load(cacheKey); ok {
+	if cachedTableField, ok := columnCache.Get(cacheKey); ok {
 		tableField, ok = cachedTableField.(TableField)
 		if ok {
 			return tableField, nil
@@ -586,7 +645,7 @@ func GetColumnNameType(modelTar Tabler, fieldPtr any, opts ...Option) (TableFiel
 	tableField.Type = ColumnType
 
 	// 存入缓存
-	columnCache.Store(cacheKey, tableField)
+	columnCache.Set(cacheKey, tableField)
 
 	return tableField, nil
 }
@@ -646,6 +705,11 @@ func GetAllColumnNameTypes(modelTar Tabler, opts ...Option) ([]TableField, error
 		return nil, err
 	}
 
+	// 虚拟模型直接从字段映射中获取
+	if v, ok := asVirtualModel(modelTar); ok {
+		return v.columnNameTypesExcept(nil, &cfg)
+	}
+
 	// 获取所有字段的指针
 	fieldPtrs, err := getExportedFieldPtrs(modelTar)
 	if err != nil {
@@ -677,6 +741,11 @@ func GetAllColumnNameTypesExcept(modelTar Tabler, exceptFieldPtrs []any, opts ..
 		return nil, err
 	}
 
+	// 虚拟模型直接从字段映射中获取, 此时 exceptFieldPtrs 为字段名
+	if v, ok := asVirtualModel(modelTar); ok {
+		return v.columnNameTypesExcept(exceptFieldPtrs, &cfg)
+	}
+
 	// 获取所有字段的指针
 	fieldPtrs, err := getExportedFieldPtrs(modelTar)
 	if err != nil {
@@ -738,7 +807,7 @@ func DeleteAtIsNull(modelTar Tabler, opts ...Option) string {
 	cacheKey := fmt.Sprintf("%s.DeleteAtIsNull.%s.%t", tableName, cfg.Prefix, cfg.TableName)
 
 	// 尝试从缓存中获取条件语句
-	if cachedCondition, ok := columnCache.Load(cacheKey); ok {
+	if cachedCondition, ok := columnCache.Get(cacheKey); ok {
 		condition, ok := cachedCondition.(string)
 		if ok {
 			return condition
@@ -765,7 +834,7 @@ func DeleteAtIsNull(modelTar Tabler, opts ...Option) string {
 	condition := fmt.Sprintf("%s IS NULL", deleteAtColName)
 
 	// 存入缓存
-	columnCache.Store(cacheKey, condition)
+	columnCache.Set(cacheKey, condition)
 
 	return condition
 }
@@ -0,0 +1,199 @@
+//
+// FilePath    : go-utils\model\virtual_model.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 虚拟模型, 不依赖结构体的表模型(报表表、动态创建的表等)
+//
+
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// VirtualModel 虚拟模型, 由表名和字段映射组成, 不依赖结构体定义
+// 实现了 Tabler 接口, 可以直接传给 GetColumnName 等函数, 此时 fieldPtr 传字段名字符串即可
+type VirtualModel struct {
+	table   string                // 表名
+	columns map[string]TableField // 字段名 => 列信息
+	fields  []string              // 字段名, 按字母顺序排列, 保证 GetAllColumnNames 等结果稳定
+}
+
+// TableName 实现 Tabler 接口
+func (v *VirtualModel) TableName() string {
+	return v.table
+}
+
+// Fields 获取虚拟模型的所有字段名
+func (v *VirtualModel) Fields() []string {
+	return slices.Clone(v.fields)
+}
+
+// 虚拟模型注册表
+var (
+	virtualModels   = make(map[string]*VirtualModel) // 表名 => 虚拟模型
+	virtualModelsMu sync.RWMutex                     // 读写锁 (保证并发安全)
+)
+
+// NewVirtualModel 创建虚拟模型
+//   - table: 表名
+//   - columns: 字段名 => 列信息, 列信息的 Type 可以为空
+func NewVirtualModel(table string, columns map[string]TableField) (*VirtualModel, error) {
+	if table == "" {
+		return nil, fmt.Errorf("虚拟模型表名不能为空")
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("虚拟模型 '%s' 至少需要一个字段", table)
+	}
+
+	v := &VirtualModel{
+		table:   table,
+		columns: make(map[string]TableField, len(columns)),
+		fields:  make([]string, 0, len(columns)),
+	}
+
+	for field, col := range columns {
+		if field == "" || col.Name == "" {
+			return nil, fmt.Errorf("虚拟模型 '%s' 的字段名和列名不能为空", table)
+		}
+
+		v.columns[field] = col
+		v.fields = append(v.fields, field)
+	}
+
+	slices.Sort(v.fields)
+
+	return v, nil
+}
+
+// RegisterVirtualModel 注册虚拟模型, 同名表会被覆盖
+//   - table: 表名
+//   - columns: 字段名 => 列信息
+func RegisterVirtualModel(table string, columns map[string]TableField) (*VirtualModel, error) {
+	v, err := NewVirtualModel(table, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	virtualModelsMu.Lock()
+	defer virtualModelsMu.Unlock()
+
+	virtualModels[table] = v
+
+	return v, nil
+}
+
+// GetVirtualModel 根据表名获取已注册的虚拟模型
+func GetVirtualModel(table string) (*VirtualModel, bool) {
+	virtualModelsMu.RLock()
+	defer virtualModelsMu.RUnlock()
+
+	v, ok := virtualModels[table]
+
+	return v, ok
+}
+
+// UnregisterVirtualModel 注销虚拟模型, 一般用于动态表被删除后
+func UnregisterVirtualModel(table string) {
+	virtualModelsMu.Lock()
+	defer virtualModelsMu.Unlock()
+
+	delete(virtualModels, table)
+}
+
+// GetVirtualModels 获取所有已注册的虚拟模型
+func GetVirtualModels() []*VirtualModel {
+	virtualModelsMu.RLock()
+	defer virtualModelsMu.RUnlock()
+
+	result := make([]*VirtualModel, 0, len(virtualModels))
+	for _, v := range virtualModels {
+		result = append(result, v)
+	}
+
+	// 按表名排序, 保证结果稳定
+	slices.SortFunc(result, func(a, b *VirtualModel) int {
+		return strings.Compare(a.table, b.table)
+	})
+
+	return result
+}
+
+// columnNameType 获取虚拟模型字段的列信息, 并根据配置添加前缀
+//   - field: 字段名, 必须是 string
+//   - cfg: 配置
+func (v *VirtualModel) columnNameType(field any, cfg *Config) (TableField, error) {
+	name, ok := field.(string)
+	if !ok {
+		return TableField{}, fmt.Errorf("虚拟模型 '%s' 的字段必须是 string, 实际为 %T", v.table, field)
+	}
+
+	col, ok := v.columns[name]
+	if !ok {
+		return TableField{}, fmt.Errorf("虚拟模型 '%s' 不存在字段 '%s'", v.table, name)
+	}
+
+	if cfg.Prefix != "" {
+		col.Name = fmt.Sprintf("%s.%s", cfg.Prefix, col.Name)
+	} else if cfg.TableName {
+		col.Name = fmt.Sprintf("%s.%s", v.table, col.Name)
+	}
+
+	return col, nil
+}
+
+// columnNameTypesExcept 获取虚拟模型除 exceptFields 之外的所有字段列信息
+//   - exceptFields: 需要排除的字段名
+//   - cfg: 配置
+func (v *VirtualModel) columnNameTypesExcept(exceptFields []any, cfg *Config) ([]TableField, error) {
+	exceptMap := make(map[string]struct{}, len(exceptFields))
+
+	for _, field := range exceptFields {
+		// 校验排除的字段是否存在
+		if _, err := v.columnNameType(field, cfg); err != nil {
+			return nil, err
+		}
+
+		name, _ := field.(string)
+		exceptMap[name] = struct{}{}
+	}
+
+	tableFields := make([]TableField, 0, len(v.fields))
+
+	for _, field := range v.fields {
+		if _, ok := exceptMap[field]; ok {
+			continue
+		}
+
+		col, err := v.columnNameType(field, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		tableFields = append(tableFields, col)
+	}
+
+	return tableFields, nil
+}
+
+// asVirtualModel 判断 modelTar 是否为虚拟模型
+func asVirtualModel(modelTar Tabler) (*VirtualModel, bool) {
+	v, ok := modelTar.(*VirtualModel)
+
+	return v, ok && v != nil
+}
+
+// tableFieldNames 提取列名
+func tableFieldNames(tableFields []TableField) []string {
+	names := make([]string, 0, len(tableFields))
+	for _, f := range tableFields {
+		names = append(names, f.Name)
+	}
+
+	return names
+}
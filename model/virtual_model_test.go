@@ -0,0 +1,83 @@
+//
+// FilePath    : go-utils\model\virtual_model_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 虚拟模型单测
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVirtualModel(t *testing.T) {
+	v, err := RegisterVirtualModel("report_daily", map[string]TableField{
+		"Day":    {Name: "day", Type: "date"},
+		"Amount": {Name: "amount", Type: "bigint"},
+		"UserID": {Name: "user_id", Type: "bigint"},
+	})
+	assert.NoError(t, err)
+
+	got, ok := GetVirtualModel("report_daily")
+	assert.True(t, ok)
+	assert.Same(t, v, got)
+
+	// 单个字段
+	col, err := GetColumnName(v, "UserID", WithTableName(true))
+	assert.NoError(t, err)
+	assert.Equal(t, "report_daily.user_id", col)
+
+	col, err = GetColumnName(v, "Day", WithPrefix("r"))
+	assert.NoError(t, err)
+	assert.Equal(t, "r.day", col)
+
+	// 多个字段
+	cols, err := GetColumnNames(v, []any{"Day", "Amount"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"day", "amount"}, cols)
+
+	// 所有字段按字段名排序
+	cols, err = GetAllColumnNames(v)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"amount", "day", "user_id"}, cols)
+
+	cols, err = GetAllColumnNamesExcept(v, []any{"Amount"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"day", "user_id"}, cols)
+
+	// 类型
+	field, err := GetColumnNameType(v, "Amount")
+	assert.NoError(t, err)
+	assert.Equal(t, TableField{Name: "amount", Type: "bigint"}, field)
+
+	fields, err := GetAllColumnNameTypesExcept(v, []any{"Day", "UserID"})
+	assert.NoError(t, err)
+	assert.Equal(t, []TableField{{Name: "amount", Type: "bigint"}}, fields)
+
+	// 错误场景
+	_, err = GetColumnName(v, "NotExist")
+	assert.Error(t, err)
+
+	_, err = GetColumnName(v, &v.table)
+	assert.Error(t, err)
+
+	UnregisterVirtualModel("report_daily")
+
+	_, ok = GetVirtualModel("report_daily")
+	assert.False(t, ok)
+}
+
+func TestNewVirtualModelInvalid(t *testing.T) {
+	_, err := NewVirtualModel("", map[string]TableField{"A": {Name: "a"}})
+	assert.Error(t, err)
+
+	_, err = NewVirtualModel("t", nil)
+	assert.Error(t, err)
+
+	_, err = NewVirtualModel("t", map[string]TableField{"A": {}})
+	assert.Error(t, err)
+}
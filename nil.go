@@ -16,6 +16,8 @@ import "reflect"
 // 可能的情况有两种：
 // 1. 接口变量本身为 nil：这意味着接口变量的类型信息和数据指针都是 nil。
 // 2. 接口变量的具体类型为 nil：这意味着接口变量的类型信息不为 nil，但数据指针为 nil。
+//
+// Deprecated: 只识别 nil 指针, 不识别 nil 的 map、切片、函数等, 请使用 reflectutil.IsNil
 func IsInterfaceNil(i any) bool {
 	// 当 i 为 nil 时，直接返回 true。
 	if i == nil {
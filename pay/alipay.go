@@ -24,10 +24,26 @@ const (
 	// AlipayTradeTypePage 支付宝网页支付
 	AlipayTradeTypePage = "alipay_trade_page_pay"
 
+	// AlipayTradeTypeWap 支付宝手机网站支付
+	AlipayTradeTypeWap = "alipay_trade_wap_pay"
+
+	// AlipayTradeTypeApp 支付宝 App 支付
+	AlipayTradeTypeApp = "alipay_trade_app_pay"
+
 	// 文档: https://opendocs.alipay.com/open/357441a2_alipay.trade.fastpay.refund.query?scene=common&pathHash=01981dca
 	AlipayTradeTypeRefundSuccess = "REFUND_SUCCESS"
 )
 
+// 支付宝支付场景常量, 用于 AlipayConfig.Scene
+const (
+	AlipayScenePage = "page" // 电脑网站支付, 默认
+	AlipaySceneWap  = "wap"  // 手机网站支付
+	AlipaySceneApp  = "app"  // App 支付
+)
+
+// alipayTimeLayout 支付宝订单失效时间格式
+const alipayTimeLayout = "2006-01-02 15:04:05"
+
 // AlipayConfig 支付宝支付配置
 type AlipayConfig struct {
 	Enabled         bool   `mapstructure:"enabled" json:"enabled"`                                                                               // 是否启用支付宝支付
@@ -40,6 +56,8 @@ type AlipayConfig struct {
 	NotifyHost      string `mapstructure:"notify_host" json:"notify_host" binding:"required_if=Enabled true" example:"https://example.com:8080"` // 支付结果通知主机地址
 	NotifyPath      string `mapstructure:"notify_path" json:"notify_path" binding:"required_if=Enabled true" example:"/alipay/notify"`           // 支付结果通知路由
 	RefundPath      string `mapstructure:"refund_path" json:"refund_path" binding:"required_if=Enabled true" example:"/alipay/refund_notify"`    // 退款结果通知路由
+	Scene           string `mapstructure:"scene" json:"scene" binding:"omitempty,oneof=page wap app" example:"page"`                             // 可选，支付场景 page(默认)、wap、app，决定 Prepay 使用的支付接口
+	QuitURL         string `mapstructure:"quit_url" json:"quit_url" example:"https://example.com/order"`                                         // 可选，手机网站支付用户付款中途退出返回商户网站的地址
 }
 
 // Alipay 支付宝支付实现
@@ -48,6 +66,9 @@ type Alipay struct {
 	Conf        *AlipayConfig  // 支付宝配置
 	APIPath     string         // API 路径前缀 e.g. /api/v1
 	PayBasePath string         // 支付基础路由 e.g. /pay
+
+	// Deduper 可选, 通知去重, 已处理的通知返回 ErrDuplicateNotify, 正在处理的通知返回 ErrNotifyProcessing
+	Deduper NotificationDeduper
 }
 
 // NewAlipay 创建新的支付宝支付实例
@@ -63,24 +84,31 @@ func NewAlipay(conf *AlipayConfig, apiPath, payBasePath string) (*Alipay, error)
 
 	// 创建 Alipay 客户端失败
 	if err != nil {
-		return nil, fmt.Errorf("create Alipay client error: %w", err)
+		return nil, newError(PayTypeAlipay, ErrInvalidConfig, "", err, "create Alipay client error")
 	}
 
 	// 加载支付宝公钥
 	if err = client.LoadAliPayPublicKey(conf.AlipayPublicKey); err != nil {
-		return nil, fmt.Errorf("load Alipay public key error: %w", err)
+		return nil, newError(PayTypeAlipay, ErrInvalidConfig, "", err, "load Alipay public key error")
 	}
 
 	// 设置接口内容加密密钥
 	if conf.EncryptKey != "" {
 		if err = client.SetEncryptKey(conf.EncryptKey); err != nil {
-			return nil, fmt.Errorf("set Alipay encrypt key error: %w", err)
+			return nil, newError(PayTypeAlipay, ErrInvalidConfig, "", err, "set Alipay encrypt key error")
 		}
 	}
 
 	// appPath 和 payBasePath 不为空
 	if apiPath == "" || payBasePath == "" {
-		return nil, fmt.Errorf("apiPath and payBasePath cannot be empty")
+		return nil, newError(PayTypeAlipay, ErrInvalidConfig, "", nil, "apiPath and payBasePath cannot be empty")
+	}
+
+	// 支付场景
+	switch conf.Scene {
+	case "", AlipayScenePage, AlipaySceneWap, AlipaySceneApp:
+	default:
+		return nil, newError(PayTypeAlipay, ErrInvalidConfig, "", nil, "unsupported Alipay scene: %s", conf.Scene)
 	}
 
 	// 返回支付宝支付实例
@@ -92,34 +120,50 @@ func NewAlipay(conf *AlipayConfig, apiPath, payBasePath string) (*Alipay, error)
 	}, nil
 }
 
-// Prepay 支付宝支付实现
+// PayType 实现 Provider 接口, 返回支付宝支付类型
+func (a *Alipay) PayType() PayType {
+	return PayTypeAlipay
+}
+
+// Prepay 兼容旧版本的支付接口, 等价于 PrepayContext(context.Background(), ...)
+func (a *Alipay) Prepay(orderID uint64, amount int64, description, returnURL string, timeExpire time.Time) (string, error) {
+	return a.PrepayContext(context.Background(), orderID, amount, description, returnURL, timeExpire)
+}
+
+// PrepayContext 支付宝支付实现, 根据 AlipayConfig.Scene 选择电脑网站、手机网站或 App 支付.
+// 支付链接在本地签名生成, 不访问网络, ctx 已取消时直接返回错误
 //   - orderID: 订单ID
 //   - amount: 金额，单位为分
 //   - description: 商品描述
-//   - returnURL: 支付完成后跳转的页面
+//   - returnURL: 支付完成后跳转的页面, App 支付时忽略
 //
-// 返回值为支付链接, 在浏览器中打开即可完成支付
-func (a *Alipay) Prepay(orderID uint64, amount int64, description, returnURL string, timeExpire time.Time) (string, error) {
-	// 文档: https://github.com/smartwalle/alipay/tree/master
-	// 支付结果通知地址
-	notifyURL := fmt.Sprintf("%s/%s%s%s",
-		a.Conf.NotifyHost,
-		a.APIPath,
-		a.PayBasePath,
-		a.Conf.NotifyPath,
-	)
+// 返回值为支付链接, 在浏览器中打开即可完成支付; App 支付时为 orderInfo 字符串
+func (a *Alipay) PrepayContext(ctx context.Context, orderID uint64, amount int64, description, returnURL string, timeExpire time.Time) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", newError(PayTypeAlipay, ErrProviderUnavailable, "", err, "alipay prepay canceled")
+	}
 
+	switch a.Conf.Scene {
+	case AlipaySceneWap:
+		return a.PrepayWap(orderID, amount, description, returnURL, timeExpire)
+	case AlipaySceneApp:
+		return a.PrepayApp(orderID, amount, description, timeExpire)
+	default:
+		return a.PrepayPage(orderID, amount, description, returnURL, timeExpire)
+	}
+}
+
+// PrepayPage 电脑网站支付, 返回支付链接, 在浏览器中打开即可完成支付
+//   - orderID: 订单ID
+//   - amount: 金额，单位为分
+//   - description: 商品描述
+//   - returnURL: 支付完成后跳转的页面
+//   - timeExpire: 订单失效时间
+func (a *Alipay) PrepayPage(orderID uint64, amount int64, description, returnURL string, timeExpire time.Time) (string, error) {
+	// 文档: https://github.com/smartwalle/alipay/tree/master
 	// 网站端支付使用 TradePagePay
 	var p = alipay.TradePagePay{
-		Trade: alipay.Trade{
-			NotifyURL:   notifyURL,
-			ReturnURL:   returnURL, // 支付完成后跳转的页面
-			Subject:     description,
-			OutTradeNo:  utils.Uint64ToStr(orderID),
-			TotalAmount: utils.Int64FenToStrYuan(amount),          // 金额单位为元
-			ProductCode: "FAST_INSTANT_TRADE_PAY",                 // 支付宝产品码默认
-			TimeExpire:  timeExpire.Format("2006-01-02 15:04:05"), // 订单失效时间, 格式为yyyy-MM-dd HH:mm:ss
-		},
+		Trade: a.trade(orderID, amount, description, returnURL, "FAST_INSTANT_TRADE_PAY", timeExpire),
 		// 是否自定义二维码
 		// 文档:https://opendocs.alipay.com/open/59da99d0_alipay.trade.page.pay?scene=22&pathHash=e26b497f
 		QRPayMode:   "4",   // 使用二维码支付模式
@@ -128,7 +172,7 @@ func (a *Alipay) Prepay(orderID uint64, amount int64, description, returnURL str
 
 	url, err := a.Client.TradePagePay(p)
 	if err != nil {
-		return "", fmt.Errorf("alipay prepay error: %w", err)
+		return "", newError(PayTypeAlipay, ErrProviderUnavailable, "", err, "alipay prepay error")
 	}
 
 	// 打印日志确认支付宝支付链接生成成功
@@ -138,23 +182,106 @@ func (a *Alipay) Prepay(orderID uint64, amount int64, description, returnURL str
 	return url.String(), err
 }
 
+// PrepayWap 手机网站支付, 返回支付链接, 在手机浏览器中打开后唤起支付宝完成支付
+//   - orderID: 订单ID
+//   - amount: 金额，单位为分
+//   - description: 商品描述
+//   - returnURL: 支付完成后跳转的页面
+//   - timeExpire: 订单失效时间
+func (a *Alipay) PrepayWap(orderID uint64, amount int64, description, returnURL string, timeExpire time.Time) (string, error) {
+	// 文档: https://opendocs.alipay.com/open/29ae8cb6_alipay.trade.wap.pay
+	var p = alipay.TradeWapPay{
+		Trade:      a.trade(orderID, amount, description, returnURL, "QUICK_WAP_WAY", timeExpire),
+		QuitURL:    a.Conf.QuitURL, // 用户付款中途退出返回商户网站的地址
+		TimeExpire: timeExpire.Format(alipayTimeLayout),
+	}
+
+	url, err := a.Client.TradeWapPay(p)
+	if err != nil {
+		return "", newError(PayTypeAlipay, ErrProviderUnavailable, "", err, "alipay wap prepay error")
+	}
+
+	zap.L().Debug("Alipay wap prepay URL generated successfully", zap.String("url", url.String()))
+
+	return url.String(), nil
+}
+
+// PrepayApp App 支付, 返回 orderInfo 字符串, 由客户端传给支付宝 SDK 唤起支付
+//   - orderID: 订单ID
+//   - amount: 金额，单位为分
+//   - description: 商品描述
+//   - timeExpire: 订单失效时间
+func (a *Alipay) PrepayApp(orderID uint64, amount int64, description string, timeExpire time.Time) (string, error) {
+	// 文档: https://opendocs.alipay.com/open/cd12c885_alipay.trade.app.pay
+	var p = alipay.TradeAppPay{
+		Trade: a.trade(orderID, amount, description, "", "QUICK_MSECURITY_PAY", timeExpire),
+	}
+
+	orderInfo, err := a.Client.TradeAppPay(p)
+	if err != nil {
+		return "", newError(PayTypeAlipay, ErrProviderUnavailable, "", err, "alipay app prepay error")
+	}
+
+	zap.L().Debug("Alipay app order info generated successfully", zap.Uint64("order_id", orderID))
+
+	return orderInfo, nil
+}
+
+// trade 构建各支付场景通用的交易参数
+//   - productCode: 支付宝产品码
+func (a *Alipay) trade(orderID uint64, amount int64, description, returnURL, productCode string, timeExpire time.Time) alipay.Trade {
+	// 支付结果通知地址
+	notifyURL := fmt.Sprintf("%s/%s%s%s",
+		a.Conf.NotifyHost,
+		a.APIPath,
+		a.PayBasePath,
+		a.Conf.NotifyPath,
+	)
+
+	return alipay.Trade{
+		NotifyURL:   notifyURL,
+		ReturnURL:   returnURL, // 支付完成后跳转的页面
+		Subject:     description,
+		OutTradeNo:  utils.Uint64ToStr(orderID),
+		TotalAmount: utils.Int64FenToStrYuan(amount),     // 金额单位为元
+		ProductCode: productCode,                         // 支付宝产品码
+		TimeExpire:  timeExpire.Format(alipayTimeLayout), // 订单失效时间, 格式为yyyy-MM-dd HH:mm:ss
+	}
+}
+
+// tradeType 根据支付场景获取交易类型
+func (a *Alipay) tradeType() string {
+	switch a.Conf.Scene {
+	case AlipaySceneWap:
+		return AlipayTradeTypeWap
+	case AlipaySceneApp:
+		return AlipayTradeTypeApp
+	default:
+		return AlipayTradeTypePage
+	}
+}
+
 // GetNotifyPayment 支付宝支付实现应答支付结果通知接口, 包含验签和获取支付结果
 func (a *Alipay) GetNotifyPayment(request *http.Request) (bool, *PaymentResult, error) {
 	// 文档: https://github.com/smartwalle/alipay/tree/master
 	if err := request.ParseForm(); err != nil {
 		// 如果 err 不为空，则表示解析表单失败
-		return false, nil, fmt.Errorf("alipay notify parse form error: %w", err)
+		return false, nil, newError(PayTypeAlipay, ErrInvalidNotify, "", err, "alipay notify parse form error")
 	}
 
 	notif, err := a.Client.DecodeNotification(request.Form)
 	if err != nil {
+		if notifyDebug.Load() {
+			alipayNotifyError(request.Form, err).log()
+		}
+
 		// 如果 err 不为空，则表示验签失败
-		return false, nil, fmt.Errorf("alipay notify verify sign error: %w", err)
+		return false, nil, newError(PayTypeAlipay, ErrInvalidNotify, "", err, "alipay notify verify sign error")
 	}
 
 	// 为了确保支付状态正确，检查 TradeStatus
 	if notif.TradeStatus != alipay.TradeStatusSuccess && notif.TradeStatus != alipay.TradeStatusFinished {
-		return false, nil, fmt.Errorf("alipay trade status not success: %s", notif.TradeStatus)
+		return false, nil, newError(PayTypeAlipay, ErrUnknownStatus, string(notif.TradeStatus), nil, "alipay trade status not success: %s", notif.TradeStatus)
 	}
 
 	result := &PaymentResult{
@@ -163,11 +290,16 @@ func (a *Alipay) GetNotifyPayment(request *http.Request) (bool, *PaymentResult,
 		TotalAmount:   utils.StrYuanToInt64Fen(notif.TotalAmount), // 转换为分
 		TransactionID: notif.TradeNo,
 		TradeState:    TradeStatePaid,
-		TradeType:     AlipayTradeTypePage,
+		TradeType:     a.tradeType(),
 		AppID:         notif.AppId,    // 支付宝应用ID
 		SellerID:      notif.SellerId, // 支付宝商户ID
 	}
 
+	// 已处理的通知返回结果和 ErrDuplicateNotify, 调用方直接应答成功; 正在处理的通知返回 ErrNotifyProcessing, 调用方应答失败
+	if err = dedupeNotify(request.Context(), a.Deduper, PayTypeAlipay, PaymentNotifyKey(result)); err != nil {
+		return false, result, err
+	}
+
 	return true, result, nil
 }
 
@@ -178,41 +310,46 @@ func (a *Alipay) GetNotifyPayment(request *http.Request) (bool, *PaymentResult,
 func (a *Alipay) ValidateNotifyPayment(payment *PaymentResult, orderID uint64, amount int64) (bool, *PaymentResult, error) {
 	// 校验 payment 是否为 nil
 	if payment == nil {
-		return false, nil, fmt.Errorf("alipay validate notify payment error: payment is nil")
+		return false, nil, newError(PayTypeAlipay, ErrInvalidNotify, "", nil, "alipay validate notify payment error: payment is nil")
 	}
 
 	// 校验订单号
 	if payment.OrderID != orderID {
-		return false, nil, fmt.Errorf("alipay validate notify payment error: order ID mismatch, expected %d, got %d", orderID, payment.OrderID)
+		return false, nil, newError(PayTypeAlipay, ErrOrderMismatch, "", nil, "alipay validate notify payment error: order ID mismatch, expected %d, got %d", orderID, payment.OrderID)
 	}
 
 	// 校验金额
 	if payment.TotalAmount != amount {
-		return false, nil, fmt.Errorf("alipay validate notify payment error: amount mismatch, expected %d, got %d", amount, payment.TotalAmount)
+		return false, nil, newError(PayTypeAlipay, ErrAmountMismatch, "", nil, "alipay validate notify payment error: amount mismatch, expected %d, got %d", amount, payment.TotalAmount)
 	}
 
 	// 校验商户号
 	if payment.SellerID != a.Conf.SellerID {
-		return false, nil, fmt.Errorf("alipay validate notify payment error: seller ID mismatch expected %s, got %s", a.Conf.SellerID, payment.SellerID)
+		return false, nil, newError(PayTypeAlipay, ErrMerchantMismatch, "", nil, "alipay validate notify payment error: seller ID mismatch expected %s, got %s", a.Conf.SellerID, payment.SellerID)
 	}
 
 	// 校验应用ID
 	if payment.AppID != a.Conf.AppID {
-		return false, nil, fmt.Errorf("alipay validate notify payment error: app ID mismatch, expected %s, got %s", a.Conf.AppID, payment.AppID)
+		return false, nil, newError(PayTypeAlipay, ErrMerchantMismatch, "", nil, "alipay validate notify payment error: app ID mismatch, expected %s, got %s", a.Conf.AppID, payment.AppID)
 	}
 
 	return true, payment, nil
 }
 
-// QueryPayment 支付宝支付实现查询支付结果接口
+// QueryPayment 兼容旧版本的查询支付结果接口, 等价于 QueryPaymentContext(context.Background(), orderID)
 func (a *Alipay) QueryPayment(orderID uint64) (*PaymentResult, error) {
+	return a.QueryPaymentContext(context.Background(), orderID)
+}
+
+// QueryPaymentContext 支付宝支付实现查询支付结果接口
+func (a *Alipay) QueryPaymentContext(ctx context.Context, orderID uint64) (*PaymentResult, error) {
 	var p = alipay.TradeQuery{
 		OutTradeNo: utils.Uint64ToStr(orderID),
 	}
 
-	resultQuery, err := a.Client.TradeQuery(context.Background(), p)
+	resultQuery, err := a.Client.TradeQuery(ctx, p)
 	if err != nil {
-		return nil, fmt.Errorf("alipay query payment error: %w", err)
+		return nil, newError(PayTypeAlipay, ErrProviderUnavailable, "", err, "alipay query payment error")
 	}
 
 	// 支付结果
@@ -221,7 +358,7 @@ func (a *Alipay) QueryPayment(orderID uint64) (*PaymentResult, error) {
 		OrderID:       orderID,
 		TotalAmount:   utils.StrYuanToInt64Fen(resultQuery.TotalAmount), // 转换为分
 		TransactionID: resultQuery.TradeNo,
-		TradeType:     AlipayTradeTypePage,
+		TradeType:     a.tradeType(),
 	}
 
 	// 处理没有查询到订单的情况, 说明没有执行支付
@@ -233,20 +370,9 @@ func (a *Alipay) QueryPayment(orderID uint64) (*PaymentResult, error) {
 		return result, nil
 	}
 
-	var state TradeState
-
-	// 状态对齐
-	switch resultQuery.TradeStatus {
-	case alipay.TradeStatusWaitBuyerPay: // 等待买家付款
-		state = TradeStateUnpaid
-	case alipay.TradeStatusClosed: // 交易关闭
-		state = TradeStateClosed
-	case alipay.TradeStatusSuccess: // 交易支付成功
-		state = TradeStatePaid
-	case alipay.TradeStatusFinished: // 交易结束，不可退款
-		state = TradeStatePaid
-	default:
-		return nil, fmt.Errorf("alipay trade status not recognized: %s", resultQuery.TradeStatus)
+	state, err := alipayTradeState(resultQuery.TradeStatus)
+	if err != nil {
+		return nil, err
 	}
 
 	// 设置支付状态
@@ -255,15 +381,20 @@ func (a *Alipay) QueryPayment(orderID uint64) (*PaymentResult, error) {
 	return result, nil
 }
 
-// CloseOrder 支付宝支付实现关闭订单接口
+// CloseOrder 兼容旧版本的关闭订单接口, 等价于 CloseOrderContext(context.Background(), orderID)
 func (a *Alipay) CloseOrder(orderID uint64) error {
+	return a.CloseOrderContext(context.Background(), orderID)
+}
+
+// CloseOrderContext 支付宝支付实现关闭订单接口
+func (a *Alipay) CloseOrderContext(ctx context.Context, orderID uint64) error {
 	var p = alipay.TradeClose{
 		OutTradeNo: utils.Uint64ToStr(orderID),
 	}
 
-	result, err := a.Client.TradeClose(context.Background(), p)
+	result, err := a.Client.TradeClose(ctx, p)
 	if err != nil {
-		return fmt.Errorf("alipay cancel order error: %w", err)
+		return newError(PayTypeAlipay, ErrProviderUnavailable, "", err, "alipay cancel order error")
 	}
 
 	// 用户未进行交互比如扫码或者登录，支付宝远端不会创建订单会得到一个 40004 的错误码
@@ -275,7 +406,7 @@ func (a *Alipay) CloseOrder(orderID uint64) error {
 
 	// 如果返回的 code 是失败状态，记录日志并返回错误
 	if result.Code.IsFailure() {
-		return fmt.Errorf("alipay cancel order failed: code %s, msg %s", result.Code, result.Msg)
+		return newError(PayTypeAlipay, alipayErrorKind(result.SubCode), string(result.Code), nil, "alipay cancel order failed: sub_code %s, msg %s", result.SubCode, result.Msg)
 	}
 
 	zap.L().Info("Alipay order closed successfully", zap.Uint64("order_id", orderID))
@@ -283,38 +414,60 @@ func (a *Alipay) CloseOrder(orderID uint64) error {
 	return nil
 }
 
-// Refund 支付宝支付实现退款接口
+// Refund 兼容旧版本的退款接口, 等价于 RefundContext(context.Background(), ...)
 func (a *Alipay) Refund(orderID, refundID uint64, amount, refundAmount int64, reason string) (*RefundResult, error) {
-	refundAmountYuan := utils.Int64FenToStrYuan(refundAmount) // 将分转换为元，保留两位小数
+	return a.RefundContext(context.Background(), orderID, refundID, amount, refundAmount, reason)
+}
+
+// RefundContext 支付宝支付实现退款接口
+func (a *Alipay) RefundContext(ctx context.Context, orderID, refundID uint64, amount, refundAmount int64, reason string) (*RefundResult, error) {
+	// 文档: https://opendocs.alipay.com/open/357441a2_alipay.trade.fastpay.refund.query?scene=common&pathHash=01981dca
+	// 当退款金额小于订单金额时，需要传入 OutRequestNo, 使用退款ID
+	outRequestNo := ""
+	if refundAmount < amount {
+		outRequestNo = utils.Uint64ToStr(refundID)
+	}
+
+	return a.RefundWithRequestNoContext(ctx, orderID, refundID, amount, refundAmount, reason, outRequestNo)
+}
+
+// RefundWithRequestNo 兼容旧版本, 等价于 RefundWithRequestNoContext(context.Background(), ...)
+func (a *Alipay) RefundWithRequestNo(orderID, refundID uint64, amount, refundAmount int64, reason, outRequestNo string) (*RefundResult, error) {
+	return a.RefundWithRequestNoContext(context.Background(), orderID, refundID, amount, refundAmount, reason, outRequestNo)
+}
 
-	// 默认当退款金额等于订单金额时，OutRequestNo 使用订单ID
-	outRequestNo := utils.Uint64ToStr(orderID)
+// RefundWithRequestNoContext 使用指定的退款请求号退款, 同一订单多次部分退款时每次的 outRequestNo 必须不同,
+// 否则支付宝视为同一笔退款的重试. RefundLedger 会自动生成唯一的退款请求号.
+//   - orderID: 订单ID
+//   - refundID: 退款ID
+//   - amount: 订单总金额，单位为分
+//   - refundAmount: 退款金额，单位为分
+//   - reason: 退款原因
+//   - outRequestNo: 退款请求号, 为空时表示全额退款, 使用订单ID
+func (a *Alipay) RefundWithRequestNoContext(ctx context.Context, orderID, refundID uint64, amount, refundAmount int64, reason, outRequestNo string) (*RefundResult, error) {
+	refundAmountYuan := utils.Int64FenToStrYuan(refundAmount) // 将分转换为元，保留两位小数
 
 	// 网站端支付使用 TradePagePay
 	var p = alipay.TradeRefund{
 		OutTradeNo:   utils.Uint64ToStr(orderID),
 		RefundReason: reason,
 		RefundAmount: refundAmountYuan, // 退款金额，单位为元
+		OutRequestNo: outRequestNo,
 	}
 
-	// 文档: https://opendocs.alipay.com/open/357441a2_alipay.trade.fastpay.refund.query?scene=common&pathHash=01981dca
-	// 当退款金额小于订单金额时，需要传入 OutRequestNo
-	if refundAmount < amount {
-		// 当退款金额小于订单金额时，OutRequestNo 使用退款ID
-		outRequestNo = utils.Uint64ToStr(refundID)
-		p.OutRequestNo = outRequestNo
-	} else {
-		// 当退款金额等于订单金额时，OutRequestNo 使用订单ID
+	if outRequestNo == "" {
+		// 全额退款时 OutRequestNo 使用订单ID
+		outRequestNo = utils.Uint64ToStr(orderID)
 		refundID = orderID
 	}
 
-	result, err := a.Client.TradeRefund(context.Background(), p)
+	result, err := a.Client.TradeRefund(ctx, p)
 	if err != nil {
-		return nil, fmt.Errorf("alipay refund error: %w", err)
+		return nil, newError(PayTypeAlipay, ErrProviderUnavailable, "", err, "alipay refund error")
 	}
 
 	if result.Code.IsFailure() {
-		return nil, fmt.Errorf("alipay refund failed: code %s, msg %s", result.Code, result.Msg)
+		return nil, newError(PayTypeAlipay, alipayErrorKind(result.SubCode), string(result.Code), nil, "alipay refund failed: sub_code %s, msg %s", result.SubCode, result.Msg)
 	}
 
 	zap.L().Debug("Alipay refund successful", zap.Uint64("order_id", orderID), zap.Uint64("refund_id", refundID))
@@ -347,26 +500,44 @@ func (a *Alipay) GetNotifyRefund(request *http.Request) (bool, *RefundResult, er
 	return true, result, nil
 }
 
-// QueryRefund 支付宝支付实现查询退款结果接口
+// QueryRefund 兼容旧版本的查询退款结果接口, 等价于 QueryRefundContext(context.Background(), orderID, refundID)
 func (a *Alipay) QueryRefund(orderID, refundID uint64) (*RefundResult, error) {
+	return a.QueryRefundContext(context.Background(), orderID, refundID)
+}
+
+// QueryRefundContext 支付宝支付实现查询退款结果接口
+func (a *Alipay) QueryRefundContext(ctx context.Context, orderID, refundID uint64) (*RefundResult, error) {
+	return a.QueryRefundByRequestNoContext(ctx, orderID, refundID, utils.Uint64ToStr(refundID))
+}
+
+// QueryRefundByRequestNo 兼容旧版本, 等价于 QueryRefundByRequestNoContext(context.Background(), ...)
+func (a *Alipay) QueryRefundByRequestNo(orderID, refundID uint64, outRequestNo string) (*RefundResult, error) {
+	return a.QueryRefundByRequestNoContext(context.Background(), orderID, refundID, outRequestNo)
+}
+
+// QueryRefundByRequestNoContext 按退款请求号查询退款结果, 用于查询 RefundWithRequestNoContext 发起的退款
+//   - orderID: 订单ID
+//   - refundID: 退款ID, 只用于填充返回结果
+//   - outRequestNo: 退款请求号
+func (a *Alipay) QueryRefundByRequestNoContext(ctx context.Context, orderID, refundID uint64, outRequestNo string) (*RefundResult, error) {
 	var p = alipay.TradeFastPayRefundQuery{
 		OutTradeNo:   utils.Uint64ToStr(orderID),
-		OutRequestNo: utils.Uint64ToStr(refundID),
+		OutRequestNo: outRequestNo,
 	}
 
-	resultQuery, err := a.Client.TradeFastPayRefundQuery(context.Background(), p)
+	resultQuery, err := a.Client.TradeFastPayRefundQuery(ctx, p)
 	if err != nil {
-		return nil, fmt.Errorf("alipay query refund error: %w", err)
+		return nil, newError(PayTypeAlipay, ErrProviderUnavailable, "", err, "alipay query refund error")
 	}
 
 	// 处理没有查询到订单的情况
 	if resultQuery.Code.IsFailure() {
-		return nil, fmt.Errorf("支付宝退款查询，该订单不存在, 订单id: %d, 退款id: %d", orderID, refundID)
+		return nil, newError(PayTypeAlipay, ErrOrderNotFound, string(resultQuery.Code), nil, "支付宝退款查询，该订单不存在, 订单id: %d, 退款id: %d", orderID, refundID)
 	}
 
 	// 状态对齐
 	if resultQuery.RefundStatus != AlipayTradeTypeRefundSuccess {
-		return nil, fmt.Errorf("alipay refund status not recognized: %s", resultQuery.RefundStatus)
+		return nil, newError(PayTypeAlipay, ErrUnknownStatus, resultQuery.RefundStatus, nil, "alipay refund status not recognized: %s", resultQuery.RefundStatus)
 	}
 
 	resultRefund := &RefundResult{
@@ -382,3 +553,36 @@ func (a *Alipay) QueryRefund(orderID, refundID uint64) (*RefundResult, error) {
 
 	return resultRefund, nil
 }
+
+// alipayTradeState 支付宝交易状态对齐, 电脑网站、手机网站和 App 支付的交易状态相同
+func alipayTradeState(status alipay.TradeStatus) (TradeState, error) {
+	switch status {
+	case alipay.TradeStatusWaitBuyerPay: // 等待买家付款
+		return TradeStateUnpaid, nil
+	case alipay.TradeStatusClosed: // 交易关闭
+		return TradeStateClosed, nil
+	case alipay.TradeStatusSuccess: // 交易支付成功
+		return TradeStatePaid, nil
+	case alipay.TradeStatusFinished: // 交易结束，不可退款
+		return TradeStatePaid, nil
+	default:
+		return "", newError(PayTypeAlipay, ErrUnknownStatus, string(status), nil, "alipay trade status not recognized: %s", status)
+	}
+}
+
+// alipayErrorKind 根据支付宝返回的 sub_code 归类错误类别
+// 文档: https://opendocs.alipay.com/open/357441a2_alipay.trade.fastpay.refund.query?scene=common&pathHash=01981dca
+func alipayErrorKind(subCode string) ErrorKind {
+	switch subCode {
+	case "ACQ.TRADE_NOT_EXIST": // 交易不存在
+		return ErrOrderNotFound
+	case "ACQ.SELLER_BALANCE_NOT_ENOUGH": // 卖家余额不足
+		return ErrInsufficientBalance
+	case "ACQ.REFUND_AMT_NOT_EQUAL_TOTAL": // 退款金额超限
+		return ErrAmountMismatch
+	case "ACQ.SYSTEM_ERROR": // 系统错误
+		return ErrProviderUnavailable
+	default:
+		return ErrProviderRejected
+	}
+}
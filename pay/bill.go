@@ -0,0 +1,453 @@
+//
+// FilePath    : go-utils\pay\bill.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 下载和解析微信支付、支付宝账单, 转换为标准化的 BillRecord
+//
+
+package pay
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha1" //nolint:gosec // 微信支付账单摘要算法为 SHA1
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jiaopengzi/go-utils"
+	"github.com/smartwalle/alipay/v3"
+	"github.com/wechatpay-apiv3/wechatpay-go/core"
+	"github.com/wechatpay-apiv3/wechatpay-go/core/consts"
+	"github.com/wechatpay-apiv3/wechatpay-go/core/option"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// billTimeLayout 账单中的时间格式
+const billTimeLayout = "2006-01-02 15:04:05"
+
+// DownloadBill 微信支付实现 BillDownloader 接口, 下载交易账单或基本账户资金账单
+func (w *WeChatPay) DownloadBill(ctx context.Context, billType BillType, date time.Time) ([]BillRecord, error) {
+	billDate := date.Format(time.DateOnly)
+
+	var requestURL string
+
+	// 文档: https://pay.weixin.qq.com/doc/v3/merchant/4012791876
+	switch billType {
+	case BillTypeTrade:
+		requestURL = fmt.Sprintf("%s/v3/bill/tradebill?bill_date=%s&bill_type=ALL", consts.WechatPayAPIServer, billDate)
+	case BillTypeFundFlow:
+		requestURL = fmt.Sprintf("%s/v3/bill/fundflowbill?bill_date=%s&account_type=BASIC", consts.WechatPayAPIServer, billDate)
+	default:
+		return nil, newError(PayTypeWechat, ErrInvalidBill, "", nil, "unsupported bill type: %s", billType)
+	}
+
+	// 第一步: 获取账单下载地址和摘要, 正常验证应答签名
+	result, err := w.Client.Get(ctx, requestURL)
+	if err != nil {
+		return nil, wechatAPIError(err, "WeChatPay bill query error")
+	}
+
+	defer func() { _ = result.Response.Body.Close() }()
+
+	var bill struct {
+		HashType    string `json:"hash_type"`
+		HashValue   string `json:"hash_value"`
+		DownloadURL string `json:"download_url"`
+	}
+
+	if err = json.NewDecoder(result.Response.Body).Decode(&bill); err != nil {
+		return nil, newError(PayTypeWechat, ErrInvalidBill, "", err, "WeChatPay bill response decode error")
+	}
+
+	// 第二步: 下载账单文件, 应答没有签名, 需要使用不验证应答签名的客户端
+	client, err := core.NewClient(ctx,
+		option.WithMerchantCredential(w.Conf.MchID, w.Conf.MchCertificateSerialNumber, w.PrivateKey),
+		option.WithoutValidator(),
+	)
+	if err != nil {
+		return nil, newError(PayTypeWechat, ErrInvalidConfig, "", err, "create WeChatPay bill download client error")
+	}
+
+	file, err := client.Get(ctx, bill.DownloadURL)
+	if err != nil {
+		return nil, wechatAPIError(err, "WeChatPay bill download error")
+	}
+
+	defer func() { _ = file.Response.Body.Close() }()
+
+	data, err := io.ReadAll(file.Response.Body)
+	if err != nil {
+		return nil, newError(PayTypeWechat, ErrProviderUnavailable, "", err, "WeChatPay bill read error")
+	}
+
+	// 使用第一步获取的摘要校验文件完整性
+	sum := sha1.Sum(data) //nolint:gosec // 微信支付账单摘要算法为 SHA1
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), bill.HashValue) {
+		return nil, newError(PayTypeWechat, ErrInvalidBill, "", nil, "WeChatPay bill %s hash mismatch", bill.HashType)
+	}
+
+	return ParseWechatBill(data, billType)
+}
+
+// ParseWechatBill 解析微信支付账单文件, 字段值以 ` 开头, 数据行之后为汇总行
+//   - data: 账单文件内容(未压缩)
+//   - billType: 账单类型
+func ParseWechatBill(data []byte, billType BillType) ([]BillRecord, error) {
+	rows, err := readBillRows(data, func(row []string) bool {
+		// 汇总行的表头没有 ` 前缀
+		return len(row) > 0 && strings.HasPrefix(strings.TrimSpace(row[0]), "`")
+	})
+	if err != nil {
+		return nil, newError(PayTypeWechat, ErrInvalidBill, "", err, "WeChatPay bill parse error")
+	}
+
+	records := make([]BillRecord, 0, len(rows))
+
+	for i, row := range rows {
+		record := BillRecord{PayType: PayTypeWechat}
+
+		switch billType {
+		case BillTypeTrade:
+			record.OrderID = utils.StrToUint64(row.get("商户订单号"))
+			record.TransactionID = row.get("微信订单号")
+			record.RefundID = row.get("商户退款单号")
+			record.TradeType = row.get("交易类型")
+			record.TradeState = wechatBillTradeState(row.get("交易状态"))
+			record.TotalAmount = row.amount(cmp.Or(row.get("订单金额"), row.get("应结订单金额")))
+			record.RefundAmount = row.amount(row.get("退款金额"))
+			record.Fee = row.amount(row.get("手续费"))
+			record.TradeTime = row.time("交易时间")
+		case BillTypeFundFlow:
+			record.OrderID = utils.StrToUint64(row.get("业务凭证号"))
+			record.TransactionID = row.get("微信支付业务单号")
+			record.TradeType = row.get("业务类型")
+			record.TradeTime = row.time("记账时间")
+
+			amount := row.amount(row.get("收支金额"))
+			if row.get("收支类型") == "支出" {
+				record.TradeState, record.RefundAmount = TradeStateRefunded, amount
+			} else {
+				record.TradeState, record.TotalAmount = TradeStatePaid, amount
+			}
+		default:
+			return nil, newError(PayTypeWechat, ErrInvalidBill, "", nil, "unsupported bill type: %s", billType)
+		}
+
+		if row.err != nil {
+			return nil, newError(PayTypeWechat, ErrInvalidBill, "", row.err, "WeChatPay bill row %d parse error", i+1)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// wechatBillTradeState 微信支付账单交易状态对齐
+func wechatBillTradeState(state string) TradeState {
+	switch state {
+	case TradeStateWechatPaySuccess:
+		return TradeStatePaid
+	case TradeStateWechatPayRefund:
+		return TradeStateRefunded
+	case TradeStateWechatPayRevoked, TradeStateWechatPayClosed:
+		return TradeStateClosed
+	default:
+		return TradeStateUnpaid
+	}
+}
+
+// DownloadBill 支付宝实现 BillDownloader 接口, 下载交易账单或账务账单(signcustomer)
+func (a *Alipay) DownloadBill(ctx context.Context, billType BillType, date time.Time) ([]BillRecord, error) {
+	var p = alipay.BillDownloadURLQuery{BillDate: date.Format(time.DateOnly)}
+
+	// 文档: https://opendocs.alipay.com/open/02e7gr
+	switch billType {
+	case BillTypeTrade:
+		p.BillType = "trade"
+	case BillTypeFundFlow:
+		p.BillType = "signcustomer"
+	default:
+		return nil, newError(PayTypeAlipay, ErrInvalidBill, "", nil, "unsupported bill type: %s", billType)
+	}
+
+	result, err := a.Client.BillDownloadURLQuery(ctx, p)
+	if err != nil {
+		return nil, newError(PayTypeAlipay, ErrProviderUnavailable, "", err, "alipay bill query error")
+	}
+
+	if result.Code.IsFailure() {
+		return nil, newError(PayTypeAlipay, alipayErrorKind(result.SubCode), string(result.Code), nil, "alipay bill query failed: sub_code %s, msg %s", result.SubCode, result.Msg)
+	}
+
+	// 下载地址有效期 30 秒, 文件为 zip 压缩包
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, result.BillDownloadURL, http.NoBody)
+	if err != nil {
+		return nil, newError(PayTypeAlipay, ErrInvalidBill, "", err, "alipay bill download request error")
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, newError(PayTypeAlipay, ErrProviderUnavailable, "", err, "alipay bill download error")
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, newError(PayTypeAlipay, ErrProviderUnavailable, strconv.Itoa(response.StatusCode), nil, "alipay bill download status error")
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, newError(PayTypeAlipay, ErrProviderUnavailable, "", err, "alipay bill read error")
+	}
+
+	return ParseAlipayBill(data, billType)
+}
+
+// ParseAlipayBill 解析支付宝账单压缩包, 读取其中的明细文件(忽略汇总文件), 文件编码为 GBK
+//   - data: 账单 zip 压缩包内容
+//   - billType: 账单类型
+func ParseAlipayBill(data []byte, billType BillType) ([]BillRecord, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, newError(PayTypeAlipay, ErrInvalidBill, "", err, "alipay bill unzip error")
+	}
+
+	for _, f := range zr.File {
+		name := decodeGBK([]byte(f.Name))
+		if !strings.HasSuffix(name, ".csv") || strings.Contains(name, "汇总") {
+			continue
+		}
+
+		content, err := readZipFile(f)
+		if err != nil {
+			return nil, newError(PayTypeAlipay, ErrInvalidBill, "", err, "alipay bill read %s error", name)
+		}
+
+		return parseAlipayBillCSV([]byte(decodeGBK(content)), billType)
+	}
+
+	return nil, newError(PayTypeAlipay, ErrInvalidBill, "", nil, "alipay bill detail file not found")
+}
+
+// parseAlipayBillCSV 解析支付宝明细文件, 以 # 开头的行为说明行
+func parseAlipayBillCSV(data []byte, billType BillType) ([]BillRecord, error) {
+	var lines [][]byte
+
+	for line := range bytes.SplitSeq(data, []byte("\n")) {
+		if !bytes.HasPrefix(bytes.TrimSpace(line), []byte("#")) {
+			lines = append(lines, line)
+		}
+	}
+
+	rows, err := readBillRows(bytes.Join(lines, []byte("\n")), nil)
+	if err != nil {
+		return nil, newError(PayTypeAlipay, ErrInvalidBill, "", err, "alipay bill parse error")
+	}
+
+	records := make([]BillRecord, 0, len(rows))
+
+	for i, row := range rows {
+		record := BillRecord{PayType: PayTypeAlipay, OrderID: utils.StrToUint64(row.get("商户订单号"))}
+
+		switch billType {
+		case BillTypeTrade:
+			record.TransactionID = row.get("支付宝交易号")
+			record.TradeType = row.get("业务类型")
+			record.TradeTime = row.time("完成时间")
+			record.Fee = abs(row.amount(row.get("服务费")))
+
+			amount := abs(row.amount(row.get("订单金额")))
+			if record.TradeType == "退款" {
+				record.TradeState, record.RefundAmount = TradeStateRefunded, amount
+				record.RefundID = row.get("退款批次号/请求号")
+			} else {
+				record.TradeState, record.TotalAmount = TradeStatePaid, amount
+			}
+		case BillTypeFundFlow:
+			record.TransactionID = row.get("业务流水号")
+			record.TradeType = row.get("业务类型")
+			record.TradeTime = row.time("发生时间")
+			record.TotalAmount = abs(row.amount(row.get("收入金额")))
+			record.RefundAmount = abs(row.amount(row.get("支出金额")))
+
+			record.TradeState = TradeStatePaid
+			if record.RefundAmount > 0 {
+				record.TradeState = TradeStateRefunded
+			}
+		default:
+			return nil, newError(PayTypeAlipay, ErrInvalidBill, "", nil, "unsupported bill type: %s", billType)
+		}
+
+		if row.err != nil {
+			return nil, newError(PayTypeAlipay, ErrInvalidBill, "", row.err, "alipay bill row %d parse error", i+1)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// billRow 账单数据行, 按表头获取字段值, 解析失败时记录第一个错误
+type billRow struct {
+	index map[string]int // 表头 -> 列序号
+	cells []string
+	err   error
+}
+
+// readBillRows 读取 CSV 账单, 第一行为表头, 表头中的单位说明会被去掉, 例如 订单金额（元） 为 订单金额
+//   - data: CSV 内容
+//   - isData: 判断是否为数据行, 返回 false 时停止读取; 为 nil 时读取所有非空行
+func readBillRows(data []byte, isData func(row []string) bool) ([]*billRow, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("bill header not found")
+	}
+
+	index := make(map[string]int, len(records[0]))
+
+	for i, name := range records[0] {
+		name = strings.TrimSpace(name)
+		if cut := strings.IndexAny(name, "(（"); cut > 0 {
+			name = name[:cut]
+		}
+
+		index[name] = i
+	}
+
+	rows := make([]*billRow, 0, len(records)-1)
+
+	for _, cells := range records[1:] {
+		if len(cells) == 0 || (len(cells) == 1 && strings.TrimSpace(cells[0]) == "") {
+			continue
+		}
+
+		if isData != nil && !isData(cells) {
+			break
+		}
+
+		rows = append(rows, &billRow{index: index, cells: cells})
+	}
+
+	return rows, nil
+}
+
+// get 获取字段值, 去掉空白和微信支付账单的 ` 前缀, 字段不存在时返回空字符串
+func (r *billRow) get(name string) string {
+	i, ok := r.index[name]
+	if !ok || i >= len(r.cells) {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(r.cells[i]), "`"))
+}
+
+// amount 将元转换为分, 使用十进制解析避免浮点误差
+func (r *billRow) amount(s string) int64 {
+	v, err := parseYuan(s)
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+
+	return v
+}
+
+// time 按 UTC+8 解析时间字段, 为空时返回零值
+func (r *billRow) time(name string) time.Time {
+	s := r.get(name)
+	if s == "" {
+		return time.Time{}
+	}
+
+	t, err := time.ParseInLocation(billTimeLayout, s, chinaLocation)
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("%s: %w", name, err)
+	}
+
+	return t
+}
+
+// parseYuan 将元转换为分, 例如 "12.34" 为 1234, "-0.5" 为 -50, 空字符串为 0, 最多两位小数
+func parseYuan(s string) (int64, error) {
+	s = strings.NewReplacer(",", "", "¥", "", "+", "").Replace(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+
+	negative := strings.HasPrefix(s, "-")
+	intPart, fracPart, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+
+	if len(fracPart) > 2 || (intPart == "" && fracPart == "") {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+
+	// 小数部分补齐两位, 例如 1.5 为 150
+	fracPart += strings.Repeat("0", 2-len(fracPart))
+
+	fen, err := strconv.ParseInt(cmp.Or(intPart, "0")+fracPart, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+
+	if negative {
+		fen = -fen
+	}
+
+	return fen, nil
+}
+
+// abs 绝对值
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+// decodeGBK 将 GBK 编码转换为 UTF-8, 已经是 UTF-8 时原样返回
+func decodeGBK(data []byte) string {
+	if utf8.Valid(data) {
+		return string(data)
+	}
+
+	decoded, err := simplifiedchinese.GBK.NewDecoder().Bytes(data)
+	if err != nil {
+		return string(data)
+	}
+
+	return string(decoded)
+}
+
+// readZipFile 读取压缩包中的文件
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rc.Close() }()
+
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,247 @@
+//
+// FilePath    : go-utils\pay\bill_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 账单解析测试
+//
+
+package pay
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseYuan(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"12.34", 1234, false},
+		{"1.5", 150, false},
+		{"-0.5", -50, false},
+		{"+3", 300, false},
+		{"1,234.5", 123450, false},
+		{"¥3", 300, false},
+		{" 0.01 ", 1, false},
+		{".5", 50, false},
+		{"", 0, false},
+		{"1.234", 0, true},
+		{"abc", 0, true},
+		{".", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseYuan(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseYuan(%q) = %d, %v, want %d, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseWechatBill(t *testing.T) {
+	paidAt := time.Date(2026, 3, 1, 10, 0, 0, 0, chinaLocation)
+
+	trade := "\ufeff交易时间,微信订单号,商户订单号,交易类型,交易状态,应结订单金额,商户退款单号,退款金额,手续费\n" +
+		"`2026-03-01 10:00:00,`4200001,`1001,`NATIVE,`SUCCESS,`12.34,`,`0.00,`0.07\n" +
+		"`2026-03-01 11:00:00,`4200002,`1002,`JSAPI,`REFUND,`0.00,`R1002,`5.5,`-0.03\n" +
+		"`2026-03-01 12:00:00,`4200003,`ORDER-X,`JSAPI,`CLOSED,`1,`,`0,`0\n" +
+		"总交易单数,应结订单总金额,退款总金额,手续费总金额\n" +
+		"`3,`13.34,`5.50,`0.04\n"
+
+	fundFlow := "记账时间,微信支付业务单号,资金流水单号,业务名称,业务类型,收支类型,收支金额,账户结余,业务凭证号\n" +
+		"`2026-03-01 10:00:00,`4200001,`F1,`交易,`交易,`收入,`12.34,`12.34,`1001\n" +
+		"`2026-03-01 11:00:00,`4200002,`F2,`退款,`退款,`支出,`5.50,`6.84,`1002\n" +
+		"资金流水总笔数,收入笔数,收入金额,支出笔数,支出金额\n" +
+		"`2,`1,`12.34,`1,`5.50\n"
+
+	tests := []struct {
+		name     string
+		data     string
+		billType BillType
+		want     []BillRecord
+	}{
+		{
+			name:     "交易账单",
+			data:     trade,
+			billType: BillTypeTrade,
+			want: []BillRecord{
+				{PayType: PayTypeWechat, OrderID: 1001, TransactionID: "4200001", TradeType: "NATIVE", TradeState: TradeStatePaid, TotalAmount: 1234, Fee: 7, TradeTime: paidAt},
+				{PayType: PayTypeWechat, OrderID: 1002, TransactionID: "4200002", RefundID: "R1002", TradeType: "JSAPI", TradeState: TradeStateRefunded, RefundAmount: 550, Fee: -3, TradeTime: paidAt.Add(time.Hour)},
+				{PayType: PayTypeWechat, TransactionID: "4200003", TradeType: "JSAPI", TradeState: TradeStateClosed, TotalAmount: 100, TradeTime: paidAt.Add(2 * time.Hour)},
+			},
+		},
+		{
+			name:     "资金账单",
+			data:     fundFlow,
+			billType: BillTypeFundFlow,
+			want: []BillRecord{
+				{PayType: PayTypeWechat, OrderID: 1001, TransactionID: "4200001", TradeType: "交易", TradeState: TradeStatePaid, TotalAmount: 1234, TradeTime: paidAt},
+				{PayType: PayTypeWechat, OrderID: 1002, TransactionID: "4200002", TradeType: "退款", TradeState: TradeStateRefunded, RefundAmount: 550, TradeTime: paidAt.Add(time.Hour)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := ParseWechatBill([]byte(tt.data), tt.billType)
+			if err != nil {
+				t.Fatalf("ParseWechatBill() error = %v", err)
+			}
+
+			assertBillRecords(t, records, tt.want)
+		})
+	}
+}
+
+func TestParseWechatBill_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		billType BillType
+	}{
+		{"金额格式错误", "交易时间,商户订单号,应结订单金额\n`2026-03-01 10:00:00,`1001,`1.234\n", BillTypeTrade},
+		{"时间格式错误", "交易时间,商户订单号,应结订单金额\n`2026/03/01,`1001,`1\n", BillTypeTrade},
+		{"空文件", "", BillTypeTrade},
+		{"不支持的账单类型", "交易时间\n`2026-03-01 10:00:00\n", BillType("other")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseWechatBill([]byte(tt.data), tt.billType); !errors.Is(err, ErrInvalidBill) {
+				t.Errorf("ParseWechatBill() error = %v, want ErrInvalidBill", err)
+			}
+		})
+	}
+}
+
+func TestParseAlipayBill(t *testing.T) {
+	paidAt := time.Date(2026, 3, 1, 10, 0, 0, 0, chinaLocation)
+
+	trade := "#支付宝交易记录明细查询\n" +
+		"#账号:[20880000000000000156]\n" +
+		"支付宝交易号,商户订单号,业务类型,商品名称,创建时间,完成时间,订单金额（元）,服务费（元）,退款批次号/请求号\n" +
+		"2026030122001,1001,交易,商品,2026-03-01 09:59:00,2026-03-01 10:00:00,12.34,-0.07,\n" +
+		"2026030122002,1002,退款,商品,2026-03-01 10:59:00,2026-03-01 11:00:00,-5.50,0.03,1002-1\n" +
+		"#-----------------------------------------业务明细列表结束------------------------------------\n" +
+		"#交易合计：1笔，商家实收：12.34元\n"
+
+	fundFlow := "#支付宝账务明细查询\n" +
+		"账务流水号,业务流水号,商户订单号,商品名称,发生时间,对方账号,收入金额（+元）,支出金额（-元）,账户余额（元）,业务类型\n" +
+		"F1,2026030122001,1001,商品,2026-03-01 10:00:00,buyer,12.34,,12.34,在线支付\n" +
+		"F2,2026030122002,1002,商品,2026-03-01 11:00:00,buyer,,-5.50,6.84,交易退款\n" +
+		"#账务明细列表结束\n"
+
+	tests := []struct {
+		name     string
+		data     []byte
+		billType BillType
+		want     []BillRecord
+	}{
+		{
+			name:     "交易账单",
+			data:     alipayBillZip(t, trade),
+			billType: BillTypeTrade,
+			want: []BillRecord{
+				{PayType: PayTypeAlipay, OrderID: 1001, TransactionID: "2026030122001", TradeType: "交易", TradeState: TradeStatePaid, TotalAmount: 1234, Fee: 7, TradeTime: paidAt},
+				{PayType: PayTypeAlipay, OrderID: 1002, TransactionID: "2026030122002", RefundID: "1002-1", TradeType: "退款", TradeState: TradeStateRefunded, RefundAmount: 550, Fee: 3, TradeTime: paidAt.Add(time.Hour)},
+			},
+		},
+		{
+			name:     "账务账单",
+			data:     alipayBillZip(t, fundFlow),
+			billType: BillTypeFundFlow,
+			want: []BillRecord{
+				{PayType: PayTypeAlipay, OrderID: 1001, TransactionID: "2026030122001", TradeType: "在线支付", TradeState: TradeStatePaid, TotalAmount: 1234, TradeTime: paidAt},
+				{PayType: PayTypeAlipay, OrderID: 1002, TransactionID: "2026030122002", TradeType: "交易退款", TradeState: TradeStateRefunded, RefundAmount: 550, TradeTime: paidAt.Add(time.Hour)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := ParseAlipayBill(tt.data, tt.billType)
+			if err != nil {
+				t.Fatalf("ParseAlipayBill() error = %v", err)
+			}
+
+			assertBillRecords(t, records, tt.want)
+		})
+	}
+}
+
+func TestParseAlipayBill_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"不是压缩包", []byte("not a zip")},
+		{"只有汇总文件", alipayBillZip(t, "")},
+		{"金额格式错误", alipayBillZip(t, "商户订单号,业务类型,订单金额（元）\n1001,交易,abc\n")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseAlipayBill(tt.data, BillTypeTrade); !errors.Is(err, ErrInvalidBill) {
+				t.Errorf("ParseAlipayBill() error = %v, want ErrInvalidBill", err)
+			}
+		})
+	}
+}
+
+// alipayBillZip 创建支付宝账单压缩包, 包含汇总文件和 detail 明细文件, detail 为空时不包含明细文件
+func alipayBillZip(t *testing.T, detail string) []byte {
+	t.Helper()
+
+	files := [][2]string{{"20880000000000000156_20260301_业务明细(汇总).csv", "#汇总\n总笔数,总金额\n2,6.84\n"}}
+	if detail != "" {
+		files = append(files, [2]string{"20880000000000000156_20260301_业务明细.csv", detail})
+	}
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	for _, f := range files {
+		w, err := zw.Create(f[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = w.Write([]byte(f[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// assertBillRecords 比对解析结果, 时间使用 Equal 比较
+func assertBillRecords(t *testing.T, got, want []BillRecord) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("records = %+v, want %d records", got, len(want))
+	}
+
+	for i := range want {
+		g, w := got[i], want[i]
+		if !g.TradeTime.Equal(w.TradeTime) {
+			t.Errorf("records[%d].TradeTime = %v, want %v", i, g.TradeTime, w.TradeTime)
+		}
+
+		g.TradeTime, w.TradeTime = time.Time{}, time.Time{}
+		if g != w {
+			t.Errorf("records[%d] = %+v, want %+v", i, g, w)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+//
+// FilePath    : go-utils\pay\dedupe.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 支付结果通知和退款结果通知去重, 支付渠道重发的通知按交易号识别, 处理中应答失败, 已处理应答成功
+//
+
+package pay
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jiaopengzi/go-utils/redis/cache"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// NotifyState 通知的处理状态
+type NotifyState int
+
+// 通知处理状态常量
+const (
+	NotifyStateNew        NotifyState = iota // 第一次收到, 已标记为处理中
+	NotifyStateProcessing                    // 其他请求正在处理
+	NotifyStateDone                          // 已处理成功
+)
+
+// NotificationDeduper 通知去重, 配置到 WeChatPay.Deduper 或 Alipay.Deduper 后,
+// GetNotifyPayment 和 GetNotifyRefund 在验签通过后标记通知为处理中, 业务处理结束后需要调用 FinishNotify.
+// 已处理成功的通知返回 ErrDuplicateNotify, 正在处理的通知返回 ErrNotifyProcessing.
+type NotificationDeduper interface {
+	// Begin 开始处理通知, 第一次收到时标记为处理中并返回 NotifyStateNew.
+	// 处理中标记的有效期较短, 处理过程中进程退出时标记自动过期, 支付渠道重发的通知可以再次处理
+	Begin(ctx context.Context, key string) (NotifyState, error)
+
+	// Done 业务处理成功后标记为已处理
+	Done(ctx context.Context, key string) error
+
+	// Release 业务处理失败时删除处理中标记, 支付渠道重发的通知可以再次处理
+	Release(ctx context.Context, key string) error
+}
+
+// PaymentNotifyKey 支付结果通知的去重键, 由支付渠道和支付渠道交易号组成
+func PaymentNotifyKey(result *PaymentResult) string {
+	return fmt.Sprintf("%s:payment:%s", result.PayType, result.TransactionID)
+}
+
+// RefundNotifyKey 退款结果通知的去重键, 由支付渠道、退款交易号和退款状态组成, 同一笔退款不同状态的通知分别处理
+func RefundNotifyKey(result *RefundResult) string {
+	return fmt.Sprintf("%s:refund:%s:%s", result.PayType, result.RefundTransactionID, result.Status)
+}
+
+// dedupeNotify 标记通知为处理中, 已处理成功时返回 ErrDuplicateNotify, 正在处理时返回 ErrNotifyProcessing.
+// 去重存储不可用时记录警告日志并放行, 由业务回调的幂等保证正确性.
+func dedupeNotify(ctx context.Context, deduper NotificationDeduper, payType PayType, key string) error {
+	if deduper == nil {
+		return nil
+	}
+
+	state, err := deduper.Begin(ctx, key)
+	if err != nil {
+		zap.L().Warn("通知去重标记失败", zap.String("支付渠道", string(payType)), zap.String("去重键", key), zap.Error(err))
+		return nil
+	}
+
+	switch state {
+	case NotifyStateDone:
+		return newError(payType, ErrDuplicateNotify, "", nil, "duplicate notify: %s", key)
+	case NotifyStateProcessing:
+		return newError(payType, ErrNotifyProcessing, "", nil, "notify is processing: %s", key)
+	default:
+		return nil
+	}
+}
+
+// notifyDeduperHolder 配置了通知去重的支付渠道
+type notifyDeduperHolder interface {
+	NotifyDeduper() NotificationDeduper
+}
+
+// NotifyDeduper 获取通知去重, 未配置时为 nil
+func (w *WeChatPay) NotifyDeduper() NotificationDeduper {
+	return w.Deduper
+}
+
+// NotifyDeduper 获取通知去重, 未配置时为 nil
+func (a *Alipay) NotifyDeduper() NotificationDeduper {
+	return a.Deduper
+}
+
+// FinishNotify 结束通知处理, 业务处理成功(handleErr 为 nil)时标记为已处理, 失败时删除处理中标记.
+// RegisterRoutes 注册的路由会自动调用; 直接调用 GetNotifyPayment 或 GetNotifyRefund 时需要在业务处理后调用,
+// 否则处理中标记过期前重发的通知都返回 ErrNotifyProcessing, 过期后再次处理.
+//   - provider: 支付渠道, 未配置通知去重时不做处理
+//   - key: 去重键, 由 PaymentNotifyKey 或 RefundNotifyKey 生成
+//   - handleErr: 业务处理的错误
+func FinishNotify(ctx context.Context, provider Provider, key string, handleErr error) {
+	holder, ok := provider.(notifyDeduperHolder)
+	if !ok || holder.NotifyDeduper() == nil {
+		return
+	}
+
+	deduper := holder.NotifyDeduper()
+
+	if handleErr == nil {
+		if err := deduper.Done(ctx, key); err != nil {
+			// 处理中标记过期后重发的通知会再次处理, 由业务回调的幂等保证正确性
+			zap.L().Warn("通知去重标记已处理失败", zap.String("支付渠道", string(provider.PayType())), zap.String("去重键", key), zap.Error(err))
+		}
+
+		return
+	}
+
+	if err := deduper.Release(ctx, key); err != nil {
+		zap.L().Warn("通知去重标记删除失败", zap.String("支付渠道", string(provider.PayType())), zap.String("去重键", key), zap.Error(err))
+	}
+}
+
+// 通知去重的默认参数
+const (
+	defaultNotifyDedupeTTL     = 48 * time.Hour // 默认已处理标记有效期, 覆盖微信支付和支付宝的通知重发周期(约 24 小时)
+	defaultNotifyProcessingTTL = time.Minute    // 默认处理中标记有效期, 超过后重发的通知可以再次处理
+)
+
+// redis 中保存的通知处理状态
+const (
+	notifyValueProcessing = "processing"
+	notifyValueDone       = "done"
+)
+
+var (
+	// KEYS: 去重标记; ARGV: 处理中状态, 处理中标记有效期(毫秒)
+	// 不存在时标记为处理中并返回空字符串, 否则返回当前状态
+	notifyBegin = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current then
+	return current
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return ''
+`)
+
+	// KEYS: 去重标记; ARGV: 处理中状态
+	// 只删除处理中标记, 不删除已处理标记
+	notifyRelease = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+)
+
+// RedisNotificationDeduper 基于 redis 的通知去重, 多实例部署时共享去重标记
+type RedisNotificationDeduper struct {
+	Cache         *cache.Client // 缓存客户端
+	TTL           time.Duration // 已处理标记有效期, 为 0 时使用 48 小时
+	ProcessingTTL time.Duration // 处理中标记有效期, 需要大于业务回调的耗时, 为 0 时使用 1 分钟
+}
+
+// NewRedisNotificationDeduper 创建基于 redis 的通知去重
+//   - c: 缓存客户端
+//   - ttl: 已处理标记有效期, 为 0 时使用 48 小时
+func NewRedisNotificationDeduper(c *cache.Client, ttl time.Duration) *RedisNotificationDeduper {
+	return &RedisNotificationDeduper{Cache: c, TTL: ttl}
+}
+
+// Begin 实现 NotificationDeduper 接口
+func (d *RedisNotificationDeduper) Begin(ctx context.Context, key string) (NotifyState, error) {
+	ttl := cmp.Or(d.ProcessingTTL, defaultNotifyProcessingTTL)
+
+	current, err := notifyBegin.Run(ctx, d.Cache.Client, []string{d.key(key)}, notifyValueProcessing, ttl.Milliseconds()).Text()
+	if err != nil {
+		return NotifyStateNew, err
+	}
+
+	switch current {
+	case "":
+		return NotifyStateNew, nil
+	case notifyValueDone:
+		return NotifyStateDone, nil
+	default:
+		return NotifyStateProcessing, nil
+	}
+}
+
+// Done 实现 NotificationDeduper 接口
+func (d *RedisNotificationDeduper) Done(ctx context.Context, key string) error {
+	return d.Cache.Client.Set(ctx, d.key(key), notifyValueDone, cmp.Or(d.TTL, defaultNotifyDedupeTTL)).Err()
+}
+
+// Release 实现 NotificationDeduper 接口
+func (d *RedisNotificationDeduper) Release(ctx context.Context, key string) error {
+	return notifyRelease.Run(ctx, d.Cache.Client, []string{d.key(key)}, notifyValueProcessing).Err()
+}
+
+// key 去重标记在 redis 中的完整 key
+func (d *RedisNotificationDeduper) key(key string) string {
+	return d.Cache.Key(cache.GenerateKey("pay_notify", key))
+}
@@ -0,0 +1,237 @@
+//
+// FilePath    : go-utils\pay\dedupe_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 通知去重测试
+//
+
+package pay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// fakeDeduper 内存中的通知去重, err 不为 nil 时 Begin 返回该错误
+type fakeDeduper struct {
+	mu     sync.Mutex
+	states map[string]NotifyState
+	err    error
+}
+
+// Begin 实现 NotificationDeduper 接口
+func (d *fakeDeduper) Begin(_ context.Context, key string) (NotifyState, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.err != nil {
+		return NotifyStateNew, d.err
+	}
+
+	if state, ok := d.states[key]; ok {
+		return state, nil
+	}
+
+	d.set(key, NotifyStateProcessing)
+
+	return NotifyStateNew, nil
+}
+
+// Done 实现 NotificationDeduper 接口
+func (d *fakeDeduper) Done(_ context.Context, key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.set(key, NotifyStateDone)
+
+	return nil
+}
+
+// set 设置去重键的状态, 调用方需要持有锁
+func (d *fakeDeduper) set(key string, state NotifyState) {
+	if d.states == nil {
+		d.states = make(map[string]NotifyState)
+	}
+
+	d.states[key] = state
+}
+
+// Release 实现 NotificationDeduper 接口
+func (d *fakeDeduper) Release(_ context.Context, key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.states[key] == NotifyStateProcessing {
+		delete(d.states, key)
+	}
+
+	return nil
+}
+
+// state 获取去重键的状态, 不存在时返回 false
+func (d *fakeDeduper) state(key string) (NotifyState, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state, ok := d.states[key]
+
+	return state, ok
+}
+
+// fakeNotifyProvider 模拟支付宝通知, 表单参数 trade_no 为支付渠道交易号, 不验签
+type fakeNotifyProvider struct {
+	Provider
+
+	deduper *fakeDeduper
+}
+
+// PayType 实现 Provider 接口
+func (p *fakeNotifyProvider) PayType() PayType {
+	return PayTypeAlipay
+}
+
+// NotifyPaths 实现 NotifyPathProvider 接口
+func (p *fakeNotifyProvider) NotifyPaths() (notifyPath, refundPath string) {
+	return "/notify", ""
+}
+
+// NotifyDeduper 获取通知去重
+func (p *fakeNotifyProvider) NotifyDeduper() NotificationDeduper {
+	return p.deduper
+}
+
+// GetNotifyPayment 实现 Payer 接口
+func (p *fakeNotifyProvider) GetNotifyPayment(request *http.Request) (bool, *PaymentResult, error) {
+	if err := request.ParseForm(); err != nil {
+		return false, nil, err
+	}
+
+	result := &PaymentResult{PayType: PayTypeAlipay, OrderID: 1, TotalAmount: 100, TransactionID: request.Form.Get("trade_no"), TradeState: TradeStatePaid}
+
+	if err := dedupeNotify(request.Context(), p.deduper, PayTypeAlipay, PaymentNotifyKey(result)); err != nil {
+		return false, result, err
+	}
+
+	return true, result, nil
+}
+
+// postNotify 发送支付结果通知, 返回应答内容
+func postNotify(engine *gin.Engine, tradeNo string) string {
+	form := url.Values{"trade_no": {tradeNo}}
+	req := httptest.NewRequest(http.MethodPost, "/pay/notify", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	return w.Body.String()
+}
+
+// newNotifyEngine 注册 provider 的通知路由
+func newNotifyEngine(provider Provider, onPayment func(context.Context, Provider, *PaymentResult) error) *gin.Engine {
+	engine := gin.New()
+	RegisterRoutes(engine.Group("/pay"), provider, PaymentCallbacks{OnPayment: onPayment})
+
+	return engine
+}
+
+// TestNotifyDedupe_Concurrent 第一次通知处理期间重发的通知应答失败且不调用回调, 处理失败后重发的通知再次处理, 成功后重复的通知应答成功
+func TestNotifyDedupe_Concurrent(t *testing.T) {
+	deduper := &fakeDeduper{}
+	provider := &fakeNotifyProvider{deduper: deduper}
+	key := PaymentNotifyKey(&PaymentResult{PayType: PayTypeAlipay, TransactionID: "T1"})
+
+	var calls atomic.Int32
+
+	entered := make(chan struct{})
+	release := make(chan error)
+
+	engine := newNotifyEngine(provider, func(context.Context, Provider, *PaymentResult) error {
+		if calls.Add(1) == 1 {
+			close(entered)
+			return <-release
+		}
+
+		return nil
+	})
+
+	first := make(chan string)
+
+	go func() { first <- postNotify(engine, "T1") }()
+
+	<-entered
+
+	// 第一次通知处理中, 重发的通知应答失败, 支付渠道稍后重试
+	if got := postNotify(engine, "T1"); got != "fail" {
+		t.Errorf("处理中重发的通知应答 = %q, want fail", got)
+	}
+
+	if state, _ := deduper.state(key); state != NotifyStateProcessing {
+		t.Errorf("处理中状态 = %v, want NotifyStateProcessing", state)
+	}
+
+	// 第一次处理失败, 删除处理中标记
+	release <- errors.New("callback failed")
+
+	if got := <-first; got != "fail" {
+		t.Errorf("处理失败的通知应答 = %q, want fail", got)
+	}
+
+	if _, ok := deduper.state(key); ok {
+		t.Fatal("处理失败后去重标记未删除")
+	}
+
+	// 支付渠道重发的通知再次处理并成功
+	if got := postNotify(engine, "T1"); got != "success" {
+		t.Errorf("重发的通知应答 = %q, want success", got)
+	}
+
+	if state, _ := deduper.state(key); state != NotifyStateDone {
+		t.Errorf("处理成功后状态 = %v, want NotifyStateDone", state)
+	}
+
+	// 已处理的通知直接应答成功, 不调用回调
+	if got := postNotify(engine, "T1"); got != "success" {
+		t.Errorf("重复的通知应答 = %q, want success", got)
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("回调次数 = %d, want 2", got)
+	}
+}
+
+// TestNotifyDedupe_BeginFailed 去重存储不可用时放行, 每次通知都调用回调
+func TestNotifyDedupe_BeginFailed(t *testing.T) {
+	provider := &fakeNotifyProvider{deduper: &fakeDeduper{err: errors.New("redis down")}}
+
+	var calls atomic.Int32
+
+	engine := newNotifyEngine(provider, func(context.Context, Provider, *PaymentResult) error {
+		calls.Add(1)
+		return nil
+	})
+
+	for range 2 {
+		if got := postNotify(engine, "T1"); got != "success" {
+			t.Errorf("通知应答 = %q, want success", got)
+		}
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("回调次数 = %d, want 2", got)
+	}
+}
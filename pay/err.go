@@ -0,0 +1,156 @@
+//
+// FilePath    : go-utils\pay\err.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 支付错误分类
+//
+
+package pay
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/jiaopengzi/go-utils/rescode"
+)
+
+// ErrorKind 支付错误类别, 值为固定的机器可读标识, 可用于日志和监控统计; 调用方可通过 errors.Is(err, ErrXXX) 判断失败类别
+type ErrorKind string
+
+// 支付错误类别常量
+const (
+	ErrAmountMismatch      = ErrorKind("pay_amount_mismatch")      // 金额不匹配
+	ErrOrderMismatch       = ErrorKind("pay_order_mismatch")       // 订单号不匹配
+	ErrMerchantMismatch    = ErrorKind("pay_merchant_mismatch")    // 商户号或应用ID不匹配
+	ErrOrderNotFound       = ErrorKind("pay_order_not_found")      // 订单不存在
+	ErrProviderUnavailable = ErrorKind("pay_provider_unavailable") // 支付渠道不可用(网络错误、SDK 调用失败等)
+	ErrProviderRejected    = ErrorKind("pay_provider_rejected")    // 支付渠道返回业务失败
+	ErrInsufficientBalance = ErrorKind("pay_insufficient_balance") // 余额不足
+	ErrInvalidNotify       = ErrorKind("pay_invalid_notify")       // 通知验签或解析失败
+	ErrUnknownStatus       = ErrorKind("pay_unknown_status")       // 无法识别的交易或退款状态
+	ErrInvalidConfig       = ErrorKind("pay_invalid_config")       // 支付配置错误
+	ErrRateUnavailable     = ErrorKind("pay_rate_unavailable")     // 汇率不可用
+	ErrRateStale           = ErrorKind("pay_rate_stale")           // 汇率已过期且没有兜底汇率
+	ErrProviderNotFound    = ErrorKind("pay_provider_not_found")   // 支付渠道未注册
+	ErrInvalidBill         = ErrorKind("pay_invalid_bill")         // 账单下载校验或解析失败
+	ErrDuplicateNotify     = ErrorKind("pay_duplicate_notify")     // 重复的通知, 已经处理过
+	ErrRefundExceeded      = ErrorKind("pay_refund_exceeded")      // 退款金额超过订单可退余额
+	ErrNotifyProcessing    = ErrorKind("pay_notify_processing")    // 相同的通知正在处理, 应答失败等待支付渠道重发
+)
+
+// Error 实现 error 接口 Error 方法
+func (k ErrorKind) Error() string { return string(k) }
+
+// Error 支付错误, 包含错误类别、支付渠道及渠道返回的错误码.
+// 响应客户端时可通过 StatusCodeOf 将错误类别映射为业务状态码, 本包不定义业务状态码,
+// 应用需要在启动时调用 RegisterErrorCodes 注册映射, 未注册的错误类别 StatusCodeOf 返回 false.
+type Error struct {
+	Kind    ErrorKind // 错误类别
+	PayType PayType   // 支付渠道
+	Code    string    // 支付渠道返回的错误码, 可能为空
+	Msg     string    // 错误描述
+	Err     error     // 原始错误, 可能为 nil
+}
+
+// Error 实现 error 接口 Error 方法
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s %s", e.PayType, e.Kind)
+
+	if e.Code != "" {
+		msg = fmt.Sprintf("%s code=%s", msg, e.Code)
+	}
+
+	if e.Msg != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Msg)
+	}
+
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+
+	return msg
+}
+
+// Unwrap 支持 errors.Is / errors.As 同时匹配错误类别和原始错误
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+
+	return []error{e.Kind, e.Err}
+}
+
+// newError 创建支付错误
+//   - payType: 支付渠道
+//   - kind: 错误类别
+//   - code: 支付渠道返回的错误码
+//   - err: 原始错误
+//   - format: 错误描述格式
+func newError(payType PayType, kind ErrorKind, code string, err error, format string, args ...any) *Error {
+	return &Error{
+		Kind:    kind,
+		PayType: payType,
+		Code:    code,
+		Msg:     fmt.Sprintf(format, args...),
+		Err:     err,
+	}
+}
+
+// AsError 从 err 中提取 *Error, 如果不是支付错误返回 false
+func AsError(err error) (*Error, bool) {
+	var payErr *Error
+	if errors.As(err, &payErr) {
+		return payErr, true
+	}
+
+	return nil, false
+}
+
+// ProviderCode 获取 err 中支付渠道返回的错误码, 不存在时返回空字符串
+func ProviderCode(err error) string {
+	payErr, ok := AsError(err)
+	if !ok {
+		return ""
+	}
+
+	return payErr.Code
+}
+
+// 错误类别与业务状态码的映射
+var (
+	errorCodeMap = make(map[ErrorKind]rescode.StatusCodeType)
+	errorCodeMu  sync.RWMutex
+)
+
+// RegisterErrorCodes 注册错误类别对应的业务状态码, 用于 StatusCodeOf 自动映射, 一般在应用启动时与 rescode.RegisterCodes 一起调用
+func RegisterErrorCodes(codeMap map[ErrorKind]rescode.StatusCodeType) {
+	errorCodeMu.Lock()
+	defer errorCodeMu.Unlock()
+
+	maps.Copy(errorCodeMap, codeMap)
+}
+
+// StatusCodeOf 根据 err 的错误类别返回已注册的业务状态码, 未注册或非支付错误时返回 false
+func StatusCodeOf(err error) (rescode.StatusCodeType, bool) {
+	if err == nil {
+		return 0, false
+	}
+
+	// 优先使用 *Error 中的错误类别, 否则尝试匹配裸的 ErrorKind
+	var kind ErrorKind
+	if payErr, ok := AsError(err); ok {
+		kind = payErr.Kind
+	} else if !errors.As(err, &kind) {
+		return 0, false
+	}
+
+	errorCodeMu.RLock()
+	defer errorCodeMu.RUnlock()
+
+	code, ok := errorCodeMap[kind]
+
+	return code, ok
+}
@@ -0,0 +1,98 @@
+//
+// FilePath    : go-utils\pay\err_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 支付错误分类测试
+//
+
+package pay
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/jiaopengzi/go-utils/rescode"
+)
+
+// TestErrorKind_Identifier 错误类别是固定格式的机器可读标识
+func TestErrorKind_Identifier(t *testing.T) {
+	kinds := []ErrorKind{
+		ErrAmountMismatch, ErrOrderMismatch, ErrMerchantMismatch, ErrOrderNotFound,
+		ErrProviderUnavailable, ErrProviderRejected, ErrInsufficientBalance, ErrInvalidNotify,
+		ErrUnknownStatus, ErrInvalidConfig, ErrRateUnavailable, ErrRateStale,
+		ErrProviderNotFound, ErrInvalidBill, ErrDuplicateNotify, ErrRefundExceeded, ErrNotifyProcessing,
+	}
+
+	pattern := regexp.MustCompile(`^pay_[a-z]+(_[a-z]+)*$`)
+
+	for _, kind := range kinds {
+		if !pattern.MatchString(string(kind)) {
+			t.Errorf("ErrorKind %q 不是 pay_xxx 格式的标识", kind)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	cause := errors.New("timeout")
+
+	tests := []struct {
+		name string
+		err  *Error
+		want string
+	}{
+		{"只有类别", &Error{Kind: ErrOrderNotFound, PayType: PayTypeAlipay}, "alipay pay_order_not_found"},
+		{"完整", newError(PayTypeWechat, ErrProviderRejected, "SYSTEMERROR", cause, "下单失败 %d", 1), "wechat_pay pay_provider_rejected code=SYSTEMERROR: 下单失败 1: timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+
+			if !errors.Is(tt.err, tt.err.Kind) {
+				t.Errorf("errors.Is(err, %s) = false", tt.err.Kind)
+			}
+		})
+	}
+
+	err := fmt.Errorf("wrap: %w", newError(PayTypeWechat, ErrProviderRejected, "SYSTEMERROR", cause, "下单失败"))
+	if !errors.Is(err, cause) || ProviderCode(err) != "SYSTEMERROR" {
+		t.Errorf("errors.Is(err, cause) = %v, ProviderCode() = %q", errors.Is(err, cause), ProviderCode(err))
+	}
+}
+
+func TestStatusCodeOf(t *testing.T) {
+	const code rescode.StatusCodeType = 30001
+
+	RegisterErrorCodes(map[ErrorKind]rescode.StatusCodeType{ErrRefundExceeded: code})
+	t.Cleanup(func() {
+		errorCodeMu.Lock()
+		delete(errorCodeMap, ErrRefundExceeded)
+		errorCodeMu.Unlock()
+	})
+
+	tests := []struct {
+		name   string
+		err    error
+		want   rescode.StatusCodeType
+		wantOK bool
+	}{
+		{"支付错误", fmt.Errorf("wrap: %w", &Error{Kind: ErrRefundExceeded}), code, true},
+		{"错误类别", ErrRefundExceeded, code, true},
+		{"未注册", &Error{Kind: ErrOrderNotFound}, 0, false},
+		{"非支付错误", errors.New("other"), 0, false},
+		{"nil", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := StatusCodeOf(tt.err); got != tt.want || ok != tt.wantOK {
+				t.Errorf("StatusCodeOf() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
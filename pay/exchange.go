@@ -0,0 +1,200 @@
+//
+// FilePath    : go-utils\pay\exchange.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 汇率换算, 用于多币种价格展示
+//
+
+package pay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jiaopengzi/go-utils/model"
+	"github.com/jiaopengzi/go-utils/redis/cache"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// CurrencyPair 货币对
+type CurrencyPair struct {
+	From model.Currency // 源货币
+	To   model.Currency // 目标货币
+}
+
+// Rate 汇率, 1 单位 From 货币等于 Rate 单位 To 货币
+type Rate struct {
+	From      model.Currency `json:"from"`       // 源货币
+	To        model.Currency `json:"to"`         // 目标货币
+	Rate      float64        `json:"rate"`       // 汇率
+	UpdatedAt time.Time      `json:"updated_at"` // 汇率更新时间
+	Fallback  bool           `json:"-"`          // 是否为兜底汇率
+}
+
+// RateProvider 汇率提供者
+type RateProvider interface {
+	// GetRate 获取 from 到 to 的汇率
+	GetRate(ctx context.Context, from, to model.Currency) (*Rate, error)
+}
+
+// 汇率换算配置
+var (
+	rateProvider  RateProvider                     // 汇率提供者
+	rateMaxAge    = 24 * time.Hour                 // 汇率最大有效期, 超过视为过期
+	fallbackRates = make(map[CurrencyPair]float64) // 兜底汇率
+	rateMu        sync.RWMutex
+)
+
+// SetRateProvider 设置汇率提供者
+func SetRateProvider(provider RateProvider) {
+	rateMu.Lock()
+	defer rateMu.Unlock()
+
+	rateProvider = provider
+}
+
+// SetRateMaxAge 设置汇率最大有效期, 汇率更新时间超过该时长视为过期, 为 0 表示不检查
+func SetRateMaxAge(d time.Duration) {
+	rateMu.Lock()
+	defer rateMu.Unlock()
+
+	rateMaxAge = d
+}
+
+// SetFallbackRates 设置兜底汇率, 汇率提供者不可用或汇率过期时使用
+func SetFallbackRates(rates map[CurrencyPair]float64) {
+	rateMu.Lock()
+	defer rateMu.Unlock()
+
+	for pair, rate := range rates {
+		fallbackRates[pair] = rate
+	}
+}
+
+// GetRate 获取 from 到 to 的汇率, 汇率提供者不可用或汇率过期时使用兜底汇率(Fallback 为 true)
+func GetRate(ctx context.Context, from, to model.Currency) (*Rate, error) {
+	if from == to {
+		return &Rate{From: from, To: to, Rate: 1, UpdatedAt: time.Now()}, nil
+	}
+
+	rateMu.RLock()
+	provider, maxAge := rateProvider, rateMaxAge
+	fallback, hasFallback := fallbackRates[CurrencyPair{From: from, To: to}]
+	rateMu.RUnlock()
+
+	fallbackRate := func(cause error) (*Rate, error) {
+		if !hasFallback {
+			return nil, cause
+		}
+
+		zap.L().Warn("使用兜底汇率", zap.String("from", from.Code()), zap.String("to", to.Code()), zap.Float64("rate", fallback), zap.Error(cause))
+
+		return &Rate{From: from, To: to, Rate: fallback, Fallback: true}, nil
+	}
+
+	if provider == nil {
+		return fallbackRate(fmt.Errorf("%w: 未设置汇率提供者", ErrRateUnavailable))
+	}
+
+	rate, err := provider.GetRate(ctx, from, to)
+	if err != nil {
+		return fallbackRate(fmt.Errorf("%w: %s -> %s: %w", ErrRateUnavailable, from.Code(), to.Code(), err))
+	}
+
+	if rate.Rate <= 0 {
+		return fallbackRate(fmt.Errorf("%w: %s -> %s 汇率不合法: %v", ErrRateUnavailable, from.Code(), to.Code(), rate.Rate))
+	}
+
+	// 检查汇率是否过期
+	if maxAge > 0 && time.Since(rate.UpdatedAt) > maxAge {
+		return fallbackRate(fmt.Errorf("%w: %s -> %s 更新于 %s", ErrRateStale, from.Code(), to.Code(), rate.UpdatedAt.Format(time.RFC3339)))
+	}
+
+	return rate, nil
+}
+
+// ConvertAmount 将 from 货币的金额换算为 to 货币的金额, 仅用于展示, 不能用于实际支付.
+// 金额单位均为最小货币单位(分), 四舍五入到最小货币单位.
+//   - fen: 金额, 单位为分
+//   - from: 源货币
+//   - to: 目标货币
+func ConvertAmount(fen int64, from, to model.Currency) (int64, error) {
+	rate, err := GetRate(context.Background(), from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(math.Round(float64(fen) * rate.Rate)), nil
+}
+
+// StaticRateProvider 固定汇率提供者, 未配置的货币对会尝试使用反向汇率的倒数
+type StaticRateProvider struct {
+	Rates     map[CurrencyPair]float64 // 汇率
+	UpdatedAt time.Time                // 汇率更新时间
+}
+
+// GetRate 实现 RateProvider 接口 GetRate 方法
+func (p *StaticRateProvider) GetRate(_ context.Context, from, to model.Currency) (*Rate, error) {
+	if rate, ok := p.Rates[CurrencyPair{From: from, To: to}]; ok {
+		return &Rate{From: from, To: to, Rate: rate, UpdatedAt: p.UpdatedAt}, nil
+	}
+
+	if rate, ok := p.Rates[CurrencyPair{From: to, To: from}]; ok && rate > 0 {
+		return &Rate{From: from, To: to, Rate: 1 / rate, UpdatedAt: p.UpdatedAt}, nil
+	}
+
+	return nil, fmt.Errorf("%w: 未配置 %s -> %s 的汇率", ErrRateUnavailable, from.Code(), to.Code())
+}
+
+// ratePurpose 汇率缓存键的用途
+const ratePurpose cache.Purpose = "pay_rate"
+
+// CachedRateProvider 带缓存(redis)的汇率提供者, 缓存未命中时从 source 获取并缓存.
+// 缓存保存了汇率更新时间, 命中缓存时同样可以检测汇率是否过期.
+type CachedRateProvider struct {
+	source RateProvider
+	cacher cache.Cacher
+	ttl    time.Duration
+}
+
+// NewCachedRateProvider 创建带缓存的汇率提供者
+//   - source: 实际的汇率提供者, 例如调用第三方汇率接口
+//   - cacher: 缓存客户端
+//   - ttl: 缓存有效期
+func NewCachedRateProvider(source RateProvider, cacher cache.Cacher, ttl time.Duration) *CachedRateProvider {
+	return &CachedRateProvider{source: source, cacher: cacher, ttl: ttl}
+}
+
+// GetRate 实现 RateProvider 接口 GetRate 方法
+func (p *CachedRateProvider) GetRate(ctx context.Context, from, to model.Currency) (*Rate, error) {
+	key := cache.GenerateKey(ratePurpose, from.Code(), to.Code())
+
+	var rate Rate
+
+	err := p.cacher.GetStringWithStruct(ctx, key, &rate)
+	if err == nil {
+		return &rate, nil
+	}
+
+	// 缓存异常不影响获取汇率
+	if !errors.Is(err, redis.Nil) {
+		zap.L().Warn("获取汇率缓存失败", zap.String("key", key), zap.Error(err))
+	}
+
+	fresh, err := p.source.GetRate(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.cacher.SetStringWithStruct(ctx, key, fresh, p.ttl); err != nil {
+		zap.L().Warn("保存汇率缓存失败", zap.String("key", key), zap.Error(err))
+	}
+
+	return fresh, nil
+}
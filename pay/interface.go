@@ -9,10 +9,19 @@
 package pay
 
 import (
+	"context"
 	"net/http"
 	"time"
 )
 
+// Payer 支付接口. 访问支付渠道的方法都有接收 context.Context 的版本(XxxContext), 用于设置超时和传递链路信息;
+// 不接收 ctx 的方法为兼容旧版本保留, 等价于使用 context.Background() 调用对应的 XxxContext 方法.
+//
+// 配置了通知去重(NotificationDeduper)时, GetNotifyPayment 和 GetNotifyRefund 将通知标记为处理中.
+// 不使用 RegisterRoutes 而直接调用时, 调用方需要自行处理:
+//   - ErrDuplicateNotify: 已处理成功, 应答成功
+//   - ErrNotifyProcessing: 正在处理, 应答失败, 支付渠道稍后重发
+//   - 业务处理结束后调用 FinishNotify, 成功时标记为已处理, 失败时删除处理中标记
 type Payer interface {
 	// Prepay 支付接口
 	//   - orderID: 订单ID
@@ -23,9 +32,13 @@ type Payer interface {
 	// 返回值为支付链接或二维码的URL
 	Prepay(orderID uint64, amount int64, description, returnURL string, timeExpire time.Time) (string, error)
 
+	// PrepayContext 支付接口, 同 Prepay
+	PrepayContext(ctx context.Context, orderID uint64, amount int64, description, returnURL string, timeExpire time.Time) (string, error)
+
 	// GetNotifyPayment 获取支付结果通知接口, 包含验签和获取支付结果
 	//  - request: HTTP请求对象
 	// 返回值为是否成功处理通知，支付结果和错误信息
+	// 配置了通知去重时, 已处理的通知返回支付结果和 ErrDuplicateNotify, 正在处理的通知返回 ErrNotifyProcessing
 	GetNotifyPayment(request *http.Request) (bool, *PaymentResult, error)
 
 	// ValidateNotifyPayment 验证支付结果通知接口
@@ -39,11 +52,17 @@ type Payer interface {
 	// 返回值为支付结果和错误信息
 	QueryPayment(orderID uint64) (*PaymentResult, error)
 
+	// QueryPaymentContext 查询支付结果接口, 同 QueryPayment
+	QueryPaymentContext(ctx context.Context, orderID uint64) (*PaymentResult, error)
+
 	// CloseOrder 关闭订单接口
 	// - orderID: 订单ID
 	// 返回值为错误信息
 	CloseOrder(orderID uint64) error
 
+	// CloseOrderContext 关闭订单接口, 同 CloseOrder
+	CloseOrderContext(ctx context.Context, orderID uint64) error
+
 	// Refund 退款接口
 	// - orderID: 订单ID
 	// - RefundID: 退款ID
@@ -51,9 +70,13 @@ type Payer interface {
 	// - refundAmount: 退款金额，单位为分(不能超过订单总金额)
 	Refund(orderID, refundID uint64, amount, refundAmount int64, reason string) (*RefundResult, error)
 
+	// RefundContext 退款接口, 同 Refund
+	RefundContext(ctx context.Context, orderID, refundID uint64, amount, refundAmount int64, reason string) (*RefundResult, error)
+
 	// GetNotifyRefund 应答退款结果通知接口, 包含验签和获取退款结果
 	//  - request: HTTP请求对象
 	// 返回值为是否成功处理通知，退款结果和错误信息
+	// 配置了通知去重时, 已处理的通知返回退款结果和 ErrDuplicateNotify, 正在处理的通知返回 ErrNotifyProcessing
 	GetNotifyRefund(request *http.Request) (bool, *RefundResult, error)
 
 	// QueryRefund 查询退款结果接口
@@ -61,4 +84,7 @@ type Payer interface {
 	//  - refundID: 退款ID
 	// 返回值为退款结果和错误信息
 	QueryRefund(orderID, refundID uint64) (*RefundResult, error)
+
+	// QueryRefundContext 查询退款结果接口, 同 QueryRefund
+	QueryRefundContext(ctx context.Context, orderID, refundID uint64) (*RefundResult, error)
 }
@@ -0,0 +1,307 @@
+//
+// FilePath    : go-utils\pay\notify_debug.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 支付通知验签诊断, 验签失败时记录脱敏的请求头、使用中的证书序列号和失败原因, 支持离线回放通知
+//
+
+package pay
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/smartwalle/alipay/v3"
+	"github.com/wechatpay-apiv3/wechatpay-go/core/consts"
+	"github.com/wechatpay-apiv3/wechatpay-go/core/downloader"
+	"github.com/wechatpay-apiv3/wechatpay-go/core/notify"
+	wechatUtils "github.com/wechatpay-apiv3/wechatpay-go/utils"
+	"go.uber.org/zap"
+)
+
+// notifyDebug 是否开启通知验签诊断模式
+var notifyDebug atomic.Bool
+
+// SetNotifyDebug 设置是否开启通知验签诊断模式, 开启后验签失败时记录诊断日志.
+// 诊断模式会缓存通知请求体, 建议只在排查问题时开启.
+func SetNotifyDebug(enabled bool) {
+	notifyDebug.Store(enabled)
+}
+
+// NotifyFailureReason 通知验签失败原因
+type NotifyFailureReason string
+
+// 通知验签失败原因常量
+const (
+	NotifyReasonMissingHeader     NotifyFailureReason = "missing_header"     // 缺少签名相关的请求头或参数
+	NotifyReasonUnknownSerial     NotifyFailureReason = "unknown_serial"     // 证书序列号不在使用中的证书内, 一般为平台证书已更换
+	NotifyReasonSignatureMismatch NotifyFailureReason = "signature_mismatch" // 签名不匹配, 一般为请求体被修改或公钥错误
+	NotifyReasonDecryptFailed     NotifyFailureReason = "decrypt_failed"     // 解密失败, 一般为 APIv3 密钥错误
+	NotifyReasonInvalidBody       NotifyFailureReason = "invalid_body"       // 请求体格式错误
+)
+
+// NotifyVerifyError 通知验签诊断信息, 验签失败时可通过 errors.As 获取
+type NotifyVerifyError struct {
+	PayType      PayType             // 支付渠道
+	Reason       NotifyFailureReason // 失败原因
+	Serial       string              // 通知使用的证书序列号
+	KnownSerials []string            // 使用中的证书序列号
+	Headers      map[string]string   // 脱敏后的签名相关请求头或参数
+	Err          error               // 原始错误, 可能为 nil
+}
+
+// Error 实现 error 接口 Error 方法
+func (e *NotifyVerifyError) Error() string {
+	msg := fmt.Sprintf("%s notify verify failed: %s", e.PayType, e.Reason)
+
+	if e.Serial != "" {
+		msg = fmt.Sprintf("%s, serial %s, known serials %v", msg, e.Serial, e.KnownSerials)
+	}
+
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+
+	return msg
+}
+
+// Unwrap 支持 errors.Is / errors.As 匹配原始错误
+func (e *NotifyVerifyError) Unwrap() error {
+	return e.Err
+}
+
+// log 记录诊断日志
+func (e *NotifyVerifyError) log() {
+	zap.L().Warn("支付通知验签失败",
+		zap.String("支付渠道", string(e.PayType)),
+		zap.String("失败原因", string(e.Reason)),
+		zap.String("证书序列号", e.Serial),
+		zap.Strings("使用中的证书序列号", e.KnownSerials),
+		zap.Any("请求头", e.Headers),
+		zap.Error(e.Err),
+	)
+}
+
+// maskNotifyValue 脱敏签名等敏感值, 只保留前后 4 个字符
+func maskNotifyValue(s string) string {
+	if len(s) <= 12 {
+		return "******"
+	}
+
+	return s[:4] + "******" + s[len(s)-4:]
+}
+
+// wechatNotifyHeaders 微信支付通知签名相关的请求头, 值为是否需要脱敏
+var wechatNotifyHeaders = map[string]bool{
+	consts.WechatPaySerial:     false,
+	consts.WechatPayTimestamp:  false,
+	consts.WechatPayNonce:      false,
+	consts.WechatPaySignature:  true,
+	"Wechatpay-Signature-Type": false,
+	consts.RequestID:           false,
+}
+
+// maskWechatNotifyHeaders 获取脱敏后的签名相关请求头
+func maskWechatNotifyHeaders(headers http.Header) map[string]string {
+	masked := make(map[string]string, len(wechatNotifyHeaders))
+
+	for name, sensitive := range wechatNotifyHeaders {
+		v := headers.Get(name)
+		if v != "" && sensitive {
+			v = maskNotifyValue(v)
+		}
+
+		masked[name] = v
+	}
+
+	return masked
+}
+
+// verifyWechatNotify 验签并解密微信支付通知, 失败时返回包含失败原因的诊断信息
+//   - headers: 通知请求头
+//   - body: 通知请求体
+//   - apiV3Key: APIv3 密钥, 用于解密通知内容
+//   - publicKeys: 平台证书序列号(或公钥 ID) -> 公钥
+func verifyWechatNotify(headers http.Header, body []byte, apiV3Key string, publicKeys map[string]*rsa.PublicKey) (*notify.Request, *NotifyVerifyError) {
+	verr := &NotifyVerifyError{
+		PayType:      PayTypeWechat,
+		Serial:       headers.Get(consts.WechatPaySerial),
+		KnownSerials: slices.Sorted(maps.Keys(publicKeys)),
+		Headers:      maskWechatNotifyHeaders(headers),
+	}
+
+	signature := headers.Get(consts.WechatPaySignature)
+	timestamp := headers.Get(consts.WechatPayTimestamp)
+	nonce := headers.Get(consts.WechatPayNonce)
+
+	if verr.Serial == "" || signature == "" || timestamp == "" || nonce == "" {
+		verr.Reason = NotifyReasonMissingHeader
+		return nil, verr
+	}
+
+	publicKey, ok := publicKeys[verr.Serial]
+	if !ok {
+		verr.Reason = NotifyReasonUnknownSerial
+		return nil, verr
+	}
+
+	// 签名串: 时间戳\n随机串\n请求体\n
+	// 文档: https://pay.weixin.qq.com/doc/v3/merchant/4013053249
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		verr.Reason, verr.Err = NotifyReasonSignatureMismatch, err
+		return nil, verr
+	}
+
+	hashed := sha256.Sum256([]byte(timestamp + "\n" + nonce + "\n" + string(body) + "\n"))
+	if err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], sig); err != nil {
+		verr.Reason, verr.Err = NotifyReasonSignatureMismatch, err
+		return nil, verr
+	}
+
+	req := new(notify.Request)
+	if err = json.Unmarshal(body, req); err != nil || req.Resource == nil {
+		verr.Reason, verr.Err = NotifyReasonInvalidBody, err
+		return nil, verr
+	}
+
+	req.Resource.Plaintext, err = wechatUtils.DecryptAES256GCM(
+		apiV3Key, req.Resource.AssociatedData, req.Resource.Nonce, req.Resource.Ciphertext,
+	)
+	if err != nil {
+		verr.Reason, verr.Err = NotifyReasonDecryptFailed, err
+		return nil, verr
+	}
+
+	return req, nil
+}
+
+// platformPublicKeys 获取验签使用的平台公钥, 优先使用 PlatformPublicKeys, 否则使用已下载的平台证书(不访问网络)
+func (w *WeChatPay) platformPublicKeys(ctx context.Context) map[string]*rsa.PublicKey {
+	if len(w.PlatformPublicKeys) > 0 {
+		return w.PlatformPublicKeys
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+
+	for serial, cert := range downloader.MgrInstance().GetCertificateVisitor(w.Conf.MchID).GetAll(ctx) {
+		if key, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			keys[serial] = key
+		}
+	}
+
+	return keys
+}
+
+// VerifyNotifyOffline 离线验签并解密微信支付通知, 不访问网络, 用于在测试中回放抓取的通知.
+// 通知内容的明文在返回值的 Resource.Plaintext 中, 失败时返回的错误包含 *NotifyVerifyError.
+// 不校验时间戳, 平台公钥使用 PlatformPublicKeys, 为空时使用已下载的平台证书.
+//   - headers: 通知请求头, 需要包含 Wechatpay-Serial、Wechatpay-Signature、Wechatpay-Timestamp、Wechatpay-Nonce
+//   - body: 通知请求体
+func (w *WeChatPay) VerifyNotifyOffline(headers http.Header, body []byte) (*notify.Request, error) {
+	req, verr := verifyWechatNotify(headers, body, w.Conf.APIv3Key, w.platformPublicKeys(context.Background()))
+	if verr != nil {
+		return nil, newError(PayTypeWechat, ErrInvalidNotify, string(verr.Reason), verr, "WeChatPay verify notify offline error")
+	}
+
+	return req, nil
+}
+
+// diagnoseWechatNotify 诊断模式下分析微信支付通知验签失败的原因并记录日志
+//   - err: SDK 返回的验签错误
+func (w *WeChatPay) diagnoseWechatNotify(ctx context.Context, headers http.Header, body []byte, err error) {
+	_, verr := verifyWechatNotify(headers, body, w.Conf.APIv3Key, w.platformPublicKeys(ctx))
+	if verr == nil {
+		// 离线验签通过, 失败原因在 SDK 的其他校验中, 例如签名类型或时间戳
+		verr = &NotifyVerifyError{
+			PayType:      PayTypeWechat,
+			Reason:       NotifyReasonInvalidBody,
+			Serial:       headers.Get(consts.WechatPaySerial),
+			KnownSerials: slices.Sorted(maps.Keys(w.platformPublicKeys(ctx))),
+			Headers:      maskWechatNotifyHeaders(headers),
+		}
+	}
+
+	if verr.Err == nil {
+		verr.Err = err
+	}
+
+	if ts, parseErr := strconv.ParseInt(headers.Get(consts.WechatPayTimestamp), 10, 64); parseErr == nil {
+		verr.Headers["timestamp_age"] = time.Since(time.Unix(ts, 0)).Round(time.Second).String()
+	}
+
+	verr.log()
+}
+
+// alipayNotifyFields 支付宝通知签名相关的参数, 值为是否需要脱敏
+var alipayNotifyFields = map[string]bool{
+	"app_id":         false,
+	"notify_id":      false,
+	"sign_type":      false,
+	"sign":           true,
+	"alipay_cert_sn": false,
+	"charset":        false,
+	"version":        false,
+}
+
+// alipayNotifyError 根据支付宝验签错误生成诊断信息
+func alipayNotifyError(form url.Values, err error) *NotifyVerifyError {
+	verr := &NotifyVerifyError{
+		PayType: PayTypeAlipay,
+		Reason:  NotifyReasonSignatureMismatch,
+		Serial:  form.Get("alipay_cert_sn"),
+		Headers: make(map[string]string, len(alipayNotifyFields)),
+		Err:     err,
+	}
+
+	for name, sensitive := range alipayNotifyFields {
+		v := form.Get(name)
+		if v != "" && sensitive {
+			v = maskNotifyValue(v)
+		}
+
+		verr.Headers[name] = v
+	}
+
+	switch {
+	case form.Get("sign") == "":
+		verr.Reason = NotifyReasonMissingHeader
+	case err != nil && strings.Contains(strings.ToLower(err.Error()), "cert"):
+		verr.Reason = NotifyReasonUnknownSerial
+	}
+
+	return verr
+}
+
+// VerifyNotifyOffline 离线验签支付宝通知, 不访问网络, 用于在测试中回放抓取的通知, 失败时返回的错误包含 *NotifyVerifyError
+//   - headers: 通知请求头, 支付宝通知不使用请求头签名, 可为 nil
+//   - body: 通知请求体, 格式为 application/x-www-form-urlencoded
+func (a *Alipay) VerifyNotifyOffline(_ http.Header, body []byte) (*alipay.Notification, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		verr := &NotifyVerifyError{PayType: PayTypeAlipay, Reason: NotifyReasonInvalidBody, Err: err}
+		return nil, newError(PayTypeAlipay, ErrInvalidNotify, string(verr.Reason), verr, "alipay verify notify offline error")
+	}
+
+	notif, err := a.Client.DecodeNotification(form)
+	if err != nil {
+		verr := alipayNotifyError(form, err)
+		return nil, newError(PayTypeAlipay, ErrInvalidNotify, string(verr.Reason), verr, "alipay verify notify offline error")
+	}
+
+	return notif, nil
+}
@@ -0,0 +1,252 @@
+//
+// FilePath    : go-utils\pay\prepay_cache.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 预支付结果缓存, 重复展示支付二维码或支付链接时复用未过期的结果, 避免重复调用支付渠道下单
+//
+
+package pay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jiaopengzi/go-utils/redis/cache"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// defaultPrepayCacheMargin 默认提前失效时间, 避免返回即将过期的支付链接
+const defaultPrepayCacheMargin = time.Minute
+
+// prepayCachePurpose 预支付结果缓存键的用途
+const prepayCachePurpose cache.Purpose = "pay_prepay"
+
+// PrepayEntry 缓存的预支付结果, 下单参数一致时才复用
+type PrepayEntry struct {
+	URL         string    `json:"url"`         // 支付链接、二维码链接或 App 支付的 orderInfo
+	Amount      int64     `json:"amount"`      // 金额, 单位为分
+	Description string    `json:"description"` // 商品描述
+	ReturnURL   string    `json:"return_url"`  // 支付完成后跳转的页面
+	TimeExpire  time.Time `json:"time_expire"` // 订单失效时间
+}
+
+// matches 下单参数是否与缓存一致
+func (e *PrepayEntry) matches(amount int64, description, returnURL string, timeExpire time.Time) bool {
+	return e.Amount == amount && e.Description == description && e.ReturnURL == returnURL && e.TimeExpire.Equal(timeExpire)
+}
+
+// PrepayCache 预支付结果缓存
+type PrepayCache interface {
+	// Get 获取缓存的预支付结果, 不存在时返回 nil, nil
+	Get(ctx context.Context, payType PayType, orderID uint64) (*PrepayEntry, error)
+
+	// Set 缓存预支付结果, ttl 到期后自动删除
+	Set(ctx context.Context, payType PayType, orderID uint64, entry *PrepayEntry, ttl time.Duration) error
+
+	// Delete 删除缓存的预支付结果, 订单支付成功或关闭时调用
+	Delete(ctx context.Context, payType PayType, orderID uint64) error
+}
+
+// RedisPrepayCache 基于 redis 的预支付结果缓存, 多实例部署时共享
+type RedisPrepayCache struct {
+	Cache *cache.Client // 缓存客户端
+}
+
+// NewRedisPrepayCache 创建基于 redis 的预支付结果缓存
+//   - c: 缓存客户端
+func NewRedisPrepayCache(c *cache.Client) *RedisPrepayCache {
+	return &RedisPrepayCache{Cache: c}
+}
+
+// Get 实现 PrepayCache 接口 Get 方法
+func (c *RedisPrepayCache) Get(ctx context.Context, payType PayType, orderID uint64) (*PrepayEntry, error) {
+	var entry PrepayEntry
+
+	err := c.Cache.GetStringWithStruct(ctx, cache.GenerateKey(prepayCachePurpose, string(payType), orderID), &entry)
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// Set 实现 PrepayCache 接口 Set 方法
+func (c *RedisPrepayCache) Set(ctx context.Context, payType PayType, orderID uint64, entry *PrepayEntry, ttl time.Duration) error {
+	return c.Cache.SetStringWithStruct(ctx, cache.GenerateKey(prepayCachePurpose, string(payType), orderID), entry, ttl)
+}
+
+// Delete 实现 PrepayCache 接口 Delete 方法
+func (c *RedisPrepayCache) Delete(ctx context.Context, payType PayType, orderID uint64) error {
+	return c.Cache.Del(ctx, cache.GenerateKey(prepayCachePurpose, string(payType), orderID))
+}
+
+// PrepayCachingProvider 支付渠道装饰器, 缓存 Prepay 返回的支付链接, 有效期与订单失效时间一致.
+// 同一订单重复下单且参数一致时直接返回缓存的结果; 订单支付成功(通知或查询)和关闭时删除缓存.
+// 缓存读写失败时记录警告日志并直接调用支付渠道, 不影响支付.
+type PrepayCachingProvider struct {
+	Provider
+
+	cache  PrepayCache
+	margin time.Duration    // 提前失效时间
+	now    func() time.Time // 当前时间, 便于测试
+}
+
+// 确保 PrepayCachingProvider 实现了 Provider 和 NotifyPathProvider 接口
+var (
+	_ Provider           = (*PrepayCachingProvider)(nil)
+	_ NotifyPathProvider = (*PrepayCachingProvider)(nil)
+)
+
+// NewPrepayCachingProvider 创建缓存预支付结果的支付渠道装饰器
+//   - provider: 被装饰的支付渠道
+//   - c: 预支付结果缓存
+//   - margin: 提前失效时间, 缓存在订单失效时间之前 margin 过期, 避免返回即将过期的支付链接, 为 0 时默认 1 分钟
+func NewPrepayCachingProvider(provider Provider, c PrepayCache, margin time.Duration) *PrepayCachingProvider {
+	if margin <= 0 {
+		margin = defaultPrepayCacheMargin
+	}
+
+	return &PrepayCachingProvider{
+		Provider: provider,
+		cache:    c,
+		margin:   margin,
+		now:      time.Now,
+	}
+}
+
+// Unwrap 获取被装饰的支付渠道
+func (p *PrepayCachingProvider) Unwrap() Provider {
+	return p.Provider
+}
+
+// NotifyPaths 实现 NotifyPathProvider 接口, 被装饰的支付渠道未实现时返回空路由
+func (p *PrepayCachingProvider) NotifyPaths() (notifyPath, refundPath string) {
+	if np, ok := p.Provider.(NotifyPathProvider); ok {
+		return np.NotifyPaths()
+	}
+
+	return "", ""
+}
+
+// NotifyDeduper 获取被装饰的支付渠道的通知去重, 未配置时为 nil
+func (p *PrepayCachingProvider) NotifyDeduper() NotificationDeduper {
+	if holder, ok := p.Provider.(notifyDeduperHolder); ok {
+		return holder.NotifyDeduper()
+	}
+
+	return nil
+}
+
+// Prepay 实现 Payer 接口, 复用缓存的支付链接
+func (p *PrepayCachingProvider) Prepay(orderID uint64, amount int64, description, returnURL string, timeExpire time.Time) (string, error) {
+	return p.PrepayContext(context.Background(), orderID, amount, description, returnURL, timeExpire)
+}
+
+// PrepayContext 实现 Payer 接口, 复用缓存的支付链接, 下单参数变化时重新下单并覆盖缓存
+func (p *PrepayCachingProvider) PrepayContext(ctx context.Context, orderID uint64, amount int64, description, returnURL string, timeExpire time.Time) (string, error) {
+	payType := p.PayType()
+
+	entry, err := p.cache.Get(ctx, payType, orderID)
+	if err != nil {
+		p.warn("读取预支付结果缓存失败", orderID, err)
+	}
+
+	if entry != nil && entry.matches(amount, description, returnURL, timeExpire) {
+		return entry.URL, nil
+	}
+
+	url, err := p.Provider.PrepayContext(ctx, orderID, amount, description, returnURL, timeExpire)
+	if err != nil {
+		return "", err
+	}
+
+	// 剩余有效期不足时不缓存
+	ttl := timeExpire.Sub(p.now()) - p.margin
+	if ttl <= 0 {
+		return url, nil
+	}
+
+	entry = &PrepayEntry{
+		URL:         url,
+		Amount:      amount,
+		Description: description,
+		ReturnURL:   returnURL,
+		TimeExpire:  timeExpire,
+	}
+
+	if err = p.cache.Set(ctx, payType, orderID, entry, ttl); err != nil {
+		p.warn("写入预支付结果缓存失败", orderID, err)
+	}
+
+	return url, nil
+}
+
+// QueryPayment 实现 Payer 接口, 订单已支付或已关闭时删除缓存
+func (p *PrepayCachingProvider) QueryPayment(orderID uint64) (*PaymentResult, error) {
+	return p.QueryPaymentContext(context.Background(), orderID)
+}
+
+// QueryPaymentContext 实现 Payer 接口, 订单已支付或已关闭时删除缓存
+func (p *PrepayCachingProvider) QueryPaymentContext(ctx context.Context, orderID uint64) (*PaymentResult, error) {
+	result, err := p.Provider.QueryPaymentContext(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.TradeState != TradeStateUnpaid {
+		p.Invalidate(ctx, orderID)
+	}
+
+	return result, nil
+}
+
+// GetNotifyPayment 实现 Payer 接口, 支付成功通知验签通过后删除缓存
+func (p *PrepayCachingProvider) GetNotifyPayment(request *http.Request) (bool, *PaymentResult, error) {
+	ok, result, err := p.Provider.GetNotifyPayment(request)
+	if result != nil && (ok || errors.Is(err, ErrDuplicateNotify)) {
+		p.Invalidate(request.Context(), result.OrderID)
+	}
+
+	return ok, result, err
+}
+
+// CloseOrder 实现 Payer 接口, 关闭成功后删除缓存
+func (p *PrepayCachingProvider) CloseOrder(orderID uint64) error {
+	return p.CloseOrderContext(context.Background(), orderID)
+}
+
+// CloseOrderContext 实现 Payer 接口, 关闭成功后删除缓存
+func (p *PrepayCachingProvider) CloseOrderContext(ctx context.Context, orderID uint64) error {
+	if err := p.Provider.CloseOrderContext(ctx, orderID); err != nil {
+		return err
+	}
+
+	p.Invalidate(ctx, orderID)
+
+	return nil
+}
+
+// Invalidate 删除订单缓存的预支付结果, 例如业务侧修改了订单金额; 删除失败只记录日志
+//   - orderID: 订单ID
+func (p *PrepayCachingProvider) Invalidate(ctx context.Context, orderID uint64) {
+	if err := p.cache.Delete(ctx, p.PayType(), orderID); err != nil {
+		p.warn("删除预支付结果缓存失败", orderID, err)
+	}
+}
+
+// warn 记录缓存读写失败的日志
+func (p *PrepayCachingProvider) warn(msg string, orderID uint64, err error) {
+	zap.L().Warn(msg,
+		zap.String("支付渠道", string(p.PayType())),
+		zap.Uint64("订单ID", orderID),
+		zap.Error(err),
+	)
+}
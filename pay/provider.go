@@ -0,0 +1,97 @@
+//
+// FilePath    : go-utils\pay\provider.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 支付渠道接口及注册表, 支持按支付类型获取渠道及接入第三方渠道
+//
+
+package pay
+
+import (
+	"slices"
+	"sync"
+)
+
+// Provider 支付渠道, 微信支付和支付宝均已实现, 第三方渠道(Stripe、PayPal 等)实现该接口后通过 RegisterProvider 注册
+type Provider interface {
+	Payer
+
+	// PayType 支付渠道对应的支付类型
+	PayType() PayType
+}
+
+// 确保内置支付渠道实现了 Provider 接口
+var (
+	_ Provider = (*WeChatPay)(nil)
+	_ Provider = (*Alipay)(nil)
+)
+
+// ProviderConstructor 支付渠道构造函数, 在第一次 Get 时调用, 例如
+//
+//	pay.RegisterProvider(pay.PayTypeWechat, func() (pay.Provider, error) {
+//		return pay.NewWeChatPay(conf, "/api/v1", "/pay")
+//	})
+type ProviderConstructor func() (Provider, error)
+
+// providerEntry 注册的支付渠道, 构造成功后缓存实例
+type providerEntry struct {
+	constructor ProviderConstructor
+	provider    Provider
+}
+
+// 支付渠道注册表
+var (
+	providers   = make(map[PayType]*providerEntry)
+	providersMu sync.Mutex
+)
+
+// RegisterProvider 注册支付渠道构造函数, 已注册的支付类型会被替换(已创建的实例同时丢弃)
+//   - payType: 支付类型, 第三方渠道可自定义, 例如 PayType("stripe")
+//   - constructor: 构造函数
+func RegisterProvider(payType PayType, constructor ProviderConstructor) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	providers[payType] = &providerEntry{constructor: constructor}
+}
+
+// Get 获取支付渠道, 第一次获取时调用构造函数创建实例并缓存, 构造失败时不缓存, 下次获取时重试.
+// 支付类型未注册时返回 ErrProviderNotFound.
+func Get(payType PayType) (Provider, error) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	entry, ok := providers[payType]
+	if !ok {
+		return nil, newError(payType, ErrProviderNotFound, "", nil, "pay provider %s not registered", payType)
+	}
+
+	if entry.provider != nil {
+		return entry.provider, nil
+	}
+
+	provider, err := entry.constructor()
+	if err != nil {
+		return nil, err
+	}
+
+	entry.provider = provider
+
+	return provider, nil
+}
+
+// RegisteredPayTypes 获取已注册的支付类型, 按字母顺序
+func RegisteredPayTypes() []PayType {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	payTypes := make([]PayType, 0, len(providers))
+	for payType := range providers {
+		payTypes = append(payTypes, payType)
+	}
+
+	slices.Sort(payTypes)
+
+	return payTypes
+}
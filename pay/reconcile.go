@@ -0,0 +1,220 @@
+//
+// FilePath    : go-utils\pay\reconcile.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 自动对账, 下载支付渠道账单并与本地支付记录比对, 生成差异报告
+//
+
+package pay
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jiaopengzi/go-utils/cron"
+	"go.uber.org/zap"
+)
+
+// BillType 账单类型
+type BillType string
+
+// 账单类型常量
+const (
+	BillTypeTrade    BillType = "trade"    // 交易账单, 微信支付 tradebill, 支付宝 trade
+	BillTypeFundFlow BillType = "fundflow" // 资金账单, 微信支付 fundflowbill, 支付宝 signcustomer
+)
+
+// BillRecord 标准化的账单记录, 金额单位为分
+type BillRecord struct {
+	PayType       PayType    `json:"pay_type"`       // 支付渠道
+	OrderID       uint64     `json:"order_id"`       // 商户订单号, 无法解析为数字时为 0
+	TransactionID string     `json:"transaction_id"` // 支付渠道交易号
+	RefundID      string     `json:"refund_id"`      // 商户退款单号, 仅退款记录
+	TradeType     string     `json:"trade_type"`     // 交易类型或业务类型
+	TradeState    TradeState `json:"trade_state"`    // 交易状态, 退款记录和资金支出为 TradeStateRefunded
+	TotalAmount   int64      `json:"total_amount"`   // 订单金额或资金收入金额
+	RefundAmount  int64      `json:"refund_amount"`  // 退款金额或资金支出金额
+	Fee           int64      `json:"fee"`            // 手续费
+	TradeTime     time.Time  `json:"trade_time"`     // 交易时间或记账时间
+}
+
+// BillDownloader 账单下载, WeChatPay 和 Alipay 已实现
+type BillDownloader interface {
+	// PayType 支付渠道
+	PayType() PayType
+
+	// DownloadBill 下载并解析 date 当天的账单
+	//   - billType: 账单类型
+	//   - date: 账单日期, 只使用年月日
+	DownloadBill(ctx context.Context, billType BillType, date time.Time) ([]BillRecord, error)
+}
+
+// ReconcileDiffType 对账差异类型
+type ReconcileDiffType string
+
+// 对账差异类型常量
+const (
+	ReconcileMissingLocal   ReconcileDiffType = "missing_local"   // 账单中已支付, 本地没有支付记录(漏单)
+	ReconcileMissingRemote  ReconcileDiffType = "missing_remote"  // 本地已支付, 账单中没有支付记录
+	ReconcileAmountMismatch ReconcileDiffType = "amount_mismatch" // 金额不一致
+	ReconcileUnknownOrder   ReconcileDiffType = "unknown_order"   // 账单中已支付, 商户订单号无法解析, 需要按支付渠道交易号人工核对
+)
+
+// ReconcileDiff 对账差异
+type ReconcileDiff struct {
+	Type    ReconcileDiffType `json:"type"`             // 差异类型
+	OrderID uint64            `json:"order_id"`         // 商户订单号, ReconcileUnknownOrder 时为 0
+	Local   *PaymentResult    `json:"local,omitempty"`  // 本地支付记录, ReconcileMissingLocal 时为 nil
+	Remote  *BillRecord       `json:"remote,omitempty"` // 账单记录, ReconcileMissingRemote 时为 nil
+}
+
+// ReconcileReport 对账报告
+type ReconcileReport struct {
+	PayType  PayType         `json:"pay_type"`  // 支付渠道
+	BillDate string          `json:"bill_date"` // 账单日期, 格式为 2006-01-02
+	Matched  int             `json:"matched"`   // 一致的订单数
+	Diffs    []ReconcileDiff `json:"diffs"`     // 差异, 按订单号排序, ReconcileUnknownOrder 在前并保持账单顺序
+}
+
+// HasDiff 是否存在差异
+func (r *ReconcileReport) HasDiff() bool {
+	return len(r.Diffs) > 0
+}
+
+// Reconcile 比对本地支付记录和账单记录, 只比对已支付的记录, 账单中的退款记录和非本渠道的本地记录被忽略.
+// 商户订单号无法解析的账单记录无法与本地记录比对, 逐条报告为 ReconcileUnknownOrder.
+//   - payType: 支付渠道
+//   - local: 本地支付记录
+//   - remote: 账单记录, 一般为 BillTypeTrade 账单
+func Reconcile(payType PayType, local []*PaymentResult, remote []BillRecord) *ReconcileReport {
+	report := &ReconcileReport{PayType: payType}
+
+	locals := make(map[uint64]*PaymentResult, len(local))
+
+	for _, p := range local {
+		if p == nil || p.PayType != payType || (p.TradeState != TradeStatePaid && p.TradeState != TradeStateRefunded) {
+			continue
+		}
+
+		locals[p.OrderID] = p
+	}
+
+	remotes := make(map[uint64]*BillRecord, len(remote))
+
+	for i := range remote {
+		r := &remote[i]
+		if r.TradeState != TradeStatePaid {
+			continue
+		}
+
+		// 按订单号为 0 放入 map 会互相覆盖, 单独报告
+		if r.OrderID == 0 {
+			report.Diffs = append(report.Diffs, ReconcileDiff{Type: ReconcileUnknownOrder, Remote: r})
+			continue
+		}
+
+		remotes[r.OrderID] = r
+	}
+
+	for orderID, r := range remotes {
+		l, ok := locals[orderID]
+
+		switch {
+		case !ok:
+			report.Diffs = append(report.Diffs, ReconcileDiff{Type: ReconcileMissingLocal, OrderID: orderID, Remote: r})
+		case l.TotalAmount != r.TotalAmount:
+			report.Diffs = append(report.Diffs, ReconcileDiff{Type: ReconcileAmountMismatch, OrderID: orderID, Local: l, Remote: r})
+		default:
+			report.Matched++
+		}
+	}
+
+	for orderID, l := range locals {
+		if _, ok := remotes[orderID]; !ok {
+			report.Diffs = append(report.Diffs, ReconcileDiff{Type: ReconcileMissingRemote, OrderID: orderID, Local: l})
+		}
+	}
+
+	// 稳定排序, 订单号相同的 ReconcileUnknownOrder 保持账单顺序
+	slices.SortStableFunc(report.Diffs, func(a, b ReconcileDiff) int {
+		return cmp.Or(cmp.Compare(a.OrderID, b.OrderID), cmp.Compare(a.Type, b.Type))
+	})
+
+	return report
+}
+
+// chinaLocation 支付渠道账单使用的时区 UTC+8
+var chinaLocation = time.FixedZone("CST", 8*60*60)
+
+// Reconciler 自动对账, 下载交易账单并与本地支付记录比对, 可通过 Task 创建每日执行的定时任务
+type Reconciler struct {
+	Downloader BillDownloader                                           // 账单下载, 例如 *WeChatPay、*Alipay
+	Source     SettlementSource                                         // 本地支付记录数据源, 与结算报表共用
+	Location   *time.Location                                           // 账单日期使用的时区, 为 nil 时使用 UTC+8
+	Output     func(ctx context.Context, report *ReconcileReport) error // 对账报告输出, 例如存储或告警, 可为 nil
+}
+
+// Run 对 date 当天的交易账单对账, 本地支付记录的支付时间范围为账单日期当天
+//   - date: 账单日期, 只使用年月日
+func (r *Reconciler) Run(ctx context.Context, date time.Time) (*ReconcileReport, error) {
+	loc := cmp.Or(r.Location, chinaLocation)
+
+	date = date.In(loc)
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+
+	remote, err := r.Downloader.DownloadBill(ctx, BillTypeTrade, start)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := r.Source.Payments(ctx, start, start.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("获取支付记录失败: %w", err)
+	}
+
+	local := make([]*PaymentResult, 0, len(payments))
+	for _, p := range payments {
+		local = append(local, p.Result)
+	}
+
+	report := Reconcile(r.Downloader.PayType(), local, remote)
+	report.BillDate = start.Format(time.DateOnly)
+
+	return report, nil
+}
+
+// Task 创建定时任务, 每次执行对前一天的交易账单对账并调用 Output, 存在差异时记录附带执行ID的警告日志.
+// 支付渠道一般在次日 10 点前生成前一天的账单, 多实例部署时建议设置返回任务的 Singleton.
+//   - name: 任务名称
+//   - spec: 定时任务表达式, 例如 "0 30 10 * * *"
+func (r *Reconciler) Task(name cron.Name, spec string) *cron.Task {
+	return &cron.Task{
+		Name: name,
+		Spec: spec,
+		ActionCtx: func(ctx context.Context) error {
+			report, err := r.Run(ctx, time.Now().AddDate(0, 0, -1))
+			if err != nil {
+				return err
+			}
+
+			if report.HasDiff() {
+				cron.Logger(ctx).Warn("对账存在差异",
+					zap.String("支付渠道", string(report.PayType)),
+					zap.String("账单日期", report.BillDate),
+					zap.Int("一致订单数", report.Matched),
+					zap.Int("差异数", len(report.Diffs)),
+				)
+			}
+
+			if r.Output == nil {
+				return nil
+			}
+
+			return r.Output(ctx, report)
+		},
+	}
+}
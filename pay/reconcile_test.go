@@ -0,0 +1,79 @@
+//
+// FilePath    : go-utils\pay\reconcile_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 对账差异比对测试
+//
+
+package pay
+
+import "testing"
+
+func TestReconcile(t *testing.T) {
+	local := []*PaymentResult{
+		{PayType: PayTypeAlipay, OrderID: 1, TotalAmount: 100, TradeState: TradeStatePaid},
+		{PayType: PayTypeAlipay, OrderID: 2, TotalAmount: 200, TradeState: TradeStatePaid},
+		{PayType: PayTypeAlipay, OrderID: 4, TotalAmount: 400, TradeState: TradeStateRefunded},
+		{PayType: PayTypeAlipay, OrderID: 5, TotalAmount: 500, TradeState: TradeStateUnpaid}, // 未支付, 忽略
+		{PayType: PayTypeWechat, OrderID: 6, TotalAmount: 600, TradeState: TradeStatePaid},   // 其他渠道, 忽略
+		nil,
+	}
+
+	remote := []BillRecord{
+		{OrderID: 1, TransactionID: "T1", TradeState: TradeStatePaid, TotalAmount: 100},
+		{OrderID: 2, TransactionID: "T2", TradeState: TradeStatePaid, TotalAmount: 199},
+		{OrderID: 3, TransactionID: "T3", TradeState: TradeStatePaid, TotalAmount: 300},
+		{OrderID: 1, TransactionID: "T1", TradeState: TradeStateRefunded, RefundAmount: 100}, // 退款记录, 忽略
+		{TransactionID: "T7", TradeState: TradeStatePaid, TotalAmount: 700},                  // 订单号无法解析
+		{TransactionID: "T8", TradeState: TradeStatePaid, TotalAmount: 800},                  // 订单号无法解析, 不覆盖 T7
+	}
+
+	report := Reconcile(PayTypeAlipay, local, remote)
+
+	if report.PayType != PayTypeAlipay || report.Matched != 1 || !report.HasDiff() {
+		t.Fatalf("report = %+v, want 1 matched with diffs", report)
+	}
+
+	want := []struct {
+		typ           ReconcileDiffType
+		orderID       uint64
+		transactionID string // 账单记录的交易号, ReconcileMissingRemote 时为空
+	}{
+		{ReconcileUnknownOrder, 0, "T7"},
+		{ReconcileUnknownOrder, 0, "T8"},
+		{ReconcileAmountMismatch, 2, "T2"},
+		{ReconcileMissingLocal, 3, "T3"},
+		{ReconcileMissingRemote, 4, ""},
+	}
+
+	if len(report.Diffs) != len(want) {
+		t.Fatalf("Diffs = %+v, want %d diffs", report.Diffs, len(want))
+	}
+
+	for i, w := range want {
+		d := report.Diffs[i]
+
+		var transactionID string
+		if d.Remote != nil {
+			transactionID = d.Remote.TransactionID
+		}
+
+		if d.Type != w.typ || d.OrderID != w.orderID || transactionID != w.transactionID {
+			t.Errorf("Diffs[%d] = %s %d %q, want %s %d %q", i, d.Type, d.OrderID, transactionID, w.typ, w.orderID, w.transactionID)
+		}
+
+		if (d.Local == nil) != (w.typ == ReconcileMissingLocal || w.typ == ReconcileUnknownOrder) {
+			t.Errorf("Diffs[%d].Local = %+v", i, d.Local)
+		}
+	}
+}
+
+func TestReconcile_NoDiff(t *testing.T) {
+	local := []*PaymentResult{{PayType: PayTypeWechat, OrderID: 1, TotalAmount: 100, TradeState: TradeStatePaid}}
+	remote := []BillRecord{{OrderID: 1, TradeState: TradeStatePaid, TotalAmount: 100}}
+
+	if report := Reconcile(PayTypeWechat, local, remote); report.HasDiff() || report.Matched != 1 {
+		t.Errorf("report = %+v, want 1 matched without diffs", report)
+	}
+}
@@ -0,0 +1,307 @@
+//
+// FilePath    : go-utils\pay\refund_ledger.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 退款台账, 支持同一订单多次部分退款, 校验累计退款金额并为支付宝部分退款生成唯一的退款请求号
+//
+
+package pay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jiaopengzi/go-utils/redis/cache"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// RefundEntry 单笔退款记录
+type RefundEntry struct {
+	RefundID     uint64       `json:"refund_id"`      // 退款ID
+	OutRequestNo string       `json:"out_request_no"` // 退款请求号, 订单内唯一, 支付宝部分退款使用
+	Amount       int64        `json:"amount"`         // 退款金额, 单位为分
+	Status       RefundStatus `json:"status"`         // 退款状态
+	CreatedAt    time.Time    `json:"created_at"`     // 创建时间
+}
+
+// occupied 是否占用可退余额, 关闭和失败的退款释放余额
+func (e *RefundEntry) occupied() bool {
+	return e.Status != RefundStatusClosed && e.Status != RefundStatusFailed
+}
+
+// OrderRefunds 订单的退款台账
+type OrderRefunds struct {
+	OrderID     uint64        `json:"order_id"`     // 订单ID
+	TotalAmount int64         `json:"total_amount"` // 订单总金额, 单位为分
+	Refunds     []RefundEntry `json:"refunds"`      // 退款记录, 按创建顺序
+}
+
+// RefundedAmount 累计退款金额, 包含处理中的退款, 不包含关闭和失败的退款
+func (o *OrderRefunds) RefundedAmount() int64 {
+	var sum int64
+
+	for i := range o.Refunds {
+		if o.Refunds[i].occupied() {
+			sum += o.Refunds[i].Amount
+		}
+	}
+
+	return sum
+}
+
+// Remaining 可退余额, 单位为分
+func (o *OrderRefunds) Remaining() int64 {
+	return o.TotalAmount - o.RefundedAmount()
+}
+
+// RefundLedgerStore 退款台账存储
+type RefundLedgerStore interface {
+	// Get 获取订单的退款台账, 不存在时返回 nil, nil
+	Get(ctx context.Context, orderID uint64) (*OrderRefunds, error)
+
+	// Update 原子地修改订单的退款台账, 不存在时 mutate 收到零值, mutate 返回错误时放弃修改
+	Update(ctx context.Context, orderID uint64, mutate func(o *OrderRefunds) error) (*OrderRefunds, error)
+}
+
+// MemoryRefundLedgerStore 基于内存的退款台账存储, 进程重启后丢失, 一般用于测试
+type MemoryRefundLedgerStore struct {
+	mu     sync.Mutex
+	orders map[uint64]OrderRefunds
+}
+
+// NewMemoryRefundLedgerStore 创建基于内存的退款台账存储
+func NewMemoryRefundLedgerStore() *MemoryRefundLedgerStore {
+	return &MemoryRefundLedgerStore{orders: make(map[uint64]OrderRefunds)}
+}
+
+// Get 实现 RefundLedgerStore 接口 Get 方法
+func (s *MemoryRefundLedgerStore) Get(_ context.Context, orderID uint64) (*OrderRefunds, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orders[orderID]
+	if !ok {
+		return nil, nil
+	}
+
+	o.Refunds = slices.Clone(o.Refunds)
+
+	return &o, nil
+}
+
+// Update 实现 RefundLedgerStore 接口 Update 方法
+func (s *MemoryRefundLedgerStore) Update(_ context.Context, orderID uint64, mutate func(o *OrderRefunds) error) (*OrderRefunds, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o := s.orders[orderID]
+	o.Refunds = slices.Clone(o.Refunds)
+
+	if err := mutate(&o); err != nil {
+		return nil, err
+	}
+
+	s.orders[orderID] = o
+
+	result := o
+	result.Refunds = slices.Clone(o.Refunds)
+
+	return &result, nil
+}
+
+// refundLedgerPurpose 退款台账缓存键的用途
+const refundLedgerPurpose cache.Purpose = "pay_refund_ledger"
+
+// CacheRefundLedgerStore 基于缓存(redis)的退款台账存储, 使用乐观锁保证并发退款不会超额
+type CacheRefundLedgerStore struct {
+	cache *cache.Client
+	ttl   time.Duration // 台账有效期, 为 0 表示永久
+}
+
+// NewCacheRefundLedgerStore 创建基于缓存的退款台账存储
+//   - c: 缓存客户端
+//   - ttl: 台账有效期, 应大于支付渠道允许退款的期限(一般为 1 年), 为 0 表示永久
+func NewCacheRefundLedgerStore(c *cache.Client, ttl time.Duration) *CacheRefundLedgerStore {
+	return &CacheRefundLedgerStore{cache: c, ttl: ttl}
+}
+
+// Get 实现 RefundLedgerStore 接口 Get 方法
+func (s *CacheRefundLedgerStore) Get(ctx context.Context, orderID uint64) (*OrderRefunds, error) {
+	var o OrderRefunds
+
+	err := s.cache.GetStringWithStruct(ctx, cache.GenerateKey(refundLedgerPurpose, orderID), &o)
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &o, nil
+}
+
+// Update 实现 RefundLedgerStore 接口 Update 方法
+func (s *CacheRefundLedgerStore) Update(ctx context.Context, orderID uint64, mutate func(o *OrderRefunds) error) (*OrderRefunds, error) {
+	return cache.UpdateStruct(ctx, s.cache, cache.GenerateKey(refundLedgerPurpose, orderID), s.ttl, mutate)
+}
+
+// requestNoRefunder 支持指定退款请求号的支付渠道, Alipay 已实现
+type requestNoRefunder interface {
+	RefundWithRequestNoContext(ctx context.Context, orderID, refundID uint64, amount, refundAmount int64, reason, outRequestNo string) (*RefundResult, error)
+}
+
+// RefundLedger 退款台账, 记录每个订单的累计退款金额, 发起退款前校验不超过可退余额
+type RefundLedger struct {
+	store RefundLedgerStore
+	now   func() time.Time
+}
+
+// NewRefundLedger 创建退款台账
+//   - store: 退款台账存储, 多实例部署时使用 CacheRefundLedgerStore
+func NewRefundLedger(store RefundLedgerStore) *RefundLedger {
+	return &RefundLedger{store: store, now: time.Now}
+}
+
+// Get 获取订单的退款台账, 没有退款记录时返回 nil, nil
+func (l *RefundLedger) Get(ctx context.Context, orderID uint64) (*OrderRefunds, error) {
+	return l.store.Get(ctx, orderID)
+}
+
+// Reserve 登记一笔待处理的退款并占用可退余额, 超过可退余额时返回 ErrRefundExceeded.
+// 同一退款ID重复登记且金额相同时返回已有记录, 便于重试; 除全额退款外, 为每笔退款生成订单内唯一的退款请求号.
+//   - orderID: 订单ID
+//   - refundID: 退款ID, 订单内唯一
+//   - totalAmount: 订单总金额, 单位为分
+//   - amount: 退款金额, 单位为分
+func (l *RefundLedger) Reserve(ctx context.Context, orderID, refundID uint64, totalAmount, amount int64) (*RefundEntry, error) {
+	if amount <= 0 || amount > totalAmount {
+		return nil, fmt.Errorf("%w: 退款金额 %d 不合法, 订单金额 %d", ErrRefundExceeded, amount, totalAmount)
+	}
+
+	var entry RefundEntry
+
+	_, err := l.store.Update(ctx, orderID, func(o *OrderRefunds) error {
+		if o.OrderID == 0 {
+			o.OrderID, o.TotalAmount = orderID, totalAmount
+		}
+
+		if o.TotalAmount != totalAmount {
+			return fmt.Errorf("订单 %d 金额不一致: 台账 %d, 请求 %d", orderID, o.TotalAmount, totalAmount)
+		}
+
+		// 重复登记
+		for _, e := range o.Refunds {
+			if e.RefundID != refundID || !e.occupied() {
+				continue
+			}
+
+			if e.Amount != amount {
+				return fmt.Errorf("退款 %d 已存在, 金额 %d 与请求 %d 不一致", refundID, e.Amount, amount)
+			}
+
+			entry = e
+
+			return nil
+		}
+
+		if remaining := o.Remaining(); amount > remaining {
+			return fmt.Errorf("%w: 订单 %d 可退余额 %d, 请求退款 %d", ErrRefundExceeded, orderID, remaining, amount)
+		}
+
+		entry = RefundEntry{
+			RefundID:     refundID,
+			OutRequestNo: fmt.Sprintf("%d-%d", orderID, len(o.Refunds)+1),
+			Amount:       amount,
+			Status:       RefundStatusPending,
+			CreatedAt:    l.now(),
+		}
+
+		// 第一笔且为全额退款时不需要退款请求号
+		if len(o.Refunds) == 0 && amount == totalAmount {
+			entry.OutRequestNo = ""
+		}
+
+		o.Refunds = append(o.Refunds, entry)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// SetStatus 更新退款状态, 例如收到退款结果通知或查询退款结果后; 关闭和失败的退款释放占用的余额
+//   - orderID: 订单ID
+//   - refundID: 退款ID
+//   - status: 退款状态
+func (l *RefundLedger) SetStatus(ctx context.Context, orderID, refundID uint64, status RefundStatus) error {
+	_, err := l.store.Update(ctx, orderID, func(o *OrderRefunds) error {
+		// 同一退款ID失败后可以重新登记, 更新最新的一条
+		for i := len(o.Refunds) - 1; i >= 0; i-- {
+			if o.Refunds[i].RefundID == refundID {
+				o.Refunds[i].Status = status
+				return nil
+			}
+		}
+
+		return fmt.Errorf("订单 %d 不存在退款 %d", orderID, refundID)
+	})
+
+	return err
+}
+
+// Refund 登记退款并调用支付渠道发起退款, 支付宝使用台账生成的退款请求号.
+// 支付渠道不可用(结果未知)时保留待处理状态, 使用相同的退款ID重试不会重复占用余额; 其他错误释放占用的余额.
+//   - provider: 支付渠道
+//   - orderID: 订单ID
+//   - refundID: 退款ID, 订单内唯一
+//   - totalAmount: 订单总金额, 单位为分
+//   - amount: 退款金额, 单位为分
+//   - reason: 退款原因
+func (l *RefundLedger) Refund(ctx context.Context, provider Provider, orderID, refundID uint64, totalAmount, amount int64, reason string) (*RefundResult, error) {
+	entry, err := l.Reserve(ctx, orderID, refundID, totalAmount, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *RefundResult
+
+	if r, ok := provider.(requestNoRefunder); ok {
+		result, err = r.RefundWithRequestNoContext(ctx, orderID, refundID, totalAmount, amount, reason, entry.OutRequestNo)
+	} else {
+		result, err = provider.RefundContext(ctx, orderID, refundID, totalAmount, amount, reason)
+	}
+
+	if err != nil {
+		if !errors.Is(err, ErrProviderUnavailable) {
+			l.updateStatus(ctx, orderID, entry.RefundID, RefundStatusFailed)
+		}
+
+		return nil, err
+	}
+
+	l.updateStatus(ctx, orderID, entry.RefundID, result.Status)
+
+	return result, nil
+}
+
+// updateStatus 更新退款状态, 失败只记录日志, 台账可通过 SetStatus 修正
+func (l *RefundLedger) updateStatus(ctx context.Context, orderID, refundID uint64, status RefundStatus) {
+	if err := l.SetStatus(ctx, orderID, refundID, status); err != nil {
+		zap.L().Error("更新退款台账失败",
+			zap.Uint64("订单ID", orderID),
+			zap.Uint64("退款ID", refundID),
+			zap.String("退款状态", string(status)),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,183 @@
+//
+// FilePath    : go-utils\pay\refund_ledger_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 退款台账测试
+//
+
+package pay
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// fakeRefundProvider 模拟支付渠道的退款, 依次返回 errs 中的错误, 用完后退款成功
+type fakeRefundProvider struct {
+	Provider
+
+	errs       []error  // 依次返回的错误, nil 表示成功
+	requestNos []string // 收到的退款请求号
+}
+
+// PayType 实现 Provider 接口
+func (p *fakeRefundProvider) PayType() PayType {
+	return PayTypeAlipay
+}
+
+// RefundContext 实现 Payer 接口, 不使用退款请求号
+func (p *fakeRefundProvider) RefundContext(ctx context.Context, orderID, refundID uint64, amount, refundAmount int64, reason string) (*RefundResult, error) {
+	return p.RefundWithRequestNoContext(ctx, orderID, refundID, amount, refundAmount, reason, "")
+}
+
+// RefundWithRequestNoContext 实现 requestNoRefunder 接口
+func (p *fakeRefundProvider) RefundWithRequestNoContext(_ context.Context, orderID, refundID uint64, amount, refundAmount int64, reason, outRequestNo string) (*RefundResult, error) {
+	p.requestNos = append(p.requestNos, outRequestNo)
+
+	if len(p.errs) > 0 {
+		err := p.errs[0]
+		p.errs = p.errs[1:]
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &RefundResult{
+		PayType:      PayTypeAlipay,
+		OrderID:      orderID,
+		RefundID:     refundID,
+		TotalAmount:  amount,
+		RefundAmount: refundAmount,
+		Reason:       reason,
+		Status:       RefundStatusSuccess,
+	}, nil
+}
+
+// TestRefundLedger_Reserve 测试超过可退余额和金额不合法时拒绝, 以及退款请求号的生成
+func TestRefundLedger_Reserve(t *testing.T) {
+	ctx := context.Background()
+	ledger := NewRefundLedger(NewMemoryRefundLedgerStore())
+
+	tests := []struct {
+		name          string
+		refundID      uint64
+		amount        int64
+		wantErr       bool // 是否返回错误
+		wantExceeded  bool // 是否为 ErrRefundExceeded
+		wantRequestNo string
+		wantRemaining int64
+	}{
+		{"金额为 0", 1, 0, true, true, "", 100},
+		{"超过订单金额", 1, 101, true, true, "", 100},
+		{"第一笔部分退款", 1, 60, false, false, "7-1", 40},
+		{"重复登记返回已有记录", 1, 60, false, false, "7-1", 40},
+		{"重复登记金额不一致", 1, 50, true, false, "", 40},
+		{"超过可退余额", 2, 41, true, true, "", 40},
+		{"退完剩余余额", 2, 40, false, false, "7-2", 0},
+		{"余额为 0", 3, 1, true, true, "", 0},
+	}
+
+	for _, tt := range tests {
+		entry, err := ledger.Reserve(ctx, 7, tt.refundID, 100, tt.amount)
+
+		switch {
+		case (err != nil) != tt.wantErr || errors.Is(err, ErrRefundExceeded) != tt.wantExceeded:
+			t.Fatalf("%s: Reserve() error = %v, want error %v, exceeded %v", tt.name, err, tt.wantErr, tt.wantExceeded)
+		case err == nil && entry.OutRequestNo != tt.wantRequestNo:
+			t.Errorf("%s: OutRequestNo = %q, want %q", tt.name, entry.OutRequestNo, tt.wantRequestNo)
+		}
+
+		if got := ledgerRemaining(t, ledger, 7); got != tt.wantRemaining {
+			t.Errorf("%s: Remaining() = %d, want %d", tt.name, got, tt.wantRemaining)
+		}
+	}
+
+	// 第一笔为全额退款时不需要退款请求号
+	entry, err := ledger.Reserve(ctx, 8, 1, 100, 100)
+	if err != nil || entry.OutRequestNo != "" {
+		t.Errorf("全额退款 Reserve() = %+v, %v, want empty OutRequestNo", entry, err)
+	}
+}
+
+// TestRefundLedger_RetryAfterUnavailable 测试支付渠道不可用时保留占用, 相同退款ID重试使用相同的退款请求号且不重复占用余额
+func TestRefundLedger_RetryAfterUnavailable(t *testing.T) {
+	ctx := context.Background()
+	ledger := NewRefundLedger(NewMemoryRefundLedgerStore())
+	provider := &fakeRefundProvider{errs: []error{newError(PayTypeAlipay, ErrProviderUnavailable, "", context.DeadlineExceeded, "refund timeout")}}
+
+	if _, err := ledger.Refund(ctx, provider, 7, 1, 100, 30, "test"); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("Refund() error = %v, want ErrProviderUnavailable", err)
+	}
+
+	if got := ledgerRemaining(t, ledger, 7); got != 70 {
+		t.Fatalf("结果未知时应保留占用, Remaining() = %d, want 70", got)
+	}
+
+	result, err := ledger.Refund(ctx, provider, 7, 1, 100, 30, "test")
+	if err != nil || result.Status != RefundStatusSuccess {
+		t.Fatalf("重试 Refund() = %+v, %v", result, err)
+	}
+
+	if want := []string{"7-1", "7-1"}; !slices.Equal(provider.requestNos, want) {
+		t.Errorf("退款请求号 = %v, want %v", provider.requestNos, want)
+	}
+
+	o, _ := ledger.Get(ctx, 7)
+	if len(o.Refunds) != 1 || o.Refunds[0].Status != RefundStatusSuccess || o.Remaining() != 70 {
+		t.Errorf("台账 = %+v", o)
+	}
+}
+
+// TestRefundLedger_ReReserveAfterFailure 测试支付渠道拒绝退款后释放余额, 相同退款ID可以重新登记并使用新的退款请求号
+func TestRefundLedger_ReReserveAfterFailure(t *testing.T) {
+	ctx := context.Background()
+	ledger := NewRefundLedger(NewMemoryRefundLedgerStore())
+	provider := &fakeRefundProvider{errs: []error{newError(PayTypeAlipay, ErrProviderRejected, "REFUND_AMT_NOT_EQUAL_TOTAL", nil, "refund rejected")}}
+
+	if _, err := ledger.Refund(ctx, provider, 7, 1, 100, 100, "test"); !errors.Is(err, ErrProviderRejected) {
+		t.Fatalf("Refund() error = %v, want ErrProviderRejected", err)
+	}
+
+	if got := ledgerRemaining(t, ledger, 7); got != 100 {
+		t.Fatalf("失败后应释放余额, Remaining() = %d, want 100", got)
+	}
+
+	if _, err := ledger.Refund(ctx, provider, 7, 1, 100, 100, "test"); err != nil {
+		t.Fatalf("重新登记 Refund() error = %v", err)
+	}
+
+	// 第一笔全额退款不需要退款请求号, 重新登记时已有失败记录, 生成新的退款请求号
+	if want := []string{"", "7-2"}; !slices.Equal(provider.requestNos, want) {
+		t.Errorf("退款请求号 = %v, want %v", provider.requestNos, want)
+	}
+
+	o, _ := ledger.Get(ctx, 7)
+	if len(o.Refunds) != 2 || o.Refunds[0].Status != RefundStatusFailed || o.Refunds[1].Status != RefundStatusSuccess || o.Remaining() != 0 {
+		t.Errorf("台账 = %+v", o)
+	}
+
+	// 已全额退款, 其他退款ID超过可退余额
+	if _, err := ledger.Refund(ctx, provider, 7, 2, 100, 1, "test"); !errors.Is(err, ErrRefundExceeded) {
+		t.Errorf("Refund() error = %v, want ErrRefundExceeded", err)
+	}
+}
+
+// ledgerRemaining 获取订单的可退余额, 没有台账时返回订单金额 100
+func ledgerRemaining(t *testing.T, ledger *RefundLedger, orderID uint64) int64 {
+	t.Helper()
+
+	o, err := ledger.Get(context.Background(), orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if o == nil {
+		return 100
+	}
+
+	return o.Remaining()
+}
@@ -0,0 +1,294 @@
+//
+// FilePath    : go-utils\pay\resilient.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 支付渠道调用的重试、熔断和超时装饰器, 避免网络抖动导致查询、退款、关单直接失败
+//
+
+package pay
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jiaopengzi/go-utils"
+	"github.com/jiaopengzi/go-utils/req"
+	"go.uber.org/zap"
+)
+
+// 重试默认值
+const (
+	defaultResilientAttempts   = 3                      // 默认最大执行次数(含首次)
+	defaultResilientBackoff    = 200 * time.Millisecond // 默认首次重试间隔
+	defaultResilientMaxBackoff = 5 * time.Second        // 默认最大重试间隔
+	defaultResilientJitter     = 0.2                    // 默认重试间隔抖动比例
+)
+
+// AttemptInfo 单次调用的结果, 用于 ResilientConfig.OnAttempt 上报指标
+type AttemptInfo struct {
+	PayType   PayType       // 支付渠道
+	Operation string        // 操作名称, 例如 QueryPayment
+	Attempt   int           // 第几次执行, 从 1 开始
+	Duration  time.Duration // 本次调用耗时
+	Err       error         // 本次调用的错误, 成功时为 nil
+	Retrying  bool          // 是否将重试
+}
+
+// ResilientConfig 重试、熔断和超时配置, 零值字段使用默认值
+type ResilientConfig struct {
+	MaxAttempts int           // 最大执行次数(含首次), 为 0 时默认 3, 为 1 时不重试
+	Backoff     time.Duration // 首次重试间隔, 之后每次翻倍, 为 0 时默认 200 毫秒
+	MaxBackoff  time.Duration // 最大重试间隔, 为 0 时默认 5 秒
+	Jitter      float64       // 重试间隔的随机抖动比例(0~1), 为 0 时默认 0.2, 小于 0 时不抖动
+	Timeout     time.Duration // 单次调用超时时间, 为 0 表示不限制
+
+	// Breaker 熔断器配置, 只有可重试的错误计为失败, IsFailure 不生效
+	Breaker req.BreakerConfig
+
+	// RetryIf 判断错误是否可重试, 为 nil 时使用 IsRetryable
+	RetryIf func(err error) bool
+
+	// OnAttempt 每次调用结束后回调, 可用于上报指标, 为 nil 时不回调
+	OnAttempt func(info AttemptInfo)
+}
+
+// IsRetryable 默认的可重试判断: 支付渠道不可用(网络错误、SDK 调用失败、超时), 业务失败不重试
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrProviderUnavailable) && !errors.Is(err, req.ErrCircuitOpen)
+}
+
+// ResilientProvider 支付渠道装饰器, 对 Prepay、QueryPayment、CloseOrder、Refund、QueryRefund 增加重试、熔断和超时.
+// 这些调用按商户订单号或退款单号幂等, 重试是安全的; 通知相关的方法直接调用被装饰的支付渠道.
+// 熔断器按支付渠道独立, 打开时直接返回包含 req.ErrCircuitOpen 的 ErrProviderUnavailable.
+type ResilientProvider struct {
+	Provider
+
+	conf    ResilientConfig
+	breaker *req.Breaker
+}
+
+// 确保 ResilientProvider 实现了 Provider 和 NotifyPathProvider 接口
+var (
+	_ Provider           = (*ResilientProvider)(nil)
+	_ NotifyPathProvider = (*ResilientProvider)(nil)
+)
+
+// NewResilientProvider 创建支付渠道装饰器
+//   - provider: 被装饰的支付渠道
+//   - conf: 重试、熔断和超时配置
+func NewResilientProvider(provider Provider, conf ResilientConfig) *ResilientProvider {
+	if conf.MaxAttempts <= 0 {
+		conf.MaxAttempts = defaultResilientAttempts
+	}
+
+	if conf.Backoff <= 0 {
+		conf.Backoff = defaultResilientBackoff
+	}
+
+	if conf.MaxBackoff <= 0 {
+		conf.MaxBackoff = defaultResilientMaxBackoff
+	}
+
+	if conf.Jitter == 0 {
+		conf.Jitter = defaultResilientJitter
+	}
+
+	if conf.RetryIf == nil {
+		conf.RetryIf = IsRetryable
+	}
+
+	return &ResilientProvider{
+		Provider: provider,
+		conf:     conf,
+		breaker:  req.NewBreaker(conf.Breaker),
+	}
+}
+
+// BreakerState 熔断器当前状态
+func (r *ResilientProvider) BreakerState() req.BreakerState {
+	return r.breaker.State()
+}
+
+// Unwrap 获取被装饰的支付渠道
+func (r *ResilientProvider) Unwrap() Provider {
+	return r.Provider
+}
+
+// NotifyPaths 实现 NotifyPathProvider 接口, 被装饰的支付渠道未实现时返回空路由
+func (r *ResilientProvider) NotifyPaths() (notifyPath, refundPath string) {
+	if p, ok := r.Provider.(NotifyPathProvider); ok {
+		return p.NotifyPaths()
+	}
+
+	return "", ""
+}
+
+// NotifyDeduper 获取被装饰的支付渠道的通知去重, 未配置时为 nil
+func (r *ResilientProvider) NotifyDeduper() NotificationDeduper {
+	if holder, ok := r.Provider.(notifyDeduperHolder); ok {
+		return holder.NotifyDeduper()
+	}
+
+	return nil
+}
+
+// Prepay 实现 Payer 接口, 增加重试、熔断和超时
+func (r *ResilientProvider) Prepay(orderID uint64, amount int64, description, returnURL string, timeExpire time.Time) (string, error) {
+	return r.PrepayContext(context.Background(), orderID, amount, description, returnURL, timeExpire)
+}
+
+// PrepayContext 实现 Payer 接口, 增加重试、熔断和超时
+func (r *ResilientProvider) PrepayContext(ctx context.Context, orderID uint64, amount int64, description, returnURL string, timeExpire time.Time) (string, error) {
+	return resilientCall(ctx, r, "Prepay", func(ctx context.Context) (string, error) {
+		return r.Provider.PrepayContext(ctx, orderID, amount, description, returnURL, timeExpire)
+	})
+}
+
+// QueryPayment 实现 Payer 接口, 增加重试、熔断和超时
+func (r *ResilientProvider) QueryPayment(orderID uint64) (*PaymentResult, error) {
+	return r.QueryPaymentContext(context.Background(), orderID)
+}
+
+// QueryPaymentContext 实现 Payer 接口, 增加重试、熔断和超时
+func (r *ResilientProvider) QueryPaymentContext(ctx context.Context, orderID uint64) (*PaymentResult, error) {
+	return resilientCall(ctx, r, "QueryPayment", func(ctx context.Context) (*PaymentResult, error) {
+		return r.Provider.QueryPaymentContext(ctx, orderID)
+	})
+}
+
+// CloseOrder 实现 Payer 接口, 增加重试、熔断和超时
+func (r *ResilientProvider) CloseOrder(orderID uint64) error {
+	return r.CloseOrderContext(context.Background(), orderID)
+}
+
+// CloseOrderContext 实现 Payer 接口, 增加重试、熔断和超时
+func (r *ResilientProvider) CloseOrderContext(ctx context.Context, orderID uint64) error {
+	_, err := resilientCall(ctx, r, "CloseOrder", func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, r.Provider.CloseOrderContext(ctx, orderID)
+	})
+
+	return err
+}
+
+// Refund 实现 Payer 接口, 增加重试、熔断和超时; 重试使用相同的退款单号, 支付渠道不会重复退款
+func (r *ResilientProvider) Refund(orderID, refundID uint64, amount, refundAmount int64, reason string) (*RefundResult, error) {
+	return r.RefundContext(context.Background(), orderID, refundID, amount, refundAmount, reason)
+}
+
+// RefundContext 实现 Payer 接口, 增加重试、熔断和超时; 重试使用相同的退款单号, 支付渠道不会重复退款
+func (r *ResilientProvider) RefundContext(ctx context.Context, orderID, refundID uint64, amount, refundAmount int64, reason string) (*RefundResult, error) {
+	return resilientCall(ctx, r, "Refund", func(ctx context.Context) (*RefundResult, error) {
+		return r.Provider.RefundContext(ctx, orderID, refundID, amount, refundAmount, reason)
+	})
+}
+
+// QueryRefund 实现 Payer 接口, 增加重试、熔断和超时
+func (r *ResilientProvider) QueryRefund(orderID, refundID uint64) (*RefundResult, error) {
+	return r.QueryRefundContext(context.Background(), orderID, refundID)
+}
+
+// QueryRefundContext 实现 Payer 接口, 增加重试、熔断和超时
+func (r *ResilientProvider) QueryRefundContext(ctx context.Context, orderID, refundID uint64) (*RefundResult, error) {
+	return resilientCall(ctx, r, "QueryRefund", func(ctx context.Context) (*RefundResult, error) {
+		return r.Provider.QueryRefundContext(ctx, orderID, refundID)
+	})
+}
+
+// resilientCall 按配置执行 fn, 熔断器打开时直接返回, 可重试的错误按退避间隔重试, ctx 取消后不再重试
+//   - ctx: 调用方的上下文, 单次调用的超时基于 ctx 派生
+//   - r: 支付渠道装饰器
+//   - op: 操作名称
+//   - fn: 实际调用
+func resilientCall[T any](ctx context.Context, r *ResilientProvider, op string, fn func(ctx context.Context) (T, error)) (T, error) {
+	payType := r.PayType()
+	attempt := 0
+
+	return utils.RetryValue(ctx, func(ctx context.Context) (T, error) {
+		attempt++
+
+		done, errB := r.breaker.Allow()
+		if errB != nil {
+			var zero T
+
+			return zero, utils.Permanent(newError(payType, ErrProviderUnavailable, "", errB, "%s rejected by circuit breaker", op))
+		}
+
+		start := time.Now()
+		result, err := callWithTimeout(ctx, payType, op, r.conf.Timeout, fn)
+
+		retryable := err != nil && r.conf.RetryIf(err)
+		done(retryable)
+
+		retrying := retryable && attempt < r.conf.MaxAttempts && ctx.Err() == nil
+
+		if r.conf.OnAttempt != nil {
+			r.conf.OnAttempt(AttemptInfo{
+				PayType:   payType,
+				Operation: op,
+				Attempt:   attempt,
+				Duration:  time.Since(start),
+				Err:       err,
+				Retrying:  retrying,
+			})
+		}
+
+		if !retrying {
+			return result, utils.Permanent(err)
+		}
+
+		return result, err
+	},
+		utils.WithMaxAttempts(r.conf.MaxAttempts),
+		utils.WithExponentialBackoff(r.conf.Backoff, r.conf.MaxBackoff),
+		utils.WithJitter(r.conf.Jitter),
+		utils.RetryIf(func(error) bool { return true }),
+		utils.WithOnRetry(func(attempt int, err error, wait time.Duration) {
+			zap.L().Warn("支付渠道调用失败, 等待重试",
+				zap.String("支付渠道", string(payType)),
+				zap.String("操作", op),
+				zap.Int("已执行次数", attempt),
+				zap.Duration("等待时间", wait),
+				zap.String("traceID", utils.TraceIDFromContext(ctx)),
+				zap.Error(err),
+			)
+		}),
+	)
+}
+
+// callWithTimeout 执行 fn, 超过 timeout 或 ctx 取消时返回 ErrProviderUnavailable, fn 收到派生的 ctx, 超时后 fn 的结果被丢弃
+//   - ctx: 调用方的上下文
+//   - payType: 支付渠道
+//   - op: 操作名称
+//   - timeout: 超时时间, 为 0 表示不限制
+//   - fn: 实际调用
+func callWithTimeout[T any](ctx context.Context, payType PayType, op string, timeout time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		value T
+		err   error
+	}
+
+	ch := make(chan result, 1)
+
+	go func() {
+		v, err := fn(ctx)
+		ch <- result{value: v, err: err}
+	}()
+
+	select {
+	case out := <-ch:
+		return out.value, out.err
+	case <-ctx.Done():
+		var zero T
+
+		return zero, newError(payType, ErrProviderUnavailable, "", ctx.Err(), "%s timeout after %s", op, timeout)
+	}
+}
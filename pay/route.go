@@ -0,0 +1,185 @@
+//
+// FilePath    : go-utils\pay\route.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 自动注册支付结果通知和退款结果通知路由, 验签后调用业务回调并按支付渠道要求应答
+//
+
+package pay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/go-utils/res"
+	"go.uber.org/zap"
+)
+
+// PaymentCallbacks 支付通知业务回调, 返回错误时应答失败, 支付渠道会重试通知, 回调需要保证幂等.
+// 支付渠道配置了通知去重时, 已处理的通知应答成功, 正在处理的通知应答失败, 都不会调用回调;
+// 回调成功后标记为已处理, 返回错误时删除处理中标记.
+type PaymentCallbacks struct {
+	// OnPayment 支付成功通知, 必选; result 已验签, 需要使用 ValidateNotifyPayment 校验订单号和金额
+	OnPayment func(ctx context.Context, provider Provider, result *PaymentResult) error
+
+	// OnRefund 退款结果通知, 可选, 为 nil 时不注册退款通知路由
+	OnRefund func(ctx context.Context, provider Provider, result *RefundResult) error
+}
+
+// NotifyPathProvider 提供通知路由的支付渠道, WeChatPay 和 Alipay 已实现
+type NotifyPathProvider interface {
+	// NotifyPaths 获取支付结果通知和退款结果通知的路由, 相对于 APIPath + PayBasePath
+	NotifyPaths() (notifyPath, refundPath string)
+}
+
+// NotifyPaths 实现 NotifyPathProvider 接口
+func (w *WeChatPay) NotifyPaths() (notifyPath, refundPath string) {
+	return w.Conf.NotifyPath, w.Conf.RefundPath
+}
+
+// NotifyPaths 实现 NotifyPathProvider 接口
+func (a *Alipay) NotifyPaths() (notifyPath, refundPath string) {
+	return a.Conf.NotifyPath, a.Conf.RefundPath
+}
+
+// RegisterRoutes 在 r 上注册支付渠道的支付结果通知和退款结果通知路由(POST).
+// 通知地址为 NotifyHost/APIPath + PayBasePath + NotifyPath, 因此 r 需要是 APIPath + PayBasePath 对应的路由组.
+// provider 需要实现 NotifyPathProvider, 未实现或没有配置 NotifyPath、缺少 OnPayment 时 panic.
+//   - r: 路由, 例如 engine.Group("/api/v1/pay")
+//   - provider: 支付渠道
+//   - callbacks: 业务回调
+func RegisterRoutes(r gin.IRouter, provider Provider, callbacks PaymentCallbacks) {
+	paths, ok := provider.(NotifyPathProvider)
+	if !ok {
+		panic(fmt.Sprintf("pay: provider %s does not implement NotifyPathProvider", provider.PayType()))
+	}
+
+	notifyPath, refundPath := paths.NotifyPaths()
+	if notifyPath == "" || callbacks.OnPayment == nil {
+		panic(fmt.Sprintf("pay: provider %s notify path and OnPayment callback are required", provider.PayType()))
+	}
+
+	r.POST(notifyPath, paymentNotifyHandler(provider, callbacks.OnPayment))
+
+	if refundPath != "" && callbacks.OnRefund != nil {
+		r.POST(refundPath, refundNotifyHandler(provider, callbacks.OnRefund))
+	}
+}
+
+// paymentNotifyHandler 支付结果通知处理函数
+func paymentNotifyHandler(provider Provider, onPayment func(context.Context, Provider, *PaymentResult) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, result, err := provider.GetNotifyPayment(c.Request)
+		if errors.Is(err, ErrDuplicateNotify) {
+			// 重复的通知已经处理过, 直接应答成功
+			respondNotify(c, provider.PayType(), true)
+
+			return
+		}
+
+		if errors.Is(err, ErrNotifyProcessing) {
+			// 相同的通知正在处理, 应答失败, 处理失败时支付渠道重发的通知可以再次处理
+			respondNotify(c, provider.PayType(), false)
+
+			return
+		}
+
+		if err != nil || !ok {
+			zap.L().Warn("支付结果通知处理失败", zap.String("支付渠道", string(provider.PayType())), zap.Error(err))
+			respondNotify(c, provider.PayType(), false)
+
+			return
+		}
+
+		err = onPayment(c.Request.Context(), provider, result)
+
+		// 使用不随请求取消的上下文, 保证去重标记及时更新
+		FinishNotify(context.WithoutCancel(c.Request.Context()), provider, PaymentNotifyKey(result), err)
+
+		if err != nil {
+			zap.L().Warn("支付结果通知业务处理失败",
+				zap.String("支付渠道", string(provider.PayType())),
+				zap.Uint64("订单ID", result.OrderID),
+				zap.Error(err),
+			)
+			respondNotify(c, provider.PayType(), false)
+
+			return
+		}
+
+		respondNotify(c, provider.PayType(), true)
+	}
+}
+
+// refundNotifyHandler 退款结果通知处理函数
+func refundNotifyHandler(provider Provider, onRefund func(context.Context, Provider, *RefundResult) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, result, err := provider.GetNotifyRefund(c.Request)
+		if errors.Is(err, ErrDuplicateNotify) {
+			// 重复的通知已经处理过, 直接应答成功
+			respondNotify(c, provider.PayType(), true)
+
+			return
+		}
+
+		if errors.Is(err, ErrNotifyProcessing) {
+			// 相同的通知正在处理, 应答失败, 处理失败时支付渠道重发的通知可以再次处理
+			respondNotify(c, provider.PayType(), false)
+
+			return
+		}
+
+		if err != nil || !ok {
+			zap.L().Warn("退款结果通知处理失败", zap.String("支付渠道", string(provider.PayType())), zap.Error(err))
+			respondNotify(c, provider.PayType(), false)
+
+			return
+		}
+
+		err = onRefund(c.Request.Context(), provider, result)
+
+		// 使用不随请求取消的上下文, 保证去重标记及时更新
+		FinishNotify(context.WithoutCancel(c.Request.Context()), provider, RefundNotifyKey(result), err)
+
+		if err != nil {
+			zap.L().Warn("退款结果通知业务处理失败",
+				zap.String("支付渠道", string(provider.PayType())),
+				zap.Uint64("退款ID", result.RefundID),
+				zap.Error(err),
+			)
+			respondNotify(c, provider.PayType(), false)
+
+			return
+		}
+
+		respondNotify(c, provider.PayType(), true)
+	}
+}
+
+// respondNotify 按支付渠道要求应答通知, 支付宝应答纯文本 success/fail, 其他渠道使用 res.MsgResPayNotify
+func respondNotify(c *gin.Context, payType PayType, success bool) {
+	if payType == PayTypeAlipay {
+		// 文档: https://opendocs.alipay.com/open/270/105902
+		body := "fail"
+		if success {
+			body = "success"
+		}
+
+		c.String(http.StatusOK, body)
+		c.Abort()
+
+		return
+	}
+
+	// 文档: https://pay.weixin.qq.com/doc/v3/merchant/4012791861
+	r := &res.ResPayNotify{IsSuccess: success, Code: "SUCCESS", Message: "成功"}
+	if !success {
+		r.Code, r.Message = "FAIL", "失败"
+	}
+
+	res.MsgResPayNotify(r, c)
+}
@@ -0,0 +1,287 @@
+//
+// FilePath    : go-utils\pay\settlement.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 结算报表, 按日/月、支付渠道、币种汇总支付和退款, 支持导出 CSV/XLSX 并通过定时任务生成
+//
+
+package pay
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jiaopengzi/go-utils/cron"
+	"github.com/jiaopengzi/go-utils/model"
+	"go.uber.org/zap"
+)
+
+// SettlementPeriod 结算周期
+type SettlementPeriod string
+
+// 结算周期常量
+const (
+	SettlementDaily   SettlementPeriod = "daily"   // 按日
+	SettlementMonthly SettlementPeriod = "monthly" // 按月
+)
+
+// layout 周期标识的时间格式
+func (p SettlementPeriod) layout() string {
+	if p == SettlementMonthly {
+		return "2006-01"
+	}
+
+	return time.DateOnly
+}
+
+// truncate 获取 t 所在周期的开始时间
+func (p SettlementPeriod) truncate(t time.Time) time.Time {
+	if p == SettlementMonthly {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// previous 获取 now 所在周期的上一个完整周期 [start, end)
+func (p SettlementPeriod) previous(now time.Time) (start, end time.Time) {
+	end = p.truncate(now)
+
+	if p == SettlementMonthly {
+		return end.AddDate(0, -1, 0), end
+	}
+
+	return end.AddDate(0, 0, -1), end
+}
+
+// PaymentRecord 结算使用的支付记录
+type PaymentRecord struct {
+	Result   *PaymentResult // 支付结果
+	Currency model.Currency // 币种
+	PaidAt   time.Time      // 支付时间
+}
+
+// RefundRecord 结算使用的退款记录
+type RefundRecord struct {
+	Result     *RefundResult  // 退款结果
+	Currency   model.Currency // 币种
+	RefundedAt time.Time      // 退款时间
+}
+
+// SettlementSource 结算数据源, 由应用从订单表、退款表中读取
+type SettlementSource interface {
+	// Payments 获取支付时间在 [start, end) 内的支付记录
+	Payments(ctx context.Context, start, end time.Time) ([]PaymentRecord, error)
+
+	// Refunds 获取退款时间在 [start, end) 内的退款记录
+	Refunds(ctx context.Context, start, end time.Time) ([]RefundRecord, error)
+}
+
+// SettlementSummary 结算汇总, 每个周期、支付渠道、币种一条
+type SettlementSummary struct {
+	Period        string         `json:"period"`         // 周期标识, 按日为 2006-01-02, 按月为 2006-01
+	PayType       PayType        `json:"pay_type"`       // 支付渠道
+	Currency      model.Currency `json:"currency"`       // 币种
+	PaymentCount  int            `json:"payment_count"`  // 支付笔数
+	PaymentAmount int64          `json:"payment_amount"` // 支付金额, 单位为分
+	RefundCount   int            `json:"refund_count"`   // 退款笔数
+	RefundAmount  int64          `json:"refund_amount"`  // 退款金额, 单位为分
+	NetAmount     int64          `json:"net_amount"`     // 净收入(支付金额 - 退款金额), 单位为分
+}
+
+// settlementKey 汇总分组键
+type settlementKey struct {
+	period   string
+	payType  PayType
+	currency model.Currency
+}
+
+// SummarizeSettlement 汇总支付和退款记录, 结果按周期、支付渠道、币种排序.
+// 只统计已支付(包括已转入退款)的支付记录和退款成功的退款记录.
+//   - period: 结算周期
+//   - loc: 划分周期使用的时区, 为 nil 时使用 time.Local
+//   - payments: 支付记录
+//   - refunds: 退款记录
+func SummarizeSettlement(period SettlementPeriod, loc *time.Location, payments []PaymentRecord, refunds []RefundRecord) []SettlementSummary {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	groups := make(map[settlementKey]*SettlementSummary)
+
+	group := func(at time.Time, payType PayType, currency model.Currency) *SettlementSummary {
+		key := settlementKey{
+			period:   period.truncate(at.In(loc)).Format(period.layout()),
+			payType:  payType,
+			currency: currency,
+		}
+
+		s, ok := groups[key]
+		if !ok {
+			s = &SettlementSummary{Period: key.period, PayType: payType, Currency: currency}
+			groups[key] = s
+		}
+
+		return s
+	}
+
+	for _, p := range payments {
+		if p.Result == nil || (p.Result.TradeState != TradeStatePaid && p.Result.TradeState != TradeStateRefunded) {
+			continue
+		}
+
+		s := group(p.PaidAt, p.Result.PayType, p.Currency)
+		s.PaymentCount++
+		s.PaymentAmount += p.Result.TotalAmount
+		s.NetAmount += p.Result.TotalAmount
+	}
+
+	for _, r := range refunds {
+		if r.Result == nil || r.Result.Status != RefundStatusSuccess {
+			continue
+		}
+
+		s := group(r.RefundedAt, r.Result.PayType, r.Currency)
+		s.RefundCount++
+		s.RefundAmount += r.Result.RefundAmount
+		s.NetAmount -= r.Result.RefundAmount
+	}
+
+	summaries := make([]SettlementSummary, 0, len(groups))
+	for _, s := range groups {
+		summaries = append(summaries, *s)
+	}
+
+	slices.SortFunc(summaries, func(a, b SettlementSummary) int {
+		return cmp.Or(
+			cmp.Compare(a.Period, b.Period),
+			cmp.Compare(a.PayType, b.PayType),
+			cmp.Compare(a.Currency, b.Currency),
+		)
+	})
+
+	return summaries
+}
+
+// SettlementReport 结算报表
+type SettlementReport struct {
+	Period    SettlementPeriod    // 结算周期
+	Start     time.Time           // 统计开始时间(包含)
+	End       time.Time           // 统计结束时间(不包含)
+	Summaries []SettlementSummary // 汇总数据
+	Format    SettlementFormat    // 导出格式
+	Filename  string              // 建议的文件名, 例如 settlement_daily_2026-01-02.csv
+	Data      []byte              // 导出的文件内容
+}
+
+// SettlementReporter 结算报表生成器
+type SettlementReporter struct {
+	Source   SettlementSource // 数据源
+	Period   SettlementPeriod // 结算周期, 为空时按日
+	Location *time.Location   // 划分周期使用的时区, 为 nil 时使用 time.Local
+	Format   SettlementFormat // 导出格式, 为空时为 CSV
+
+	// Output 报表输出, 例如保存到对象存储或发送邮件; 定时任务生成报表后调用
+	Output func(ctx context.Context, report *SettlementReport) error
+}
+
+// Generate 生成 [start, end) 内的结算报表
+func (r *SettlementReporter) Generate(ctx context.Context, start, end time.Time) (*SettlementReport, error) {
+	if r.Source == nil {
+		return nil, &Error{Kind: ErrInvalidConfig, Msg: "结算数据源不能为空"}
+	}
+
+	period := cmp.Or(r.Period, SettlementDaily)
+	format := cmp.Or(r.Format, SettlementFormatCSV)
+
+	writer, ok := settlementWriters[format]
+	if !ok {
+		return nil, &Error{Kind: ErrInvalidConfig, Msg: fmt.Sprintf("不支持的结算报表格式 %s", format)}
+	}
+
+	payments, err := r.Source.Payments(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("获取支付记录失败: %w", err)
+	}
+
+	refunds, err := r.Source.Refunds(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("获取退款记录失败: %w", err)
+	}
+
+	report := &SettlementReport{
+		Period:    period,
+		Start:     start,
+		End:       end,
+		Summaries: SummarizeSettlement(period, r.Location, payments, refunds),
+		Format:    format,
+		Filename:  fmt.Sprintf("settlement_%s_%s.%s", period, start.Format(period.layout()), format),
+	}
+
+	var buf bytes.Buffer
+	if err := writer(&buf, report.Summaries); err != nil {
+		return nil, fmt.Errorf("导出结算报表失败: %w", err)
+	}
+
+	report.Data = buf.Bytes()
+
+	return report, nil
+}
+
+// GeneratePrevious 生成 now 所在周期的上一个完整周期的结算报表, 例如按日时为昨天, 按月时为上个月
+func (r *SettlementReporter) GeneratePrevious(ctx context.Context, now time.Time) (*SettlementReport, error) {
+	loc := r.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	start, end := cmp.Or(r.Period, SettlementDaily).previous(now.In(loc))
+
+	return r.Generate(ctx, start, end)
+}
+
+// Task 创建定时任务, 每次执行生成上一个完整周期的结算报表并调用 Output.
+// 多实例部署时建议设置返回任务的 Singleton, 避免重复生成.
+//   - name: 任务名称
+//   - spec: 定时任务表达式, 例如按日 "0 10 0 * * *", 按月 "0 10 0 1 * *"
+func (r *SettlementReporter) Task(name cron.Name, spec string) *cron.Task {
+	return &cron.Task{
+		Name: name,
+		Spec: spec,
+		ActionCtx: func(ctx context.Context) error {
+			report, err := r.GeneratePrevious(ctx, time.Now())
+			if err != nil {
+				return err
+			}
+
+			zap.L().Info("生成结算报表",
+				zap.String("文件名", report.Filename),
+				zap.Time("开始时间", report.Start),
+				zap.Time("结束时间", report.End),
+				zap.Int("汇总条数", len(report.Summaries)),
+			)
+
+			if r.Output == nil {
+				return nil
+			}
+
+			return r.Output(ctx, report)
+		},
+	}
+}
+
+// RegisterSettlementTask 通过 cron.RegisterTask 注册结算报表定时任务, 在 cron.Init 时添加到任务管理器
+//   - reporter: 结算报表生成器
+//   - name: 任务名称
+//   - spec: 定时任务表达式
+func RegisterSettlementTask(reporter *SettlementReporter, name cron.Name, spec string) {
+	cron.RegisterTask(func() error {
+		cron.Tasks = append(cron.Tasks, reporter.Task(name, spec))
+		return nil
+	})
+}
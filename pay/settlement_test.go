@@ -0,0 +1,231 @@
+//
+// FilePath    : go-utils\pay\settlement_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 结算报表汇总测试
+//
+
+package pay
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jiaopengzi/go-utils/model"
+)
+
+// 测试使用的时区, 使用固定偏移避免依赖时区数据库
+var (
+	zoneShanghai = time.FixedZone("UTC+8", 8*3600)
+	zoneNewYork  = time.FixedZone("UTC-5", -5*3600)
+)
+
+// paidRecord 创建已支付的支付记录
+func paidRecord(payType PayType, currency model.Currency, amount int64, paidAt time.Time) PaymentRecord {
+	return PaymentRecord{
+		Result:   &PaymentResult{PayType: payType, TotalAmount: amount, TradeState: TradeStatePaid},
+		Currency: currency,
+		PaidAt:   paidAt,
+	}
+}
+
+// refundRecord 创建退款记录
+func refundRecord(payType PayType, currency model.Currency, amount int64, status RefundStatus, refundedAt time.Time) RefundRecord {
+	return RefundRecord{
+		Result:     &RefundResult{PayType: payType, RefundAmount: amount, Status: status},
+		Currency:   currency,
+		RefundedAt: refundedAt,
+	}
+}
+
+func TestSummarizeSettlement(t *testing.T) {
+	// UTC 1 月 31 日 20:00, UTC+8 为 2 月 1 日 04:00, UTC-5 为 1 月 31 日 15:00
+	boundary := time.Date(2026, 1, 31, 20, 0, 0, 0, time.UTC)
+	// UTC 1 月 15 日 03:00, UTC-5 为 1 月 14 日 22:00
+	midJanuary := time.Date(2026, 1, 15, 3, 0, 0, 0, time.UTC)
+	// 1 月的支付在 2 月退款
+	february := time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC)
+
+	payments := []PaymentRecord{
+		paidRecord(PayTypeWechat, model.CurrencyCNY, 100, boundary),
+		paidRecord(PayTypeAlipay, model.CurrencyCNY, 200, midJanuary),
+		paidRecord(PayTypeAlipay, model.CurrencyUSD, 300, midJanuary),
+		{Result: &PaymentResult{PayType: PayTypeAlipay, TotalAmount: 400, TradeState: TradeStateRefunded}, Currency: model.CurrencyCNY, PaidAt: midJanuary},
+		{Result: &PaymentResult{PayType: PayTypeAlipay, TotalAmount: 999, TradeState: TradeStateUnpaid}, Currency: model.CurrencyCNY, PaidAt: midJanuary}, // 未支付, 忽略
+		// 没有支付结果, 忽略
+		{Currency: model.CurrencyCNY, PaidAt: midJanuary},
+	}
+
+	refunds := []RefundRecord{
+		refundRecord(PayTypeAlipay, model.CurrencyCNY, 150, RefundStatusSuccess, february),
+		refundRecord(PayTypeAlipay, model.CurrencyCNY, 999, RefundStatusFailed, february),     // 退款失败, 忽略
+		refundRecord(PayTypeAlipay, model.CurrencyCNY, 999, RefundStatusProcessing, february), // 退款处理中, 忽略
+	}
+
+	tests := []struct {
+		name   string
+		period SettlementPeriod
+		loc    *time.Location
+		want   []SettlementSummary
+	}{
+		{
+			name:   "按日 UTC+8 跨日",
+			period: SettlementDaily,
+			loc:    zoneShanghai,
+			want: []SettlementSummary{
+				{Period: "2026-01-15", PayType: PayTypeAlipay, Currency: model.CurrencyCNY, PaymentCount: 2, PaymentAmount: 600, NetAmount: 600},
+				{Period: "2026-01-15", PayType: PayTypeAlipay, Currency: model.CurrencyUSD, PaymentCount: 1, PaymentAmount: 300, NetAmount: 300},
+				{Period: "2026-02-01", PayType: PayTypeWechat, Currency: model.CurrencyCNY, PaymentCount: 1, PaymentAmount: 100, NetAmount: 100},
+				{Period: "2026-02-10", PayType: PayTypeAlipay, Currency: model.CurrencyCNY, RefundCount: 1, RefundAmount: 150, NetAmount: -150},
+			},
+		},
+		{
+			name:   "按日 UTC-5 跨日",
+			period: SettlementDaily,
+			loc:    zoneNewYork,
+			want: []SettlementSummary{
+				{Period: "2026-01-14", PayType: PayTypeAlipay, Currency: model.CurrencyCNY, PaymentCount: 2, PaymentAmount: 600, NetAmount: 600},
+				{Period: "2026-01-14", PayType: PayTypeAlipay, Currency: model.CurrencyUSD, PaymentCount: 1, PaymentAmount: 300, NetAmount: 300},
+				{Period: "2026-01-31", PayType: PayTypeWechat, Currency: model.CurrencyCNY, PaymentCount: 1, PaymentAmount: 100, NetAmount: 100},
+				{Period: "2026-02-10", PayType: PayTypeAlipay, Currency: model.CurrencyCNY, RefundCount: 1, RefundAmount: 150, NetAmount: -150},
+			},
+		},
+		{
+			name:   "按月 UTC+8 跨月, 退款计入退款所在月份",
+			period: SettlementMonthly,
+			loc:    zoneShanghai,
+			want: []SettlementSummary{
+				{Period: "2026-01", PayType: PayTypeAlipay, Currency: model.CurrencyCNY, PaymentCount: 2, PaymentAmount: 600, NetAmount: 600},
+				{Period: "2026-01", PayType: PayTypeAlipay, Currency: model.CurrencyUSD, PaymentCount: 1, PaymentAmount: 300, NetAmount: 300},
+				{Period: "2026-02", PayType: PayTypeAlipay, Currency: model.CurrencyCNY, RefundCount: 1, RefundAmount: 150, NetAmount: -150},
+				{Period: "2026-02", PayType: PayTypeWechat, Currency: model.CurrencyCNY, PaymentCount: 1, PaymentAmount: 100, NetAmount: 100},
+			},
+		},
+		{
+			name:   "按月 UTC-5",
+			period: SettlementMonthly,
+			loc:    zoneNewYork,
+			want: []SettlementSummary{
+				{Period: "2026-01", PayType: PayTypeAlipay, Currency: model.CurrencyCNY, PaymentCount: 2, PaymentAmount: 600, NetAmount: 600},
+				{Period: "2026-01", PayType: PayTypeAlipay, Currency: model.CurrencyUSD, PaymentCount: 1, PaymentAmount: 300, NetAmount: 300},
+				{Period: "2026-01", PayType: PayTypeWechat, Currency: model.CurrencyCNY, PaymentCount: 1, PaymentAmount: 100, NetAmount: 100},
+				{Period: "2026-02", PayType: PayTypeAlipay, Currency: model.CurrencyCNY, RefundCount: 1, RefundAmount: 150, NetAmount: -150},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SummarizeSettlement(tt.period, tt.loc, payments, refunds)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SummarizeSettlement() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSummarizeSettlement_RefundNetting 同一周期内的支付和退款合并为一条, 净收入为支付金额减退款金额
+func TestSummarizeSettlement_RefundNetting(t *testing.T) {
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, zoneShanghai)
+
+	got := SummarizeSettlement(SettlementDaily, zoneShanghai,
+		[]PaymentRecord{paidRecord(PayTypeWechat, model.CurrencyCNY, 1000, at), paidRecord(PayTypeWechat, model.CurrencyCNY, 500, at)},
+		[]RefundRecord{refundRecord(PayTypeWechat, model.CurrencyCNY, 300, RefundStatusSuccess, at.Add(time.Hour))},
+	)
+
+	want := []SettlementSummary{
+		{Period: "2026-03-01", PayType: PayTypeWechat, Currency: model.CurrencyCNY, PaymentCount: 2, PaymentAmount: 1500, RefundCount: 1, RefundAmount: 300, NetAmount: 1200},
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("SummarizeSettlement() = %+v, want %+v", got, want)
+	}
+}
+
+// fakeSettlementSource 返回固定的支付和退款记录, 记录查询的时间范围
+type fakeSettlementSource struct {
+	payments   []PaymentRecord
+	refunds    []RefundRecord
+	start, end time.Time
+	err        error
+}
+
+// Payments 实现 SettlementSource 接口
+func (s *fakeSettlementSource) Payments(_ context.Context, start, end time.Time) ([]PaymentRecord, error) {
+	s.start, s.end = start, end
+	return s.payments, s.err
+}
+
+// Refunds 实现 SettlementSource 接口
+func (s *fakeSettlementSource) Refunds(context.Context, time.Time, time.Time) ([]RefundRecord, error) {
+	return s.refunds, nil
+}
+
+func TestSettlementReporter_GeneratePrevious(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 30, 0, 0, zoneShanghai)
+
+	tests := []struct {
+		name         string
+		period       SettlementPeriod
+		format       SettlementFormat
+		wantStart    time.Time
+		wantEnd      time.Time
+		wantFilename string
+	}{
+		{"按日", "", "", time.Date(2026, 2, 28, 0, 0, 0, 0, zoneShanghai), time.Date(2026, 3, 1, 0, 0, 0, 0, zoneShanghai), "settlement_daily_2026-02-28.csv"},
+		{"按月", SettlementMonthly, SettlementFormatXLSX, time.Date(2026, 2, 1, 0, 0, 0, 0, zoneShanghai), time.Date(2026, 3, 1, 0, 0, 0, 0, zoneShanghai), "settlement_monthly_2026-02.xlsx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &fakeSettlementSource{payments: []PaymentRecord{paidRecord(PayTypeAlipay, model.CurrencyCNY, 100, now.Add(-time.Hour))}}
+			reporter := &SettlementReporter{Source: source, Period: tt.period, Location: zoneShanghai, Format: tt.format}
+
+			report, err := reporter.GeneratePrevious(context.Background(), now.UTC())
+			if err != nil {
+				t.Fatalf("GeneratePrevious() error = %v", err)
+			}
+
+			if !source.start.Equal(tt.wantStart) || !source.end.Equal(tt.wantEnd) {
+				t.Errorf("查询范围 = [%v, %v), want [%v, %v)", source.start, source.end, tt.wantStart, tt.wantEnd)
+			}
+
+			if report.Filename != tt.wantFilename || len(report.Summaries) != 1 || len(report.Data) == 0 {
+				t.Errorf("report = %s, %+v, %d bytes", report.Filename, report.Summaries, len(report.Data))
+			}
+		})
+	}
+}
+
+func TestSettlementReporter_GenerateInvalid(t *testing.T) {
+	errSource := errors.New("db down")
+
+	tests := []struct {
+		name     string
+		reporter *SettlementReporter
+		wantKind ErrorKind
+		wantErr  error
+	}{
+		{"没有数据源", &SettlementReporter{}, ErrInvalidConfig, nil},
+		{"不支持的格式", &SettlementReporter{Source: &fakeSettlementSource{}, Format: "pdf"}, ErrInvalidConfig, nil},
+		{"数据源错误", &SettlementReporter{Source: &fakeSettlementSource{err: errSource}}, "", errSource},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.reporter.Generate(context.Background(), time.Now().Add(-time.Hour), time.Now())
+
+			if tt.wantKind != "" && !errors.Is(err, tt.wantKind) {
+				t.Errorf("Generate() error = %v, want %v", err, tt.wantKind)
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Generate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+//
+// FilePath    : go-utils\pay\settlement_writer.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 结算报表导出, CSV 和 XLSX
+//
+
+package pay
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SettlementFormat 结算报表导出格式
+type SettlementFormat string
+
+// 结算报表导出格式常量
+const (
+	SettlementFormatCSV  SettlementFormat = "csv"  // CSV
+	SettlementFormatXLSX SettlementFormat = "xlsx" // Excel
+)
+
+// SettlementWriter 结算报表导出函数
+type SettlementWriter func(w io.Writer, summaries []SettlementSummary) error
+
+// settlementWriters 结算报表导出函数
+var settlementWriters = map[SettlementFormat]SettlementWriter{
+	SettlementFormatCSV:  WriteSettlementCSV,
+	SettlementFormatXLSX: WriteSettlementXLSX,
+}
+
+// settlementHeader 结算报表表头
+var settlementHeader = []string{"周期", "支付渠道", "币种", "支付笔数", "支付金额(分)", "退款笔数", "退款金额(分)", "净收入(分)"}
+
+// settlementRow 结算汇总转换为一行, 数值列为 int64
+func settlementRow(s *SettlementSummary) []any {
+	return []any{
+		s.Period, string(s.PayType), s.Currency.Code(),
+		int64(s.PaymentCount), s.PaymentAmount, int64(s.RefundCount), s.RefundAmount, s.NetAmount,
+	}
+}
+
+// WriteSettlementCSV 导出 CSV, 首行为表头, 写入 UTF-8 BOM 以便 Excel 正确识别中文
+func WriteSettlementCSV(w io.Writer, summaries []SettlementSummary) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(settlementHeader); err != nil {
+		return err
+	}
+
+	record := make([]string, len(settlementHeader))
+
+	for i := range summaries {
+		for j, v := range settlementRow(&summaries[i]) {
+			record[j] = fmt.Sprint(v)
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// WriteSettlementXLSX 导出 XLSX, 单个工作表, 首行为表头
+func WriteSettlementXLSX(w io.Writer, summaries []SettlementSummary) error {
+	rows := make([][]any, 0, len(summaries)+1)
+
+	header := make([]any, len(settlementHeader))
+	for i, h := range settlementHeader {
+		header[i] = h
+	}
+
+	rows = append(rows, header)
+
+	for i := range summaries {
+		rows = append(rows, settlementRow(&summaries[i]))
+	}
+
+	return writeXLSX(w, "settlement", rows)
+}
+
+// xlsxParts XLSX 固定内容的文件
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// writeXLSX 写入只有一个工作表的 XLSX, 字符串使用内联字符串, 整数使用数值
+//   - w: 写入目标
+//   - sheet: 工作表名称
+//   - rows: 行数据, 单元格为 string 或 int64
+func writeXLSX(w io.Writer, sheet string, rows [][]any) error {
+	zw := zip.NewWriter(w)
+
+	for _, part := range xlsxParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	f, err := zw.Create("xl/workbook.xml")
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(f, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `+
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`+
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`, xmlEscape(sheet)); err != nil {
+		return err
+	}
+
+	f, err = zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	for i, row := range rows {
+		fmt.Fprintf(&sb, `<row r="%d">`, i+1)
+
+		for j, cell := range row {
+			ref := xlsxColumn(j) + strconv.Itoa(i+1)
+
+			switch v := cell.(type) {
+			case int64:
+				fmt.Fprintf(&sb, `<c r="%s"><v>%d</v></c>`, ref, v)
+			default:
+				fmt.Fprintf(&sb, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, xmlEscape(fmt.Sprint(v)))
+			}
+		}
+
+		sb.WriteString(`</row>`)
+	}
+
+	sb.WriteString(`</sheetData></worksheet>`)
+
+	if _, err := io.WriteString(f, sb.String()); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// xlsxColumn 列序号(从 0 开始)转换为列名, 例如 0 为 A, 26 为 AA
+func xlsxColumn(i int) string {
+	name := ""
+
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+
+	return name
+}
+
+// xmlEscape 转义 XML 文本
+func xmlEscape(s string) string {
+	var sb strings.Builder
+
+	_ = xml.EscapeText(&sb, []byte(s))
+
+	return sb.String()
+}
@@ -0,0 +1,224 @@
+//
+// FilePath    : go-utils\pay\settlement_writer_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 结算报表导出测试
+//
+
+package pay
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jiaopengzi/go-utils/model"
+)
+
+// testSummaries 导出测试使用的汇总数据, 支付渠道包含需要转义的字符
+var testSummaries = []SettlementSummary{
+	{Period: "2026-01-02", PayType: PayTypeAlipay, Currency: model.CurrencyCNY, PaymentCount: 2, PaymentAmount: 300, RefundCount: 1, RefundAmount: 100, NetAmount: 200},
+	{Period: "2026-01-02", PayType: PayType(`a<b>&"c"`), Currency: model.CurrencyUSD, PaymentCount: 1, PaymentAmount: 50, NetAmount: 50},
+}
+
+func TestWriteSettlementCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSettlementCSV(&buf, testSummaries); err != nil {
+		t.Fatalf("WriteSettlementCSV() error = %v", err)
+	}
+
+	data, ok := bytes.CutPrefix(buf.Bytes(), []byte("\ufeff"))
+	if !ok {
+		t.Fatal("缺少 UTF-8 BOM")
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("解析 CSV 失败: %v", err)
+	}
+
+	want := [][]string{
+		settlementHeader,
+		{"2026-01-02", "alipay", "CNY", "2", "300", "1", "100", "200"},
+		{"2026-01-02", `a<b>&"c"`, "USD", "1", "50", "0", "0", "50"},
+	}
+
+	if !slices.EqualFunc(records, want, slices.Equal) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	tests := []struct {
+		i    int
+		want string
+	}{
+		{0, "A"},
+		{7, "H"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+
+	for _, tt := range tests {
+		if got := xlsxColumn(tt.i); got != tt.want {
+			t.Errorf("xlsxColumn(%d) = %q, want %q", tt.i, got, tt.want)
+		}
+	}
+}
+
+// xlsxCell 工作表单元格
+type xlsxCell struct {
+	Ref    string `xml:"r,attr"`
+	Type   string `xml:"t,attr"`
+	Value  string `xml:"v"`
+	Inline string `xml:"is>t"`
+}
+
+// xlsxWorksheet 工作表
+type xlsxWorksheet struct {
+	Rows []struct {
+		Ref   string     `xml:"r,attr"`
+		Cells []xlsxCell `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX 解压 XLSX, 返回文件名到内容的映射
+func readXLSX(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("解压 XLSX 失败: %v", err)
+	}
+
+	parts := make(map[string]string, len(zr.File))
+
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := io.ReadAll(rc)
+		_ = rc.Close()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parts[f.Name] = string(content)
+	}
+
+	return parts
+}
+
+func TestWriteSettlementXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSettlementXLSX(&buf, testSummaries); err != nil {
+		t.Fatalf("WriteSettlementXLSX() error = %v", err)
+	}
+
+	parts := readXLSX(t, buf.Bytes())
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels", "xl/workbook.xml", "xl/worksheets/sheet1.xml"} {
+		content, ok := parts[name]
+		if !ok {
+			t.Fatalf("缺少 %s", name)
+		}
+
+		// 每个文件都是合法的 XML
+		if err := xml.Unmarshal([]byte(content), new(struct{})); err != nil {
+			t.Errorf("%s 不是合法的 XML: %v", name, err)
+		}
+	}
+
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="settlement"`) {
+		t.Errorf("workbook.xml = %s", parts["xl/workbook.xml"])
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(sheet, `a&lt;b&gt;&amp;&#34;c&#34;`) {
+		t.Errorf("支付渠道未转义: %s", sheet)
+	}
+
+	var ws xlsxWorksheet
+	if err := xml.Unmarshal([]byte(sheet), &ws); err != nil {
+		t.Fatalf("解析工作表失败: %v", err)
+	}
+
+	if len(ws.Rows) != len(testSummaries)+1 {
+		t.Fatalf("rows = %d, want %d", len(ws.Rows), len(testSummaries)+1)
+	}
+
+	header := ws.Rows[0].Cells
+	if len(header) != len(settlementHeader) || header[0].Ref != "A1" || header[7].Ref != "H1" {
+		t.Fatalf("表头单元格 = %+v", header)
+	}
+
+	for i, c := range header {
+		if c.Type != "inlineStr" || c.Inline != settlementHeader[i] {
+			t.Errorf("表头[%d] = %+v, want %s", i, c, settlementHeader[i])
+		}
+	}
+
+	want := []xlsxCell{
+		{Ref: "A3", Type: "inlineStr", Inline: "2026-01-02"},
+		{Ref: "B3", Type: "inlineStr", Inline: `a<b>&"c"`},
+		{Ref: "C3", Type: "inlineStr", Inline: "USD"},
+		{Ref: "D3", Value: "1"},
+		{Ref: "E3", Value: "50"},
+		{Ref: "F3", Value: "0"},
+		{Ref: "G3", Value: "0"},
+		{Ref: "H3", Value: "50"},
+	}
+
+	if row := ws.Rows[2]; row.Ref != "3" || !slices.Equal(row.Cells, want) {
+		t.Errorf("第 3 行 = %+v, want %+v", row, want)
+	}
+}
+
+// TestWriteXLSX_Escape 工作表名称和单元格内容转义, 超过 26 列时使用两位列名
+func TestWriteXLSX_Escape(t *testing.T) {
+	row := make([]any, 28)
+	for i := range row {
+		row[i] = int64(i)
+	}
+
+	row[27] = "<&>"
+
+	var buf bytes.Buffer
+	if err := writeXLSX(&buf, `R&D "2026"`, [][]any{row}); err != nil {
+		t.Fatalf("writeXLSX() error = %v", err)
+	}
+
+	parts := readXLSX(t, buf.Bytes())
+
+	if !strings.Contains(parts["xl/workbook.xml"], `name="R&amp;D &#34;2026&#34;"`) {
+		t.Errorf("工作表名称未转义: %s", parts["xl/workbook.xml"])
+	}
+
+	var ws xlsxWorksheet
+	if err := xml.Unmarshal([]byte(parts["xl/worksheets/sheet1.xml"]), &ws); err != nil {
+		t.Fatalf("解析工作表失败: %v", err)
+	}
+
+	cells := ws.Rows[0].Cells
+	if got := []xlsxCell{cells[25], cells[26], cells[27]}; !slices.Equal(got, []xlsxCell{
+		{Ref: "Z1", Value: "25"},
+		{Ref: "AA1", Value: "26"},
+		{Ref: "AB1", Type: "inlineStr", Inline: "<&>"},
+	}) {
+		t.Errorf("cells = %+v", got)
+	}
+}
@@ -9,12 +9,14 @@
 package pay
 
 import (
+	"bytes"
 	"context"
 	"crypto/rsa"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"regexp"
+	"strings"
 	"time"
 
 	"github.com/jiaopengzi/go-utils"
@@ -43,6 +45,16 @@ const (
 	TradeStateWechatPayPayError   = "PAYERROR"   // 支付失败（仅付款码支付会返回）
 )
 
+// 微信支付错误码常量
+// 文档: https://pay.weixin.qq.com/doc/v3/merchant/4012791862
+const (
+	wechatCodeNotEnough         = "NOT_ENOUGH"          // 余额不足
+	wechatCodeOrderNotExist     = "ORDER_NOT_EXIST"     // 订单不存在
+	wechatCodeResourceNotExists = "RESOURCE_NOT_EXISTS" // 资源不存在
+	wechatCodeSystemError       = "SYSTEM_ERROR"        // 系统错误
+	wechatCodeFrequencyLimited  = "FREQUENCY_LIMITED"   // 频率限制
+)
+
 // WeChatPayConfig 微信支付配置
 type WeChatPayConfig struct {
 	Enabled                    bool   `mapstructure:"enabled" json:"enabled"`                                                                                                     // 是否启用微信支付
@@ -62,6 +74,12 @@ type WeChatPay struct {
 	Conf        *WeChatPayConfig // 支付宝配置
 	APIPath     string           // API 路径前缀 e.g. /api/v1
 	PayBasePath string           // 支付基础路由 e.g. /pay
+
+	// PlatformPublicKeys 可选, 离线验签和诊断使用的平台公钥, 证书序列号(或公钥 ID) -> 公钥; 为空时使用已下载的平台证书
+	PlatformPublicKeys map[string]*rsa.PublicKey
+
+	// Deduper 可选, 通知去重, 已处理的通知返回 ErrDuplicateNotify, 正在处理的通知返回 ErrNotifyProcessing
+	Deduper NotificationDeduper
 }
 
 // NewWeChatPay 创建新的微信支付实例
@@ -69,7 +87,7 @@ func NewWeChatPay(conf *WeChatPayConfig, apiPath, payBasePath string) (*WeChatPa
 	// 使用 utils 提供的函数从本地文件中加载商户私钥，商户私钥会用来生成请求的签名
 	mchPrivateKey, err := wechatUtils.LoadPrivateKey(conf.MchPrivateKey)
 	if err != nil {
-		return nil, fmt.Errorf("load WeChatPay private key error: %w", err)
+		return nil, newError(PayTypeWechat, ErrInvalidConfig, "", err, "load WeChatPay private key error")
 	}
 
 	// 使用商户私钥等初始化 client，并使它具有自动定时获取微信支付平台证书的能力
@@ -85,7 +103,7 @@ func NewWeChatPay(conf *WeChatPayConfig, apiPath, payBasePath string) (*WeChatPa
 	// 创建 WeChatPay 客户端
 	client, err := core.NewClient(context.Background(), opts...)
 	if err != nil {
-		return nil, fmt.Errorf("create WeChatPay client error: %w", err)
+		return nil, newError(PayTypeWechat, ErrInvalidConfig, "", err, "create WeChatPay client error")
 	}
 
 	// 创建 WeChatPay 实例
@@ -103,8 +121,18 @@ func NewWeChatPay(conf *WeChatPayConfig, apiPath, payBasePath string) (*WeChatPa
 	return wechatPay, nil
 }
 
-// Prepay 微信支付实现 二维码的URL, 使用二维码转码工具生成二维码图片, 手机扫码支付
+// PayType 实现 Provider 接口, 返回微信支付支付类型
+func (w *WeChatPay) PayType() PayType {
+	return PayTypeWechat
+}
+
+// Prepay 兼容旧版本的支付接口, 等价于 PrepayContext(context.Background(), ...)
 func (w *WeChatPay) Prepay(orderID uint64, amount int64, description, returnURL string, timeExpire time.Time) (string, error) {
+	return w.PrepayContext(context.Background(), orderID, amount, description, returnURL, timeExpire)
+}
+
+// PrepayContext 微信支付实现 二维码的URL, 使用二维码转码工具生成二维码图片, 手机扫码支付
+func (w *WeChatPay) PrepayContext(ctx context.Context, orderID uint64, amount int64, description, returnURL string, timeExpire time.Time) (string, error) {
 	// 文档: https://github.com/wechatpay-apiv3/wechatpay-go/tree/main
 	// 支付结果通知地址
 	notifyURL := fmt.Sprintf("%s/%s%s%s",
@@ -114,7 +142,7 @@ func (w *WeChatPay) Prepay(orderID uint64, amount int64, description, returnURL
 		w.Conf.NotifyPath,
 	)
 	svc := native.NativeApiService{Client: w.Client}
-	resp, _, err := svc.Prepay(context.Background(),
+	resp, _, err := svc.Prepay(ctx,
 		native.PrepayRequest{
 			Appid:       core.String(w.Conf.AppID),
 			Mchid:       core.String(w.Conf.MchID),
@@ -130,7 +158,7 @@ func (w *WeChatPay) Prepay(orderID uint64, amount int64, description, returnURL
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("WeChatPay prepay error: %w", err)
+		return "", wechatAPIError(err, "WeChatPay prepay error")
 	}
 
 	return *resp.CodeUrl, nil
@@ -141,8 +169,8 @@ func (w *WeChatPay) GetNotifyPayment(request *http.Request) (bool, *PaymentResul
 	// 验签和解析
 	transaction, err := validateParseNotifyRequest[payments.Transaction](w, request)
 	if err != nil {
-		// 如果验签未通过，或者解密失败
-		return false, nil, fmt.Errorf("WeChatPay verify sign error: %w", err)
+		// 如果验签未通过，或者解密失败, 错误已包含分类
+		return false, nil, err
 	}
 
 	// 检查响应字段是否为 nil
@@ -154,12 +182,12 @@ func (w *WeChatPay) GetNotifyPayment(request *http.Request) (bool, *PaymentResul
 		transaction.Appid == nil ||
 		transaction.Mchid == nil ||
 		transaction.TradeState == nil {
-		return false, nil, errors.New("transaction fields are nil")
+		return false, nil, newError(PayTypeWechat, ErrInvalidNotify, "", nil, "transaction fields are nil")
 	}
 
 	// 检查交易状态是否为成功
 	if *transaction.TradeState != TradeStateWechatPaySuccess {
-		return false, nil, errors.New("trade state is not success : " + *transaction.TradeState)
+		return false, nil, newError(PayTypeWechat, ErrUnknownStatus, *transaction.TradeState, nil, "trade state is not success : %s", *transaction.TradeState)
 	}
 
 	result := &PaymentResult{
@@ -173,6 +201,11 @@ func (w *WeChatPay) GetNotifyPayment(request *http.Request) (bool, *PaymentResul
 		MchID:         *transaction.Mchid,
 	}
 
+	// 已处理的通知返回结果和 ErrDuplicateNotify, 调用方直接应答成功; 正在处理的通知返回 ErrNotifyProcessing, 调用方应答失败
+	if err = dedupeNotify(request.Context(), w.Deduper, PayTypeWechat, PaymentNotifyKey(result)); err != nil {
+		return false, result, err
+	}
+
 	return true, result, nil
 }
 
@@ -183,36 +216,40 @@ func (w *WeChatPay) GetNotifyPayment(request *http.Request) (bool, *PaymentResul
 func (w *WeChatPay) ValidateNotifyPayment(payment *PaymentResult, orderID uint64, amount int64) (bool, *PaymentResult, error) {
 	// 检查支付结果是否为 nil
 	if payment == nil {
-		return false, nil, errors.New("WeChatPay payment result is nil")
+		return false, nil, newError(PayTypeWechat, ErrInvalidNotify, "", nil, "WeChatPay payment result is nil")
 	}
 
 	// 校验订单号
 	if payment.OrderID != orderID {
-		return false, nil, fmt.Errorf("order ID mismatch: expected %d, got %d", orderID, payment.OrderID)
+		return false, nil, newError(PayTypeWechat, ErrOrderMismatch, "", nil, "order ID mismatch: expected %d, got %d", orderID, payment.OrderID)
 	}
 
 	// 校验金额
 	if payment.TotalAmount != amount {
-		return false, nil, fmt.Errorf("amount mismatch: expected %d, got %d", amount, payment.TotalAmount)
+		return false, nil, newError(PayTypeWechat, ErrAmountMismatch, "", nil, "amount mismatch: expected %d, got %d", amount, payment.TotalAmount)
 	}
 
 	// 校验商户号
 	if payment.MchID != w.Conf.MchID {
-		return false, nil, fmt.Errorf("MchID mismatch: expected %s, got %s", w.Conf.MchID, payment.MchID)
+		return false, nil, newError(PayTypeWechat, ErrMerchantMismatch, "", nil, "MchID mismatch: expected %s, got %s", w.Conf.MchID, payment.MchID)
 	}
 
 	// 校验 AppID
 	if payment.AppID != w.Conf.AppID {
-		return false, nil, fmt.Errorf("AppID mismatch: expected %s, got %s", w.Conf.AppID, payment.AppID)
+		return false, nil, newError(PayTypeWechat, ErrMerchantMismatch, "", nil, "AppID mismatch: expected %s, got %s", w.Conf.AppID, payment.AppID)
 	}
 
 	// 如果所有校验都通过，返回 true 和支付结果
 	return true, payment, nil
 }
 
-// QueryPayment 微信支付实现查询支付结果接口
+// QueryPayment 兼容旧版本的查询支付结果接口, 等价于 QueryPaymentContext(context.Background(), orderID)
 func (w *WeChatPay) QueryPayment(orderID uint64) (*PaymentResult, error) {
-	ctx := context.Background()
+	return w.QueryPaymentContext(context.Background(), orderID)
+}
+
+// QueryPaymentContext 微信支付实现查询支付结果接口
+func (w *WeChatPay) QueryPaymentContext(ctx context.Context, orderID uint64) (*PaymentResult, error) {
 	svc := native.NativeApiService{Client: w.Client}
 
 	resp, _, err := svc.QueryOrderByOutTradeNo(ctx,
@@ -223,7 +260,7 @@ func (w *WeChatPay) QueryPayment(orderID uint64) (*PaymentResult, error) {
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("WeChatPay query payment error: %w", err)
+		return nil, wechatAPIError(err, "WeChatPay query payment error")
 	}
 
 	result := &PaymentResult{
@@ -245,7 +282,7 @@ func (w *WeChatPay) QueryPayment(orderID uint64) (*PaymentResult, error) {
 		case TradeStateWechatPayClosed: // 已关闭
 			state = TradeStateClosed
 		default:
-			return nil, fmt.Errorf("WeChatPay unknown trade state: %s", *resp.TradeState)
+			return nil, newError(PayTypeWechat, ErrUnknownStatus, *resp.TradeState, nil, "WeChatPay unknown trade state: %s", *resp.TradeState)
 		}
 	}
 
@@ -268,12 +305,17 @@ func (w *WeChatPay) QueryPayment(orderID uint64) (*PaymentResult, error) {
 	return result, nil
 }
 
-// CloseOrder 微信支付实现关闭订单接口
+// CloseOrder 兼容旧版本的关闭订单接口, 等价于 CloseOrderContext(context.Background(), orderID)
 func (w *WeChatPay) CloseOrder(orderID uint64) error {
+	return w.CloseOrderContext(context.Background(), orderID)
+}
+
+// CloseOrderContext 微信支付实现关闭订单接口
+func (w *WeChatPay) CloseOrderContext(ctx context.Context, orderID uint64) error {
 	// 文档: https://github.com/wechatpay-apiv3/wechatpay-go/tree/main
 	svc := native.NativeApiService{Client: w.Client}
 
-	result, err := svc.CloseOrder(context.Background(),
+	result, err := svc.CloseOrder(ctx,
 		native.CloseOrderRequest{
 			OutTradeNo: core.String(utils.Uint64ToStr(orderID)), // 商户订单号字符串规则最小长度为6
 			Mchid:      core.String(w.Conf.MchID),
@@ -281,20 +323,25 @@ func (w *WeChatPay) CloseOrder(orderID uint64) error {
 	)
 
 	if err != nil {
-		return fmt.Errorf("WeChatPay cancel order error: %w", err)
+		return wechatAPIError(err, "WeChatPay cancel order error")
 	}
 
 	// 检查响应状态码是否为 204 No Content
 	// 文档: https://pay.weixin.qq.com/doc/v3/merchant/4012791881
 	if result.Response.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("WeChatPay cancel order failed: status code %d", result.Response.StatusCode)
+		return newError(PayTypeWechat, ErrProviderRejected, "", nil, "WeChatPay cancel order failed: status code %d", result.Response.StatusCode)
 	}
 
 	return nil
 }
 
-// Refund 微信支付实现退款接口
+// Refund 兼容旧版本的退款接口, 等价于 RefundContext(context.Background(), ...)
 func (w *WeChatPay) Refund(orderID, refundID uint64, amount, refundAmount int64, reason string) (*RefundResult, error) {
+	return w.RefundContext(context.Background(), orderID, refundID, amount, refundAmount, reason)
+}
+
+// RefundContext 微信支付实现退款接口
+func (w *WeChatPay) RefundContext(ctx context.Context, orderID, refundID uint64, amount, refundAmount int64, reason string) (*RefundResult, error) {
 	// 退款结果通知地址
 	refundURL := fmt.Sprintf("%s/%s%s%s",
 		w.Conf.NotifyHost,
@@ -304,7 +351,7 @@ func (w *WeChatPay) Refund(orderID, refundID uint64, amount, refundAmount int64,
 	)
 	svc := refunddomestic.RefundsApiService{Client: w.Client}
 
-	resp, apiResult, err := svc.Create(context.Background(),
+	resp, apiResult, err := svc.Create(ctx,
 		refunddomestic.CreateRequest{
 			OutTradeNo:  core.String(utils.Uint64ToStr(orderID)),
 			OutRefundNo: core.String(utils.Uint64ToStr(refundID)),
@@ -319,32 +366,25 @@ func (w *WeChatPay) Refund(orderID, refundID uint64, amount, refundAmount int64,
 	)
 
 	if err != nil {
-		// 如果是余额不足错误，则返回自定义错误
-		if apiResult != nil && apiResult.Response != nil && apiResult.Response.StatusCode == http.StatusForbidden {
-			matched, errR := regexp.MatchString(`NOT_ENOUGH`, err.Error())
-			if errR != nil {
-				return nil, fmt.Errorf("regexp match error: %w", errR)
-			}
-
-			// 如果匹配到余额不足错误，则返回自定义错误
-			if matched {
-				zap.L().Warn("WeChatPay refund error", zap.Error(err))
-				return nil, utils.ErrRefundWeChatNotEnough
-			}
+		// 如果是余额不足错误，则返回自定义错误, 同时保留 utils.ErrRefundWeChatNotEnough 以兼容旧的判断方式
+		if apiResult != nil && apiResult.Response != nil && apiResult.Response.StatusCode == http.StatusForbidden &&
+			strings.Contains(err.Error(), wechatCodeNotEnough) {
+			zap.L().Warn("WeChatPay refund error", zap.Error(err))
+			return nil, newError(PayTypeWechat, ErrInsufficientBalance, wechatCodeNotEnough, utils.ErrRefundWeChatNotEnough, "WeChatPay refund error: %v", err)
 		}
 
-		return nil, fmt.Errorf("WeChatPay refund error: %w", err)
+		return nil, wechatAPIError(err, "WeChatPay refund error")
 	}
 
 	// 检查响应字段是否为 nil
 	if err = checkRefundFields(resp); err != nil {
-		return nil, fmt.Errorf("WeChatPay response fields are nil: %w", err)
+		return nil, newError(PayTypeWechat, ErrProviderRejected, "", err, "WeChatPay response fields are nil")
 	}
 
 	// 对齐状态
 	state, err := parseRefundStatus(*resp.Status)
 	if err != nil {
-		return nil, fmt.Errorf("WeChatPay parse refund status error: %w", err)
+		return nil, newError(PayTypeWechat, ErrUnknownStatus, "", err, "WeChatPay parse refund status error")
 	}
 
 	// 成功发起后状态为退款中, 等待退款通知再更新状态
@@ -392,14 +432,14 @@ func (w *WeChatPay) GetNotifyRefund(request *http.Request) (bool, *RefundResult,
 	// 验签和解析
 	refund, err := validateParseNotifyRequest[RefundNotifyWechat](w, request)
 	if err != nil {
-		// 如果验签未通过，或者解密失败
-		return false, nil, fmt.Errorf("WeChatPay verify sign error: %w", err)
+		// 如果验签未通过，或者解密失败, 错误已包含分类
+		return false, nil, err
 	}
 
 	// 对齐状态
 	state, err := parseRefundStatus(refund.RefundStatus)
 	if err != nil {
-		return false, nil, fmt.Errorf("WeChatPay parse refund status error: %w", err)
+		return false, nil, newError(PayTypeWechat, ErrUnknownStatus, "", err, "WeChatPay parse refund status error")
 	}
 
 	result := &RefundResult{
@@ -413,32 +453,42 @@ func (w *WeChatPay) GetNotifyRefund(request *http.Request) (bool, *RefundResult,
 		Status:              state,                // 退款状态
 	}
 
+	// 已处理的通知返回结果和 ErrDuplicateNotify, 调用方直接应答成功; 正在处理的通知返回 ErrNotifyProcessing, 调用方应答失败
+	if err = dedupeNotify(request.Context(), w.Deduper, PayTypeWechat, RefundNotifyKey(result)); err != nil {
+		return false, result, err
+	}
+
 	return true, result, nil
 }
 
-// QueryRefund 微信支付实现查询退款结果接口
+// QueryRefund 兼容旧版本的查询退款结果接口, 等价于 QueryRefundContext(context.Background(), orderID, refundID)
 func (w *WeChatPay) QueryRefund(orderID, refundID uint64) (*RefundResult, error) {
+	return w.QueryRefundContext(context.Background(), orderID, refundID)
+}
+
+// QueryRefundContext 微信支付实现查询退款结果接口
+func (w *WeChatPay) QueryRefundContext(ctx context.Context, orderID, refundID uint64) (*RefundResult, error) {
 	svc := refunddomestic.RefundsApiService{Client: w.Client}
 
 	resp, _, err := svc.QueryByOutRefundNo(
-		context.Background(),
+		ctx,
 		refunddomestic.QueryByOutRefundNoRequest{
 			OutRefundNo: core.String(utils.Uint64ToStr(refundID)), // 商户退款单号
 		},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("WeChatPay query refund error: %w", err)
+		return nil, wechatAPIError(err, "WeChatPay query refund error")
 	}
 
 	// 检查响应字段是否为 nil
 	if err = checkRefundFields(resp); err != nil {
-		return nil, fmt.Errorf("WeChatPay response fields are nil: %w", err)
+		return nil, newError(PayTypeWechat, ErrProviderRejected, "", err, "WeChatPay response fields are nil")
 	}
 
 	// 对齐状态
 	state, err := parseRefundStatus(*resp.Status)
 	if err != nil {
-		return nil, fmt.Errorf("WeChatPay parse refund status error: %w", err)
+		return nil, newError(PayTypeWechat, ErrUnknownStatus, "", err, "WeChatPay parse refund status error")
 	}
 
 	result := &RefundResult{
@@ -458,7 +508,7 @@ func (w *WeChatPay) QueryRefund(orderID, refundID uint64) (*RefundResult, error)
 // validateParseNotifyRequest 验签和解析微信支付通知请求, 包含支付和退款通知
 func validateParseNotifyRequest[T any](w *WeChatPay, request *http.Request) (*T, error) {
 	// 文档: https://github.com/wechatpay-apiv3/wechatpay-go/tree/main
-	ctx := context.Background()
+	ctx := request.Context()
 
 	// 1. 使用 `RegisterDownloaderWithPrivateKey` 注册下载器
 	err := downloader.MgrInstance().RegisterDownloaderWithPrivateKey(
@@ -469,7 +519,7 @@ func validateParseNotifyRequest[T any](w *WeChatPay, request *http.Request) (*T,
 		w.Conf.APIv3Key,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("WeChatPay register downloader error: %w", err)
+		return nil, newError(PayTypeWechat, ErrProviderUnavailable, "", err, "WeChatPay register downloader error")
 	}
 
 	// 2. 获取商户号对应的微信支付平台证书访问器
@@ -478,13 +528,28 @@ func validateParseNotifyRequest[T any](w *WeChatPay, request *http.Request) (*T,
 	// 3. 使用证书访问器初始化 `notify.Handler`
 	handler := notify.NewNotifyHandler(w.Conf.APIv3Key, verifiers.NewSHA256WithRSAVerifier(certificateVisitor))
 
+	// 诊断模式下缓存请求体, 验签失败时用于分析原因
+	var body []byte
+
+	if notifyDebug.Load() {
+		if body, err = io.ReadAll(request.Body); err != nil {
+			return nil, newError(PayTypeWechat, ErrInvalidNotify, "", err, "WeChatPay read notify body error")
+		}
+
+		request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
 	// 4. 验签和解析通知请求
 	t := new(T)
 
 	_, err = handler.ParseNotifyRequest(ctx, request, t)
 	if err != nil {
+		if notifyDebug.Load() {
+			w.diagnoseWechatNotify(ctx, request.Header, body, err)
+		}
+
 		// 如果验签未通过，或者解密失败
-		return nil, fmt.Errorf("WeChatPay verify sign error: %w", err)
+		return nil, newError(PayTypeWechat, ErrInvalidNotify, "", err, "WeChatPay verify sign error")
 	}
 
 	return t, nil
@@ -520,3 +585,30 @@ func checkRefundFields(refund *refunddomestic.Refund) error {
 
 	return nil
 }
+
+// wechatAPIError 将微信支付 SDK 返回的错误归类为支付错误
+//   - err: SDK 返回的错误
+//   - msg: 错误描述
+func wechatAPIError(err error, msg string) *Error {
+	var apiErr *core.APIError
+	if !errors.As(err, &apiErr) {
+		// 非 API 错误, 一般为网络错误
+		return newError(PayTypeWechat, ErrProviderUnavailable, "", err, "%s", msg)
+	}
+
+	var kind ErrorKind
+
+	switch {
+	case apiErr.Code == wechatCodeNotEnough:
+		kind = ErrInsufficientBalance
+	case apiErr.Code == wechatCodeOrderNotExist || apiErr.Code == wechatCodeResourceNotExists:
+		kind = ErrOrderNotFound
+	case apiErr.Code == wechatCodeSystemError || apiErr.Code == wechatCodeFrequencyLimited ||
+		apiErr.StatusCode >= http.StatusInternalServerError:
+		kind = ErrProviderUnavailable
+	default:
+		kind = ErrProviderRejected
+	}
+
+	return newError(PayTypeWechat, kind, apiErr.Code, err, "%s", msg)
+}
@@ -0,0 +1,166 @@
+//
+// FilePath    : go-utils\pinyin.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 汉字转拼音, 用于生成搜索键和 URL slug
+//
+
+package utils
+
+import (
+	"maps"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// builtinPinyin 内置的常用汉字拼音表(不带声调), 拼音 => 汉字
+// 多音字取最常用的读音, 需要其他读音或更多汉字时使用 RegisterPinyin 注册
+var builtinPinyin = map[string]string{
+	"a": "啊阿", "ai": "爱艾哀挨矮碍", "an": "安按案暗岸", "ang": "昂", "ao": "奥傲澳",
+	"ba": "八把吧爸巴拔", "bai": "白百败拜摆", "ban": "办半版班般板伴搬", "bang": "帮邦榜棒",
+	"bao": "报保包宝暴饱抱", "bei": "被北备背倍杯悲贝", "ben": "本奔", "bi": "比必笔币避闭壁鼻",
+	"bian": "边变便遍编", "biao": "表标", "bie": "别", "bin": "宾滨", "bing": "并病兵冰饼",
+	"bo": "波博播伯", "bu": "不部步布补",
+	"cai": "才采彩菜财材猜", "can": "参餐残", "cang": "藏仓", "cao": "草操", "ce": "策册测侧",
+	"ceng": "层曾", "cha": "查茶差察", "chan": "产", "chang": "长常场厂唱", "chao": "超朝",
+	"che": "车", "chen": "陈晨沉", "cheng": "成城程称承诚", "chi": "吃持池迟", "chong": "冲充虫",
+	"chu": "出处初除楚础", "chuan": "传川船穿", "chuang": "创窗床", "chun": "春纯", "ci": "次此词",
+	"cong": "从聪", "cun": "村存", "cuo": "错",
+	"da": "大达打答", "dai": "代带待", "dan": "但单担", "dang": "当党", "dao": "到道导",
+	"de": "的得德", "deng": "等灯", "di": "地第低底帝", "dian": "点电店", "diao": "调",
+	"ding": "定订", "dong": "东动懂冬", "dou": "都斗", "du": "度读独", "duan": "段短断",
+	"dui": "对队", "duo": "多",
+	"e": "额恶", "er": "而二儿",
+	"fa": "发法", "fan": "反饭范", "fang": "方放房", "fei": "非飞费", "fen": "分份",
+	"feng": "风丰", "fu": "服复府付父",
+	"gai": "改该", "gan": "感干", "gang": "刚", "gao": "高告", "ge": "个各格歌", "gei": "给",
+	"gen": "根跟", "geng": "更", "gong": "工公共功", "gou": "够购", "gu": "古故", "gua": "挂",
+	"guan": "关管观", "guang": "光广", "gui": "规贵", "guo": "国过果",
+	"hai": "还海", "han": "汉", "hao": "好号", "he": "和合河", "hei": "黑", "hen": "很",
+	"hong": "红", "hou": "后候", "hu": "湖户", "hua": "话化花画华", "huai": "坏",
+	"huan": "欢环换", "huang": "黄", "hui": "会回", "huo": "或活火",
+	"ji": "机几记基级及技集", "jia": "家加价", "jian": "见建间件简", "jiang": "将讲",
+	"jiao": "教交", "jie": "解接结界", "jin": "进金今", "jing": "经京精", "jiu": "就九",
+	"ju": "局举", "jue": "觉决", "jun": "军",
+	"kai": "开", "kan": "看", "kao": "考", "ke": "可科客", "kong": "空", "kou": "口", "kuai": "快",
+	"la": "拉", "lai": "来", "lao": "老", "le": "了", "lei": "类", "li": "理里力利",
+	"lian": "联连", "liang": "两量", "liao": "料", "lin": "林", "ling": "领", "liu": "六流",
+	"long": "龙", "lu": "路", "lv": "绿律", "lun": "论", "luo": "落",
+	"ma": "吗妈马", "mai": "买卖", "man": "满", "mao": "毛", "mei": "没美每", "men": "们门",
+	"mi": "米", "mian": "面", "min": "民", "ming": "明名", "mo": "么", "mu": "目",
+	"na": "那", "nan": "南难", "nei": "内", "neng": "能", "ni": "你", "nian": "年", "nv": "女",
+	"pai": "排", "pin": "品", "ping": "平",
+	"qi": "其期起气", "qian": "前千", "qiang": "强", "qing": "情请清", "qiu": "求",
+	"qu": "去区", "quan": "全",
+	"ran": "然", "ren": "人认", "ri": "日", "ru": "如入",
+	"san": "三", "shang": "上商", "shao": "少", "she": "社设", "shen": "什身深", "sheng": "生",
+	"shi": "是时事实十市式", "shou": "手首", "shu": "数书", "shui": "水", "shuo": "说",
+	"si": "四思", "sou": "搜", "suo": "所索",
+	"ta": "他她它", "tai": "太", "tian": "天", "tiao": "条", "tong": "同通", "tou": "头", "tu": "图",
+	"wai": "外", "wan": "万完", "wang": "网王", "wei": "为位", "wen": "文问", "wo": "我", "wu": "无五",
+	"xi": "系西", "xia": "下", "xian": "现先", "xiang": "想向", "xiao": "小", "xie": "些",
+	"xin": "新心", "xing": "行性", "xue": "学",
+	"yan": "研言", "yang": "样", "yao": "要", "ye": "也业", "yi": "一以已意", "yin": "因",
+	"ying": "应", "yong": "用", "you": "有又", "yu": "于与语", "yuan": "元原", "yue": "月",
+	"yun": "运",
+	"zai": "在再", "zhan": "展", "zhe": "这者", "zhen": "真", "zheng": "正政", "zhi": "之只知制",
+	"zhong": "中重", "zhu": "主", "zhuan": "专", "zi": "自子字", "zong": "总", "zou": "走",
+	"zui": "最", "zuo": "作做",
+}
+
+// 汉字 => 拼音
+var (
+	pinyinDict   map[rune]string
+	pinyinDictMu sync.RWMutex
+	pinyinOnce   sync.Once
+)
+
+// initPinyinDict 将内置拼音表转换为 汉字 => 拼音 的映射
+func initPinyinDict() {
+	pinyinOnce.Do(func() {
+		dict := make(map[rune]string, len(builtinPinyin)*2)
+
+		for py, chars := range builtinPinyin {
+			for _, r := range chars {
+				dict[r] = py
+			}
+		}
+
+		pinyinDictMu.Lock()
+		defer pinyinDictMu.Unlock()
+
+		pinyinDict = dict
+	})
+}
+
+// RegisterPinyin 注册汉字拼音, 会覆盖内置拼音表中的读音
+//   - dict: 汉字 => 拼音(不带声调, 小写)
+func RegisterPinyin(dict map[rune]string) {
+	initPinyinDict()
+
+	pinyinDictMu.Lock()
+	defer pinyinDictMu.Unlock()
+
+	maps.Copy(pinyinDict, dict)
+}
+
+// lookupPinyin 查询汉字的拼音
+func lookupPinyin(r rune) (string, bool) {
+	initPinyinDict()
+
+	pinyinDictMu.RLock()
+	defer pinyinDictMu.RUnlock()
+
+	py, ok := pinyinDict[r]
+
+	return py, ok
+}
+
+// ToPinyin 将 s 中的汉字转换为拼音, 每个汉字一个元素; 非汉字的连续字母数字作为一个元素, 其他字符作为分隔符忽略.
+// 拼音表中不存在的汉字保留原字符.
+// 例如 "Go 语言2026" 转换为 ["go", "yu", "yan", "2026"]
+func ToPinyin(s string) []string {
+	s = NormalizeSearchKey(s)
+
+	var (
+		result []string
+		word   strings.Builder // 连续的字母数字
+	)
+
+	// 将已累积的字母数字作为一个元素
+	flush := func() {
+		if word.Len() > 0 {
+			result = append(result, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+
+			if py, ok := lookupPinyin(r); ok {
+				result = append(result, py)
+			} else {
+				result = append(result, string(r))
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+
+	flush()
+
+	return result
+}
+
+// PinyinSlug 生成 URL slug, 汉字转换为拼音, 使用中划线连接.
+// 例如 "Go 语言：入门" 转换为 "go-yu-yan-ru-men"
+func PinyinSlug(s string) string {
+	return strings.Join(ToPinyin(s), "-")
+}
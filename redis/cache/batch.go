@@ -0,0 +1,110 @@
+//
+// FilePath    : go-utils\redis\cache\batch.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 使用 pipeline 批量读写结构体, 一次网络往返处理多个 key
+//
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MGetWithStruct 实现 Cacher 接口 MGetWithStruct 方法, 使用 pipeline 批量获取结构体.
+// dst 为切片指针, 例如 *[]User 或 *[]*User, 会被重置为与 keys 等长, 第 i 个元素对应 keys[i];
+// 未命中的 key 对应元素为零值(指针元素为 nil), 并按 keys 的顺序返回.
+// 使用逐个 GET 而不是 MGET, 集群模式下 key 可以位于不同的槽.
+//   - keys: 缓存 key
+//   - dst: 结果切片指针
+func (c *Client) MGetWithStruct(ctx context.Context, keys []string, dst any) ([]string, error) {
+	slice := reflect.ValueOf(dst)
+	if slice.Kind() != reflect.Pointer || slice.IsNil() || slice.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("dst 必须是非 nil 的切片指针, 实际为 %T", dst)
+	}
+
+	slice = slice.Elem()
+	slice.Set(reflect.MakeSlice(slice.Type(), len(keys), len(keys)))
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	cmds := make([]*redis.StringCmd, len(keys))
+
+	_, err := c.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Get(ctx, c.Key(key))
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	var missing []string
+
+	for i, cmd := range cmds {
+		data, err := cmd.Bytes()
+		if errors.Is(err, redis.Nil) {
+			missing = append(missing, keys[i])
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("获取 %s 失败: %w", keys[i], err)
+		}
+
+		// 指针元素需要先分配, 反序列化写入分配后的值
+		elem := slice.Index(i)
+		if elem.Kind() == reflect.Pointer {
+			elem.Set(reflect.New(elem.Type().Elem()))
+		}
+
+		if err = c.codec().Unmarshal(data, elem.Addr().Interface()); err != nil {
+			return nil, fmt.Errorf("unmarshal %s: %w", keys[i], err)
+		}
+	}
+
+	return missing, nil
+}
+
+// MSetWithStruct 实现 Cacher 接口 MSetWithStruct 方法, 使用 pipeline 批量写入结构体, 所有 key 使用相同的有效期.
+// 序列化失败时不写入任何 key; pipeline 中部分命令失败时返回第一个错误, 其他 key 可能已写入.
+//   - values: 缓存 key -> 值
+//   - duration: 有效期, 为 0 时永不过期
+func (c *Client) MSetWithStruct(ctx context.Context, values map[string]any, duration time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	// 先全部序列化, 避免写入一半后因序列化失败中止
+	encoded := make(map[string]string, len(values))
+
+	for key, value := range values {
+		valueJSON, err := c.codec().Marshal(value)
+		if err != nil {
+			return fmt.Errorf("marshal %s: %w", key, err)
+		}
+
+		encoded[c.Key(key)] = string(valueJSON)
+	}
+
+	_, err := c.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, valueJSON := range encoded {
+			pipe.Set(ctx, key, valueJSON, duration)
+		}
+
+		return nil
+	})
+
+	return err
+}
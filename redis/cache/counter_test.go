@@ -0,0 +1,107 @@
+//
+// FilePath    : go-utils\redis\cache\counter_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 计数器并发性能对比, lua 脚本与 WATCH 事务
+//
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// benchRedisAddrEnv 基准测试和依赖 redis 的测试使用的 redis 地址, 未设置时跳过
+const benchRedisAddrEnv = "CACHE_BENCH_REDIS_ADDR"
+
+// benchClient 创建基准测试和依赖 redis 的测试使用的缓存客户端
+func benchClient(b testing.TB) *Client {
+	b.Helper()
+
+	addr := os.Getenv(benchRedisAddrEnv)
+	if addr == "" {
+		b.Skipf("未设置环境变量 %s, 跳过", benchRedisAddrEnv)
+	}
+
+	rdb := redis.NewClient(&redis.Options{Addr: addr, PoolSize: 64})
+	b.Cleanup(func() { _ = rdb.Close() })
+
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		b.Skipf("无法连接 redis %s: %v", addr, err)
+	}
+
+	return NewClient(rdb)
+}
+
+// watchIncrement 原 WATCH/MULTI 实现的计数器递增, 作为对比基准
+func watchIncrement(ctx context.Context, c *Client, key string, duration time.Duration) error {
+	return c.WatchKeys(ctx, []string{key}, func(tx *redis.Tx) error {
+		if err := tx.Get(ctx, key).Err(); err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+
+		ttl, err := c.GetKeyTll(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Incr(ctx, key)
+
+			if ttl <= 0 {
+				pipe.Expire(ctx, key, duration)
+			}
+
+			return nil
+		})
+
+		return err
+	})
+}
+
+// BenchmarkIncrementCounter 同一个 key 高并发递增: WATCH 事务冲突后重试, 超过重试次数即失败, lua 脚本没有冲突
+func BenchmarkIncrementCounter(b *testing.B) {
+	c := benchClient(b)
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		incr func(key string) error
+	}{
+		{"watch", func(key string) error { return watchIncrement(ctx, c, key, time.Minute) }},
+		{"lua", func(key string) error {
+			_, err := c.IncrementByCounter(ctx, key, 1, time.Minute, false)
+			return err
+		}},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			key := GenerateKey("bench_counter", tc.name)
+			b.Cleanup(func() { _ = c.Del(ctx, key) })
+
+			var failures atomic.Int64
+
+			b.SetParallelism(8)
+			b.ResetTimer()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if err := tc.incr(key); err != nil {
+						failures.Add(1)
+					}
+				}
+			})
+
+			b.ReportMetric(float64(failures.Load())/float64(b.N), "failures/op")
+		})
+	}
+}
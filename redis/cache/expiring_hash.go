@@ -0,0 +1,322 @@
+//
+// FilePath    : go-utils\redis\cache\expiring_hash.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 字段级过期的 hash, 使用记录过期时间的 zset 模拟 redis hash 不支持的字段过期, 用于设备在线状态、限流桶等场景
+//
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// luaReapExpired 清理过期时间不晚于 now 的字段, 返回清理数量; 每批 500 个, 避免 unpack 参数过多
+const luaReapExpired = `
+local function reap(now)
+	local count = 0
+	while true do
+		local expired = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', now, 'LIMIT', 0, 500)
+		if #expired == 0 then
+			return count
+		end
+		redis.call('HDEL', KEYS[1], unpack(expired))
+		redis.call('ZREM', KEYS[2], unpack(expired))
+		count = count + #expired
+	end
+end
+`
+
+// luaRefreshKeyExpire 所有字段都有过期时间时, hash 和 zset 在最晚的过期时间整体过期, 否则永久保留
+const luaRefreshKeyExpire = `
+local function refresh()
+	if redis.call('ZCARD', KEYS[2]) == redis.call('HLEN', KEYS[1]) then
+		local last = redis.call('ZRANGE', KEYS[2], -1, -1, 'WITHSCORES')
+		if #last > 0 then
+			redis.call('PEXPIREAT', KEYS[1], last[2])
+			redis.call('PEXPIREAT', KEYS[2], last[2])
+		end
+	else
+		redis.call('PERSIST', KEYS[1])
+		redis.call('PERSIST', KEYS[2])
+	end
+end
+`
+
+// luaDropIfExpired 字段在 now 时已过期则删除并返回 true
+const luaDropIfExpired = `
+local function dropIfExpired(field, now)
+	local deadline = redis.call('ZSCORE', KEYS[2], field)
+	if deadline and tonumber(deadline) <= tonumber(now) then
+		redis.call('HDEL', KEYS[1], field)
+		redis.call('ZREM', KEYS[2], field)
+		return true
+	end
+	return false
+end
+`
+
+// luaSetDeadline 设置字段的过期时间, deadline 为 0 表示永不过期
+const luaSetDeadline = `
+local function setDeadline(field, deadline)
+	if tonumber(deadline) > 0 then
+		redis.call('ZADD', KEYS[2], deadline, field)
+	else
+		redis.call('ZREM', KEYS[2], field)
+	end
+end
+`
+
+// ExpiringHash 使用的 lua 脚本, KEYS[1] 为 hash, KEYS[2] 为记录过期时间(毫秒时间戳)的 zset
+var (
+	// ARGV: field, value, deadline
+	expiringHashSet = redis.NewScript(luaRefreshKeyExpire + luaSetDeadline + `
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+setDeadline(ARGV[1], ARGV[3])
+refresh()
+return 1
+`)
+
+	// ARGV: field, now
+	expiringHashGet = redis.NewScript(luaDropIfExpired + `
+if dropIfExpired(ARGV[1], ARGV[2]) then
+	return false
+end
+return redis.call('HGET', KEYS[1], ARGV[1])
+`)
+
+	// ARGV: now
+	expiringHashGetAll = redis.NewScript(luaReapExpired + `
+reap(ARGV[1])
+return redis.call('HGETALL', KEYS[1])
+`)
+
+	// ARGV: now
+	expiringHashLen = redis.NewScript(luaReapExpired + `
+reap(ARGV[1])
+return redis.call('HLEN', KEYS[1])
+`)
+
+	// ARGV: now
+	expiringHashReap = redis.NewScript(luaReapExpired + luaRefreshKeyExpire + `
+local count = reap(ARGV[1])
+refresh()
+return count
+`)
+
+	// ARGV: field, delta, deadline, now
+	expiringHashIncrBy = redis.NewScript(luaRefreshKeyExpire + luaDropIfExpired + luaSetDeadline + `
+dropIfExpired(ARGV[1], ARGV[4])
+local existed = redis.call('HEXISTS', KEYS[1], ARGV[1])
+local value = redis.call('HINCRBY', KEYS[1], ARGV[1], ARGV[2])
+if existed == 0 then
+	setDeadline(ARGV[1], ARGV[3])
+	refresh()
+end
+return value
+`)
+
+	// ARGV: field, deadline, now
+	expiringHashExpire = redis.NewScript(luaRefreshKeyExpire + luaDropIfExpired + luaSetDeadline + `
+dropIfExpired(ARGV[1], ARGV[3])
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+setDeadline(ARGV[1], ARGV[2])
+refresh()
+return 1
+`)
+
+	// ARGV: fields...
+	expiringHashDel = redis.NewScript(luaRefreshKeyExpire + `
+local count = redis.call('HDEL', KEYS[1], unpack(ARGV))
+redis.call('ZREM', KEYS[2], unpack(ARGV))
+refresh()
+return count
+`)
+)
+
+// ExpiringHash 字段级过期的 hash. redis hash 不支持字段过期, 使用 zset 记录每个字段的过期时间:
+// 读取时惰性删除过期字段, 后台清理任务(StartReaper)定期清理未被读取的过期字段;
+// 所有字段都有过期时间时 hash 在最晚的过期时间整体过期, 不会残留空 key.
+// 过期时间使用本机时间计算, 多实例部署时需要保证时钟同步.
+// redis 集群模式下 hash 和 zset 需要位于同一个槽, key 需要包含 hash tag, 例如 "{presence}:user:1".
+type ExpiringHash struct {
+	client      *Client
+	key         string           // hash 的 key
+	deadlineKey string           // 记录字段过期时间的 zset 的 key
+	now         func() time.Time // 当前时间, 便于测试
+}
+
+// NewExpiringHash 创建字段级过期的 hash
+//   - c: 缓存客户端
+//   - key: hash 的 key, 一般通过 GenerateKey 生成, 记录过期时间的 zset 为 key + Delimiter + "deadline"
+func NewExpiringHash(c *Client, key string) *ExpiringHash {
+	fullKey := c.Key(key)
+
+	return &ExpiringHash{
+		client:      c,
+		key:         fullKey,
+		deadlineKey: fullKey + Delimiter + "deadline",
+		now:         time.Now,
+	}
+}
+
+// Key 获取 hash 在 redis 中的完整 key(包含命名空间)
+func (h *ExpiringHash) Key() string {
+	return h.key
+}
+
+// Set 设置字段的值和有效期, 覆盖原有的值和有效期
+//   - field: 字段
+//   - value: 值
+//   - ttl: 有效期, 为 0 表示永不过期
+func (h *ExpiringHash) Set(ctx context.Context, field string, value any, ttl time.Duration) error {
+	return expiringHashSet.Run(ctx, h.client.Client, h.keys(), field, value, h.deadline(ttl)).Err()
+}
+
+// Get 获取字段的值, 字段不存在或已过期时返回 redis.Nil
+//   - field: 字段
+func (h *ExpiringHash) Get(ctx context.Context, field string) (string, error) {
+	return expiringHashGet.Run(ctx, h.client.Client, h.keys(), field, h.nowMilli()).Text()
+}
+
+// GetAll 获取所有未过期的字段和值
+func (h *ExpiringHash) GetAll(ctx context.Context) (map[string]string, error) {
+	values, err := expiringHashGetAll.Run(ctx, h.client.Client, h.keys(), h.nowMilli()).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		result[values[i]] = values[i+1]
+	}
+
+	return result, nil
+}
+
+// Len 获取未过期的字段数量
+func (h *ExpiringHash) Len(ctx context.Context) (int64, error) {
+	return expiringHashLen.Run(ctx, h.client.Client, h.keys(), h.nowMilli()).Int64()
+}
+
+// IncrBy 字段的值增加 delta 并返回增加后的值, 字段不存在或已过期时从 0 开始并设置有效期, 已存在时不修改有效期.
+// 适用于固定窗口的限流桶: 窗口内第一次计数时开始计时, 窗口结束后字段过期重新计数.
+//   - field: 字段
+//   - delta: 增量, 可以为负数
+//   - ttl: 字段新建时的有效期, 为 0 表示永不过期
+func (h *ExpiringHash) IncrBy(ctx context.Context, field string, delta int64, ttl time.Duration) (int64, error) {
+	return expiringHashIncrBy.Run(ctx, h.client.Client, h.keys(), field, delta, h.deadline(ttl), h.nowMilli()).Int64()
+}
+
+// Expire 修改字段的有效期, 例如设备心跳时续期, 字段不存在或已过期时返回 false
+//   - field: 字段
+//   - ttl: 新的有效期, 为 0 表示永不过期
+func (h *ExpiringHash) Expire(ctx context.Context, field string, ttl time.Duration) (bool, error) {
+	n, err := expiringHashExpire.Run(ctx, h.client.Client, h.keys(), field, h.deadline(ttl), h.nowMilli()).Int64()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+// TTL 获取字段的剩余有效期, 与 redis TTL 命令一致: 字段不存在或已过期时返回 -2, 永不过期时返回 -1
+//   - field: 字段
+func (h *ExpiringHash) TTL(ctx context.Context, field string) (time.Duration, error) {
+	pipe := h.client.Client.Pipeline()
+	exists := pipe.HExists(ctx, h.key, field)
+	score := pipe.ZScore(ctx, h.deadlineKey, field)
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+
+	if !exists.Val() {
+		return -2, nil
+	}
+
+	if errors.Is(score.Err(), redis.Nil) {
+		return -1, nil
+	}
+
+	remaining := time.UnixMilli(int64(score.Val())).Sub(h.now())
+	if remaining <= 0 {
+		return -2, nil
+	}
+
+	return remaining, nil
+}
+
+// Del 删除字段, 返回实际删除的数量
+//   - fields: 字段
+func (h *ExpiringHash) Del(ctx context.Context, fields ...string) (int64, error) {
+	if len(fields) == 0 {
+		return 0, nil
+	}
+
+	args := make([]any, len(fields))
+	for i, f := range fields {
+		args[i] = f
+	}
+
+	return expiringHashDel.Run(ctx, h.client.Client, h.keys(), args...).Int64()
+}
+
+// Clear 删除整个 hash
+func (h *ExpiringHash) Clear(ctx context.Context) error {
+	return h.client.Client.Del(ctx, h.key, h.deadlineKey).Err()
+}
+
+// Reap 清理所有过期的字段, 返回清理的数量
+func (h *ExpiringHash) Reap(ctx context.Context) (int64, error) {
+	return expiringHashReap.Run(ctx, h.client.Client, h.keys(), h.nowMilli()).Int64()
+}
+
+// StartReaper 启动后台清理任务, 每隔 interval 清理一次过期字段, ctx 取消时退出.
+// 字段读取时会惰性删除, 清理任务用于及时释放不再读取的字段, 多实例同时清理是安全的.
+//   - interval: 清理间隔
+func (h *ExpiringHash) StartReaper(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := h.Reap(ctx); err != nil && ctx.Err() == nil {
+					zap.L().Warn("清理过期的 hash 字段失败", zap.String("key", h.key), zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// keys 获取 lua 脚本的 KEYS
+func (h *ExpiringHash) keys() []string {
+	return []string{h.key, h.deadlineKey}
+}
+
+// nowMilli 当前毫秒时间戳
+func (h *ExpiringHash) nowMilli() int64 {
+	return h.now().UnixMilli()
+}
+
+// deadline 计算过期时间的毫秒时间戳, ttl 不大于 0 时返回 0 表示永不过期
+func (h *ExpiringHash) deadline(ttl time.Duration) string {
+	if ttl <= 0 {
+		return "0"
+	}
+
+	return strconv.FormatInt(h.now().Add(ttl).UnixMilli(), 10)
+}
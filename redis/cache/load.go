@@ -0,0 +1,179 @@
+//
+// FilePath    : go-utils\redis\cache\load.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 旁路缓存加载, 同一个 key 并发未命中时只调用一次加载函数, 防止缓存击穿
+//
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// loadCall 正在进行的加载
+type loadCall struct {
+	done chan struct{} // 加载完成后关闭
+	val  any           // 加载结果
+	err  error         // 加载错误
+}
+
+// loadKey 加载合并的键, 包含命名空间的完整 key 和结果类型, 不同命名空间或不同类型的加载互不合并
+type loadKey struct {
+	key string       // 完整 key
+	typ reflect.Type // 结果类型
+}
+
+// loadGroup 按 loadKey 合并并发的加载, 同一时刻同一个 loadKey 只有一个加载在执行
+type loadGroup struct {
+	mu    sync.Mutex
+	calls map[loadKey]*loadCall
+}
+
+// loads 本进程内所有 GetOrLoad 共享的加载合并
+var loads loadGroup
+
+// do 执行 key 对应的加载, 已有加载在执行时等待其结果; ctx 结束时停止等待, 不影响正在执行的加载
+func (g *loadGroup) do(ctx context.Context, key loadKey, fn func() (any, error)) (val any, err error) {
+	g.mu.Lock()
+
+	if g.calls == nil {
+		g.calls = make(map[loadKey]*loadCall)
+	}
+
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+
+		select {
+		case <-call.done:
+			return call.val, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	call := &loadCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		// 加载函数 panic 时以错误返回给所有等待者, 避免等待者永远阻塞
+		if r := recover(); r != nil {
+			call.val, call.err = nil, fmt.Errorf("加载缓存数据 panic: key=%s; %v", key.key, r)
+			val, err = call.val, call.err
+		}
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+
+		close(call.done)
+	}()
+
+	call.val, call.err = fn()
+
+	return call.val, call.err
+}
+
+// loadConfig GetOrLoad 配置
+type loadConfig struct {
+	jitter time.Duration // 有效期随机增加的最大时长
+}
+
+// LoadOption 定义 GetOrLoad 的可选配置函数类型
+type LoadOption func(*loadConfig)
+
+// WithLoadJitter 设置有效期随机增加的最大时长, 实际有效期为 ttl + [0, jitter), 避免同一批 key 同时过期导致缓存雪崩
+func WithLoadJitter(jitter time.Duration) LoadOption {
+	return func(c *loadConfig) {
+		c.jitter = jitter
+	}
+}
+
+// GetOrLoad 旁路缓存读取 key 对应的 JSON 结构体, 未命中时调用 loader 加载并写入缓存.
+// 本进程内同一个完整 key(包含命名空间)和同一个 T 并发未命中时只调用一次 loader, 其他调用等待并共享结果, 防止缓存击穿.
+// 合并的加载使用第一个调用方的 ctx 读写缓存, 其他调用方的 ctx 结束时只停止等待, 不影响正在执行的加载.
+// loader 返回错误时不写入缓存, 所有等待的调用方都返回该错误; 读写缓存失败只记录日志, 仍返回 loader 的结果, 缓存不可用时不影响业务.
+//   - c: 缓存客户端
+//   - key: 缓存 key
+//   - ttl: 有效期, 为 0 时永不过期
+//   - loader: 加载函数, 例如查询数据库
+//   - opts: 可选配置, 例如 WithLoadJitter
+func GetOrLoad[T any](ctx context.Context, c *Client, key string, ttl time.Duration, loader func() (T, error), opts ...LoadOption) (T, error) {
+	var value T
+
+	hit, err := getCached(ctx, c, key, &value)
+	if hit {
+		return value, nil
+	}
+
+	if err != nil {
+		zap.L().Warn("读取缓存失败, 直接加载", zap.String("key", key), zap.Error(err))
+	}
+
+	cfg := &loadConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	result, err := loads.do(ctx, loadKey{key: c.Key(key), typ: reflect.TypeFor[T]()}, func() (any, error) {
+		// 上一次加载可能刚写入缓存
+		var cached T
+		if hit, _ := getCached(ctx, c, key, &cached); hit {
+			return cached, nil
+		}
+
+		loaded, err := loader()
+		if err != nil {
+			return loaded, err
+		}
+
+		expiration := ttl
+		if expiration > 0 && cfg.jitter > 0 {
+			expiration += rand.N(cfg.jitter)
+		}
+
+		if err = c.SetStringWithStruct(ctx, key, loaded, expiration); err != nil {
+			zap.L().Warn("写入缓存失败", zap.String("key", key), zap.Error(err))
+		}
+
+		return loaded, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	// T 为接口类型且结果为 nil 时保持零值
+	value, ok := result.(T)
+	if !ok && result != nil {
+		var zero T
+		return zero, fmt.Errorf("加载缓存数据类型不匹配: key=%s; got %T, want %s", key, result, reflect.TypeFor[T]())
+	}
+
+	return value, nil
+}
+
+// getCached 读取缓存, 返回是否命中; 未命中(redis.Nil)时不返回错误
+func getCached[T any](ctx context.Context, c *Client, key string, value *T) (bool, error) {
+	err := c.GetStringWithStruct(ctx, key, value)
+	if err == nil {
+		return true, nil
+	}
+
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+
+	return false, err
+}
@@ -0,0 +1,188 @@
+//
+// FilePath    : go-utils\redis\cache\load_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 旁路缓存加载测试, 使用无法连接的 redis, 读写缓存失败时直接调用加载函数
+//
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// errOffline 模拟 redis 不可用
+var errOffline = errors.New("redis offline")
+
+// offlineHook 所有命令直接返回 errOffline, 不连接 redis
+type offlineHook struct{}
+
+// DialHook 实现 redis.Hook 接口
+func (offlineHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook 实现 redis.Hook 接口
+func (offlineHook) ProcessHook(redis.ProcessHook) redis.ProcessHook {
+	return func(_ context.Context, cmd redis.Cmder) error {
+		cmd.SetErr(errOffline)
+		return errOffline
+	}
+}
+
+// ProcessPipelineHook 实现 redis.Hook 接口
+func (offlineHook) ProcessPipelineHook(redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(context.Context, []redis.Cmder) error {
+		return errOffline
+	}
+}
+
+// offlineClient 创建 redis 不可用的缓存客户端, 每次读取都失败
+func offlineClient(t *testing.T, opts ...ClientOption) *Client {
+	t.Helper()
+
+	rdb := redis.NewClient(&redis.Options{})
+	rdb.AddHook(offlineHook{})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	return NewClientWithOptions(rdb, opts...)
+}
+
+// waitAll 通知 entered 后等待所有加载函数都已进入, 超时返回 false
+func waitAll(entered *sync.WaitGroup) bool {
+	entered.Done()
+
+	done := make(chan struct{})
+	go func() {
+		entered.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(500 * time.Millisecond):
+		return false
+	}
+}
+
+// TestGetOrLoad_Coalesce 同一个 key 并发未命中时只调用一次加载函数, 所有调用方共享结果
+func TestGetOrLoad_Coalesce(t *testing.T) {
+	c := offlineClient(t)
+	ctx := context.Background()
+
+	var (
+		calls   atomic.Int32
+		started sync.WaitGroup
+		wg      sync.WaitGroup
+	)
+
+	release := make(chan struct{})
+	loader := func() (int, error) {
+		calls.Add(1)
+		<-release
+
+		return 42, nil
+	}
+
+	const callers = 8
+
+	started.Add(callers)
+
+	for range callers {
+		wg.Go(func() {
+			started.Done()
+
+			if got, err := GetOrLoad(ctx, c, "coalesce", time.Minute, loader); err != nil || got != 42 {
+				t.Errorf("GetOrLoad() = %d, %v, want 42", got, err)
+			}
+		})
+	}
+
+	started.Wait()
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("loader calls = %d, want 1", got)
+	}
+}
+
+// TestGetOrLoad_Isolation 不同命名空间或不同结果类型的相同 key 不合并加载, 各自调用加载函数
+func TestGetOrLoad_Isolation(t *testing.T) {
+	ctx := context.Background()
+	tenantA := offlineClient(t, WithNamespace("tenant_a"))
+	tenantB := offlineClient(t, WithNamespace("tenant_b"))
+
+	// 三个加载函数都进入后才返回, 被合并时等待超时
+	var entered, wg sync.WaitGroup
+
+	entered.Add(3)
+
+	barrier := func() error {
+		if !waitAll(&entered) {
+			return errors.New("加载被合并")
+		}
+
+		return nil
+	}
+
+	wg.Go(func() {
+		got, err := GetOrLoad(ctx, tenantA, "profile", time.Minute, func() (string, error) { return "a", barrier() })
+		if err != nil || got != "a" {
+			t.Errorf("tenant_a GetOrLoad() = %q, %v, want a", got, err)
+		}
+	})
+
+	wg.Go(func() {
+		got, err := GetOrLoad(ctx, tenantB, "profile", time.Minute, func() (string, error) { return "b", barrier() })
+		if err != nil || got != "b" {
+			t.Errorf("tenant_b GetOrLoad() = %q, %v, want b", got, err)
+		}
+	})
+
+	wg.Go(func() {
+		got, err := GetOrLoad(ctx, tenantA, "profile", time.Minute, func() (int, error) { return 7, barrier() })
+		if err != nil || got != 7 {
+			t.Errorf("tenant_a int GetOrLoad() = %d, %v, want 7", got, err)
+		}
+	})
+
+	wg.Wait()
+}
+
+// TestGetOrLoad_Error 加载函数返回错误或 panic 时所有调用方返回错误和零值, 之后的调用重新加载
+func TestGetOrLoad_Error(t *testing.T) {
+	c := offlineClient(t)
+	ctx := context.Background()
+	errLoad := errors.New("db down")
+
+	got, err := GetOrLoad(ctx, c, "error", time.Minute, func() (*int, error) {
+		v := 1
+		return &v, errLoad
+	})
+	if !errors.Is(err, errLoad) || got != nil {
+		t.Fatalf("GetOrLoad() = %v, %v, want nil, errLoad", got, err)
+	}
+
+	if _, err = GetOrLoad(ctx, c, "error", time.Minute, func() (*int, error) { panic("boom") }); err == nil {
+		t.Fatal("loader panic GetOrLoad() error = nil")
+	}
+
+	value, err := GetOrLoad(ctx, c, "error", time.Minute, func() (*int, error) {
+		v := 2
+		return &v, nil
+	})
+	if err != nil || value == nil || *value != 2 {
+		t.Errorf("GetOrLoad() after error = %v, %v, want 2", value, err)
+	}
+}
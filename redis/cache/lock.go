@@ -0,0 +1,285 @@
+//
+// FilePath    : go-utils\redis\cache\lock.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 基于 SET NX 的分布式锁, 持有者令牌防误删, 单调递增的 fencing token, 持有期间自动续期
+//
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// 分布式锁相关的错误
+var (
+	ErrLockNotAcquired = errors.New("锁已被其他持有者占用")    // TryLock 未获取到锁
+	ErrLockNotHeld     = errors.New("锁已过期或被其他持有者获取") // 释放或续期时锁已不属于当前持有者
+	ErrLockRenewFailed = errors.New("分布式锁续期失败")      // AutoRenew 续期失败, 作为 context.Cause 返回
+)
+
+// 分布式锁的默认参数
+const (
+	defaultLockTTL           = 30 * time.Second       // 默认锁有效期
+	minLockTTL               = time.Millisecond       // 最小锁有效期, redis PX 的精度为毫秒
+	defaultLockRetryInterval = 100 * time.Millisecond // Lock 默认重试间隔
+)
+
+// lockRetryInterval Lock 获取锁失败时的重试间隔
+var lockRetryInterval = defaultLockRetryInterval
+
+// SetLockRetryInterval 设置 Lock 获取锁失败时的重试间隔, 不设置则默认 100 毫秒
+func SetLockRetryInterval(interval time.Duration) {
+	lockRetryInterval = interval
+}
+
+var (
+	// KEYS: 锁, fencing 计数器; ARGV: token, ttl(毫秒)
+	// 获取成功返回递增后的 fencing token, 锁已被占用返回 0
+	lockAcquire = redis.NewScript(`
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return redis.call('INCR', KEYS[2])
+end
+return 0
+`)
+
+	// KEYS: 锁; ARGV: token
+	// 只删除自己持有的锁
+	lockRelease = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+	// KEYS: 锁; ARGV: token, ttl(毫秒)
+	// 只续期自己持有的锁
+	lockRefresh = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+)
+
+// Locker 分布式锁接口
+type Locker interface {
+	TryLock(ctx context.Context, key string, ttl time.Duration) (*Lock, error)                             // 尝试获取锁, 已被占用时返回 ErrLockNotAcquired
+	Lock(ctx context.Context, key string, ttl time.Duration) (*Lock, error)                                // 获取锁, 已被占用时重试直到 ctx 结束
+	WithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) error // 持有锁执行 fn, 期间自动续期, 结束后释放
+}
+
+// 确保 Client 实现了 Locker 接口
+var _ Locker = (*Client)(nil)
+
+// Lock 已获取的分布式锁
+type Lock struct {
+	client   *Client
+	key      string        // 锁的 key
+	fenceKey string        // fencing 计数器的 key
+	token    string        // 持有者令牌
+	fence    int64         // fencing token
+	ttl      time.Duration // 有效期
+}
+
+// Key 获取锁在 redis 中的完整 key(包含命名空间)
+func (l *Lock) Key() string {
+	return l.key
+}
+
+// Token 获取持有者令牌, 释放和续期时用于确认锁仍属于当前持有者
+func (l *Lock) Token() string {
+	return l.token
+}
+
+// Fence 获取 fencing token, 同一个 key 每次获取锁时单调递增.
+// 写入下游存储时携带该值, 存储拒绝小于已见最大值的写入, 可以防止锁过期后旧持有者的迟到写入.
+func (l *Lock) Fence() int64 {
+	return l.fence
+}
+
+// TTL 获取锁的有效期
+func (l *Lock) TTL() time.Duration {
+	return l.ttl
+}
+
+// Refresh 将锁的有效期重置为 TTL, 锁已不属于当前持有者时返回 ErrLockNotHeld
+func (l *Lock) Refresh(ctx context.Context) error {
+	ok, err := lockRefresh.Run(ctx, l.client.Client, []string{l.key}, l.token, l.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+
+	if ok == 0 {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
+
+// Unlock 释放锁, 只删除自己持有的锁; 锁已过期或被其他持有者获取时返回 ErrLockNotHeld
+func (l *Lock) Unlock(ctx context.Context) error {
+	deleted, err := lockRelease.Run(ctx, l.client.Client, []string{l.key}, l.token).Int64()
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
+
+// AutoRenew 启动后台续期, 按有效期的 1/3 续期, 返回的 ctx 在 cancel 调用、父 ctx 结束或续期失败时取消.
+// 续期失败说明锁可能已丢失, 此时 context.Cause(ctx) 包装了 ErrLockRenewFailed 和续期错误, 持有者应尽快停止受保护的操作.
+//   - ctx: 父上下文
+func (l *Lock) AutoRenew(ctx context.Context) (context.Context, context.CancelFunc) {
+	renewCtx, cancel := context.WithCancelCause(ctx)
+
+	go func() {
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-ticker.C:
+				if err := l.Refresh(renewCtx); err != nil {
+					// 持有者已结束导致的续期失败不需要处理
+					if renewCtx.Err() != nil {
+						return
+					}
+
+					zap.L().Error("分布式锁续期失败", zap.String("key", l.key), zap.Error(err))
+					cancel(fmt.Errorf("%w: %w", ErrLockRenewFailed, err))
+
+					return
+				}
+			}
+		}
+	}()
+
+	return renewCtx, func() { cancel(context.Canceled) }
+}
+
+// newLock 创建锁对象, ttl 为 0 时使用默认有效期, 小于 1 毫秒时按 1 毫秒处理
+func (c *Client) newLock(key string, ttl time.Duration) *Lock {
+	if ttl <= 0 {
+		ttl = defaultLockTTL
+	}
+
+	ttl = max(ttl, minLockTTL)
+
+	return &Lock{
+		client:   c,
+		key:      c.Key(key),
+		fenceKey: c.Key(key) + Delimiter + "fence",
+		token:    uuid.NewString(),
+		ttl:      ttl,
+	}
+}
+
+// acquire 执行一次获取锁, 返回是否获取成功
+func (l *Lock) acquire(ctx context.Context) (bool, error) {
+	fence, err := lockAcquire.Run(ctx, l.client.Client, []string{l.key, l.fenceKey}, l.token, l.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+
+	l.fence = fence
+
+	return fence > 0, nil
+}
+
+// TryLock 实现 Locker 接口 TryLock 方法, 尝试获取一次锁, 已被占用时返回 ErrLockNotAcquired.
+// fencing 计数器的 key 为 key + Delimiter + "fence", 永久保留;
+// redis 集群模式下两者需要位于同一个槽, key 需要包含 hash tag, 例如 "{order:1}:lock".
+//   - key: 锁的 key, 一般通过 GenerateKey 生成
+//   - ttl: 锁的有效期, 为 0 时默认 30 秒, 最小 1 毫秒
+func (c *Client) TryLock(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	l := c.newLock(key, ttl)
+
+	ok, err := l.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, ErrLockNotAcquired
+	}
+
+	return l, nil
+}
+
+// Lock 实现 Locker 接口 Lock 方法, 获取锁, 已被占用时按 SetLockRetryInterval 设置的间隔重试, 直到获取成功或 ctx 结束
+//   - key: 锁的 key, 一般通过 GenerateKey 生成
+//   - ttl: 锁的有效期, 为 0 时默认 30 秒, 最小 1 毫秒
+func (c *Client) Lock(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	l := c.newLock(key, ttl)
+
+	ticker := time.NewTicker(lockRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := l.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			return l, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", ErrLockNotAcquired, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// WithLock 实现 Locker 接口 WithLock 方法, 获取锁后执行 fn, 执行期间自动续期, 结束后释放锁.
+// 续期失败时取消传给 fn 的 ctx 并返回 ErrLockNotHeld; 释放锁使用不随 ctx 取消的上下文, 保证锁被及时释放.
+//   - key: 锁的 key, 一般通过 GenerateKey 生成
+//   - ttl: 锁的有效期, 为 0 时默认 30 秒, 最小 1 毫秒
+//   - fn: 持有锁期间执行的函数
+func (c *Client) WithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	l, err := c.Lock(ctx, key, ttl)
+	if err != nil {
+		return err
+	}
+
+	renewCtx, stop := l.AutoRenew(ctx)
+	err = fn(renewCtx)
+
+	// 只处理续期失败导致的取消, 父 ctx 的取消原因原样由 fn 返回
+	lost := context.Cause(renewCtx)
+	if !errors.Is(lost, ErrLockRenewFailed) {
+		lost = nil
+	}
+
+	stop()
+
+	unlockErr := l.Unlock(context.WithoutCancel(ctx))
+
+	switch {
+	case lost != nil:
+		return errors.Join(err, fmt.Errorf("%w: %w", ErrLockNotHeld, lost))
+	case errors.Is(unlockErr, ErrLockNotHeld):
+		return errors.Join(err, unlockErr)
+	case unlockErr != nil:
+		zap.L().Warn("释放分布式锁失败, 等待有效期结束自动释放", zap.String("key", key), zap.Error(unlockErr))
+	}
+
+	return err
+}
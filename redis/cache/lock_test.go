@@ -0,0 +1,251 @@
+//
+// FilePath    : go-utils\redis\cache\lock_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 分布式锁测试, 除有效期和续期失败外需要设置 CACHE_BENCH_REDIS_ADDR
+//
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// lockTestKey 生成本次测试独占的锁 key, 测试结束后删除锁和 fencing 计数器
+func lockTestKey(t *testing.T, c *Client) string {
+	t.Helper()
+
+	key := GenerateKey("test_lock", uuid.NewString())
+	t.Cleanup(func() {
+		_ = c.Del(context.Background(), key)
+		_ = c.Del(context.Background(), key+Delimiter+"fence")
+	})
+
+	return key
+}
+
+// TestLock_FenceMonotonic 同一个 key 每次获取锁的 fencing token 单调递增, 包括锁过期后被重新获取
+func TestLock_FenceMonotonic(t *testing.T) {
+	c := benchClient(t)
+	ctx := context.Background()
+	key := lockTestKey(t, c)
+
+	first, err := c.TryLock(ctx, key, time.Minute)
+	if err != nil {
+		t.Fatalf("TryLock() error = %v", err)
+	}
+
+	if _, err = c.TryLock(ctx, key, time.Minute); !errors.Is(err, ErrLockNotAcquired) {
+		t.Fatalf("锁被占用时 TryLock() error = %v, want ErrLockNotAcquired", err)
+	}
+
+	if err = first.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+
+	// 有效期很短的锁过期后被其他持有者获取
+	short, err := c.TryLock(ctx, key, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("TryLock() error = %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	next, err := c.TryLock(ctx, key, time.Minute)
+	if err != nil {
+		t.Fatalf("锁过期后 TryLock() error = %v", err)
+	}
+
+	if fences := []int64{first.Fence(), short.Fence(), next.Fence()}; fences[0] <= 0 || fences[1] <= fences[0] || fences[2] <= fences[1] {
+		t.Fatalf("fences = %v, want strictly increasing", fences)
+	}
+
+	// 旧持有者不能续期或释放新持有者的锁
+	if err = short.Refresh(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("旧持有者 Refresh() error = %v, want ErrLockNotHeld", err)
+	}
+
+	if err = short.Unlock(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("旧持有者 Unlock() error = %v, want ErrLockNotHeld", err)
+	}
+
+	if err = next.Unlock(ctx); err != nil {
+		t.Errorf("新持有者 Unlock() error = %v", err)
+	}
+}
+
+// TestLock_FenceConcurrent 并发竞争同一个锁, 每次获取到的 fencing token 互不相同
+func TestLock_FenceConcurrent(t *testing.T) {
+	c := benchClient(t)
+	key := lockTestKey(t, c)
+
+	SetLockRetryInterval(5 * time.Millisecond)
+	t.Cleanup(func() { SetLockRetryInterval(defaultLockRetryInterval) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	const workers = 8
+
+	var (
+		mu     sync.Mutex
+		fences = make(map[int64]bool, workers)
+		wg     sync.WaitGroup
+	)
+
+	for range workers {
+		wg.Go(func() {
+			l, err := c.Lock(ctx, key, time.Second)
+			if err != nil {
+				t.Errorf("Lock() error = %v", err)
+				return
+			}
+
+			mu.Lock()
+			fences[l.Fence()] = true
+			mu.Unlock()
+
+			if err = l.Unlock(ctx); err != nil {
+				t.Errorf("Unlock() error = %v", err)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if len(fences) != workers {
+		t.Errorf("distinct fences = %d, want %d", len(fences), workers)
+	}
+}
+
+// TestWithLock 正常结束时返回 fn 的错误并释放锁
+func TestWithLock(t *testing.T) {
+	c := benchClient(t)
+	ctx := context.Background()
+	key := lockTestKey(t, c)
+	errFn := errors.New("fn error")
+
+	err := c.WithLock(ctx, key, time.Second, func(context.Context) error { return errFn })
+	if !errors.Is(err, errFn) || errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("WithLock() error = %v, want fn error only", err)
+	}
+
+	l, err := c.TryLock(ctx, key, time.Second)
+	if err != nil {
+		t.Fatalf("WithLock 结束后 TryLock() error = %v, want released", err)
+	}
+
+	_ = l.Unlock(ctx)
+}
+
+// TestWithLock_LostLock 锁在执行期间丢失时续期失败, 取消传给 fn 的 ctx 并返回 ErrLockNotHeld, 且不释放新持有者的锁
+func TestWithLock_LostLock(t *testing.T) {
+	c := benchClient(t)
+	ctx := context.Background()
+	key := lockTestKey(t, c)
+
+	var other *Lock
+
+	err := c.WithLock(ctx, key, 300*time.Millisecond, func(ctx context.Context) error {
+		// 模拟锁过期后被其他持有者获取
+		if err := c.Del(ctx, key); err != nil {
+			return err
+		}
+
+		var err error
+		if other, err = c.TryLock(ctx, key, time.Minute); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+			t.Error("续期失败后 ctx 未取消")
+			return nil
+		}
+	})
+
+	if !errors.Is(err, ErrLockNotHeld) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("WithLock() error = %v, want ErrLockNotHeld joined with fn error", err)
+	}
+
+	if other == nil {
+		t.Fatal("其他持有者未获取到锁")
+	}
+
+	if err = other.Refresh(ctx); err != nil {
+		t.Errorf("其他持有者的锁被释放, Refresh() error = %v", err)
+	}
+}
+
+// TestLock_TTL 有效期为 0 时使用默认值, 小于 1 毫秒时按 1 毫秒处理, 续期不会 panic
+func TestLock_TTL(t *testing.T) {
+	c := offlineClient(t)
+
+	tests := []struct {
+		ttl  time.Duration
+		want time.Duration
+	}{
+		{0, defaultLockTTL},
+		{-time.Second, defaultLockTTL},
+		{time.Nanosecond, time.Millisecond},
+		{2 * time.Nanosecond, time.Millisecond},
+		{time.Second, time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := c.newLock("ttl", tt.ttl).TTL(); got != tt.want {
+			t.Errorf("newLock(%v).TTL() = %v, want %v", tt.ttl, got, tt.want)
+		}
+	}
+}
+
+// TestLock_AutoRenewFailed 续期失败时取消 ctx, context.Cause 包装 ErrLockRenewFailed 和续期错误
+func TestLock_AutoRenewFailed(t *testing.T) {
+	l := offlineClient(t).newLock("renew", time.Nanosecond)
+
+	ctx, stop := l.AutoRenew(context.Background())
+	defer stop()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("续期失败后 ctx 未取消")
+	}
+
+	if cause := context.Cause(ctx); !errors.Is(cause, ErrLockRenewFailed) || !errors.Is(cause, errOffline) {
+		t.Errorf("context.Cause() = %v, want ErrLockRenewFailed wrapping errOffline", cause)
+	}
+}
+
+// TestWithLock_ParentCause 父 ctx 以自定义原因取消时返回 fn 的错误, 不报告为锁丢失
+func TestWithLock_ParentCause(t *testing.T) {
+	c := benchClient(t)
+	key := lockTestKey(t, c)
+	errShutdown := errors.New("shutdown")
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	err := c.WithLock(ctx, key, time.Second, func(ctx context.Context) error {
+		cancel(errShutdown)
+		<-ctx.Done()
+
+		return context.Cause(ctx)
+	})
+
+	if !errors.Is(err, errShutdown) || errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("WithLock() error = %v, want shutdown only", err)
+	}
+
+	if _, err = c.TryLock(context.Background(), key, time.Second); err != nil {
+		t.Errorf("WithLock 结束后 TryLock() error = %v, want released", err)
+	}
+}
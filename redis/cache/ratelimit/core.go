@@ -0,0 +1,182 @@
+//
+// FilePath    : go-utils\redis\cache\ratelimit\core.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 基于 redis 的分布式限流, 令牌桶和滑动窗口日志算法, 判断和计数在同一个 lua 脚本中原子完成
+//
+
+// Package ratelimit 基于 redis 的分布式限流, 多实例部署时共享配额
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jiaopengzi/go-utils/redis/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// Result 限流判断结果
+type Result struct {
+	Allowed    bool          // 是否允许
+	Limit      int64         // 窗口内允许的请求数
+	Remaining  int64         // 剩余可用的请求数
+	RetryAfter time.Duration // 被拒绝时距离下一次可能允许的时间, 允许时为 0
+}
+
+// Limiter 限流器接口
+type Limiter interface {
+	// Allow 判断 key 本次请求是否允许, 允许时计入配额
+	//   - key: 限流的 key, 一般通过 cache.GenerateKey 生成
+	//   - limit: 窗口内允许的请求数
+	//   - window: 窗口时长
+	Allow(ctx context.Context, key string, limit int64, window time.Duration) (*Result, error)
+}
+
+// 确保限流器实现了 Limiter 接口
+var (
+	_ Limiter = (*TokenBucket)(nil)
+	_ Limiter = (*SlidingWindow)(nil)
+)
+
+// validate 校验限流参数
+func validate(limit int64, window time.Duration) error {
+	if limit <= 0 {
+		return errors.New("限流数量必须大于 0")
+	}
+
+	if window < time.Millisecond {
+		return errors.New("限流窗口不能小于 1 毫秒")
+	}
+
+	return nil
+}
+
+// tokenBucketScript 令牌桶, hash 记录剩余令牌数和上次更新时间, 令牌按 limit/window 的速率连续补充.
+// KEYS: 令牌桶; ARGV: 容量, 窗口(毫秒), 当前时间(毫秒)
+// 返回 {是否允许, 剩余令牌数(向下取整), 重试等待(毫秒)}
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local rate = capacity / window
+
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+-- 实例间时钟不同步时 now 可能早于 ts, 不能回退 ts, 否则下次按回退的时间重复补充令牌
+ts = math.max(ts, now)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', ts)
+-- 令牌补满后桶与不存在等价, 此时过期
+redis.call('PEXPIRE', KEYS[1], math.ceil((capacity - tokens) / rate) + 1)
+
+return {allowed, math.floor(tokens), retry}
+`)
+
+// TokenBucket 令牌桶限流器, 容量为 limit, 每个 window 补充 limit 个令牌, 允许突发 limit 个请求后按平均速率放行.
+// 使用本机时间计算, 多实例部署时需要保证时钟同步; 时钟落后的实例不会回退补充时间, 不会多发令牌.
+type TokenBucket struct {
+	client *cache.Client
+	now    func() time.Time // 当前时间, 便于测试
+}
+
+// NewTokenBucket 创建令牌桶限流器
+//   - c: 缓存客户端
+func NewTokenBucket(c *cache.Client) *TokenBucket {
+	return &TokenBucket{client: c, now: time.Now}
+}
+
+// Allow 实现 Limiter 接口, 取出一个令牌, 令牌不足时拒绝
+func (b *TokenBucket) Allow(ctx context.Context, key string, limit int64, window time.Duration) (*Result, error) {
+	if err := validate(limit, window); err != nil {
+		return nil, err
+	}
+
+	values, err := tokenBucketScript.Run(ctx, b.client.Client, []string{b.client.Key(key)},
+		limit, window.Milliseconds(), b.now().UnixMilli()).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	return newResult(values, limit), nil
+}
+
+// slidingWindowScript 滑动窗口日志, zset 记录窗口内每次允许的请求时间, 窗口内请求数达到 limit 时拒绝.
+// KEYS: 请求日志; ARGV: 限制数量, 窗口(毫秒), 当前时间(毫秒), 请求唯一标识
+// 返回 {是否允许, 剩余请求数, 重试等待(毫秒)}
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+
+local count = redis.call('ZCARD', KEYS[1])
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	return {1, limit - count - 1, 0}
+end
+
+-- 最早的请求移出窗口后才能再次允许
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return {0, 0, math.max(1, tonumber(oldest[2]) + window - now)}
+`)
+
+// SlidingWindow 滑动窗口日志限流器, 任意 window 时长内允许的请求数不超过 limit, 比固定窗口精确, 没有窗口边界的突发.
+// 每个允许的请求占用 zset 中的一个成员, 适合 limit 不太大(例如每分钟数千以内)的场景.
+// 使用本机时间计算, 多实例部署时需要保证时钟同步.
+type SlidingWindow struct {
+	client *cache.Client
+	now    func() time.Time // 当前时间, 便于测试
+}
+
+// NewSlidingWindow 创建滑动窗口日志限流器
+//   - c: 缓存客户端
+func NewSlidingWindow(c *cache.Client) *SlidingWindow {
+	return &SlidingWindow{client: c, now: time.Now}
+}
+
+// Allow 实现 Limiter 接口, 窗口内请求数未达到 limit 时允许并记录本次请求
+func (w *SlidingWindow) Allow(ctx context.Context, key string, limit int64, window time.Duration) (*Result, error) {
+	if err := validate(limit, window); err != nil {
+		return nil, err
+	}
+
+	values, err := slidingWindowScript.Run(ctx, w.client.Client, []string{w.client.Key(key)},
+		limit, window.Milliseconds(), w.now().UnixMilli(), uuid.NewString()).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	return newResult(values, limit), nil
+}
+
+// newResult 根据脚本返回的 {是否允许, 剩余数量, 重试等待(毫秒)} 创建结果
+func newResult(values []int64, limit int64) *Result {
+	return &Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  values[1],
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}
+}
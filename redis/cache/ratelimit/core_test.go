@@ -0,0 +1,192 @@
+//
+// FilePath    : go-utils\redis\cache\ratelimit\core_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 分布式限流测试, 除参数校验外需要设置 CACHE_BENCH_REDIS_ADDR
+//
+
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jiaopengzi/go-utils/redis/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisAddrEnv 依赖 redis 的测试使用的 redis 地址, 未设置时跳过
+const redisAddrEnv = "CACHE_BENCH_REDIS_ADDR"
+
+// testClient 创建依赖 redis 的测试使用的缓存客户端
+func testClient(t *testing.T) *cache.Client {
+	t.Helper()
+
+	addr := os.Getenv(redisAddrEnv)
+	if addr == "" {
+		t.Skipf("未设置环境变量 %s, 跳过", redisAddrEnv)
+	}
+
+	rdb := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		t.Skipf("无法连接 redis %s: %v", addr, err)
+	}
+
+	return cache.NewClient(rdb)
+}
+
+// testKey 生成本次测试独占的限流 key, 测试结束后删除
+func testKey(t *testing.T, c *cache.Client) string {
+	t.Helper()
+
+	key := cache.GenerateKey("test_ratelimit", uuid.NewString())
+	t.Cleanup(func() { _ = c.Del(context.Background(), key) })
+
+	return key
+}
+
+// fakeClock 手动推进的时钟
+type fakeClock struct {
+	t time.Time
+}
+
+// now 获取当前时间
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+// advance 推进 d
+func (c *fakeClock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+// allow 调用 Allow 并校验结果
+func allow(t *testing.T, l Limiter, key string, limit int64, window time.Duration, want Result) {
+	t.Helper()
+
+	got, err := l.Allow(context.Background(), key, limit, window)
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+
+	if *got != want {
+		t.Fatalf("Allow() = %+v, want %+v", *got, want)
+	}
+}
+
+// assertPTTL 校验 key 的剩余有效期在 (0, max] 之间
+func assertPTTL(t *testing.T, c *cache.Client, key string, maxTTL time.Duration) {
+	t.Helper()
+
+	ttl, err := c.Client.PTTL(context.Background(), c.Key(key)).Result()
+	if err != nil {
+		t.Fatalf("PTTL() error = %v", err)
+	}
+
+	if ttl <= 0 || ttl > maxTTL {
+		t.Errorf("PTTL() = %v, want (0, %v]", ttl, maxTTL)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	limiters := []Limiter{NewTokenBucket(nil), NewSlidingWindow(nil)}
+
+	for _, l := range limiters {
+		if _, err := l.Allow(context.Background(), "key", 0, time.Second); err == nil {
+			t.Errorf("%T limit 为 0 Allow() error = nil", l)
+		}
+
+		if _, err := l.Allow(context.Background(), "key", 1, time.Microsecond); err == nil {
+			t.Errorf("%T window 小于 1 毫秒 Allow() error = nil", l)
+		}
+	}
+}
+
+// TestTokenBucket 突发 limit 个请求后拒绝, 令牌按 limit/window 的速率补充, 不超过容量
+func TestTokenBucket(t *testing.T) {
+	c := testClient(t)
+	key := testKey(t, c)
+	clock := &fakeClock{t: time.UnixMilli(1_000_000)}
+
+	b := NewTokenBucket(c)
+	b.now = clock.now
+
+	// 容量 3, 每秒补充 1 个
+	const limit, window = 3, 3 * time.Second
+
+	allow(t, b, key, limit, window, Result{Allowed: true, Limit: limit, Remaining: 2})
+	assertPTTL(t, c, key, time.Second+time.Millisecond)
+
+	allow(t, b, key, limit, window, Result{Allowed: true, Limit: limit, Remaining: 1})
+	allow(t, b, key, limit, window, Result{Allowed: true, Limit: limit, Remaining: 0})
+	allow(t, b, key, limit, window, Result{Limit: limit, RetryAfter: time.Second})
+
+	// 补充半个令牌, 仍然拒绝
+	clock.advance(500 * time.Millisecond)
+	allow(t, b, key, limit, window, Result{Limit: limit, RetryAfter: 500 * time.Millisecond})
+
+	clock.advance(500 * time.Millisecond)
+	allow(t, b, key, limit, window, Result{Allowed: true, Limit: limit, Remaining: 0})
+
+	// 长时间空闲后最多补满容量
+	clock.advance(time.Hour)
+	allow(t, b, key, limit, window, Result{Allowed: true, Limit: limit, Remaining: 2})
+	assertPTTL(t, c, key, time.Second+time.Millisecond)
+}
+
+// TestTokenBucket_ClockSkew 时钟落后的实例不会回退令牌桶的更新时间, 不会多发令牌
+func TestTokenBucket_ClockSkew(t *testing.T) {
+	c := testClient(t)
+	key := testKey(t, c)
+
+	ahead := &fakeClock{t: time.UnixMilli(1_000_000)}
+	behind := &fakeClock{t: ahead.t.Add(-time.Second)}
+
+	a, b := NewTokenBucket(c), NewTokenBucket(c)
+	a.now, b.now = ahead.now, behind.now
+
+	// 容量 2, 每秒补充 1 个
+	const limit, window = 2, 2 * time.Second
+
+	allow(t, a, key, limit, window, Result{Allowed: true, Limit: limit, Remaining: 1})
+	allow(t, a, key, limit, window, Result{Allowed: true, Limit: limit, Remaining: 0})
+	allow(t, b, key, limit, window, Result{Limit: limit, RetryAfter: time.Second})
+
+	// 时钟正常的实例在同一时刻仍然没有令牌
+	allow(t, a, key, limit, window, Result{Limit: limit, RetryAfter: time.Second})
+}
+
+// TestSlidingWindow 任意窗口内允许的请求数不超过 limit, 最早的请求移出窗口后再次允许
+func TestSlidingWindow(t *testing.T) {
+	c := testClient(t)
+	key := testKey(t, c)
+	clock := &fakeClock{t: time.UnixMilli(1_000_000)}
+
+	w := NewSlidingWindow(c)
+	w.now = clock.now
+
+	const limit, window = 2, time.Second
+
+	allow(t, w, key, limit, window, Result{Allowed: true, Limit: limit, Remaining: 1})
+
+	clock.advance(100 * time.Millisecond)
+	allow(t, w, key, limit, window, Result{Allowed: true, Limit: limit, Remaining: 0})
+	assertPTTL(t, c, key, window)
+
+	// 最早的请求 900 毫秒后移出窗口
+	clock.advance(100 * time.Millisecond)
+	allow(t, w, key, limit, window, Result{Limit: limit, RetryAfter: 800 * time.Millisecond})
+
+	clock.advance(800 * time.Millisecond)
+	allow(t, w, key, limit, window, Result{Allowed: true, Limit: limit, Remaining: 0})
+
+	// 被拒绝的请求不计入窗口
+	clock.advance(100 * time.Millisecond)
+	allow(t, w, key, limit, window, Result{Allowed: true, Limit: limit, Remaining: 0})
+}
@@ -0,0 +1,119 @@
+//
+// FilePath    : go-utils\redis\cache\ratelimit\middleware.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : gin 限流中间件
+//
+
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/go-utils/redis/cache"
+	"go.uber.org/zap"
+)
+
+// 限流响应头
+const (
+	HeaderLimit     = "X-RateLimit-Limit"     // 窗口内允许的请求数
+	HeaderRemaining = "X-RateLimit-Remaining" // 剩余可用的请求数
+	HeaderRetry     = "Retry-After"           // 被拒绝时距离下一次可能允许的秒数
+)
+
+// ratelimitPurpose 限流缓存键的用途
+const ratelimitPurpose cache.Purpose = "ratelimit"
+
+// KeyFunc 获取请求的限流 key, 返回空字符串时不限流
+type KeyFunc func(c *gin.Context) string
+
+// KeyByClientIP 按客户端 IP 限流
+func KeyByClientIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// KeyByRouteAndClientIP 按路由和客户端 IP 限流, 每个路由单独计算配额
+func KeyByRouteAndClientIP(c *gin.Context) string {
+	return c.Request.Method + " " + c.FullPath() + " " + c.ClientIP()
+}
+
+// middlewareConfig 限流中间件配置
+type middlewareConfig struct {
+	onLimited  func(c *gin.Context, r *Result) // 被限流时的处理函数
+	failClosed bool                            // 限流器出错时是否拒绝请求
+}
+
+// MiddlewareOption 定义限流中间件的可选配置函数类型
+type MiddlewareOption func(*middlewareConfig)
+
+// WithLimitedHandler 设置被限流时的处理函数, 需要自行中止请求; 默认返回 429 和 JSON 错误信息
+func WithLimitedHandler(fn func(c *gin.Context, r *Result)) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.onLimited = fn
+	}
+}
+
+// WithFailClosed 设置限流器出错(例如 redis 不可用)时拒绝请求并返回 503, 默认记录日志后放行
+func WithFailClosed() MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.failClosed = true
+	}
+}
+
+// defaultLimitedHandler 默认的限流处理, 返回 429
+func defaultLimitedHandler(c *gin.Context, _ *Result) {
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "请求过于频繁, 请稍后再试"})
+}
+
+// Middleware gin 限流中间件, 按 keyFunc 的结果分别计算配额, 设置 X-RateLimit-* 响应头, 被拒绝时设置 Retry-After.
+// 缓存 key 为 cache.GenerateKey("ratelimit", keyFunc(c)), 多个中间件使用不同配额时 keyFunc 需要返回不同的 key.
+//   - limiter: 限流器, 例如 NewTokenBucket(c)
+//   - limit: 窗口内允许的请求数
+//   - window: 窗口时长
+//   - keyFunc: 获取请求的限流 key, 例如 KeyByClientIP
+//   - opts: 可选配置
+func Middleware(limiter Limiter, limit int64, window time.Duration, keyFunc KeyFunc, opts ...MiddlewareOption) gin.HandlerFunc {
+	cfg := &middlewareConfig{onLimited: defaultLimitedHandler}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(c *gin.Context) {
+		key := keyFunc(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		result, err := limiter.Allow(c.Request.Context(), cache.GenerateKey(ratelimitPurpose, key), limit, window)
+		if err != nil {
+			zap.L().Error("限流判断失败", zap.String("key", key), zap.Bool("failClosed", cfg.failClosed), zap.Error(err))
+
+			if cfg.failClosed {
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "服务繁忙, 请稍后再试"})
+				return
+			}
+
+			c.Next()
+
+			return
+		}
+
+		c.Header(HeaderLimit, strconv.FormatInt(result.Limit, 10))
+		c.Header(HeaderRemaining, strconv.FormatInt(result.Remaining, 10))
+
+		if !result.Allowed {
+			c.Header(HeaderRetry, strconv.FormatInt(int64(math.Ceil(result.RetryAfter.Seconds())), 10))
+			cfg.onLimited(c, result)
+
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,65 @@
+//
+// FilePath    : go-utils\redis\cache\serializer.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 结构体序列化器, 默认 JSON, 可选 msgpack
+//
+
+package cache
+
+import (
+	"encoding/json"
+
+	"github.com/ugorji/go/codec"
+)
+
+// Serializer 结构体序列化器, 用于 SetStringWithStruct、GetStringWithStruct 等读写结构体的方法
+type Serializer interface {
+	Marshal(v any) ([]byte, error)      // 序列化
+	Unmarshal(data []byte, v any) error // 反序列化, v 为指针
+}
+
+// 确保序列化器实现了 Serializer 接口
+var (
+	_ Serializer = JSONSerializer{}
+	_ Serializer = MsgpackSerializer{}
+)
+
+// JSONSerializer JSON 序列化器, 默认使用
+type JSONSerializer struct{}
+
+// Marshal 实现 Serializer 接口
+func (JSONSerializer) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal 实现 Serializer 接口
+func (JSONSerializer) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// msgpackHandle msgpack 编解码配置, 与 gin 的 msgpack 渲染一样使用 codec 或 json 标签命名字段, 字符串解码为 string 而不是 []byte
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{}
+	h.RawToString = true
+
+	return h
+}()
+
+// MsgpackSerializer msgpack 序列化器, 体积比 JSON 小, 编解码更快, 但 redis-cli 中不可直接阅读
+type MsgpackSerializer struct{}
+
+// Marshal 实现 Serializer 接口
+func (MsgpackSerializer) Marshal(v any) ([]byte, error) {
+	var data []byte
+
+	err := codec.NewEncoderBytes(&data, msgpackHandle).Encode(v)
+
+	return data, err
+}
+
+// Unmarshal 实现 Serializer 接口
+func (MsgpackSerializer) Unmarshal(data []byte, v any) error {
+	return codec.NewDecoderBytes(data, msgpackHandle).Decode(v)
+}
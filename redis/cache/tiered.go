@@ -0,0 +1,478 @@
+//
+// FilePath    : go-utils\redis\cache\tiered.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 二级缓存, 进程内 LRU + redis, 通过 redis 发布订阅在实例间同步失效, 减少热点 key 的网络往返
+//
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jiaopengzi/go-utils"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// 二级缓存的默认参数
+const (
+	defaultLocalSize = 10000       // 默认本地缓存最大条目数
+	defaultLocalTTL  = time.Minute // 默认本地缓存有效期
+)
+
+// invalidatePurpose 失效通知频道的用途
+const invalidatePurpose Purpose = "invalidate"
+
+// localEntry 本地缓存条目
+type localEntry struct {
+	value    string    // 缓存值
+	expireAt time.Time // 过期时间
+}
+
+// invalidation 实例间的失效通知
+type invalidation struct {
+	From   string   `json:"from"`             // 发送通知的实例, 实例忽略自己发送的通知
+	Keys   []string `json:"keys,omitempty"`   // 失效的 key
+	Prefix string   `json:"prefix,omitempty"` // 失效的 key 前缀
+}
+
+// tieredConfig 二级缓存配置
+type tieredConfig struct {
+	localSize int           // 本地缓存最大条目数
+	localTTL  time.Duration // 本地缓存有效期
+	channel   string        // 失效通知频道
+}
+
+// TieredOption 定义二级缓存的可选配置函数类型
+type TieredOption func(*tieredConfig)
+
+// WithLocalSize 设置本地缓存最大条目数, 默认 10000
+func WithLocalSize(size int) TieredOption {
+	return func(c *tieredConfig) {
+		c.localSize = size
+	}
+}
+
+// WithLocalTTL 设置本地缓存有效期, 默认 1 分钟; 也是失效通知丢失时本地数据过期的最长时间, 订阅连接重连时清空本地缓存
+func WithLocalTTL(ttl time.Duration) TieredOption {
+	return func(c *tieredConfig) {
+		c.localTTL = ttl
+	}
+}
+
+// WithInvalidationChannel 设置失效通知频道, 默认 c.Key(GenerateKey("invalidate")), 同一组实例需要使用相同的频道
+func WithInvalidationChannel(channel string) TieredOption {
+	return func(c *tieredConfig) {
+		c.channel = channel
+	}
+}
+
+// TieredClient 二级缓存客户端, 在 Client 之上为字符串类型的读取(GetString、GetStringWithStruct 等)增加进程内 LRU 缓存.
+// 通过本实例写入或删除 key 时删除本地缓存并发布失效通知, 其他实例收到后删除各自的本地缓存;
+// 本地缓存的有效期不超过 redis 中 key 的剩余有效期和 WithLocalTTL.
+// MGetWithStruct、hash、set、zset、计数器读取等其他方法直接使用 redis. 绕过 TieredClient 直接修改 redis 的 key 需要调用 Invalidate.
+type TieredClient struct {
+	*Client
+
+	local      *utils.LRU[string, localEntry] // 本地缓存, 按条目数限制容量
+	mu         sync.Mutex                     // 保证失效与写入本地缓存的顺序
+	generation uint64                         // 失效次数, 每次失效或订阅重连后递增
+	localTTL   time.Duration                  // 本地缓存有效期
+	channel    string                         // 失效通知频道
+	instanceID string                         // 实例ID, 用于忽略自己发送的通知
+	pubsub     *redis.PubSub                  // 失效通知订阅
+	now        func() time.Time               // 当前时间, 便于测试
+}
+
+// 确保 TieredClient 实现了 Cacher 接口
+var _ Cacher = (*TieredClient)(nil)
+
+// NewTieredClient 创建二级缓存客户端并订阅失效通知, ctx 取消或调用 Close 时停止订阅
+//   - c: 缓存客户端
+//   - opts: 可选配置
+func NewTieredClient(ctx context.Context, c *Client, opts ...TieredOption) (*TieredClient, error) {
+	cfg := &tieredConfig{
+		localSize: defaultLocalSize,
+		localTTL:  defaultLocalTTL,
+		channel:   c.Key(GenerateKey(invalidatePurpose)),
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	pubsub := c.Client.Subscribe(ctx, cfg.channel)
+
+	// 等待订阅确认, 确保创建成功后不会漏掉失效通知
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+
+	t := &TieredClient{
+		Client:     c,
+		local:      utils.NewLRU[string, localEntry](int64(cfg.localSize), nil),
+		localTTL:   cfg.localTTL,
+		channel:    cfg.channel,
+		instanceID: uuid.NewString(),
+		pubsub:     pubsub,
+		now:        time.Now,
+	}
+
+	go t.listen(ctx)
+
+	return t, nil
+}
+
+// Close 停止订阅失效通知, 之后本地缓存不再接收其他实例的失效通知
+func (t *TieredClient) Close() error {
+	return t.pubsub.Close()
+}
+
+// LocalLen 获取本地缓存的条目数量
+func (t *TieredClient) LocalLen() int {
+	return t.local.Len()
+}
+
+// listen 接收失效通知并删除本地缓存, 订阅重连时清空本地缓存
+func (t *TieredClient) listen(ctx context.Context) {
+	ch := t.pubsub.ChannelWithSubscriptions()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = t.pubsub.Close()
+			return
+
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			switch msg := msg.(type) {
+			case *redis.Subscription:
+				// 创建时的订阅确认已被接收, 之后的订阅确认说明连接断开后重新订阅, 期间的失效通知已丢失
+				if msg.Kind == "subscribe" {
+					t.resetLocal()
+					zap.L().Warn("缓存失效通知订阅已重连, 清空本地缓存", zap.String("channel", msg.Channel))
+				}
+			case *redis.Message:
+				t.handleMessage(msg.Payload)
+			}
+		}
+	}
+}
+
+// handleMessage 处理其他实例的失效通知
+func (t *TieredClient) handleMessage(payload string) {
+	var inv invalidation
+	if err := json.Unmarshal([]byte(payload), &inv); err != nil {
+		zap.L().Warn("解析缓存失效通知失败", zap.String("payload", payload), zap.Error(err))
+		return
+	}
+
+	if inv.From == t.instanceID {
+		return
+	}
+
+	t.applyInvalidation(&inv)
+}
+
+// applyInvalidation 删除失效通知对应的本地缓存
+func (t *TieredClient) applyInvalidation(inv *invalidation) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.generation++
+
+	for _, key := range inv.Keys {
+		t.local.Delete(key)
+	}
+
+	if inv.Prefix != "" {
+		t.local.DeleteFunc(func(key string, _ localEntry) bool { return strings.HasPrefix(key, inv.Prefix) })
+	}
+}
+
+// resetLocal 清空本地缓存
+func (t *TieredClient) resetLocal() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.generation++
+	t.local.Clear()
+}
+
+// currentGeneration 获取当前失效次数
+func (t *TieredClient) currentGeneration() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.generation
+}
+
+// fill 写入本地缓存, 失效次数与读取 redis 前不同时不写入, 避免读取期间失效的旧值写入本地缓存
+//   - key: 缓存 key
+//   - entry: 本地缓存条目
+//   - generation: 读取 redis 前的失效次数
+func (t *TieredClient) fill(key string, entry localEntry, generation uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.generation != generation {
+		return
+	}
+
+	t.local.Set(key, entry)
+}
+
+// publish 删除本地缓存并通知其他实例, 通知失败只记录日志, 其他实例的本地缓存在有效期结束后自动过期
+func (t *TieredClient) publish(ctx context.Context, inv *invalidation) {
+	inv.From = t.instanceID
+	t.applyInvalidation(inv)
+
+	payload, err := json.Marshal(inv)
+	if err == nil {
+		err = t.Client.Client.Publish(ctx, t.channel, payload).Err()
+	}
+
+	if err != nil {
+		zap.L().Warn("发布缓存失效通知失败", zap.Strings("keys", inv.Keys), zap.String("prefix", inv.Prefix), zap.Error(err))
+	}
+}
+
+// Invalidate 删除本地缓存并通知其他实例, 用于绕过 TieredClient 修改了 redis 中的 key 的场景
+//   - keys: 缓存 key
+func (t *TieredClient) Invalidate(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	t.publish(ctx, &invalidation{Keys: keys})
+}
+
+// localGet 获取未过期的本地缓存值, 过期的条目顺便删除
+func (t *TieredClient) localGet(key string, now time.Time) (string, bool) {
+	entry, ok := t.local.Get(key)
+	if !ok {
+		return "", false
+	}
+
+	if !now.Before(entry.expireAt) {
+		t.local.Delete(key)
+		return "", false
+	}
+
+	return entry.value, true
+}
+
+// getString 先读本地缓存, 未命中时读取 redis 并写入本地缓存; 读取期间有任何失效时不写入本地缓存
+func (t *TieredClient) getString(ctx context.Context, key string) (string, error) {
+	now := t.now()
+	if value, ok := t.localGet(key, now); ok {
+		return value, nil
+	}
+
+	// 读取 redis 后、写入本地缓存前可能收到该 key 的失效通知, 记录读取前的失效次数
+	generation := t.currentGeneration()
+
+	var (
+		getCmd  *redis.StringCmd
+		pttlCmd *redis.DurationCmd
+	)
+
+	_, err := t.Client.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		getCmd = pipe.Get(ctx, t.Key(key))
+		pttlCmd = pipe.PTTL(ctx, t.Key(key))
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", err
+	}
+
+	value, err := getCmd.Result()
+	if err != nil {
+		return "", err
+	}
+
+	// 本地缓存不超过 key 在 redis 中的剩余有效期, PTTL 为 -1 表示永不过期
+	ttl := t.localTTL
+	if remaining := pttlCmd.Val(); remaining > 0 && remaining < ttl {
+		ttl = remaining
+	}
+
+	t.fill(key, localEntry{value: value, expireAt: now.Add(ttl)}, generation)
+
+	return value, nil
+}
+
+// SetString 实现 Cacher 接口 SetString 方法, 写入后失效各实例的本地缓存
+func (t *TieredClient) SetString(ctx context.Context, key, value string, duration time.Duration) error {
+	if err := t.Client.SetString(ctx, key, value, duration); err != nil {
+		return err
+	}
+
+	t.Invalidate(ctx, key)
+
+	return nil
+}
+
+// SetBool 实现 Cacher 接口 SetBool 方法, 写入后失效各实例的本地缓存
+func (t *TieredClient) SetBool(ctx context.Context, key string, value bool, duration time.Duration) error {
+	if err := t.Client.SetBool(ctx, key, value, duration); err != nil {
+		return err
+	}
+
+	t.Invalidate(ctx, key)
+
+	return nil
+}
+
+// SetStringWithStruct 实现 Cacher 接口 SetStringWithStruct 方法, 写入后失效各实例的本地缓存
+func (t *TieredClient) SetStringWithStruct(ctx context.Context, key string, value any, duration time.Duration) error {
+	if err := t.Client.SetStringWithStruct(ctx, key, value, duration); err != nil {
+		return err
+	}
+
+	t.Invalidate(ctx, key)
+
+	return nil
+}
+
+// MSetWithStruct 实现 Cacher 接口 MSetWithStruct 方法, 写入后失效各实例的本地缓存
+func (t *TieredClient) MSetWithStruct(ctx context.Context, values map[string]any, duration time.Duration) error {
+	err := t.Client.MSetWithStruct(ctx, values, duration)
+
+	// 部分写入失败时已写入的 key 同样需要失效
+	t.Invalidate(ctx, utils.Keys(values)...)
+
+	return err
+}
+
+// GetBool 实现 Cacher 接口 GetBool 方法, 优先读取本地缓存
+func (t *TieredClient) GetBool(ctx context.Context, key string) (bool, error) {
+	boolStr, err := t.getString(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	return boolStr == "true", nil
+}
+
+// GetString 实现 Cacher 接口 GetString 方法, 优先读取本地缓存
+func (t *TieredClient) GetString(ctx context.Context, key string) (string, error) {
+	return t.getString(ctx, key)
+}
+
+// GetStringWithStruct 实现 Cacher 接口 GetStringWithStruct 方法, 优先读取本地缓存
+func (t *TieredClient) GetStringWithStruct(ctx context.Context, key string, value any) error {
+	valueJSON, err := t.getString(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return t.codec().Unmarshal([]byte(valueJSON), value)
+}
+
+// CheckString 实现 Cacher 接口 CheckString 方法, 优先读取本地缓存
+func (t *TieredClient) CheckString(ctx context.Context, key, str string) (bool, error) {
+	val, err := t.getString(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	return val == str, nil
+}
+
+// CheckWithStruct 实现 Cacher 接口 CheckWithStruct 方法, 优先读取本地缓存
+func (t *TieredClient) CheckWithStruct(ctx context.Context, key string, value any) (bool, error) {
+	valueJSONSrc, err := t.getString(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	valueJSONTar, err := t.codec().Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	return valueJSONSrc == string(valueJSONTar), nil
+}
+
+// SetCounter 实现 Cacher 接口 SetCounter 方法, 写入后失效各实例的本地缓存
+func (t *TieredClient) SetCounter(ctx context.Context, key string, value int64, duration time.Duration) error {
+	if err := t.Client.SetCounter(ctx, key, value, duration); err != nil {
+		return err
+	}
+
+	t.Invalidate(ctx, key)
+
+	return nil
+}
+
+// IncrementCounter 实现 Cacher 接口 IncrementCounter 方法, 写入后失效各实例的本地缓存
+func (t *TieredClient) IncrementCounter(ctx context.Context, key string, duration time.Duration, overrideTTL bool) (int64, error) {
+	val, err := t.Client.IncrementCounter(ctx, key, duration, overrideTTL)
+	if err != nil {
+		return 0, err
+	}
+
+	t.Invalidate(ctx, key)
+
+	return val, nil
+}
+
+// DecrementCounter 实现 Cacher 接口 DecrementCounter 方法, 写入后失效各实例的本地缓存
+func (t *TieredClient) DecrementCounter(ctx context.Context, key string, duration time.Duration, overrideTTL bool) (int64, error) {
+	val, err := t.Client.DecrementCounter(ctx, key, duration, overrideTTL)
+	if err != nil {
+		return 0, err
+	}
+
+	t.Invalidate(ctx, key)
+
+	return val, nil
+}
+
+// IncrementByCounter 实现 Cacher 接口 IncrementByCounter 方法, 写入后失效各实例的本地缓存
+func (t *TieredClient) IncrementByCounter(ctx context.Context, key string, delta int64, duration time.Duration, overrideTTL bool) (int64, error) {
+	val, err := t.Client.IncrementByCounter(ctx, key, delta, duration, overrideTTL)
+	if err != nil {
+		return 0, err
+	}
+
+	t.Invalidate(ctx, key)
+
+	return val, nil
+}
+
+// Del 实现 Cacher 接口 Del 方法, 删除后失效各实例的本地缓存
+func (t *TieredClient) Del(ctx context.Context, key string) error {
+	if err := t.Client.Del(ctx, key); err != nil {
+		return err
+	}
+
+	t.Invalidate(ctx, key)
+
+	return nil
+}
+
+// DelKeysWithPrefix 实现 Cacher 接口 DelKeysWithPrefix 方法, 删除后失效各实例中该前缀的本地缓存
+func (t *TieredClient) DelKeysWithPrefix(ctx context.Context, prefix string) error {
+	if err := t.Client.DelKeysWithPrefix(ctx, prefix); err != nil {
+		return err
+	}
+
+	t.publish(ctx, &invalidation{Prefix: prefix})
+
+	return nil
+}
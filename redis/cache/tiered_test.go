@@ -0,0 +1,62 @@
+//
+// FilePath    : go-utils\redis\cache\tiered_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 二级缓存本地层失效测试
+//
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jiaopengzi/go-utils"
+)
+
+// TestTieredClient_LocalInvalidation 测试读取期间失效时不写入旧值, 忽略自己的通知, 重连时清空, 过期条目不返回
+func TestTieredClient_LocalInvalidation(t *testing.T) {
+	now := time.Now()
+	tc := &TieredClient{local: utils.NewLRU[string, localEntry](10, nil), instanceID: "self", now: time.Now}
+
+	// 读取 redis 后收到失效通知, 旧值不写入本地缓存
+	generation := tc.currentGeneration()
+	tc.handleMessage(`{"from":"other","keys":["k"]}`)
+	tc.fill("k", localEntry{value: "old", expireAt: now.Add(time.Minute)}, generation)
+
+	if value, ok := tc.localGet("k", now); ok {
+		t.Fatalf("读取期间已失效的旧值不应写入本地缓存, got %q", value)
+	}
+
+	generation = tc.currentGeneration()
+	tc.fill("k", localEntry{value: "new", expireAt: now.Add(time.Minute)}, generation)
+	tc.fill("user:1", localEntry{value: "u1", expireAt: now.Add(time.Minute)}, generation)
+
+	// 自己发送的通知已在发布时处理
+	tc.handleMessage(`{"from":"self","keys":["k"]}`)
+
+	if value, ok := tc.localGet("k", now); !ok || value != "new" {
+		t.Fatalf("localGet(k) = %q, %v, want new", value, ok)
+	}
+
+	tc.handleMessage(`{"from":"other","prefix":"user:"}`)
+
+	if _, ok := tc.localGet("user:1", now); ok || tc.LocalLen() != 1 {
+		t.Errorf("前缀失效后 LocalLen() = %d, want 1", tc.LocalLen())
+	}
+
+	if _, ok := tc.localGet("k", now.Add(time.Minute)); ok || tc.LocalLen() != 0 {
+		t.Errorf("过期条目不应返回并应删除, LocalLen() = %d", tc.LocalLen())
+	}
+
+	// 订阅重连时清空本地缓存, 重连前开始的读取不再写入
+	generation = tc.currentGeneration()
+	tc.fill("k", localEntry{value: "v", expireAt: now.Add(time.Minute)}, generation)
+	tc.resetLocal()
+	tc.fill("k2", localEntry{value: "v", expireAt: now.Add(time.Minute)}, generation)
+
+	if tc.LocalLen() != 0 {
+		t.Errorf("重连后 LocalLen() = %d, want 0", tc.LocalLen())
+	}
+}
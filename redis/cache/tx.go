@@ -0,0 +1,95 @@
+//
+// FilePath    : go-utils\redis\cache\tx.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 基于 WATCH/MULTI 的乐观锁事务工具
+//
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jiaopengzi/go-utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// WatchKeys 使用 WATCH 乐观锁执行事务, keys 在 WATCH 之后被其他客户端修改时重试, 最多重试 transactionRetry 次.
+// fn 中通过 tx 读取数据, 通过 tx.TxPipelined 排队写入命令; fn 可能被多次调用, 不能有其他副作用.
+// keys 和 fn 中使用的 key 直接访问 redis, 设置了命名空间时需要通过 c.Key 获取完整 key.
+//   - keys: 需要 WATCH 的完整 key
+//   - fn: 事务函数
+func (c *Client) WatchKeys(ctx context.Context, keys []string, fn func(tx *redis.Tx) error) error {
+	// 事务冲突时立即重试, 其他错误直接返回
+	return utils.Retry(ctx, func(ctx context.Context) error {
+		return c.Client.Watch(ctx, fn, keys...)
+	},
+		utils.WithMaxAttempts(transactionRetry),
+		utils.WithConstantBackoff(0),
+		utils.RetryIf(func(err error) bool { return errors.Is(err, redis.TxFailedErr) }),
+	)
+}
+
+// UpdateStruct 使用乐观锁更新 key 对应的结构体: 读取当前值, 调用 mutate 修改后写回,
+// 期间 key 被其他客户端修改时重新读取并重试. key 不存在时 mutate 收到 T 的零值.
+//   - c: 缓存客户端
+//   - key: 缓存 key
+//   - duration: 有效期, 为 0 时保留 key 原有的有效期
+//   - mutate: 修改函数, 可能被多次调用, 返回错误时放弃更新
+func UpdateStruct[T any](ctx context.Context, c *Client, key string, duration time.Duration, mutate func(v *T) error) (*T, error) {
+	var result *T
+
+	fullKey := c.Key(key)
+
+	err := c.WatchKeys(ctx, []string{fullKey}, func(tx *redis.Tx) error {
+		var value T
+
+		// 读取当前值, key 不存在时使用零值
+		data, err := tx.Get(ctx, fullKey).Bytes()
+
+		switch {
+		case errors.Is(err, redis.Nil):
+		case err != nil:
+			return err
+		default:
+			if err = c.codec().Unmarshal(data, &value); err != nil {
+				return fmt.Errorf("unmarshal %s: %w", key, err)
+			}
+		}
+
+		if err = mutate(&value); err != nil {
+			return err
+		}
+
+		newData, err := c.codec().Marshal(&value)
+		if err != nil {
+			return err
+		}
+
+		expiration := duration
+		if expiration <= 0 {
+			expiration = redis.KeepTTL
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, fullKey, newData, expiration)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		result = &value
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,330 @@
+//
+// FilePath    : go-utils\redis\stream\admin\core.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : stream 运维管理, 消费者组快照、pending 消息查询及强制认领、签收、删除
+//
+
+// Package admin redis stream 运维管理, 无需 redis-cli 即可查看和处理消费者组及 pending 消息
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ErrNotFound stream、消费者组或消息不存在
+var ErrNotFound = errors.New("stream、消费者组或消息不存在")
+
+// StreamSnapshot stream 快照
+type StreamSnapshot struct {
+	Stream          string          `json:"stream"`            // stream 名称
+	Length          int64           `json:"length"`            // 消息数量
+	FirstID         string          `json:"first_id"`          // 第一条消息 ID
+	LastID          string          `json:"last_id"`           // 最后一条消息 ID
+	LastGeneratedID string          `json:"last_generated_id"` // 最后生成的消息 ID
+	EntriesAdded    int64           `json:"entries_added"`     // 累计添加的消息数量
+	Groups          []GroupSnapshot `json:"groups"`            // 消费者组
+}
+
+// GroupSnapshot 消费者组快照
+type GroupSnapshot struct {
+	Name            string             `json:"name"`              // 消费者组名称
+	Pending         int64              `json:"pending"`           // 已投递未签收的消息数量
+	LastDeliveredID string             `json:"last_delivered_id"` // 最后投递的消息 ID
+	Lag             int64              `json:"lag"`               // 未投递的消息数量, -1 表示无法确定
+	Consumers       []ConsumerSnapshot `json:"consumers"`         // 消费者
+}
+
+// ConsumerSnapshot 消费者快照
+type ConsumerSnapshot struct {
+	Name     string        `json:"name"`     // 消费者名称
+	Pending  int64         `json:"pending"`  // 已投递未签收的消息数量
+	Idle     time.Duration `json:"idle"`     // 距离上次读取的时间
+	Inactive time.Duration `json:"inactive"` // 距离上次成功处理的时间, -1ms 表示从未处理
+}
+
+// PendingMessage pending 消息
+type PendingMessage struct {
+	ID         string        `json:"id"`          // 消息 ID
+	Group      string        `json:"group"`       // 消费者组
+	Consumer   string        `json:"consumer"`    // 持有消息的消费者
+	Idle       time.Duration `json:"idle"`        // 距离上次投递的时间
+	RetryCount int64         `json:"retry_count"` // 投递次数
+}
+
+// Message 消息详情
+type Message struct {
+	ID      string           `json:"id"`      // 消息 ID
+	Values  map[string]any   `json:"values"`  // 消息内容
+	Pending []PendingMessage `json:"pending"` // 在各消费者组中的 pending 状态, 已签收的组不包含
+}
+
+// PendingQuery pending 消息查询条件
+type PendingQuery struct {
+	Consumer string        // 消费者, 为空表示全部
+	Start    string        // 开始 ID, 为空时为 "-"
+	End      string        // 结束 ID, 为空时为 "+"
+	Count    int64         // 最大数量, 小于等于 0 时为 100
+	MinIdle  time.Duration // 最小空闲时间, 用于查找卡住的消息
+}
+
+// Admin stream 运维管理
+type Admin struct {
+	Rdb redis.UniversalClient // Redis 客户端
+}
+
+// NewAdmin 创建 stream 运维管理
+func NewAdmin(rdb redis.UniversalClient) *Admin {
+	return &Admin{Rdb: rdb}
+}
+
+// notFound 将 redis 返回的 stream 或消费者组不存在错误转换为 ErrNotFound
+func notFound(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, redis.Nil) || err.Error() == "ERR no such key" || strings.HasPrefix(err.Error(), "NOGROUP") {
+		return fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), ErrNotFound)
+	}
+
+	return err
+}
+
+// Snapshot 获取 stream 及其所有消费者组、消费者的快照
+//   - ctx: 上下文
+//   - stream: stream 名称
+func (a *Admin) Snapshot(ctx context.Context, stream string) (*StreamSnapshot, error) {
+	info, err := a.Rdb.XInfoStream(ctx, stream).Result()
+	if err != nil {
+		return nil, notFound(err, "stream %s", stream)
+	}
+
+	snapshot := &StreamSnapshot{
+		Stream:          stream,
+		Length:          info.Length,
+		FirstID:         info.FirstEntry.ID,
+		LastID:          info.LastEntry.ID,
+		LastGeneratedID: info.LastGeneratedID,
+		EntriesAdded:    info.EntriesAdded,
+	}
+
+	groups, err := a.Rdb.XInfoGroups(ctx, stream).Result()
+	if err != nil {
+		return nil, notFound(err, "stream %s", stream)
+	}
+
+	for _, g := range groups {
+		consumers, err := a.Rdb.XInfoConsumers(ctx, stream, g.Name).Result()
+		if err != nil {
+			return nil, notFound(err, "stream %s group %s", stream, g.Name)
+		}
+
+		group := GroupSnapshot{
+			Name:            g.Name,
+			Pending:         g.Pending,
+			LastDeliveredID: g.LastDeliveredID,
+			Lag:             g.Lag,
+			Consumers:       make([]ConsumerSnapshot, 0, len(consumers)),
+		}
+
+		for _, c := range consumers {
+			group.Consumers = append(group.Consumers, ConsumerSnapshot{
+				Name:     c.Name,
+				Pending:  c.Pending,
+				Idle:     c.Idle,
+				Inactive: c.Inactive,
+			})
+		}
+
+		snapshot.Groups = append(snapshot.Groups, group)
+	}
+
+	return snapshot, nil
+}
+
+// Pending 查询消费者组的 pending 消息
+//   - ctx: 上下文
+//   - stream: stream 名称
+//   - group: 消费者组
+//   - query: 查询条件
+func (a *Admin) Pending(ctx context.Context, stream, group string, query PendingQuery) ([]PendingMessage, error) {
+	args := &redis.XPendingExtArgs{
+		Stream:   stream,
+		Group:    group,
+		Idle:     query.MinIdle,
+		Start:    query.Start,
+		End:      query.End,
+		Count:    query.Count,
+		Consumer: query.Consumer,
+	}
+
+	if args.Start == "" {
+		args.Start = "-"
+	}
+
+	if args.End == "" {
+		args.End = "+"
+	}
+
+	if args.Count <= 0 {
+		args.Count = 100
+	}
+
+	pending, err := a.Rdb.XPendingExt(ctx, args).Result()
+	if err != nil {
+		return nil, notFound(err, "stream %s group %s", stream, group)
+	}
+
+	messages := make([]PendingMessage, 0, len(pending))
+	for _, p := range pending {
+		messages = append(messages, PendingMessage{
+			ID:         p.ID,
+			Group:      group,
+			Consumer:   p.Consumer,
+			Idle:       p.Idle,
+			RetryCount: p.RetryCount,
+		})
+	}
+
+	return messages, nil
+}
+
+// Message 获取消息内容及其在各消费者组中的 pending 状态
+//   - ctx: 上下文
+//   - stream: stream 名称
+//   - id: 消息 ID
+func (a *Admin) Message(ctx context.Context, stream, id string) (*Message, error) {
+	entries, err := a.Rdb.XRange(ctx, stream, id, id).Result()
+	if err != nil {
+		return nil, notFound(err, "stream %s", stream)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("stream %s message %s: %w", stream, id, ErrNotFound)
+	}
+
+	message := &Message{ID: entries[0].ID, Values: entries[0].Values}
+
+	groups, err := a.Rdb.XInfoGroups(ctx, stream).Result()
+	if err != nil {
+		return nil, notFound(err, "stream %s", stream)
+	}
+
+	for _, g := range groups {
+		pending, err := a.Pending(ctx, stream, g.Name, PendingQuery{Start: id, End: id, Count: 1})
+		if err != nil {
+			return nil, err
+		}
+
+		message.Pending = append(message.Pending, pending...)
+	}
+
+	return message, nil
+}
+
+// Claim 强制将 pending 消息转移给 consumer, 不检查空闲时间, 返回认领成功的消息 ID
+//   - ctx: 上下文
+//   - stream: stream 名称
+//   - group: 消费者组
+//   - consumer: 认领消息的消费者
+//   - ids: 消息 ID
+func (a *Admin) Claim(ctx context.Context, stream, group, consumer string, ids ...string) ([]string, error) {
+	if consumer == "" || len(ids) == 0 {
+		return nil, errors.New("消费者和消息 ID 不能为空")
+	}
+
+	claimed, err := a.Rdb.XClaimJustID(ctx, &redis.XClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  0,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, notFound(err, "stream %s group %s", stream, group)
+	}
+
+	zap.L().Warn("强制认领 stream 消息",
+		zap.String("stream", stream), zap.String("group", group), zap.String("consumer", consumer),
+		zap.Strings("ids", ids), zap.Strings("claimed", claimed))
+
+	return claimed, nil
+}
+
+// Ack 强制签收 pending 消息, 返回签收成功的数量
+//   - ctx: 上下文
+//   - stream: stream 名称
+//   - group: 消费者组
+//   - ids: 消息 ID
+func (a *Admin) Ack(ctx context.Context, stream, group string, ids ...string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, errors.New("消息 ID 不能为空")
+	}
+
+	acked, err := a.Rdb.XAck(ctx, stream, group, ids...).Result()
+	if err != nil {
+		return 0, notFound(err, "stream %s group %s", stream, group)
+	}
+
+	zap.L().Warn("强制签收 stream 消息",
+		zap.String("stream", stream), zap.String("group", group), zap.Strings("ids", ids), zap.Int64("acked", acked))
+
+	return acked, nil
+}
+
+// Delete 删除消息, 删除前在所有消费者组中签收, 避免 pending 列表残留已删除的消息, 返回删除成功的数量
+//   - ctx: 上下文
+//   - stream: stream 名称
+//   - ids: 消息 ID
+func (a *Admin) Delete(ctx context.Context, stream string, ids ...string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, errors.New("消息 ID 不能为空")
+	}
+
+	groups, err := a.Rdb.XInfoGroups(ctx, stream).Result()
+	if err != nil {
+		return 0, notFound(err, "stream %s", stream)
+	}
+
+	for _, g := range groups {
+		if err := a.Rdb.XAck(ctx, stream, g.Name, ids...).Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	deleted, err := a.Rdb.XDel(ctx, stream, ids...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	zap.L().Warn("删除 stream 消息", zap.String("stream", stream), zap.Strings("ids", ids), zap.Int64("deleted", deleted))
+
+	return deleted, nil
+}
+
+// DeleteConsumer 删除消费者, 其 pending 消息会从消费者组中移除(不再重新投递), 返回被移除的 pending 消息数量.
+// 需要保留消息时先通过 Claim 转移给其他消费者.
+//   - ctx: 上下文
+//   - stream: stream 名称
+//   - group: 消费者组
+//   - consumer: 消费者
+func (a *Admin) DeleteConsumer(ctx context.Context, stream, group, consumer string) (int64, error) {
+	pending, err := a.Rdb.XGroupDelConsumer(ctx, stream, group, consumer).Result()
+	if err != nil {
+		return 0, notFound(err, "stream %s group %s", stream, group)
+	}
+
+	zap.L().Warn("删除 stream 消费者",
+		zap.String("stream", stream), zap.String("group", group), zap.String("consumer", consumer), zap.Int64("pending", pending))
+
+	return pending, nil
+}
@@ -0,0 +1,156 @@
+//
+// FilePath    : go-utils\redis\stream\admin\route.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : stream 运维管理 gin 路由
+//
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idsRequest 消息 ID 请求体
+type idsRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1"` // 消息 ID
+}
+
+// claimRequest 认领请求体
+type claimRequest struct {
+	Consumer string   `json:"consumer" binding:"required"`  // 认领消息的消费者
+	IDs      []string `json:"ids" binding:"required,min=1"` // 消息 ID
+}
+
+// RegisterRoutes 注册 stream 运维管理路由, 路由组需要由调用方添加鉴权中间件, 仅供运维使用.
+//   - GET    /streams/:stream                                        stream 快照
+//   - GET    /streams/:stream/messages/:id                           消息详情
+//   - POST   /streams/:stream/messages/delete                        删除消息, 请求体 {"ids": []}
+//   - GET    /streams/:stream/groups/:group/pending                  pending 消息, 参数 consumer、start、end、count、min_idle(如 30s)
+//   - POST   /streams/:stream/groups/:group/claim                    强制认领, 请求体 {"consumer": "", "ids": []}
+//   - POST   /streams/:stream/groups/:group/ack                      强制签收, 请求体 {"ids": []}
+//   - DELETE /streams/:stream/groups/:group/consumers/:consumer      删除消费者
+//
+// 成功时返回 200 及 JSON 数据, 不存在时返回 404, 参数错误时返回 400, 错误信息为 {"error": ""}.
+func RegisterRoutes(rg *gin.RouterGroup, a *Admin) {
+	streams := rg.Group("/streams/:stream")
+
+	streams.GET("", a.handleSnapshot)
+	streams.GET("/messages/:id", a.handleMessage)
+	streams.POST("/messages/delete", a.handleDelete)
+
+	groups := streams.Group("/groups/:group")
+	groups.GET("/pending", a.handlePending)
+	groups.POST("/claim", a.handleClaim)
+	groups.POST("/ack", a.handleAck)
+	groups.DELETE("/consumers/:consumer", a.handleDeleteConsumer)
+}
+
+// respond 返回数据或错误
+func respond(c *gin.Context, data any, err error) {
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, data)
+	case errors.Is(err, ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// badRequest 返回参数错误
+func badRequest(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// handleSnapshot stream 快照
+func (a *Admin) handleSnapshot(c *gin.Context) {
+	snapshot, err := a.Snapshot(c.Request.Context(), c.Param("stream"))
+	respond(c, snapshot, err)
+}
+
+// handleMessage 消息详情
+func (a *Admin) handleMessage(c *gin.Context) {
+	message, err := a.Message(c.Request.Context(), c.Param("stream"), c.Param("id"))
+	respond(c, message, err)
+}
+
+// handlePending pending 消息
+func (a *Admin) handlePending(c *gin.Context) {
+	query := PendingQuery{
+		Consumer: c.Query("consumer"),
+		Start:    c.Query("start"),
+		End:      c.Query("end"),
+	}
+
+	if v := c.Query("count"); v != "" {
+		count, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+
+		query.Count = count
+	}
+
+	if v := c.Query("min_idle"); v != "" {
+		minIdle, err := time.ParseDuration(v)
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+
+		query.MinIdle = minIdle
+	}
+
+	messages, err := a.Pending(c.Request.Context(), c.Param("stream"), c.Param("group"), query)
+	respond(c, messages, err)
+}
+
+// handleClaim 强制认领
+func (a *Admin) handleClaim(c *gin.Context) {
+	var req claimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	claimed, err := a.Claim(c.Request.Context(), c.Param("stream"), c.Param("group"), req.Consumer, req.IDs...)
+	respond(c, gin.H{"claimed": claimed}, err)
+}
+
+// handleAck 强制签收
+func (a *Admin) handleAck(c *gin.Context) {
+	var req idsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	acked, err := a.Ack(c.Request.Context(), c.Param("stream"), c.Param("group"), req.IDs...)
+	respond(c, gin.H{"acked": acked}, err)
+}
+
+// handleDelete 删除消息
+func (a *Admin) handleDelete(c *gin.Context) {
+	var req idsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	deleted, err := a.Delete(c.Request.Context(), c.Param("stream"), req.IDs...)
+	respond(c, gin.H{"deleted": deleted}, err)
+}
+
+// handleDeleteConsumer 删除消费者
+func (a *Admin) handleDeleteConsumer(c *gin.Context) {
+	pending, err := a.DeleteConsumer(c.Request.Context(), c.Param("stream"), c.Param("group"), c.Param("consumer"))
+	respond(c, gin.H{"pending": pending}, err)
+}
@@ -16,6 +16,9 @@ const (
 	CreateStreamStart  = "$"         // 创建消息队列开始位置
 )
 
+// FieldTraceID 消息中链路追踪ID的字段名, 生产时从上下文写入, 消费时通过 ContextWithMessageTrace 恢复
+const FieldTraceID = "trace_id"
+
 // Consumer 相关常量
 const (
 	GenerateUniqueConsumerNameMaxCount = 200   // 同组生成唯一消费者名称最大校验次数
@@ -0,0 +1,358 @@
+//
+// FilePath    : go-utils\redis\stream\consumer\autoscale.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 消费者自动扩缩容, 根据消费者组积压在最小和最大数量之间调整消费者
+//
+
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jiaopengzi/go-utils"
+	_stream "github.com/jiaopengzi/go-utils/redis/stream"
+	"go.uber.org/zap"
+)
+
+// AutoscaleConfig 自动扩缩容配置, 积压为消费者组待确认消息数与未投递消息数(lag)之和
+type AutoscaleConfig struct {
+	MinConsumers     int           // 最小消费者数量
+	MaxConsumers     int           // 最大消费者数量
+	Interval         time.Duration // 采样间隔, 零值为 10 秒
+	ScaleUpBacklog   int64         // 平均每个消费者的积压超过该值时扩容
+	ScaleDownBacklog int64         // 平均每个消费者的积压低于该值时缩容, 需要小于 ScaleUpBacklog, 两者之间的区间不调整
+	UpSamples        int           // 扩容判断的积压平滑周期(采样次数), 采样次数不足时不扩容, 零值为 2
+	DownSamples      int           // 缩容判断的积压平滑周期(采样次数), 采样次数不足时不缩容, 零值为 6, 缩容比扩容更保守
+	Cooldown         time.Duration // 两次调整的最小间隔, 零值为 30 秒
+}
+
+// withDefaults 返回填充默认值并校验后的配置
+func (cfg AutoscaleConfig) withDefaults() (AutoscaleConfig, error) {
+	if cfg.MinConsumers < _stream.ConsumerMinCount || cfg.MaxConsumers > _stream.ConsumerMaxCount || cfg.MinConsumers > cfg.MaxConsumers {
+		return cfg, fmt.Errorf("消费者数量范围 [%d, %d] 不合法, 需要在 [%d, %d] 之间",
+			cfg.MinConsumers, cfg.MaxConsumers, _stream.ConsumerMinCount, _stream.ConsumerMaxCount)
+	}
+
+	if cfg.ScaleUpBacklog <= 0 || cfg.ScaleDownBacklog < 0 || cfg.ScaleDownBacklog >= cfg.ScaleUpBacklog {
+		return cfg, fmt.Errorf("积压阈值不合法: ScaleDownBacklog=%d, ScaleUpBacklog=%d", cfg.ScaleDownBacklog, cfg.ScaleUpBacklog)
+	}
+
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Second
+	}
+
+	if cfg.UpSamples <= 0 {
+		cfg.UpSamples = 2
+	}
+
+	if cfg.DownSamples <= 0 {
+		cfg.DownSamples = 6
+	}
+
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+
+	return cfg, nil
+}
+
+// runningConsumer 运行中的消费者
+type runningConsumer struct {
+	name   string
+	cancel context.CancelFunc
+	done   chan struct{} // 消费者退出后关闭
+}
+
+// autoscaler 消费者自动扩缩容
+type autoscaler[T any] struct {
+	template *BaseConsumer[T] // 消费者模板
+	cfg      AutoscaleConfig
+
+	running   []*runningConsumer // 运行中的消费者, 缩容时移除最后启动的
+	retiring  []string           // 已停止等待从消费者组移除的消费者
+	upAvg     *utils.EWMA        // 扩容判断使用的积压平滑值, 平滑周期为 UpSamples
+	downAvg   *utils.EWMA        // 缩容判断使用的积压平滑值, 平滑周期为 DownSamples
+	samples   int                // 已采样次数
+	lastScale time.Time          // 上次调整时间
+}
+
+// newAutoscaler 创建消费者自动扩缩容
+//   - template: 消费者模板
+//   - cfg: 填充默认值后的自动扩缩容配置
+func newAutoscaler[T any](template *BaseConsumer[T], cfg AutoscaleConfig) *autoscaler[T] {
+	return &autoscaler[T]{
+		template: template,
+		cfg:      cfg,
+		upAvg:    utils.NewEWMAWithSpan(cfg.UpSamples),
+		downAvg:  utils.NewEWMAWithSpan(cfg.DownSamples),
+	}
+}
+
+// ManageAutoscaledConsumers 创建消费者组并启动 MinConsumers 个消费者, 之后按 Interval 采样积压自动扩缩容, 直到 config.Ctx 取消.
+// 扩缩容使用积压的指数移动平均和冷却时间防止抖动; 缩容时停止最后启动的消费者, 其未签收的消息由其他消费者认领后从消费者组移除.
+//   - config: 消费者配置, ConfigCount 会被忽略
+//   - scale: 自动扩缩容配置
+func ManageAutoscaledConsumers[T any](config *ConsumerConfig[T], scale AutoscaleConfig) error {
+	cfg, err := scale.withDefaults()
+	if err != nil {
+		return err
+	}
+
+	template := &BaseConsumer[T]{
+		StreamName:         config.StreamName,
+		GroupName:          config.GroupName,
+		Start:              _stream.CreateStreamStart,
+		MsgKey:             config.MsgKey,
+		ProcessMessageFunc: config.ProcessMessageFunc,
+		Ctx:                config.Ctx,
+		Rdb:                config.Rdb,
+		StateManager:       config.StateManager,
+		MaxDeliveries:      config.MaxDeliveries,
+		Concurrency:        config.Concurrency,
+		PoolMetrics:        config.PoolMetrics,
+	}
+
+	if err = template.CreateGroup(); err != nil {
+		return err
+	}
+
+	a := newAutoscaler(template, cfg)
+
+	if err = a.startMin(); err != nil {
+		a.stopAll()
+		return err
+	}
+
+	go a.loop()
+
+	return nil
+}
+
+// startMin 启动最小数量的消费者, 优先复用消费者组中已有的消费者名称
+func (a *autoscaler[T]) startMin() error {
+	consumerInfos, err := a.template.GetConsumersInfo()
+	if err != nil {
+		return err
+	}
+
+	for i := range a.cfg.MinConsumers {
+		if i < len(consumerInfos) {
+			a.start(consumerInfos[i].Name)
+			continue
+		}
+
+		if err = a.scaleUp(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// start 使用独立的上下文启动消费者, 缩容时单独取消
+//   - name: 消费者名称
+func (a *autoscaler[T]) start(name string) {
+	ctx, cancel := context.WithCancel(a.template.Ctx)
+	rc := &runningConsumer{name: name, cancel: cancel, done: make(chan struct{})}
+
+	c := *a.template // 传递值, 每个消费者单独一份
+	c.ConsumerName = name
+	c.Ctx = ctx
+
+	go func() {
+		defer close(rc.done)
+
+		if err := c.RunConsumer(); err != nil {
+			zap.L().Error("消费者运行错误", zap.Error(err), zap.String("consumerName", name))
+		}
+	}()
+
+	a.running = append(a.running, rc)
+}
+
+// scaleUp 创建并启动一个消费者
+func (a *autoscaler[T]) scaleUp() error {
+	c := *a.template
+	if err := createConsumerIfNeeded(&c, len(a.running)); err != nil {
+		return err
+	}
+
+	a.start(c.ConsumerName)
+
+	return nil
+}
+
+// scaleDown 停止最后启动的消费者, 等待其处理完已拉取的消息后加入待移除列表
+func (a *autoscaler[T]) scaleDown() {
+	rc := a.running[len(a.running)-1]
+	a.running = a.running[:len(a.running)-1]
+
+	rc.cancel()
+	<-rc.done
+
+	a.retiring = append(a.retiring, rc.name)
+}
+
+// stopAll 停止所有消费者
+func (a *autoscaler[T]) stopAll() {
+	for _, rc := range a.running {
+		rc.cancel()
+	}
+}
+
+// loop 按采样间隔调整消费者数量, 直到上下文取消
+func (a *autoscaler[T]) loop() {
+	ticker := time.NewTicker(a.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.template.Ctx.Done():
+			a.stopAll()
+			zap.L().Info("消费者自动扩缩容已停止", zap.String("streamName", a.template.StreamName), zap.String("groupName", a.template.GroupName))
+
+			return
+
+		case <-ticker.C:
+			if err := a.tick(); err != nil {
+				// 只记录错误日志, 不中断循环
+				zap.L().Warn("消费者自动扩缩容失败", zap.String("streamName", a.template.StreamName), zap.Error(err))
+			}
+		}
+	}
+}
+
+// tick 一次采样和调整
+func (a *autoscaler[T]) tick() error {
+	a.pruneExited()
+	a.removeRetired()
+
+	// 运行错误退出的消费者需要补足最小数量, 不受冷却时间限制
+	for len(a.running) < a.cfg.MinConsumers {
+		if err := a.scaleUp(); err != nil {
+			return err
+		}
+
+		a.logScale("补足最小数量", 0)
+	}
+
+	backlog, err := a.backlog()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	switch a.observe(backlog, now) {
+	case 1:
+		if err = a.scaleUp(); err != nil {
+			return err
+		}
+
+		a.logScale("扩容", backlog)
+	case -1:
+		a.scaleDown()
+		a.logScale("缩容", backlog)
+	default:
+		return nil
+	}
+
+	a.lastScale = now
+
+	return nil
+}
+
+// observe 记录一次积压采样并判断是否需要调整, 返回 1 表示扩容, -1 表示缩容, 0 表示不调整.
+// 扩容和缩容分别使用不同平滑周期的积压指数移动平均, 除以当前消费者数量后与阈值比较, 冷却时间内不调整.
+//   - backlog: 本次采样的积压
+//   - now: 采样时间
+func (a *autoscaler[T]) observe(backlog int64, now time.Time) int {
+	a.upAvg.Add(float64(backlog))
+	a.downAvg.Add(float64(backlog))
+	a.samples++
+
+	if now.Sub(a.lastScale) < a.cfg.Cooldown {
+		return 0
+	}
+
+	consumers := float64(len(a.running))
+
+	switch {
+	case a.samples >= a.cfg.UpSamples && len(a.running) < a.cfg.MaxConsumers &&
+		a.upAvg.Value()/consumers > float64(a.cfg.ScaleUpBacklog):
+		return 1
+	case a.samples >= a.cfg.DownSamples && len(a.running) > a.cfg.MinConsumers &&
+		a.downAvg.Value()/consumers < float64(a.cfg.ScaleDownBacklog):
+		return -1
+	default:
+		return 0
+	}
+}
+
+// backlog 获取消费者组的积压, 即待确认消息数与未投递消息数之和
+func (a *autoscaler[T]) backlog() (int64, error) {
+	groups, err := a.template.Rdb.XInfoGroups(a.template.Ctx, a.template.StreamName).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, g := range groups {
+		if g.Name == a.template.GroupName {
+			return g.Pending + max(g.Lag, 0), nil
+		}
+	}
+
+	return 0, fmt.Errorf("消费者组 %s 不存在", a.template.GroupName)
+}
+
+// pruneExited 移除已自行退出(例如运行错误)的消费者
+func (a *autoscaler[T]) pruneExited() {
+	running := a.running[:0]
+
+	for _, rc := range a.running {
+		select {
+		case <-rc.done:
+			a.retiring = append(a.retiring, rc.name)
+		default:
+			running = append(running, rc)
+		}
+	}
+
+	a.running = running
+}
+
+// removeRetired 从消费者组移除已停止的消费者, 仍有未签收消息时保留, 等待其他消费者认领后下次再移除
+func (a *autoscaler[T]) removeRetired() {
+	retiring := a.retiring[:0]
+
+	for _, name := range a.retiring {
+		info, err := a.template.GetConsumerInfo(name)
+		if err != nil {
+			// 已不存在
+			continue
+		}
+
+		if err = a.template.RemoveConsumer(info); err != nil {
+			retiring = append(retiring, name)
+			continue
+		}
+
+		zap.L().Info("移除消费者成功", zap.String("consumerName", name))
+	}
+
+	a.retiring = retiring
+}
+
+// logScale 记录调整日志
+func (a *autoscaler[T]) logScale(action string, backlog int64) {
+	zap.L().Info("消费者自动"+action,
+		zap.String("streamName", a.template.StreamName),
+		zap.String("groupName", a.template.GroupName),
+		zap.Int("consumers", len(a.running)),
+		zap.Int64("backlog", backlog),
+	)
+}
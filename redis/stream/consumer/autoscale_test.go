@@ -0,0 +1,82 @@
+//
+// FilePath    : go-utils\redis\stream\consumer\autoscale_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 消费者自动扩缩容判断测试
+//
+
+package consumer
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAutoscaler_Observe 使用模拟的积压采样测试扩容、冷却时间、最大数量和缩容
+func TestAutoscaler_Observe(t *testing.T) {
+	cfg, err := AutoscaleConfig{
+		MinConsumers:     1,
+		MaxConsumers:     3,
+		ScaleUpBacklog:   100,
+		ScaleDownBacklog: 10,
+		UpSamples:        2,
+		DownSamples:      3,
+		Cooldown:         30 * time.Second,
+	}.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := newAutoscaler(&BaseConsumer[string]{}, cfg)
+	a.running = []*runningConsumer{{name: "c1"}}
+
+	start := time.Now()
+
+	steps := []struct {
+		name    string
+		at      time.Duration // 相对开始的采样时间
+		backlog int64
+		want    int
+	}{
+		{"采样次数不足不扩容", 0, 500, 0},
+		{"平均积压超过阈值扩容", 10 * time.Second, 500, 1},
+		{"冷却时间内不调整", 20 * time.Second, 500, 0},
+		{"冷却结束继续扩容", 40 * time.Second, 500, 1},
+		{"已达最大数量", 80 * time.Second, 500, 0},
+		{"积压下降后平滑值仍高", 90 * time.Second, 0, 0},
+		{"平滑值逐步下降", 100 * time.Second, 0, 0},
+		{"平滑值逐步下降", 110 * time.Second, 0, 0},
+		{"平均积压仍高于缩容阈值", 120 * time.Second, 0, 0},
+		{"平均积压低于阈值缩容", 130 * time.Second, 0, -1},
+		{"冷却时间内不缩容", 140 * time.Second, 0, 0},
+		{"冷却结束继续缩容", 170 * time.Second, 0, -1},
+		{"已达最小数量", 210 * time.Second, 0, 0},
+		{"单次尖峰被平滑", 220 * time.Second, 120, 0},
+	}
+
+	for _, step := range steps {
+		now := start.Add(step.at)
+
+		got := a.observe(step.backlog, now)
+		if got != step.want {
+			t.Fatalf("%s: observe(%d) at %v = %d, want %d (consumers=%d)", step.name, step.backlog, step.at, got, step.want, len(a.running))
+		}
+
+		// 与 tick 相同, 调整后记录调整时间
+		switch got {
+		case 1:
+			a.running = append(a.running, &runningConsumer{})
+		case -1:
+			a.running = a.running[:len(a.running)-1]
+		default:
+			continue
+		}
+
+		a.lastScale = now
+	}
+
+	if len(a.running) != cfg.MinConsumers {
+		t.Errorf("consumers = %d, want %d", len(a.running), cfg.MinConsumers)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/jiaopengzi/go-utils/redis/stream"
@@ -54,6 +55,12 @@ type MessageStateManager interface {
 	UpdateAckStatus(streamName, msgID, groupName string, isSuccess bool) error
 }
 
+// MessageFailureRecorder 消息失败原因记录接口, StateManager 同时实现该接口时, 处理失败会记录失败原因
+type MessageFailureRecorder interface {
+	// RecordFailure 记录最后一次处理失败的原因
+	RecordFailure(streamName, msgID, reason string) error
+}
+
 // BaseConsumer 消费者基类
 type BaseConsumer[T any] struct {
 	StreamName         string                                                 // stream 名称 相同的 stream 名称的消费者将会共享消息
@@ -65,6 +72,68 @@ type BaseConsumer[T any] struct {
 	ProcessMessageFunc func(c *BaseConsumer[T], message redis.XMessage) error // 处理消息函数
 	Rdb                redis.UniversalClient                                  // Redis 客户端
 	StateManager       MessageStateManager                                    // 消息状态管理器
+	MaxDeliveries      int64                                                  // 最大投递次数, 大于 0 时处理失败的消息不签收而是重新投递, 达到次数后转移到死信队列; 零值为处理失败即签收
+	Concurrency        int                                                    // 并发处理数, 大于 1 时使用工作池并发处理消息; 零值为逐条处理
+	PoolMetrics        *PoolMetrics                                           // 工作池指标, 为 nil 时不对外暴露
+
+	pool     *workerPool[T] // 工作池, RunConsumer 时创建
+	inFlight *inFlightSet   // 已分发未处理完成的消息, RunConsumer 时创建
+}
+
+// inFlightSet 当前消费者已分发未处理完成的消息ID, 包括工作池队列中等待处理的消息, 认领 pending 消息时跳过.
+// 只在进程内记录, 不依赖 StateManager; 方法对 nil 安全, nil 表示不记录.
+type inFlightSet struct {
+	mu  sync.Mutex
+	ids map[string]struct{}
+}
+
+// newInFlightSet 创建已分发消息记录
+func newInFlightSet() *inFlightSet {
+	return &inFlightSet{ids: make(map[string]struct{})}
+}
+
+// add 记录已分发的消息, 消息已在处理中时返回 false
+func (s *inFlightSet) add(msgID string) bool {
+	if s == nil {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.ids[msgID]; ok {
+		return false
+	}
+
+	s.ids[msgID] = struct{}{}
+
+	return true
+}
+
+// remove 移除处理完成的消息
+func (s *inFlightSet) remove(msgID string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.ids, msgID)
+}
+
+// contains 判断消息是否已分发未处理完成
+func (s *inFlightSet) contains(msgID string) bool {
+	if s == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.ids[msgID]
+
+	return ok
 }
 
 // isGroupExistsByError 通过错误信息判断组是否存在, true 存在, false 不存在.
@@ -166,6 +235,11 @@ func (c *BaseConsumer[T]) PendingMessage() error {
 		return err
 	}
 
+	// 启用死信队列时先转移超过最大投递次数的消息
+	if c.MaxDeliveries > 0 {
+		pendingExt = c.deadLetterExceeded(pendingExt)
+	}
+
 	// 没有 pending 消息
 	if len(pendingExt) == 0 {
 		return nil
@@ -219,18 +293,24 @@ func (c *BaseConsumer[T]) getPendingExt(maxPending int64) ([]redis.XPendingExt,
 }
 
 // filterClaimableMsgIDs 过滤并提取需要认领的消息 ID：
-//   - 跳过属于自己的 pending(避免重复处理)
+//   - 跳过属于自己的 pending(避免重复处理), 启用死信队列时自己处理失败的消息也需要重新投递, 不跳过
+//   - 跳过自己已分发未处理完成的消息(包括工作池队列中等待处理的), 避免同一消息并发处理两次并增加投递次数
 //   - 只认领空闲时间大于阈值的消息(避免正在被其他消费者处理的短暂情况)
 //   - 跳过已被标记为正在处理(processing)的消息
 func (c *BaseConsumer[T]) filterClaimableMsgIDs(pendingExt []redis.XPendingExt, minIdle time.Duration) ([]string, error) {
 	var msgIDs []string
 
 	for _, p := range pendingExt {
-		if p.Consumer == c.ConsumerName {
+		if p.Consumer == c.ConsumerName && c.MaxDeliveries <= 0 {
 			// 跳过属于自己的 pending
 			continue
 		}
 
+		// 自己正在处理或等待处理, 空闲时间只反映投递时间, 不代表处理已中断
+		if c.inFlight.contains(p.ID) {
+			continue
+		}
+
 		// 仅认领空闲时间达到阈值的消息
 		if p.Idle < minIdle {
 			continue
@@ -271,12 +351,33 @@ func (c *BaseConsumer[T]) claimMessages(msgIDs []string, minIdle time.Duration)
 	return claimedMessages, nil
 }
 
+// dispatchMessage 启用工作池时将消息分发到工作池, 否则直接处理; 处理完成前记录为已分发, 已在处理中的消息不再重复分发
+func (c *BaseConsumer[T]) dispatchMessage(message redis.XMessage) error {
+	if !c.inFlight.add(message.ID) {
+		return nil
+	}
+
+	if c.pool != nil {
+		// 工作池处理完成后移除
+		if err := c.pool.submit(c.Ctx, message); err != nil {
+			c.inFlight.remove(message.ID)
+			return err
+		}
+
+		return nil
+	}
+
+	defer c.inFlight.remove(message.ID)
+
+	return c.ProcessMessage(message)
+}
+
 // processClaimedMessages 处理认领到的消息
 func (c *BaseConsumer[T]) processClaimedMessages(claimedMessages []redis.XMessage) {
 	for _, msg := range claimedMessages {
-		if err := c.ProcessMessage(msg); err != nil {
+		if err := c.dispatchMessage(msg); err != nil {
 			// 只记录错误日志, 继续处理其他消息
-			zap.L().Warn("处理 pending 消息失败, 跳过", zap.String("msgID", msg.ID), zap.Error(err))
+			zap.L().Warn("处理 pending 消息失败, 跳过", zap.String("msgID", msg.ID), zap.String("traceID", stream.TraceID(msg)), zap.Error(err))
 			continue
 		}
 	}
@@ -314,9 +415,14 @@ func (c *BaseConsumer[T]) OnlineMessage() error {
 
 	// 交给 ProcessMessage 处理, 内部可决定是否 Ack
 	for _, entry := range entries[0].Messages {
-		if err := c.ProcessMessage(entry); err != nil {
+		if err := c.dispatchMessage(entry); err != nil {
 			// 只记录错误日志, 继续处理其他消息
-			zap.L().Warn("处理在线消息失败, 跳过", zap.String("msgID", entry.ID), zap.String("consumer", c.ConsumerName), zap.Error(err))
+			zap.L().Warn("处理在线消息失败, 跳过",
+				zap.String("msgID", entry.ID),
+				zap.String("traceID", stream.TraceID(entry)),
+				zap.String("consumer", c.ConsumerName),
+				zap.Error(err),
+			)
 		}
 	}
 
@@ -366,10 +472,26 @@ func (c *BaseConsumer[T]) startOnlineMessageLoop(ctx context.Context) error {
 	}
 }
 
-// RunConsumer 实现 Consumer 接口方法, 运行消费者
+// RunConsumer 实现 Consumer 接口方法, 运行消费者.
+// Concurrency 大于 1 时启动工作池, 退出前等待已分发的消息处理完成.
 func (c *BaseConsumer[T]) RunConsumer() error {
+	c.inFlight = newInFlightSet()
+
+	if c.Concurrency > 1 {
+		c.pool = newWorkerPool(c, c.Concurrency)
+		defer c.pool.stop()
+	}
+
+	// pending 循环也会分发消息, 需要在停止工作池之前退出
+	ctx, cancel := context.WithCancel(c.Ctx)
+
+	var wg sync.WaitGroup
+
+	defer wg.Wait()
+	defer cancel()
+
 	// 启动 goroutine 持续处理 pending 消息
-	go c.startPendingLoop(c.Ctx)
+	wg.Go(func() { c.startPendingLoop(ctx) })
 
 	// 主循环：持续监听新消息
 	return c.startOnlineMessageLoop(c.Ctx)
@@ -0,0 +1,70 @@
+//
+// FilePath    : go-utils\redis\stream\consumer\core_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 消费者认领 pending 消息过滤测试
+//
+
+package consumer
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TestFilterClaimableMsgIDs_InFlight 测试认领时跳过自己已分发未处理完成的消息
+func TestFilterClaimableMsgIDs_InFlight(t *testing.T) {
+	c := &BaseConsumer[string]{ConsumerName: "c1", MaxDeliveries: 3, inFlight: newInFlightSet()}
+	c.inFlight.add("1-0")
+
+	pending := []redis.XPendingExt{
+		{ID: "1-0", Consumer: "c1", Idle: 10 * time.Second}, // 自己正在处理
+		{ID: "2-0", Consumer: "c1", Idle: 10 * time.Second}, // 自己处理失败等待重新投递
+		{ID: "3-0", Consumer: "c2", Idle: time.Second},      // 空闲时间不足
+		{ID: "4-0", Consumer: "c2", Idle: 10 * time.Second}, // 其他消费者中断的消息
+	}
+
+	got, err := c.filterClaimableMsgIDs(pending, 2*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"2-0", "4-0"}; !slices.Equal(got, want) {
+		t.Errorf("filterClaimableMsgIDs() = %v, want %v", got, want)
+	}
+
+	c.inFlight.remove("1-0")
+
+	if got, _ = c.filterClaimableMsgIDs(pending[:1], 2*time.Second); !slices.Equal(got, []string{"1-0"}) {
+		t.Errorf("处理完成后应可认领, got %v", got)
+	}
+}
+
+// TestDispatchMessage_InFlight 测试处理期间记录为已分发, 重复分发直接跳过, 处理完成后移除
+func TestDispatchMessage_InFlight(t *testing.T) {
+	calls := 0
+
+	c := &BaseConsumer[string]{inFlight: newInFlightSet()}
+	c.ProcessMessageFunc = func(c *BaseConsumer[string], message redis.XMessage) error {
+		calls++
+
+		if !c.inFlight.contains(message.ID) {
+			t.Errorf("处理期间消息 %s 应记录为已分发", message.ID)
+		}
+
+		// 处理期间再次分发同一消息
+		return c.dispatchMessage(message)
+	}
+
+	if err := c.dispatchMessage(redis.XMessage{ID: "1-0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 1 || c.inFlight.contains("1-0") {
+		t.Errorf("calls = %d, in flight = %v", calls, c.inFlight.contains("1-0"))
+	}
+}
@@ -0,0 +1,219 @@
+//
+// FilePath    : go-utils\redis\stream\consumer\dlq.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 死信队列, 超过最大投递次数的消息转移到 <stream>:dlq, 并支持重新投递
+//
+
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/jiaopengzi/go-utils/redis/stream"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// errMaxDeliveriesExceeded 消息超过最大投递次数且没有处理结果(例如处理过程中进程退出)
+var errMaxDeliveriesExceeded = errors.New("超过最大投递次数")
+
+// deliveryCount 获取消息的投递次数, 消息不在 pending 列表中时返回 0
+//   - msgID: 消息ID
+func (c *BaseConsumer[T]) deliveryCount(msgID string) (int64, error) {
+	pendingExt, err := c.Rdb.XPendingExt(c.Ctx, &redis.XPendingExtArgs{
+		Stream: c.StreamName,
+		Group:  c.GroupName,
+		Start:  msgID,
+		End:    msgID,
+		Count:  1,
+	}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+
+	if len(pendingExt) == 0 {
+		return 0, nil
+	}
+
+	return pendingExt[0].RetryCount, nil
+}
+
+// retryOrDeadLetter 处理失败的消息: 投递次数未达到 MaxDeliveries 时不签收, 由 pending 循环重新投递; 达到后转移到死信队列
+//   - message: 消息
+//   - valueStruct: 解析后的消息, 解析失败时为 nil
+//   - cause: 处理失败的错误
+func (c *BaseConsumer[T]) retryOrDeadLetter(message redis.XMessage, valueStruct *T, cause error) error {
+	deliveries, err := c.deliveryCount(message.ID)
+	if err != nil {
+		return fmt.Errorf("获取投递次数失败: %w; 处理失败: %w", err, cause)
+	}
+
+	if deliveries < c.MaxDeliveries {
+		zap.L().Warn("处理消息失败, 等待重新投递",
+			zap.String("msgID", message.ID),
+			zap.String("consumer", c.ConsumerName),
+			zap.Int64("deliveries", deliveries),
+			zap.Int64("maxDeliveries", c.MaxDeliveries),
+			zap.Error(cause),
+		)
+
+		return cause
+	}
+
+	if err = c.MoveToDLQ(message, deliveries, cause); err != nil {
+		return fmt.Errorf("转移死信队列失败: %w; 处理失败: %w", err, cause)
+	}
+
+	zap.L().Error("处理消息失败, 已转移到死信队列",
+		zap.String("msgID", message.ID),
+		zap.String("consumer", c.ConsumerName),
+		zap.Int64("deliveries", deliveries),
+		zap.Any("value", valueStruct),
+		zap.Error(cause),
+	)
+
+	return cause
+}
+
+// clearProcessing 删除消息的处理标记, 失败时只记录日志
+//   - msgID: 消息ID
+func (c *BaseConsumer[T]) clearProcessing(msgID string) {
+	if c.StateManager == nil {
+		return
+	}
+
+	if err := c.StateManager.ClearProcessing(c.StreamName, msgID); err != nil {
+		zap.L().Error("del processing flag failed", zap.Error(err), zap.String("msgID", msgID))
+	}
+}
+
+// recordFailure StateManager 实现了 MessageFailureRecorder 时记录处理失败的原因, 失败时只记录日志
+//   - msgID: 消息ID
+//   - cause: 处理失败的错误
+func (c *BaseConsumer[T]) recordFailure(msgID string, cause error) {
+	recorder, ok := c.StateManager.(MessageFailureRecorder)
+	if !ok {
+		return
+	}
+
+	if err := recorder.RecordFailure(c.StreamName, msgID, cause.Error()); err != nil {
+		zap.L().Error("record failure reason failed", zap.Error(err), zap.String("msgID", msgID))
+	}
+}
+
+// MoveToDLQ 将消息连同失败信息写入死信队列后签收.
+// 死信队列与原 stream 在集群模式下可能位于不同的槽, 不使用事务; 写入成功后才签收, 签收失败时消息可能重复进入死信队列.
+//   - message: 消息
+//   - deliveries: 投递次数
+//   - cause: 处理失败的错误
+func (c *BaseConsumer[T]) MoveToDLQ(message redis.XMessage, deliveries int64, cause error) error {
+	values := maps.Clone(message.Values)
+	values[stream.FieldDLQOriginID] = message.ID
+	values[stream.FieldDLQGroup] = c.GroupName
+	values[stream.FieldDLQConsumer] = c.ConsumerName
+	values[stream.FieldDLQDeliveries] = deliveries
+	values[stream.FieldDLQError] = cause.Error()
+	values[stream.FieldDLQFailedAt] = time.Now().Format(time.RFC3339)
+
+	err := c.Rdb.XAdd(c.Ctx, &redis.XAddArgs{
+		Stream: stream.DLQStreamName(c.StreamName),
+		ID:     "*",
+		Values: values,
+	}).Err()
+	if err != nil {
+		return err
+	}
+
+	if err = c.Rdb.XAck(c.Ctx, c.StreamName, c.GroupName, message.ID).Err(); err != nil {
+		return err
+	}
+
+	if c.StateManager != nil {
+		if err = c.StateManager.UpdateAckStatus(c.StreamName, message.ID, c.GroupName, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deadLetterExceeded 将投递次数超过 MaxDeliveries 的 pending 消息转移到死信队列.
+// 这些消息没有留下处理结果, 一般是处理过程中进程退出或超时, 继续投递可能再次导致同样的问题; 自己正在处理的消息不转移.
+//   - pendingExt: pending 消息详情
+//
+// 返回剩余未转移的 pending 消息
+func (c *BaseConsumer[T]) deadLetterExceeded(pendingExt []redis.XPendingExt) []redis.XPendingExt {
+	remaining := pendingExt[:0:0]
+
+	for _, p := range pendingExt {
+		if p.RetryCount <= c.MaxDeliveries || c.inFlight.contains(p.ID) {
+			remaining = append(remaining, p)
+			continue
+		}
+
+		messages, err := c.Rdb.XRangeN(c.Ctx, c.StreamName, p.ID, p.ID, 1).Result()
+		if err != nil {
+			zap.L().Warn("获取超过最大投递次数的消息失败", zap.String("msgID", p.ID), zap.Error(err))
+			continue
+		}
+
+		// 消息已被修剪, 只能签收
+		message := redis.XMessage{ID: p.ID, Values: map[string]any{}}
+		if len(messages) > 0 {
+			message = messages[0]
+		}
+
+		if err = c.MoveToDLQ(message, p.RetryCount, errMaxDeliveriesExceeded); err != nil {
+			zap.L().Warn("转移死信队列失败", zap.String("msgID", p.ID), zap.Error(err))
+			continue
+		}
+
+		c.recordFailure(p.ID, errMaxDeliveriesExceeded)
+		c.clearProcessing(p.ID)
+		zap.L().Error("消息超过最大投递次数, 已转移到死信队列", zap.String("msgID", p.ID), zap.Int64("deliveries", p.RetryCount))
+	}
+
+	return remaining
+}
+
+// ReprocessDLQ 将死信队列中最早的 count 条消息去掉失败信息后重新投递到原 stream, 并从死信队列删除.
+// 重新投递的消息会获得新的消息ID, 不会调用生产者的状态初始化器; 投递成功后才从死信队列删除, 删除失败时消息可能重复投递.
+//   - ctx: 上下文
+//   - rdb: Redis 客户端
+//   - streamName: 原 stream 名称
+//   - count: 最多重新投递的数量
+//
+// 返回重新投递的数量
+func ReprocessDLQ(ctx context.Context, rdb redis.UniversalClient, streamName string, count int64) (int, error) {
+	dlqName := stream.DLQStreamName(streamName)
+
+	messages, err := rdb.XRangeN(ctx, dlqName, "-", "+", count).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, message := range messages {
+		values := make(map[string]any, len(message.Values))
+		for field, value := range message.Values {
+			if !stream.IsDLQField(field) {
+				values[field] = value
+			}
+		}
+
+		if err = rdb.XAdd(ctx, &redis.XAddArgs{Stream: streamName, ID: "*", Values: values}).Err(); err != nil {
+			return i, fmt.Errorf("重新投递死信消息 %s 失败: %w", message.ID, err)
+		}
+
+		if err = rdb.XDel(ctx, dlqName, message.ID).Err(); err != nil {
+			return i + 1, fmt.Errorf("删除死信消息 %s 失败: %w", message.ID, err)
+		}
+	}
+
+	return len(messages), nil
+}
@@ -30,6 +30,9 @@ type ConsumerConfig[T any] struct {
 	Ctx                context.Context                                        // context 上下文
 	Rdb                redis.UniversalClient                                  // Redis 客户端
 	StateManager       MessageStateManager                                    // 消息状态管理器
+	MaxDeliveries      int64                                                  // 最大投递次数, 零值为不启用死信队列, 见 BaseConsumer.MaxDeliveries
+	Concurrency        int                                                    // 每个消费者的并发处理数, 零值为逐条处理, 见 BaseConsumer.Concurrency
+	PoolMetrics        *PoolMetrics                                           // 工作池指标, 例如 NewPoolMetrics(), 通过 Stats 获取各消费者的队列深度和处理耗时
 }
 
 // ManageConsumers 通过配置初始化并管理消费者
@@ -51,6 +54,9 @@ func ManageConsumers[T any](config *ConsumerConfig[T]) error {
 		Ctx:                config.Ctx,
 		Rdb:                config.Rdb,
 		StateManager:       config.StateManager,
+		MaxDeliveries:      config.MaxDeliveries,
+		Concurrency:        config.Concurrency,
+		PoolMetrics:        config.PoolMetrics,
 	}
 
 	// 创建消费者组
@@ -236,11 +242,34 @@ func HandleAndAckMessage[T any](c *BaseConsumer[T], message redis.XMessage, msgK
 
 	if err != nil {
 		zap.L().Error("parseMessageValue() failed", logFields(err)...)
-		return fmt.Errorf("解析消息失败: %w", err)
+		err = fmt.Errorf("解析消息失败: %w", err)
+		c.recordFailure(message.ID, err)
+
+		// 启用死信队列时无法解析的消息直接转移, 重新投递也无法解析
+		if c.MaxDeliveries > 0 {
+			deliveries, _ := c.deliveryCount(message.ID)
+			if errDLQ := c.MoveToDLQ(message, deliveries, err); errDLQ != nil {
+				zap.L().Error("c.MoveToDLQ() failed", logFields(errDLQ)...)
+			}
+
+			c.clearProcessing(message.ID)
+		}
+
+		return err
 	}
 
 	// 调用回调函数处理消息
 	if err = messageHandler(valueStruct); err != nil {
+		c.recordFailure(message.ID, err)
+
+		// 启用死信队列时由投递次数决定重新投递或转移到死信队列
+		if c.MaxDeliveries > 0 {
+			err = c.retryOrDeadLetter(message, valueStruct, err)
+			c.clearProcessing(message.ID)
+
+			return err
+		}
+
 		zap.L().Error("messageHandler() failed DLQ(Dead Letter Queue, 死信队列)", logFields(err)...)
 
 		// 消费失败 ACK 签收消息
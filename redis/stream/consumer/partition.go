@@ -0,0 +1,125 @@
+//
+// FilePath    : go-utils\redis\stream\consumer\partition.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 分区消费者, 每个分区只有一个消费者顺序处理, 保证同一实体的消息按顺序处理
+//
+
+package consumer
+
+import (
+	"errors"
+	"fmt"
+
+	_stream "github.com/jiaopengzi/go-utils/redis/stream"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ManagePartitionedConsumers 为每个分区创建并运行一个消费者, 与 ManagePartitionedProducers 配合使用.
+// 每个分区只有一个消费者且逐条处理, 不认领其他消费者的 pending 消息, 从而保证同一分区内的消息按写入顺序处理;
+// 多实例部署时同一分区会复用同一个消费者名称, 需保证同一时间只有一个实例运行分区消费者.
+// 启用死信队列(MaxDeliveries 大于 0)时处理失败的消息只在消费者重启时重新投递.
+//   - config: 消费者配置, StreamName 为未分区的名称, ConfigCount 会被忽略
+//   - partitions: 分区数量, 需要与分区生产者一致
+func ManagePartitionedConsumers[T any](config *ConsumerConfig[T], partitions int) error {
+	if err := _stream.ValidatePartitions(partitions); err != nil {
+		return err
+	}
+
+	for i := range partitions {
+		consumer := &BaseConsumer[T]{
+			StreamName:         _stream.PartitionStreamName(config.StreamName, i),
+			GroupName:          config.GroupName,
+			Start:              _stream.CreateStreamStart,
+			MsgKey:             config.MsgKey,
+			ProcessMessageFunc: config.ProcessMessageFunc,
+			Ctx:                config.Ctx,
+			Rdb:                config.Rdb,
+			StateManager:       config.StateManager,
+			MaxDeliveries:      config.MaxDeliveries,
+		}
+
+		if err := preparePartitionConsumer(consumer, i); err != nil {
+			return fmt.Errorf("创建分区 %d 消费者失败: %w", i, err)
+		}
+
+		go func(c *BaseConsumer[T]) {
+			if err := c.RunOrderedConsumer(); err != nil {
+				zap.L().Error("分区消费者运行错误", zap.Error(err), zap.String("consumerName", c.ConsumerName), zap.String("streamName", c.StreamName))
+			}
+		}(consumer)
+	}
+
+	return nil
+}
+
+// preparePartitionConsumer 创建分区的消费者组和消费者, 已存在消费者时复用, 以便接着处理其未签收的消息
+func preparePartitionConsumer[T any](consumer *BaseConsumer[T], partition int) error {
+	if err := consumer.CreateGroup(); err != nil {
+		return err
+	}
+
+	consumerInfos, err := consumer.GetConsumersInfo()
+	if err != nil {
+		return err
+	}
+
+	// 复用已有的消费者
+	if len(consumerInfos) > 0 {
+		consumer.ConsumerName = consumerInfos[0].Name
+
+		if len(consumerInfos) > 1 {
+			zap.L().Warn("分区存在多个消费者, 可能无法保证顺序", zap.String("streamName", consumer.StreamName), zap.Int("count", len(consumerInfos)))
+		}
+
+		return nil
+	}
+
+	return createConsumerIfNeeded(consumer, partition)
+}
+
+// RunOrderedConsumer 顺序运行消费者: 先按顺序处理自己未签收的消息, 再逐条处理新消息.
+// 不启动 pending 认领循环, 避免认领其他消费者的消息打乱顺序.
+func (c *BaseConsumer[T]) RunOrderedConsumer() error {
+	if err := c.drainOwnPending(); err != nil {
+		return err
+	}
+
+	return c.startOnlineMessageLoop(c.Ctx)
+}
+
+// drainOwnPending 按顺序处理当前消费者已读取但未签收的消息(例如上次运行中断)
+func (c *BaseConsumer[T]) drainOwnPending() error {
+	// 从 0 开始读取的是当前消费者的 pending 消息
+	start := "0"
+
+	for {
+		entries, err := c.Rdb.XReadGroup(c.Ctx, &redis.XReadGroupArgs{
+			Group:    c.GroupName,
+			Consumer: c.ConsumerName,
+			Streams:  []string{c.StreamName, start},
+			Count:    10,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				return nil
+			}
+
+			return fmt.Errorf("拉取未签收消息失败: consumer=%s; %w", c.ConsumerName, err)
+		}
+
+		if len(entries) == 0 || len(entries[0].Messages) == 0 {
+			return nil
+		}
+
+		for _, message := range entries[0].Messages {
+			if err = c.ProcessMessage(message); err != nil {
+				zap.L().Warn("处理未签收消息失败, 跳过", zap.String("msgID", message.ID), zap.String("consumer", c.ConsumerName), zap.Error(err))
+			}
+
+			start = message.ID
+		}
+	}
+}
@@ -0,0 +1,283 @@
+//
+// FilePath    : go-utils\redis\stream\consumer\pool.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 消费者工作池, 单个消费者并发处理消息, 带 panic 恢复、有序签收记录和处理指标
+//
+
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jiaopengzi/go-utils"
+	"github.com/jiaopengzi/go-utils/redis/stream"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// PoolStats 单个消费者工作池的处理统计
+type PoolStats struct {
+	Consumer   string        `json:"consumer"`    // 消费者名称
+	QueueDepth int64         `json:"queue_depth"` // 等待处理的消息数
+	InFlight   int64         `json:"in_flight"`   // 正在处理的消息数
+	Processed  int64         `json:"processed"`   // 已处理的消息数, 包括失败的
+	Failed     int64         `json:"failed"`      // 处理失败的消息数, 包括 panic 的
+	Panics     int64         `json:"panics"`      // 处理时 panic 的消息数
+	AvgLatency time.Duration `json:"avg_latency"` // 平均处理耗时
+	MaxLatency time.Duration `json:"max_latency"` // 最大处理耗时
+	Watermark  string        `json:"watermark"`   // 有序完成位置, 该ID及之前交给工作协程的消息均已处理完成
+}
+
+// poolGauge 单个消费者工作池的指标
+type poolGauge struct {
+	queueDepth   atomic.Int64
+	inFlight     atomic.Int64
+	processed    atomic.Int64
+	failed       atomic.Int64
+	panics       atomic.Int64
+	totalLatency atomic.Int64 // 纳秒
+	maxLatency   atomic.Int64 // 纳秒
+
+	tracker *ackTracker
+}
+
+// observe 记录一条消息的处理结果和耗时
+func (g *poolGauge) observe(latency time.Duration, failed, panicked bool) {
+	g.processed.Add(1)
+	g.totalLatency.Add(int64(latency))
+
+	if failed {
+		g.failed.Add(1)
+	}
+
+	if panicked {
+		g.panics.Add(1)
+	}
+
+	for {
+		current := g.maxLatency.Load()
+		if int64(latency) <= current || g.maxLatency.CompareAndSwap(current, int64(latency)) {
+			return
+		}
+	}
+}
+
+// PoolMetrics 工作池指标, 同一配置的所有消费者共用, 按消费者名称分别统计
+type PoolMetrics struct {
+	gauges sync.Map // consumerName -> *poolGauge
+}
+
+// NewPoolMetrics 创建工作池指标
+func NewPoolMetrics() *PoolMetrics {
+	return &PoolMetrics{}
+}
+
+// gauge 获取消费者的指标, 不存在时创建
+func (m *PoolMetrics) gauge(consumerName string) *poolGauge {
+	if g, ok := m.gauges.Load(consumerName); ok {
+		return g.(*poolGauge)
+	}
+
+	actual, _ := m.gauges.LoadOrStore(consumerName, &poolGauge{tracker: newAckTracker()})
+
+	return actual.(*poolGauge)
+}
+
+// Stats 获取所有消费者工作池的处理统计, 按消费者名称排序
+func (m *PoolMetrics) Stats() []PoolStats {
+	var stats []PoolStats
+
+	m.gauges.Range(func(key, value any) bool {
+		g := value.(*poolGauge)
+
+		s := PoolStats{
+			Consumer:   key.(string),
+			QueueDepth: g.queueDepth.Load(),
+			InFlight:   g.inFlight.Load(),
+			Processed:  g.processed.Load(),
+			Failed:     g.failed.Load(),
+			Panics:     g.panics.Load(),
+			MaxLatency: time.Duration(g.maxLatency.Load()),
+			Watermark:  g.tracker.Watermark(),
+		}
+
+		if s.Processed > 0 {
+			s.AvgLatency = time.Duration(g.totalLatency.Load() / s.Processed)
+		}
+
+		stats = append(stats, s)
+
+		return true
+	})
+
+	utils.SortSliceBy(stats, func(s PoolStats) string { return s.Consumer }, true)
+
+	return stats
+}
+
+// ackTracker 有序签收记录, 消息并发处理时完成顺序与分发顺序不同, 记录按分发顺序连续完成的位置
+type ackTracker struct {
+	mu        sync.Mutex
+	order     []string        // 已分发未推进的消息ID, 按分发顺序
+	done      map[string]bool // 已完成的消息ID
+	watermark string          // 有序完成位置
+}
+
+// newAckTracker 创建有序签收记录
+func newAckTracker() *ackTracker {
+	return &ackTracker{done: make(map[string]bool)}
+}
+
+// dispatch 记录分发的消息
+func (t *ackTracker) dispatch(msgID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.order = append(t.order, msgID)
+}
+
+// complete 记录完成的消息, 并推进有序完成位置
+func (t *ackTracker) complete(msgID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.done[msgID] = true
+	t.advance()
+}
+
+// abandon 移除未交给工作协程的消息, 不记录为完成, 有序完成位置不会经过该消息
+func (t *ackTracker) abandon(msgID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if i := slices.Index(t.order, msgID); i >= 0 {
+		t.order = slices.Delete(t.order, i, i+1)
+	}
+
+	t.advance()
+}
+
+// advance 按分发顺序推进有序完成位置, 调用方需持有锁
+func (t *ackTracker) advance() {
+	n := 0
+	for n < len(t.order) && t.done[t.order[n]] {
+		delete(t.done, t.order[n])
+		t.watermark = t.order[n]
+		n++
+	}
+
+	t.order = t.order[n:]
+}
+
+// Watermark 获取有序完成位置, 该ID及之前交给工作协程的消息均已处理完成, 停止时未交给工作协程的消息不计入
+func (t *ackTracker) Watermark() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.watermark
+}
+
+// workerPool 单个消费者的工作池
+type workerPool[T any] struct {
+	consumer *BaseConsumer[T]
+	jobs     chan redis.XMessage
+	gauge    *poolGauge
+	wg       sync.WaitGroup
+}
+
+// newWorkerPool 创建并启动工作池, 队列长度与并发数相同, 队列已满时分发阻塞, 从而限制拉取速度
+//   - c: 消费者
+//   - concurrency: 并发数
+func newWorkerPool[T any](c *BaseConsumer[T], concurrency int) *workerPool[T] {
+	metrics := c.PoolMetrics
+	if metrics == nil {
+		metrics = NewPoolMetrics()
+	}
+
+	p := &workerPool[T]{
+		consumer: c,
+		jobs:     make(chan redis.XMessage, concurrency),
+		gauge:    metrics.gauge(c.ConsumerName),
+	}
+
+	for range concurrency {
+		p.wg.Go(p.work)
+	}
+
+	return p
+}
+
+// submit 分发消息, 队列已满时等待, ctx 取消时返回错误, 消息保留在 pending 中等待重新投递.
+// 队列中等待处理的消息已由 dispatchMessage 记录为已分发, 不会被 pending 循环重新认领.
+//   - ctx: 上下文
+//   - message: 消息
+func (p *workerPool[T]) submit(ctx context.Context, message redis.XMessage) error {
+	p.gauge.tracker.dispatch(message.ID)
+	p.gauge.queueDepth.Add(1)
+
+	select {
+	case p.jobs <- message:
+		return nil
+	case <-ctx.Done():
+		p.gauge.queueDepth.Add(-1)
+		p.gauge.tracker.abandon(message.ID)
+
+		return ctx.Err()
+	}
+}
+
+// work 工作协程, 逐条处理队列中的消息
+func (p *workerPool[T]) work() {
+	for message := range p.jobs {
+		p.gauge.queueDepth.Add(-1)
+		p.gauge.inFlight.Add(1)
+
+		start := time.Now()
+		panicked, err := p.process(message)
+
+		p.gauge.observe(time.Since(start), err != nil, panicked)
+		p.gauge.inFlight.Add(-1)
+		p.gauge.tracker.complete(message.ID)
+		p.consumer.inFlight.remove(message.ID)
+
+		if err != nil {
+			zap.L().Warn("工作池处理消息失败",
+				zap.String("msgID", message.ID),
+				zap.String("traceID", stream.TraceID(message)),
+				zap.String("consumer", p.consumer.ConsumerName),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// process 处理一条消息, panic 时恢复并清除处理标记, 消息不签收, 保留在 pending 中等待重新投递
+func (p *workerPool[T]) process(message redis.XMessage) (panicked bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			zap.L().Error("处理消息 panic", zap.String("msgID", message.ID), zap.Any("panic", r), zap.ByteString("stack", debug.Stack()))
+
+			panicked = true
+			err = fmt.Errorf("处理消息 panic: %v", r)
+
+			p.consumer.recordFailure(message.ID, err)
+			p.consumer.clearProcessing(message.ID)
+		}
+	}()
+
+	return false, p.consumer.ProcessMessage(message)
+}
+
+// stop 停止分发并等待已分发的消息处理完成
+func (p *workerPool[T]) stop() {
+	close(p.jobs)
+	p.wg.Wait()
+}
@@ -0,0 +1,68 @@
+//
+// FilePath    : go-utils\redis\stream\consumer\pool_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 消费者工作池有序签收记录测试
+//
+
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TestAckTracker 测试乱序完成和放弃未处理的消息时有序完成位置的推进
+func TestAckTracker(t *testing.T) {
+	tracker := newAckTracker()
+
+	for _, id := range []string{"1-0", "2-0", "3-0", "4-0"} {
+		tracker.dispatch(id)
+	}
+
+	steps := []struct {
+		name    string
+		apply   func()
+		wantPos string
+	}{
+		{"后分发的先完成", func() { tracker.complete("3-0") }, ""},
+		{"放弃最早的消息", func() { tracker.abandon("1-0") }, ""},
+		{"补齐空缺后连续推进", func() { tracker.complete("2-0") }, "3-0"},
+		{"放弃的消息不推进位置", func() { tracker.abandon("4-0") }, "3-0"},
+	}
+
+	for _, step := range steps {
+		step.apply()
+
+		if got := tracker.Watermark(); got != step.wantPos {
+			t.Fatalf("%s: Watermark() = %q, want %q", step.name, got, step.wantPos)
+		}
+	}
+}
+
+// TestWorkerPool_SubmitCanceled 测试停止时未交给工作协程的消息不计入有序完成位置, 并移除已分发记录
+func TestWorkerPool_SubmitCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &BaseConsumer[string]{ConsumerName: "c1", Ctx: ctx, inFlight: newInFlightSet()}
+
+	// 没有工作协程且队列长度为 0, 分发只能等待 ctx 取消
+	c.pool = &workerPool[string]{consumer: c, jobs: make(chan redis.XMessage), gauge: NewPoolMetrics().gauge("c1")}
+
+	if err := c.dispatchMessage(redis.XMessage{ID: "1-0"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("dispatchMessage() error = %v, want context.Canceled", err)
+	}
+
+	if got := c.pool.gauge.tracker.Watermark(); got != "" {
+		t.Errorf("Watermark() = %q, want empty", got)
+	}
+
+	if c.pool.gauge.queueDepth.Load() != 0 || c.inFlight.contains("1-0") {
+		t.Errorf("queue depth = %d, in flight = %v", c.pool.gauge.queueDepth.Load(), c.inFlight.contains("1-0"))
+	}
+}
@@ -0,0 +1,37 @@
+//
+// FilePath    : go-utils\redis\stream\dlq.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 死信队列(DLQ, Dead Letter Queue) stream 命名及失败信息字段.
+//
+
+package stream
+
+import "strings"
+
+// DLQSuffix 死信队列 stream 名称后缀, 例如 stream:order:dlq
+const DLQSuffix = ":dlq"
+
+// 死信消息在原消息字段之外追加的失败信息字段, 均以 DLQFieldPrefix 开头, 重新投递时会被去掉
+const (
+	DLQFieldPrefix     = "dlq_"           // 失败信息字段前缀
+	FieldDLQOriginID   = "dlq_origin_id"  // 原消息ID
+	FieldDLQGroup      = "dlq_group"      // 处理失败的消费者组
+	FieldDLQConsumer   = "dlq_consumer"   // 最后一次处理失败的消费者
+	FieldDLQDeliveries = "dlq_deliveries" // 投递次数
+	FieldDLQError      = "dlq_error"      // 最后一次处理失败的错误信息
+	FieldDLQFailedAt   = "dlq_failed_at"  // 进入死信队列的时间, RFC3339
+)
+
+// DLQStreamName 获取死信队列 stream 名称
+//   - streamName: stream 名称
+func DLQStreamName(streamName string) string {
+	return streamName + DLQSuffix
+}
+
+// IsDLQField 判断消息字段是否为死信失败信息字段
+//   - field: 字段名
+func IsDLQField(field string) bool {
+	return strings.HasPrefix(field, DLQFieldPrefix)
+}
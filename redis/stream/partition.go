@@ -0,0 +1,51 @@
+//
+// FilePath    : go-utils\redis\stream\partition.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : stream 分区, 同一实体(如订单ID)的消息写入同一个分区, 保证按顺序消费.
+//
+
+package stream
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// 分区相关常量
+const (
+	PartitionSeparator = ":p" // 分区 stream 名称分隔符, 例如 stream:order:p3
+	PartitionMaxCount  = 256  // 最大分区数量
+	PartitionMinCount  = 1    // 最小分区数量
+)
+
+// PartitionStreamName 获取分区 stream 名称
+//   - streamName: stream 名称
+//   - partition: 分区序号, 从 0 开始
+func PartitionStreamName(streamName string, partition int) string {
+	return fmt.Sprintf("%s%s%d", streamName, PartitionSeparator, partition)
+}
+
+// PartitionOf 根据分区键 key 计算分区序号, 相同的 key 总是得到相同的分区
+//   - key: 分区键, 例如订单ID
+//   - partitions: 分区数量
+func PartitionOf(key string, partitions int) int {
+	if partitions <= PartitionMinCount {
+		return 0
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return int(h.Sum32() % uint32(partitions))
+}
+
+// ValidatePartitions 校验分区数量
+func ValidatePartitions(partitions int) error {
+	if partitions < PartitionMinCount || partitions > PartitionMaxCount {
+		return fmt.Errorf("分区数量 %d 不合法, 范围为 [%d, %d]", partitions, PartitionMinCount, PartitionMaxCount)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+//
+// FilePath    : go-utils\redis\stream\partition_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : stream 分区测试
+//
+
+package stream
+
+import (
+	"strconv"
+	"testing"
+)
+
+// TestPartitionOf_Deterministic 相同的 key 总是得到相同的分区, 分区序号在 [0, partitions) 之间
+func TestPartitionOf_Deterministic(t *testing.T) {
+	for _, partitions := range []int{2, 3, 8, PartitionMaxCount} {
+		for i := range 1000 {
+			key := "order:" + strconv.Itoa(i)
+
+			got := PartitionOf(key, partitions)
+			if got < 0 || got >= partitions {
+				t.Fatalf("PartitionOf(%q, %d) = %d, 超出范围", key, partitions, got)
+			}
+
+			if again := PartitionOf(key, partitions); again != got {
+				t.Fatalf("PartitionOf(%q, %d) = %d, 再次计算 = %d", key, partitions, got, again)
+			}
+		}
+	}
+
+	// 固定的取值, 哈希算法变化会导致升级前后同一实体的消息写入不同的分区, 破坏顺序
+	pinned := []struct {
+		key        string
+		partitions int
+		want       int
+	}{
+		{"order:1", 8, 2},
+		{"order:2", 8, 7},
+		{"order:1", 256, 146},
+		{"user:42", 256, 130},
+	}
+
+	for _, tt := range pinned {
+		if got := PartitionOf(tt.key, tt.partitions); got != tt.want {
+			t.Errorf("PartitionOf(%q, %d) = %d, want %d", tt.key, tt.partitions, got, tt.want)
+		}
+	}
+}
+
+// TestPartitionOf_Single 分区数量不大于 1 时总是分区 0
+func TestPartitionOf_Single(t *testing.T) {
+	for _, partitions := range []int{-1, 0, 1} {
+		if got := PartitionOf("order:1", partitions); got != 0 {
+			t.Errorf("PartitionOf(order:1, %d) = %d, want 0", partitions, got)
+		}
+	}
+}
+
+// TestPartitionOf_Distribution 连续的 key 均匀分布到各个分区
+func TestPartitionOf_Distribution(t *testing.T) {
+	const keys = 100000
+
+	for _, partitions := range []int{4, 8, 16, 64} {
+		counts := make([]int, partitions)
+		for i := range keys {
+			counts[PartitionOf("order:"+strconv.Itoa(i), partitions)]++
+		}
+
+		// 每个分区与平均值的偏差不超过 10%
+		mean := keys / partitions
+		for p, n := range counts {
+			if n < mean*9/10 || n > mean*11/10 {
+				t.Errorf("%d 个分区时分区 %d 有 %d 个 key, 平均 %d", partitions, p, n, mean)
+			}
+		}
+	}
+}
+
+func TestValidatePartitions(t *testing.T) {
+	tests := []struct {
+		partitions int
+		wantErr    bool
+	}{
+		{0, true},
+		{PartitionMinCount, false},
+		{PartitionMaxCount, false},
+		{PartitionMaxCount + 1, true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidatePartitions(tt.partitions); (err != nil) != tt.wantErr {
+			t.Errorf("ValidatePartitions(%d) error = %v, wantErr %v", tt.partitions, err, tt.wantErr)
+		}
+	}
+}
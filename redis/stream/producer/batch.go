@@ -0,0 +1,118 @@
+//
+// FilePath    : go-utils\redis\stream\producer\batch.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 批量生产, 使用 pipeline 一次网络往返写入多条消息.
+//
+
+package producer
+
+import (
+	"context"
+
+	_stream "github.com/jiaopengzi/go-utils/redis/stream"
+	"github.com/redis/go-redis/v9"
+)
+
+// BatchProducer 支持批量添加消息的生产者
+type BatchProducer[T any] interface {
+	Producer[T]
+
+	// AddMessagesWithContext 批量添加消息到 stream, 返回的 StreamInfo 与 values 一一对应
+	AddMessagesWithContext(ctx context.Context, values []T) ([]*StreamInfo, error)
+}
+
+// 确保生产者实现了 BatchProducer 接口
+var (
+	_ BatchProducer[any] = (*BaseProducer[any])(nil)
+	_ BatchProducer[any] = (*PartitionedProducer[any])(nil)
+)
+
+// AddMessagesWithContext 实现 BatchProducer 接口方法, 使用 pipeline 批量添加消息到 stream.
+// 先全部序列化并检查积压, 任一失败时不写入任何消息; pipeline 中部分命令失败时返回第一个错误, 其他消息可能已写入.
+//   - ctx: 上下文, 有链路追踪ID时写入每条消息的 trace_id 字段
+//   - values: 消息
+func (p *BaseProducer[T]) AddMessagesWithContext(ctx context.Context, values []T) ([]*StreamInfo, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	args := make([]*redis.XAddArgs, len(values))
+
+	for i, value := range values {
+		a, err := p.xAddArgs(ctx, value)
+		if err != nil {
+			return nil, err
+		}
+
+		args[i] = a
+	}
+
+	if err := p.checkBackpressure(ctx, p.Rdb, p.StreamName, int64(len(values))); err != nil {
+		return nil, err
+	}
+
+	cmds := make([]*redis.StringCmd, len(args))
+
+	_, err := p.Rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, a := range args {
+			cmds[i] = pipe.XAdd(ctx, a)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*StreamInfo, len(cmds))
+
+	for i, cmd := range cmds {
+		msgID := cmd.Val()
+
+		// 如果有状态初始化器, 初始化消息状态
+		if p.StateInitializer != nil {
+			if err = p.StateInitializer.InitMessageStatus(p.StreamName, msgID); err != nil {
+				return nil, err
+			}
+		}
+
+		infos[i] = &StreamInfo{Name: p.StreamName, ID: msgID}
+	}
+
+	return infos, nil
+}
+
+// AddMessagesWithContext 实现 BatchProducer 接口方法, 根据 KeyFunc 将消息按分区分组后批量添加到对应的分区 stream.
+// 分区之间依次写入, 某个分区失败时返回错误, 之前的分区已写入.
+//   - ctx: 上下文
+//   - values: 消息
+func (p *PartitionedProducer[T]) AddMessagesWithContext(ctx context.Context, values []T) ([]*StreamInfo, error) {
+	// 分区序号 -> 消息在 values 中的下标
+	indexes := make(map[int][]int)
+	for i, value := range values {
+		partition := _stream.PartitionOf(p.KeyFunc(value), len(p.Producers))
+		indexes[partition] = append(indexes[partition], i)
+	}
+
+	infos := make([]*StreamInfo, len(values))
+
+	for partition, idx := range indexes {
+		batch := make([]T, len(idx))
+		for j, i := range idx {
+			batch[j] = values[i]
+		}
+
+		partInfos, err := p.Producers[partition].AddMessagesWithContext(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, i := range idx {
+			infos[i] = partInfos[j]
+		}
+	}
+
+	return infos, nil
+}
@@ -13,6 +13,8 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/jiaopengzi/go-utils"
+	_stream "github.com/jiaopengzi/go-utils/redis/stream"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -44,24 +46,29 @@ type BaseProducer[T any] struct {
 	Ctx              context.Context         // context 上下文
 	Rdb              redis.UniversalClient   // Redis 客户端
 	StateInitializer MessageStateInitializer // 状态初始化器
+	Options                                  // 可选配置
 }
 
 // AddMessageToStream 实现 Producer 接口方法, 添加消息到 stream, 并返回消息 ID
 func (p *BaseProducer[T]) AddMessageToStream(value T) (*StreamInfo, error) {
-	// 将 value 转换为 json 字符串
-	jsonBytes, err := json.Marshal(value)
+	return p.AddMessageWithContext(p.Ctx, value)
+}
+
+// AddMessageWithContext 使用 ctx 添加消息到 stream, ctx 中有链路追踪ID时一并写入消息的 trace_id 字段.
+// 设置了积压阈值且超过时返回 ErrBackpressure, 消息不会写入.
+//   - ctx: 上下文, 例如定时任务的执行上下文
+//   - value: 消息
+func (p *BaseProducer[T]) AddMessageWithContext(ctx context.Context, value T) (*StreamInfo, error) {
+	args, err := p.xAddArgs(ctx, value)
 	if err != nil {
 		return nil, err
 	}
 
-	// jsonString := string(jsonBytes)
-	// fmt.Printf("==>Producer jsonString:%v\n", jsonString)
+	if err = p.checkBackpressure(ctx, p.Rdb, p.StreamName, 1); err != nil {
+		return nil, err
+	}
 
-	msgID, err := p.Rdb.XAdd(p.Ctx, &redis.XAddArgs{
-		Stream: p.StreamName,                        // stream 名称
-		ID:     "*",                                 // 自动创建 ID
-		Values: map[string]any{p.MsgKey: jsonBytes}, // 消息内容
-	}).Result()
+	msgID, err := p.Rdb.XAdd(ctx, args).Result()
 	if err != nil {
 		return nil, err
 	}
@@ -73,15 +80,32 @@ func (p *BaseProducer[T]) AddMessageToStream(value T) (*StreamInfo, error) {
 		}
 	}
 
-	// 如果设置了最大消息长度,则进行修剪
-	if p.MaxLength > 0 {
-		if err = p.Rdb.XTrimMaxLen(p.Ctx, p.StreamName, p.MaxLength).Err(); err != nil {
-			return nil, err
-		}
-	}
-
 	return &StreamInfo{
 		Name: p.StreamName,
 		ID:   msgID,
 	}, nil
 }
+
+// xAddArgs 将消息转换为 XADD 参数, 设置了最大消息长度时在写入的同时修剪
+//   - ctx: 上下文, 有链路追踪ID时写入消息的 trace_id 字段
+//   - value: 消息
+func (p *BaseProducer[T]) xAddArgs(ctx context.Context, value T) (*redis.XAddArgs, error) {
+	// 将 value 转换为 json 字符串
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]any{p.MsgKey: jsonBytes} // 消息内容
+	if traceID := utils.TraceIDFromContext(ctx); traceID != "" {
+		values[_stream.FieldTraceID] = traceID
+	}
+
+	return &redis.XAddArgs{
+		Stream: p.StreamName, // stream 名称
+		MaxLen: p.MaxLength,  // 最大消息数量, 零值为不修剪
+		Approx: p.ApproxTrim, // 是否近似修剪
+		ID:     "*",          // 自动创建 ID
+		Values: values,
+	}, nil
+}
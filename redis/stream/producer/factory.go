@@ -20,9 +20,10 @@ import (
 //   - maxLength: 最大消息数量
 //   - rdb: Redis 客户端
 //   - initializer: 消息状态初始化器
+//   - opts: 可选配置, 例如 WithApproxTrim、WithMaxPending
 //
-// 返回 Producer 接口
-func ManageProducers[T any](msgKey string, maxLength int64, rdb redis.UniversalClient, initializer MessageStateInitializer) Producer[T] {
+// 返回 Producer 接口, 需要批量写入时可断言为 BatchProducer
+func ManageProducers[T any](msgKey string, maxLength int64, rdb redis.UniversalClient, initializer MessageStateInitializer, opts ...Option) Producer[T] {
 	return &BaseProducer[T]{
 		StreamName:       _stream.NamePrefix + msgKey, // 消息队列名称
 		MsgKey:           msgKey,                      // 消息的 key 用于解析消息.
@@ -30,5 +31,6 @@ func ManageProducers[T any](msgKey string, maxLength int64, rdb redis.UniversalC
 		Ctx:              context.Background(),        // 默认使用背景上下文
 		Rdb:              rdb,                         // Redis 客户端
 		StateInitializer: initializer,                 // 状态初始化器
+		Options:          newOptions(opts),            // 可选配置
 	}
 }
@@ -0,0 +1,117 @@
+//
+// FilePath    : go-utils\redis\stream\producer\options.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 生产者可选配置, 近似修剪和积压背压.
+//
+
+package producer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrBackpressure stream 积压超过阈值, 生产者拒绝写入, 调用方可稍后重试或降级处理
+var ErrBackpressure = errors.New("stream 积压超过阈值")
+
+// Options 生产者可选配置
+type Options struct {
+	ApproxTrim bool  // 修剪时是否使用近似修剪(MAXLEN ~), 按宏节点整块删除, 性能更好, 实际长度可能略大于 MaxLength
+	MaxBacklog int64 // 未消费消息数阈值, 写入后任一消费者组的积压将超过该值时返回 ErrBackpressure, 零值为不检查
+	MaxPending int64 // 待确认消息数阈值, 任一消费者组的待确认消息数达到该值时返回 ErrBackpressure, 零值为不检查
+}
+
+// Option 定义生产者的可选配置函数类型
+type Option func(*Options)
+
+// WithApproxTrim 使用近似修剪, 适合 MaxLength 较大、写入频繁的 stream
+func WithApproxTrim() Option {
+	return func(o *Options) {
+		o.ApproxTrim = true
+	}
+}
+
+// WithMaxBacklog 设置未消费消息数阈值, 避免消费跟不上时无限积压.
+// 消费者组的积压为未投递消息数(XINFO GROUPS 的 lag, 需要 redis 7.0+)与已投递未确认消息数之和,
+// 与 stream 长度无关, 签收后不删除消息(MaxLength 为 0)时也不会误判; lag 无法确定时只计算已投递未确认的消息.
+// stream 还没有消费者组时按 stream 长度计算.
+//   - n: 未消费消息数阈值
+func WithMaxBacklog(n int64) Option {
+	return func(o *Options) {
+		o.MaxBacklog = n
+	}
+}
+
+// WithMaxPending 设置待确认消息数阈值, 消费者处理变慢或宕机时生产者及时感知
+//   - n: 待确认消息数阈值
+func WithMaxPending(n int64) Option {
+	return func(o *Options) {
+		o.MaxPending = n
+	}
+}
+
+// newOptions 根据可选配置函数创建配置
+func newOptions(opts []Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// checkBackpressure 检查 stream 积压, 写入 n 条消息后积压超过 MaxBacklog 或待确认消息数达到 MaxPending 时返回 ErrBackpressure
+//   - ctx: 上下文
+//   - rdb: Redis 客户端
+//   - streamName: stream 名称
+//   - n: 本次写入的消息数量
+func (o *Options) checkBackpressure(ctx context.Context, rdb redis.UniversalClient, streamName string, n int64) error {
+	if o.MaxBacklog <= 0 && o.MaxPending <= 0 {
+		return nil
+	}
+
+	length, err := rdb.XLen(ctx, streamName).Result()
+	if err != nil {
+		return err
+	}
+
+	// stream 不存在时没有消费者组, XINFO GROUPS 会返回错误
+	var groups []redis.XInfoGroup
+	if length > 0 {
+		if groups, err = rdb.XInfoGroups(ctx, streamName).Result(); err != nil {
+			return err
+		}
+	}
+
+	return o.exceeded(streamName, length, groups, n)
+}
+
+// exceeded 根据 stream 长度和消费者组状态判断写入 n 条消息后是否超过阈值
+//   - streamName: stream 名称
+//   - length: stream 长度
+//   - groups: 消费者组状态
+//   - n: 本次写入的消息数量
+func (o *Options) exceeded(streamName string, length int64, groups []redis.XInfoGroup, n int64) error {
+	if o.MaxBacklog > 0 && len(groups) == 0 && length+n > o.MaxBacklog {
+		return fmt.Errorf("%w: stream %s 没有消费者组, 长度 %d, 本次写入 %d, 阈值 %d", ErrBackpressure, streamName, length, n, o.MaxBacklog)
+	}
+
+	for _, g := range groups {
+		if o.MaxPending > 0 && g.Pending >= o.MaxPending {
+			return fmt.Errorf("%w: stream %s 消费者组 %s 待确认消息 %d, 阈值 %d", ErrBackpressure, streamName, g.Name, g.Pending, o.MaxPending)
+		}
+
+		// lag 为 -1 时无法确定未投递消息数
+		backlog := g.Pending + max(g.Lag, 0)
+		if o.MaxBacklog > 0 && backlog+n > o.MaxBacklog {
+			return fmt.Errorf("%w: stream %s 消费者组 %s 积压 %d, 本次写入 %d, 阈值 %d", ErrBackpressure, streamName, g.Name, backlog, n, o.MaxBacklog)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+//
+// FilePath    : go-utils\redis\stream\producer\options_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 生产者积压背压测试
+//
+
+package producer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestOptions_Exceeded(t *testing.T) {
+	group := func(pending, lag int64) redis.XInfoGroup {
+		return redis.XInfoGroup{Name: "group", Pending: pending, Lag: lag}
+	}
+
+	tests := []struct {
+		name   string
+		opts   Options
+		length int64
+		groups []redis.XInfoGroup
+		n      int64
+		want   bool
+	}{
+		{"不检查", Options{}, 1000, []redis.XInfoGroup{group(1000, 1000)}, 1, false},
+		{"没有消费者组按长度计算", Options{MaxBacklog: 10}, 9, nil, 1, false},
+		{"没有消费者组超过长度", Options{MaxBacklog: 10}, 9, nil, 2, true},
+		{"已签收的消息不计入积压", Options{MaxBacklog: 10}, 1000, []redis.XInfoGroup{group(3, 5)}, 2, false},
+		{"未投递和待确认之和超过阈值", Options{MaxBacklog: 10}, 1000, []redis.XInfoGroup{group(3, 5)}, 3, true},
+		{"任一消费者组超过阈值", Options{MaxBacklog: 10}, 1000, []redis.XInfoGroup{group(0, 0), group(0, 10)}, 1, true},
+		{"lag 无法确定时只计算待确认", Options{MaxBacklog: 10}, 1000, []redis.XInfoGroup{group(5, -1)}, 5, false},
+		{"待确认未达到阈值", Options{MaxPending: 5}, 1000, []redis.XInfoGroup{group(4, 100)}, 100, false},
+		{"待确认达到阈值", Options{MaxPending: 5}, 1000, []redis.XInfoGroup{group(5, 0)}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.exceeded("stream", tt.length, tt.groups, tt.n)
+			if got := errors.Is(err, ErrBackpressure); got != tt.want || (err != nil && !got) {
+				t.Errorf("exceeded() error = %v, want backpressure %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// newTestProducer 创建使用 miniredis 的生产者
+func newTestProducer(t *testing.T, opts ...Option) *BaseProducer[int] {
+	t.Helper()
+
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	return &BaseProducer[int]{
+		StreamName: "stream:test",
+		MsgKey:     "test",
+		Ctx:        context.Background(),
+		Rdb:        rdb,
+		Options:    newOptions(opts),
+	}
+}
+
+// TestBaseProducer_MaxBacklog 没有消费者组时按 stream 长度计算积压, 单条和批量写入超过阈值时都不写入
+func TestBaseProducer_MaxBacklog(t *testing.T) {
+	p := newTestProducer(t, WithMaxBacklog(3))
+	ctx := context.Background()
+
+	infos, err := p.AddMessagesWithContext(ctx, []int{1, 2})
+	if err != nil || len(infos) != 2 || infos[0].ID == "" || infos[0].ID == infos[1].ID {
+		t.Fatalf("AddMessagesWithContext() = %+v, %v", infos, err)
+	}
+
+	// 批量写入后超过阈值, 整批不写入
+	if _, err = p.AddMessagesWithContext(ctx, []int{3, 4}); !errors.Is(err, ErrBackpressure) {
+		t.Fatalf("AddMessagesWithContext() error = %v, want ErrBackpressure", err)
+	}
+
+	if _, err = p.AddMessageWithContext(ctx, 3); err != nil {
+		t.Fatalf("AddMessageWithContext() error = %v", err)
+	}
+
+	if _, err = p.AddMessageWithContext(ctx, 4); !errors.Is(err, ErrBackpressure) {
+		t.Fatalf("AddMessageWithContext() error = %v, want ErrBackpressure", err)
+	}
+
+	if length := p.Rdb.XLen(ctx, p.StreamName).Val(); length != 3 {
+		t.Errorf("stream length = %d, want 3", length)
+	}
+}
+
+// TestBaseProducer_MaxPending 消费者组的待确认消息达到阈值时拒绝写入, 签收后恢复
+func TestBaseProducer_MaxPending(t *testing.T) {
+	p := newTestProducer(t, WithMaxPending(2))
+	ctx := context.Background()
+
+	if err := p.Rdb.XGroupCreateMkStream(ctx, p.StreamName, "group", "0").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := p.AddMessagesWithContext(ctx, []int{1, 2, 3}); err != nil {
+		t.Fatalf("AddMessagesWithContext() error = %v", err)
+	}
+
+	// 投递两条, 未签收
+	streams, err := p.Rdb.XReadGroup(ctx, &redis.XReadGroupArgs{Group: "group", Consumer: "c1", Streams: []string{p.StreamName, ">"}, Count: 2}).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = p.AddMessagesWithContext(ctx, []int{4}); !errors.Is(err, ErrBackpressure) {
+		t.Fatalf("AddMessagesWithContext() error = %v, want ErrBackpressure", err)
+	}
+
+	if err = p.Rdb.XAck(ctx, p.StreamName, "group", streams[0].Messages[0].ID).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = p.AddMessageWithContext(ctx, 4); err != nil {
+		t.Errorf("签收后 AddMessageWithContext() error = %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+//
+// FilePath    : go-utils\redis\stream\producer\partition.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 分区生产者, 按分区键将消息写入确定的分区 stream.
+//
+
+package producer
+
+import (
+	"context"
+
+	_stream "github.com/jiaopengzi/go-utils/redis/stream"
+	"github.com/redis/go-redis/v9"
+)
+
+// PartitionedProducer 分区生产者, 相同分区键的消息写入同一个分区 stream, 配合分区消费者保证同一实体的消息按顺序处理
+type PartitionedProducer[T any] struct {
+	Producers []*BaseProducer[T]   // 每个分区的生产者, 下标为分区序号
+	KeyFunc   func(value T) string // 获取消息的分区键, 例如订单ID
+}
+
+// AddMessageToStream 实现 Producer 接口方法, 根据 KeyFunc 获取分区键后添加消息到对应的分区 stream
+func (p *PartitionedProducer[T]) AddMessageToStream(value T) (*StreamInfo, error) {
+	return p.AddMessageWithKey(p.KeyFunc(value), value)
+}
+
+// AddMessageWithKey 使用指定的分区键添加消息到对应的分区 stream
+//   - key: 分区键
+//   - value: 消息
+func (p *PartitionedProducer[T]) AddMessageWithKey(key string, value T) (*StreamInfo, error) {
+	return p.Producers[_stream.PartitionOf(key, len(p.Producers))].AddMessageToStream(value)
+}
+
+// AddMessageWithContext 根据 KeyFunc 获取分区键后使用 ctx 添加消息到对应的分区 stream, 规则见 BaseProducer.AddMessageWithContext
+//   - ctx: 上下文
+//   - value: 消息
+func (p *PartitionedProducer[T]) AddMessageWithContext(ctx context.Context, value T) (*StreamInfo, error) {
+	return p.Producers[_stream.PartitionOf(p.KeyFunc(value), len(p.Producers))].AddMessageWithContext(ctx, value)
+}
+
+// ManagePartitionedProducers 通过配置初始化分区生产者
+//   - msgKey: 消息键
+//   - partitions: 分区数量, 需要与分区消费者一致
+//   - maxLength: 每个分区的最大消息数量
+//   - rdb: Redis 客户端
+//   - initializer: 消息状态初始化器
+//   - keyFunc: 获取消息的分区键
+//   - opts: 可选配置, 每个分区单独检查积压
+//
+// 返回 Producer 接口, 需要批量写入时可断言为 BatchProducer
+func ManagePartitionedProducers[T any](msgKey string, partitions int, maxLength int64, rdb redis.UniversalClient,
+	initializer MessageStateInitializer, keyFunc func(value T) string, opts ...Option) (Producer[T], error) {
+	if err := _stream.ValidatePartitions(partitions); err != nil {
+		return nil, err
+	}
+
+	options := newOptions(opts)
+	producers := make([]*BaseProducer[T], partitions)
+
+	for i := range partitions {
+		producers[i] = &BaseProducer[T]{
+			StreamName:       _stream.PartitionStreamName(_stream.NamePrefix+msgKey, i), // 分区消息队列名称
+			MsgKey:           msgKey,                                                    // 消息的 key 用于解析消息.
+			MaxLength:        maxLength,                                                 // 最大消息数量
+			Ctx:              context.Background(),                                      // 默认使用背景上下文
+			Rdb:              rdb,                                                       // Redis 客户端
+			StateInitializer: initializer,                                               // 状态初始化器
+			Options:          options,                                                   // 可选配置
+		}
+	}
+
+	return &PartitionedProducer[T]{Producers: producers, KeyFunc: keyFunc}, nil
+}
@@ -0,0 +1,229 @@
+//
+// FilePath    : go-utils\redis\stream\state\redis.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 基于 redis 缓存的消息状态管理, 记录处理标记、签收状态和失败原因.
+//
+
+// Package state redis stream 消息状态管理的默认实现
+package state
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jiaopengzi/go-utils/redis/cache"
+	"github.com/jiaopengzi/go-utils/redis/stream/consumer"
+	"github.com/jiaopengzi/go-utils/redis/stream/producer"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// 缓存键的用途
+const (
+	statusPurpose     cache.Purpose = "stream_state"      // 消息状态 hash
+	processingPurpose cache.Purpose = "stream_processing" // 处理标记
+)
+
+// 消息状态 hash 的字段, 每个消费者组的签收状态使用 FieldAckPrefix + 组名称
+const (
+	FieldStatus      = "status"     // 消息状态, 见 Status 常量
+	FieldCreatedAt   = "created_at" // 生产时间, RFC3339
+	FieldError       = "error"      // 最后一次处理失败的原因
+	FieldFailedAt    = "failed_at"  // 最后一次处理失败的时间, RFC3339
+	FieldAckPrefix   = "ack:"       // 消费者组签收状态字段前缀, 值为 Status 常量
+	FieldAckedPrefix = "acked_at:"  // 消费者组签收时间字段前缀, RFC3339
+)
+
+// 消息状态
+const (
+	StatusPending = "pending" // 已生产未签收
+	StatusSuccess = "success" // 处理成功并签收
+	StatusFailed  = "failed"  // 处理失败并签收(或进入死信队列)
+)
+
+// MessageStatus 消息状态
+type MessageStatus struct {
+	Status     string            `json:"status"`     // 最后一次签收的状态, 见 Status 常量
+	CreatedAt  string            `json:"created_at"` // 生产时间
+	Acks       map[string]string `json:"acks"`       // 消费者组 -> 签收状态
+	Error      string            `json:"error"`      // 最后一次处理失败的原因
+	FailedAt   string            `json:"failed_at"`  // 最后一次处理失败的时间
+	Processing string            `json:"processing"` // 正在处理的消费者名称, 为空表示没有在处理
+}
+
+// 确保 RedisMessageStateManager 实现了状态管理相关接口
+var (
+	_ consumer.MessageStateManager     = (*RedisMessageStateManager)(nil)
+	_ consumer.MessageFailureRecorder  = (*RedisMessageStateManager)(nil)
+	_ producer.MessageStateInitializer = (*RedisMessageStateManager)(nil)
+)
+
+// RedisMessageStateManager 基于 redis 缓存的消息状态管理, 可同时配置为生产者的 StateInitializer 和消费者的 StateManager.
+// 每条消息的状态保存在一个 hash 中, 每次写入时刷新有效期; 处理标记单独保存并使用较短的有效期,
+// 消费者处理过程中退出时标记自动过期, 消息可以被其他消费者认领.
+type RedisMessageStateManager struct {
+	Ctx           context.Context // context 上下文, 接口方法没有上下文参数时使用
+	Cache         *cache.Client   // 缓存客户端
+	StatusTTL     time.Duration   // 消息状态的有效期
+	ProcessingTTL time.Duration   // 处理标记的有效期, 需要大于消息的最长处理时间
+}
+
+// Option 定义消息状态管理的可选配置函数类型
+type Option func(*RedisMessageStateManager)
+
+// WithStatusTTL 设置消息状态的有效期, 默认 24 小时
+//   - ttl: 有效期
+func WithStatusTTL(ttl time.Duration) Option {
+	return func(m *RedisMessageStateManager) {
+		m.StatusTTL = ttl
+	}
+}
+
+// WithProcessingTTL 设置处理标记的有效期, 默认 5 分钟
+//   - ttl: 有效期
+func WithProcessingTTL(ttl time.Duration) Option {
+	return func(m *RedisMessageStateManager) {
+		m.ProcessingTTL = ttl
+	}
+}
+
+// NewRedisMessageStateManager 创建基于 redis 缓存的消息状态管理
+//   - c: 缓存客户端
+//   - opts: 可选配置
+func NewRedisMessageStateManager(c *cache.Client, opts ...Option) *RedisMessageStateManager {
+	m := &RedisMessageStateManager{
+		Ctx:           context.Background(),
+		Cache:         c,
+		StatusTTL:     24 * time.Hour,
+		ProcessingTTL: 5 * time.Minute,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// statusKey 消息状态 hash 的完整 key
+func (m *RedisMessageStateManager) statusKey(streamName, msgID string) string {
+	return m.Cache.Key(cache.GenerateKey(statusPurpose, streamName, msgID))
+}
+
+// processingKey 处理标记的 key
+func (m *RedisMessageStateManager) processingKey(streamName, msgID string) string {
+	return cache.GenerateKey(processingPurpose, streamName, msgID)
+}
+
+// setStatus 写入消息状态的字段并刷新有效期
+func (m *RedisMessageStateManager) setStatus(streamName, msgID string, fields map[string]any) error {
+	key := m.statusKey(streamName, msgID)
+
+	_, err := m.Cache.Client.Pipelined(m.Ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(m.Ctx, key, fields)
+		pipe.Expire(m.Ctx, key, m.StatusTTL)
+
+		return nil
+	})
+
+	return err
+}
+
+// InitMessageStatus 实现 producer.MessageStateInitializer 接口方法, 记录消息为已生产未签收
+func (m *RedisMessageStateManager) InitMessageStatus(streamName, msgID string) error {
+	return m.setStatus(streamName, msgID, map[string]any{
+		FieldStatus:    StatusPending,
+		FieldCreatedAt: time.Now().Format(time.RFC3339),
+	})
+}
+
+// IsProcessing 实现 consumer.MessageStateManager 接口方法, 检查消息是否正在被处理.
+// 读取失败时视为没有在处理并记录警告日志, 避免 redis 故障时消息无法被认领
+func (m *RedisMessageStateManager) IsProcessing(streamName, msgID string) (string, bool) {
+	consumerName, err := m.Cache.GetString(m.Ctx, m.processingKey(streamName, msgID))
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			zap.L().Warn("读取消息处理标记失败, 视为没有在处理",
+				zap.String("streamName", streamName),
+				zap.String("msgID", msgID),
+				zap.Error(err),
+			)
+		}
+
+		return "", false
+	}
+
+	return consumerName, true
+}
+
+// MarkProcessing 实现 consumer.MessageStateManager 接口方法, 标记消息开始处理, 标记在 ProcessingTTL 后过期
+func (m *RedisMessageStateManager) MarkProcessing(streamName, msgID, consumerName string) error {
+	return m.Cache.SetString(m.Ctx, m.processingKey(streamName, msgID), consumerName, m.ProcessingTTL)
+}
+
+// ClearProcessing 实现 consumer.MessageStateManager 接口方法, 清除处理标记
+func (m *RedisMessageStateManager) ClearProcessing(streamName, msgID string) error {
+	return m.Cache.Del(m.Ctx, m.processingKey(streamName, msgID))
+}
+
+// UpdateAckStatus 实现 consumer.MessageStateManager 接口方法, 记录消费者组的签收状态
+func (m *RedisMessageStateManager) UpdateAckStatus(streamName, msgID, groupName string, isSuccess bool) error {
+	status := StatusFailed
+	if isSuccess {
+		status = StatusSuccess
+	}
+
+	return m.setStatus(streamName, msgID, map[string]any{
+		FieldStatus:                  status,
+		FieldAckPrefix + groupName:   status,
+		FieldAckedPrefix + groupName: time.Now().Format(time.RFC3339),
+	})
+}
+
+// RecordFailure 实现 consumer.MessageFailureRecorder 接口方法, 记录最后一次处理失败的原因
+func (m *RedisMessageStateManager) RecordFailure(streamName, msgID, reason string) error {
+	return m.setStatus(streamName, msgID, map[string]any{
+		FieldError:    reason,
+		FieldFailedAt: time.Now().Format(time.RFC3339),
+	})
+}
+
+// GetStatus 获取消息状态, 状态不存在(未记录或已过期)时返回 redis.Nil
+//   - ctx: 上下文
+//   - streamName: 流名称
+//   - msgID: 消息ID
+func (m *RedisMessageStateManager) GetStatus(ctx context.Context, streamName, msgID string) (*MessageStatus, error) {
+	fields, err := m.Cache.Client.HGetAll(ctx, m.statusKey(streamName, msgID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 0 {
+		return nil, redis.Nil
+	}
+
+	s := &MessageStatus{
+		Status:    fields[FieldStatus],
+		CreatedAt: fields[FieldCreatedAt],
+		Error:     fields[FieldError],
+		FailedAt:  fields[FieldFailedAt],
+		Acks:      make(map[string]string),
+	}
+
+	for field, value := range fields {
+		if group, ok := strings.CutPrefix(field, FieldAckPrefix); ok {
+			s.Acks[group] = value
+		}
+	}
+
+	s.Processing, err = m.Cache.GetString(ctx, m.processingKey(streamName, msgID))
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	return s, nil
+}
@@ -0,0 +1,205 @@
+//
+// FilePath    : go-utils\redis\stream\state\redis_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 消息状态管理测试, 设置 STREAMTEST_REDIS_ADDR 时使用真实的 redis, 否则使用内嵌的 miniredis
+//
+
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/jiaopengzi/go-utils/redis/cache"
+	"github.com/jiaopengzi/go-utils/redis/stream/streamtest"
+)
+
+// newTestManager 创建消息状态管理和本次测试独占的流名称, 测试结束后删除写入的 key
+func newTestManager(t *testing.T, opts ...Option) (*RedisMessageStateManager, string) {
+	t.Helper()
+
+	rdb := streamtest.RedisFromEnv(t)
+	c := cache.NewClient(rdb)
+	streamName := "test_state_" + uuid.NewString()
+
+	t.Cleanup(func() {
+		ctx := context.Background()
+
+		for _, pattern := range []string{
+			c.Key(cache.GenerateKey(statusPurpose, streamName, "*")),
+			c.Key(cache.GenerateKey(processingPurpose, streamName, "*")),
+		} {
+			keys, _ := rdb.Keys(ctx, pattern).Result()
+			if len(keys) > 0 {
+				_ = rdb.Del(ctx, keys...).Err()
+			}
+		}
+	})
+
+	return NewRedisMessageStateManager(c, opts...), streamName
+}
+
+// getStatus 获取消息状态, 失败时测试失败
+func getStatus(t *testing.T, m *RedisMessageStateManager, streamName, msgID string) *MessageStatus {
+	t.Helper()
+
+	s, err := m.GetStatus(context.Background(), streamName, msgID)
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+
+	return s
+}
+
+// assertTTL 校验 key 的剩余有效期在 (0, max] 之间
+func assertTTL(t *testing.T, m *RedisMessageStateManager, key string, maxTTL time.Duration) {
+	t.Helper()
+
+	ttl, err := m.Cache.Client.PTTL(context.Background(), key).Result()
+	if err != nil {
+		t.Fatalf("PTTL() error = %v", err)
+	}
+
+	if ttl <= 0 || ttl > maxTTL {
+		t.Errorf("PTTL(%s) = %v, want (0, %v]", key, ttl, maxTTL)
+	}
+}
+
+// TestRedisMessageStateManager_Lifecycle 生产、处理、失败、签收的完整状态变化
+func TestRedisMessageStateManager_Lifecycle(t *testing.T) {
+	m, streamName := newTestManager(t, WithStatusTTL(time.Hour), WithProcessingTTL(time.Minute))
+
+	const msgID = "1-0"
+
+	if err := m.InitMessageStatus(streamName, msgID); err != nil {
+		t.Fatalf("InitMessageStatus() error = %v", err)
+	}
+
+	s := getStatus(t, m, streamName, msgID)
+	if s.Status != StatusPending || s.CreatedAt == "" || len(s.Acks) != 0 || s.Processing != "" {
+		t.Fatalf("初始化后状态 = %+v", *s)
+	}
+
+	assertTTL(t, m, m.statusKey(streamName, msgID), time.Hour)
+
+	// 开始处理
+	if _, ok := m.IsProcessing(streamName, msgID); ok {
+		t.Fatal("标记前 IsProcessing() = true")
+	}
+
+	if err := m.MarkProcessing(streamName, msgID, "consumer-1"); err != nil {
+		t.Fatalf("MarkProcessing() error = %v", err)
+	}
+
+	if name, ok := m.IsProcessing(streamName, msgID); !ok || name != "consumer-1" {
+		t.Fatalf("IsProcessing() = %q, %v, want consumer-1, true", name, ok)
+	}
+
+	assertTTL(t, m, m.Cache.Key(m.processingKey(streamName, msgID)), time.Minute)
+
+	if s = getStatus(t, m, streamName, msgID); s.Processing != "consumer-1" {
+		t.Errorf("Processing = %q, want consumer-1", s.Processing)
+	}
+
+	// 处理失败, 记录原因并签收失败
+	if err := m.RecordFailure(streamName, msgID, "timeout"); err != nil {
+		t.Fatalf("RecordFailure() error = %v", err)
+	}
+
+	if err := m.UpdateAckStatus(streamName, msgID, "group-a", false); err != nil {
+		t.Fatalf("UpdateAckStatus() error = %v", err)
+	}
+
+	if err := m.ClearProcessing(streamName, msgID); err != nil {
+		t.Fatalf("ClearProcessing() error = %v", err)
+	}
+
+	s = getStatus(t, m, streamName, msgID)
+	if s.Status != StatusFailed || s.Error != "timeout" || s.FailedAt == "" || s.Acks["group-a"] != StatusFailed || s.Processing != "" {
+		t.Fatalf("失败后状态 = %+v", *s)
+	}
+
+	if _, ok := m.IsProcessing(streamName, msgID); ok {
+		t.Error("清除标记后 IsProcessing() = true")
+	}
+
+	// 另一个消费者组处理成功, 状态为最后一次签收的状态, 各组的签收状态分别记录
+	if err := m.UpdateAckStatus(streamName, msgID, "group-b", true); err != nil {
+		t.Fatalf("UpdateAckStatus() error = %v", err)
+	}
+
+	s = getStatus(t, m, streamName, msgID)
+	if s.Status != StatusSuccess || s.Acks["group-a"] != StatusFailed || s.Acks["group-b"] != StatusSuccess {
+		t.Errorf("签收后状态 = %+v", *s)
+	}
+
+	if s.Error != "timeout" {
+		t.Errorf("Error = %q, 签收成功后保留最后一次失败的原因", s.Error)
+	}
+
+	assertTTL(t, m, m.statusKey(streamName, msgID), time.Hour)
+}
+
+// TestRedisMessageStateManager_GetStatusNotFound 状态不存在时返回 redis.Nil
+func TestRedisMessageStateManager_GetStatusNotFound(t *testing.T) {
+	m, streamName := newTestManager(t)
+
+	if _, err := m.GetStatus(context.Background(), streamName, "1-0"); !errors.Is(err, redis.Nil) {
+		t.Errorf("GetStatus() error = %v, want redis.Nil", err)
+	}
+
+	// 只有处理标记没有状态时同样视为不存在
+	if err := m.MarkProcessing(streamName, "2-0", "consumer-1"); err != nil {
+		t.Fatalf("MarkProcessing() error = %v", err)
+	}
+
+	if _, err := m.GetStatus(context.Background(), streamName, "2-0"); !errors.Is(err, redis.Nil) {
+		t.Errorf("GetStatus() error = %v, want redis.Nil", err)
+	}
+}
+
+// TestRedisMessageStateManager_IsProcessingError redis 不可用时视为没有在处理并记录警告日志, 标记不存在时不记录
+func TestRedisMessageStateManager_IsProcessingError(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	t.Cleanup(zap.ReplaceGlobals(zap.New(core)))
+
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	m := NewRedisMessageStateManager(cache.NewClient(rdb))
+
+	if _, ok := m.IsProcessing("orders", "1-0"); ok {
+		t.Fatal("IsProcessing() = true")
+	}
+
+	if n := logs.Len(); n != 0 {
+		t.Fatalf("标记不存在时记录了 %d 条日志", n)
+	}
+
+	server.Close()
+
+	if _, ok := m.IsProcessing("orders", "1-0"); ok {
+		t.Fatal("redis 不可用时 IsProcessing() = true")
+	}
+
+	entries := logs.FilterLevelExact(zapcore.WarnLevel).All()
+	if len(entries) != 1 {
+		t.Fatalf("警告日志条数 = %d, want 1", len(entries))
+	}
+
+	if fields := entries[0].ContextMap(); fields["msgID"] != "1-0" || fields["error"] == nil {
+		t.Errorf("日志字段 = %v", fields)
+	}
+}
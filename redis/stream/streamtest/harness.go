@@ -0,0 +1,483 @@
+//
+// FilePath    : go-utils\redis\stream\streamtest\harness.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : stream 消费者集成测试工具, 发布测试消息、同步运行消费者并断言签收、死信和 pending 状态
+//
+
+// Package streamtest redis stream 消费者集成测试工具.
+//
+// 通过环境变量 STREAMTEST_REDIS_ADDR 指定 redis 实例, 例如 CI 中启动的 redis 容器;
+// 未设置时使用内嵌的 miniredis, 不需要外部 redis 也可以运行. 每个 Harness 使用独立的 stream 和消费者组, 测试结束时自动删除, 可以并行测试.
+//
+//	func TestOrderConsumer(t *testing.T) {
+//		h := streamtest.New(t, streamtest.RedisFromEnv(t), handleOrder)
+//		ids := h.Publish(Order{ID: 1}, Order{ID: -1})
+//		h.RunConsumer()
+//		h.AssertAcked(ids[0])
+//		h.AssertDeadLettered(ids[1])
+//		h.AssertPending(0)
+//	}
+package streamtest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	_stream "github.com/jiaopengzi/go-utils/redis/stream"
+	"github.com/jiaopengzi/go-utils/redis/stream/consumer"
+	"github.com/jiaopengzi/go-utils/redis/stream/producer"
+)
+
+// EnvRedisAddr redis 地址的环境变量, 多个地址(集群)使用逗号分隔
+const EnvRedisAddr = "STREAMTEST_REDIS_ADDR"
+
+// MsgKey 测试消息的 key
+const MsgKey = "streamtest"
+
+// readCount 同步运行消费者时每次拉取的消息数量
+const readCount = 10
+
+// invalidNameChars 测试名称中不适合作为 stream 名称的字符
+var invalidNameChars = regexp.MustCompile(`[^0-9A-Za-z_\-]+`)
+
+// RedisFromEnv 根据环境变量 STREAMTEST_REDIS_ADDR 创建 redis 客户端, 未设置时使用 EmbeddedRedis, 无法连接时测试失败, 测试结束时关闭客户端
+//   - t: 测试
+func RedisFromEnv(t testing.TB) redis.UniversalClient {
+	t.Helper()
+
+	addr := strings.TrimSpace(os.Getenv(EnvRedisAddr))
+	if addr == "" {
+		return EmbeddedRedis(t)
+	}
+
+	rdb := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: strings.Split(addr, ",")})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		_ = rdb.Close()
+		t.Fatalf("无法连接 redis %s: %v", addr, err)
+	}
+
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	return rdb
+}
+
+// EmbeddedRedis 启动内嵌的 miniredis 并创建 redis 客户端, 测试结束时关闭.
+// miniredis 支持 stream 和消费者组, 但 XINFO 等命令的部分字段与 redis 不一致, 依赖这些字段的测试需要使用真实的 redis.
+//   - t: 测试
+func EmbeddedRedis(t testing.TB) redis.UniversalClient {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	return rdb
+}
+
+// Processed 同步运行消费者时单条消息的处理结果
+type Processed struct {
+	ID  string // 消息 ID
+	Err error  // ProcessMessage 返回的错误
+}
+
+// Harness stream 消费者测试工具, 生产者和消费者使用同一个独立的 stream 和消费者组
+type Harness[T any] struct {
+	T        testing.TB                // 测试
+	Ctx      context.Context           // context 上下文
+	Rdb      redis.UniversalClient     // Redis 客户端
+	Producer *producer.BaseProducer[T] // 生产者
+	Consumer *consumer.BaseConsumer[T] // 消费者, 可以替换 ProcessMessageFunc 测试自定义的处理函数
+	Recorder *Recorder                 // 消息状态记录, 同时作为生产者的状态初始化器和消费者的状态管理器
+}
+
+// New 创建 stream 消费者测试工具, 消费者使用 consumer.HandleAndAckMessage 处理消息:
+// handler 返回 nil 时签收成功, 返回错误时签收失败并进入死信.
+//   - t: 测试
+//   - rdb: Redis 客户端, 例如 RedisFromEnv(t)
+//   - handler: 处理消息的回调函数
+func New[T any](t testing.TB, rdb redis.UniversalClient, handler func(valueStruct *T) error) *Harness[T] {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	name := invalidNameChars.ReplaceAllString(t.Name(), "_") + ":" + uuid.NewString()[:8]
+	streamName := _stream.NamePrefix + "streamtest:" + name
+	recorder := NewRecorder()
+
+	h := &Harness[T]{
+		T:   t,
+		Ctx: ctx,
+		Rdb: rdb,
+		Producer: &producer.BaseProducer[T]{
+			StreamName:       streamName,
+			MsgKey:           MsgKey,
+			Ctx:              ctx,
+			Rdb:              rdb,
+			StateInitializer: recorder,
+		},
+		Consumer: &consumer.BaseConsumer[T]{
+			StreamName:   streamName,
+			GroupName:    _stream.GroupNamePrefix + "streamtest:" + name,
+			Start:        "0",
+			ConsumerName: _stream.ConsumerNamePrefix + "streamtest",
+			MsgKey:       MsgKey,
+			Ctx:          ctx,
+			ProcessMessageFunc: func(c *consumer.BaseConsumer[T], message redis.XMessage) error {
+				return consumer.HandleAndAckMessage(c, message, c.MsgKey, handler)
+			},
+			Rdb:          rdb,
+			StateManager: recorder,
+		},
+		Recorder: recorder,
+	}
+
+	t.Cleanup(func() {
+		cancel()
+
+		if err := rdb.Del(context.Background(), streamName).Err(); err != nil {
+			t.Logf("删除测试 stream %s 失败: %v", streamName, err)
+		}
+	})
+
+	if err := h.Consumer.CreateGroup(); err != nil {
+		t.Fatalf("创建消费者组失败: %v", err)
+	}
+
+	if err := h.Consumer.CreateConsumer(); err != nil {
+		t.Fatalf("创建消费者失败: %v", err)
+	}
+
+	return h
+}
+
+// Publish 通过生产者发布测试消息, 返回消息 ID, 顺序与 values 一致
+//   - values: 消息
+func (h *Harness[T]) Publish(values ...T) []string {
+	h.T.Helper()
+
+	ids := make([]string, 0, len(values))
+
+	for _, value := range values {
+		info, err := h.Producer.AddMessageWithContext(h.Ctx, value)
+		if err != nil {
+			h.T.Fatalf("发布测试消息失败: %v", err)
+		}
+
+		ids = append(ids, info.ID)
+	}
+
+	return ids
+}
+
+// PublishRaw 直接写入消息字段, 不经过生产者, 用于测试格式错误的消息, 返回消息 ID
+//   - values: 消息字段, 例如 map[string]any{streamtest.MsgKey: "not json"}
+func (h *Harness[T]) PublishRaw(values map[string]any) string {
+	h.T.Helper()
+
+	id, err := h.Rdb.XAdd(h.Ctx, &redis.XAddArgs{Stream: h.Producer.StreamName, ID: "*", Values: values}).Result()
+	if err != nil {
+		h.T.Fatalf("写入测试消息失败: %v", err)
+	}
+
+	return id
+}
+
+// RunConsumer 同步运行消费者, 拉取并处理所有未投递的消息, 没有新消息时返回.
+// 返回每条消息的处理结果, 顺序与投递顺序一致.
+func (h *Harness[T]) RunConsumer() []Processed {
+	h.T.Helper()
+
+	return h.drain(">")
+}
+
+// RedeliverPending 重新处理消费者已拉取但未签收的消息, 模拟消费者重启后处理自己的 pending 消息
+func (h *Harness[T]) RedeliverPending() []Processed {
+	h.T.Helper()
+
+	return h.drain("0")
+}
+
+// drain 从 start 开始循环拉取并处理消息, 直到没有消息; start 为 ">" 时拉取新消息, 否则按 ID 游标拉取自己的 pending 消息
+func (h *Harness[T]) drain(start string) []Processed {
+	h.T.Helper()
+
+	var results []Processed
+
+	for {
+		entries, err := h.Rdb.XReadGroup(h.Ctx, &redis.XReadGroupArgs{
+			Group:    h.Consumer.GroupName,
+			Consumer: h.Consumer.ConsumerName,
+			Streams:  []string{h.Consumer.StreamName, start},
+			Count:    readCount,
+			Block:    -1, // 不阻塞
+		}).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			h.T.Fatalf("拉取消息失败: %v", err)
+		}
+
+		var messages []redis.XMessage
+		for _, entry := range entries {
+			messages = append(messages, entry.Messages...)
+		}
+
+		if len(messages) == 0 {
+			return results
+		}
+
+		for _, message := range messages {
+			results = append(results, Processed{ID: message.ID, Err: h.Consumer.ProcessMessage(message)})
+		}
+
+		// 处理失败未签收的消息仍在 pending 中, 移动游标避免重复处理
+		if start != ">" {
+			start = messages[len(messages)-1].ID
+		}
+	}
+}
+
+// PendingCount 获取消费者组已投递未签收的消息数量
+func (h *Harness[T]) PendingCount() int64 {
+	h.T.Helper()
+
+	count, err := h.Consumer.GetPendingCount()
+	if err != nil {
+		h.T.Fatalf("获取 pending 消息数量失败: %v", err)
+	}
+
+	return count
+}
+
+// PendingIDs 获取消费者组已投递未签收的消息 ID
+func (h *Harness[T]) PendingIDs() []string {
+	h.T.Helper()
+
+	pending, err := h.Rdb.XPendingExt(h.Ctx, &redis.XPendingExtArgs{
+		Stream: h.Consumer.StreamName,
+		Group:  h.Consumer.GroupName,
+		Start:  "-",
+		End:    "+",
+		Count:  1000,
+	}).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		h.T.Fatalf("获取 pending 消息失败: %v", err)
+	}
+
+	ids := make([]string, 0, len(pending))
+	for _, p := range pending {
+		ids = append(ids, p.ID)
+	}
+
+	return ids
+}
+
+// DeadLetters 获取签收失败(死信)的消息, 顺序与签收顺序一致
+func (h *Harness[T]) DeadLetters() []redis.XMessage {
+	h.T.Helper()
+
+	ids := h.Recorder.Failed()
+	messages := make([]redis.XMessage, 0, len(ids))
+
+	for _, id := range ids {
+		found, err := h.Rdb.XRange(h.Ctx, h.Consumer.StreamName, id, id).Result()
+		if err != nil {
+			h.T.Fatalf("获取死信消息 %s 失败: %v", id, err)
+		}
+
+		messages = append(messages, found...)
+	}
+
+	return messages
+}
+
+// AssertPending 断言消费者组已投递未签收的消息数量
+//   - want: 期望的数量
+func (h *Harness[T]) AssertPending(want int64) {
+	h.T.Helper()
+
+	if got := h.PendingCount(); got != want {
+		h.T.Errorf("pending 消息数量为 %d, 期望 %d, pending: %v", got, want, h.PendingIDs())
+	}
+}
+
+// AssertAcked 断言消息已签收成功
+//   - ids: 消息 ID
+func (h *Harness[T]) AssertAcked(ids ...string) {
+	h.T.Helper()
+	h.assertAckStatus(ids, true)
+}
+
+// AssertDeadLettered 断言消息已签收失败(进入死信)
+//   - ids: 消息 ID
+func (h *Harness[T]) AssertDeadLettered(ids ...string) {
+	h.T.Helper()
+	h.assertAckStatus(ids, false)
+}
+
+// AssertNoDeadLetters 断言没有签收失败的消息
+func (h *Harness[T]) AssertNoDeadLetters() {
+	h.T.Helper()
+
+	if failed := h.Recorder.Failed(); len(failed) > 0 {
+		h.T.Errorf("存在签收失败的消息: %v", failed)
+	}
+}
+
+// assertAckStatus 断言消息的签收状态, 且消息不在 pending 中
+func (h *Harness[T]) assertAckStatus(ids []string, wantSuccess bool) {
+	h.T.Helper()
+
+	pending := h.PendingIDs()
+
+	for _, id := range ids {
+		acked, success := h.Recorder.AckStatus(id)
+
+		switch {
+		case !acked:
+			h.T.Errorf("消息 %s 未签收", id)
+		case success != wantSuccess:
+			h.T.Errorf("消息 %s 签收结果为 success=%t, 期望 success=%t", id, success, wantSuccess)
+		case slices.Contains(pending, id):
+			h.T.Errorf("消息 %s 已记录签收但仍在 pending 中", id)
+		}
+	}
+}
+
+// Recorder 在内存中记录消息状态, 实现 producer.MessageStateInitializer 和 consumer.MessageStateManager 接口
+type Recorder struct {
+	mu         sync.Mutex
+	published  []string          // 已发布的消息 ID
+	processing map[string]string // 正在处理的消息 ID -> 消费者名称
+	acks       map[string]bool   // 已签收的消息 ID -> 是否成功
+	ackOrder   []string          // 签收顺序
+}
+
+// 确保 Recorder 实现了消息状态接口
+var (
+	_ producer.MessageStateInitializer = (*Recorder)(nil)
+	_ consumer.MessageStateManager     = (*Recorder)(nil)
+)
+
+// NewRecorder 创建消息状态记录
+func NewRecorder() *Recorder {
+	return &Recorder{
+		processing: make(map[string]string),
+		acks:       make(map[string]bool),
+	}
+}
+
+// InitMessageStatus 实现 producer.MessageStateInitializer 接口, 记录已发布的消息
+func (r *Recorder) InitMessageStatus(_, msgID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.published = append(r.published, msgID)
+
+	return nil
+}
+
+// IsProcessing 实现 consumer.MessageStateManager 接口
+func (r *Recorder) IsProcessing(_, msgID string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name, ok := r.processing[msgID]
+
+	return name, ok
+}
+
+// MarkProcessing 实现 consumer.MessageStateManager 接口
+func (r *Recorder) MarkProcessing(_, msgID, consumerName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.processing[msgID] = consumerName
+
+	return nil
+}
+
+// ClearProcessing 实现 consumer.MessageStateManager 接口
+func (r *Recorder) ClearProcessing(_, msgID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.processing, msgID)
+
+	return nil
+}
+
+// UpdateAckStatus 实现 consumer.MessageStateManager 接口, 记录签收结果
+func (r *Recorder) UpdateAckStatus(_, msgID, _ string, isSuccess bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.acks[msgID]; !ok {
+		r.ackOrder = append(r.ackOrder, msgID)
+	}
+
+	r.acks[msgID] = isSuccess
+
+	return nil
+}
+
+// Published 获取已发布的消息 ID
+func (r *Recorder) Published() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.published)
+}
+
+// AckStatus 获取消息的签收状态
+//   - msgID: 消息 ID
+//
+// 返回 (是否已签收, 是否签收成功)
+func (r *Recorder) AckStatus(msgID string) (acked, success bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	success, acked = r.acks[msgID]
+
+	return acked, success
+}
+
+// Acked 获取签收成功的消息 ID, 顺序与签收顺序一致
+func (r *Recorder) Acked() []string {
+	return r.filterAcks(true)
+}
+
+// Failed 获取签收失败(死信)的消息 ID, 顺序与签收顺序一致
+func (r *Recorder) Failed() []string {
+	return r.filterAcks(false)
+}
+
+// filterAcks 按签收结果过滤消息 ID
+func (r *Recorder) filterAcks(success bool) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []string
+
+	for _, id := range r.ackOrder {
+		if r.acks[id] == success {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
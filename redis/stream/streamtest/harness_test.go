@@ -0,0 +1,109 @@
+//
+// FilePath    : go-utils\redis\stream\streamtest\harness_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : stream 消费者集成测试工具自身的测试
+//
+
+package streamtest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jiaopengzi/go-utils/redis/stream/consumer"
+)
+
+// order 测试消息
+type order struct {
+	ID int `json:"id"`
+}
+
+// handleOrder ID 为负数时处理失败
+func handleOrder(o *order) error {
+	if o.ID < 0 {
+		return errors.New("invalid order")
+	}
+
+	return nil
+}
+
+// TestHarness 发布消息后同步消费, 处理成功和失败的消息分别签收成功和进入死信
+func TestHarness(t *testing.T) {
+	h := New(t, RedisFromEnv(t), handleOrder)
+
+	ids := h.Publish(order{ID: 1}, order{ID: -1}, order{ID: 2})
+
+	if got := h.Recorder.Published(); !slices.Equal(got, ids) {
+		t.Errorf("Published() = %v, want %v", got, ids)
+	}
+
+	results := h.RunConsumer()
+	if len(results) != len(ids) {
+		t.Fatalf("RunConsumer() = %+v, want %d results", results, len(ids))
+	}
+
+	for i, r := range results {
+		if r.ID != ids[i] {
+			t.Errorf("results[%d].ID = %s, want %s", i, r.ID, ids[i])
+		}
+	}
+
+	h.AssertAcked(ids[0], ids[2])
+	h.AssertDeadLettered(ids[1])
+	h.AssertPending(0)
+
+	if got := h.Recorder.Acked(); !slices.Equal(got, []string{ids[0], ids[2]}) {
+		t.Errorf("Acked() = %v", got)
+	}
+
+	if dead := h.DeadLetters(); len(dead) != 1 || dead[0].ID != ids[1] {
+		t.Errorf("DeadLetters() = %+v, want %s", dead, ids[1])
+	}
+
+	// 没有新消息时直接返回
+	if results = h.RunConsumer(); len(results) != 0 {
+		t.Errorf("RunConsumer() without messages = %+v", results)
+	}
+}
+
+// TestHarness_RedeliverPending 处理时未签收的消息留在 pending 中, 重新处理后签收
+func TestHarness_RedeliverPending(t *testing.T) {
+	h := New(t, RedisFromEnv(t), handleOrder)
+
+	// 第一次处理不签收, 模拟消费者处理中途退出
+	process := h.Consumer.ProcessMessageFunc
+	h.Consumer.ProcessMessageFunc = func(*consumer.BaseConsumer[order], redis.XMessage) error {
+		return errors.New("crashed")
+	}
+
+	ids := h.Publish(order{ID: 1}, order{ID: 2})
+	malformed := h.PublishRaw(map[string]any{MsgKey: "not json"})
+
+	for _, r := range h.RunConsumer() {
+		if r.Err == nil {
+			t.Errorf("消息 %s 处理成功, 期望失败", r.ID)
+		}
+	}
+
+	h.AssertPending(3)
+
+	h.Consumer.ProcessMessageFunc = process
+
+	results := h.RedeliverPending()
+	if len(results) != 3 {
+		t.Fatalf("RedeliverPending() = %+v, want 3 results", results)
+	}
+
+	h.AssertAcked(ids...)
+	h.AssertNoDeadLetters()
+
+	// 无法解析的消息没有启用死信队列, 仍在 pending 中
+	if pending := h.PendingIDs(); !slices.Equal(pending, []string{malformed}) {
+		t.Errorf("PendingIDs() = %v, want [%s]", pending, malformed)
+	}
+}
@@ -0,0 +1,104 @@
+//
+// FilePath    : go-utils\redis\stream\streamtest\partition_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 分区生产者和消费者的顺序测试
+//
+
+package streamtest
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	_stream "github.com/jiaopengzi/go-utils/redis/stream"
+	"github.com/jiaopengzi/go-utils/redis/stream/producer"
+)
+
+// orderEvent 同一订单的事件, Seq 为生产顺序
+type orderEvent struct {
+	OrderID string `json:"order_id"`
+	Seq     int    `json:"seq"`
+}
+
+// TestPartitionedProducer_Order 同一订单的事件写入同一个分区, 各分区的消费者独立处理, 同一订单的事件按生产顺序处理
+func TestPartitionedProducer_Order(t *testing.T) {
+	const partitions, orders, events = 4, 12, 5
+
+	rdb := RedisFromEnv(t)
+
+	// 每个分区一个测试工具, 记录分区处理的事件
+	harnesses := make([]*Harness[orderEvent], partitions)
+	handled := make([][]orderEvent, partitions)
+	producers := make([]*producer.BaseProducer[orderEvent], partitions)
+
+	for i := range partitions {
+		harnesses[i] = New(t, rdb, func(e *orderEvent) error {
+			handled[i] = append(handled[i], *e)
+			return nil
+		})
+		producers[i] = harnesses[i].Producer
+	}
+
+	p := &producer.PartitionedProducer[orderEvent]{
+		Producers: producers,
+		KeyFunc:   func(e orderEvent) string { return e.OrderID },
+	}
+
+	// 不同订单的事件交替生产
+	for seq := range events {
+		for o := range orders {
+			if _, err := p.AddMessageToStream(orderEvent{OrderID: "order:" + strconv.Itoa(o), Seq: seq}); err != nil {
+				t.Fatalf("AddMessageToStream() error = %v", err)
+			}
+		}
+	}
+
+	// 分区消费者的进度互不相关, 按相反的顺序运行
+	for i := partitions - 1; i >= 0; i-- {
+		harnesses[i].RunConsumer()
+		harnesses[i].AssertPending(0)
+		harnesses[i].AssertNoDeadLetters()
+	}
+
+	got := make(map[string][]int)
+
+	for i, partition := range handled {
+		for _, e := range partition {
+			if want := _stream.PartitionOf(e.OrderID, partitions); i != want {
+				t.Errorf("%s 的事件在分区 %d 处理, want %d", e.OrderID, i, want)
+			}
+
+			got[e.OrderID] = append(got[e.OrderID], e.Seq)
+		}
+	}
+
+	want := make([]int, events)
+	for seq := range want {
+		want[seq] = seq
+	}
+
+	if len(got) != orders {
+		t.Fatalf("处理了 %d 个订单的事件, want %d", len(got), orders)
+	}
+
+	for orderID, seqs := range got {
+		if !slices.Equal(seqs, want) {
+			t.Errorf("%s 的事件处理顺序 = %v, want %v", orderID, seqs, want)
+		}
+	}
+
+	// 12 个订单至少分布到 2 个分区, 否则测试没有覆盖多个分区
+	used := 0
+	for _, partition := range handled {
+		if len(partition) > 0 {
+			used++
+		}
+	}
+
+	if used < 2 {
+		t.Errorf("只使用了 %d 个分区", used)
+	}
+}
@@ -0,0 +1,35 @@
+//
+// FilePath    : go-utils\redis\stream\trace.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 消息的链路追踪ID.
+//
+
+package stream
+
+import (
+	"context"
+
+	"github.com/jiaopengzi/go-utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// TraceID 获取消息的链路追踪ID, 生产时上下文中没有链路追踪ID则为空字符串
+func TraceID(message redis.XMessage) string {
+	traceID, _ := message.Values[FieldTraceID].(string)
+
+	return traceID
+}
+
+// ContextWithMessageTrace 返回携带消息链路追踪ID的上下文, 消息没有链路追踪ID时返回 ctx
+//   - ctx: 父上下文
+//   - message: 消息
+func ContextWithMessageTrace(ctx context.Context, message redis.XMessage) context.Context {
+	traceID := TraceID(message)
+	if traceID == "" {
+		return ctx
+	}
+
+	return utils.ContextWithTraceID(ctx, traceID)
+}
@@ -0,0 +1,64 @@
+//
+// FilePath    : go-utils\reflectutil\reflectutil.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : nil 判断、指针和零值相关的泛型工具
+//
+
+// Package reflectutil nil 判断、指针和零值相关的工具, 正确处理包装在接口中的 nil
+package reflectutil
+
+import "reflect"
+
+// Ptr 返回 v 的指针, 用于字面量和常量取地址, 例如 Ptr(true)、Ptr("name")
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Val 返回指针指向的值, p 为 nil 时返回 def
+//   - p: 指针
+//   - def: 默认值
+func Val[T any](p *T, def T) T {
+	if p == nil {
+		return def
+	}
+
+	return *p
+}
+
+// IsZero 判断 v 是否为类型的零值
+func IsZero[T comparable](v T) bool {
+	var zero T
+
+	return v == zero
+}
+
+// IsNil 判断 v 是否为 nil, 除了 v 本身为 nil 外, 还处理包装在接口中的 nil:
+// nil 指针、map、切片、chan、函数、接口和 unsafe.Pointer 均返回 true.
+//
+// 例如 var p *User; var v any = p; 此时 v != nil, 但 IsNil(v) 为 true.
+func IsNil(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// IsPointer 判断 v 是否为指针, v 为 nil 时返回 false
+func IsPointer(v any) bool {
+	return v != nil && reflect.TypeOf(v).Kind() == reflect.Pointer
+}
+
+// IsNonNilPointer 判断 v 是否为非 nil 指针, 常用于校验反序列化或反射写入的目标
+func IsNonNilPointer(v any) bool {
+	return IsPointer(v) && !reflect.ValueOf(v).IsNil()
+}
@@ -0,0 +1,79 @@
+//
+// FilePath    : go-utils\reflectutil\reflectutil_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : nil 判断、指针和零值工具测试
+//
+
+package reflectutil
+
+import "testing"
+
+type user struct{ Name string }
+
+func TestIsNil(t *testing.T) {
+	var (
+		p  *user
+		m  map[string]int
+		s  []int
+		ch chan int
+		fn func()
+		e  error
+	)
+
+	tests := []struct {
+		name string
+		v    any
+		want bool
+	}{
+		{"nil", nil, true},
+		{"nil 指针", p, true},
+		{"nil map", m, true},
+		{"nil 切片", s, true},
+		{"nil chan", ch, true},
+		{"nil 函数", fn, true},
+		{"nil error", e, true},
+		{"非 nil 指针", &user{}, false},
+		{"空切片", []int{}, false},
+		{"结构体", user{}, false},
+		{"零值整数", 0, false},
+		{"空字符串", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNil(tt.v); got != tt.want {
+				t.Errorf("IsNil(%#v) = %v, want %v", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPointerHelpers(t *testing.T) {
+	if p := Ptr(3); *p != 3 {
+		t.Errorf("Ptr(3) = %d", *p)
+	}
+
+	if got := Val[int](nil, 7); got != 7 {
+		t.Errorf("Val(nil, 7) = %d, want 7", got)
+	}
+
+	if got := Val(Ptr("a"), "b"); got != "a" {
+		t.Errorf("Val(Ptr(a), b) = %s, want a", got)
+	}
+
+	if !IsZero(user{}) || IsZero(user{Name: "x"}) || !IsZero("") {
+		t.Errorf("IsZero() mismatch")
+	}
+
+	var p *user
+
+	if IsPointer(nil) || !IsPointer(p) || IsPointer(user{}) {
+		t.Errorf("IsPointer() mismatch")
+	}
+
+	if IsNonNilPointer(p) || !IsNonNilPointer(&user{}) {
+		t.Errorf("IsNonNilPointer() mismatch")
+	}
+}
@@ -0,0 +1,184 @@
+//
+// FilePath    : go-utils\req\breaker.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 出站请求熔断器, 连续失败达到阈值后熔断, 冷却后半开试探
+//
+
+package req
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 熔断器处于打开状态, 请求被拒绝
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState 熔断器状态
+type BreakerState int
+
+// 熔断器状态常量
+const (
+	BreakerClosed   BreakerState = iota // 关闭, 正常放行
+	BreakerOpen                         // 打开, 拒绝所有请求
+	BreakerHalfOpen                     // 半开, 放行少量试探请求
+)
+
+// String 实现 fmt.Stringer 接口
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig 熔断器配置, 零值字段使用默认值
+type BreakerConfig struct {
+	FailureThreshold int                                       // 连续失败次数阈值, 默认 5
+	OpenTimeout      time.Duration                             // 打开状态持续时间, 之后进入半开, 默认 30 秒
+	HalfOpenMaxCalls int                                       // 半开状态允许的并发试探请求数, 默认 1
+	IsFailure        func(resp *http.Response, err error) bool // 判断请求是否失败, 默认网络错误或 5xx 视为失败
+}
+
+// withDefaults 填充默认值
+func (c BreakerConfig) withDefaults() BreakerConfig {
+	if c.FailureThreshold <= 0 {
+		c.FailureThreshold = 5
+	}
+
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = 30 * time.Second
+	}
+
+	if c.HalfOpenMaxCalls <= 0 {
+		c.HalfOpenMaxCalls = 1
+	}
+
+	if c.IsFailure == nil {
+		c.IsFailure = DefaultIsFailure
+	}
+
+	return c
+}
+
+// DefaultIsFailure 默认的失败判断: 网络错误或 5xx 响应
+func DefaultIsFailure(resp *http.Response, err error) bool {
+	return err != nil || (resp != nil && resp.StatusCode >= http.StatusInternalServerError)
+}
+
+// Breaker 熔断器, 并发安全
+type Breaker struct {
+	cfg BreakerConfig
+	now func() time.Time // 当前时间, 便于测试
+
+	mu            sync.Mutex
+	state         BreakerState
+	failures      int       // 连续失败次数
+	openedAt      time.Time // 进入打开状态的时间
+	halfOpenCalls int       // 半开状态下进行中的试探请求数
+}
+
+// NewBreaker 创建熔断器
+func NewBreaker(cfg BreakerConfig) *Breaker {
+	return &Breaker{cfg: cfg.withDefaults(), now: time.Now}
+}
+
+// State 获取熔断器当前状态, 打开状态超过 OpenTimeout 时返回半开
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh()
+
+	return b.state
+}
+
+// refresh 打开状态超过 OpenTimeout 时进入半开, 调用方需持有锁
+func (b *Breaker) refresh() {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.state = BreakerHalfOpen
+		b.halfOpenCalls = 0
+	}
+}
+
+// Allow 判断是否放行请求, 放行时返回的 done 必须在请求结束后调用并传入是否失败
+func (b *Breaker) Allow() (done func(failed bool), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh()
+
+	switch b.state {
+	case BreakerOpen:
+		return nil, ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.halfOpenCalls >= b.cfg.HalfOpenMaxCalls {
+			return nil, ErrCircuitOpen
+		}
+
+		b.halfOpenCalls++
+
+		return b.onHalfOpenDone, nil
+	default:
+		return b.onClosedDone, nil
+	}
+}
+
+// onClosedDone 关闭状态下的请求结束
+func (b *Breaker) onClosedDone(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// 请求期间状态可能已被其他请求改变
+	if b.state != BreakerClosed {
+		return
+	}
+
+	if !failed {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.cfg.FailureThreshold {
+		b.trip()
+	}
+}
+
+// onHalfOpenDone 半开状态下的试探请求结束, 成功则关闭, 失败则重新打开
+func (b *Breaker) onHalfOpenDone(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BreakerHalfOpen {
+		return
+	}
+
+	b.halfOpenCalls--
+
+	if failed {
+		b.trip()
+		return
+	}
+
+	b.state = BreakerClosed
+	b.failures = 0
+}
+
+// trip 进入打开状态, 调用方需持有锁
+func (b *Breaker) trip() {
+	b.state = BreakerOpen
+	b.openedAt = b.now()
+	b.failures = 0
+	b.halfOpenCalls = 0
+}
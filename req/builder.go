@@ -0,0 +1,454 @@
+//
+// FilePath    : go-utils\req\builder.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 出站请求构建器, 支持查询参数结构体编码、表单请求体和流式 multipart 文件上传
+//
+
+package req
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 请求构建器错误
+var (
+	ErrBodyConflict = errors.New("request body already set") // 同时设置了原始请求体和表单或文件
+	ErrNotStruct    = errors.New("value must be a struct")   // 编码的值不是结构体
+)
+
+// filePart multipart 文件
+type filePart struct {
+	field    string
+	filename string
+	reader   io.Reader
+}
+
+// RequestBuilder 出站请求构建器, 通过 Client.NewRequest 创建, 方法可链式调用, 遇到的第一个错误在 Build 或 Do 时返回.
+// 只设置表单时请求体为 application/x-www-form-urlencoded; 设置了文件时表单字段和文件一起以 multipart/form-data 流式发送.
+type RequestBuilder struct {
+	client *Client
+	ctx    context.Context
+	method string
+	rawURL string
+
+	header      http.Header
+	query       url.Values
+	form        url.Values
+	files       []filePart
+	body        io.Reader
+	contentType string
+
+	err error
+}
+
+// NewRequest 创建请求构建器
+//   - ctx: 请求上下文
+//   - method: 请求方法, 例如 http.MethodPost
+//   - rawURL: 请求地址, 可以包含查询参数
+func (c *Client) NewRequest(ctx context.Context, method, rawURL string) *RequestBuilder {
+	return &RequestBuilder{
+		client: c,
+		ctx:    ctx,
+		method: method,
+		rawURL: rawURL,
+		header: make(http.Header),
+		query:  make(url.Values),
+		form:   make(url.Values),
+	}
+}
+
+// setErr 记录第一个错误
+func (b *RequestBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Header 设置请求头
+func (b *RequestBuilder) Header(key, value string) *RequestBuilder {
+	b.header.Set(key, value)
+
+	return b
+}
+
+// Query 追加查询参数
+func (b *RequestBuilder) Query(key string, values ...string) *RequestBuilder {
+	for _, v := range values {
+		b.query.Add(key, v)
+	}
+
+	return b
+}
+
+// QueryStruct 将结构体按 form 标签编码后追加为查询参数, 规则见 EncodeValues
+func (b *RequestBuilder) QueryStruct(v any) *RequestBuilder {
+	values, err := EncodeValues(v)
+	if err != nil {
+		b.setErr(fmt.Errorf("encode query: %w", err))
+		return b
+	}
+
+	for key, vs := range values {
+		b.query[key] = append(b.query[key], vs...)
+	}
+
+	return b
+}
+
+// Form 追加表单字段
+func (b *RequestBuilder) Form(key string, values ...string) *RequestBuilder {
+	for _, v := range values {
+		b.form.Add(key, v)
+	}
+
+	return b
+}
+
+// FormStruct 将结构体按 form 标签编码后追加为表单字段, 规则见 EncodeValues
+func (b *RequestBuilder) FormStruct(v any) *RequestBuilder {
+	values, err := EncodeValues(v)
+	if err != nil {
+		b.setErr(fmt.Errorf("encode form: %w", err))
+		return b
+	}
+
+	for key, vs := range values {
+		b.form[key] = append(b.form[key], vs...)
+	}
+
+	return b
+}
+
+// File 追加 multipart 文件, 发送请求时才从 r 流式读取, 不会把文件读入内存.
+// r 实现 io.Closer 时在写入完成或请求结束后关闭.
+//   - field: 表单字段名
+//   - filename: 文件名
+//   - r: 文件内容
+func (b *RequestBuilder) File(field, filename string, r io.Reader) *RequestBuilder {
+	b.files = append(b.files, filePart{field: field, filename: filename, reader: r})
+
+	return b
+}
+
+// Body 设置原始请求体, 不能与 Form、FormStruct、File 同时使用
+//   - contentType: 请求体类型, 例如 application/json
+//   - r: 请求体
+func (b *RequestBuilder) Body(contentType string, r io.Reader) *RequestBuilder {
+	b.body = r
+	b.contentType = contentType
+
+	return b
+}
+
+// Build 构建请求
+func (b *RequestBuilder) Build() (*http.Request, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if b.body != nil && (len(b.form) > 0 || len(b.files) > 0) {
+		return nil, ErrBodyConflict
+	}
+
+	u, err := url.Parse(b.rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse url: %w", err)
+	}
+
+	if len(b.query) > 0 {
+		q := u.Query()
+		for key, vs := range b.query {
+			q[key] = append(q[key], vs...)
+		}
+
+		u.RawQuery = q.Encode()
+	}
+
+	body, contentType := b.body, b.contentType
+
+	switch {
+	case len(b.files) > 0:
+		mb := newMultipartBody(b.form, b.files)
+		body, contentType = mb, mb.writer.FormDataContentType()
+	case len(b.form) > 0:
+		body, contentType = strings.NewReader(b.form.Encode()), "application/x-www-form-urlencoded"
+	}
+
+	r, err := http.NewRequestWithContext(b.ctx, b.method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, vs := range b.header {
+		r.Header[key] = vs
+	}
+
+	if contentType != "" && r.Header.Get("Content-Type") == "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+
+	return r, nil
+}
+
+// Do 构建并发送请求
+func (b *RequestBuilder) Do() (*http.Response, error) {
+	r, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.client.Do(r)
+}
+
+// multipartBody 流式 multipart 请求体, 首次读取时才开始写入, 未发送的请求不会泄漏 goroutine
+type multipartBody struct {
+	pr     *io.PipeReader
+	pw     *io.PipeWriter
+	writer *multipart.Writer
+	fields url.Values
+	files  []filePart
+	once   sync.Once
+}
+
+// newMultipartBody 创建流式 multipart 请求体
+func newMultipartBody(fields url.Values, files []filePart) *multipartBody {
+	pr, pw := io.Pipe()
+
+	return &multipartBody{pr: pr, pw: pw, writer: multipart.NewWriter(pw), fields: fields, files: files}
+}
+
+// Read 实现 io.Reader 接口
+func (m *multipartBody) Read(p []byte) (int, error) {
+	m.once.Do(func() { go m.write() })
+
+	return m.pr.Read(p)
+}
+
+// Close 实现 io.Closer 接口, 中断写入并关闭未写入的文件
+func (m *multipartBody) Close() error {
+	started := true
+
+	m.once.Do(func() { started = false })
+
+	if !started {
+		m.closeFiles(m.files)
+	}
+
+	return m.pr.Close()
+}
+
+// write 写入表单字段和文件, 出错时通过管道传递给读取方
+func (m *multipartBody) write() {
+	err := m.writeParts()
+	if err == nil {
+		err = m.writer.Close()
+	}
+
+	m.pw.CloseWithError(err)
+}
+
+// writeParts 依次写入表单字段和文件
+func (m *multipartBody) writeParts() error {
+	for key, vs := range m.fields {
+		for _, v := range vs {
+			if err := m.writer.WriteField(key, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, f := range m.files {
+		part, err := m.writer.CreateFormFile(f.field, f.filename)
+		if err == nil {
+			_, err = io.Copy(part, f.reader)
+		}
+
+		m.closeFiles(m.files[i : i+1])
+
+		if err != nil {
+			m.closeFiles(m.files[i+1:])
+			return fmt.Errorf("write file %s: %w", f.field, err)
+		}
+	}
+
+	return nil
+}
+
+// closeFiles 关闭实现了 io.Closer 的文件
+func (m *multipartBody) closeFiles(files []filePart) {
+	for _, f := range files {
+		if c, ok := f.reader.(io.Closer); ok {
+			_ = c.Close()
+		}
+	}
+}
+
+var (
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	timeType          = reflect.TypeFor[time.Time]()
+)
+
+// EncodeValues 将结构体编码为 url.Values, 与 gin 的 form 绑定使用相同的标签, 请求和服务端 DTO 可以共用.
+//   - 字段名取 form 标签, 没有标签时使用字段名, 标签为 "-" 时跳过, 支持 omitempty
+//   - 未设置标签的匿名结构体字段展开
+//   - 切片和数组编码为多个同名参数, nil 指针跳过
+//   - time.Time 默认使用 RFC3339, 可以通过 time_format 标签指定格式, "unix" 和 "unixmilli" 编码为时间戳
+//   - 实现 encoding.TextMarshaler 的类型使用 MarshalText
+func EncodeValues(v any) (url.Values, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return url.Values{}, nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %T", ErrNotStruct, v)
+	}
+
+	values := make(url.Values)
+	if err := encodeStruct(values, rv); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// encodeStruct 编码结构体字段
+func encodeStruct(values url.Values, rv reflect.Value) error {
+	rt := rv.Type()
+
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+
+		tag, hasTag := sf.Tag.Lookup("form")
+		if tag == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+
+		if sf.Anonymous && !hasTag {
+			for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+
+			if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+				if err := encodeStruct(values, fv); err != nil {
+					return err
+				}
+
+				continue
+			}
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+
+		if opts == "omitempty" && fv.IsZero() {
+			continue
+		}
+
+		if err := encodeField(values, name, fv, sf.Tag.Get("time_format")); err != nil {
+			return fmt.Errorf("field %s: %w", sf.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// encodeField 编码单个字段, 切片和数组编码为多个同名参数
+func encodeField(values url.Values, name string, fv reflect.Value, timeFormat string) error {
+	for fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+
+		fv = fv.Elem()
+	}
+
+	isBytes := fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Uint8
+	if (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array) && !isBytes {
+		for i := range fv.Len() {
+			if err := encodeField(values, name, fv.Index(i), timeFormat); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	s, err := formatValue(fv, timeFormat)
+	if err != nil {
+		return err
+	}
+
+	values.Add(name, s)
+
+	return nil
+}
+
+// formatValue 将单个值格式化为字符串
+func formatValue(fv reflect.Value, timeFormat string) (string, error) {
+	if fv.Type() == timeType {
+		t := fv.Interface().(time.Time)
+
+		switch timeFormat {
+		case "":
+			return t.Format(time.RFC3339), nil
+		case "unix":
+			return strconv.FormatInt(t.Unix(), 10), nil
+		case "unixmilli":
+			return strconv.FormatInt(t.UnixMilli(), 10), nil
+		default:
+			return t.Format(timeFormat), nil
+		}
+	}
+
+	if fv.Type().Implements(textMarshalerType) {
+		text, err := fv.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(fv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(fv.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(fv.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(fv.Float(), 'f', -1, 64), nil
+	case reflect.Slice:
+		// []byte 按字符串编码
+		return string(fv.Bytes()), nil
+	default:
+		return "", fmt.Errorf("unsupported kind %s", fv.Kind())
+	}
+}
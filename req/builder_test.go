@@ -0,0 +1,186 @@
+//
+// FilePath    : go-utils\req\builder_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 出站请求构建器单元测试
+//
+
+package req
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type pageQuery struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size,omitempty"`
+}
+
+type listQuery struct {
+	pageQuery
+
+	Keyword string     `form:"keyword,omitempty"`
+	IDs     []uint64   `form:"ids"`
+	Start   time.Time  `form:"start" time_format:"2006-01-02"`
+	End     *time.Time `form:"end" time_format:"unix"`
+	Ignored string     `form:"-"`
+	Enabled *bool      `form:"enabled"`
+}
+
+// TestEncodeValues 测试结构体编码为查询参数
+func TestEncodeValues(t *testing.T) {
+	end := time.Unix(1700000000, 0)
+	q := &listQuery{
+		pageQuery: pageQuery{Page: 2},
+		IDs:       []uint64{1, 2},
+		Start:     time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		End:       &end,
+		Ignored:   "x",
+	}
+
+	values, err := EncodeValues(q)
+	if err != nil {
+		t.Fatalf("编码失败: %v", err)
+	}
+
+	want := "end=1700000000&ids=1&ids=2&page=2&start=2026-01-02"
+	if got := values.Encode(); got != want {
+		t.Errorf("编码结果: %s, 期望: %s", got, want)
+	}
+
+	if _, err = EncodeValues(map[string]string{}); !errors.Is(err, ErrNotStruct) {
+		t.Errorf("非结构体应返回 ErrNotStruct, 实际: %v", err)
+	}
+}
+
+// TestRequestBuilder_Form 测试查询参数和表单请求体
+func TestRequestBuilder_Form(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("解析表单失败: %v", err)
+		}
+
+		if got := r.URL.Query().Encode(); got != "a=1&page=3" {
+			t.Errorf("查询参数: %s", got)
+		}
+
+		if got := r.PostForm.Encode(); got != "name=jiao&page=1" {
+			t.Errorf("表单: %s", got)
+		}
+
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("Content-Type: %s", ct)
+		}
+	}))
+	defer srv.Close()
+
+	resp, err := NewClient().NewRequest(context.Background(), http.MethodPost, srv.URL+"?a=1").
+		QueryStruct(pageQuery{Page: 3}).
+		Form("name", "jiao").
+		FormStruct(&pageQuery{Page: 1}).
+		Do()
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+
+	_ = resp.Body.Close()
+}
+
+// closeRecorder 记录是否已关闭的 io.Reader
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+// Close 实现 io.Closer 接口
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+// TestRequestBuilder_Multipart 测试流式 multipart 文件上传
+func TestRequestBuilder_Multipart(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength != -1 {
+			t.Errorf("流式上传不应设置 Content-Length, 实际: %d", r.ContentLength)
+		}
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("解析 multipart 失败: %v", err)
+			return
+		}
+
+		if got := r.FormValue("name"); got != "jiao" {
+			t.Errorf("表单字段: %s", got)
+		}
+
+		f, h, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("获取文件失败: %v", err)
+			return
+		}
+		defer f.Close()
+
+		data, _ := io.ReadAll(f)
+		if h.Filename != "a.txt" || string(data) != "hello" {
+			t.Errorf("文件: %s %q", h.Filename, data)
+		}
+	}))
+	defer srv.Close()
+
+	file := &closeRecorder{Reader: strings.NewReader("hello")}
+
+	resp, err := NewClient().NewRequest(context.Background(), http.MethodPost, srv.URL).
+		Form("name", "jiao").
+		File("file", "a.txt", file).
+		Do()
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+
+	_ = resp.Body.Close()
+
+	if !file.closed {
+		t.Errorf("上传完成后应关闭文件")
+	}
+}
+
+// TestRequestBuilder_Errors 测试构建错误
+func TestRequestBuilder_Errors(t *testing.T) {
+	c := NewClient()
+
+	_, err := c.NewRequest(context.Background(), http.MethodPost, "http://example.com").
+		Body("application/json", strings.NewReader("{}")).
+		Form("a", "1").
+		Build()
+	if !errors.Is(err, ErrBodyConflict) {
+		t.Errorf("同时设置请求体和表单应返回 ErrBodyConflict, 实际: %v", err)
+	}
+
+	_, err = c.NewRequest(context.Background(), http.MethodGet, "http://example.com").QueryStruct(1).Build()
+	if !errors.Is(err, ErrNotStruct) {
+		t.Errorf("查询参数不是结构体应返回 ErrNotStruct, 实际: %v", err)
+	}
+
+	// 未发送的 multipart 请求关闭后释放文件
+	file := &closeRecorder{Reader: strings.NewReader("hello")}
+
+	r, err := c.NewRequest(context.Background(), http.MethodPost, "http://example.com").File("file", "a.txt", file).Build()
+	if err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	_ = r.Body.Close()
+
+	if !file.closed {
+		t.Errorf("关闭请求体后应关闭文件")
+	}
+}
@@ -0,0 +1,71 @@
+//
+// FilePath    : go-utils\req\bulkhead.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 出站请求舱壁隔离, 限制单个目标的并发请求数
+//
+
+package req
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrBulkheadFull 并发请求数已达上限且等待超时, 请求被拒绝
+var ErrBulkheadFull = errors.New("bulkhead is full")
+
+// Bulkhead 舱壁, 使用信号量限制并发数, 并发安全
+type Bulkhead struct {
+	sem     chan struct{}
+	maxWait time.Duration
+}
+
+// NewBulkhead 创建舱壁
+//   - maxConcurrent: 最大并发数
+//   - maxWait: 并发已满时的最长等待时间, 为 0 时不等待直接拒绝
+func NewBulkhead(maxConcurrent int, maxWait time.Duration) *Bulkhead {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+
+	return &Bulkhead{sem: make(chan struct{}, maxConcurrent), maxWait: maxWait}
+}
+
+// Acquire 获取一个并发名额, 成功后必须调用 Release 释放.
+// 并发已满时最多等待 maxWait, 超时返回 ErrBulkheadFull, ctx 结束时返回 ctx.Err().
+func (b *Bulkhead) Acquire(ctx context.Context) error {
+	select {
+	case b.sem <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if b.maxWait <= 0 {
+		return ErrBulkheadFull
+	}
+
+	timer := time.NewTimer(b.maxWait)
+	defer timer.Stop()
+
+	select {
+	case b.sem <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrBulkheadFull
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release 释放一个并发名额
+func (b *Bulkhead) Release() {
+	<-b.sem
+}
+
+// InFlight 当前进行中的请求数
+func (b *Bulkhead) InFlight() int {
+	return len(b.sem)
+}
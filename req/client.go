@@ -0,0 +1,267 @@
+//
+// FilePath    : go-utils\req\client.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 出站 HTTP 客户端, 按目标主机熔断和舱壁隔离, 避免慢接口拖垮连接池
+//
+
+package req
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jiaopengzi/go-utils"
+)
+
+// HostStats 单个目标主机的请求统计
+type HostStats struct {
+	Host             string        `json:"host"`              // 目标主机
+	State            BreakerState  `json:"state"`             // 熔断器状态
+	InFlight         int           `json:"in_flight"`         // 进行中的请求数
+	Requests         int64         `json:"requests"`          // 实际发出的请求数
+	Failures         int64         `json:"failures"`          // 失败的请求数
+	BreakerRejected  int64         `json:"breaker_rejected"`  // 被熔断器拒绝的请求数
+	BulkheadRejected int64         `json:"bulkhead_rejected"` // 被舱壁拒绝的请求数
+	FailureRate      float64       `json:"failure_rate"`      // 最近一分钟平均每秒失败的请求数
+	LatencyP99       time.Duration `json:"latency_p99"`       // 最近 1000 次(5 分钟内)请求到收到响应头耗时的 P99, 用于发现慢接口
+}
+
+// 主机统计的窗口
+const (
+	hostRateWindow     = time.Minute     // 失败速率的统计窗口
+	hostLatencySamples = 1000            // 耗时分位数的样本数
+	hostLatencyMaxAge  = 5 * time.Minute // 耗时样本的最大存活时间
+)
+
+// hostGuard 单个目标主机的熔断器、舱壁和统计
+type hostGuard struct {
+	breaker  *Breaker
+	bulkhead *Bulkhead
+
+	requests         atomic.Int64
+	failures         atomic.Int64
+	breakerRejected  atomic.Int64
+	bulkheadRejected atomic.Int64
+	failureRate      *utils.RateMeter     // 失败速率
+	latency          *utils.SlidingWindow // 请求耗时, 单位纳秒
+}
+
+// GuardTransport 按目标主机熔断和舱壁隔离的 http.RoundTripper
+type GuardTransport struct {
+	Base          http.RoundTripper // 实际发送请求的 RoundTripper, 为 nil 时使用 http.DefaultTransport
+	Breaker       BreakerConfig     // 熔断器配置
+	MaxConcurrent int               // 每个主机的最大并发数, 为 0 时不限制
+	MaxWait       time.Duration     // 并发已满时的最长等待时间
+
+	hosts sync.Map // host -> *hostGuard
+}
+
+// guard 获取目标主机的 hostGuard, 不存在时创建
+func (t *GuardTransport) guard(host string) *hostGuard {
+	if g, ok := t.hosts.Load(host); ok {
+		return g.(*hostGuard)
+	}
+
+	g := &hostGuard{
+		breaker:     NewBreaker(t.Breaker),
+		failureRate: utils.NewRateMeter(hostRateWindow, 60),
+		latency:     utils.NewSlidingWindow(hostLatencySamples, hostLatencyMaxAge),
+	}
+	if t.MaxConcurrent > 0 {
+		g.bulkhead = NewBulkhead(t.MaxConcurrent, t.MaxWait)
+	}
+
+	actual, _ := t.hosts.LoadOrStore(host, g)
+
+	return actual.(*hostGuard)
+}
+
+// RoundTrip 实现 http.RoundTripper 接口.
+// 熔断器打开时返回 ErrCircuitOpen, 舱壁已满时返回 ErrBulkheadFull, 均不会发出请求.
+func (t *GuardTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	g := t.guard(r.URL.Host)
+
+	// 先获取并发名额, 避免未发出的请求影响熔断器的半开试探
+	if g.bulkhead != nil {
+		if err := g.bulkhead.Acquire(r.Context()); err != nil {
+			g.bulkheadRejected.Add(1)
+			return nil, err
+		}
+
+		defer g.bulkhead.Release()
+	}
+
+	done, err := g.breaker.Allow()
+	if err != nil {
+		g.breakerRejected.Add(1)
+		return nil, err
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	g.requests.Add(1)
+
+	start := time.Now()
+	resp, err := base.RoundTrip(r)
+	g.latency.Add(float64(time.Since(start)))
+
+	failed := g.breaker.cfg.IsFailure(resp, err)
+	if failed {
+		g.failures.Add(1)
+		g.failureRate.Mark(1)
+	}
+
+	done(failed)
+
+	return resp, err
+}
+
+// Stats 获取所有目标主机的请求统计, 按主机名排序
+func (t *GuardTransport) Stats() []HostStats {
+	var stats []HostStats
+
+	t.hosts.Range(func(key, value any) bool {
+		g := value.(*hostGuard)
+
+		s := HostStats{
+			Host:             key.(string),
+			State:            g.breaker.State(),
+			Requests:         g.requests.Load(),
+			Failures:         g.failures.Load(),
+			BreakerRejected:  g.breakerRejected.Load(),
+			BulkheadRejected: g.bulkheadRejected.Load(),
+			FailureRate:      g.failureRate.Rate(),
+			LatencyP99:       time.Duration(g.latency.Percentile(99)),
+		}
+
+		if g.bulkhead != nil {
+			s.InFlight = g.bulkhead.InFlight()
+		}
+
+		stats = append(stats, s)
+
+		return true
+	})
+
+	utils.SortSliceBy(stats, func(s HostStats) string { return s.Host }, true)
+
+	return stats
+}
+
+// Client 出站 HTTP 客户端
+type Client struct {
+	httpClient *http.Client
+	guard      *GuardTransport
+	timeout    time.Duration // 每次尝试的超时时间
+	retry      *RetryPolicy  // 重试策略, 为 nil 时不重试
+	logging    bool          // 是否记录请求日志
+}
+
+// ClientOption 定义 Client 的可选配置函数类型
+type ClientOption func(*Client)
+
+// WithHTTPClient 设置底层 http.Client, 其 Transport 会被包装为 GuardTransport 的 Base
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		clone := *hc
+		c.httpClient = &clone
+	}
+}
+
+// WithBreaker 设置每个目标主机的熔断器配置
+func WithBreaker(cfg BreakerConfig) ClientOption {
+	return func(c *Client) {
+		c.guard.Breaker = cfg
+	}
+}
+
+// WithBulkhead 设置每个目标主机的最大并发数和并发已满时的最长等待时间
+func WithBulkhead(maxConcurrent int, maxWait time.Duration) ClientOption {
+	return func(c *Client) {
+		c.guard.MaxConcurrent = maxConcurrent
+		c.guard.MaxWait = maxWait
+	}
+}
+
+// NewClient 创建出站 HTTP 客户端, 默认按目标主机启用熔断器(连续 5 次失败熔断 30 秒), 不限制并发
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{httpClient: &http.Client{}, guard: &GuardTransport{}}
+
+	for _, apply := range opts {
+		apply(c)
+	}
+
+	c.guard.Base = c.httpClient.Transport
+	c.httpClient.Transport = c.guard
+
+	return c
+}
+
+// Do 发送请求, 按配置设置超时、重试和记录日志, 请求头中没有 X-Request-ID 时透传上下文中的请求ID.
+// 与 http.Client 一致, 得到响应时不论状态码都返回 nil 错误, 可使用 CheckStatus 检查;
+// 未得到响应时返回 *RequestError. 重试前会丢弃上一次的响应.
+//   - r: 请求, 需要重试带请求体的请求时 GetBody 不能为 nil(http.NewRequest 使用 bytes、strings 类型的 Reader 时自动设置)
+func (c *Client) Do(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	setRequestID(r)
+
+	var attempts int
+
+	resp, err := utils.RetryValue(r.Context(), func(context.Context) (*http.Response, error) {
+		attempts++
+
+		ar, err := attemptRequest(r, attempts)
+		if err != nil {
+			return nil, utils.Permanent(err)
+		}
+
+		resp, err := c.doOnce(ar)
+		if !c.retry.shouldRetry(r, attempts, resp, err) {
+			return resp, utils.Permanent(err)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &retryableResponse{resp: resp}
+	}, append(c.retry.retryOptions(), utils.WithOnRetry(func(_ int, err error, _ time.Duration) {
+		// 丢弃本次响应, 连接可以复用
+		var rr *retryableResponse
+		if errors.As(err, &rr) {
+			rr.discard()
+		}
+	}))...)
+
+	// 只有调用方取消时才会以需要重试的响应结束
+	var rr *retryableResponse
+	if errors.As(err, &rr) {
+		rr.discard()
+		resp, err = nil, r.Context().Err()
+	}
+
+	if c.logging {
+		c.logRequest(r, resp, err, attempts, time.Since(start))
+	}
+
+	if err != nil {
+		return nil, &RequestError{Method: r.Method, URL: redactURL(r), Attempts: attempts, Err: err}
+	}
+
+	return resp, nil
+}
+
+// Stats 获取所有目标主机的请求统计, 可用于暴露监控指标
+func (c *Client) Stats() []HostStats {
+	return c.guard.Stats()
+}
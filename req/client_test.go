@@ -0,0 +1,220 @@
+//
+// FilePath    : go-utils\req\client_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 出站 HTTP 客户端熔断和舱壁隔离单元测试
+//
+
+package req
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestBreaker 测试熔断器状态转换
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	b := NewBreaker(BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute})
+	b.now = func() time.Time { return now }
+
+	for range 2 {
+		done, err := b.Allow()
+		if err != nil {
+			t.Fatalf("关闭状态应放行: %v", err)
+		}
+
+		done(true)
+	}
+
+	if state := b.State(); state != BreakerOpen {
+		t.Fatalf("连续失败后状态应为 open, 实际: %s", state)
+	}
+
+	if _, err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("打开状态应拒绝, 实际: %v", err)
+	}
+
+	// 冷却后进入半开, 只放行一个试探请求
+	now = now.Add(time.Minute)
+
+	done, err := b.Allow()
+	if err != nil {
+		t.Fatalf("半开状态应放行试探请求: %v", err)
+	}
+
+	if _, err = b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("半开状态应拒绝多余的请求, 实际: %v", err)
+	}
+
+	// 试探失败重新打开
+	done(true)
+
+	if state := b.State(); state != BreakerOpen {
+		t.Fatalf("试探失败后状态应为 open, 实际: %s", state)
+	}
+
+	// 试探成功关闭
+	now = now.Add(time.Minute)
+
+	done, err = b.Allow()
+	if err != nil {
+		t.Fatalf("半开状态应放行试探请求: %v", err)
+	}
+
+	done(false)
+
+	if state := b.State(); state != BreakerClosed {
+		t.Fatalf("试探成功后状态应为 closed, 实际: %s", state)
+	}
+}
+
+// TestBreaker_SuccessResetsFailures 测试成功请求重置连续失败次数
+func TestBreaker_SuccessResetsFailures(t *testing.T) {
+	b := NewBreaker(BreakerConfig{FailureThreshold: 2})
+
+	for _, failed := range []bool{true, false, true} {
+		done, err := b.Allow()
+		if err != nil {
+			t.Fatalf("关闭状态应放行: %v", err)
+		}
+
+		done(failed)
+	}
+
+	if state := b.State(); state != BreakerClosed {
+		t.Fatalf("失败不连续时状态应为 closed, 实际: %s", state)
+	}
+}
+
+// TestBulkhead 测试舱壁并发限制
+func TestBulkhead(t *testing.T) {
+	b := NewBulkhead(1, 10*time.Millisecond)
+
+	if err := b.Acquire(context.Background()); err != nil {
+		t.Fatalf("获取名额失败: %v", err)
+	}
+
+	if err := b.Acquire(context.Background()); !errors.Is(err, ErrBulkheadFull) {
+		t.Fatalf("并发已满应返回 ErrBulkheadFull, 实际: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// 共享信号量且等待时间较长的舱壁, 验证 ctx 取消
+	waiting := &Bulkhead{sem: b.sem, maxWait: time.Minute}
+	if err := waiting.Acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ctx 取消应返回 context.Canceled, 实际: %v", err)
+	}
+
+	b.Release()
+
+	if err := b.Acquire(context.Background()); err != nil {
+		t.Fatalf("释放后应能获取名额: %v", err)
+	}
+}
+
+// TestClient_Breaker 测试客户端按主机熔断
+func TestClient_Breaker(t *testing.T) {
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer bad.Close()
+
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer good.Close()
+
+	c := NewClient(WithBreaker(BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute}))
+
+	get := func(url string) (*http.Response, error) {
+		r, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, url, http.NoBody)
+
+		resp, err := c.Do(r)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+
+		return resp, err
+	}
+
+	for range 2 {
+		if _, err := get(bad.URL); err != nil {
+			t.Fatalf("熔断前应发出请求: %v", err)
+		}
+	}
+
+	if _, err := get(bad.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("熔断后应返回 ErrCircuitOpen, 实际: %v", err)
+	}
+
+	// 其他主机不受影响
+	if resp, err := get(good.URL); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("其他主机应正常请求: %v", err)
+	}
+
+	stats := c.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("应有 2 个主机的统计, 实际: %d", len(stats))
+	}
+
+	for _, s := range stats {
+		if s.Host != bad.Listener.Addr().String() {
+			continue
+		}
+
+		if s.State != BreakerOpen || s.Requests != 2 || s.Failures != 2 || s.BreakerRejected != 1 {
+			t.Errorf("统计不符合预期: %+v", s)
+		}
+
+		if s.FailureRate != 2/hostRateWindow.Seconds() || s.LatencyP99 <= 0 {
+			t.Errorf("失败速率或耗时 P99 不符合预期: %+v", s)
+		}
+	}
+}
+
+// TestClient_Bulkhead 测试客户端按主机限制并发
+func TestClient_Bulkhead(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBulkhead(1, 0))
+
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+		r, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, http.NoBody)
+		if resp, err := c.Do(r); err == nil {
+			_ = resp.Body.Close()
+		}
+	})
+
+	<-started
+
+	r, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, http.NoBody)
+	if _, err := c.Do(r); !errors.Is(err, ErrBulkheadFull) {
+		t.Fatalf("并发已满应返回 ErrBulkheadFull, 实际: %v", err)
+	}
+
+	if stats := c.Stats(); len(stats) != 1 || stats[0].InFlight != 1 || stats[0].BulkheadRejected != 1 {
+		t.Errorf("统计不符合预期: %+v", stats)
+	}
+
+	close(release)
+	wg.Wait()
+}
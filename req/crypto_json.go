@@ -15,14 +15,14 @@ import (
 
 	"github.com/jiaopengzi/cert/core"
 	utilC "github.com/jiaopengzi/cert/utils"
-	"github.com/jiaopengzi/go-utils"
+	"github.com/jiaopengzi/go-utils/reflectutil"
 )
 
 // EncryptJSON 使用证书 certPEM 加密任意结构体 data, 并返回 Base64 编码的密文和 nonce.
 // 如果 data 为 nil, 则返回空密文和有效的 nonce.
 func EncryptJSON(data any, certPEM string) (string, string, error) {
 	// 如果 data 为 nil, 生成 nonce 并返回空密文.
-	if utils.IsInterfaceNil(data) {
+	if reflectutil.IsNil(data) {
 		nonce, errN := utilC.GenerateGCMNonce()
 		if errN != nil {
 			return "", "", fmt.Errorf("generate nonce: %w", errN)
@@ -51,7 +51,7 @@ func EncryptJSON(data any, certPEM string) (string, string, error) {
 // 如果 encryptedB64 为空字符串, 则直接返回 nil.
 func DecryptJSON(encryptedB64, keyPEM string, dst any) error {
 	// 如果 dst 不是指针类型, 返回错误.
-	if !utils.IsPointer(dst) {
+	if !reflectutil.IsPointer(dst) {
 		return fmt.Errorf("dst %T must be a pointer", dst)
 	}
 
@@ -0,0 +1,242 @@
+//
+// FilePath    : go-utils\req\envelope.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 带密钥 ID 的加密 JSON 信封, 接收方按密钥 ID 从 KeyStore 查找私钥, 支持证书平滑轮换
+//
+
+package req
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/cert/core"
+	"github.com/jiaopengzi/go-utils/reflectutil"
+)
+
+// ErrUnknownKeyID 信封中的密钥 ID 在 KeyStore 中不存在, 例如证书已下线
+var ErrUnknownKeyID = errors.New("unknown key id")
+
+// KeyEnvelopeKeyID 解密中间件在 gin.Context 中保存请求信封密钥 ID 的 key, 便于使用同一证书加密响应
+const KeyEnvelopeKeyID = "envelopeKeyID"
+
+// Envelope 加密 JSON 信封, 与 EncryptedData 兼容, 旧客户端不带 kid 时使用 KeyStore 的当前密钥解密
+type Envelope struct {
+	KeyID      string `json:"kid,omitempty" example:"sha256:abc123"` // 密钥 ID, 加密证书的 sha256 指纹
+	CipherText string `json:"cipher_text" example:"cipher_text"`     // 密文, Base64 编码, nonce 前置
+}
+
+// KeyID 计算证书的密钥 ID, 即证书的 sha256 指纹, 例如 "sha256:abc123..."
+//   - certPEM: 证书 PEM
+func KeyID(certPEM string) (string, error) {
+	return core.GetCertFingerprint(certPEM, core.HashAlgoSHA256)
+}
+
+// EncryptEnvelope 使用证书 certPEM 加密 data, 返回带密钥 ID 的信封
+//   - data: 待加密的数据, 为 nil 时密文为空
+//   - certPEM: 接收方证书 PEM
+func EncryptEnvelope(data any, certPEM string) (*Envelope, error) {
+	keyID, err := KeyID(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("key id: %w", err)
+	}
+
+	cipherText, _, err := EncryptJSON(data, certPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{KeyID: keyID, CipherText: cipherText}, nil
+}
+
+// DecryptEnvelope 按信封的密钥 ID 从 store 查找私钥, 解密到 dst, dst 应为指针类型.
+// 密文为空时直接返回 nil.
+//   - env: 信封
+//   - store: 私钥存储
+//   - dst: 目标结构指针
+func DecryptEnvelope(env *Envelope, store KeyStore, dst any) error {
+	if !reflectutil.IsPointer(dst) {
+		return fmt.Errorf("dst %T must be a pointer", dst)
+	}
+
+	plaintext, err := openEnvelope(env, store)
+	if err != nil || plaintext == nil {
+		return err
+	}
+
+	return json.Unmarshal(plaintext, dst)
+}
+
+// openEnvelope 解密信封, 返回明文 JSON; 密文为空时返回 nil
+func openEnvelope(env *Envelope, store KeyStore) ([]byte, error) {
+	if env.CipherText == "" {
+		return nil, nil
+	}
+
+	keyPEM, err := store.PrivateKey(env.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %w", env.KeyID, err)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(env.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("base64 decode: %w", err)
+	}
+
+	plaintext, err := core.DecryptWithKey(keyPEM, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// KeyStore 私钥存储, 按密钥 ID 查找私钥
+type KeyStore interface {
+	// PrivateKey 返回密钥 ID 对应的私钥 PEM, 不存在时返回 ErrUnknownKeyID.
+	// keyID 为空时返回当前密钥, 兼容不带 kid 的旧信封.
+	PrivateKey(keyID string) (string, error)
+}
+
+// 确保 MemoryKeyStore 实现了 KeyStore 接口
+var _ KeyStore = (*MemoryKeyStore)(nil)
+
+// keyPair 证书和私钥
+type keyPair struct {
+	certPEM string
+	keyPEM  string
+}
+
+// MemoryKeyStore 内存私钥存储, 并发安全.
+// 证书轮换时先 Add 新证书(成为当前证书), 旧证书保留到使用它加密的客户端全部更新后再 Remove.
+type MemoryKeyStore struct {
+	mu      sync.RWMutex
+	keys    map[string]keyPair
+	current string // 当前密钥 ID
+}
+
+// NewMemoryKeyStore 创建内存私钥存储
+func NewMemoryKeyStore() *MemoryKeyStore {
+	return &MemoryKeyStore{keys: make(map[string]keyPair)}
+}
+
+// Add 添加证书和私钥并设为当前证书, 返回密钥 ID
+//   - certPEM: 证书 PEM
+//   - keyPEM: 私钥 PEM
+func (s *MemoryKeyStore) Add(certPEM, keyPEM string) (string, error) {
+	keyID, err := KeyID(certPEM)
+	if err != nil {
+		return "", fmt.Errorf("key id: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[keyID] = keyPair{certPEM: certPEM, keyPEM: keyPEM}
+	s.current = keyID
+
+	return keyID, nil
+}
+
+// Remove 移除密钥 ID 对应的证书, 移除当前证书后不再有当前证书, 不带 kid 的信封无法解密
+//   - keyID: 密钥 ID
+func (s *MemoryKeyStore) Remove(keyID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, keyID)
+
+	if s.current == keyID {
+		s.current = ""
+	}
+}
+
+// Current 返回当前证书的密钥 ID 和证书 PEM, 用于下发给客户端加密
+func (s *MemoryKeyStore) Current() (keyID, certPEM string, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pair, ok := s.keys[s.current]
+	if !ok {
+		return "", "", ErrUnknownKeyID
+	}
+
+	return s.current, pair.certPEM, nil
+}
+
+// PrivateKey 实现 KeyStore 接口
+func (s *MemoryKeyStore) PrivateKey(keyID string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if keyID == "" {
+		keyID = s.current
+	}
+
+	pair, ok := s.keys[keyID]
+	if !ok {
+		return "", ErrUnknownKeyID
+	}
+
+	return pair.keyPEM, nil
+}
+
+// BindEnvelope 读取请求体中的信封并解密到 dst, dst 应为指针类型; 请求体为空时直接返回 nil
+//   - c: gin 上下文
+//   - store: 私钥存储
+//   - dst: 目标结构指针
+func BindEnvelope(c *gin.Context, store KeyStore, dst any) error {
+	if c.Request.ContentLength == 0 {
+		return nil
+	}
+
+	var env Envelope
+	if err := c.ShouldBindJSON(&env); err != nil {
+		return err
+	}
+
+	c.Set(KeyEnvelopeKeyID, env.KeyID)
+
+	return DecryptEnvelope(&env, store, dst)
+}
+
+// DecryptBody gin 中间件, 将加密信封请求体解密为明文 JSON 写回请求体, 后续处理函数直接使用 ShouldBindJSON.
+// 信封格式错误或解密失败时返回 400; 请求体为空时不处理.
+//   - store: 私钥存储
+func DecryptBody(store KeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength == 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		var env Envelope
+		if err := c.ShouldBindJSON(&env); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "加密请求体格式错误"})
+			return
+		}
+
+		plaintext, err := openEnvelope(&env, store)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "请求体解密失败"})
+			return
+		}
+
+		c.Set(KeyEnvelopeKeyID, env.KeyID)
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(plaintext))
+		c.Request.ContentLength = int64(len(plaintext))
+		c.Request.Header.Set("Content-Type", gin.MIMEJSON)
+
+		c.Next()
+	}
+}
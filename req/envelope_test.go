@@ -0,0 +1,155 @@
+//
+// FilePath    : go-utils\req\envelope_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 加密 JSON 信封和证书轮换单元测试
+//
+
+package req
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type envelopePayload struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestEnvelope_KeyRotation(t *testing.T) {
+	oldCert, oldKey := generateTestCertECDSA(t)
+	newCert, newKey := generateTestCertRSA(t)
+
+	store := NewMemoryKeyStore()
+
+	oldID, err := store.Add(oldCert, oldKey)
+	if err != nil {
+		t.Fatalf("Add old error: %v", err)
+	}
+
+	// 轮换前使用旧证书加密的信封
+	oldEnv, err := EncryptEnvelope(envelopePayload{Name: "old", Age: 1}, oldCert)
+	if err != nil {
+		t.Fatalf("EncryptEnvelope error: %v", err)
+	}
+
+	if oldEnv.KeyID != oldID {
+		t.Fatalf("KeyID = %q, want %q", oldEnv.KeyID, oldID)
+	}
+
+	newID, err := store.Add(newCert, newKey)
+	if err != nil {
+		t.Fatalf("Add new error: %v", err)
+	}
+
+	if id, _, _ := store.Current(); id != newID {
+		t.Fatalf("Current() = %q, want %q", id, newID)
+	}
+
+	newEnv, err := EncryptEnvelope(envelopePayload{Name: "new", Age: 2}, newCert)
+	if err != nil {
+		t.Fatalf("EncryptEnvelope error: %v", err)
+	}
+
+	// 新旧证书加密的信封都能解密
+	for _, tc := range []struct {
+		env  *Envelope
+		want string
+	}{{oldEnv, "old"}, {newEnv, "new"}} {
+		var got envelopePayload
+		if err := DecryptEnvelope(tc.env, store, &got); err != nil {
+			t.Fatalf("DecryptEnvelope(%s) error: %v", tc.want, err)
+		}
+
+		if got.Name != tc.want {
+			t.Errorf("DecryptEnvelope() name = %q, want %q", got.Name, tc.want)
+		}
+	}
+
+	// 不带 kid 的旧格式使用当前证书
+	legacy, _, err := EncryptJSON(envelopePayload{Name: "legacy"}, newCert)
+	if err != nil {
+		t.Fatalf("EncryptJSON error: %v", err)
+	}
+
+	var got envelopePayload
+	if err := DecryptEnvelope(&Envelope{CipherText: legacy}, store, &got); err != nil || got.Name != "legacy" {
+		t.Fatalf("DecryptEnvelope(legacy) = %+v, %v", got, err)
+	}
+
+	// 旧证书下线后无法解密
+	store.Remove(oldID)
+
+	if err := DecryptEnvelope(oldEnv, store, &got); !errors.Is(err, ErrUnknownKeyID) {
+		t.Fatalf("DecryptEnvelope() after Remove error = %v, want ErrUnknownKeyID", err)
+	}
+}
+
+func TestDecryptBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	certPEM, keyPEM := generateTestCertEd25519(t)
+
+	store := NewMemoryKeyStore()
+	if _, err := store.Add(certPEM, keyPEM); err != nil {
+		t.Fatalf("Add error: %v", err)
+	}
+
+	r := gin.New()
+	r.POST("/", DecryptBody(store), func(c *gin.Context) {
+		var p envelopePayload
+		if err := c.ShouldBindJSON(&p); err != nil {
+			c.Status(http.StatusUnprocessableEntity)
+			return
+		}
+
+		c.JSON(http.StatusOK, p)
+	})
+
+	env, err := EncryptEnvelope(envelopePayload{Name: "alice", Age: 30}, certPEM)
+	if err != nil {
+		t.Fatalf("EncryptEnvelope error: %v", err)
+	}
+
+	body, _ := json.Marshal(env)
+
+	tests := []struct {
+		name     string
+		body     []byte
+		wantCode int
+	}{
+		{"正常解密", body, http.StatusOK},
+		{"格式错误", []byte(`{invalid`), http.StatusBadRequest},
+		{"未知密钥", []byte(`{"kid":"sha256:unknown","cipher_text":"YQ=="}`), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.wantCode, w.Body.String())
+			}
+
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var got envelopePayload
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || got.Name != "alice" || got.Age != 30 {
+				t.Errorf("response = %s, err = %v", w.Body.String(), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+//
+// FilePath    : go-utils\req\retry.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 出站 HTTP 客户端的超时、指数退避重试、请求ID透传和请求日志
+//
+
+package req
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jiaopengzi/go-utils"
+	"github.com/jiaopengzi/go-utils/res"
+	"go.uber.org/zap"
+)
+
+// HeaderRequestID 透传请求ID的请求头
+const HeaderRequestID = "X-Request-ID"
+
+// defaultRetryBackoff 默认首次重试间隔
+const defaultRetryBackoff = 200 * time.Millisecond
+
+// RequestError 请求未得到响应的错误, 包括网络错误、超时、熔断和舱壁拒绝, 可通过 errors.Is 判断原因, 例如 ErrCircuitOpen
+type RequestError struct {
+	Method   string // 请求方法
+	URL      string // 请求地址, 不含查询参数
+	Attempts int    // 尝试次数
+	Err      error  // 最后一次尝试的错误
+}
+
+// Error 实现 error 接口
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s %s 请求失败(尝试 %d 次): %v", e.Method, e.URL, e.Attempts, e.Err)
+}
+
+// Unwrap 返回最后一次尝试的错误
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// StatusError 响应状态码不是 2xx 的错误, 由 CheckStatus 返回
+type StatusError struct {
+	Method     string // 请求方法
+	URL        string // 请求地址, 不含查询参数
+	StatusCode int    // 响应状态码
+	Body       string // 响应体的前 512 个字节, 便于排查
+}
+
+// Error 实现 error 接口
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s 响应状态码 %d: %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+// CheckStatus 检查响应状态码, 不是 2xx 时读取部分响应体并关闭, 返回 *StatusError
+//   - resp: 响应
+func CheckStatus(resp *http.Response) error {
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	return &StatusError{
+		Method:     resp.Request.Method,
+		URL:        redactURL(resp.Request),
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+	}
+}
+
+// RetryPolicy 请求失败时的重试策略, 使用指数退避
+type RetryPolicy struct {
+	MaxAttempts int                                                        // 最大尝试次数(含首次), 小于等于 1 表示不重试
+	Backoff     time.Duration                                              // 首次重试间隔, 为 0 时默认 200 毫秒
+	MaxBackoff  time.Duration                                              // 最大重试间隔, 为 0 表示不限制
+	Multiplier  float64                                                    // 每次重试间隔的倍数, 小于 1 时默认 2
+	RetryIf     func(r *http.Request, resp *http.Response, err error) bool // 判断是否需要重试, 为 nil 时使用 DefaultRetryIf
+}
+
+// DefaultRetryIf 默认的重试条件: 幂等请求(GET、HEAD、OPTIONS、PUT、DELETE)遇到网络错误、超时或 5xx 响应时重试;
+// 熔断和舱壁拒绝、调用方取消时不重试
+func DefaultRetryIf(r *http.Request, resp *http.Response, err error) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	if err != nil {
+		return !errors.Is(err, ErrCircuitOpen) && !errors.Is(err, ErrBulkheadFull) && r.Context().Err() == nil
+	}
+
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// retryOptions 转换为 utils.Retry 的配置, 是否重试由 shouldRetry 判断, 不重试的结果以 utils.Permanent 返回
+func (p *RetryPolicy) retryOptions() []utils.RetryOption {
+	if p == nil {
+		return []utils.RetryOption{utils.WithMaxAttempts(1)}
+	}
+
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	return []utils.RetryOption{
+		utils.WithMaxAttempts(p.MaxAttempts),
+		utils.WithBackoff(utils.ExponentialBackoff(backoff, p.MaxBackoff, p.Multiplier)),
+		utils.RetryIf(func(error) bool { return true }),
+	}
+}
+
+// retryableResponse 需要重试的响应, 作为 utils.Retry 的错误传递
+type retryableResponse struct {
+	resp *http.Response
+}
+
+// Error 实现 error 接口
+func (e *retryableResponse) Error() string {
+	return fmt.Sprintf("响应状态码 %d, 需要重试", e.resp.StatusCode)
+}
+
+// discard 丢弃响应体并关闭
+func (e *retryableResponse) discard() {
+	_, _ = io.Copy(io.Discard, io.LimitReader(e.resp.Body, 4<<10))
+	_ = e.resp.Body.Close()
+}
+
+// shouldRetry 判断第 attempt 次尝试后是否需要重试, 有请求体但无法重放(GetBody 为 nil)时不重试
+func (p *RetryPolicy) shouldRetry(r *http.Request, attempt int, resp *http.Response, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+
+	if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+		return false
+	}
+
+	retryIf := p.RetryIf
+	if retryIf == nil {
+		retryIf = DefaultRetryIf
+	}
+
+	return retryIf(r, resp, err)
+}
+
+// WithTimeout 设置每次尝试的超时时间, 包括读取响应体, 调用方的 ctx 仍然生效
+//   - timeout: 超时时间, 为 0 时不限制
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetry 设置请求失败时的重试策略
+//   - policy: 重试策略
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = &policy
+	}
+}
+
+// WithLogging 开启请求日志, 每个请求完成后记录方法、地址、状态码、耗时和尝试次数
+func WithLogging() ClientOption {
+	return func(c *Client) {
+		c.logging = true
+	}
+}
+
+// cancelOnClose 关闭响应体时取消单次尝试的超时上下文
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close 关闭响应体并取消上下文
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
+
+// doOnce 发送一次请求, 设置了超时时间时使用单独的超时上下文
+//   - r: 请求, 重试时为重放请求体后的副本
+func (c *Client) doOnce(r *http.Request) (*http.Response, error) {
+	if c.timeout <= 0 {
+		return c.httpClient.Do(r)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), c.timeout)
+
+	resp, err := c.httpClient.Do(r.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// attemptRequest 获取第 attempt 次尝试(从 1 开始)使用的请求, 重试时重放请求体
+func attemptRequest(r *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 || r.GetBody == nil {
+		return r, nil
+	}
+
+	body, err := r.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	ar := r.Clone(r.Context())
+	ar.Body = body
+
+	return ar, nil
+}
+
+// setRequestID 请求头中没有请求ID时, 从上下文中获取链路追踪ID或 gin 上下文中的请求ID透传给下游
+func setRequestID(r *http.Request) {
+	if r.Header.Get(HeaderRequestID) != "" {
+		return
+	}
+
+	requestID := utils.TraceIDFromContext(r.Context())
+	if requestID == "" {
+		// 使用 gin.Context 作为 ctx 时可以取到请求ID
+		requestID, _ = r.Context().Value(res.KeyRequestID).(string)
+	}
+
+	if requestID != "" {
+		r.Header.Set(HeaderRequestID, requestID)
+	}
+}
+
+// redactURL 获取不含查询参数的请求地址, 避免在日志和错误中记录查询参数中的敏感信息
+func redactURL(r *http.Request) string {
+	u := *r.URL
+	u.RawQuery = ""
+	u.User = nil
+
+	return u.String()
+}
+
+// logRequest 记录请求日志
+func (c *Client) logRequest(r *http.Request, resp *http.Response, err error, attempts int, cost time.Duration) {
+	fields := []zap.Field{
+		zap.String("method", r.Method),
+		zap.String("url", redactURL(r)),
+		zap.Duration("cost", cost),
+		zap.Int("attempts", attempts),
+	}
+
+	if requestID := r.Header.Get(HeaderRequestID); requestID != "" {
+		fields = append(fields, zap.String("requestID", requestID))
+	}
+
+	switch {
+	case err != nil:
+		zap.L().Warn("[HTTP Client]", append(fields, zap.Error(err))...)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		zap.L().Warn("[HTTP Client]", append(fields, zap.Int("status", resp.StatusCode))...)
+	default:
+		zap.L().Info("[HTTP Client]", append(fields, zap.Int("status", resp.StatusCode))...)
+	}
+}
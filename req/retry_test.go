@@ -0,0 +1,129 @@
+//
+// FilePath    : go-utils\req\retry_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 出站 HTTP 客户端超时、重试和请求ID透传单元测试
+//
+
+package req
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jiaopengzi/go-utils"
+)
+
+// TestClient_Retry 测试 5xx 响应重试并重放请求体
+func TestClient_Retry(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("第 %d 次请求体: %q", calls.Load()+1, body)
+		}
+
+		if r.Header.Get(HeaderRequestID) != "trace-1" {
+			t.Errorf("请求ID未透传: %q", r.Header.Get(HeaderRequestID))
+		}
+
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+
+	ctx := utils.ContextWithTraceID(context.Background(), "trace-1")
+	r, _ := http.NewRequestWithContext(ctx, http.MethodPut, srv.URL, strings.NewReader("payload"))
+
+	resp, err := c.Do(r)
+	if err != nil {
+		t.Fatalf("重试后应成功: %v", err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Fatalf("状态码 %d, 请求次数 %d", resp.StatusCode, calls.Load())
+	}
+}
+
+// TestClient_RetryNonIdempotent 测试默认不重试非幂等请求, 重试次数用完后返回最后一次响应
+func TestClient_RetryNonIdempotent(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+
+	for _, tt := range []struct {
+		method string
+		want   int32
+	}{
+		{http.MethodPost, 1},
+		{http.MethodGet, 3},
+	} {
+		calls.Store(0)
+
+		r, _ := http.NewRequestWithContext(context.Background(), tt.method, srv.URL, http.NoBody)
+
+		resp, err := c.Do(r)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.method, err)
+		}
+
+		var statusErr *StatusError
+		if err = CheckStatus(resp); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+			t.Fatalf("%s: CheckStatus 应返回 StatusError, 实际: %v", tt.method, err)
+		}
+
+		if calls.Load() != tt.want {
+			t.Errorf("%s: 请求次数 %d, want %d", tt.method, calls.Load(), tt.want)
+		}
+	}
+}
+
+// TestClient_Timeout 测试单次尝试超时返回 RequestError
+func TestClient_Timeout(t *testing.T) {
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := NewClient(WithTimeout(20*time.Millisecond), WithRetry(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}))
+
+	r, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"?token=secret", http.NoBody)
+
+	_, err := c.Do(r)
+
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("应返回超时的 RequestError, 实际: %v", err)
+	}
+
+	if reqErr.Attempts != 2 || strings.Contains(reqErr.URL, "secret") {
+		t.Errorf("RequestError 不符合预期: %+v", reqErr)
+	}
+}
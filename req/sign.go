@@ -152,10 +152,7 @@ func BuildQueryString(params map[string]string) string {
 	}
 
 	// 1. 提取所有 key
-	keys := make([]string, 0, len(params))
-	for k := range params {
-		keys = append(keys, k)
-	}
+	keys := utils.Keys(params)
 
 	// 2. 对 key 进行排序
 	slices.Sort(keys)
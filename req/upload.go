@@ -0,0 +1,127 @@
+//
+// FilePath    : go-utils\req\upload.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : multipart 文件上传, 流式发送文件并携带文件哈希, 支持上传进度回调
+//
+
+package req
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/jiaopengzi/go-utils"
+)
+
+// 文件上传请求头
+const (
+	HeaderFileHash          = "X-File-Hash"           // 文件哈希, 十六进制
+	HeaderFileHashAlgorithm = "X-File-Hash-Algorithm" // 文件哈希算法, 例如 SHA-256
+)
+
+// ProgressFunc 上传进度回调, 在写入请求体的 goroutine 中调用, 不应阻塞
+//   - sent: 已发送的文件字节数
+//   - total: 文件总字节数
+type ProgressFunc func(sent, total int64)
+
+// uploadConfig 文件上传配置
+type uploadConfig struct {
+	field      string              // 文件表单字段名
+	algorithm  utils.HashAlgorithm // 哈希算法
+	onProgress ProgressFunc        // 进度回调
+}
+
+// UploadOption 定义文件上传的可选配置函数类型
+type UploadOption func(*uploadConfig)
+
+// WithUploadField 设置文件的表单字段名, 默认为 file
+func WithUploadField(field string) UploadOption {
+	return func(c *uploadConfig) {
+		c.field = field
+	}
+}
+
+// WithUploadHashAlgorithm 设置文件哈希算法, 默认为 utils.SHA256
+func WithUploadHashAlgorithm(alg utils.HashAlgorithm) UploadOption {
+	return func(c *uploadConfig) {
+		c.algorithm = alg
+	}
+}
+
+// WithUploadProgress 设置上传进度回调
+func WithUploadProgress(fn ProgressFunc) UploadOption {
+	return func(c *uploadConfig) {
+		c.onProgress = fn
+	}
+}
+
+// UploadFile 以 multipart/form-data 上传文件, 文件从磁盘流式读取, 不会读入内存.
+// 发送前通过 utils.GenerateHashByFilePath 计算文件哈希, 放在 X-File-Hash 请求头中供服务端校验.
+// 请求体不可重放, 客户端配置的重试对上传请求只会尝试一次.
+//   - ctx: 请求上下文
+//   - rawURL: 上传地址
+//   - filePath: 文件路径
+//   - fields: 额外的表单字段
+//   - opts: 可选配置
+func (c *Client) UploadFile(ctx context.Context, rawURL, filePath string, fields map[string]string, opts ...UploadOption) (*http.Response, error) {
+	cfg := &uploadConfig{field: "file", algorithm: utils.SHA256}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	hash, err := utils.GenerateHashByFilePath(filePath, utils.WithAlgorithm(cfg.algorithm))
+	if err != nil {
+		return nil, fmt.Errorf("hash file: %w", err)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	b := c.NewRequest(ctx, http.MethodPost, rawURL).
+		Header(HeaderFileHash, hash).
+		Header(HeaderFileHashAlgorithm, string(cfg.algorithm))
+
+	for key, value := range fields {
+		b.Form(key, value)
+	}
+
+	var r io.Reader = file
+	if cfg.onProgress != nil {
+		r = &progressReader{ReadCloser: file, total: info.Size(), onProgress: cfg.onProgress}
+	}
+
+	return b.File(cfg.field, filepath.Base(filePath), r).Do()
+}
+
+// progressReader 统计已读取字节数并回调进度
+type progressReader struct {
+	io.ReadCloser
+	sent       int64
+	total      int64
+	onProgress ProgressFunc
+}
+
+// Read 实现 io.Reader 接口
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.ReadCloser.Read(b)
+	if n > 0 {
+		p.sent += int64(n)
+		p.onProgress(p.sent, p.total)
+	}
+
+	return n, err
+}
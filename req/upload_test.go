@@ -0,0 +1,89 @@
+//
+// FilePath    : go-utils\req\upload_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 文件上传单元测试
+//
+
+package req
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jiaopengzi/go-utils"
+)
+
+// TestUploadFile 测试文件上传携带哈希、表单字段和进度回调
+func TestUploadFile(t *testing.T) {
+	content := strings.Repeat("hello upload ", 10000)
+	filePath := filepath.Join(t.TempDir(), "data.txt")
+
+	if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+
+	wantHash, err := utils.GenerateHashByStrContent(content)
+	if err != nil {
+		t.Fatalf("计算哈希失败: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("upload")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		data, _ := io.ReadAll(file)
+		hash, _ := utils.GenerateHashByStrContent(string(data))
+
+		if hash != r.Header.Get(HeaderFileHash) || header.Filename != "data.txt" || r.FormValue("name") != "demo" {
+			http.Error(w, "mismatch", http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	var last, total int64
+
+	resp, err := NewClient().UploadFile(context.Background(), srv.URL, filePath, map[string]string{"name": "demo"},
+		WithUploadField("upload"),
+		WithUploadProgress(func(sent, t int64) { last, total = sent, t }),
+	)
+	if err != nil {
+		t.Fatalf("上传失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("状态码: %d, 响应: %s", resp.StatusCode, body)
+	}
+
+	if resp.Request.Header.Get(HeaderFileHash) != wantHash {
+		t.Errorf("哈希请求头: %s, 期望: %s", resp.Request.Header.Get(HeaderFileHash), wantHash)
+	}
+
+	if last != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("进度: %d/%d, 期望: %d/%d", last, total, len(content), len(content))
+	}
+}
+
+// TestUploadFile_NotExist 测试文件不存在时返回错误
+func TestUploadFile_NotExist(t *testing.T) {
+	_, err := NewClient().UploadFile(context.Background(), "http://127.0.0.1", filepath.Join(t.TempDir(), "missing"), nil)
+	if err == nil {
+		t.Fatal("文件不存在应返回错误")
+	}
+}
@@ -0,0 +1,408 @@
+//
+// FilePath    : go-utils\res\compress.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 响应压缩中间件, 按内容类型和最小长度决定是否压缩, 支持注册 brotli 等压缩算法
+//
+
+package res
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Compressor 压缩算法, 创建写入 w 的压缩 Writer
+//   - w: 压缩数据写入的目标
+//   - level: 压缩级别, 含义由压缩算法决定, -1 表示默认级别
+type Compressor func(w io.Writer, level int) (io.WriteCloser, error)
+
+// compressors 已注册的压缩算法, 键为 Content-Encoding
+var (
+	compressors = map[string]Compressor{
+		"gzip": func(w io.Writer, level int) (io.WriteCloser, error) {
+			return gzip.NewWriterLevel(w, level)
+		},
+		"deflate": func(w io.Writer, level int) (io.WriteCloser, error) {
+			return flate.NewWriter(w, level)
+		},
+	}
+	compressorsMu sync.RWMutex
+)
+
+// RegisterCompressor 注册压缩算法, 已存在时覆盖
+//   - encoding: Content-Encoding 名称, 例如 br
+//   - c: 压缩算法
+//
+// 例如使用 github.com/andybalholm/brotli 注册 br:
+//
+//	res.RegisterCompressor("br", func(w io.Writer, level int) (io.WriteCloser, error) {
+//		return brotli.NewWriterLevel(w, level), nil
+//	})
+func RegisterCompressor(encoding string, c Compressor) {
+	compressorsMu.Lock()
+	defer compressorsMu.Unlock()
+
+	compressors[strings.ToLower(encoding)] = c
+}
+
+// getCompressor 获取已注册的压缩算法
+func getCompressor(encoding string) (Compressor, bool) {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+
+	c, ok := compressors[encoding]
+
+	return c, ok
+}
+
+// compressConfig 响应压缩配置
+type compressConfig struct {
+	level        int      // 压缩级别
+	minSize      int      // 最小压缩长度, 单位字节
+	contentTypes []string // 需要压缩的内容类型, 以 / 结尾表示前缀匹配
+	encodings    []string // 压缩算法优先级
+}
+
+// CompressOption 定义响应压缩的可选配置函数类型
+type CompressOption func(*compressConfig)
+
+// WithCompressLevel 设置压缩级别, 默认 -1 使用压缩算法的默认级别
+func WithCompressLevel(level int) CompressOption {
+	return func(c *compressConfig) {
+		c.level = level
+	}
+}
+
+// WithCompressMinSize 设置最小压缩长度(字节), 响应体小于该长度时不压缩, 默认 1024
+func WithCompressMinSize(size int) CompressOption {
+	return func(c *compressConfig) {
+		c.minSize = size
+	}
+}
+
+// WithCompressContentTypes 设置需要压缩的内容类型, 以 / 结尾表示前缀匹配, 例如 text/
+func WithCompressContentTypes(contentTypes ...string) CompressOption {
+	return func(c *compressConfig) {
+		c.contentTypes = contentTypes
+	}
+}
+
+// WithCompressEncodings 设置压缩算法优先级, 客户端 q 值相同时按该顺序选择, 默认 br, gzip, deflate(未注册的忽略)
+func WithCompressEncodings(encodings ...string) CompressOption {
+	return func(c *compressConfig) {
+		c.encodings = encodings
+	}
+}
+
+// Compress 响应压缩中间件, 根据 Accept-Encoding 协商压缩算法, 只压缩指定内容类型且不小于最小长度的响应体,
+// 并设置 Vary: Accept-Encoding. 已设置 Content-Encoding 的响应和 SSE 等未配置的内容类型不压缩.
+// 压缩在写入连接时进行, MsgResponse 等记录的日志仍为未压缩(已脱敏)的数据,
+// 在中间件内部 c.Writer.Size() 返回未压缩的长度.
+func Compress(opts ...CompressOption) gin.HandlerFunc {
+	cfg := &compressConfig{
+		level:   -1,
+		minSize: 1024,
+		contentTypes: []string{
+			"application/json", "application/xml", "application/javascript",
+			"application/problem+json", "image/svg+xml", "text/",
+		},
+		encodings: []string{"br", "gzip", "deflate"},
+	}
+
+	for _, apply := range opts {
+		apply(cfg)
+	}
+
+	return func(c *gin.Context) {
+		// HEAD 请求和协议升级(websocket)不压缩
+		if c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		w := &compressWriter{
+			ResponseWriter: c.Writer,
+			cfg:            cfg,
+			encoding:       negotiateEncoding(c.GetHeader("Accept-Encoding"), cfg.encodings),
+			status:         http.StatusOK,
+		}
+
+		c.Writer = w
+
+		defer func() {
+			w.close()
+			c.Writer = w.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding 根据 Accept-Encoding 选择 q 值最大的已注册压缩算法, q 值相同时按 preference 顺序
+func negotiateEncoding(acceptEncoding string, preference []string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	// 解析 q 值
+	qualities := make(map[string]float64)
+
+	for part := range strings.SplitSeq(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		q := 1.0
+
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+
+			q = parsed
+		}
+
+		qualities[name] = q
+	}
+
+	best, bestQ := "", 0.0
+
+	for _, enc := range preference {
+		if _, ok := getCompressor(enc); !ok {
+			continue
+		}
+
+		q, ok := qualities[enc]
+		if !ok {
+			q, ok = qualities["*"]
+		}
+
+		if ok && q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+
+	return best
+}
+
+// compressWriter 压缩响应的 gin.ResponseWriter, 在响应体达到最小压缩长度或结束时决定是否压缩
+type compressWriter struct {
+	gin.ResponseWriter
+	cfg      *compressConfig
+	encoding string // 协商的压缩算法, 为空表示客户端不支持压缩
+
+	status  int            // 决定前暂存的状态码
+	buf     []byte         // 决定前暂存的响应体
+	decided bool           // 是否已决定是否压缩并写入响应头
+	comp    io.WriteCloser // 压缩 Writer, 为 nil 表示不压缩
+	size    int            // 未压缩的响应体长度
+}
+
+// WriteHeader 实现 http.ResponseWriter 接口, 决定前只暂存状态码
+func (w *compressWriter) WriteHeader(code int) {
+	if !w.decided {
+		w.status = code
+		return
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// WriteHeaderNow 实现 gin.ResponseWriter 接口, 立即决定是否压缩并写入响应头
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			zap.L().Warn("写入响应失败", zap.Error(err))
+		}
+	}
+}
+
+// Write 实现 http.ResponseWriter 接口
+func (w *compressWriter) Write(p []byte) (int, error) {
+	w.size += len(p)
+
+	if !w.decided {
+		w.buf = append(w.buf, p...)
+		if len(w.buf) < w.cfg.minSize {
+			return len(p), nil
+		}
+
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+
+		return len(p), nil
+	}
+
+	return w.write(p)
+}
+
+// WriteString 实现 gin.ResponseWriter 接口
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Status 实现 gin.ResponseWriter 接口
+func (w *compressWriter) Status() int {
+	if !w.decided {
+		return w.status
+	}
+
+	return w.ResponseWriter.Status()
+}
+
+// Size 实现 gin.ResponseWriter 接口, 返回未压缩的响应体长度
+func (w *compressWriter) Size() int {
+	if w.size == 0 {
+		return w.ResponseWriter.Size()
+	}
+
+	return w.size
+}
+
+// Written 实现 gin.ResponseWriter 接口, 已暂存响应体时也视为已写入
+func (w *compressWriter) Written() bool {
+	return w.decided || len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+// Flush 实现 http.Flusher 接口, 用于流式响应
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return
+		}
+	}
+
+	if f, ok := w.comp.(interface{ Flush() error }); ok {
+		if err := f.Flush(); err != nil {
+			zap.L().Warn("刷新压缩数据失败", zap.String("encoding", w.encoding), zap.Error(err))
+		}
+	}
+
+	w.ResponseWriter.Flush()
+}
+
+// Hijack 实现 http.Hijacker 接口, 接管连接后不再压缩
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.decided = true
+
+	return w.ResponseWriter.Hijack()
+}
+
+// write 写入响应体, 需要压缩时写入压缩 Writer
+func (w *compressWriter) write(p []byte) (int, error) {
+	if w.comp != nil {
+		return w.comp.Write(p)
+	}
+
+	return w.ResponseWriter.Write(p)
+}
+
+// decide 决定是否压缩, 写入响应头和暂存的响应体
+func (w *compressWriter) decide() error {
+	w.decided = true
+
+	if w.shouldCompress() {
+		compressor, _ := getCompressor(w.encoding)
+
+		comp, err := compressor(w.ResponseWriter, w.cfg.level)
+		if err != nil {
+			zap.L().Warn("创建压缩 Writer 失败, 不压缩响应", zap.String("encoding", w.encoding), zap.Error(err))
+		} else {
+			w.comp = comp
+			w.Header().Set("Content-Encoding", w.encoding)
+			w.Header().Del("Content-Length")
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	buf := w.buf
+	w.buf = nil
+
+	_, err := w.write(buf)
+
+	return err
+}
+
+// shouldCompress 判断是否压缩, 内容类型可压缩时设置 Vary: Accept-Encoding
+func (w *compressWriter) shouldCompress() bool {
+	h := w.Header()
+
+	// 已编码或部分内容不压缩
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" {
+		return false
+	}
+
+	// 没有响应体的状态码
+	if w.status < http.StatusOK || w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		return false
+	}
+
+	contentType := h.Get("Content-Type")
+	if contentType == "" && len(w.buf) > 0 {
+		contentType = http.DetectContentType(w.buf)
+		h.Set("Content-Type", contentType)
+	}
+
+	if !w.compressibleType(contentType) {
+		return false
+	}
+
+	// 是否压缩取决于 Accept-Encoding, 需要告知缓存
+	if !strings.Contains(strings.ToLower(strings.Join(h.Values("Vary"), ",")), "accept-encoding") {
+		h.Add("Vary", "Accept-Encoding")
+	}
+
+	return w.encoding != "" && len(w.buf) >= w.cfg.minSize
+}
+
+// compressibleType 判断内容类型是否需要压缩, SSE 需要及时推送, 不压缩
+func (w *compressWriter) compressibleType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "text/event-stream" {
+		return false
+	}
+
+	for _, t := range w.cfg.contentTypes {
+		if mediaType == t || (strings.HasSuffix(t, "/") && strings.HasPrefix(mediaType, t)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// close 结束响应, 决定是否压缩并关闭压缩 Writer
+func (w *compressWriter) close() {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			zap.L().Warn("写入响应失败", zap.Error(err))
+			return
+		}
+	}
+
+	if w.comp != nil {
+		if err := w.comp.Close(); err != nil {
+			zap.L().Warn("关闭压缩 Writer 失败", zap.String("encoding", w.encoding), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,156 @@
+//
+// FilePath    : go-utils\res\compress_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 响应压缩中间件测试
+//
+
+package res
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat("a", 2048)
+
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		acceptEncoding string
+		wantEncoding   string
+		wantVary       bool
+	}{
+		{"JSON 超过最小长度", "application/json", large, "gzip, deflate", "gzip", true},
+		{"q 值优先", "application/json", large, "gzip;q=0.5, deflate", "deflate", true},
+		{"text/ 前缀匹配", "text/html; charset=utf-8", large, "gzip", "gzip", true},
+		{"小于最小长度", "application/json", "small", "gzip", "", true},
+		{"客户端不支持压缩", "application/json", large, "", "", true},
+		{"不可压缩的内容类型", "image/png", large, "gzip", "", false},
+		{"SSE 不压缩", "text/event-stream", large, "gzip", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine(func(c *gin.Context) {
+				c.Data(http.StatusOK, tt.contentType, []byte(tt.body))
+			}, Compress())
+
+			w := serve(engine, "Accept-Encoding", tt.acceptEncoding)
+
+			if got := w.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+
+			if got := w.Header().Get("Vary") == "Accept-Encoding"; got != tt.wantVary {
+				t.Errorf("Vary = %q, want Accept-Encoding: %v", w.Header().Get("Vary"), tt.wantVary)
+			}
+
+			if got := decodeBody(t, w.Header().Get("Content-Encoding"), w.Body); got != tt.body {
+				t.Errorf("body length = %d, want %d", len(got), len(tt.body))
+			}
+		})
+	}
+}
+
+func TestCompress_Options(t *testing.T) {
+	engine := newTestEngine(func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/x-custom", []byte("0123456789"))
+	}, Compress(WithCompressMinSize(10), WithCompressContentTypes("application/x-custom")))
+
+	w := serve(engine, "Accept-Encoding", "gzip")
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+
+	if got := decodeBody(t, "gzip", w.Body); got != "0123456789" {
+		t.Errorf("body = %q", got)
+	}
+
+	// 已设置 Content-Encoding 的响应不再压缩
+	engine = newTestEngine(func(c *gin.Context) {
+		c.Header("Content-Encoding", "identity")
+		c.Data(http.StatusOK, "application/json", []byte(strings.Repeat("a", 2048)))
+	}, Compress())
+
+	if got := serve(engine, "Accept-Encoding", "gzip").Header().Get("Content-Encoding"); got != "identity" {
+		t.Errorf("Content-Encoding = %q, want identity", got)
+	}
+}
+
+// TestCompress_LogUncompressed 压缩后日志仍记录未压缩的响应数据, c.Writer.Size() 返回未压缩的长度
+func TestCompress_LogUncompressed(t *testing.T) {
+	type payload struct {
+		Content string `json:"content"`
+	}
+
+	SetEnableResponseBody(true)
+	t.Cleanup(func() { SetEnableResponseBody(false) })
+
+	logs := observeLogs(t)
+	content := strings.Repeat("compress me ", 200)
+
+	var size int
+
+	engine := newTestEngine(func(c *gin.Context) {
+		MsgResponse(&Response[payload]{Data: payload{Content: content}}, c)
+		size = c.Writer.Size()
+	}, Compress())
+
+	w := serve(engine, "Accept-Encoding", "gzip")
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+
+	body := decodeBody(t, "gzip", w.Body)
+	if !strings.Contains(body, content) {
+		t.Fatalf("decoded body does not contain payload")
+	}
+
+	if size != len(body) {
+		t.Errorf("c.Writer.Size() = %d, want uncompressed %d", size, len(body))
+	}
+
+	entries := logs.FilterMessage("响应信息").All()
+	if len(entries) != 1 {
+		t.Fatalf("response logs = %d, want 1", len(entries))
+	}
+
+	logged, ok := entries[0].ContextMap()["data"].(*payload)
+	if !ok || logged.Content != content {
+		t.Errorf("logged data = %#v, want uncompressed payload", entries[0].ContextMap()["data"])
+	}
+}
+
+// decodeBody 按 Content-Encoding 解压响应体
+func decodeBody(t *testing.T, encoding string, r io.Reader) string {
+	t.Helper()
+
+	switch encoding {
+	case "gzip":
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			t.Fatalf("gzip.NewReader() error = %v", err)
+		}
+
+		r = gr
+	case "deflate":
+		r = flate.NewReader(r)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read body error = %v", err)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,86 @@
+//
+// FilePath    : go-utils\res\context.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : gin 上下文中请求ID、用户ID、文章ID的类型安全读写
+//
+
+package res
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/go-utils"
+)
+
+// SetRequestID 设置请求ID, 同时写入请求上下文的链路追踪ID, 日志和出站请求可以通过 context 获取
+//   - c: gin 上下文
+//   - requestID: 请求ID
+func SetRequestID(c *gin.Context, requestID string) {
+	c.Set(KeyRequestID, requestID)
+
+	if c.Request != nil {
+		c.Request = c.Request.WithContext(utils.ContextWithTraceID(c.Request.Context(), requestID))
+	}
+}
+
+// GetRequestID 获取请求ID, 不存在或为空时返回 utils.ErrRequestIDNotFound
+func GetRequestID(c *gin.Context) (string, error) {
+	requestID := c.GetString(KeyRequestID)
+	if requestID == "" {
+		return "", utils.ErrRequestIDNotFound
+	}
+
+	return requestID, nil
+}
+
+// MustRequestID 获取请求ID, 不存在时 panic, 用于已经使用 RequestID 中间件的路由
+func MustRequestID(c *gin.Context) string {
+	requestID, err := GetRequestID(c)
+	if err != nil {
+		panic(err)
+	}
+
+	return requestID
+}
+
+// SetUserID 设置用户ID
+//   - c: gin 上下文
+//   - userID: 用户ID
+func SetUserID(c *gin.Context, userID uint64) {
+	c.Set(KeyUserID, userID)
+}
+
+// GetUserID 获取用户ID, 不存在时返回 utils.ErrUserIDNotFound
+func GetUserID(c *gin.Context) (uint64, error) {
+	return getUint64(c, KeyUserID, utils.ErrUserIDNotFound)
+}
+
+// SetPostID 设置文章ID
+//   - c: gin 上下文
+//   - postID: 文章ID
+func SetPostID(c *gin.Context, postID uint64) {
+	c.Set(KeyPostID, postID)
+}
+
+// GetPostID 获取文章ID, 不存在时返回 utils.ErrPostIDNotFound
+func GetPostID(c *gin.Context) (uint64, error) {
+	return getUint64(c, KeyPostID, utils.ErrPostIDNotFound)
+}
+
+// getUint64 获取 uint64 类型的值, 不存在时返回 notFound, 类型不匹配时返回包装 notFound 的错误
+func getUint64(c *gin.Context, key string, notFound error) (uint64, error) {
+	value, ok := c.Get(key)
+	if !ok {
+		return 0, notFound
+	}
+
+	id, ok := value.(uint64)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s 的类型为 %T, 期望 uint64", notFound, key, value)
+	}
+
+	return id, nil
+}
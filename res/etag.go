@@ -0,0 +1,94 @@
+//
+// FilePath    : go-utils\res\etag.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 基于 ETag/If-None-Match 的响应缓存, 数据未变化时返回 304
+//
+
+package res
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/go-utils"
+	"go.uber.org/zap"
+)
+
+// MsgResponseCached 与 MsgResponse 相同, 但根据业务状态码和序列化后的 Data 计算 ETag;
+// 请求头 If-None-Match 与 ETag 匹配时返回 304 且不返回响应体, 适合频繁轮询的读接口.
+// 响应中的请求ID每次都不同, 因此使用弱 ETag; 未设置 Cache-Control 时设置为 no-cache, 要求客户端每次重新验证.
+func MsgResponseCached[D any](r *Response[D], c *gin.Context) {
+	// 构建日志字段
+	fields, _, err := CheckRequestID(c)
+	if err != nil {
+		return
+	}
+
+	etag, err := responseETag(r)
+	if err != nil {
+		// 无法计算 ETag 时按普通响应处理
+		zap.L().Warn("计算 ETag 失败", append(fields, zap.Error(err))...)
+		MsgResponse(r, c)
+
+		return
+	}
+
+	c.Header("ETag", etag)
+
+	if c.Writer.Header().Get("Cache-Control") == "" {
+		c.Header("Cache-Control", "no-cache")
+	}
+
+	if !etagMatch(c.GetHeader("If-None-Match"), etag) {
+		MsgResponse(r, c)
+		return
+	}
+
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+
+	fields = append(fields, zap.Any("code", r.Code), zap.String("etag", etag))
+	zap.L().Info("响应信息-未修改", fields...)
+	checkResponseThresholds(c, fields)
+	RecordTraffic(c)
+
+	c.Abort()
+}
+
+// responseETag 根据业务状态码和序列化后的 Data 计算弱 ETag
+func responseETag[D any](r *Response[D]) (string, error) {
+	data, err := json.Marshal(r.Data)
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := utils.GenerateHashByStrContent(strconv.Itoa(int(r.Code)) + "\n" + string(data))
+	if err != nil {
+		return "", err
+	}
+
+	return `W/"` + hash + `"`, nil
+}
+
+// etagMatch 按弱比较判断 If-None-Match 是否与 etag 匹配, 支持 * 和逗号分隔的多个 ETag
+func etagMatch(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	want := strings.TrimPrefix(etag, "W/")
+
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+
+	return false
+}
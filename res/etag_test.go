@@ -0,0 +1,81 @@
+//
+// FilePath    : go-utils\res\etag_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : ETag 响应缓存测试
+//
+
+package res
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMsgResponseCached(t *testing.T) {
+	engine := newTestEngine(func(c *gin.Context) {
+		MsgResponseCached(&Response[[]int]{Data: []int{1, 2, 3}}, c)
+	})
+
+	first := serve(engine)
+	etag := first.Header().Get("ETag")
+
+	if first.Code != http.StatusOK || !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("first response status = %d, ETag = %q", first.Code, etag)
+	}
+
+	if got := first.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("Cache-Control = %q, want no-cache", got)
+	}
+
+	strong := strings.TrimPrefix(etag, "W/")
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{"弱 ETag 匹配", etag, http.StatusNotModified},
+		{"强 ETag 弱比较匹配", strong, http.StatusNotModified},
+		{"通配符", "*", http.StatusNotModified},
+		{"列表中包含", `"other", ` + etag + `, W/"another"`, http.StatusNotModified},
+		{"不匹配", `W/"other"`, http.StatusOK},
+		{"列表中不包含", `"a", "b"`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(engine, "If-None-Match", tt.ifNoneMatch)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if got := w.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %q, want %q", got, etag)
+			}
+
+			if tt.wantStatus == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("304 response has body %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestResponseETag_DataChanged(t *testing.T) {
+	a, errA := responseETag(&Response[[]int]{Data: []int{1}})
+	b, errB := responseETag(&Response[[]int]{Data: []int{2}})
+	c, errC := responseETag(&Response[[]int]{Code: 1, Data: []int{1}})
+
+	if errA != nil || errB != nil || errC != nil {
+		t.Fatalf("responseETag() errors = %v, %v, %v", errA, errB, errC)
+	}
+
+	if a == b || a == c {
+		t.Errorf("ETag did not change with data or code: %q, %q, %q", a, b, c)
+	}
+}
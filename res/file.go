@@ -0,0 +1,119 @@
+//
+// FilePath    : go-utils\res\file.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 文件下载响应, 设置 Content-Disposition, 支持 Range 断点续传, 流式输出
+//
+
+package res
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// MsgFileResponse 以附件形式流式输出 reader 的内容, 并记录下载信息到日志.
+// reader 实现 io.ReadSeeker 时支持 Range 请求断点续传, 否则按 size 设置 Content-Length 顺序输出.
+//   - c: gin 上下文
+//   - reader: 文件内容
+//   - filename: 下载文件名, 支持非 ASCII 字符
+//   - contentType: 内容类型, 为空时根据 filename 扩展名推断
+//   - size: 文件大小, 未知时为 -1, 仅在 reader 不支持 Seek 时使用
+func MsgFileResponse(c *gin.Context, reader io.Reader, filename, contentType string, size int64) {
+	serveFile(c, reader, filename, contentType, size, time.Time{})
+}
+
+// MsgFileFromPath 以附件形式输出 filePath 对应的文件, 支持 Range 请求断点续传, 文件不存在时返回 404.
+//   - c: gin 上下文
+//   - filePath: 文件路径
+//   - filename: 下载文件名, 为空时使用 filePath 的文件名
+func MsgFileFromPath(c *gin.Context, filePath, filename string) {
+	fields, _, err := CheckRequestID(c)
+	if err != nil {
+		return
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		zap.L().Warn("打开下载文件失败", append(fields, zap.String("path", filePath), zap.Error(err))...)
+		c.AbortWithStatus(http.StatusNotFound)
+
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		zap.L().Warn("下载文件不可用", append(fields, zap.String("path", filePath), zap.Error(err))...)
+		c.AbortWithStatus(http.StatusNotFound)
+
+		return
+	}
+
+	if filename == "" {
+		filename = filepath.Base(filePath)
+	}
+
+	serveFile(c, file, filename, "", info.Size(), info.ModTime())
+}
+
+// serveFile 设置下载响应头并输出文件内容, 记录下载日志
+func serveFile(c *gin.Context, reader io.Reader, filename, contentType string, size int64, modTime time.Time) {
+	// 构建日志字段
+	fields, _, err := CheckRequestID(c)
+	if err != nil {
+		return
+	}
+
+	if userID, ok := c.Get(KeyUserID); ok {
+		fields = append(fields, zap.Any("userID", userID))
+	}
+
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(filename))
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+	if rs, ok := reader.(io.ReadSeeker); ok {
+		// ServeContent 处理 Range、If-Range 和 If-Modified-Since, 按需输出 206 或 304
+		http.ServeContent(c.Writer, c.Request, filename, modTime, rs)
+	} else {
+		if size >= 0 {
+			c.Header("Content-Length", strconv.FormatInt(size, 10))
+		}
+
+		c.Status(http.StatusOK)
+
+		if _, err = io.Copy(c.Writer, reader); err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+	}
+
+	fields = append(fields,
+		zap.String("filename", filename),
+		zap.String("contentType", contentType),
+		zap.Int("status", c.Writer.Status()),
+		zap.Int("size", c.Writer.Size()),
+		zap.String("range", c.GetHeader("Range")),
+	)
+	zap.L().Info("响应信息-文件下载", fields...)
+	checkResponseThresholds(c, fields)
+	RecordTraffic(c)
+
+	c.Abort()
+}
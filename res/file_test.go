@@ -0,0 +1,152 @@
+//
+// FilePath    : go-utils\res\file_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 文件下载响应测试
+//
+
+package res
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const fileContent = "0123456789abcdefghij"
+
+func TestMsgFileResponse_Range(t *testing.T) {
+	engine := newTestEngine(func(c *gin.Context) {
+		MsgFileResponse(c, strings.NewReader(fileContent), "report.txt", "", -1)
+	})
+
+	tests := []struct {
+		name             string
+		rangeHeader      string
+		wantStatus       int
+		wantBody         string
+		wantContentRange string
+	}{
+		{"完整内容", "", http.StatusOK, fileContent, ""},
+		{"指定范围", "bytes=2-5", http.StatusPartialContent, "2345", "bytes 2-5/20"},
+		{"后缀范围", "bytes=-3", http.StatusPartialContent, "hij", "bytes 17-19/20"},
+		{"超出范围", "bytes=100-200", http.StatusRequestedRangeNotSatisfiable, "", "bytes */20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header []string
+			if tt.rangeHeader != "" {
+				header = []string{"Range", tt.rangeHeader}
+			}
+
+			w := serve(engine, header...)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if got := w.Header().Get("Content-Range"); got != tt.wantContentRange {
+				t.Errorf("Content-Range = %q, want %q", got, tt.wantContentRange)
+			}
+
+			if tt.wantStatus != http.StatusRequestedRangeNotSatisfiable && w.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestMsgFileResponse_NonSeekable(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       int64
+		wantLength string
+	}{
+		{"已知大小", int64(len(fileContent)), "20"},
+		{"未知大小", -1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine(func(c *gin.Context) {
+				// 包装后不再实现 io.Seeker
+				reader := io.MultiReader(strings.NewReader(fileContent))
+				MsgFileResponse(c, reader, "data.bin", "", tt.size)
+			})
+
+			// 不支持 Seek 时忽略 Range, 完整输出
+			w := serve(engine, "Range", "bytes=0-1")
+
+			if w.Code != http.StatusOK || w.Body.String() != fileContent {
+				t.Fatalf("status = %d, body = %q", w.Code, w.Body.String())
+			}
+
+			if got := w.Header().Get("Content-Length"); got != tt.wantLength {
+				t.Errorf("Content-Length = %q, want %q", got, tt.wantLength)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != "application/octet-stream" {
+				t.Errorf("Content-Type = %q, want application/octet-stream", got)
+			}
+		})
+	}
+}
+
+func TestMsgFileResponse_ContentDisposition(t *testing.T) {
+	for _, filename := range []string{"report.csv", "财务报表 2026.csv", `a"b.txt`} {
+		t.Run(filename, func(t *testing.T) {
+			engine := newTestEngine(func(c *gin.Context) {
+				MsgFileResponse(c, strings.NewReader(fileContent), filename, "", -1)
+			})
+
+			w := serve(engine)
+
+			disposition, params, err := mime.ParseMediaType(w.Header().Get("Content-Disposition"))
+			if err != nil {
+				t.Fatalf("parse Content-Disposition %q error = %v", w.Header().Get("Content-Disposition"), err)
+			}
+
+			if disposition != "attachment" || params["filename"] != filename {
+				t.Errorf("Content-Disposition = %q, want attachment with filename %q", w.Header().Get("Content-Disposition"), filename)
+			}
+		})
+	}
+}
+
+func TestMsgFileFromPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.txt")
+	if err := os.WriteFile(path, []byte(fileContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := newTestEngine(func(c *gin.Context) {
+		MsgFileFromPath(c, c.Query("path"), "")
+	})
+
+	w := serveURL(engine, "/?path="+url.QueryEscape(path), "Range", "bytes=10-")
+	if w.Code != http.StatusPartialContent || w.Body.String() != "abcdefghij" {
+		t.Fatalf("range request status = %d, body = %q", w.Code, w.Body.String())
+	}
+
+	if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", got)
+	}
+
+	if got := w.Header().Get("Last-Modified"); got == "" {
+		t.Errorf("Last-Modified not set")
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	if w = serveURL(engine, "/?path="+url.QueryEscape(missing)); w.Code != http.StatusNotFound {
+		t.Errorf("missing file status = %d, want 404", w.Code)
+	}
+}
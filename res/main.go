@@ -15,6 +15,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jiaopengzi/go-utils"
 	"github.com/jiaopengzi/go-utils/logger"
+	"github.com/jiaopengzi/go-utils/reflectutil"
 	"github.com/jiaopengzi/go-utils/rescode"
 	"go.uber.org/zap"
 )
@@ -25,6 +26,7 @@ const (
 	KeyUserID    = "UserID"    // 用户ID
 	KeyPostID    = "PostID"    // 文章ID
 	KeyUserCert  = "UserCert"  // 用户证书
+	KeyStartTime = "StartTime" // 请求开始时间, 用于计算响应耗时
 )
 
 // enableResponseBody 是否记录响应体到日志
@@ -35,6 +37,11 @@ func SetEnableResponseBody(enable bool) {
 	enableResponseBody = enable
 }
 
+// ResponseBodyEnabled 是否记录响应体到日志, 供请求日志中间件使用
+func ResponseBodyEnabled() bool {
+	return enableResponseBody
+}
+
 // DocResponse 由于 Swagger 不支持泛型, DocResponse 仅用于 Swagger 文档生成.
 type DocResponse struct {
 	RequestID string                 `json:"request_id" example:"request_id"` // 请求ID
@@ -73,10 +80,19 @@ func MsgResponse[D any](r *Response[D], c *gin.Context) {
 		Data:      r.Data,
 	})
 
+	logResponse(fields, r)
+	checkResponseThresholds(c, fields)
+	RecordTraffic(c)
+
+	c.Abort()
+}
+
+// logResponse 记录响应信息到日志, 如果配置了 enableResponseBody 则同时记录脱敏后的 Data
+func logResponse[D any](fields []zap.Field, r *Response[D]) {
 	fields = append(fields, zap.Any("code", r.Code), zap.String("msg", r.Code.Msg()))
 
 	// 如果配置了 enableResponseBody, 并且 Data 不为 nil, 则记录 Data
-	if enableResponseBody && !utils.IsInterfaceNil(r.Data) {
+	if enableResponseBody && !reflectutil.IsNil(r.Data) {
 		// 创建 data 的副本
 		dataCopy, err := utils.DeepCopy(r.Data)
 		if err != nil {
@@ -85,13 +101,11 @@ func MsgResponse[D any](r *Response[D], c *gin.Context) {
 		}
 
 		// 移除敏感字段
-		logger.MaskSensitiveFields(&dataCopy, logger.SensitiveFields)
+		logger.Mask(&dataCopy)
 		fields = append(fields, zap.Any("data", &dataCopy))
 	}
 
 	zap.L().Info("响应信息", fields...)
-
-	c.Abort()
 }
 
 // MsgResPayNotify 通过 r 响应信息, c gin 上下文, 统一返回信息的格式，并记录响应信息到日志.
@@ -150,7 +164,7 @@ func MsgResXMLResponse(xml []byte, c *gin.Context) {
 		}
 
 		// 尝试对数据做掩码处理（对字符串类型无副作用）
-		logger.MaskSensitiveFields(&dataCopy, logger.SensitiveFields)
+		logger.Mask(&dataCopy)
 
 		if s, ok := any(dataCopy).([]byte); ok {
 			fields = append(fields, zap.String("xml", string(s)))
@@ -158,26 +172,29 @@ func MsgResXMLResponse(xml []byte, c *gin.Context) {
 	}
 
 	zap.L().Info("响应信息-XML", fields...)
+	checkResponseThresholds(c, fields)
+	RecordTraffic(c)
 
 	c.Abort()
 }
 
 // CheckRequestID 检查请求ID是否存在, 并返回日志字段.
 func CheckRequestID(c *gin.Context) ([]zap.Field, string, error) {
-	// 构建日志字段
+	// 构建日志字段, 请求上下文中有链路追踪信息时一并记录, 便于跨系统关联
+	requestID, err := GetRequestID(c)
+
 	fields := []zap.Field{
-		zap.String("requestID", c.GetString(KeyRequestID)), // 请求ID
+		zap.String("requestID", requestID), // 请求ID
 	}
-
-	requestID := c.GetString(KeyRequestID)
+	fields = append(fields, logger.TraceFields(c.Request.Context())...)
 
 	// 没有获取到请求ID
-	if requestID == "" {
+	if err != nil {
 		c.Status(http.StatusInternalServerError)
 		c.Abort()
 		zap.L().Error("获取请求ID失败", fields...)
 
-		return nil, "", utils.ErrRequestIDNotFound
+		return nil, "", err
 	}
 
 	return fields, requestID, nil
@@ -212,7 +229,7 @@ func MsgResHTMLResponse(html []byte, c *gin.Context) {
 		}
 
 		// 尝试对数据做掩码处理（对字符串类型无副作用）
-		logger.MaskSensitiveFields(&dataCopy, logger.SensitiveFields)
+		logger.Mask(&dataCopy)
 
 		if s, ok := any(dataCopy).([]byte); ok {
 			fields = append(fields, zap.String("html", string(s)))
@@ -220,6 +237,8 @@ func MsgResHTMLResponse(html []byte, c *gin.Context) {
 	}
 
 	zap.L().Info("响应信息-HTML", fields...)
+	checkResponseThresholds(c, fields)
+	RecordTraffic(c)
 
 	c.Abort()
 }
@@ -0,0 +1,65 @@
+//
+// FilePath    : go-utils\res\main_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 响应测试公共函数
+//
+
+package res
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestEngine 创建设置了请求ID的 gin 引擎, middlewares 在 handler 之前执行
+func newTestEngine(handler gin.HandlerFunc, middlewares ...gin.HandlerFunc) *gin.Engine {
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		SetRequestID(c, "test-request-id")
+		c.Next()
+	})
+	engine.Use(middlewares...)
+	engine.GET("/", handler)
+
+	return engine
+}
+
+// serve 向 / 发送 GET 请求, header 为请求头键值对
+func serve(engine *gin.Engine, header ...string) *httptest.ResponseRecorder {
+	return serveURL(engine, "/", header...)
+}
+
+// serveURL 向 target 发送 GET 请求, header 为请求头键值对
+func serveURL(engine *gin.Engine, target string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	return w
+}
+
+// observeLogs 将全局日志替换为 observer, 测试结束后还原
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	t.Cleanup(zap.ReplaceGlobals(zap.New(core)))
+
+	return logs
+}
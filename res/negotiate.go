@@ -0,0 +1,167 @@
+//
+// FilePath    : go-utils\res\negotiate.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 响应内容协商, 根据 Accept 请求头选择序列化格式
+//
+
+package res
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// 协议格式下通过响应头传递的信封字段
+const (
+	HeaderRequestID = "X-Request-ID"    // 请求ID
+	HeaderCode      = "X-Response-Code" // 业务状态码
+	HeaderMsg       = "X-Response-Msg"  // 状态码对应信息, URL 编码
+)
+
+// Encoder 响应序列化器, 根据响应 v 生成 gin 渲染器
+//   - c: gin 上下文, 可用于设置响应头
+//   - v: 响应信息, 类型为 *Response[D], 可通过 Payload 方法获取 Data
+type Encoder func(c *gin.Context, v any) (render.Render, error)
+
+// 已注册的序列化器
+var (
+	encoders = map[string]Encoder{
+		binding.MIMEJSON:     encodeJSON,
+		binding.MIMEMSGPACK:  encodeMsgPack,
+		binding.MIMEMSGPACK2: encodeMsgPack,
+		binding.MIMEPROTOBUF: encodeProtoBuf,
+	}
+	// offered 按优先级排列的 MIME 类型, 第一个为默认格式
+	offered = []string{
+		binding.MIMEJSON,
+		binding.MIMEMSGPACK,
+		binding.MIMEMSGPACK2,
+		binding.MIMEPROTOBUF,
+	}
+	encodersMu sync.RWMutex // 读写锁 (保证并发安全)
+)
+
+// RegisterEncoder 注册自定义序列化器, 已存在的 MIME 类型会被覆盖
+//   - mime: MIME 类型, 例如 "application/cbor"
+//   - enc: 序列化器
+func RegisterEncoder(mime string, enc Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	if _, ok := encoders[mime]; !ok {
+		offered = append(offered, mime)
+	}
+
+	encoders[mime] = enc
+}
+
+// Payload 获取响应数据, 供自定义 Encoder 使用
+func (r *Response[D]) Payload() any {
+	return r.Data
+}
+
+// MsgResponseNegotiated 与 MsgResponse 相同, 但根据 Accept 请求头选择序列化格式.
+// 默认支持 JSON、msgpack 和 protobuf, 无法匹配时使用 JSON.
+func MsgResponseNegotiated[D any](r *Response[D], c *gin.Context) {
+	// 构建日志字段
+	fields, requestID, err := CheckRequestID(c)
+	if err != nil {
+		return
+	}
+
+	resp := &Response[D]{
+		RequestID: requestID,
+		Code:      r.Code,
+		Msg:       r.Code.Msg(),
+		Data:      r.Data,
+	}
+
+	// 选择序列化器
+	mime, enc := negotiateEncoder(c)
+	fields = append(fields, zap.String("format", mime))
+
+	rd, err := enc(c, resp)
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+		zap.L().Error("响应序列化失败", fields...)
+		c.AbortWithStatus(http.StatusInternalServerError)
+
+		return
+	}
+
+	c.Render(http.StatusOK, rd)
+
+	logResponse(fields, resp)
+	checkResponseThresholds(c, fields)
+	RecordTraffic(c)
+
+	c.Abort()
+}
+
+// negotiateEncoder 根据 Accept 请求头选择序列化器
+func negotiateEncoder(c *gin.Context) (string, Encoder) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	mime := c.NegotiateFormat(offered...)
+	if enc, ok := encoders[mime]; ok {
+		return mime, enc
+	}
+
+	// 无法匹配时使用默认格式
+	return offered[0], encoders[offered[0]]
+}
+
+// encodeJSON JSON 序列化
+func encodeJSON(_ *gin.Context, v any) (render.Render, error) {
+	return render.JSON{Data: v}, nil
+}
+
+// encodeMsgPack msgpack 序列化
+func encodeMsgPack(_ *gin.Context, v any) (render.Render, error) {
+	return render.MsgPack{Data: v}, nil
+}
+
+// encodeProtoBuf protobuf 序列化, Data 必须实现 proto.Message;
+// 由于信封没有对应的 proto 定义, RequestID、Code、Msg 通过响应头传递
+func encodeProtoBuf(c *gin.Context, v any) (render.Render, error) {
+	r, ok := v.(interface {
+		Payload() any
+	})
+	if !ok {
+		return nil, fmt.Errorf("response %T does not support protobuf", v)
+	}
+
+	msg, ok := r.Payload().(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("response data %T does not implement proto.Message", r.Payload())
+	}
+
+	// 通过响应头传递信封字段
+	if resp, ok := v.(interface {
+		envelope() (string, string, string)
+	}); ok {
+		requestID, code, respMsg := resp.envelope()
+		c.Header(HeaderRequestID, requestID)
+		c.Header(HeaderCode, code)
+		c.Header(HeaderMsg, url.QueryEscape(respMsg))
+	}
+
+	return render.ProtoBuf{Data: msg}, nil
+}
+
+// envelope 获取信封字段, 用于通过响应头传递
+func (r *Response[D]) envelope() (requestID, code, msg string) {
+	return r.RequestID, strconv.Itoa(int(r.Code)), r.Msg
+}
@@ -0,0 +1,89 @@
+//
+// FilePath    : go-utils\res\page.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 分页响应, 列表接口统一返回的分页结构
+//
+
+package res
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/go-utils"
+	"github.com/jiaopengzi/go-utils/rescode"
+)
+
+// PageResponse 分页响应数据, 作为 Response 的 Data 返回
+type PageResponse[D any] struct {
+	Items    []D   `json:"items"`                   // 当前页数据, 无数据时为空数组
+	Total    int64 `json:"total" example:"100"`     // 总记录数
+	Page     int64 `json:"page" example:"1"`        // 当前页, 从 1 开始
+	PageSize int64 `json:"page_size" example:"10"`  // 分页大小
+	HasNext  bool  `json:"has_next" example:"true"` // 是否有下一页
+}
+
+// NewPageResponse 创建分页响应数据, 根据总记录数计算是否有下一页
+//   - items: 当前页数据
+//   - total: 总记录数
+//   - page: 当前页, 从 1 开始, 小于 1 时按 1 处理
+//   - pageSize: 分页大小
+func NewPageResponse[D any](items []D, total, page, pageSize int64) *PageResponse[D] {
+	if items == nil {
+		items = []D{}
+	}
+
+	page = max(page, 1)
+
+	return &PageResponse[D]{
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		HasNext:  pageSize > 0 && page*pageSize < total,
+	}
+}
+
+// FromGormPage 根据 gorm 查询使用的 limit/offset 和 Count 查询的总记录数创建分页响应数据
+//   - items: 当前页数据, 即 db.Limit(limit).Offset(offset).Find 的结果
+//   - limit: 分页大小
+//   - offset: 偏移量
+//   - total: 总记录数, 即 db.Count 的结果
+func FromGormPage[D any](items []D, limit, offset int, total int64) *PageResponse[D] {
+	var page int64 = 1
+	if limit > 0 {
+		page = int64(offset/limit) + 1
+	}
+
+	return NewPageResponse(items, total, page, int64(limit))
+}
+
+// FromPage 根据 utils.Page 的分页查询结果创建分页响应数据
+//   - p: 执行过 SelectPages 的分页结构
+func FromPage[D any](p *utils.Page[D]) *PageResponse[D] {
+	if p == nil || p.PageBase == nil {
+		return NewPageResponse[D](nil, 0, 1, 0)
+	}
+
+	return NewPageResponse(p.Records, p.Total, p.CurrentPage, p.PageSize)
+}
+
+// MsgPageResponse 通过 code 和分页数据 p 响应列表信息, 与 MsgResponse 相同, Data 为 PageResponse,
+// Items 为 nil 时返回空数组
+//   - code: 业务状态码
+//   - p: 分页数据
+//   - c: gin 上下文
+func MsgPageResponse[D any](code rescode.StatusCodeType, p *PageResponse[D], c *gin.Context) {
+	if p == nil {
+		p = NewPageResponse[D](nil, 0, 1, 0)
+	}
+
+	if p.Items == nil {
+		// 复制一份, 不修改调用方的数据
+		cp := *p
+		cp.Items = []D{}
+		p = &cp
+	}
+
+	MsgResponse(&Response[*PageResponse[D]]{Code: code, Data: p}, c)
+}
@@ -0,0 +1,134 @@
+//
+// FilePath    : go-utils\res\page_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 分页响应测试
+//
+
+package res
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/go-utils"
+)
+
+func TestNewPageResponse(t *testing.T) {
+	tests := []struct {
+		name              string
+		items             []int
+		total, page, size int64
+		wantPage          int64
+		wantHasNext       bool
+	}{
+		{"有下一页", []int{1, 2}, 5, 1, 2, 1, true},
+		{"倒数第二页", []int{3, 4}, 5, 2, 2, 2, true},
+		{"最后一页不满", []int{5}, 5, 3, 2, 3, false},
+		{"最后一页刚好满", []int{3, 4}, 4, 2, 2, 2, false},
+		{"超出最后一页", nil, 4, 5, 2, 5, false},
+		{"pageSize 为 0", nil, 5, 1, 0, 1, false},
+		{"pageSize 为负数", nil, 5, 1, -1, 1, false},
+		{"page 为 0 按 1 处理", []int{1, 2}, 5, 0, 2, 1, true},
+		{"page 为负数按 1 处理", []int{1, 2}, 2, -3, 2, 1, false},
+		{"无数据", nil, 0, 1, 10, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewPageResponse(tt.items, tt.total, tt.page, tt.size)
+
+			if got.Page != tt.wantPage || got.HasNext != tt.wantHasNext {
+				t.Errorf("Page = %d, HasNext = %v, want %d, %v", got.Page, got.HasNext, tt.wantPage, tt.wantHasNext)
+			}
+
+			if got.Items == nil {
+				t.Error("Items = nil, want []")
+			}
+
+			if got.Total != tt.total || got.PageSize != tt.size {
+				t.Errorf("Total = %d, PageSize = %d, want %d, %d", got.Total, got.PageSize, tt.total, tt.size)
+			}
+		})
+	}
+}
+
+func TestFromGormPage(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit, offset int
+		total         int64
+		wantPage      int64
+		wantHasNext   bool
+	}{
+		{"第一页", 10, 0, 25, 1, true},
+		{"中间页", 10, 10, 25, 2, true},
+		{"最后一页", 10, 20, 25, 3, false},
+		{"偏移量不是 limit 的整数倍", 10, 15, 25, 2, true},
+		{"limit 为 0", 0, 20, 25, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromGormPage[int](nil, tt.limit, tt.offset, tt.total)
+
+			if got.Page != tt.wantPage || got.HasNext != tt.wantHasNext || got.Items == nil {
+				t.Errorf("FromGormPage() = %+v, want page %d, hasNext %v", *got, tt.wantPage, tt.wantHasNext)
+			}
+		})
+	}
+}
+
+func TestFromPage(t *testing.T) {
+	tests := []struct {
+		name        string
+		page        *utils.Page[int]
+		wantTotal   int64
+		wantPage    int64
+		wantHasNext bool
+	}{
+		{"nil", nil, 0, 1, false},
+		{"PageBase 为 nil", &utils.Page[int]{}, 0, 1, false},
+		{"有下一页", &utils.Page[int]{PageBase: &utils.PageBase{Total: 3, CurrentPage: 1, PageSize: 2}, Records: []int{1, 2}}, 3, 1, true},
+		{"最后一页", &utils.Page[int]{PageBase: &utils.PageBase{Total: 3, CurrentPage: 2, PageSize: 2}, Records: []int{3}}, 3, 2, false},
+		{"Records 为 nil", &utils.Page[int]{PageBase: &utils.PageBase{Total: 0, CurrentPage: 1, PageSize: 10}}, 0, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromPage(tt.page)
+
+			if got.Total != tt.wantTotal || got.Page != tt.wantPage || got.HasNext != tt.wantHasNext || got.Items == nil {
+				t.Errorf("FromPage() = %+v, want total %d, page %d, hasNext %v", *got, tt.wantTotal, tt.wantPage, tt.wantHasNext)
+			}
+		})
+	}
+}
+
+// TestMsgPageResponse 无数据时 items 序列化为空数组而不是 null
+func TestMsgPageResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		page *PageResponse[int]
+	}{
+		{"nil 分页数据", nil},
+		{"nil 当前页数据", NewPageResponse[int](nil, 0, 1, 10)},
+		{"直接构造的分页数据", &PageResponse[int]{Page: 1, PageSize: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observeLogs(t)
+
+			engine := newTestEngine(func(c *gin.Context) {
+				MsgPageResponse(testErrorCode, tt.page, c)
+			})
+
+			if body := serve(engine).Body.String(); !strings.Contains(body, `"items":[]`) {
+				t.Errorf("body = %s, want items []", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+//
+// FilePath    : go-utils\res\problem.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 错误响应, 支持原有 Response 结构和 RFC 7807 problem+json 两种格式
+//
+
+package res
+
+import (
+	"cmp"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"github.com/jiaopengzi/go-utils/rescode"
+	"go.uber.org/zap"
+)
+
+// MIMEProblemJSON RFC 7807 错误响应的内容类型
+const MIMEProblemJSON = "application/problem+json"
+
+// KeyProblemDetails gin 上下文中记录错误响应是否使用 problem+json 的 key, 由 UseProblemDetails 设置
+const KeyProblemDetails = "ProblemDetails"
+
+// problemTypeBase 错误类型 URI 前缀, 为空时 type 为 about:blank
+var problemTypeBase string
+
+// SetProblemTypeBase 设置错误类型 URI 前缀, type 为前缀加业务状态码, 例如 https://example.com/errors/ + 20001
+func SetProblemTypeBase(base string) {
+	problemTypeBase = base
+}
+
+// ProblemDetails RFC 7807 错误响应, 保留业务状态码 code 和请求ID
+type ProblemDetails struct {
+	Type      string                 `json:"type" example:"about:blank"`        // 错误类型 URI
+	Title     string                 `json:"title" example:"参数错误"`              // 错误简述, 默认为业务状态码对应信息
+	Status    int                    `json:"status" example:"400"`              // HTTP 状态码
+	Detail    string                 `json:"detail,omitempty" example:"detail"` // 错误详情
+	Instance  string                 `json:"instance" example:"request_id"`     // 出错的请求, 即请求ID
+	Code      rescode.StatusCodeType `json:"code" example:"20001"`              // 业务状态码
+	RequestID string                 `json:"request_id" example:"request_id"`   // 请求ID, 与原有 Response 结构保持一致
+}
+
+// errorConfig 错误响应配置
+type errorConfig struct {
+	status  int     // HTTP 状态码
+	typeURI string  // 错误类型 URI
+	title   string  // 错误简述
+	detail  *string // 错误详情, 为 nil 时不返回
+	problem *bool   // 是否使用 problem+json, 为 nil 时根据路由组设置
+}
+
+// ErrorOption 定义错误响应的可选配置函数类型
+type ErrorOption func(*errorConfig)
+
+// WithErrorStatus 设置 HTTP 状态码, 默认 problem+json 为 400, 原有 Response 结构为 200
+func WithErrorStatus(status int) ErrorOption {
+	return func(c *errorConfig) {
+		c.status = status
+	}
+}
+
+// WithErrorType 设置错误类型 URI, 默认为 SetProblemTypeBase 设置的前缀加业务状态码
+func WithErrorType(typeURI string) ErrorOption {
+	return func(c *errorConfig) {
+		c.typeURI = typeURI
+	}
+}
+
+// WithErrorTitle 设置错误简述, 默认为业务状态码对应信息
+func WithErrorTitle(title string) ErrorOption {
+	return func(c *errorConfig) {
+		c.title = title
+	}
+}
+
+// WithErrorDetail 设置返回给客户端的错误详情, 默认不返回; err 只记录到日志, 避免内部错误暴露给客户端
+func WithErrorDetail(detail string) ErrorOption {
+	return func(c *errorConfig) {
+		c.detail = &detail
+	}
+}
+
+// WithProblemDetails 设置本次响应是否使用 problem+json, 优先于 UseProblemDetails
+func WithProblemDetails(enable bool) ErrorOption {
+	return func(c *errorConfig) {
+		c.problem = &enable
+	}
+}
+
+// UseProblemDetails gin 中间件, 设置路由组内 MsgError 是否使用 problem+json, 未设置时使用原有 Response 结构
+//   - enable: 是否使用 problem+json
+func UseProblemDetails(enable bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(KeyProblemDetails, enable)
+		c.Next()
+	}
+}
+
+// MsgError 通过业务状态码 code 和错误 err 响应错误信息, 并记录错误到日志.
+// 根据 UseProblemDetails 或 WithProblemDetails 选择 RFC 7807 problem+json 或原有 Response 结构;
+// err 只记录到日志, 不返回给客户端, 需要返回错误详情时使用 WithErrorDetail.
+//   - c: gin 上下文
+//   - code: 业务状态码
+//   - err: 错误, 可以为 nil
+//   - opts: 可选配置
+func MsgError(c *gin.Context, code rescode.StatusCodeType, err error, opts ...ErrorOption) {
+	// 构建日志字段
+	fields, requestID, errID := CheckRequestID(c)
+	if errID != nil {
+		return
+	}
+
+	cfg := &errorConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	problem := c.GetBool(KeyProblemDetails)
+	if cfg.problem != nil {
+		problem = *cfg.problem
+	}
+
+	fields = append(fields, zap.Any("code", code), zap.String("msg", code.Msg()), zap.Bool("problem", problem))
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+
+	if problem {
+		p := newProblemDetails(cfg, code, requestID)
+
+		c.Header("Content-Type", MIMEProblemJSON)
+		c.Render(p.Status, render.JSON{Data: p})
+	} else {
+		c.JSON(cmp.Or(cfg.status, http.StatusOK), &Response[any]{
+			RequestID: requestID,
+			Code:      code,
+			Msg:       code.Msg(),
+		})
+	}
+
+	zap.L().Warn("响应信息-错误", fields...)
+	checkResponseThresholds(c, fields)
+	RecordTraffic(c)
+
+	c.Abort()
+}
+
+// newProblemDetails 根据配置创建 RFC 7807 错误响应
+func newProblemDetails(cfg *errorConfig, code rescode.StatusCodeType, requestID string) *ProblemDetails {
+	p := &ProblemDetails{
+		Type:      cfg.typeURI,
+		Title:     cfg.title,
+		Status:    cmp.Or(cfg.status, http.StatusBadRequest),
+		Instance:  requestID,
+		Code:      code,
+		RequestID: requestID,
+	}
+
+	if p.Type == "" {
+		p.Type = "about:blank"
+		if problemTypeBase != "" {
+			p.Type = problemTypeBase + strconv.Itoa(int(code))
+		}
+	}
+
+	if p.Title == "" {
+		p.Title = code.Msg()
+	}
+
+	if cfg.detail != nil {
+		p.Detail = *cfg.detail
+	}
+
+	return p
+}
@@ -0,0 +1,138 @@
+//
+// FilePath    : go-utils\res\problem_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 错误响应测试
+//
+
+package res
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/go-utils/rescode"
+)
+
+// testErrorCode 测试使用的业务状态码
+const testErrorCode rescode.StatusCodeType = 29001
+
+// errInternal 不应返回给客户端的内部错误
+var errInternal = errors.New("dial tcp 10.0.0.1:3306: connection refused")
+
+func init() {
+	rescode.RegisterCodes(map[rescode.StatusCodeType]string{testErrorCode: "参数错误"})
+}
+
+func TestMsgError(t *testing.T) {
+	tests := []struct {
+		name        string
+		middlewares []gin.HandlerFunc
+		opts        []ErrorOption
+		wantProblem bool
+		wantStatus  int
+		wantDetail  string
+	}{
+		{"默认使用 Response", nil, nil, false, http.StatusOK, ""},
+		{"Response 设置状态码", nil, []ErrorOption{WithErrorStatus(http.StatusConflict)}, false, http.StatusConflict, ""},
+		{"路由组使用 problem+json", []gin.HandlerFunc{UseProblemDetails(true)}, nil, true, http.StatusBadRequest, ""},
+		{"problem+json 设置状态码", []gin.HandlerFunc{UseProblemDetails(true)}, []ErrorOption{WithErrorStatus(http.StatusNotFound)}, true, http.StatusNotFound, ""},
+		{"problem+json 设置错误详情", []gin.HandlerFunc{UseProblemDetails(true)}, []ErrorOption{WithErrorDetail("库存不足")}, true, http.StatusBadRequest, "库存不足"},
+		{"WithProblemDetails 优先于路由组关闭", []gin.HandlerFunc{UseProblemDetails(false)}, []ErrorOption{WithProblemDetails(true)}, true, http.StatusBadRequest, ""},
+		{"WithProblemDetails 优先于路由组开启", []gin.HandlerFunc{UseProblemDetails(true)}, []ErrorOption{WithProblemDetails(false)}, false, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observeLogs(t)
+
+			engine := newTestEngine(func(c *gin.Context) {
+				MsgError(c, testErrorCode, errInternal, tt.opts...)
+			}, tt.middlewares...)
+
+			w := serve(engine)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			// 内部错误只记录到日志, 不返回给客户端
+			if strings.Contains(w.Body.String(), errInternal.Error()) {
+				t.Errorf("body = %s, exposes internal error", w.Body.String())
+			}
+
+			contentType := w.Header().Get("Content-Type")
+
+			if !tt.wantProblem {
+				var got Response[any]
+				if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+					t.Fatal(err)
+				}
+
+				if !strings.HasPrefix(contentType, gin.MIMEJSON) || got.Code != testErrorCode || got.Msg != "参数错误" || got.RequestID != "test-request-id" {
+					t.Errorf("Content-Type = %q, body = %+v", contentType, got)
+				}
+
+				return
+			}
+
+			var got ProblemDetails
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+
+			want := ProblemDetails{
+				Type:      "about:blank",
+				Title:     "参数错误",
+				Status:    tt.wantStatus,
+				Detail:    tt.wantDetail,
+				Instance:  "test-request-id",
+				Code:      testErrorCode,
+				RequestID: "test-request-id",
+			}
+
+			if contentType != MIMEProblemJSON || got != want {
+				t.Errorf("Content-Type = %q, body = %+v, want %+v", contentType, got, want)
+			}
+		})
+	}
+}
+
+func TestMsgError_ProblemType(t *testing.T) {
+	observeLogs(t)
+
+	SetProblemTypeBase("https://example.com/errors/")
+	t.Cleanup(func() { SetProblemTypeBase("") })
+
+	tests := []struct {
+		name      string
+		opts      []ErrorOption
+		wantType  string
+		wantTitle string
+	}{
+		{"前缀加业务状态码", nil, "https://example.com/errors/29001", "参数错误"},
+		{"自定义类型和简述", []ErrorOption{WithErrorType("https://example.com/stock"), WithErrorTitle("库存不足")}, "https://example.com/stock", "库存不足"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newTestEngine(func(c *gin.Context) {
+				MsgError(c, testErrorCode, nil, tt.opts...)
+			}, UseProblemDetails(true))
+
+			var got ProblemDetails
+			if err := json.Unmarshal(serve(engine).Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Type != tt.wantType || got.Title != tt.wantTitle {
+				t.Errorf("type = %q, title = %q, want %q, %q", got.Type, got.Title, tt.wantType, tt.wantTitle)
+			}
+		})
+	}
+}
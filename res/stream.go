@@ -0,0 +1,137 @@
+//
+// FilePath    : go-utils\res\stream.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 流式响应(SSE)
+//
+
+package res
+
+import (
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jiaopengzi/go-utils"
+	"github.com/jiaopengzi/go-utils/logger"
+	"github.com/jiaopengzi/go-utils/reflectutil"
+	"github.com/jiaopengzi/go-utils/rescode"
+	"go.uber.org/zap"
+)
+
+// StreamEventMessage SSE 默认事件名称
+const StreamEventMessage = "message"
+
+// StreamEvent 流式响应的单个事件
+type StreamEvent[D any] struct {
+	Event string                 // 事件名称, 为空时使用 StreamEventMessage
+	Code  rescode.StatusCodeType // 业务状态码
+	Data  D                      // 事件数据
+}
+
+// StreamResponse 通过 SSE 将 events 中的事件逐条推送给客户端, 每条事件的数据格式与 MsgResponse 一致.
+// events 关闭或客户端断开连接时结束推送, 返回已推送的事件数量.
+//   - c: gin 上下文
+//   - events: 事件通道, 由调用方写入并在完成后关闭
+func StreamResponse[D any](c *gin.Context, events <-chan StreamEvent[D]) int {
+	// 构建日志字段
+	fields, requestID, err := CheckRequestID(c)
+	if err != nil {
+		return 0
+	}
+
+	// 设置 SSE 相关的响应头, X-Accel-Buffering 用于关闭 nginx 的缓冲
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	zap.L().Info("流式响应开始", fields...)
+
+	var (
+		count    int                    // 已推送的事件数量
+		lastCode rescode.StatusCodeType // 最后一条事件的状态码
+	)
+
+	ctx := c.Request.Context()
+
+	// c.Stream 在客户端断开连接时返回 true
+	clientGone := c.Stream(func(_ io.Writer) bool {
+		var (
+			event StreamEvent[D]
+			ok    bool
+		)
+
+		// 等待事件时同时监听客户端断开连接
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok = <-events:
+			if !ok {
+				return false
+			}
+		}
+
+		name := event.Event
+		if name == "" {
+			name = StreamEventMessage
+		}
+
+		c.SSEvent(name, &Response[D]{
+			RequestID: requestID,
+			Code:      event.Code,
+			Msg:       event.Code.Msg(),
+			Data:      event.Data,
+		})
+
+		count++
+		lastCode = event.Code
+
+		logStreamEvent(fields, name, &event)
+
+		return true
+	})
+
+	// 等待事件期间客户端断开连接
+	clientGone = clientGone || ctx.Err() != nil
+
+	fields = append(fields,
+		zap.Int("count", count),
+		zap.Any("code", lastCode),
+		zap.String("msg", lastCode.Msg()),
+		zap.Bool("clientGone", clientGone),
+	)
+
+	// 客户端提前断开连接时记录警告
+	if clientGone {
+		zap.L().Warn("流式响应中断-客户端断开连接", fields...)
+	} else {
+		zap.L().Info("流式响应结束", fields...)
+	}
+
+	c.Abort()
+
+	return count
+}
+
+// logStreamEvent 记录单条流式事件的日志, 如果配置了 enableResponseBody 则记录脱敏后的数据
+func logStreamEvent[D any](fields []zap.Field, name string, event *StreamEvent[D]) {
+	// 使用新切片, 避免修改调用方的 fields
+	eventFields := make([]zap.Field, 0, len(fields)+3)
+	eventFields = append(eventFields, fields...)
+	eventFields = append(eventFields, zap.String("event", name), zap.Any("code", event.Code))
+
+	if enableResponseBody && !reflectutil.IsNil(event.Data) {
+		// 创建 data 的副本
+		dataCopy, err := utils.DeepCopy(event.Data)
+		if err != nil {
+			zap.L().Error("dataCopy, err := utils.DeepCopy(event.Data) failed")
+			return
+		}
+
+		// 移除敏感字段
+		logger.Mask(&dataCopy)
+		eventFields = append(eventFields, zap.Any("data", &dataCopy))
+	}
+
+	zap.L().Debug("流式响应事件", eventFields...)
+}
@@ -0,0 +1,77 @@
+//
+// FilePath    : go-utils\res\threshold.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 慢响应和大响应体告警
+//
+
+package res
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+var (
+	slowResponseThreshold  time.Duration // 慢响应阈值, 为 0 时不检查
+	largeResponseThreshold int           // 大响应体阈值, 单位字节, 为 0 时不检查
+)
+
+// SetSlowResponseThreshold 设置慢响应阈值, 响应耗时超过阈值时记录 WARN 日志, 为 0 时不检查.
+// 耗时从 gin 上下文中的 KeyStartTime 开始计算, 未设置时不检查.
+func SetSlowResponseThreshold(d time.Duration) {
+	slowResponseThreshold = d
+}
+
+// SetLargeResponseThreshold 设置大响应体阈值(字节), 响应体超过阈值时记录 WARN 日志, 为 0 时不检查.
+func SetLargeResponseThreshold(size int) {
+	largeResponseThreshold = size
+}
+
+// checkResponseThresholds 检查响应耗时和响应体大小是否超过阈值, 超过时记录 WARN 日志
+//   - c: gin 上下文, 需要在写入响应之后调用
+//   - fields: 日志字段
+func checkResponseThresholds(c *gin.Context, fields []zap.Field) {
+	if slowResponseThreshold <= 0 && largeResponseThreshold <= 0 {
+		return
+	}
+
+	var (
+		cost time.Duration
+		size = c.Writer.Size()
+		slow bool
+	)
+
+	// 计算耗时
+	if start := c.GetTime(KeyStartTime); !start.IsZero() {
+		cost = time.Since(start)
+		slow = slowResponseThreshold > 0 && cost > slowResponseThreshold
+	}
+
+	large := largeResponseThreshold > 0 && size > largeResponseThreshold
+
+	if !slow && !large {
+		return
+	}
+
+	// 使用新切片, 避免修改调用方的 fields
+	warnFields := make([]zap.Field, 0, len(fields)+6)
+	warnFields = append(warnFields, fields...)
+	warnFields = append(warnFields,
+		zap.String("method", c.Request.Method),
+		zap.String("path", c.FullPath()),
+		zap.Duration("cost", cost),
+		zap.Int("size", size),
+	)
+
+	if slow {
+		zap.L().Warn("响应耗时过长", append(warnFields, zap.Duration("threshold", slowResponseThreshold))...)
+	}
+
+	if large {
+		zap.L().Warn("响应体过大", append(warnFields, zap.Int("threshold", largeResponseThreshold))...)
+	}
+}
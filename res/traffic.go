@@ -0,0 +1,230 @@
+//
+// FilePath    : go-utils\res\traffic.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 按路由统计请求体和响应体的字节数, 以 prometheus 文本格式导出, 用于容量规划和出口流量成本分析
+//
+
+package res
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 流量统计在 gin 上下文中的 key
+const (
+	keyTrafficRecorded = "TrafficRecorded" // 当前请求已统计, 避免响应层和访问日志中间件重复统计
+	keyRequestCounter  = "RequestCounter"  // 请求体计数器
+)
+
+// unmatchedRoute 未匹配到路由的请求统一使用的路由标签, 避免路由标签的数量无限增长
+const unmatchedRoute = "unmatched"
+
+// enableTrafficAccounting 是否统计请求体和响应体的字节数
+var enableTrafficAccounting atomic.Bool
+
+// SetTrafficAccounting 设置是否按路由统计请求体和响应体的字节数, 默认不统计
+func SetTrafficAccounting(enable bool) {
+	enableTrafficAccounting.Store(enable)
+}
+
+// RouteTraffic 单个路由的流量统计
+type RouteTraffic struct {
+	Method        string // 请求方法
+	Route         string // 路由模板, 例如 /api/v1/user/:id, 未匹配到路由时为 unmatched
+	Requests      uint64 // 请求数
+	RequestBytes  uint64 // 请求体字节数
+	ResponseBytes uint64 // 响应体字节数
+}
+
+// routeCounter 单个路由的计数器
+type routeCounter struct {
+	requests      atomic.Uint64
+	requestBytes  atomic.Uint64
+	responseBytes atomic.Uint64
+}
+
+// routeKey 路由计数器的键
+type routeKey struct {
+	method string
+	route  string
+}
+
+// trafficCounters 路由计数器, 键为 routeKey, 值为 *routeCounter
+var trafficCounters sync.Map
+
+// RequestCounter 统计请求体实际读取的字节数, 用于没有 Content-Length 的分块请求
+type RequestCounter struct {
+	io.ReadCloser
+
+	n atomic.Int64
+}
+
+// Read 实现 io.Reader 接口, 累加读取的字节数
+func (r *RequestCounter) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n.Add(int64(n))
+
+	return n, err
+}
+
+// Count 已读取的字节数
+func (r *RequestCounter) Count() int64 {
+	return r.n.Load()
+}
+
+// CountRequestBody 使用 RequestCounter 包装请求体, 需要在读取请求体之前调用, 访问日志中间件开启统计时会自动调用
+//   - c: gin 上下文
+func CountRequestBody(c *gin.Context) {
+	if !enableTrafficAccounting.Load() || c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return
+	}
+
+	counter := &RequestCounter{ReadCloser: c.Request.Body}
+	c.Request.Body = counter
+	c.Set(keyRequestCounter, counter)
+}
+
+// RequestSize 获取请求体的字节数: Content-Length 与实际读取字节数中较大的一个
+//   - c: gin 上下文
+func RequestSize(c *gin.Context) int64 {
+	size := max(c.Request.ContentLength, 0)
+
+	if v, ok := c.Get(keyRequestCounter); ok {
+		if counter, ok := v.(*RequestCounter); ok {
+			size = max(size, counter.Count())
+		}
+	}
+
+	return size
+}
+
+// ResponseSize 获取已写入的响应体字节数, 未写入时为 0
+//   - c: gin 上下文
+func ResponseSize(c *gin.Context) int64 {
+	return int64(max(c.Writer.Size(), 0))
+}
+
+// RecordTraffic 统计当前请求的请求体和响应体字节数, 同一请求只统计一次, 需要在写入响应之后调用.
+// 响应层的 MsgResponse 等函数和访问日志中间件已自动调用, 未开启统计时不做任何处理.
+//   - c: gin 上下文
+func RecordTraffic(c *gin.Context) {
+	if !enableTrafficAccounting.Load() || c.GetBool(keyTrafficRecorded) {
+		return
+	}
+
+	c.Set(keyTrafficRecorded, true)
+
+	route := c.FullPath()
+	if route == "" {
+		route = unmatchedRoute
+	}
+
+	key := routeKey{method: c.Request.Method, route: route}
+
+	v, ok := trafficCounters.Load(key)
+	if !ok {
+		v, _ = trafficCounters.LoadOrStore(key, &routeCounter{})
+	}
+
+	counter := v.(*routeCounter) //nolint:forcetypeassert // 只存储 *routeCounter
+	counter.requests.Add(1)
+	counter.requestBytes.Add(uint64(RequestSize(c)))
+	counter.responseBytes.Add(uint64(ResponseSize(c)))
+}
+
+// TrafficSnapshot 获取所有路由的流量统计, 按路由和请求方法排序; 使用 prometheus client 时可据此实现 Collector
+func TrafficSnapshot() []RouteTraffic {
+	var result []RouteTraffic
+
+	trafficCounters.Range(func(k, v any) bool {
+		key := k.(routeKey)          //nolint:forcetypeassert // 只存储 routeKey
+		counter := v.(*routeCounter) //nolint:forcetypeassert // 只存储 *routeCounter
+
+		result = append(result, RouteTraffic{
+			Method:        key.method,
+			Route:         key.route,
+			Requests:      counter.requests.Load(),
+			RequestBytes:  counter.requestBytes.Load(),
+			ResponseBytes: counter.responseBytes.Load(),
+		})
+
+		return true
+	})
+
+	slices.SortFunc(result, func(a, b RouteTraffic) int {
+		if c := strings.Compare(a.Route, b.Route); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Method, b.Method)
+	})
+
+	return result
+}
+
+// ResetTraffic 清空流量统计
+func ResetTraffic() {
+	trafficCounters.Clear()
+}
+
+// trafficMetrics 导出的指标
+var trafficMetrics = []struct {
+	name  string
+	help  string
+	value func(t *RouteTraffic) uint64
+}{
+	{"http_requests_total", "Total number of HTTP requests by route.", func(t *RouteTraffic) uint64 { return t.Requests }},
+	{"http_request_body_bytes_total", "Total bytes of HTTP request bodies by route.", func(t *RouteTraffic) uint64 { return t.RequestBytes }},
+	{"http_response_body_bytes_total", "Total bytes of HTTP response bodies by route.", func(t *RouteTraffic) uint64 { return t.ResponseBytes }},
+}
+
+// WriteTrafficMetrics 以 prometheus 文本格式(text/plain; version=0.0.4)写出流量统计
+//   - w: 写入目标
+func WriteTrafficMetrics(w io.Writer) error {
+	snapshot := TrafficSnapshot()
+
+	var b strings.Builder
+
+	for _, m := range trafficMetrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", m.name, m.help, m.name)
+
+		for i := range snapshot {
+			fmt.Fprintf(&b, "%s{method=\"%s\",route=\"%s\"} %d\n",
+				m.name, escapeLabelValue(snapshot[i].Method), escapeLabelValue(snapshot[i].Route), m.value(&snapshot[i]))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// TrafficMetricsHandler prometheus 抓取流量统计的处理函数, 例如 engine.GET("/metrics/traffic", res.TrafficMetricsHandler())
+func TrafficMetricsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		c.Status(http.StatusOK)
+
+		if err := WriteTrafficMetrics(c.Writer); err != nil {
+			_ = c.Error(err)
+		}
+	}
+}
+
+// labelValueEscaper prometheus 标签值转义
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabelValue 转义 prometheus 标签值
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
@@ -0,0 +1,128 @@
+//
+// FilePath    : go-utils\rescode\doc.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 状态码文档生成, 输出 Markdown 和 OpenAPI 枚举 schema
+//
+
+package rescode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jiaopengzi/go-utils"
+)
+
+// otherGroupTitle 未通过 RegisterDocCodes 分组的状态码标题
+const otherGroupTitle = "其他"
+
+// Doc 状态码文档
+type Doc struct {
+	Markdown string         // Markdown 文档
+	OpenAPI  map[string]any // OpenAPI 兼容的枚举 schema, 可直接 json.Marshal 后放入 components.schemas
+}
+
+// GenerateDoc 根据已注册的状态码生成文档, 分组按起始状态码升序, 组内按状态码升序
+//   - title: Markdown 一级标题, 为空时不输出标题
+func GenerateDoc(title string) *Doc {
+	return &Doc{
+		Markdown: GenerateMarkdown(title),
+		OpenAPI:  GenerateOpenAPISchema(),
+	}
+}
+
+// GenerateMarkdown 根据 StatusCodeMsgMapDoc 生成 Markdown 文档,
+// 已通过 RegisterCodes 注册但不在任何分组中的状态码放在最后的 "其他" 分组中
+//   - title: Markdown 一级标题, 为空时不输出标题
+func GenerateMarkdown(title string) string {
+	var b strings.Builder
+
+	if title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", title)
+	}
+
+	for _, group := range sortedDocGroups() {
+		fmt.Fprintf(&b, "## %s\n\n", escapeMarkdown(group.Title))
+		b.WriteString("| 状态码 | 说明 |\n")
+		b.WriteString("| --- | --- |\n")
+
+		for _, code := range sortedCodes(group.Map) {
+			fmt.Fprintf(&b, "| %d | %s |\n", code, escapeMarkdown(group.Map[code]))
+		}
+
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// GenerateOpenAPISchema 根据 StatusCodeMsgMap 生成 OpenAPI 兼容的整数枚举 schema,
+// 说明信息放在 x-enum-descriptions 中, 与 enum 一一对应
+func GenerateOpenAPISchema() map[string]any {
+	codes := sortedCodes(StatusCodeMsgMap)
+
+	enum := make([]int, 0, len(codes))
+	descriptions := make([]string, 0, len(codes))
+
+	for _, code := range codes {
+		enum = append(enum, int(code))
+		descriptions = append(descriptions, StatusCodeMsgMap[code])
+	}
+
+	return map[string]any{
+		"type":                "integer",
+		"description":         "业务状态码",
+		"enum":                enum,
+		"x-enum-descriptions": descriptions,
+	}
+}
+
+// sortedDocGroups 获取按起始状态码升序排列的文档分组, 并追加未分组的状态码
+func sortedDocGroups() []CodeMsgMapDoc {
+	starts := utils.Keys(StatusCodeMsgMapDoc)
+	SortStatusCodeTypeSlice(starts, true)
+
+	groups := make([]CodeMsgMapDoc, 0, len(starts)+1)
+	grouped := make(map[StatusCodeType]struct{}) // 已分组的状态码
+
+	for _, start := range starts {
+		group := StatusCodeMsgMapDoc[start]
+		groups = append(groups, group)
+
+		for code := range group.Map {
+			grouped[code] = struct{}{}
+		}
+	}
+
+	// 未分组的状态码
+	others := make(CodeMsgMap)
+
+	for code, msg := range StatusCodeMsgMap {
+		if _, ok := grouped[code]; !ok {
+			others[code] = msg
+		}
+	}
+
+	if len(others) > 0 {
+		groups = append(groups, CodeMsgMapDoc{Title: otherGroupTitle, Map: others})
+	}
+
+	return groups
+}
+
+// sortedCodes 获取 m 中按升序排列的状态码
+func sortedCodes(m CodeMsgMap) []StatusCodeType {
+	codes := utils.Keys(m)
+	SortStatusCodeTypeSlice(codes, true)
+
+	return codes
+}
+
+// escapeMarkdown 转义 Markdown 表格中的特殊字符
+func escapeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+
+	return strings.ReplaceAll(s, "\n", " ")
+}
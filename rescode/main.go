@@ -9,7 +9,11 @@
 // Package rescode 响应状态码
 package rescode
 
-import "maps"
+import (
+	"maps"
+
+	"github.com/jiaopengzi/go-utils"
+)
 
 // StatusCodeType 状态码类型
 type StatusCodeType int
@@ -56,31 +60,5 @@ func RegisterDocCodes(start StatusCodeType, title string, codeMap map[StatusCode
 
 // SortStatusCodeTypeSlice 对 StatusCodeType 切片进行排序, isAsc 为 true 则升序排序, 否则降序排序
 func SortStatusCodeTypeSlice(codes []StatusCodeType, isAsc bool) {
-	if isAsc {
-		sortAsc(codes)
-	} else {
-		sortDesc(codes)
-	}
-}
-
-// sortAsc 对 StatusCodeType 切片进行升序冒泡排序
-func sortAsc(codes []StatusCodeType) {
-	for i := 0; i < len(codes)-1; i++ {
-		for j := 0; j < len(codes)-i-1; j++ {
-			if codes[j] > codes[j+1] {
-				codes[j], codes[j+1] = codes[j+1], codes[j]
-			}
-		}
-	}
-}
-
-// sortDesc 对 StatusCodeType 切片进行降序冒泡排序
-func sortDesc(codes []StatusCodeType) {
-	for i := 0; i < len(codes)-1; i++ {
-		for j := 0; j < len(codes)-i-1; j++ {
-			if codes[j] < codes[j+1] {
-				codes[j], codes[j+1] = codes[j+1], codes[j]
-			}
-		}
-	}
+	utils.SortSlice(codes, isAsc)
 }
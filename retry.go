@@ -0,0 +1,234 @@
+//
+// FilePath    : go-utils\retry.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 通用重试工具, 支持固定和指数退避、随机抖动、按错误类型判断是否重试
+//
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// 重试默认值
+const (
+	defaultRetryMaxAttempts  = 3                      // 默认最多尝试次数
+	defaultRetryInitialDelay = 100 * time.Millisecond // 默认首次重试等待时间
+	defaultRetryMaxDelay     = 10 * time.Second       // 默认最大重试等待时间
+	defaultRetryMultiplier   = 2.0                    // 默认指数退避倍数
+)
+
+// PermanentError 不可重试的错误, 通过 Permanent 创建, Retry 遇到时立即返回内部错误
+type PermanentError struct {
+	Err error
+}
+
+// Error 实现 error 接口
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap 返回内部错误
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent 将 err 标记为不可重试, err 为 nil 时返回 nil
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &PermanentError{Err: err}
+}
+
+// retryConfig 重试配置
+type retryConfig struct {
+	maxAttempts int                                              // 最多尝试次数, 包括第一次
+	backoff     func(retry int) time.Duration                    // 第 retry 次重试(从 1 开始)前的等待时间
+	jitter      float64                                          // 随机抖动比例
+	retryIf     func(err error) bool                             // 判断错误是否需要重试
+	onRetry     func(attempt int, err error, wait time.Duration) // 重试前的回调
+}
+
+// RetryOption 定义重试的可选配置函数类型
+type RetryOption func(*retryConfig)
+
+// WithMaxAttempts 设置最多尝试次数(包括第一次), 默认 3 次, 小于 1 时按 1 处理
+func WithMaxAttempts(n int) RetryOption {
+	return func(c *retryConfig) {
+		c.maxAttempts = max(n, 1)
+	}
+}
+
+// WithConstantBackoff 设置每次重试前等待固定时间, 为 0 时立即重试
+func WithConstantBackoff(delay time.Duration) RetryOption {
+	return func(c *retryConfig) {
+		c.backoff = func(int) time.Duration { return delay }
+	}
+}
+
+// WithExponentialBackoff 设置指数退避, 第 n 次重试前等待 initial * 2^(n-1), 不超过 maxDelay;
+// 默认 initial 为 100ms, maxDelay 为 10s
+//   - initial: 首次重试等待时间
+//   - maxDelay: 最大等待时间, 为 0 时不限制
+func WithExponentialBackoff(initial, maxDelay time.Duration) RetryOption {
+	return WithBackoff(ExponentialBackoff(initial, maxDelay, defaultRetryMultiplier))
+}
+
+// WithBackoff 设置自定义退避函数, 例如使用 ExponentialBackoff 指定倍数
+//   - fn: 返回第 retry 次重试(从 1 开始)前的等待时间
+func WithBackoff(fn func(retry int) time.Duration) RetryOption {
+	return func(c *retryConfig) {
+		c.backoff = fn
+	}
+}
+
+// WithJitter 设置随机抖动, 实际等待时间在 [d*(1-fraction), d*(1+fraction)] 之间均匀分布, 避免多个客户端同时重试
+//   - fraction: 抖动比例, 取值范围 [0, 1]
+func WithJitter(fraction float64) RetryOption {
+	return func(c *retryConfig) {
+		c.jitter = min(max(fraction, 0), 1)
+	}
+}
+
+// RetryIf 设置判断错误是否需要重试的函数, 默认除 Permanent 错误和上下文错误外都重试;
+// Permanent 错误和上下文取消始终不重试
+func RetryIf(fn func(err error) bool) RetryOption {
+	return func(c *retryConfig) {
+		c.retryIf = fn
+	}
+}
+
+// WithOnRetry 设置每次重试前的回调, 可用于记录日志
+//   - fn: attempt 为刚失败的尝试次数(从 1 开始), err 为该次错误, wait 为重试前的等待时间
+func WithOnRetry(fn func(attempt int, err error, wait time.Duration)) RetryOption {
+	return func(c *retryConfig) {
+		c.onRetry = fn
+	}
+}
+
+// ExponentialBackoff 创建指数退避函数, 第 n 次重试前等待 initial * multiplier^(n-1), 不超过 maxDelay
+//   - initial: 首次重试等待时间
+//   - maxDelay: 最大等待时间, 为 0 时不限制
+//   - multiplier: 每次重试等待时间的倍数, 小于 1 时使用 2
+func ExponentialBackoff(initial, maxDelay time.Duration, multiplier float64) func(retry int) time.Duration {
+	if multiplier < 1 {
+		multiplier = defaultRetryMultiplier
+	}
+
+	return func(retry int) time.Duration {
+		d := initial
+
+		for i := 1; i < retry; i++ {
+			d = time.Duration(float64(d) * multiplier)
+
+			if maxDelay > 0 && d >= maxDelay {
+				return maxDelay
+			}
+		}
+
+		if maxDelay > 0 && d > maxDelay {
+			return maxDelay
+		}
+
+		return d
+	}
+}
+
+// wait 获取第 retry 次重试前的等待时间, 加入随机抖动
+func (c *retryConfig) wait(retry int) time.Duration {
+	d := c.backoff(retry)
+	if d <= 0 || c.jitter == 0 {
+		return d
+	}
+
+	// 在 [1-jitter, 1+jitter) 范围内随机缩放
+	return time.Duration(float64(d) * (1 - c.jitter + 2*c.jitter*rand.Float64()))
+}
+
+// shouldRetry 判断错误是否需要重试
+func (c *retryConfig) shouldRetry(ctx context.Context, err error) bool {
+	var permanent *PermanentError
+	if errors.As(err, &permanent) || ctx.Err() != nil {
+		return false
+	}
+
+	if c.retryIf != nil {
+		return c.retryIf(err)
+	}
+
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// Retry 执行 fn, 失败时按配置等待后重试, 直到成功、错误不可重试、达到最多尝试次数或 ctx 取消.
+//   - 错误不可重试时返回该错误, Permanent 错误返回其内部错误
+//   - 达到最多尝试次数时返回包装最后一次错误的错误
+//   - 等待期间 ctx 取消时返回 ctx 的错误和最后一次错误
+//   - ctx: 上下文
+//   - fn: 执行函数, 可能被多次调用
+//   - opts: 可选配置, 默认最多 3 次, 指数退避 100ms 起, 最大 10s
+func Retry(ctx context.Context, fn func(ctx context.Context) error, opts ...RetryOption) error {
+	_, err := RetryValue(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, opts...)
+
+	return err
+}
+
+// RetryValue 与 Retry 相同, fn 成功时返回其结果
+func RetryValue[T any](ctx context.Context, fn func(ctx context.Context) (T, error), opts ...RetryOption) (T, error) {
+	cfg := &retryConfig{
+		maxAttempts: defaultRetryMaxAttempts,
+		backoff:     ExponentialBackoff(defaultRetryInitialDelay, defaultRetryMaxDelay, defaultRetryMultiplier),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var zero T
+
+	for attempt := 1; ; attempt++ {
+		v, err := fn(ctx)
+		if err == nil {
+			return v, nil
+		}
+
+		if !cfg.shouldRetry(ctx, err) {
+			var permanent *PermanentError
+			if errors.As(err, &permanent) {
+				return zero, permanent.Err
+			}
+
+			return zero, err
+		}
+
+		if attempt >= cfg.maxAttempts {
+			return zero, fmt.Errorf("exceeded retry limit after %d attempts: %w", attempt, err)
+		}
+
+		wait := cfg.wait(attempt)
+		if cfg.onRetry != nil {
+			cfg.onRetry(attempt, err, wait)
+		}
+
+		if wait <= 0 {
+			continue
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, errors.Join(ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,119 @@
+//
+// FilePath    : go-utils\retry_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 通用重试工具测试
+//
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	errTemp := errors.New("temporary")
+	errFatal := errors.New("fatal")
+
+	tests := []struct {
+		name      string
+		fails     []error // 依次返回的错误, 用完后成功
+		opts      []RetryOption
+		wantCalls int
+		wantErr   error
+	}{
+		{"首次成功", nil, nil, 1, nil},
+		{"重试后成功", []error{errTemp, errTemp}, nil, 3, nil},
+		{"超过次数", []error{errTemp, errTemp, errTemp}, nil, 3, errTemp},
+		{"不可重试", []error{Permanent(errFatal), errTemp}, nil, 1, errFatal},
+		{"RetryIf 拒绝", []error{errFatal}, []RetryOption{RetryIf(func(err error) bool { return errors.Is(err, errTemp) })}, 1, errFatal},
+		{"最多 5 次", []error{errTemp, errTemp, errTemp, errTemp}, []RetryOption{WithMaxAttempts(5)}, 5, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			opts := append([]RetryOption{WithConstantBackoff(time.Millisecond)}, tt.opts...)
+
+			err := Retry(context.Background(), func(context.Context) error {
+				calls++
+				if calls <= len(tt.fails) {
+					return tt.fails[calls-1]
+				}
+
+				return nil
+			}, opts...)
+
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+
+			if tt.wantErr == nil && err != nil || !errors.Is(err, tt.wantErr) {
+				t.Errorf("Retry() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var permanent *PermanentError
+			if errors.As(err, &permanent) {
+				t.Errorf("Retry() should unwrap PermanentError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	errTemp := errors.New("temporary")
+	start := time.Now()
+
+	err := Retry(ctx, func(context.Context) error { return errTemp },
+		WithMaxAttempts(10), WithExponentialBackoff(time.Second, 0))
+
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errTemp) {
+		t.Fatalf("Retry() error = %v, want deadline exceeded and last error", err)
+	}
+
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Retry() did not stop waiting on context cancel")
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second, 0)
+
+	for retry, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 5: time.Second, 50: time.Second} {
+		if got := backoff(retry); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", retry, got, want)
+		}
+	}
+
+	triple := ExponentialBackoff(10*time.Millisecond, 0, 3)
+	if got := triple(3); got != 90*time.Millisecond {
+		t.Errorf("backoff(3) with multiplier 3 = %v, want 90ms", got)
+	}
+
+	cfg := &retryConfig{backoff: backoff, jitter: 0.5}
+	for range 100 {
+		if got := cfg.wait(2); got < 100*time.Millisecond || got > 300*time.Millisecond {
+			t.Fatalf("wait(2) with jitter = %v, want in [100ms, 300ms]", got)
+		}
+	}
+
+	var waits []time.Duration
+
+	value, err := RetryValue(context.Background(), func(context.Context) (int, error) {
+		if len(waits) < 2 {
+			return 0, errors.New("retry")
+		}
+
+		return 42, nil
+	}, WithConstantBackoff(0), WithOnRetry(func(_ int, _ error, wait time.Duration) { waits = append(waits, wait) }))
+	if err != nil || value != 42 || len(waits) != 2 {
+		t.Errorf("RetryValue() = %d, %v, waits = %v", value, err, waits)
+	}
+}
@@ -0,0 +1,40 @@
+//
+// FilePath    : go-utils\sort.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 泛型排序工具, 基于 slices 包, 时间复杂度 O(n log n)
+//
+
+package utils
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SortSlice 原地排序, isAsc 为 true 时升序, 否则降序.
+// 可比较类型的相等元素无法区分, 因此不需要稳定排序; 浮点数 NaN 升序时排在最前, 降序时排在最后.
+//   - s: 切片
+//   - isAsc: 是否升序
+func SortSlice[S ~[]E, E cmp.Ordered](s S, isAsc bool) {
+	if isAsc {
+		slices.Sort(s)
+		return
+	}
+
+	slices.SortFunc(s, func(a, b E) int { return cmp.Compare(b, a) })
+}
+
+// SortSliceBy 按 key 原地稳定排序, key 相等的元素保持原有顺序, isAsc 为 true 时升序, 否则降序
+//   - s: 切片
+//   - key: 获取元素的排序 key
+//   - isAsc: 是否升序
+func SortSliceBy[S ~[]E, E any, K cmp.Ordered](s S, key func(item E) K, isAsc bool) {
+	if isAsc {
+		slices.SortStableFunc(s, func(a, b E) int { return cmp.Compare(key(a), key(b)) })
+		return
+	}
+
+	slices.SortStableFunc(s, func(a, b E) int { return cmp.Compare(key(b), key(a)) })
+}
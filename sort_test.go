@@ -0,0 +1,101 @@
+//
+// FilePath    : go-utils\sort_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 泛型排序工具测试, 与冒泡排序的性能对比
+//
+
+package utils
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestSortSlice(t *testing.T) {
+	s := []int{3, 1, 4, 1, 5, 9, 2, 6}
+
+	SortSlice(s, true)
+
+	if want := []int{1, 1, 2, 3, 4, 5, 6, 9}; !slices.Equal(s, want) {
+		t.Errorf("SortSlice(asc) = %v, want %v", s, want)
+	}
+
+	SortSlice(s, false)
+
+	if want := []int{9, 6, 5, 4, 3, 2, 1, 1}; !slices.Equal(s, want) {
+		t.Errorf("SortSlice(desc) = %v, want %v", s, want)
+	}
+
+	type code int
+
+	codes := []code{30, 10, 20}
+	SortSlice(codes, true)
+
+	if !slices.Equal(codes, []code{10, 20, 30}) {
+		t.Errorf("SortSlice(named type) = %v", codes)
+	}
+}
+
+func TestSortSliceBy_Stable(t *testing.T) {
+	type item struct {
+		group int
+		name  string
+	}
+
+	items := []item{{2, "a"}, {1, "b"}, {2, "c"}, {1, "d"}, {2, "e"}}
+
+	SortSliceBy(items, func(i item) int { return i.group }, true)
+
+	want := []item{{1, "b"}, {1, "d"}, {2, "a"}, {2, "c"}, {2, "e"}}
+	if !slices.Equal(items, want) {
+		t.Errorf("SortSliceBy(asc) = %v, want %v", items, want)
+	}
+
+	SortSliceBy(items, func(i item) int { return i.group }, false)
+
+	want = []item{{2, "a"}, {2, "c"}, {2, "e"}, {1, "b"}, {1, "d"}}
+	if !slices.Equal(items, want) {
+		t.Errorf("SortSliceBy(desc) = %v, want %v", items, want)
+	}
+}
+
+// bubbleSort 原 rescode 使用的冒泡排序, 作为对比基准
+func bubbleSort(s []int) {
+	for i := 0; i < len(s)-1; i++ {
+		for j := 0; j < len(s)-i-1; j++ {
+			if s[j] > s[j+1] {
+				s[j], s[j+1] = s[j+1], s[j]
+			}
+		}
+	}
+}
+
+// BenchmarkSort 10k 元素排序: 冒泡排序 O(n²) 与 SortSlice O(n log n)
+func BenchmarkSort(b *testing.B) {
+	src := make([]int, 10000)
+	for i := range src {
+		src[i] = rand.IntN(len(src))
+	}
+
+	cases := []struct {
+		name string
+		sort func(s []int)
+	}{
+		{"bubble", bubbleSort},
+		{"SortSlice", func(s []int) { SortSlice(s, true) }},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			s := make([]int, len(src))
+
+			for b.Loop() {
+				copy(s, src)
+				tc.sort(s)
+			}
+		})
+	}
+}
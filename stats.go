@@ -0,0 +1,253 @@
+//
+// FilePath    : go-utils\stats.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 流式统计, 指数移动平均、滑动窗口分位数、速率统计, 用于自动扩缩容、熔断和慢响应检测
+//
+
+package utils
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// EWMA 指数移动平均, 并发安全, 新值权重为 alpha, 第一个值直接作为平均值
+type EWMA struct {
+	mu          sync.Mutex
+	alpha       float64 // 新值权重
+	value       float64 // 当前平均值
+	initialized bool    // 是否已添加过值
+}
+
+// NewEWMA 创建指数移动平均
+//   - alpha: 新值权重, 取值范围 (0, 1], 越大对新值越敏感; 超出范围时使用 0.1
+func NewEWMA(alpha float64) *EWMA {
+	if alpha <= 0 || alpha > 1 {
+		alpha = 0.1
+	}
+
+	return &EWMA{alpha: alpha}
+}
+
+// NewEWMAWithSpan 根据平滑周期创建指数移动平均, alpha = 2 / (span + 1), 近似于最近 span 个值的平均
+//   - span: 平滑周期, 小于 1 时使用 1
+func NewEWMAWithSpan(span int) *EWMA {
+	return NewEWMA(2 / (float64(max(span, 1)) + 1))
+}
+
+// Add 添加一个值
+func (e *EWMA) Add(v float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.initialized {
+		e.value, e.initialized = v, true
+		return
+	}
+
+	e.value += e.alpha * (v - e.value)
+}
+
+// Value 获取当前平均值, 未添加过值时返回 0
+func (e *EWMA) Value() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.value
+}
+
+// Reset 重置为未添加过值的状态
+func (e *EWMA) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.value, e.initialized = 0, false
+}
+
+// windowSample 滑动窗口样本
+type windowSample struct {
+	value float64
+	at    time.Time
+}
+
+// SlidingWindow 滑动窗口统计, 保留最近 size 个样本, 可选只保留 maxAge 内的样本, 并发安全.
+// 分位数使用排序计算, 适用于窗口较小(数千以内)的场景, 例如最近 1000 次请求的耗时 P99.
+type SlidingWindow struct {
+	mu      sync.Mutex
+	samples []windowSample // 环形缓冲区
+	next    int            // 下一个写入位置
+	count   int            // 已写入样本数, 不超过 len(samples)
+	maxAge  time.Duration  // 样本最大存活时间, 为 0 表示不限制
+	now     func() time.Time
+}
+
+// NewSlidingWindow 创建滑动窗口统计
+//   - size: 窗口大小(样本数), 小于 1 时使用 1
+//   - maxAge: 样本最大存活时间, 为 0 表示只按数量淘汰
+func NewSlidingWindow(size int, maxAge time.Duration) *SlidingWindow {
+	return &SlidingWindow{
+		samples: make([]windowSample, max(size, 1)),
+		maxAge:  maxAge,
+		now:     time.Now,
+	}
+}
+
+// Add 添加一个样本, 窗口已满时覆盖最旧的样本
+func (w *SlidingWindow) Add(v float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.samples[w.next] = windowSample{value: v, at: w.now()}
+	w.next = (w.next + 1) % len(w.samples)
+	w.count = min(w.count+1, len(w.samples))
+}
+
+// values 获取窗口内未过期的样本值, 调用方需要持有锁
+func (w *SlidingWindow) values() []float64 {
+	values := make([]float64, 0, w.count)
+
+	var deadline time.Time
+	if w.maxAge > 0 {
+		deadline = w.now().Add(-w.maxAge)
+	}
+
+	for i := range w.count {
+		s := w.samples[(w.next-w.count+i+len(w.samples))%len(w.samples)]
+		if !deadline.IsZero() && s.at.Before(deadline) {
+			continue
+		}
+
+		values = append(values, s.value)
+	}
+
+	return values
+}
+
+// Count 获取窗口内未过期的样本数
+func (w *SlidingWindow) Count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.values())
+}
+
+// Mean 获取窗口内未过期样本的平均值, 没有样本时返回 0
+func (w *SlidingWindow) Mean() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	values := w.values()
+	if len(values) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
+
+// Percentile 获取窗口内未过期样本的分位数, 使用最近秩法, 没有样本时返回 0
+//   - p: 分位, 取值范围 [0, 100], 例如 99 表示 P99
+func (w *SlidingWindow) Percentile(p float64) float64 {
+	w.mu.Lock()
+	values := w.values()
+	w.mu.Unlock()
+
+	if len(values) == 0 {
+		return 0
+	}
+
+	slices.Sort(values)
+
+	p = min(max(p, 0), 100)
+	rank := int(math.Ceil(p / 100 * float64(len(values))))
+
+	return values[max(rank-1, 0)]
+}
+
+// Reset 清空窗口
+func (w *SlidingWindow) Reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	clear(w.samples)
+	w.next, w.count = 0, 0
+}
+
+// RateMeter 速率统计, 将时间窗口划分为多个桶, 统计窗口内每秒的事件数, 并发安全
+type RateMeter struct {
+	mu         sync.Mutex
+	window     time.Duration // 时间窗口
+	bucketSize time.Duration // 每个桶的时长
+	buckets    []int64       // 环形桶, 记录事件数
+	bucketAt   []int64       // 每个桶对应的时间序号, 用于判断是否过期
+	now        func() time.Time
+}
+
+// NewRateMeter 创建速率统计
+//   - window: 时间窗口, 小于等于 0 时使用 1 分钟
+//   - buckets: 桶数量, 越多越精确, 小于 1 时使用 60
+func NewRateMeter(window time.Duration, buckets int) *RateMeter {
+	if window <= 0 {
+		window = time.Minute
+	}
+
+	if buckets < 1 {
+		buckets = 60
+	}
+
+	return &RateMeter{
+		window:     window,
+		bucketSize: max(window/time.Duration(buckets), 1),
+		buckets:    make([]int64, buckets),
+		bucketAt:   make([]int64, buckets),
+		now:        time.Now,
+	}
+}
+
+// Mark 记录 n 个事件
+func (m *RateMeter) Mark(n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seq := m.now().UnixNano() / int64(m.bucketSize)
+	i := int(seq % int64(len(m.buckets)))
+
+	// 桶已过期时重新计数
+	if m.bucketAt[i] != seq {
+		m.buckets[i], m.bucketAt[i] = 0, seq
+	}
+
+	m.buckets[i] += n
+}
+
+// Count 获取时间窗口内的事件数
+func (m *RateMeter) Count() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seq := m.now().UnixNano() / int64(m.bucketSize)
+	oldest := seq - int64(len(m.buckets)) + 1
+
+	var total int64
+
+	for i, at := range m.bucketAt {
+		if at >= oldest && at <= seq {
+			total += m.buckets[i]
+		}
+	}
+
+	return total
+}
+
+// Rate 获取时间窗口内平均每秒的事件数
+func (m *RateMeter) Rate() float64 {
+	return float64(m.Count()) / m.window.Seconds()
+}
@@ -0,0 +1,119 @@
+//
+// FilePath    : go-utils\stats_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 流式统计测试
+//
+
+package utils
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEWMA(t *testing.T) {
+	e := NewEWMA(0.5)
+	if e.Value() != 0 {
+		t.Fatalf("Value() = %v, want 0", e.Value())
+	}
+
+	e.Add(10)
+	e.Add(20)
+	e.Add(20)
+
+	if got := e.Value(); got != 17.5 {
+		t.Errorf("Value() = %v, want 17.5", got)
+	}
+
+	e.Reset()
+	e.Add(3)
+
+	if got := e.Value(); got != 3 {
+		t.Errorf("Value() after Reset = %v, want 3", got)
+	}
+
+	if got := NewEWMAWithSpan(9).alpha; math.Abs(got-0.2) > 1e-9 {
+		t.Errorf("NewEWMAWithSpan(9).alpha = %v, want 0.2", got)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	w := NewSlidingWindow(5, time.Minute)
+	w.now = func() time.Time { return now }
+
+	for i := 1; i <= 7; i++ {
+		w.Add(float64(i))
+	}
+
+	// 只保留最近 5 个样本 3-7
+	if got := w.Count(); got != 5 {
+		t.Errorf("Count() = %d, want 5", got)
+	}
+
+	if got := w.Mean(); got != 5 {
+		t.Errorf("Mean() = %v, want 5", got)
+	}
+
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0, 3}, {50, 5}, {80, 6}, {99, 7}, {100, 7},
+	}
+	for _, tt := range tests {
+		if got := w.Percentile(tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+
+	// 过期样本不参与统计
+	now = now.Add(30 * time.Second)
+	w.Add(100)
+
+	now = now.Add(45 * time.Second)
+	if got := w.Count(); got != 1 || w.Percentile(50) != 100 {
+		t.Errorf("Count() = %d, Percentile(50) = %v, want 1 and 100", got, w.Percentile(50))
+	}
+
+	w.Reset()
+
+	if got := w.Percentile(99); got != 0 {
+		t.Errorf("Percentile() after Reset = %v, want 0", got)
+	}
+}
+
+func TestRateMeter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := NewRateMeter(10*time.Second, 10)
+	m.now = func() time.Time { return now }
+
+	for range 5 {
+		m.Mark(2)
+		now = now.Add(time.Second)
+	}
+
+	if got := m.Count(); got != 10 {
+		t.Errorf("Count() = %d, want 10", got)
+	}
+
+	if got := m.Rate(); got != 1 {
+		t.Errorf("Rate() = %v, want 1", got)
+	}
+
+	// 窗口滑过后只统计最近 10 秒
+	now = now.Add(7 * time.Second)
+	if got := m.Count(); got != 4 {
+		t.Errorf("Count() after slide = %d, want 4", got)
+	}
+
+	now = now.Add(time.Minute)
+	m.Mark(1)
+
+	if got := m.Count(); got != 1 {
+		t.Errorf("Count() after expire = %d, want 1", got)
+	}
+}
@@ -0,0 +1,220 @@
+//
+// FilePath    : go-utils\string.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 字符串工具, 按 rune 或显示宽度反转、截断和截取字符串, 不会截断多字节字符
+//
+
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// DefaultEllipsis 省略截断默认使用的省略号
+const DefaultEllipsis = "..."
+
+// ReverseString 将字符串按 rune 逆序排列并返回, 多字节字符保持完整
+func ReverseString(str string) string {
+	runes := []rune(str)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes)
+}
+
+// RuneLen 获取字符串的字符(rune)数量
+func RuneLen(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// TruncateRunes 截取字符串的前 n 个字符(rune), 不足 n 个时返回原字符串, n 不大于 0 时返回空字符串
+//   - s: 字符串
+//   - n: 保留的字符数
+func TruncateRunes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	count := 0
+	for i := range s {
+		if count == n {
+			return s[:i]
+		}
+
+		count++
+	}
+
+	return s
+}
+
+// TruncateRunesWithEllipsis 字符串超过 n 个字符时截断并追加省略号, 结果(含省略号)不超过 n 个字符.
+// n 不足以容纳省略号时只截断不追加.
+//   - s: 字符串
+//   - n: 最大字符数
+//   - ellipsis: 省略号, 为空时使用 DefaultEllipsis
+func TruncateRunesWithEllipsis(s string, n int, ellipsis string) string {
+	if RuneLen(s) <= n {
+		return s
+	}
+
+	if ellipsis == "" {
+		ellipsis = DefaultEllipsis
+	}
+
+	keep := n - RuneLen(ellipsis)
+	if keep <= 0 {
+		return TruncateRunes(s, n)
+	}
+
+	return TruncateRunes(s, keep) + ellipsis
+}
+
+// RuneWidth 获取字符在等宽字体中的显示宽度: 中日韩等宽字符和全角字符为 2, 组合字符和控制字符为 0, 其他为 1
+func RuneWidth(r rune) int {
+	// 组合字符、零宽字符和控制字符不占宽度
+	if r == 0 || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || unicode.IsControl(r) {
+		return 0
+	}
+
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// StringWidth 获取字符串在等宽字体中的显示宽度, 例如 "Go语言" 的宽度为 6
+func StringWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		w += RuneWidth(r)
+	}
+
+	return w
+}
+
+// TruncateWidth 按显示宽度截断字符串, 结果的宽度不超过 maxWidth, 不会截断宽字符和其后的组合字符
+//   - s: 字符串
+//   - maxWidth: 最大显示宽度
+func TruncateWidth(s string, maxWidth int) string {
+	if maxWidth <= 0 {
+		return ""
+	}
+
+	w := 0
+	for i, r := range s {
+		rw := RuneWidth(r)
+		if w+rw > maxWidth {
+			return s[:i]
+		}
+
+		w += rw
+	}
+
+	return s
+}
+
+// TruncateWidthWithEllipsis 字符串的显示宽度超过 maxWidth 时截断并追加省略号, 结果(含省略号)的宽度不超过 maxWidth.
+// 适合表格、通知标题等按显示宽度排版的场景, maxWidth 不足以容纳省略号时只截断不追加.
+//   - s: 字符串
+//   - maxWidth: 最大显示宽度
+//   - ellipsis: 省略号, 为空时使用 DefaultEllipsis
+func TruncateWidthWithEllipsis(s string, maxWidth int, ellipsis string) string {
+	if StringWidth(s) <= maxWidth {
+		return s
+	}
+
+	if ellipsis == "" {
+		ellipsis = DefaultEllipsis
+	}
+
+	keep := maxWidth - StringWidth(ellipsis)
+	if keep <= 0 {
+		return TruncateWidth(s, maxWidth)
+	}
+
+	return TruncateWidth(s, keep) + ellipsis
+}
+
+// SubstringRunes 按字符(rune)截取子串, 越界时自动收缩到有效范围, 不会 panic.
+// start 为负数时从末尾倒数, 例如 SubstringRunes("你好世界", -2, 2) 返回 "世界".
+//   - s: 字符串
+//   - start: 起始字符位置, 从 0 开始
+//   - length: 截取的字符数, 小于 0 时截取到末尾
+func SubstringRunes(s string, start, length int) string {
+	runes := []rune(s)
+	n := len(runes)
+
+	if start < 0 {
+		start = max(n+start, 0)
+	}
+
+	if start >= n || length == 0 {
+		return ""
+	}
+
+	end := n
+	if length > 0 && start+length < n {
+		end = start + length
+	}
+
+	return string(runes[start:end])
+}
+
+// SafePrefix 截取字符串的前 n 个字节, 若第 n 个字节位于多字节字符中间, 则退回到该字符之前.
+// 用于数据库字段、请求头等按字节限制长度的场景.
+//   - s: 字符串
+//   - n: 最大字节数
+func SafePrefix(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
+
+// SafeSuffix 截取字符串的后 n 个字节, 若起始位置位于多字节字符中间, 则前进到下一个字符.
+//   - s: 字符串
+//   - n: 最大字节数
+func SafeSuffix(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	if len(s) <= n {
+		return s
+	}
+
+	start := len(s) - n
+	for start < len(s) && !utf8.RuneStart(s[start]) {
+		start++
+	}
+
+	return s[start:]
+}
+
+// RuneIndex 获取 substr 在 s 中第一次出现的字符(rune)位置, 不存在时返回 -1
+func RuneIndex(s, substr string) int {
+	i := strings.Index(s, substr)
+	if i < 0 {
+		return -1
+	}
+
+	return utf8.RuneCountInString(s[:i])
+}
@@ -0,0 +1,135 @@
+//
+// FilePath    : go-utils\string_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 字符串工具测试
+//
+
+package utils
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestReverseString(t *testing.T) {
+	cases := map[string]string{
+		"":      "",
+		"abc":   "cba",
+		"你好世界":  "界世好你",
+		"Go语言🚀": "🚀言语oG",
+	}
+
+	for in, want := range cases {
+		if got := ReverseString(in); got != want {
+			t.Errorf("ReverseString(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	if got := TruncateRunes("你好世界", 2); got != "你好" {
+		t.Errorf("TruncateRunes got %q", got)
+	}
+
+	if got := TruncateRunes("abc", 5); got != "abc" {
+		t.Errorf("TruncateRunes got %q", got)
+	}
+
+	if got := TruncateRunesWithEllipsis("你好世界你好", 5, ""); got != "你好..." {
+		t.Errorf("TruncateRunesWithEllipsis got %q", got)
+	}
+
+	if got := TruncateRunesWithEllipsis("你好世界", 4, "…"); got != "你好世界" {
+		t.Errorf("TruncateRunesWithEllipsis got %q", got)
+	}
+
+	if got := TruncateRunesWithEllipsis("abcdef", 2, ""); got != "ab" {
+		t.Errorf("TruncateRunesWithEllipsis got %q", got)
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	if w := StringWidth("Go语言"); w != 6 {
+		t.Errorf("StringWidth got %d", w)
+	}
+
+	// 宽字符不会被拆开
+	if got := TruncateWidth("Go语言", 3); got != "Go" {
+		t.Errorf("TruncateWidth got %q", got)
+	}
+
+	// 组合字符跟随前一个字符
+	if got := TruncateWidth("e\u0301x", 1); got != "e\u0301" {
+		t.Errorf("TruncateWidth got %q", got)
+	}
+
+	if got := TruncateWidthWithEllipsis("中文标题很长", 8, "…"); got != "中文标…" || StringWidth(got) > 8 {
+		t.Errorf("TruncateWidthWithEllipsis got %q", got)
+	}
+}
+
+func TestSubstringRunes(t *testing.T) {
+	cases := []struct {
+		start, length int
+		want          string
+	}{
+		{0, 2, "你好"},
+		{2, -1, "世界"},
+		{-2, 2, "世界"},
+		{-10, 1, "你"},
+		{3, 10, "界"},
+		{4, 1, ""},
+		{1, 0, ""},
+	}
+
+	for _, c := range cases {
+		if got := SubstringRunes("你好世界", c.start, c.length); got != c.want {
+			t.Errorf("SubstringRunes(%d, %d) = %q, want %q", c.start, c.length, got, c.want)
+		}
+	}
+}
+
+func TestSafePrefixSuffix(t *testing.T) {
+	s := "ab你好"
+
+	for n := range len(s) + 2 {
+		if p := SafePrefix(s, n); !utf8.ValidString(p) || len(p) > n {
+			t.Errorf("SafePrefix(%d) = %q", n, p)
+		}
+
+		if p := SafeSuffix(s, n); !utf8.ValidString(p) || len(p) > n {
+			t.Errorf("SafeSuffix(%d) = %q", n, p)
+		}
+	}
+
+	if got := SafePrefix(s, 4); got != "ab" {
+		t.Errorf("SafePrefix got %q", got)
+	}
+
+	if got := RuneIndex(s, "好"); got != 3 {
+		t.Errorf("RuneIndex got %d", got)
+	}
+}
+
+func TestPlayKeyRoundTrip(t *testing.T) {
+	key, iv, err := GenerateAESKeyAndIV()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := PlayKeyEncryptAES2Base64("play-key", key, iv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := PlayKeyDecryptAES2String(encrypted); err != nil || got != "play-key" {
+		t.Errorf("PlayKeyDecryptAES2String = %q, %v", got, err)
+	}
+
+	// 长度不足时返回错误而不是 panic
+	if _, err = PlayKeyDecryptAES2String("短"); err == nil {
+		t.Error("expected error for short input")
+	}
+}
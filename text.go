@@ -0,0 +1,75 @@
+//
+// FilePath    : go-utils\text.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 文本规范化工具, Unicode 规范化、全角半角转换、空白折叠
+//
+
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// NormalizeNFC 将 s 转换为 Unicode NFC 规范形式(标准等价合成), 适合存储和比较
+func NormalizeNFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// NormalizeNFKC 将 s 转换为 Unicode NFKC 规范形式(兼容等价合成), 会把全角字母数字、上标等转换为普通字符
+func NormalizeNFKC(s string) string {
+	return norm.NFKC.String(s)
+}
+
+// ToHalfWidth 全角转半角, 例如 "ＡＢＣ１２３，" 转换为 "ABC123,"
+func ToHalfWidth(s string) string {
+	return width.Narrow.String(s)
+}
+
+// ToFullWidth 半角转全角, 例如 "ABC123," 转换为 "ＡＢＣ１２３，"
+func ToFullWidth(s string) string {
+	return width.Widen.String(s)
+}
+
+// CollapseWhitespace 去除首尾空白, 并将连续的空白字符(含全角空格、换行、制表符)折叠为一个半角空格
+func CollapseWhitespace(s string) string {
+	var b strings.Builder
+
+	b.Grow(len(s))
+
+	// 是否处于空白中
+	inSpace := false
+
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			inSpace = true
+			continue
+		}
+
+		// 非首个字符之前的空白折叠为一个空格
+		if inSpace && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+
+		inSpace = false
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// NormalizeSearchKey 生成搜索键: NFKC 规范化、全角转半角、转小写并折叠空白,
+// 使 "Ｇｏ　语言" 和 "go 语言" 得到相同的结果
+func NormalizeSearchKey(s string) string {
+	s = NormalizeNFKC(s)
+	s = ToHalfWidth(s)
+	s = strings.ToLower(s)
+
+	return CollapseWhitespace(s)
+}
@@ -0,0 +1,88 @@
+//
+// FilePath    : go-utils\text_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 文本规范化和拼音测试
+//
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	// e + 组合重音符 => é
+	if got := NormalizeNFC("e\u0301"); got != "\u00e9" {
+		t.Errorf("NormalizeNFC got %q", got)
+	}
+
+	if got := NormalizeNFKC("ＡＢＣ①"); got != "ABC1" {
+		t.Errorf("NormalizeNFKC got %q", got)
+	}
+}
+
+func TestWidthConversion(t *testing.T) {
+	if got := ToHalfWidth("ＡＢＣ１２３，"); got != "ABC123," {
+		t.Errorf("ToHalfWidth got %q", got)
+	}
+
+	if got := ToFullWidth("ABC123,"); got != "ＡＢＣ１２３，" {
+		t.Errorf("ToFullWidth got %q", got)
+	}
+}
+
+func TestCollapseWhitespace(t *testing.T) {
+	cases := map[string]string{
+		"  a   b  ": "a b",
+		"a\t\n b":   "a b",
+		"中　　文":      "中 文",
+		"":          "",
+		"   ":       "",
+	}
+
+	for in, want := range cases {
+		if got := CollapseWhitespace(in); got != want {
+			t.Errorf("CollapseWhitespace(%q) = %q; want %q", in, got, want)
+		}
+	}
+}
+
+func TestNormalizeSearchKey(t *testing.T) {
+	if a, b := NormalizeSearchKey("Ｇｏ　语言"), NormalizeSearchKey(" go  语言 "); a != b {
+		t.Errorf("NormalizeSearchKey mismatch: %q vs %q", a, b)
+	}
+}
+
+func TestToPinyin(t *testing.T) {
+	got := ToPinyin("Go 语言2026")
+	want := []string{"go", "yu", "yan", "2026"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToPinyin got %v; want %v", got, want)
+	}
+}
+
+func TestPinyinSlug(t *testing.T) {
+	cases := map[string]string{
+		"Go 语言：入门":    "go-yu-yan-ru-men",
+		"中国-北京":       "zhong-guo-bei-jing",
+		"Hello World": "hello-world",
+		"":            "",
+	}
+
+	for in, want := range cases {
+		if got := PinyinSlug(in); got != want {
+			t.Errorf("PinyinSlug(%q) = %q; want %q", in, got, want)
+		}
+	}
+
+	// 注册覆盖读音
+	RegisterPinyin(map[rune]string{'鑫': "xin"})
+
+	if got := PinyinSlug("鑫"); got != "xin" {
+		t.Errorf("PinyinSlug after RegisterPinyin got %q", got)
+	}
+}
@@ -0,0 +1,32 @@
+//
+// FilePath    : go-utils\trace.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 上下文中的链路追踪ID, 在定时任务、消息队列和支付调用之间传递, 便于跨系统排查同一次执行
+//
+
+package utils
+
+import "context"
+
+// traceIDKey 链路追踪ID的上下文键
+type traceIDKey struct{}
+
+// ContextWithTraceID 返回携带链路追踪ID的上下文
+//   - ctx: 父上下文
+//   - traceID: 链路追踪ID, 例如请求ID或定时任务的执行ID
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext 获取上下文中的链路追踪ID, 不存在时返回空字符串
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+
+	return traceID
+}
@@ -8,9 +8,11 @@
 
 package utils
 
-import "reflect"
+import "github.com/jiaopengzi/go-utils/reflectutil"
 
-// IsPointer 检查传入的 v 是否是指针
+// IsPointer 检查传入的 v 是否是指针, v 为 nil 时返回 false
+//
+// Deprecated: 请使用 reflectutil.IsPointer
 func IsPointer(v any) bool {
-	return reflect.TypeOf(v).Kind() == reflect.Pointer
+	return reflectutil.IsPointer(v)
 }
@@ -0,0 +1,396 @@
+//
+// FilePath    : go-utils\upload.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 文件上传工具, 流式保存、大小限制、边写边计算哈希、MIME 嗅探、分片上传合并
+//
+
+package utils
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UploadedFile 已保存的上传文件信息
+type UploadedFile struct {
+	Path     string // 保存路径
+	FileName string // 文件名
+	Size     int64  // 文件大小(字节)
+	Hash     string // 文件哈希值(十六进制), 与 GenerateHashByFileContent 结果一致
+	MIME     string // 嗅探得到的 MIME 类型, 不含参数
+}
+
+// UploadOption 上传选项
+type UploadOption struct {
+	MaxSize     int64         // 最大文件大小(字节), 为 0 表示不限制
+	AllowedMIME []string      // 允许的 MIME 类型, 支持 "image/*" 通配, 为空表示不限制
+	Algorithm   HashAlgorithm // 哈希算法, 默认 SHA256
+	Perm        os.FileMode   // 文件权限, 默认 0644
+}
+
+// UploadOptionFunc 上传选项函数
+type UploadOptionFunc func(*UploadOption)
+
+// WithUploadMaxSize 设置最大文件大小(字节)
+func WithUploadMaxSize(size int64) UploadOptionFunc {
+	return func(o *UploadOption) {
+		o.MaxSize = size
+	}
+}
+
+// WithUploadAllowedMIME 设置允许的 MIME 类型, 支持 "image/*" 通配
+func WithUploadAllowedMIME(types ...string) UploadOptionFunc {
+	return func(o *UploadOption) {
+		o.AllowedMIME = types
+	}
+}
+
+// WithUploadAlgorithm 设置哈希算法
+func WithUploadAlgorithm(alg HashAlgorithm) UploadOptionFunc {
+	return func(o *UploadOption) {
+		o.Algorithm = alg
+	}
+}
+
+// WithUploadPerm 设置文件权限
+func WithUploadPerm(perm os.FileMode) UploadOptionFunc {
+	return func(o *UploadOption) {
+		o.Perm = perm
+	}
+}
+
+// newUploadOption 生成上传选项
+func newUploadOption(opts ...UploadOptionFunc) *UploadOption {
+	opt := &UploadOption{
+		Algorithm: SHA256,
+		Perm:      0o644,
+	}
+
+	for _, fn := range opts {
+		fn(opt)
+	}
+
+	return opt
+}
+
+// SaveUpload 将 r 的内容流式保存到 dir/fileName, 同时校验大小、嗅探 MIME 并计算哈希.
+// 先写入临时文件, 全部校验通过后再重命名, 失败时不会留下不完整的文件.
+//   - r: 文件内容
+//   - dir: 保存目录, 不存在时自动创建
+//   - fileName: 文件名, 只保留最后一级, 避免路径穿越
+//   - opts: 上传选项
+func SaveUpload(r io.Reader, dir, fileName string, opts ...UploadOptionFunc) (*UploadedFile, error) {
+	opt := newUploadOption(opts...)
+
+	fileName = filepath.Base(filepath.Clean("/" + fileName))
+	if fileName == "/" || fileName == "." {
+		return nil, fmt.Errorf("文件名不合法")
+	}
+
+	// 嗅探 MIME 类型
+	br := bufio.NewReaderSize(r, contentSniffLen)
+
+	head, err := br.Peek(contentSniffLen)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+
+	mimeType := DetectMIME(head)
+	if !MIMEAllowed(mimeType, opt.AllowedMIME) {
+		return nil, fmt.Errorf("%w: %s", ErrUploadMIMENotAllowed, mimeType)
+	}
+
+	if err = CreateDir(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return nil, err
+	}
+
+	// 失败时清理临时文件
+	saved := false
+
+	defer func() {
+		if !saved {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	// 多读一个字节用于判断是否超出大小限制
+	var src io.Reader = br
+	if opt.MaxSize > 0 {
+		src = io.LimitReader(br, opt.MaxSize+1)
+	}
+
+	hasher := GenerateHasher(WithAlgorithm(opt.Algorithm))
+
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), src)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.MaxSize > 0 && size > opt.MaxSize {
+		return nil, fmt.Errorf("%w: 超过 %d 字节", ErrUploadTooLarge, opt.MaxSize)
+	}
+
+	if err = tmp.Chmod(opt.Perm); err != nil {
+		return nil, err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	dst := filepath.Join(dir, fileName)
+	if err = os.Rename(tmp.Name(), dst); err != nil {
+		return nil, err
+	}
+
+	saved = true
+
+	return &UploadedFile{
+		Path:     dst,
+		FileName: fileName,
+		Size:     size,
+		Hash:     hex.EncodeToString(hasher.Sum(nil)),
+		MIME:     mimeType,
+	}, nil
+}
+
+// ReceiveMultipartUpload 从 multipart 请求中流式读取 field 字段的文件并保存到 dir,
+// 不会把整个请求体读入内存或临时文件, 适合大文件上传.
+//   - r: http 请求
+//   - field: 文件字段名
+//   - dir: 保存目录
+//   - opts: 上传选项
+func ReceiveMultipartUpload(r *http.Request, field, dir string, opts ...UploadOptionFunc) (*UploadedFile, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrUploadFieldNotFound
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// 跳过其他字段
+		if part.FormName() != field || part.FileName() == "" {
+			_ = part.Close()
+			continue
+		}
+
+		file, err := SaveUpload(part, dir, part.FileName(), opts...)
+		_ = part.Close()
+
+		return file, err
+	}
+}
+
+// DetectMIME 根据文件头嗅探 MIME 类型, 返回不含参数的类型, 例如 "image/png".
+// 先识别 heic、avif、office 文档等扩展格式, 其余交给 http.DetectContentType.
+func DetectMIME(head []byte) string {
+	if mimeType := detectExtended(head); mimeType != "" {
+		return mimeType
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return MIMEOctetStream
+	}
+
+	return mediaType
+}
+
+// MIMEAllowed 判断 mimeType 是否在 allowed 中, 支持 "image/*" 通配, allowed 为空时允许所有类型
+func MIMEAllowed(mimeType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, a := range allowed {
+		if prefix, ok := strings.CutSuffix(a, "/*"); ok {
+			if strings.HasPrefix(mimeType, prefix+"/") {
+				return true
+			}
+
+			continue
+		}
+
+		if strings.EqualFold(a, mimeType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// uploadIDRegex 上传ID只允许字母、数字、下划线和中划线, 避免路径穿越
+var uploadIDRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+// chunkSuffix 分片文件后缀
+const chunkSuffix = ".part"
+
+// ChunkedUpload 分片上传, 每个上传ID的分片保存在 Dir/<uploadID>/<index>.part, 支持断点续传
+type ChunkedUpload struct {
+	Dir string // 分片保存的根目录
+}
+
+// NewChunkedUpload 创建分片上传
+//   - dir: 分片保存的根目录
+func NewChunkedUpload(dir string) *ChunkedUpload {
+	return &ChunkedUpload{Dir: dir}
+}
+
+// chunkDir 获取上传ID对应的分片目录
+func (u *ChunkedUpload) chunkDir(uploadID string) (string, error) {
+	if !uploadIDRegex.MatchString(uploadID) {
+		return "", ErrUploadInvalidID
+	}
+
+	return filepath.Join(u.Dir, uploadID), nil
+}
+
+// SaveChunk 保存第 index 个分片, 重复上传同一分片会覆盖
+//   - uploadID: 上传ID
+//   - index: 分片序号, 从 0 开始
+//   - r: 分片内容
+//   - maxChunkSize: 单个分片最大大小(字节), 为 0 表示不限制
+func (u *ChunkedUpload) SaveChunk(uploadID string, index int, r io.Reader, maxChunkSize int64) error {
+	if index < 0 {
+		return fmt.Errorf("分片序号不能为负数: %d", index)
+	}
+
+	dir, err := u.chunkDir(uploadID)
+	if err != nil {
+		return err
+	}
+
+	_, err = SaveUpload(r, dir, strconv.Itoa(index)+chunkSuffix, WithUploadMaxSize(maxChunkSize))
+
+	return err
+}
+
+// UploadedChunks 获取已上传的分片序号(升序), 用于断点续传
+func (u *ChunkedUpload) UploadedChunks(uploadID string) ([]int, error) {
+	dir, err := u.chunkDir(uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []int{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]int, 0, len(entries))
+
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), chunkSuffix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		if index, err := strconv.Atoi(name); err == nil {
+			indexes = append(indexes, index)
+		}
+	}
+
+	sort.Ints(indexes)
+
+	return indexes, nil
+}
+
+// Assemble 按序号合并 total 个分片并保存到 dir/fileName, 合并成功后删除分片目录.
+// 合并时同样会校验大小、嗅探 MIME 并计算哈希.
+//   - uploadID: 上传ID
+//   - total: 分片总数
+//   - dir: 保存目录
+//   - fileName: 文件名
+//   - opts: 上传选项
+func (u *ChunkedUpload) Assemble(uploadID string, total int, dir, fileName string, opts ...UploadOptionFunc) (*UploadedFile, error) {
+	chunkDir, err := u.chunkDir(uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	uploaded, err := u.UploadedChunks(uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查分片是否完整
+	for i := range total {
+		if _, found := slices.BinarySearch(uploaded, i); !found {
+			return nil, fmt.Errorf("%w: %d", ErrUploadChunkMissing, i)
+		}
+	}
+
+	files := make([]*os.File, 0, total)
+
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+
+	readers := make([]io.Reader, 0, total)
+
+	for i := range total {
+		f, err := os.Open(filepath.Join(chunkDir, strconv.Itoa(i)+chunkSuffix))
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, f)
+		readers = append(readers, f)
+	}
+
+	file, err := SaveUpload(io.MultiReader(readers...), dir, fileName, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 合并成功后删除分片
+	if err = u.Abort(uploadID); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Abort 取消上传, 删除上传ID对应的所有分片
+func (u *ChunkedUpload) Abort(uploadID string) error {
+	dir, err := u.chunkDir(uploadID)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,201 @@
+//
+// FilePath    : go-utils\upload_test.go
+// Author      : jiaopengzi
+// Blog        : https://jiaopengzi.com
+// Copyright   : Copyright (c) 2026 by jiaopengzi, All Rights Reserved.
+// Description : 单元测试 - 文件上传工具
+//
+
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveUpload(t *testing.T) {
+	png := append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), bytes.Repeat([]byte{0}, 32)...)
+
+	tests := []struct {
+		name     string
+		content  []byte
+		fileName string
+		opts     []UploadOptionFunc
+		wantMIME string
+		wantErr  error
+	}{
+		{
+			name:     "plain text",
+			content:  []byte("hello world"),
+			fileName: "a.txt",
+			wantMIME: "text/plain",
+		},
+		{
+			name:     "path traversal file name",
+			content:  []byte("hello world"),
+			fileName: "../../b.txt",
+			wantMIME: "text/plain",
+		},
+		{
+			name:     "image wildcard allowed",
+			content:  png,
+			fileName: "c.png",
+			opts:     []UploadOptionFunc{WithUploadAllowedMIME("image/*")},
+			wantMIME: "image/png",
+		},
+		{
+			name:     "mime not allowed",
+			content:  []byte("hello world"),
+			fileName: "d.txt",
+			opts:     []UploadOptionFunc{WithUploadAllowedMIME("image/png")},
+			wantErr:  ErrUploadMIMENotAllowed,
+		},
+		{
+			name:     "exactly max size",
+			content:  []byte("12345"),
+			fileName: "e.txt",
+			opts:     []UploadOptionFunc{WithUploadMaxSize(5)},
+			wantMIME: "text/plain",
+		},
+		{
+			name:     "too large",
+			content:  []byte("123456"),
+			fileName: "f.txt",
+			opts:     []UploadOptionFunc{WithUploadMaxSize(5)},
+			wantErr:  ErrUploadTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			file, err := SaveUpload(bytes.NewReader(tt.content), dir, tt.fileName, tt.opts...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("SaveUpload() error = %v, want %v", err, tt.wantErr)
+				}
+
+				// 失败时不应留下任何文件
+				entries, _ := os.ReadDir(dir)
+				if len(entries) != 0 {
+					t.Errorf("SaveUpload() left %d files", len(entries))
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("SaveUpload() error = %v", err)
+			}
+
+			if filepath.Dir(file.Path) != dir {
+				t.Errorf("SaveUpload() path = %s, want in %s", file.Path, dir)
+			}
+
+			if file.MIME != tt.wantMIME {
+				t.Errorf("SaveUpload() MIME = %s, want %s", file.MIME, tt.wantMIME)
+			}
+
+			if file.Size != int64(len(tt.content)) {
+				t.Errorf("SaveUpload() size = %d, want %d", file.Size, len(tt.content))
+			}
+
+			wantHash, _ := GenerateHashByFileContent(bytes.NewReader(tt.content))
+			if file.Hash != wantHash {
+				t.Errorf("SaveUpload() hash = %s, want %s", file.Hash, wantHash)
+			}
+
+			got, _ := os.ReadFile(file.Path)
+			if !bytes.Equal(got, tt.content) {
+				t.Errorf("SaveUpload() content = %q, want %q", got, tt.content)
+			}
+		})
+	}
+}
+
+func TestReceiveMultipartUpload(t *testing.T) {
+	var body bytes.Buffer
+
+	w := multipart.NewWriter(&body)
+	_ = w.WriteField("name", "test")
+	fw, _ := w.CreateFormFile("file", "hello.txt")
+	_, _ = fw.Write([]byte("hello multipart"))
+	_ = w.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	dir := t.TempDir()
+
+	file, err := ReceiveMultipartUpload(req, "file", dir)
+	if err != nil {
+		t.Fatalf("ReceiveMultipartUpload() error = %v", err)
+	}
+
+	if file.FileName != "hello.txt" || file.Size != int64(len("hello multipart")) {
+		t.Errorf("ReceiveMultipartUpload() = %+v", file)
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, "/upload", strings.NewReader("--x--\r\n"))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+
+	if _, err = ReceiveMultipartUpload(req, "file", dir); !errors.Is(err, ErrUploadFieldNotFound) {
+		t.Errorf("ReceiveMultipartUpload() error = %v, want %v", err, ErrUploadFieldNotFound)
+	}
+}
+
+func TestChunkedUpload(t *testing.T) {
+	u := NewChunkedUpload(t.TempDir())
+	dst := t.TempDir()
+	chunks := []string{"hello ", "chunked ", "upload"}
+
+	if err := u.SaveChunk("../evil", 0, strings.NewReader("x"), 0); !errors.Is(err, ErrUploadInvalidID) {
+		t.Fatalf("SaveChunk() error = %v, want %v", err, ErrUploadInvalidID)
+	}
+
+	// 乱序上传, 并缺少最后一个分片
+	for _, i := range []int{1, 0} {
+		if err := u.SaveChunk("abc", i, strings.NewReader(chunks[i]), 16); err != nil {
+			t.Fatalf("SaveChunk() error = %v", err)
+		}
+	}
+
+	if err := u.SaveChunk("abc", 2, strings.NewReader(strings.Repeat("x", 17)), 16); !errors.Is(err, ErrUploadTooLarge) {
+		t.Fatalf("SaveChunk() error = %v, want %v", err, ErrUploadTooLarge)
+	}
+
+	got, err := u.UploadedChunks("abc")
+	if err != nil || len(got) != 2 || got[0] != 0 || got[1] != 1 {
+		t.Fatalf("UploadedChunks() = %v, %v", got, err)
+	}
+
+	if _, err = u.Assemble("abc", len(chunks), dst, "out.txt"); !errors.Is(err, ErrUploadChunkMissing) {
+		t.Fatalf("Assemble() error = %v, want %v", err, ErrUploadChunkMissing)
+	}
+
+	if err = u.SaveChunk("abc", 2, strings.NewReader(chunks[2]), 16); err != nil {
+		t.Fatalf("SaveChunk() error = %v", err)
+	}
+
+	file, err := u.Assemble("abc", len(chunks), dst, "out.txt")
+	if err != nil {
+		t.Fatalf("Assemble() error = %v", err)
+	}
+
+	content, _ := os.ReadFile(file.Path)
+	if string(content) != strings.Join(chunks, "") {
+		t.Errorf("Assemble() content = %q", content)
+	}
+
+	// 合并后分片被删除
+	if got, _ = u.UploadedChunks("abc"); len(got) != 0 {
+		t.Errorf("UploadedChunks() after assemble = %v", got)
+	}
+}